@@ -0,0 +1,144 @@
+//////////////////////////////////////////////////
+// Acknowledgment Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for answering "thanks!" without asking Wit.ai or Wolfram|Alpha
+import (
+	"context"   // Permits scoping of the reply's wording
+	"log"       // Permits console logging
+	"math/rand" // Permits varying of the reply
+	"regexp"    // Permits splitting of acknowledgments into phrases
+	"strings"   // Permits string normalization
+
+	wit "github.com/christianrondeau/go-wit" // External Wit.ai API
+	slack "github.com/nlopes/slack"          // External Slack API
+)
+
+// Global phrases that thank the bot, and those that merely acknowledge an answer; a message counts as thanks when it is
+// made only of these and at least one thanks
+var (
+	gratitudePhrases = map[string]bool{
+		"thanks": true, "thank you": true, "thank u": true, "thanks a lot": true, "thanks so much": true, "thank you so much": true,
+		"thank you very much": true, "thanks again": true, "many thanks": true, "thx": true, "ty": true, "tysm": true,
+		"cheers": true, "much appreciated": true, "appreciate it": true, "i appreciate it": true, "perfect": true,
+		"thats what i needed": true, "thats perfect": true, "that helps": true, "that helped": true, "very helpful": true,
+	}
+	acknowledgmentPhrases = map[string]bool{
+		"ok": true, "okay": true, "great": true, "cool": true, "nice": true, "awesome": true, "got it": true,
+		"thats great": true,
+	}
+)
+
+// Global patterns splitting a message into its phrases and dropping emoji shortcodes and apostrophes
+var (
+	acknowledgmentSplitPattern = regexp.MustCompile(`[\s]*[,.!;]+[\s]*|\s+-\s+`)
+	emojiShortcodePattern      = regexp.MustCompile(`:[a-z0-9_+\-]+:`)
+	apostrophePattern          = regexp.MustCompile(`['’]`)
+)
+
+// Registering the acknowledgment handler among the built-ins, for Wit.ai apps trained with an "acknowledgment" intent
+// (it is local, so apps without one aren't warned about it)
+func init() {
+	registerEntityHandler(&entityHandler{
+		name:        "acknowledgment",
+		description: "Say you're welcome when thanked.",
+		local:       true,
+		repeatable:  true,
+		handle:      handleAcknowledgmentIntent,
+	})
+}
+
+// Global function for checking whether a message only thanks or acknowledges the bot ("thanks!", "perfect, that's what
+// I needed"), and not, say, "thanks Alice" or "thanks, what's the weather in Paris?"
+func isAcknowledgment(text string) bool {
+	text = strings.ToLower(apostrophePattern.ReplaceAllString(emojiShortcodePattern.ReplaceAllString(text, " "), ""))
+	thanked := false
+	for _, phrase := range acknowledgmentSplitPattern.Split(text, -1) {
+		// Dropping the bot's name, as in "thanks wolfy"
+		var words []string
+		for _, word := range strings.Fields(strings.Trim(phrase, " :)(?")) {
+			if word != "wolfy" && word != "wolfybot" {
+				words = append(words, word)
+			}
+		}
+		phrase = strings.Join(words, " ")
+		switch {
+		case phrase == "":
+		case gratitudePhrases[phrase]:
+			thanked = true
+		case len(words) > 1 && acknowledgmentPhrases[words[0]] && gratitudePhrases[strings.Join(words[1:], " ")]:
+			// Run together, as in "ok thanks"
+			thanked = true
+		case !acknowledgmentPhrases[phrase]:
+			return false
+		}
+	}
+	return thanked
+}
+
+// Global function for checking whether thanks are for the bot: sent in a DM, mentioning the bot or starting with a
+// trigger prefix, or posted in a thread the bot answered in, and never while naming someone else
+func isAcknowledgmentForBot(event *slack.MessageEvent, raw string) bool {
+	if strings.Contains(event.Msg.Text, "<@") || strings.Contains(event.Msg.Text, "<!") {
+		return false
+	}
+	if isDirectMessage(event.Channel) {
+		return true
+	}
+	if currentBotUserID() != "" && strings.Contains(raw, "<@"+currentBotUserID()+">") {
+		return true
+	}
+	if _, ok := matchTriggerPrefix(raw); ok {
+		return true
+	}
+	_, ok := participatedThread(event)
+	return ok
+}
+
+// Global function for choosing one of the acknowledgment replies, which the catalog lists one per line
+func acknowledgmentReply(ctx context.Context, user string) string {
+	var replies []string
+	for _, reply := range strings.Split(personalizedMessage(ctx, "acknowledgment", user), "\n") {
+		if reply = strings.TrimSpace(reply); reply != "" {
+			replies = append(replies, reply)
+		}
+	}
+	if len(replies) == 0 {
+		return ""
+	}
+	return replies[rand.Intn(len(replies))]
+}
+
+// Global function for answering thanks addressed to the bot, reporting whether the message was consumed: in a thread
+// with the configured reaction (so the thread stays quiet), otherwise with a brief "you're welcome"
+func handleAcknowledgment(ctx context.Context, event *slack.MessageEvent, raw string) bool {
+	if !isAcknowledgment(event.Msg.Text) || !isAcknowledgmentForBot(event, raw) {
+		return false
+	}
+	metrics.inc("wolfy_intents_total", "intent", "acknowledgment")
+	if !handlerEnabled("acknowledgment") {
+		noteHandlerDisabled(event, "acknowledgment")
+		return true
+	}
+
+	if event.ThreadTimestamp != "" && config.AcknowledgmentReaction != "" {
+		err := slackAPI().AddReaction(config.AcknowledgmentReaction, slack.NewRefToMessage(event.Channel, event.Timestamp))
+		if err == nil {
+			traceStep(ctx, "acknowledged thanks with :%s:", config.AcknowledgmentReaction)
+			return true
+		}
+		log.Printf("SLACK ERROR: Unable to react to thanks in %s.\nError Details: %v", event.Channel, err)
+	}
+	traceStep(ctx, "replied to thanks")
+	postText(answerDestination(event), acknowledgmentReply(ctx, event.User))
+	return true
+}
+
+// Handler replying to an "acknowledgment" intent from Wit.ai
+func handleAcknowledgmentIntent(ctx context.Context, event *slack.MessageEvent, entity wit.MessageEntity) (handlerResponse, error) {
+	return handlerResponse{Text: acknowledgmentReply(ctx, event.User)}, nil
+}
@@ -0,0 +1,123 @@
+//////////////////////////////////////////////////
+// Acknowledgment Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing which thank-yous are taken as thanks for the bot
+import (
+	"context" // Permits the handler's request context
+	"testing" // Permits Go testing
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global function for building a message as handleMSGEvent passes it on: its raw text, and its text with the bot's
+// mention and any trigger prefix removed
+func acknowledgmentMessage(channel string, thread string, raw string) (*slack.MessageEvent, string) {
+	event := testMessage(channel, "UTHANKS", "8400.000002", raw)
+	event.ThreadTimestamp = thread
+	event.Msg.Text = normalizeQueryText(stripTriggerPrefix(channel, stripBotMention(raw)))
+	return event, raw
+}
+
+// Test for messages made only of thanks and acknowledgments counting as thanks, and any that ask, name someone, or
+// merely acknowledge not counting
+func TestIsAcknowledgment(t *testing.T) {
+	cases := []struct {
+		text string
+		want bool
+	}{
+		{"thanks", true},
+		{"Thank you!", true},
+		{"thx :pray:", true},
+		{"ok thanks", true},
+		{"Perfect, that's what I needed.", true},
+		{"great - much appreciated", true},
+		{"cheers wolfy", true},
+		{"Thanks so much!! :)", true},
+		{"ok", false},
+		{"cool, got it", false},
+		{"thanks alice", false},
+		{"thanks @alice", false},
+		{"thanks, what's the weather in Paris?", false},
+		{"no thanks", false},
+		{"", false},
+	}
+	for _, c := range cases {
+		if got := isAcknowledgment(c.text); got != c.want {
+			t.Errorf("isAcknowledgment(%q) = %v; want %v", c.text, got, c.want)
+		}
+	}
+}
+
+// Test for thanks counting as the bot's only when sent in a DM, with its mention or trigger prefix, or in a thread
+// it answered in, and never when naming someone else
+func TestIsAcknowledgmentForBot(t *testing.T) {
+	touchThread(threadKey("CTHANKS", "8400.000001"), "42")
+	cases := []struct {
+		name    string
+		channel string
+		thread  string
+		raw     string
+		want    bool
+	}{
+		{"DM", "DTHANKS", "", "thanks!", true},
+		{"mention", "CTHANKS", "", "<@UBOT> thanks!", true},
+		{"trigger prefix", "CTHANKS", "", "wolfy: thanks", true},
+		{"answered thread", "CTHANKS", "8400.000001", "thanks!", true},
+		{"unrelated thread", "CTHANKS", "8400.000009", "thanks!", false},
+		{"channel", "CTHANKS", "", "thanks!", false},
+		{"channel thanking someone else", "CTHANKS", "", "thanks <@UALICE>", false},
+		{"mention thanking someone else too", "CTHANKS", "", "<@UBOT> thanks <@UALICE>", false},
+		{"answered thread thanking someone else", "CTHANKS", "8400.000001", "thanks <@UALICE>!", false},
+		{"DM thanking someone else", "DTHANKS", "", "thanks <@UALICE>", false},
+		{"channel broadcast", "CTHANKS", "", "<@UBOT> thanks <!here>", false},
+	}
+	for _, c := range cases {
+		event, raw := acknowledgmentMessage(c.channel, c.thread, c.raw)
+		if got := isAcknowledgmentForBot(event, raw); got != c.want {
+			t.Errorf("%s: isAcknowledgmentForBot(%q) = %v; want %v", c.name, c.raw, got, c.want)
+		}
+	}
+}
+
+// Test for thanks to the bot being answered with a reply, or in a thread with a reaction, and other messages (thanks
+// to someone else among them) being passed on to the usual handlers
+func TestHandleAcknowledgment(t *testing.T) {
+	withConfig(t, func(config *Config) { config.AcknowledgmentReaction = "heart" })
+	touchThread(threadKey("CTHANKS", "8400.000001"), "42")
+	cases := []struct {
+		name     string
+		channel  string
+		thread   string
+		raw      string
+		consumed bool
+		reacted  bool
+	}{
+		{"DM", "DTHANKSREPLY", "", "thank you", true, false},
+		{"mention", "CTHANKSREPLY", "", "<@UBOT> thanks a lot", true, false},
+		{"answered thread", "CTHANKS", "8400.000001", "perfect, thanks", true, true},
+		{"channel thanking someone else", "CTHANKSREPLY", "", "thanks <@UALICE>", false, false},
+		{"question", "DTHANKSREPLY", "", "thanks, what is 5 * 6", false, false},
+	}
+	for _, c := range cases {
+		event, raw := acknowledgmentMessage(c.channel, c.thread, c.raw)
+		destination := answerDestination(event)
+		posted, reactions := len(postsContaining(destination, "")), len(fakeSlack.callsTo("reactions.add"))
+		if got := handleAcknowledgment(context.Background(), event, raw); got != c.consumed {
+			t.Errorf("%s: handleAcknowledgment(%q) = %v; want %v", c.name, c.raw, got, c.consumed)
+			continue
+		}
+		if got := len(fakeSlack.callsTo("reactions.add")) - reactions; got != map[bool]int{true: 1, false: 0}[c.reacted] {
+			t.Errorf("%s: added %d reactions; want reacted %v", c.name, got, c.reacted)
+		}
+		if c.consumed && !c.reacted {
+			waitFor(t, c.name+" reply", func() bool { return len(postsContaining(destination, "")) == posted+1 })
+		} else if got := len(postsContaining(destination, "")) - posted; got != 0 {
+			t.Errorf("%s: posted %d replies; want none", c.name, got)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+//////////////////////////////////////////////////
+// Activity Feed Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for the in-memory feed of recently handled messages and connection state
+import (
+	"fmt"     // Permits formatting of "!recent" lines
+	"strconv" // Permits parsing of Slack message timestamps and "!recent" counts
+	"strings" // Permits joining of "!recent" lines
+	"sync"    // Permits concurrency-safe access to the feed
+	"time"    // Permits latency measurement
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constant holding the text shown instead of the question of a user who opted out of having theirs kept
+const optedOutQuery = "(hidden: the asker opted out)"
+
+// Global struct holding one handled message as shown on the dashboard and by "!recent", keyed by the message it
+// answered (channel and timestamp, as in the dedup and coordination logs)
+type activityEntry struct {
+	Request string
+	At      time.Time
+	User    string
+	Channel string
+	Query   string
+	Intent  string
+	Outcome string
+	Latency time.Duration
+}
+
+// Global struct holding a fixed-size ring of recent activity, newest overwriting oldest
+type activityFeed struct {
+	mu      sync.Mutex
+	entries []activityEntry
+	next    int
+}
+
+// Global activity feed shared by the message handlers and the dashboard
+var recentActivity = &activityFeed{}
+
+// Global RTM connection state ("connecting", "connected", "disconnected") and when it last changed
+var (
+	connectionMu      sync.Mutex
+	connectionState   = "starting"
+	connectionChanged = time.Now()
+)
+
+// Global function for recording an RTM connection state change
+func setConnectionState(state string) {
+	connectionMu.Lock()
+	connectionState, connectionChanged = state, time.Now()
+	connectionMu.Unlock()
+}
+
+// Global function for reading the current RTM connection state
+func currentConnectionState() (string, time.Time) {
+	connectionMu.Lock()
+	defer connectionMu.Unlock()
+	return connectionState, connectionChanged
+}
+
+// Global function for measuring how long ago Slack says a message was sent (zero when unknown)
+func messageLatency(timestamp string, now time.Time) time.Duration {
+	seconds, err := strconv.ParseFloat(timestamp, 64)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return now.Sub(time.Unix(0, int64(seconds*float64(time.Second))))
+}
+
+// Global function for checking whether a user opted out of having their questions kept for admins to read
+func optedOut(user string) bool {
+	for _, optedOut := range config.OptedOutUsers {
+		if optedOut == user {
+			return true
+		}
+	}
+	return false
+}
+
+// Method for adding an entry, overwriting the oldest once full (the size is read from config on each add, so a
+// reload resizes the feed)
+func (feed *activityFeed) add(entry activityEntry) {
+	feed.mu.Lock()
+	defer feed.mu.Unlock()
+
+	size := config.RecentBufferSize
+	if size <= 0 {
+		return
+	}
+	if len(feed.entries) > size {
+		// Dropping entries past a size lowered since they were added
+		feed.entries, feed.next = feed.entries[:0], 0
+	}
+	if len(feed.entries) < size {
+		if feed.next != 0 {
+			// Unwrapping a ring filled at a smaller size, so the new entry lands after the newest
+			feed.entries = append(append([]activityEntry(nil), feed.entries[feed.next:]...), feed.entries[:feed.next]...)
+			feed.next = 0
+		}
+		feed.entries = append(feed.entries, entry)
+		return
+	}
+	feed.entries[feed.next] = entry
+	feed.next = (feed.next + 1) % size
+}
+
+// Method for copying the feed, newest first
+func (feed *activityFeed) snapshot() []activityEntry {
+	feed.mu.Lock()
+	defer feed.mu.Unlock()
+
+	entries := make([]activityEntry, 0, len(feed.entries))
+	for i := len(feed.entries) - 1; i >= 0; i-- {
+		entries = append(entries, feed.entries[(feed.next+i)%len(feed.entries)])
+	}
+	return entries
+}
+
+// Admin command listing the most recent handled messages, optionally only those in a channel or from a user:
+// "!recent [count] [#channel|@user]"
+func runAdminRecent(event *slack.MessageEvent, args []string) string {
+	usage := "Usage: `!recent [count] [#channel|@user]`"
+	limit, channel, user := 10, "", ""
+	for _, arg := range args {
+		if count, err := strconv.Atoi(arg); err == nil && count > 0 {
+			limit = count
+		} else if match := channelReferencePattern.FindStringSubmatch(arg); match != nil {
+			channel = match[1]
+		} else if match := userReferencePattern.FindStringSubmatch(arg); match != nil {
+			user = match[1]
+		} else {
+			return usage
+		}
+	}
+
+	var lines []string
+	for _, entry := range recentActivity.snapshot() {
+		if len(lines) == limit {
+			break
+		}
+		if (channel != "" && entry.Channel != channel) || (user != "" && entry.User != user) {
+			continue
+		}
+		line := fmt.Sprintf("`%s` %s <@%s> in <#%s> *%s* %s", entry.Request, entry.At.Format("Jan 2 15:04:05"), entry.User, entry.Channel, entry.Intent, entry.Outcome)
+		if entry.Latency > 0 {
+			line += fmt.Sprintf(" (%s)", entry.Latency.Round(time.Millisecond))
+		}
+		lines = append(lines, line+"\n> "+entry.Query)
+	}
+	if len(lines) == 0 {
+		return "No matching messages handled since startup (or the buffer is off, see WOLFY_RECENT_BUFFER_SIZE)."
+	}
+	return fmt.Sprintf("*Most recent %d handled message(s), newest first:*\n%s", len(lines), strings.Join(lines, "\n"))
+}
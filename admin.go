@@ -0,0 +1,176 @@
+//////////////////////////////////////////////////
+// Admin Commands Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for "!"-prefixed admin commands
+import (
+	"fmt"     // Permits string formatting of command output
+	"log"     // Permits console logging
+	"sort"    // Permits stable ordering of command listings
+	"strings" // Permits parsing of command text
+	"time"    // Permits formatting of timestamps
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global struct describing a single admin command and the least role that may run it
+type adminCommand struct {
+	description string
+	run         func(event *slack.MessageEvent, args []string) string
+	role        string
+}
+
+// Global constants naming the admin role tiers: viewers may run read-only commands, operators every command
+const (
+	roleViewer   = "viewer"
+	roleOperator = "operator"
+)
+
+// Global map ranking the admin roles, each tier including those below it
+var adminRoleRanks = map[string]int{roleViewer: 1, roleOperator: 2}
+
+// Global map of admin command names (without the "!" prefix) to their implementations
+var adminCommands map[string]adminCommand
+
+// Registering admin commands at init time so "!help" can reference the full map
+func init() {
+	adminCommands = map[string]adminCommand{
+		"help":        {"List the available admin commands.", runAdminHelp, roleViewer},
+		"stats":       {"Show the bot's internal counters.", runAdminStats, roleViewer},
+		"events":      {"Show recently sampled unhandled RTM events (debug mode).", runAdminEvents, roleViewer},
+		"flags":       {"List feature flags and their overrides.", runAdminFlags, roleViewer},
+		"flag":        {"Override a feature flag: `!flag enable|disable|clear <flag> [#channel|@user|workspace|all]`.", runAdminFlag, roleOperator},
+		"trace":       {"Turn routing traces on or off for yourself or a user: `!trace on|off [@user]`.", runAdminTrace, roleOperator},
+		"reload":      {"Re-read the entity routing file, or the message catalogs and personality packs, and swap them in: `!reload routing` / `!reload messages`.", runAdminReload, roleOperator},
+		"cache":       {"Inspect or drop one cached answer: `!cache lookup <query>` / `!cache evict <query>`.", runAdminCache, roleOperator},
+		"apis":        {"Show or flip the external API kill-switch (`!apis off` serves local answers only).", runAdminAPIs, roleOperator},
+		"diagnose":    {"Make a live test call to Wit.ai, Wolfram|Alpha, and Slack and report latency and status.", runAdminDiagnose, roleViewer},
+		"channel":     {"Show or set per-channel configuration: `!channel language <code>|clear [#channel]` / `!channel quiet <HH:MM-HH:MM> [timezone]|clear [#channel]` / `!channel style terse|normal|detailed|clear [#channel]` / `!channel show [#channel]`.", runAdminChannel, roleOperator},
+		"errors":      {"Show recent logged errors with their correlation IDs: `!errors [count] [category]`.", runAdminErrors, roleViewer},
+		"confidence":  {"Show per-intent Wit.ai confidence percentiles and near-threshold rejections: `!confidence report [days]`.", runAdminConfidence, roleViewer},
+		"maintenance": {"List the current and upcoming scheduled maintenance windows.", runAdminMaintenance, roleViewer},
+		"feedback":    {"List the questions whose answers got the most :-1: reactions, after decay.", runAdminFeedback, roleViewer},
+		"config":      {"Export the configuration as YAML to your DM, or import one uploaded there: `!config export` / `!config import [confirm|cancel]`.", runAdminConfig, roleOperator},
+		"recent":      {"Show the most recent handled messages, with their intent, outcome, and latency: `!recent [count] [#channel|@user]`.", runAdminRecent, roleViewer},
+		"handlers":    {"List the handlers that can be switched off, and whether each is on.", runAdminHandlers, roleViewer},
+		"handler":     {"Switch a handler off or on, or back to its configured state: `!handler disable|enable|clear <handler> [--force]` (core handlers need `--force` to switch off).", runAdminHandler, roleOperator},
+		"rotate":      {"Re-read the Slack, Wit.ai, and Wolfram credentials and switch to any that changed without dropping the connection (also on SIGUSR1).", runAdminRotate, roleOperator},
+	}
+}
+
+// Global set of admin commands whose output is shown ephemerally in the channel they were run in (rather than by DM)
+var ephemeralAdminCommands = map[string]bool{"cache": true, "recent": true}
+
+// Global function for splitting a configured admin entry ("U123" or "U123:viewer") into its user ID and role, admins
+// listed without a role being operators
+func parseAdminEntry(entry string) (string, string) {
+	if i := strings.Index(entry, ":"); i >= 0 {
+		return entry[:i], strings.ToLower(entry[i+1:])
+	}
+	return entry, roleOperator
+}
+
+// Global function for looking up a Slack user's admin role, empty when they aren't an admin
+func adminRole(user string) string {
+	for _, entry := range config.AdminUsers {
+		if id, role := parseAdminEntry(entry); id == user {
+			return role
+		}
+	}
+	return ""
+}
+
+// Global function for checking whether a Slack user holds at least the required admin role
+func isAuthorized(user string, requiredRole string) bool {
+	rank, ok := adminRoleRanks[adminRole(user)]
+	return ok && rank >= adminRoleRanks[requiredRole]
+}
+
+// Global function for running an admin command, reporting whether the message was consumed
+func handleAdminCommand(event *slack.MessageEvent) bool {
+	fields := strings.Fields(event.Msg.Text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "!") {
+		return false
+	}
+
+	name := strings.ToLower(strings.TrimPrefix(fields[0], "!"))
+	command, known := adminCommands[name]
+	if !known || (!handlerEnabled("admin_command") && name != "handler" && name != "handlers") {
+		return false
+	}
+
+	// Rejecting admin commands from users without the command's role, revealing no output
+	if !isAuthorized(event.User, command.role) {
+		role := adminRole(event.User)
+		log.Printf("ADMIN COMMAND REJECTED: User %s (role %q) attempted %q, which needs %s.", event.User, role, fields[0], command.role)
+		if role == "" {
+			postText(event.User, "Sorry, that command is only available to WolfyBot admins. :-/")
+		} else {
+			postText(event.User, fmt.Sprintf("Sorry, `%s` needs the %s admin role, and you're a %s. :-/", fields[0], command.role, role))
+		}
+		return true
+	}
+
+	output := command.run(event, fields[1:])
+	if ephemeralAdminCommands[name] {
+		postPrivately(event, output)
+		return true
+	}
+	postText(event.User, output)
+	return true
+}
+
+// Admin command listing every registered admin command
+func runAdminHelp(event *slack.MessageEvent, args []string) string {
+	names := make([]string, 0, len(adminCommands))
+	for name := range adminCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := []string{fmt.Sprintf("*Admin commands* (you're a %s):", adminRole(event.User))}
+	for _, name := range names {
+		line := fmt.Sprintf("`!%s` - %s", name, adminCommands[name].description)
+		if !isAuthorized(event.User, adminCommands[name].role) {
+			line += fmt.Sprintf(" _(%s only)_", adminCommands[name].role)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Admin command dumping all internal counters
+func runAdminStats(event *slack.MessageEvent, args []string) string {
+	keys, values := metrics.sortedKeys()
+	if len(keys) == 0 {
+		return "No counters recorded yet."
+	}
+
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf("%s %d", key, values[key]))
+	}
+	return fmt.Sprintf("*Stats since %s* (external APIs %s):\n```%s```\n%s\n%d recent errors buffered (`!errors` to list them).", startedAt.Format(time.RFC1123), externalAPIsStatus(), strings.Join(lines, "\n"), describeMonthlySpend(time.Now())+"\n"+describeWitQueue(time.Now())+describeRecoveryStats(time.Now())+"\n"+describeCaches(), len(recentErrors.snapshot()))
+}
+
+// Admin command listing the ring buffer of recently sampled unhandled events
+func runAdminEvents(event *slack.MessageEvent, args []string) string {
+	if !config.Debug {
+		return "Unhandled event sampling is only active in debug mode (WOLFY_DEBUG=true)."
+	}
+
+	events := unhandledEvents.snapshot()
+	if len(events) == 0 {
+		return "No unhandled events sampled yet."
+	}
+
+	lines := make([]string, 0, len(events))
+	for _, sampled := range events {
+		lines = append(lines, fmt.Sprintf("%s %s %s", sampled.ReceivedAt.Format("15:04:05"), sampled.Type, sampled.Sample))
+	}
+	return fmt.Sprintf("*Recent unhandled events (newest first):*\n```%s```", strings.Join(lines, "\n"))
+}
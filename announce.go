@@ -0,0 +1,115 @@
+//////////////////////////////////////////////////
+// Announcement Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for admin channel announcements and run-state tracking
+import (
+	"encoding/json" // Permits (de)serialization of the state file
+	"fmt"           // Permits string formatting of announcements
+	"io/ioutil"     // Permits reading and writing of the state file
+	"log"           // Permits console logging
+	"strings"       // Permits string manipulation
+	"time"          // Permits timestamps
+)
+
+// Global struct holding the run state persisted between restarts
+type runState struct {
+	Version       string    `json:"version"`
+	StartedAt     time.Time `json:"started_at"`
+	StoppedAt     time.Time `json:"stopped_at,omitempty"`
+	CleanShutdown bool      `json:"clean_shutdown"`
+}
+
+// Global variables holding the current start time and the previous shutdown reason
+var (
+	startedAt              time.Time
+	previousShutdownReason string
+)
+
+// Global function for reading the previous run state and marking the current run as started
+func recordStartup() {
+	startedAt = time.Now()
+	previousShutdownReason = describePreviousShutdown(readRunState())
+
+	// A crash leaves this record behind untouched, which is how we detect it on the next start
+	if err := writeRunState(runState{Version: version, StartedAt: startedAt}); err != nil {
+		log.Printf("STATE FILE ERROR: Unable to record startup.\nError Details: %v", err)
+	}
+}
+
+// Global function for marking the current run as cleanly shut down
+func recordCleanShutdown() {
+	state := runState{Version: version, StartedAt: startedAt, StoppedAt: time.Now(), CleanShutdown: true}
+	if err := writeRunState(state); err != nil {
+		log.Printf("STATE FILE ERROR: Unable to record clean shutdown.\nError Details: %v", err)
+	}
+}
+
+// Global function for loading the run state file (nil when missing or unreadable)
+func readRunState() *runState {
+	data, err := ioutil.ReadFile(config.StateFile)
+	if err != nil {
+		return nil
+	}
+	state := &runState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil
+	}
+	return state
+}
+
+// Global function for persisting the run state file
+func writeRunState(state runState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(config.StateFile, data, 0644)
+}
+
+// Global function for turning the previous run state into a human-readable reason
+func describePreviousShutdown(state *runState) string {
+	switch {
+	case state == nil:
+		return "unknown (no previous state recorded)"
+	case state.CleanShutdown:
+		return fmt.Sprintf("clean shutdown at %s", state.StoppedAt.Format(time.RFC1123))
+	default:
+		return fmt.Sprintf("crash or forced exit (run started %s never shut down cleanly)", state.StartedAt.Format(time.RFC1123))
+	}
+}
+
+// Global function for checking whether admin announcements should be posted
+func announcementsEnabled() bool {
+	return config.AnnouncementsEnabled && config.AdminChannel != ""
+}
+
+// Global function for posting the startup announcement without blocking the event loop
+func announceStartup() {
+	if !announcementsEnabled() {
+		return
+	}
+
+	text := fmt.Sprintf(
+		":wolf: *WolfyBot %s is online.*\nStarted: %s\nTransport: %s\nHandlers: %s\nPrevious shutdown: %s",
+		version,
+		startedAt.Format(time.RFC1123),
+		config.ConnectionMode,
+		strings.Join(entityHandlerNames(), ", "),
+		previousShutdownReason,
+	)
+	go postText(config.AdminChannel, text)
+}
+
+// Global function for queueing the shutdown announcement, which the shutdown drain then waits to send along with
+// every other pending post
+func announceShutdown() {
+	if !announcementsEnabled() {
+		return
+	}
+	postText(config.AdminChannel, fmt.Sprintf(":wrench: WolfyBot %s is going down for maintenance. Back soon!", version))
+}
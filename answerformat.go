@@ -0,0 +1,96 @@
+//////////////////////////////////////////////////
+// Answer Formatting Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for presenting each kind of answer with its own configurable template
+import (
+	"context" // Permits tracing of the chosen template
+	"log"     // Permits console logging
+	"regexp"  // Permits classification of answer text
+	"strings" // Permits string building and normalization
+)
+
+// Global patterns classifying short answers: a number with an optional unit, a calendar date, and list separators
+var (
+	numberAnswerPattern = regexp.MustCompile(`^(?:≈\s*)?[-+\x{2212}]?\d[\d.,'\x{202f}\x{a0} ]*(?:\s*×\s*10\^-?\d+)?(?:\s*%|\s+[^\s\d]+(?:\s+[^\s\d]+){0,2})?$`)
+	dateAnswerPattern   = regexp.MustCompile(`(?i)^(?:(?:monday|tuesday|wednesday|thursday|friday|saturday|sunday),?\s+)?(?:(?:january|february|march|april|may|june|july|august|september|october|november|december)\s+\d{1,2},?\s+\d{4}|\d{1,2}\s+(?:january|february|march|april|may|june|july|august|september|october|november|december)\s+\d{4}|\d{4}-\d{2}-\d{2})$`)
+	listSplitPattern    = regexp.MustCompile(`,\s*(?:and\s+)?|\s+and\s+`)
+)
+
+// Global map of the templates an answer type can be given, each wrapping the answer's text
+var answerTemplates = map[string]func(kind string, text string) string{
+	"plain":     func(kind, text string) string { return text },
+	"bold":      func(kind, text string) string { return "*" + text + "*" },
+	"italic":    func(kind, text string) string { return "_" + text + "_" },
+	"code":      func(kind, text string) string { return "`" + text + "`" },
+	"codeblock": func(kind, text string) string { return "```\n" + text + "\n```" },
+	"quote":     func(kind, text string) string { return "> " + strings.Replace(text, "\n", "\n> ", -1) },
+	"bullets":   bulletAnswer,
+}
+
+// Global function for classifying an answer as a number, a date, a list, a table, or prose
+func classifyAnswer(text string) string {
+	text = strings.TrimSpace(text)
+	switch {
+	case strings.Contains(text, " | ") || strings.Contains(text, "\n"):
+		return "table"
+	case dateAnswerPattern.MatchString(text):
+		return "date"
+	case numberAnswerPattern.MatchString(text):
+		return "number"
+	case isListAnswer(text):
+		return "list"
+	}
+	return "prose"
+}
+
+// Global function for checking whether an answer is a run of at least three short items ("Mercury, Venus, and Earth")
+func isListAnswer(text string) bool {
+	items := listSplitPattern.Split(text, -1)
+	if len(items) < 3 {
+		return false
+	}
+	for _, item := range items {
+		if words := len(strings.Fields(item)); words == 0 || words > 4 {
+			return false
+		}
+	}
+	return true
+}
+
+// Global function for rendering an answer as bullets: one per list item, or one per row for tables and multi-line text
+func bulletAnswer(kind string, text string) string {
+	var items []string
+	if kind == "list" {
+		items = listSplitPattern.Split(text, -1)
+	} else {
+		items = strings.Split(text, "\n")
+	}
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			lines = append(lines, "• "+item)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Global function for applying the template configured for an answer's type, leaving it plain when none is set
+func formatAnswer(ctx context.Context, text string) string {
+	kind := classifyAnswer(text)
+	name, ok := config.AnswerFormats[kind]
+	if !ok {
+		return text
+	}
+	template, ok := answerTemplates[strings.ToLower(name)]
+	if !ok {
+		log.Printf("CONFIG ERROR: Ignoring unknown answer format %q for %s answers.", name, kind)
+		return text
+	}
+	traceStep(ctx, "formatted %s answer as %s", kind, name)
+	return template(kind, strings.TrimSpace(text))
+}
@@ -0,0 +1,136 @@
+//////////////////////////////////////////////////
+// Archive Channel Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for mirroring each question and answer to an archive channel as a searchable in-Slack record
+import (
+	"crypto/sha256" // Permits stable pseudonyms for redacted users
+	"fmt"           // Permits formatting of archive entries
+	"log"           // Permits console logging
+	"regexp"        // Permits redaction of user mentions
+	"sync"          // Permits concurrency-safe rate limiting and privacy lookups
+	"time"          // Permits the rate limit window
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constants capping how much of a question and answer an archive entry holds, and the rate limit window
+const (
+	maxArchiveText     = 500
+	archiveRateWindow  = time.Minute
+	archivePrivacyTTL  = time.Hour
+	archiveRedactedTag = "user-"
+)
+
+// Global pattern recognizing user mentions ("<@U0123>") redacted from archived text
+var userMentionPattern = regexp.MustCompile(`<@[UW][A-Z0-9]+(?:\|[^>]*)?>`)
+
+// Global state of the archive's rate limit window and of the privacy lookups for conversations seen so far
+var archiveState = struct {
+	mu          sync.Mutex
+	windowStart time.Time
+	posted      int
+	dropped     int
+	private     map[string]archivePrivacy
+}{private: map[string]archivePrivacy{}}
+
+// Global struct caching whether a conversation is private, and when that was looked up
+type archivePrivacy struct {
+	private  bool
+	lookedUp time.Time
+}
+
+// Global function for checking whether a conversation is private (a DM, group DM, or private channel), failing closed
+// when Slack can't say
+func isPrivateConversation(channel string) bool {
+	if channel == "" || isDirectMessage(channel) || channel[0] == 'G' {
+		return true
+	}
+	archiveState.mu.Lock()
+	cached, ok := archiveState.private[channel]
+	archiveState.mu.Unlock()
+	if ok && time.Since(cached.lookedUp) < archivePrivacyTTL {
+		return cached.private
+	}
+
+	info, err := slackAPI().GetConversationInfo(channel, false)
+	if err != nil {
+		log.Printf("ARCHIVE ERROR: Unable to check whether %s is private; not mirroring from it.\nError Details: %v", channel, err)
+		return true
+	}
+	private := info.IsPrivate || info.IsIM || info.IsMpIM
+	archiveState.mu.Lock()
+	archiveState.private[channel] = archivePrivacy{private: private, lookedUp: time.Now()}
+	archiveState.mu.Unlock()
+	return private
+}
+
+// Global function for naming the asker in the archive: a mention, or a stable pseudonym when users are redacted
+func archiveUserName(user string) string {
+	if !config.ArchiveRedactUsers {
+		return "<@" + user + ">"
+	}
+	sum := sha256.Sum256([]byte(user))
+	return fmt.Sprintf("%s%x", archiveRedactedTag, sum[:2])
+}
+
+// Global function for redacting user mentions from archived text when users are redacted
+func archiveText(text string) string {
+	if config.ArchiveRedactUsers {
+		text = userMentionPattern.ReplaceAllString(text, "@someone")
+	}
+	return truncateGraphemes(text, maxArchiveText)
+}
+
+// Global function for claiming a slot in the archive's rate limit window, returning how many entries were dropped in the
+// previous window (to note once) and whether this one may be posted
+func claimArchiveSlot(now time.Time) (int, bool) {
+	archiveState.mu.Lock()
+	defer archiveState.mu.Unlock()
+
+	dropped := 0
+	if now.Sub(archiveState.windowStart) >= archiveRateWindow {
+		dropped = archiveState.dropped
+		archiveState.windowStart, archiveState.posted, archiveState.dropped = now, 0, 0
+	}
+	if archiveState.posted >= config.ArchiveRateLimit {
+		archiveState.dropped++
+		return 0, false
+	}
+	archiveState.posted++
+	return dropped, true
+}
+
+// Global function for mirroring a question and its answer to the archive channel in the background, so a slow or failing
+// archive never holds up or breaks the reply itself
+func mirrorInteraction(event *slack.MessageEvent, entry interaction) {
+	if config.ArchiveChannel == "" || event.Channel == config.ArchiveChannel || entry.Text == "" {
+		return
+	}
+	go func() {
+		if !config.ArchivePrivate && isPrivateConversation(event.Channel) {
+			metrics.inc("wolfy_archived_interactions_total", "result", "private")
+			return
+		}
+		dropped, ok := claimArchiveSlot(time.Now())
+		if !ok {
+			metrics.inc("wolfy_archived_interactions_total", "result", "rate_limited")
+			return
+		}
+
+		where := "a DM"
+		if event.Channel != "" && !isDirectMessage(event.Channel) {
+			where = "<#" + event.Channel + ">"
+		}
+		text := fmt.Sprintf("*Q* (%s in %s): %s\n*A* (%s, %s): %s", archiveUserName(event.User), where, archiveText(entry.Text), intentLabel(entry.EntityKey), entry.Outcome, archiveText(entry.Answer))
+		if dropped > 0 {
+			text = fmt.Sprintf("_(%d earlier exchanges weren't archived, over the %d per minute limit.)_\n%s", dropped, config.ArchiveRateLimit, text)
+		}
+		postText(config.ArchiveChannel, text)
+		metrics.inc("wolfy_archived_interactions_total", "result", "posted")
+	}()
+}
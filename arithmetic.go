@@ -0,0 +1,34 @@
+//////////////////////////////////////////////////
+// Exact Arithmetic Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for answering big-number arithmetic exactly without any external API
+import (
+	"fmt" // Permits string formatting of replies
+
+	answer "github.com/AakashSudhakar/wolfybot/internal/answer" // Internal local answers
+	slack "github.com/nlopes/slack"                             // External Slack API
+)
+
+// Local answerer for exact big-number arithmetic ("factorial of 100", "2^512 - 1"), leaving anything symbolic to Wolfram
+func answerExactArithmetic(event *slack.MessageEvent) (string, bool) {
+	asked, expression, ok := answer.ArithmeticExpression(event.Msg.Text)
+	if !ok {
+		return "", false
+	}
+	value, err := answer.EvaluateExact(expression)
+	if err != nil {
+		if err == answer.ErrArithmeticLimit {
+			metrics.inc("wolfy_exact_arithmetic_total", "result", "too_large")
+		}
+		return "", false
+	}
+
+	// Exact results skip locale rounding, which would throw away the digits that make them exact
+	metrics.inc("wolfy_exact_arithmetic_total", "result", "answered")
+	return fmt.Sprintf("%s = %s", asked, answer.FormatExact(value)), true
+}
@@ -0,0 +1,51 @@
+//////////////////////////////////////////////////
+// Data Recency Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for noting how recent the data behind a statistic is
+import (
+	"context" // Permits bounding the full results call
+	"regexp"  // Permits extraction of date qualifiers
+	"strings" // Permits string normalization
+
+	wolfram "github.com/Krognol/go-wolfram" // External Wolfram API
+)
+
+// Global patterns finding Wolfram's recency qualifiers: "as of June 2024" phrases and parenthesized years like "(2023 estimate)"
+var (
+	asOfPhrasePattern = regexp.MustCompile(`(?i)\bas of\s+(?:[a-z]+\s+)?(?:\d{1,2},?\s+)?(?:19|20)\d{2}\b`)
+	yearNotePattern   = regexp.MustCompile(`\(([^()]*\b(?:19|20)\d{2}\b[^()]*)\)`)
+)
+
+// Global function for pulling a recency qualifier out of pod text, empty when it has none
+func extractAsOf(text string) string {
+	if phrase := asOfPhrasePattern.FindString(text); phrase != "" {
+		return phrase
+	}
+	if match := yearNotePattern.FindStringSubmatch(text); match != nil {
+		return strings.TrimSpace(match[1])
+	}
+	return ""
+}
+
+// Global function for finding how recent a numeric answer's data is via full results, empty when disabled, already
+// stated in the answer, or not reported
+func answerAsOf(ctx context.Context, client *wolfram.Client, query string, units wolfram.Unit, answer string) string {
+	if !config.AnswerAsOfDates || !isNumericAnswer(answer) || extractAsOf(answer) != "" {
+		return ""
+	}
+	result, err := fetchFullResult(ctx, client, query, candidateParams(units, ""))
+	if err != nil {
+		traceStep(ctx, "as-of lookup failed: %v", err)
+		return ""
+	}
+	asOf := extractAsOf(result.primaryText())
+	if asOf != "" {
+		traceStep(ctx, "data is %s", asOf)
+	}
+	return asOf
+}
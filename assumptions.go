@@ -0,0 +1,119 @@
+//////////////////////////////////////////////////
+// Input Assumptions Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for letting askers settle an ambiguous reading up front ("treat mercury as a chemical element")
+import (
+	"context" // Permits honoring of the handler deadline
+	"regexp"  // Permits recognition of assumption directives
+	"sort"    // Permits longest-first matching of category names
+	"strings" // Permits string normalization and stripping
+
+	wolfram "github.com/Krognol/go-wolfram" // External Wolfram API
+)
+
+// Global map of the categories an asker can name to the Wolfram category a "Clash" assumption selects
+var assumptionCategories = map[string]string{
+	"chemical element":      "Element",
+	"element":               "Element",
+	"chemical":              "Chemical",
+	"chemical compound":     "Chemical",
+	"planet":                "Planet",
+	"movie":                 "Movie",
+	"film":                  "Movie",
+	"city":                  "City",
+	"country":               "Country",
+	"person":                "Person",
+	"animal":                "Species",
+	"species":               "Species",
+	"plant":                 "Species",
+	"mathematical constant": "MathematicalConstant",
+	"constant":              "MathematicalConstant",
+	"unit":                  "Unit",
+	"word":                  "Word",
+	"company":               "Financial",
+	"stock":                 "Financial",
+	"programming language":  "ProgrammingLanguage",
+	"band":                  "MusicAct",
+	"musician":              "MusicAct",
+	"airport":               "Airport",
+}
+
+// Global patterns recognizing "assume metric units" / "using the imperial system" and "treat X as a Y" / "assume X is a Y"
+// directives, each with any punctuation setting it off from the question
+var (
+	unitsDirectivePattern    = regexp.MustCompile(`(?i)[,;(]?\s*\b(?:assum(?:e|ing)|using|use)\s+(?:the\s+)?(metric|imperial|si|us customary)(?:\s+(?:system|units?|measurements?))?\b\s*\)?`)
+	categoryDirectivePattern = buildCategoryDirectivePattern()
+)
+
+// Global struct holding what a question's directives settled: a unit system, and the Wolfram assumptions with their
+// plain descriptions
+type inputAssumptions struct {
+	units       wolfram.Unit
+	hasUnits    bool
+	assumptions []string
+	described   []string
+}
+
+// Registering assumption directives among the built-in capabilities
+func init() {
+	registerCapability("assumptions", "Add \"assume metric units\" or \"treat mercury as a chemical element\" to a question to settle how I read it.", nil)
+}
+
+// Global function for building the category directive pattern, longest category name first so "chemical element"
+// isn't read as "chemical"
+func buildCategoryDirectivePattern() *regexp.Regexp {
+	names := make([]string, 0, len(assumptionCategories))
+	for name := range assumptionCategories {
+		names = append(names, regexp.QuoteMeta(name))
+	}
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+	return regexp.MustCompile(`(?i)[,;(]?\s*\b(?:assum(?:e|ing)|treat(?:ing)?|where|with)\s+["“']?([\pL\pN][\pL\pN' -]*?)["”']?\s+(?:is|as|means)\s+(?:an?\s+|the\s+)?(` + strings.Join(names, "|") + `)s?\b\s*\)?`)
+}
+
+// Global function for pulling assumption directives out of a question, returning the question without them and what
+// they settled
+func parseAssumptionDirectives(query string) (string, inputAssumptions) {
+	var settled inputAssumptions
+	if match := unitsDirectivePattern.FindStringSubmatch(query); match != nil {
+		if strings.EqualFold(match[1], "imperial") || strings.EqualFold(match[1], "us customary") {
+			settled.units = wolfram.Imperial
+		} else {
+			settled.units = wolfram.Metric
+		}
+		settled.hasUnits = true
+		settled.described = append(settled.described, unitsName(settled.units)+" units")
+		query = unitsDirectivePattern.ReplaceAllString(query, " ")
+	}
+	for _, match := range categoryDirectivePattern.FindAllStringSubmatch(query, -1) {
+		word := strings.TrimSpace(match[1])
+		category := assumptionCategories[strings.ToLower(match[2])]
+		settled.assumptions = append(settled.assumptions, "*C."+strings.ToLower(word)+"-_*"+category+"-")
+		settled.described = append(settled.described, word+" as a "+strings.ToLower(match[2]))
+	}
+	query = categoryDirectivePattern.ReplaceAllString(query, " ")
+	return strings.Trim(strings.Join(strings.Fields(query), " "), " ,;:-"), settled
+}
+
+// Global function for answering a question under explicit assumptions via the full results API, which (unlike the short
+// answer API) takes them
+func fetchAssumedAnswer(ctx context.Context, client *wolfram.Client, query string, units wolfram.Unit, assumptions []string) (podAnswer, error) {
+	params := candidateParams(units, "")
+	params.Set("format", "plaintext,image")
+	for _, assumption := range assumptions {
+		params.Add("assumption", assumption)
+	}
+	result, err := fetchFullResult(ctx, client, query, params)
+	if err != nil {
+		return podAnswer{}, err
+	}
+	resolveAsyncPods(ctx, result)
+	if answer := result.answerContent(); answer.text != "" {
+		return answer, nil
+	}
+	return podAnswer{}, errAnswerNotUnderstood
+}
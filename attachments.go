@@ -0,0 +1,28 @@
+//////////////////////////////////////////////////
+// Answer Attachments Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for posting supporting answer detail as a secondary attachment
+import (
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global function for building message options: the concise answer as text, supporting detail (and any image) as a colored attachment
+func answerMessageOptions(text string, details []slack.AttachmentField, imageURL string) []slack.MsgOption {
+	options := []slack.MsgOption{messageText(text)}
+	if !config.AnswerAttachments || (len(details) == 0 && imageURL == "") {
+		// Plaintext-only mode drops the detail entirely
+		return options
+	}
+
+	return append(options, slack.MsgOptionAttachments(slack.Attachment{
+		Color:    config.AnswerAttachmentColor,
+		Fallback: escapeSlackText(text),
+		Fields:   escapeAttachmentFields(details),
+		ImageURL: imageURL,
+	}))
+}
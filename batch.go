@@ -0,0 +1,143 @@
+//////////////////////////////////////////////////
+// Batch Questions Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for answering a pasted list of questions in one threaded reply
+import (
+	"context" // Permits per-question API usage accounting
+	"fmt"     // Permits formatting of numbered answers
+	"regexp"  // Permits detection of list items
+	"strconv" // Permits checking of item numbering
+	"strings" // Permits string manipulation
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global patterns recognizing list items on their own lines, numbered items run together on one line, the month names
+// that make "1. Mai" a date rather than an item, and code fences
+var (
+	listLinePattern   = regexp.MustCompile(`^\s*(?:(\d{1,2})[.)]|[-*•])\s+(\S.*)$`)
+	inlineItemPattern = regexp.MustCompile(`(?:^|\s)(\d{1,2})[.)]\s+`)
+	inlineDatePattern = regexp.MustCompile(`(?i)^(?:jan(?:uary|uar)?|feb(?:ruary|ruar)?|mar(?:ch)?|märz|apr(?:il)?|may|mai|june?|juni|july?|juli|aug(?:ust)?|sept?(?:ember)?|o[ck]t(?:ober)?|nov(?:ember)?|de[cz](?:ember)?)\b`)
+	listLeadInPattern = regexp.MustCompile(`^[^\n]{0,80}:\s*`)
+	codeFencePattern  = regexp.MustCompile("(?s)^\\s*```\\s*(.*?)\\s*```\\s*$")
+)
+
+// Global function for splitting a pasted list ("1. ... 2. ..." or one bullet per line, optionally in a code block) into its
+// questions, returning nil for anything else. Numbered items must count up from 1 so prose with dates or versions ("Go 1.
+// 2 was released...", "on 3. May") never looks like a list.
+func splitQuestionList(text string) []string {
+	text = strings.TrimSpace(text)
+	if match := codeFencePattern.FindStringSubmatch(text); match != nil {
+		text = match[1]
+	}
+
+	if items := splitListLines(text); items != nil {
+		return items
+	}
+	return splitInlineList(strings.TrimSpace(listLeadInPattern.ReplaceAllString(text, "")))
+}
+
+// Global function for splitting a list written one item per line, allowing a lead-in line before it
+func splitListLines(text string) []string {
+	var items []string
+	numbered, bulleted := 0, 0
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		match := listLinePattern.FindStringSubmatch(line)
+		if match == nil {
+			if len(items) == 0 && strings.HasSuffix(strings.TrimSpace(line), ":") {
+				// "Answer these:" and the like
+				continue
+			}
+			return nil
+		}
+		if match[1] != "" {
+			if number, _ := strconv.Atoi(match[1]); number != numbered+1 {
+				return nil
+			}
+			numbered++
+		} else {
+			bulleted++
+		}
+		items = append(items, strings.TrimSpace(match[2]))
+	}
+	if len(items) < 2 || (numbered > 0 && bulleted > 0) {
+		return nil
+	}
+	return items
+}
+
+// Global function for splitting numbered items run together on one line ("1. derivative of ln(x) 2. integral of 1/x"),
+// but not dates written day first ("1. Mai und 2. Juni sind Feiertage")
+func splitInlineList(text string) []string {
+	markers := inlineItemPattern.FindAllStringSubmatchIndex(text, -1)
+	if len(markers) < 2 || markers[0][0] != 0 {
+		return nil
+	}
+	items := make([]string, 0, len(markers))
+	for i, marker := range markers {
+		if number, _ := strconv.Atoi(text[marker[2]:marker[3]]); number != i+1 {
+			return nil
+		}
+		end := len(text)
+		if i+1 < len(markers) {
+			end = markers[i+1][0]
+		}
+		item := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(text[marker[1]:end]), ",;"))
+		if item == "" || inlineDatePattern.MatchString(item) {
+			return nil
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// Global function for answering each item of a pasted list in order, up to the batch cap, in one threaded reply
+func answerQuestionList(ctx context.Context, event *slack.MessageEvent, items []string) {
+	metrics.inc("wolfy_batch_questions_total")
+
+	lines := make([]string, 0, len(items)+1)
+	failed := 0
+	for i, item := range items {
+		if i >= config.BatchQuestionLimit {
+			lines = append(lines, fmt.Sprintf("_Skipped %d more (I answer up to %d questions per message) - send them again in another message._", len(items)-i, config.BatchQuestionLimit))
+			break
+		}
+		if requestCancelled(ctx) {
+			traceStep(ctx, "cancelled by the asker after %d of %d items", i, len(items))
+			return
+		}
+		reply := answerSubQuestion(ctx, event, item)
+		marker, result := "", "answered"
+		if isFailureReply(reply) {
+			marker, result, failed = ":x: ", "failed", failed+1
+		}
+		metrics.inc("wolfy_batch_items_total", "result", result)
+		lines = append(lines, fmt.Sprintf("%d. %s*%s* %s", i+1, marker, isolateDirection(item), isolateDirection(reply)))
+	}
+	if !claimDelivery(ctx) {
+		traceStep(ctx, "cancelled by the asker; dropping the answers")
+		return
+	}
+
+	reply := strings.Join(lines, "\n")
+	channel := answerDestination(event)
+	options := answerMessageOptions(reply, nil, "")
+	if channel == event.Channel && !isDirectMessage(channel) {
+		threadTS := event.ThreadTimestamp
+		if threadTS == "" {
+			threadTS = event.Timestamp
+		}
+		options = append(options, slack.MsgOptionTS(threadTS))
+	}
+	postMessage(channel, options...)
+	traceStep(ctx, "answered a %d item list (%d failed)", len(items), failed)
+	rememberInteraction(event, interaction{Text: event.Msg.Text, EntityKey: "question_list", Answer: reply, Calls: apiCalls(ctx)})
+}
@@ -0,0 +1,46 @@
+//////////////////////////////////////////////////
+// Batch Questions Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing which pasted text is split into a list of questions
+import (
+	"reflect" // Permits comparison of split items
+	"testing" // Permits Go testing
+)
+
+// Test for numbered, bulleted, fenced, and run-together lists being split into their questions, while prose with day
+// first dates, version numbers, a single numbered line, or numbering that doesn't count up from 1 is left whole
+func TestSplitQuestionList(t *testing.T) {
+	cases := []struct {
+		text string
+		want []string
+	}{
+		{"1. derivative of ln(x)\n2. integral of 1/x", []string{"derivative of ln(x)", "integral of 1/x"}},
+		{"Answer these:\n- capital of France\n- 5 * 6", []string{"capital of France", "5 * 6"}},
+		{"```\n1) mass of Earth\n2) mass of Mars\n```", []string{"mass of Earth", "mass of Mars"}},
+		{"1. derivative of ln(x) 2. integral of 1/x, 3. 5 * 6", []string{"derivative of ln(x)", "integral of 1/x", "5 * 6"}},
+		{"Quick ones: 1) capital of France 2) height of Everest", []string{"capital of France", "height of Everest"}},
+		{"on 3. May and 4. June we travel", nil},
+		{"1. Mai und 2. Juni sind Feiertage", nil},
+		{"1. März: what is the weather in Berlin", nil},
+		{"Go 1. 2 was released in 2012", nil},
+		{"Release 1.2 and 2.0 are out", nil},
+		{"version 1.21.3\nversion 1.22.0", nil},
+		{"1.5 million people live there. 2. place went to Lyon", nil},
+		{"1. what is pi", nil},
+		{"Notes:\n1. Go 1.21 is out", nil},
+		{"- what is pi", nil},
+		{"1. first\n3. third", nil},
+		{"2. capital of France 3. height of Everest", nil},
+		{"1. capital of France\n- height of Everest", nil},
+	}
+	for _, c := range cases {
+		if got := splitQuestionList(c.text); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("splitQuestionList(%q) = %q; want %q", c.text, got, c.want)
+		}
+	}
+}
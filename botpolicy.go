@@ -0,0 +1,86 @@
+//////////////////////////////////////////////////
+// Bot Message Policy Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for deciding which bots' messages get answered, without ever answering ourselves
+import (
+	"strings" // Permits normalization of message text
+	"sync"    // Permits concurrency-safe output tracking
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constants bounding how many of our recent outputs are remembered and how long one must be to count as quoted inside another message
+const (
+	recentOutputLimit     = 200
+	minRelayedOutputRunes = 12
+)
+
+// Global ring of our recent message texts (normalized), used to spot allowlisted bots relaying us back
+var recentOutputs = struct {
+	sync.Mutex
+	texts []string
+}{}
+
+// Global function for normalizing text for relay matching: lowercased with whitespace collapsed
+func normalizeOutput(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
+// Global function for remembering one of our own messages
+func noteRecentOutput(text string) {
+	normalized := normalizeOutput(text)
+	if normalized == "" {
+		return
+	}
+	recentOutputs.Lock()
+	defer recentOutputs.Unlock()
+	recentOutputs.texts = append(recentOutputs.texts, normalized)
+	if len(recentOutputs.texts) > recentOutputLimit {
+		recentOutputs.texts = recentOutputs.texts[len(recentOutputs.texts)-recentOutputLimit:]
+	}
+}
+
+// Global function for checking whether a message repeats one of our recent outputs, or quotes one long enough to be unambiguous
+func relaysOurOutput(text string) bool {
+	normalized := normalizeOutput(text)
+	recentOutputs.Lock()
+	defer recentOutputs.Unlock()
+	for _, output := range recentOutputs.texts {
+		if normalized == output || (len([]rune(output)) >= minRelayedOutputRunes && strings.Contains(normalized, output)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Global function for checking whether a bot is on the allowlist by bot ID or username
+func isAllowedBot(event *slack.MessageEvent) bool {
+	for _, allowed := range config.AllowedBots {
+		if allowed == event.BotID || (event.Username != "" && strings.EqualFold(allowed, event.Username)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Global function for deciding whether to handle a message by its sender: people always, other bots only when
+// allowlisted and not relaying our own output back to us (which would loop)
+func acceptsSender(event *slack.MessageEvent) bool {
+	if event.BotID == "" {
+		return true
+	}
+	if !isAllowedBot(event) {
+		return false
+	}
+	if relaysOurOutput(event.Text) {
+		metrics.inc("wolfy_bot_messages_total", "result", "relay_loop")
+		return false
+	}
+	metrics.inc("wolfy_bot_messages_total", "result", "accepted")
+	return true
+}
@@ -0,0 +1,113 @@
+//////////////////////////////////////////////////
+// Bot Message Policy Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing which bots get answered and that a relaying bot can't start a loop
+import (
+	"strconv" // Permits message timestamps
+	"strings" // Permits inspection of posted answers
+	"testing" // Permits Go testing
+	"time"    // Permits message timestamps
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global function for forgetting the outputs a test notes, restoring the ones remembered before it
+func withRecentOutputs(t *testing.T) {
+	recentOutputs.Lock()
+	previous := append([]string(nil), recentOutputs.texts...)
+	recentOutputs.Unlock()
+	t.Cleanup(func() {
+		recentOutputs.Lock()
+		recentOutputs.texts = previous
+		recentOutputs.Unlock()
+	})
+}
+
+// Global function for building a message posted by a bot
+func botMessage(channel string, botID string, username string, text string) *slack.MessageEvent {
+	event := testMessage(channel, "", strconv.FormatInt(time.Now().UnixNano(), 10), text)
+	event.BotID, event.Username = botID, username
+	return event
+}
+
+// Test for people always being accepted and bots only when allowlisted by ID or username, never while repeating or
+// unambiguously quoting one of our recent outputs
+func TestAcceptsSender(t *testing.T) {
+	withConfig(t, func(c *Config) { c.AllowedBots = []string{"BRELAY", "standup-bot"} })
+	withRecentOutputs(t)
+	noteRecentOutput("The speed of light is 299792 km/s")
+	noteRecentOutput("42 km")
+
+	cases := []struct {
+		name  string
+		event *slack.MessageEvent
+		want  bool
+	}{
+		{"person", testMessage("CBOTS", "UPERSON", "7500.000001", "The speed of light is 299792 km/s"), true},
+		{"unlisted bot", botMessage("CBOTS", "BOTHER", "other-bot", "what is 5 * 6"), false},
+		{"allowlisted by ID", botMessage("CBOTS", "BRELAY", "", "what is 5 * 6"), true},
+		{"allowlisted by username", botMessage("CBOTS", "BSTANDUP", "Standup-Bot", "what is 5 * 6"), true},
+		{"relaying our output", botMessage("CBOTS", "BRELAY", "", "the  speed of LIGHT is 299792 km/s"), false},
+		{"quoting our output", botMessage("CBOTS", "BRELAY", "", "Wolfy said: The speed of light is 299792 km/s!"), false},
+		{"repeating a short output", botMessage("CBOTS", "BRELAY", "", "42 km"), false},
+		{"mentioning a short output", botMessage("CBOTS", "BRELAY", "", "how far is 42 km in miles"), true},
+	}
+	relayed := metricValue("wolfy_bot_messages_total", "result", "relay_loop")
+	for _, c := range cases {
+		if got := acceptsSender(c.event); got != c.want {
+			t.Errorf("%s: acceptsSender(%q) = %v; want %v", c.name, c.event.Text, got, c.want)
+		}
+	}
+	if got := metricValue("wolfy_bot_messages_total", "result", "relay_loop") - relayed; got != 3 {
+		t.Errorf("relay loops counted = %d; want 3", got)
+	}
+}
+
+// Test for an allowlisted bot getting one answer and no more: our own copy of the answer and the bot relaying it back
+// are turned away before dispatch, an unlisted bot is too, and the bot's next question still gets through
+func TestBotPingPongStops(t *testing.T) {
+	const channel = "DPINGPONG"
+	withConfig(t, func(c *Config) {
+		c.AllowedBots = []string{"BPONG"}
+		c.RepeatCooldown = 0
+	})
+	withRecentOutputs(t)
+	dispatched := func(event *slack.MessageEvent) bool {
+		_, ok := processedEvents.get(messageEventKey(event))
+		return ok
+	}
+	answered := len(answerPosts())
+
+	// Answering the bot's question as the dispatcher would, then our copy of the answer arriving over RTM
+	handleMSGEvent(botMessage(channel, "BPONG", "pong-bot", "what is 5 * 6"))
+	waitFor(t, "the bot's question to be answered", func() bool { return len(answerPosts()) == answered+1 })
+	answer := answerPosts()[answered].values.Get("text")
+	own := testMessage(channel, currentBotUserID(), strconv.FormatInt(time.Now().UnixNano(), 10), answer)
+	own.BotID = "BSELF"
+	handleIncomingEvent(slack.RTMEvent{Type: "message", Data: own})
+	if dispatched(own) {
+		t.Errorf("our own answer %q was dispatched as a question", answer)
+	}
+
+	for _, event := range []*slack.MessageEvent{
+		botMessage(channel, "BPONG", "pong-bot", answer),
+		botMessage(channel, "BPONG", "pong-bot", "  "+strings.ToUpper(answer)+"\n"),
+		botMessage(channel, "BOTHER", "other-bot", "what is 7 * 8"),
+	} {
+		handleIncomingEvent(slack.RTMEvent{Type: "message", Data: event})
+		if dispatched(event) {
+			t.Errorf("bot %s's message %q was dispatched; want it turned away", event.BotID, event.Text)
+		}
+	}
+	if next := botMessage(channel, "BPONG", "pong-bot", "what is 7 * 8"); !acceptsSender(next) {
+		t.Errorf("acceptsSender(%q) from the allowlisted bot after the relays = false; want true", next.Text)
+	}
+	if got := len(answerPosts()) - answered; got != 1 {
+		t.Errorf("answers posted to the ping-ponging bot = %d; want 1", got)
+	}
+}
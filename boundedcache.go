@@ -0,0 +1,254 @@
+//////////////////////////////////////////////////
+// Bounded Cache Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for the bounded, expiring caches behind every in-process lookup table
+import (
+	"container/list" // Permits LRU ordering of cached entries
+	"fmt"            // Permits formatting of the cache summary
+	"sort"           // Permits listing of caches in a stable order
+	"strings"        // Permits joining of the cache summary
+	"sync"           // Permits concurrency-safe access to each cache
+	"time"           // Permits entry expiry and sweeping
+)
+
+// Global constant setting how often the sweeper drops expired entries from every cache
+const cacheSweepInterval = time.Minute
+
+// Global struct holding one cached value and when it was stored
+type boundedCacheEntry struct {
+	key      string
+	value    interface{}
+	storedAt time.Time
+}
+
+// Global struct holding a cache capped at a number of entries (evicting the least recently stored, or used, beyond it)
+// whose entries expire after a TTL. The limit and TTL are read from config on each use, so reloads apply at once; a
+// limit of 0 or less leaves the cache unbounded and a nil TTL keeps entries until they're evicted.
+type boundedCache struct {
+	name      string
+	mu        sync.Mutex
+	order     *list.List
+	entries   map[string]*list.Element
+	limit     func() int
+	ttl       func(value interface{}) time.Duration
+	recency   bool
+	onEvict   func(key string, value interface{})
+	evictions int64
+}
+
+// Global registry of every bounded cache, for the sweeper, metrics, and "!stats"
+var boundedCaches = struct {
+	sync.Mutex
+	caches []*boundedCache
+}{}
+
+// Global function for creating and registering a bounded cache, its size and evictions reported under its name
+func newBoundedCache(name string, limit func() int, ttl func(value interface{}) time.Duration) *boundedCache {
+	cache := &boundedCache{name: name, order: list.New(), entries: map[string]*list.Element{}, limit: limit, ttl: ttl}
+	boundedCaches.Lock()
+	boundedCaches.caches = append(boundedCaches.caches, cache)
+	boundedCaches.Unlock()
+	return cache
+}
+
+// Method for making lookups count as use, so the least recently used entry is evicted rather than the oldest stored
+func (c *boundedCache) evictingLeastRecentlyUsed() *boundedCache {
+	c.recency = true
+	return c
+}
+
+// Method for running a callback on every entry evicted for capacity or expiry (not on explicit removal), with the
+// cache's lock held
+func (c *boundedCache) evictedBy(onEvict func(key string, value interface{})) *boundedCache {
+	c.onEvict = onEvict
+	return c
+}
+
+// Method for looking up a fresh entry, dropping it once expired
+func (c *boundedCache) get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*boundedCacheEntry)
+	if c.expired(entry, time.Now()) {
+		c.evict(element, "expired")
+		c.report()
+		return nil, false
+	}
+	if c.recency {
+		c.order.MoveToFront(element)
+	}
+	return entry.value, true
+}
+
+// Method for storing an entry as of now
+func (c *boundedCache) put(key string, value interface{}) {
+	c.putAt(key, value, time.Now())
+}
+
+// Method for storing an entry as of a given time (when reloading records kept elsewhere), evicting beyond capacity
+func (c *boundedCache) putAt(key string, value interface{}, storedAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.store(key, value, storedAt)
+}
+
+// Method for storing an entry and evicting beyond capacity (callers hold the lock)
+func (c *boundedCache) store(key string, value interface{}, storedAt time.Time) {
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*boundedCacheEntry)
+		entry.value, entry.storedAt = value, storedAt
+		c.order.MoveToFront(element)
+	} else {
+		c.entries[key] = c.order.PushFront(&boundedCacheEntry{key: key, value: value, storedAt: storedAt})
+	}
+	if limit := c.limit(); limit > 0 {
+		for c.order.Len() > limit {
+			c.evict(c.order.Back(), "capacity")
+		}
+	}
+	c.report()
+}
+
+// Method for storing an entry as of now unless a fresh one is already held, reporting whether it was stored (checked
+// and stored under one lock, so of two concurrent callers only one wins)
+func (c *boundedCache) add(key string, value interface{}) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if element, ok := c.entries[key]; ok && !c.expired(element.Value.(*boundedCacheEntry), now) {
+		return false
+	}
+	c.store(key, value, now)
+	return true
+}
+
+// Method for removing an entry, reporting whether there was one
+func (c *boundedCache) remove(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return false
+	}
+	c.order.Remove(element)
+	delete(c.entries, key)
+	c.report()
+	return true
+}
+
+// Method for removing every entry a predicate matches, returning their keys
+func (c *boundedCache) removeMatching(match func(key string, value interface{}) bool) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var removed []string
+	for key, element := range c.entries {
+		if match(key, element.Value.(*boundedCacheEntry).value) {
+			c.order.Remove(element)
+			delete(c.entries, key)
+			removed = append(removed, key)
+		}
+	}
+	c.report()
+	return removed
+}
+
+// Method for counting the entries held, expired or not
+func (c *boundedCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Method for dropping every expired entry
+func (c *boundedCache) sweep(now time.Time) {
+	if c.ttl == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, element := range c.entries {
+		if c.expired(element.Value.(*boundedCacheEntry), now) {
+			c.evict(element, "expired")
+		}
+	}
+	c.report()
+}
+
+// Method for checking whether an entry has outlived its TTL (callers hold the lock)
+func (c *boundedCache) expired(entry *boundedCacheEntry, now time.Time) bool {
+	return c.ttl != nil && now.Sub(entry.storedAt) > c.ttl(entry.value)
+}
+
+// Method for evicting an entry and counting why (callers hold the lock)
+func (c *boundedCache) evict(element *list.Element, reason string) {
+	entry := element.Value.(*boundedCacheEntry)
+	c.order.Remove(element)
+	delete(c.entries, entry.key)
+	c.evictions++
+	metrics.inc("wolfy_cache_evictions_total", "cache", c.name, "reason", reason)
+	if c.onEvict != nil {
+		c.onEvict(entry.key, entry.value)
+	}
+}
+
+// Method for publishing the cache's entry count (callers hold the lock)
+func (c *boundedCache) report() {
+	metrics.set(int64(c.order.Len()), "wolfy_cache_entries", "cache", c.name)
+}
+
+// Global function for listing the registered caches by name
+func registeredCaches() []*boundedCache {
+	boundedCaches.Lock()
+	caches := append([]*boundedCache(nil), boundedCaches.caches...)
+	boundedCaches.Unlock()
+	sort.Slice(caches, func(i, j int) bool { return caches[i].name < caches[j].name })
+	return caches
+}
+
+// Global function for periodically dropping expired entries from every cache, so entries nobody looks up again don't
+// linger until capacity pushes them out
+func startCacheSweeper() {
+	go func() {
+		for now := range time.Tick(cacheSweepInterval) {
+			sweepCaches(now)
+		}
+	}()
+}
+
+// Global function for dropping every registered cache's entries expired as of a moment
+func sweepCaches(now time.Time) {
+	for _, cache := range registeredCaches() {
+		cache.sweep(now)
+	}
+}
+
+// Global function for summarizing each cache's entries against its limit and its evictions, for "!stats"
+func describeCaches() string {
+	var lines []string
+	for _, cache := range registeredCaches() {
+		cache.mu.Lock()
+		entries, evictions := cache.order.Len(), cache.evictions
+		cache.mu.Unlock()
+
+		limit := "unbounded"
+		if cache.limit() > 0 {
+			limit = fmt.Sprintf("%d", cache.limit())
+		}
+		lines = append(lines, fmt.Sprintf("%s %d/%s (%d evicted)", cache.name, entries, limit, evictions))
+	}
+	return "Caches: " + strings.Join(lines, ", ")
+}
@@ -0,0 +1,207 @@
+//////////////////////////////////////////////////
+// Bounded Cache Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing the bounded, expiring caches behind every in-process lookup table
+import (
+	"reflect" // Permits comparison of evicted keys
+	"strconv" // Permits naming of synthetic entries
+	"strings" // Permits matching of synthetic entries
+	"sync"    // Permits sustained concurrent load
+	"testing" // Permits Go testing
+	"time"    // Permits entry ages and the fake clock
+)
+
+// Test for evicting the oldest stored entry beyond the cap, or the least recently used one when lookups count as
+// use, with the cap read afresh on every store and 0 leaving the cache unbounded
+func TestBoundedCacheCapacityEviction(t *testing.T) {
+	cases := []struct {
+		name    string
+		recency bool
+		evicted []string
+	}{
+		{"oldest stored", false, []string{"a", "b", "c"}},
+		{"least recently used", true, []string{"b", "c", "a"}},
+	}
+	for _, c := range cases {
+		limit := 3
+		cache := newBoundedCache("test_capacity_"+strings.Replace(c.name, " ", "_", -1), func() int { return limit }, nil)
+		if c.recency {
+			cache.evictingLeastRecentlyUsed()
+		}
+		var evicted []string
+		cache.evictedBy(func(key string, value interface{}) { evicted = append(evicted, key) })
+		before := metricValue("wolfy_cache_evictions_total", "cache", cache.name, "reason", "capacity")
+
+		for _, key := range []string{"a", "b", "c"} {
+			cache.put(key, key)
+		}
+		cache.get("a")
+		cache.put("d", "d")
+		limit = 2
+		cache.put("e", "e")
+		if !reflect.DeepEqual(evicted, c.evicted) || cache.len() != 2 {
+			t.Errorf("%s: evicted %v leaving %d entries; want %v leaving 2", c.name, evicted, cache.len(), c.evicted)
+		}
+		if got := metricValue("wolfy_cache_evictions_total", "cache", cache.name, "reason", "capacity") - before; got != 3 {
+			t.Errorf("%s: capacity evictions counted = %d; want 3", c.name, got)
+		}
+
+		limit = 0
+		for i := 0; i < 10; i++ {
+			cache.put("unbounded-"+strconv.Itoa(i), i)
+		}
+		if got := cache.len(); got != 12 {
+			t.Errorf("%s: entries with no cap = %d; want 12", c.name, got)
+		}
+	}
+}
+
+// Test for entries expiring after their own TTL: dropped on lookup, replaceable by add, and kept while fresh
+func TestBoundedCacheExpiry(t *testing.T) {
+	cache := newBoundedCache("test_expiry", func() int { return 10 }, func(value interface{}) time.Duration { return value.(time.Duration) })
+	now := time.Now()
+	cache.putAt("short", time.Minute, now.Add(-2*time.Minute))
+	cache.putAt("long", time.Hour, now.Add(-2*time.Minute))
+	expired := metricValue("wolfy_cache_evictions_total", "cache", "test_expiry", "reason", "expired")
+
+	if _, ok := cache.get("short"); ok {
+		t.Error("get returned an entry past its TTL")
+	}
+	if _, ok := cache.get("long"); !ok {
+		t.Error("get dropped an entry within its TTL")
+	}
+	if got := metricValue("wolfy_cache_evictions_total", "cache", "test_expiry", "reason", "expired") - expired; got != 1 {
+		t.Errorf("expiry evictions counted = %d; want 1", got)
+	}
+	if cache.add("long", time.Hour) {
+		t.Error("add replaced a fresh entry")
+	}
+	cache.putAt("stale", time.Minute, now.Add(-time.Hour))
+	if !cache.add("stale", time.Minute) {
+		t.Error("add refused to replace an expired entry")
+	}
+}
+
+// Test for the sweeper dropping expired entries nobody looks up again from every registered cache, leaving caches
+// without a TTL alone
+func TestCacheSweeper(t *testing.T) {
+	ttl := func(interface{}) time.Duration { return time.Hour }
+	first := newBoundedCache("test_sweep_first", func() int { return 10 }, ttl)
+	second := newBoundedCache("test_sweep_second", func() int { return 10 }, ttl)
+	untimed := newBoundedCache("test_sweep_untimed", func() int { return 10 }, nil)
+	now := time.Now()
+	for _, cache := range []*boundedCache{first, second, untimed} {
+		cache.putAt("old", 1, now.Add(-2*time.Hour))
+		cache.putAt("new", 2, now)
+	}
+
+	sweepCaches(now.Add(cacheSweepInterval))
+	for _, c := range []struct {
+		cache *boundedCache
+		want  int
+	}{{first, 1}, {second, 1}, {untimed, 2}} {
+		if got := c.cache.len(); got != c.want {
+			t.Errorf("%s entries after a sweep = %d; want %d", c.cache.name, got, c.want)
+		}
+	}
+}
+
+// Test for every migrated cache staying at its cap under sustained synthetic load from several writers, checked
+// throughout the load and not only once it ends
+func TestCacheCapsUnderSustainedLoad(t *testing.T) {
+	const capacity, writers, perWriter = 64, 4, 2000
+	withConfig(t, func(c *Config) {
+		c.ThreadTrackingSize, c.UserCacheSize, c.DedupCacheSize = capacity, capacity, capacity
+		c.SessionCacheSize, c.ThreadContextSize, c.AnswerCacheSize = capacity, capacity, capacity
+	})
+	answerCache, _ := answers.(*memoryAnswerCache)
+	if answerCache == nil {
+		answerCache = newMemoryAnswerCache()
+	}
+
+	caches := []struct {
+		cache *boundedCache
+		cap   int
+		load  func(key string)
+	}{
+		{threads, capacity, nil},
+		{userInfo, capacity, nil},
+		{slackDeliveries, capacity, nil},
+		{answerMessages, maxTrackedAnswers, nil},
+		{sessions.users, capacity, nil},
+		{sessions.channels, capacity, nil},
+		{conversationTeams, capacity, nil},
+		{threadParents, capacity, nil},
+		{processedEvents, capacity, nil},
+		{processedReactions, capacity, nil},
+		{recentAnswers, capacity, nil},
+		{answerCache.entries, capacity, func(key string) {
+			answerCache.store(key, cachedAnswer{}, []string{key + ":alias"})
+		}},
+		{answerCache.aliases, capacity, nil},
+	}
+	isSynthetic := func(key string, value interface{}) bool { return strings.HasPrefix(key, "soak:") }
+	t.Cleanup(func() {
+		for _, c := range caches {
+			c.cache.removeMatching(isSynthetic)
+		}
+	})
+
+	var over sync.Map
+	done := make(chan struct{})
+	checked := make(chan struct{})
+	go func() {
+		defer close(checked)
+		for {
+			for _, c := range caches {
+				if got := c.cache.len(); got > c.cap {
+					over.Store(c.cache.name, got)
+				}
+			}
+			select {
+			case <-done:
+				return
+			default:
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for writer := 0; writer < writers; writer++ {
+		wg.Add(1)
+		go func(writer int) {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				key := "soak:" + strconv.Itoa(writer) + ":" + strconv.Itoa(i)
+				for _, c := range caches {
+					switch {
+					case c.load != nil:
+						c.load(key)
+					case c.cache == answerCache.aliases:
+						// Filled through the answer cache's own stores
+					default:
+						c.cache.put(key, i)
+					}
+				}
+			}
+		}(writer)
+	}
+	wg.Wait()
+	close(done)
+	<-checked
+
+	over.Range(func(name, entries interface{}) bool {
+		t.Errorf("%s held %d entries during the load; want at most its cap", name, entries)
+		return true
+	})
+	for _, c := range caches {
+		if got := c.cache.len(); got != c.cap {
+			t.Errorf("%s entries after the load = %d; want its cap of %d", c.cache.name, got, c.cap)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+//////////////////////////////////////////////////
+// Team Branding Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for presenting one deployment with each workspace's own name, emoji, and signature
+import (
+	"encoding/json" // Permits decoding of the branding file
+	"io/ioutil"     // Permits reading of the branding file
+	"log"           // Permits console logging
+	"net/url"       // Permits replacing a post's text
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global struct holding one workspace's branding: the name and emoji its posts start with, and the line they end with
+type teamBranding struct {
+	Name      string `json:"name"`
+	Emoji     string `json:"emoji"`
+	Signature string `json:"signature"`
+}
+
+// Global branding by team ID ("*" applies to teams without their own), loaded from the branding file at startup
+var teamBrandings = map[string]teamBranding{}
+
+// Global teams each conversation (channel or user) was last heard from, so posts to it are branded for its workspace;
+// the least recently used is forgotten beyond the user cache size (falling back to the "*" branding)
+var conversationTeams = newBoundedCache("conversation_teams", func() int { return config.UserCacheSize }, nil).evictingLeastRecentlyUsed()
+
+// Global function for loading the branding file, leaving posts unbranded when it is unusable
+func loadTeamBranding() {
+	if config.TeamBrandingFile == "" {
+		return
+	}
+	data, err := ioutil.ReadFile(config.TeamBrandingFile)
+	if err != nil {
+		log.Printf("CONFIG ERROR: Unable to read team branding file %s.\nError Details: %v", config.TeamBrandingFile, err)
+		return
+	}
+	brandings := map[string]teamBranding{}
+	if err := json.Unmarshal(data, &brandings); err != nil {
+		log.Printf("CONFIG ERROR: Unable to parse team branding file %s.\nError Details: %v", config.TeamBrandingFile, err)
+		return
+	}
+	teamBrandings = brandings
+}
+
+// Global function for noting which team a message's channel and sender belong to
+func noteConversationTeam(event *slack.MessageEvent) {
+	if event.Team == "" || len(teamBrandings) == 0 {
+		return
+	}
+	for _, conversation := range []string{event.Channel, event.User} {
+		if conversation != "" {
+			conversationTeams.put(conversation, event.Team)
+		}
+	}
+}
+
+// Global function for finding the branding for a conversation's team, falling back to the "*" entry
+func brandingFor(conversation string) (teamBranding, bool) {
+	team := ""
+	if value, ok := conversationTeams.get(conversation); ok {
+		team = value.(string)
+	}
+	if branding, ok := teamBrandings[team]; ok && team != "" {
+		return branding, true
+	}
+	branding, ok := teamBrandings["*"]
+	return branding, ok
+}
+
+// Global function for branding a post's text for its conversation's team at send time, after the answer is settled,
+// so answers themselves never depend on the workspace (posts without text are left alone). The method is the Web API
+// method sending it ("chat.postMessage" or "chat.update"), kept as the endpoint while the text is replaced.
+func brandedOptions(conversation string, method string, options []slack.MsgOption) []slack.MsgOption {
+	branding, ok := brandingFor(conversation)
+	if !ok {
+		return options
+	}
+	_, values, err := slack.UnsafeApplyMsgOptions("", conversation, options...)
+	if err != nil || values.Get("text") == "" {
+		return options
+	}
+
+	text := values.Get("text")
+	if branding.Name != "" {
+		text = "*" + escapeSlackText(branding.Name) + ":* " + text
+	}
+	if branding.Emoji != "" {
+		text = branding.Emoji + " " + text
+	}
+	if branding.Signature != "" {
+		text += "\n_" + escapeSlackText(branding.Signature) + "_"
+	}
+	return append(options, slack.UnsafeMsgOptionEndpoint(slack.APIURL+method, func(values url.Values) { values.Set("text", text) }))
+}
@@ -0,0 +1,194 @@
+//////////////////////////////////////////////////
+// Error Burn Rate Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for alerting admins when a pipeline stage fails far more than its usual baseline
+import (
+	"fmt"     // Permits formatting of alerts
+	"log"     // Permits console logging
+	"sort"    // Permits stable ordering of stages
+	"strconv" // Permits parsing of per-stage thresholds
+	"sync"    // Permits concurrency-safe detector state
+	"time"    // Permits rolling windows
+)
+
+// Global constants naming the alert severities, from none to the most urgent
+const (
+	burnSeverityNone     = ""
+	burnSeverityWarning  = "warning"
+	burnSeverityCritical = "critical"
+)
+
+// Global constant holding the failure ratio assumed for a spotless baseline, so any burst against it stays finite
+const burnRateBaselineFloor = 0.01
+
+// Global display names for the stages whose error rates are watched
+var stageNames = map[string]string{"classify": "Classification", "answer": "Answering", "post": "Posting"}
+
+// Global struct holding a stage's cumulative outcome counters at one tick
+type stageSample struct {
+	at     time.Time
+	ok     int64
+	failed int64
+}
+
+// Global struct holding the samples and current alert severity of every watched stage
+type burnRateDetector struct {
+	mu       sync.Mutex
+	samples  map[string][]stageSample
+	severity map[string]string
+}
+
+// Global detector shared by the ticker and the admin alerts
+var burnRates = &burnRateDetector{samples: map[string][]stageSample{}, severity: map[string]string{}}
+
+// Global function for counting the outcome of one classification, answer, or post for burn rate alerting
+func noteStageResult(stage string, failed bool) {
+	result := "ok"
+	if failed {
+		result = "error"
+	}
+	metrics.inc("wolfy_stage_results_total", "stage", stage, "result", result)
+}
+
+// Global function for reading the configured burn multiple per stage, skipping unknown stages and invalid values
+func burnRateThresholds() map[string]float64 {
+	thresholds := map[string]float64{}
+	for stage, value := range config.BurnRateAlerts {
+		threshold, err := strconv.ParseFloat(value, 64)
+		if _, known := stageNames[stage]; !known || err != nil || threshold <= 1 {
+			log.Printf("CONFIG ERROR: Ignoring burn rate threshold %q for %q (stages are classify, answer, post; thresholds must exceed 1).", value, stage)
+			continue
+		}
+		thresholds[stage] = threshold
+	}
+	return thresholds
+}
+
+// Method for recording a stage's counters, dropping samples no longer needed for the long window
+func (detector *burnRateDetector) record(stage string, sample stageSample) {
+	samples := append(detector.samples[stage], sample)
+	// Keeping the newest sample at or before the long window's start as the baseline to subtract
+	cutoff := sample.at.Add(-config.BurnRateLongWindow)
+	drop := 0
+	for drop+1 < len(samples) && !samples[drop+1].at.After(cutoff) {
+		drop++
+	}
+	detector.samples[stage] = samples[drop:]
+}
+
+// Global function for computing the failure ratio and event count across the window ending at the newest sample
+func windowFailureRatio(samples []stageSample, window time.Duration) (float64, int64) {
+	if len(samples) < 2 {
+		return 0, 0
+	}
+	newest := samples[len(samples)-1]
+	base := samples[0]
+	for _, sample := range samples {
+		if sample.at.After(newest.at.Add(-window)) {
+			break
+		}
+		base = sample
+	}
+
+	failed := newest.failed - base.failed
+	events := failed + newest.ok - base.ok
+	if events <= 0 {
+		return 0, 0
+	}
+	return float64(failed) / float64(events), events
+}
+
+// Global function for grading how hard the short window burns against the long-window baseline
+func burnSeverity(short float64, shortEvents int64, long float64, threshold float64) (string, float64) {
+	if shortEvents < int64(config.BurnRateMinEvents) || short == 0 {
+		return burnSeverityNone, 0
+	}
+	if long < burnRateBaselineFloor {
+		long = burnRateBaselineFloor
+	}
+	burn := short / long
+	switch {
+	case burn >= 2*threshold:
+		return burnSeverityCritical, burn
+	case burn >= threshold:
+		return burnSeverityWarning, burn
+	}
+	return burnSeverityNone, burn
+}
+
+// Method for sampling every watched stage's counters at now, returning alerts for stages whose severity changed
+func (detector *burnRateDetector) evaluate(now time.Time, counters map[string]int64, thresholds map[string]float64) []string {
+	detector.mu.Lock()
+	defer detector.mu.Unlock()
+
+	stages := make([]string, 0, len(thresholds))
+	for stage := range thresholds {
+		stages = append(stages, stage)
+	}
+	sort.Strings(stages)
+
+	var alerts []string
+	for _, stage := range stages {
+		detector.record(stage, stageSample{
+			at:     now,
+			ok:     counters[metricKey("wolfy_stage_results_total", "stage", stage, "result", "ok")],
+			failed: counters[metricKey("wolfy_stage_results_total", "stage", stage, "result", "error")],
+		})
+		samples := detector.samples[stage]
+		short, shortEvents := windowFailureRatio(samples, config.BurnRateShortWindow)
+		long, _ := windowFailureRatio(samples, config.BurnRateLongWindow)
+		severity, burn := burnSeverity(short, shortEvents, long, thresholds[stage])
+
+		previous := detector.severity[stage]
+		if severity == previous {
+			continue
+		}
+		detector.severity[stage] = severity
+		if severity == burnSeverityNone {
+			alerts = append(alerts, fmt.Sprintf(":white_check_mark: *Resolved*: %s errors are at %.1f%% over the last %s, back under %.1fx the %s baseline.", stageNames[stage], 100*short, config.BurnRateShortWindow, thresholds[stage], config.BurnRateLongWindow))
+			continue
+		}
+		icon := ":warning:"
+		if severity == burnSeverityCritical {
+			icon = ":rotating_light:"
+		}
+		alerts = append(alerts, fmt.Sprintf("%s *%s*: %s errors are at %.1f%% over the last %s (%d events), %.1fx the %s baseline of %.1f%%.",
+			icon, severity, stageNames[stage], 100*short, config.BurnRateShortWindow, shortEvents, burn, config.BurnRateLongWindow, 100*long))
+	}
+
+	for _, stage := range stages {
+		value := int64(0)
+		switch detector.severity[stage] {
+		case burnSeverityWarning:
+			value = 1
+		case burnSeverityCritical:
+			value = 2
+		}
+		metrics.set(value, "wolfy_burn_rate_severity", "stage", stage)
+	}
+	return alerts
+}
+
+// Global function for starting the ticker that watches stage error rates and alerts the admin channel on transitions
+func startBurnRateAlerts() {
+	thresholds := burnRateThresholds()
+	if len(thresholds) == 0 || config.BurnRateInterval <= 0 {
+		return
+	}
+	go func() {
+		for now := range time.Tick(config.BurnRateInterval) {
+			for _, alert := range burnRates.evaluate(now, metrics.snapshot(), thresholds) {
+				log.Printf("BURN RATE ALERT: %s", alert)
+				metrics.inc("wolfy_burn_rate_alerts_total")
+				if config.AdminChannel != "" {
+					postText(config.AdminChannel, alert)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,122 @@
+//////////////////////////////////////////////////
+// Error Burn Rate Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing burn rate alerts against synthetic error series
+import (
+	"reflect" // Permits comparison of alert sequences
+	"strings" // Permits inspection of alerts
+	"testing" // Permits Go testing
+	"time"    // Permits the fake clock
+)
+
+// Global struct describing a stretch of synthetic traffic: how many minutes it lasts and each minute's outcomes
+type burnTraffic struct {
+	minutes int
+	ok      int64
+	failed  int64
+}
+
+// Global struct holding an alert raised while replaying traffic, by the minute it was raised at
+type burnAlert struct {
+	minute int
+	text   string
+}
+
+// Global function for replaying synthetic traffic through a fresh detector for one stage, a tick a minute on a fake
+// clock, returning the alerts raised
+func replayBurnTraffic(stage string, threshold float64, traffic []burnTraffic) []burnAlert {
+	detector := &burnRateDetector{samples: map[string][]stageSample{}, severity: map[string]string{}}
+	thresholds := map[string]float64{stage: threshold}
+	clock := time.Date(2026, time.June, 10, 0, 0, 0, 0, time.UTC)
+	var ok, failed int64
+	var alerts []burnAlert
+	minute := 0
+	for _, stretch := range traffic {
+		for i := 0; i < stretch.minutes; i++ {
+			ok, failed = ok+stretch.ok, failed+stretch.failed
+			counters := map[string]int64{
+				metricKey("wolfy_stage_results_total", "stage", stage, "result", "ok"):    ok,
+				metricKey("wolfy_stage_results_total", "stage", stage, "result", "error"): failed,
+			}
+			for _, text := range detector.evaluate(clock.Add(time.Duration(minute)*time.Minute), counters, thresholds) {
+				alerts = append(alerts, burnAlert{minute, text})
+			}
+			minute++
+		}
+	}
+	return alerts
+}
+
+// Global function for reducing alerts to their minute and severity, for comparison
+func burnAlertKinds(alerts []burnAlert) []string {
+	var kinds []string
+	for _, alert := range alerts {
+		kind := "?"
+		for _, severity := range []string{"*" + burnSeverityWarning + "*", "*" + burnSeverityCritical + "*", "*Resolved*"} {
+			if strings.Contains(alert.text, severity) {
+				kind = strings.Trim(severity, "*")
+			}
+		}
+		kinds = append(kinds, time.Duration(alert.minute*int(time.Minute)).String()+" "+kind)
+	}
+	return kinds
+}
+
+// Test for a burst of errors against a steady 1% baseline raising an alert within a tick, escalating while it lasts,
+// and resolving once the short window has moved past it, with one alert per change of severity
+func TestBurnRateAlertAndResolve(t *testing.T) {
+	withConfig(t, func(c *Config) {
+		c.BurnRateShortWindow = 5 * time.Minute
+		c.BurnRateLongWindow = time.Hour
+		c.BurnRateMinEvents = 10
+	})
+	alerts := replayBurnTraffic("answer", 3, []burnTraffic{
+		{minutes: 61, ok: 99, failed: 1},
+		{minutes: 2, ok: 70, failed: 30},
+		{minutes: 20, ok: 99, failed: 1},
+	})
+	want := []string{"1h1m0s warning", "1h2m0s critical", "1h6m0s warning", "1h7m0s Resolved"}
+	if got := burnAlertKinds(alerts); !reflect.DeepEqual(got, want) {
+		t.Fatalf("alerts raised = %v; want %v", got, want)
+	}
+	if first := alerts[0].text; !strings.Contains(first, "Answering errors are at") || !strings.Contains(first, "over the last 5m0s") {
+		t.Errorf("first alert = %q; want the stage and short window named", first)
+	}
+	if last := alerts[len(alerts)-1].text; !strings.Contains(last, "back under 3.0x the 1h0m0s baseline") {
+		t.Errorf("resolving alert = %q; want the threshold and baseline named", last)
+	}
+
+	// A spotless baseline is floored rather than dividing by zero
+	if got := burnAlertKinds(replayBurnTraffic("answer", 3, []burnTraffic{{minutes: 61, ok: 100}, {minutes: 1, ok: 50, failed: 50}})); !reflect.DeepEqual(got, []string{"1h1m0s critical"}) {
+		t.Errorf("alerts for a burst against a spotless baseline = %v; want [1h1m0s critical]", got)
+	}
+}
+
+// Test for a quiet stage failing half of too few events staying silent under the WOLFY_BURN_RATE_MIN_EVENTS floor, and
+// alerting once the floor is lowered to its traffic, resolving as soon as the short window falls back under the floor
+func TestBurnRateMinEventsFloor(t *testing.T) {
+	quiet := []burnTraffic{{minutes: 61, ok: 1}, {minutes: 10, ok: 1, failed: 1}, {minutes: 10, ok: 1}}
+	cases := []struct {
+		floor string
+		want  []string
+	}{
+		{"11", nil},
+		{"10", []string{"1h5m0s critical", "1h9m0s warning", "1h11m0s Resolved"}},
+	}
+	for _, c := range cases {
+		t.Setenv("WOLFY_BURN_RATE_MIN_EVENTS", c.floor)
+		withConfig(t, func(cfg *Config) {
+			cfg.BurnRateShortWindow = 5 * time.Minute
+			cfg.BurnRateLongWindow = time.Hour
+			cfg.BurnRateMinEvents = getEnvInt("WOLFY_BURN_RATE_MIN_EVENTS", 10)
+		})
+		if got := burnAlertKinds(replayBurnTraffic("post", 2, quiet)); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("alerts with a floor of %s events = %v; want %v", c.floor, got, c.want)
+		}
+	}
+}
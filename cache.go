@@ -0,0 +1,265 @@
+//////////////////////////////////////////////////
+// Answer Cache Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for caching Wolfram answers by normalized query
+import (
+	"context" // Permits carrying the question's intent to the cache
+	"fmt"     // Permits formatting of admin output
+	"log"     // Permits console logging
+	"regexp"  // Permits query normalization
+	"strings" // Permits string normalization
+	"time"    // Permits entry expiry and ages
+
+	wolfram "github.com/Krognol/go-wolfram" // External Wolfram API
+	slack "github.com/nlopes/slack"         // External Slack API
+)
+
+// Global struct holding a cached answer
+type cachedAnswer struct {
+	Query    string
+	Answer   string
+	StoredAt time.Time
+	TTL      time.Duration
+}
+
+// Global context key type for the intent a question was classified as
+type cacheIntentKey struct{}
+
+// Global struct pairing a topic whose answers change quickly with the pattern recognizing it in a query
+type volatileTopic struct {
+	intent  string
+	pattern *regexp.Regexp
+}
+
+// Global topics treated as their own intents for caching, since Wolfram answers them under the generic search intent
+var volatileTopics = []volatileTopic{
+	{"weather", regexp.MustCompile(`(?i)\b(?:weather|forecast|humidity|rain(?:ing)?|snow(?:ing)?|wind\s+speed|temperature\s+(?:in|at|outside))\b`)},
+	{"currency", regexp.MustCompile(`(?i)\b(?:exchange\s+rate|currency|bitcoin|btc|ethereum|(?:usd|eur|gbp|jpy|cad|aud|inr|cny)\s+(?:to|in)\b)`)},
+	{"stocks", regexp.MustCompile(`(?i)\b(?:stocks?|share\s+price|market\s+cap|nasdaq|dow\s+jones|s&p\s*500)\b`)},
+	{"time", regexp.MustCompile(`(?i)\b(?:time\s+(?:is\s+it\s+)?(?:in|at)|current\s+time|right\s+now|today|tonight|sunrise|sunset)\b`)},
+}
+
+// Global default cache TTLs for the volatile topics ("0" leaves them uncached), overridable by WOLFY_ANSWER_CACHE_INTENTS
+var defaultIntentCacheTTLs = map[string]string{"weather": "10m", "currency": "5m", "stocks": "0", "time": "0"}
+
+// Global interface implemented by every answer cache backend
+type answerCache interface {
+	lookup(key string) (cachedAnswer, bool)
+	store(key string, answer cachedAnswer, aliases []string)
+	evict(key string) []string
+}
+
+// Global struct holding a bounded in-memory cache whose fuzzy aliases point at canonical entries, evicting the oldest
+// stored entry beyond capacity (and the aliases pointing at it along with it)
+type memoryAnswerCache struct {
+	entries *boundedCache
+	aliases *boundedCache
+}
+
+// Global patterns stripping punctuation, leading question phrases, and articles when normalizing queries
+var (
+	cachePunctuationPattern = regexp.MustCompile(`[^\p{L}\p{N}\s.+\-*/^%]+|\.+$`)
+	cacheLeadPattern        = regexp.MustCompile(`^(?:what\s+is|what's|whats|what\s+are|who\s+is|who's|how\s+much\s+is|tell\s+me)\s+`)
+	cacheArticlePattern     = regexp.MustCompile(`\b(?:the|a|an)\s+`)
+)
+
+// Global answer cache shared by the Wolfram handler and the admin commands (nil when caching is disabled)
+var answers answerCache
+
+// Global function for creating the configured answer cache backend, when caching is on by default or for any intent
+func openAnswerCache() {
+	enabled := config.AnswerCacheTTL > 0
+	for intent := range config.AnswerCacheIntents {
+		if ttl, ok := intentCacheTTL(intent); ok && ttl > 0 {
+			enabled = true
+		}
+	}
+	if !enabled {
+		return
+	}
+	answers = newMemoryAnswerCache()
+}
+
+// Global function for creating the in-memory answer cache, each entry expiring after its own TTL (else the default one)
+func newMemoryAnswerCache() *memoryAnswerCache {
+	cache := &memoryAnswerCache{aliases: newBoundedCache("answer_aliases", func() int { return config.AnswerCacheSize }, nil)}
+	cache.entries = newBoundedCache("answers", func() int { return config.AnswerCacheSize }, func(value interface{}) time.Duration {
+		if ttl := value.(cachedAnswer).TTL; ttl > 0 {
+			return ttl
+		}
+		return config.AnswerCacheTTL
+	}).evictedBy(func(key string, value interface{}) { cache.removeAliases(key) })
+	return cache
+}
+
+// Global function for recording a question's Wit.ai intent so the cache can apply that intent's settings
+func withCacheIntent(ctx context.Context, entityKey string) context.Context {
+	return context.WithValue(ctx, cacheIntentKey{}, entityKey)
+}
+
+// Global function for choosing the intent whose cache settings govern a query: a volatile topic it mentions, else its Wit.ai intent
+func cacheIntentFor(ctx context.Context, query string) string {
+	for _, topic := range volatileTopics {
+		if topic.pattern.MatchString(query) {
+			return topic.intent
+		}
+	}
+	intent, _ := ctx.Value(cacheIntentKey{}).(string)
+	return intent
+}
+
+// Global function for resolving an intent's cache TTL: its WOLFY_ANSWER_CACHE_INTENTS entry, else its built-in default,
+// else WOLFY_ANSWER_CACHE_TTL (a TTL of 0 means the intent isn't cached)
+func intentCacheTTL(intent string) (time.Duration, bool) {
+	setting, ok := config.AnswerCacheIntents[intent]
+	if !ok {
+		if setting, ok = defaultIntentCacheTTLs[intent]; !ok {
+			return config.AnswerCacheTTL, true
+		}
+	}
+	if strings.EqualFold(setting, "off") {
+		return 0, true
+	}
+	ttl, err := time.ParseDuration(setting)
+	if err != nil || ttl < 0 {
+		log.Printf("CONFIG ERROR: Ignoring invalid cache TTL %q for intent %s.", setting, intent)
+		return config.AnswerCacheTTL, false
+	}
+	return ttl, true
+}
+
+// Global function for normalizing a query into its canonical cache key (case, spacing, and punctuation insensitive)
+func normalizeCacheQuery(query string) string {
+	query = cachePunctuationPattern.ReplaceAllString(strings.ToLower(query), "")
+	return strings.Join(strings.Fields(query), " ")
+}
+
+// Global function for loosening a normalized query for fuzzy matching ("what is the population of france" -> "population of france")
+func fuzzyCacheQuery(normalized string) string {
+	return strings.TrimSpace(cacheArticlePattern.ReplaceAllString(cacheLeadPattern.ReplaceAllString(normalized, ""), ""))
+}
+
+// Global function for building the cache key for a query in a unit system
+func answerCacheKey(query string, units wolfram.Unit) string {
+	return unitsName(units) + ":" + normalizeCacheQuery(query)
+}
+
+// Global function for building the fuzzy alias key for a query in a unit system
+func answerCacheAlias(query string, units wolfram.Unit) string {
+	return unitsName(units) + ":" + fuzzyCacheQuery(normalizeCacheQuery(query))
+}
+
+// Global function for finding a cached answer for a query by its exact normalized form, then its fuzzy alias, skipping
+// the cache entirely for intents it's turned off for
+func cachedAnswerFor(ctx context.Context, query string, units wolfram.Unit) (cachedAnswer, bool) {
+	if answers == nil {
+		return cachedAnswer{}, false
+	}
+	if ttl, _ := intentCacheTTL(cacheIntentFor(ctx, query)); ttl <= 0 {
+		return cachedAnswer{}, false
+	}
+	if entry, ok := answers.lookup(answerCacheKey(query, units)); ok {
+		return entry, true
+	}
+	return answers.lookup(answerCacheAlias(query, units))
+}
+
+// Global function for caching a Wolfram answer under its normalized query, with its fuzzy alias pointing at it, for as
+// long as the query's intent allows (not at all when its TTL is 0)
+func cacheAnswer(ctx context.Context, query string, units wolfram.Unit, answer string) {
+	intent := cacheIntentFor(ctx, query)
+	ttl, _ := intentCacheTTL(intent)
+	if ttl <= 0 {
+		traceStep(ctx, "not caching: caching is off for intent %q", intent)
+		return
+	}
+	ttl = timeSensitiveCacheTTL(query, ttl)
+	answers.store(answerCacheKey(query, units), cachedAnswer{Query: query, Answer: answer, StoredAt: time.Now(), TTL: ttl}, []string{answerCacheAlias(query, units)})
+}
+
+// Method for finding a fresh entry by canonical key, or through a fuzzy alias pointing at one
+func (c *memoryAnswerCache) lookup(key string) (cachedAnswer, bool) {
+	entry, ok := c.entries.get(c.canonicalKey(key))
+	if !ok {
+		return cachedAnswer{}, false
+	}
+	return entry.(cachedAnswer), true
+}
+
+// Method for storing an entry and its aliases
+func (c *memoryAnswerCache) store(key string, answer cachedAnswer, aliases []string) {
+	c.entries.put(key, answer)
+	for _, alias := range aliases {
+		if alias != key {
+			c.aliases.put(alias, key)
+		}
+	}
+}
+
+// Method for evicting an entry (found by key or alias) along with every alias pointing at it, returning the keys removed
+func (c *memoryAnswerCache) evict(key string) []string {
+	key = c.canonicalKey(key)
+	if !c.entries.remove(key) {
+		return nil
+	}
+	return append([]string{key}, c.removeAliases(key)...)
+}
+
+// Method for resolving a key to the entry it names: itself when cached under it, else the entry its alias points at
+func (c *memoryAnswerCache) canonicalKey(key string) string {
+	if canonical, ok := c.aliases.get(key); ok {
+		if _, exact := c.entries.get(key); !exact {
+			return canonical.(string)
+		}
+	}
+	return key
+}
+
+// Method for removing the aliases pointing at an entry, returning them
+func (c *memoryAnswerCache) removeAliases(key string) []string {
+	return c.aliases.removeMatching(func(alias string, canonical interface{}) bool { return canonical.(string) == key })
+}
+
+// Admin command showing or evicting the cached answers for a query (in both unit systems)
+func runAdminCache(event *slack.MessageEvent, args []string) string {
+	usage := "Usage: `!cache lookup <query>` or `!cache evict <query>`"
+	if len(args) < 2 {
+		return usage
+	}
+	if answers == nil {
+		return "The answer cache is disabled (set `WOLFY_ANSWER_CACHE_TTL`)."
+	}
+	query := strings.Join(args[1:], " ")
+
+	var lines []string
+	for _, units := range []wolfram.Unit{wolfram.Metric, wolfram.Imperial} {
+		key := answerCacheKey(query, units)
+		switch strings.ToLower(args[0]) {
+		case "lookup":
+			if entry, ok := cachedAnswerFor(context.Background(), query, units); ok {
+				age := time.Since(entry.StoredAt).Round(time.Second)
+				lines = append(lines, fmt.Sprintf("`%s` (%s units, asked as \"%s\", cached %s ago): %s", normalizeCacheQuery(entry.Query), unitsName(units), entry.Query, age, entry.Answer))
+			}
+		case "evict":
+			removed := answers.evict(key)
+			if len(removed) == 0 {
+				removed = answers.evict(answerCacheAlias(query, units))
+			}
+			if len(removed) > 0 {
+				log.Printf("ADMIN: %s evicted cached answer %s.", event.User, strings.Join(removed, ", "))
+				lines = append(lines, fmt.Sprintf("Evicted `%s`", strings.Join(removed, "`, `")))
+			}
+		default:
+			return usage
+		}
+	}
+	if len(lines) == 0 {
+		return fmt.Sprintf("Nothing cached for \"%s\" (normalized: `%s`).", query, normalizeCacheQuery(query))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,139 @@
+//////////////////////////////////////////////////
+// Answer Candidates Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for surfacing alternative interpretations when Wolfram finds a query ambiguous
+import (
+	"context"       // Permits deadlines on the candidate lookups
+	"encoding/json" // Permits decoding of the assumptions field
+	"fmt"           // Permits string formatting of candidate lines
+	"log"           // Permits console logging
+	"net/url"       // Permits building of assumption parameters
+	"regexp"        // Permits normalization of answers for comparison
+	"strings"       // Permits joining of candidate lines
+
+	wolfram "github.com/Krognol/go-wolfram" // External Wolfram API
+)
+
+// Global struct holding one Wolfram assumption (e.g. a "Clash" over what a word means) and its alternatives, most likely first
+type wolframAssumption struct {
+	Type   string `json:"type"`
+	Word   string `json:"word"`
+	Values []struct {
+		Name  string `json:"name"`
+		Desc  string `json:"desc"`
+		Input string `json:"input"`
+	} `json:"values"`
+}
+
+// Global pattern stripping what doesn't change an answer's meaning (case aside) before answers are compared: hedges like
+// "about", punctuation, and spacing
+var answerNoisePattern = regexp.MustCompile(`(?i)\b(?:about|approximately|approx|roughly|around)\b|[≈~\s]+|[^\p{L}\p{N}.%/^-]+|\.$`)
+
+// Registering the opt-in candidate answers (each alternative costs an extra full results call)
+func init() {
+	registerFeatureFlag(featureFlag{name: "answer_candidates", description: "Show the top answers for each reading of an ambiguous question (\"pi\" the constant vs. the movie)."})
+}
+
+// Method for decoding the assumptions, which Wolfram sends as a single object or an array
+func (result *fullResult) assumptions() []wolframAssumption {
+	if len(result.Assumptions) == 0 {
+		return nil
+	}
+	var list []wolframAssumption
+	if err := json.Unmarshal(result.Assumptions, &list); err == nil {
+		return list
+	}
+	var single wolframAssumption
+	if err := json.Unmarshal(result.Assumptions, &single); err == nil {
+		return []wolframAssumption{single}
+	}
+	return nil
+}
+
+// Method for picking the answer text of a full result: the primary pod, else the "Result" pod
+func (result *fullResult) primaryText() string {
+	for _, pod := range result.Pods {
+		if pod.Primary && pod.plaintext() != "" {
+			return pod.plaintext()
+		}
+	}
+	for _, pod := range result.Pods {
+		if pod.ID == "Result" {
+			return pod.plaintext()
+		}
+	}
+	return ""
+}
+
+// Global function for normalizing an answer for comparison, so "≈ 3.14159" and "about 3.14159." compare equal
+func normalizeAnswer(answer string) string {
+	answer = strings.Replace(strings.ToLower(strings.TrimSpace(answer)), "\u2212", "-", -1)
+	return answerNoisePattern.ReplaceAllString(answer, "")
+}
+
+// Global function for building full results parameters in the asker's units, optionally forcing an assumption
+func candidateParams(units wolfram.Unit, assumption string) url.Values {
+	params := url.Values{"format": {"plaintext"}, "units": {unitsName(units)}}
+	if assumption != "" {
+		params.Set("assumption", assumption)
+	}
+	return params
+}
+
+// Global function for listing up to config.AnswerCandidates answers when Wolfram reports competing readings of a
+// query, reporting false (keep the single short answer) when the query is unambiguous or alternatives can't be fetched
+func answerCandidates(ctx context.Context, client *wolfram.Client, query string, units wolfram.Unit, shortAnswer string) (string, bool) {
+	if config.AnswerCandidates < 2 {
+		return "", false
+	}
+	result, err := fetchFullResult(ctx, client, query, candidateParams(units, ""))
+	if err != nil {
+		log.Printf("ERROR: Unable to check %q for alternative interpretations. Error Msg: %v", query, err)
+		return "", false
+	}
+
+	for _, assumption := range result.assumptions() {
+		if assumption.Type != "Clash" || len(assumption.Values) < 2 {
+			continue
+		}
+
+		lines := []string{fmt.Sprintf("*\"%s\" could mean a few things:*", assumption.Word)}
+		seen := map[string]bool{}
+		for i, value := range assumption.Values {
+			if len(lines)-1 >= config.AnswerCandidates {
+				break
+			}
+			// The short answer already reflects Wolfram's default (first) reading
+			answer := shortAnswer
+			if i > 0 {
+				alternative, err := fetchFullResult(ctx, client, query, candidateParams(units, value.Input))
+				if err != nil {
+					log.Printf("ERROR: Unable to fetch the %q reading of %q. Error Msg: %v", value.Desc, query, err)
+					continue
+				}
+				answer = alternative.primaryText()
+			}
+			if answer == "" {
+				continue
+			}
+			// Keeping only the first (most likely) reading's phrasing of an answer several readings share
+			normalized := normalizeAnswer(answer)
+			if config.AnswerDedup && seen[normalized] {
+				metrics.inc("wolfy_duplicate_answers_total")
+				continue
+			}
+			seen[normalized] = true
+			lines = append(lines, fmt.Sprintf("• _%s:_ %s", value.Desc, strings.Replace(answer, "\n", "; ", -1)))
+		}
+		if len(lines) > 2 {
+			metrics.inc("wolfy_answer_candidates_total")
+			return strings.Join(lines, "\n"), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,78 @@
+//////////////////////////////////////////////////
+// Capabilities Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for describing what the bot can currently do
+import (
+	"fmt"     // Permits string formatting of the capability list
+	"regexp"  // Permits matching of capability questions
+	"strings" // Permits joining of capability lines
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global struct describing a built-in command that is not a Wit.ai entity handler
+type capability struct {
+	name        string
+	description string
+	enabled     func() bool
+}
+
+// Global list of built-in commands, registered by the files implementing them
+var capabilities []capability
+
+// Global pattern recognizing "what can you do?" style questions
+var capabilitiesPattern = regexp.MustCompile(`(?i)^\s*(what\s+(can|do)\s+you\s+do|what\s+are\s+your\s+(capabilities|skills|features)|help|how\s+do\s+i\s+use\s+you)\s*[?.!]*\s*$`)
+
+// Global function for adding a built-in command to the capability list (enabled may be nil for always-on commands)
+func registerCapability(name string, description string, enabled func() bool) {
+	capabilities = append(capabilities, capability{name: name, description: description, enabled: enabled})
+}
+
+// Global function for listing everything currently enabled, straight from the registries, in the channel's language
+// where its catalog translates the descriptions
+func describeCapabilities(channel string) string {
+	language := channelLanguage(channel)
+	describe := func(name string, description string) string {
+		if localized, ok := localizedMessage(language, "capability."+name); ok {
+			description = localized
+		}
+		return fmt.Sprintf("• *%s* - %s", name, description)
+	}
+
+	intro := "Here's what I can do right now:"
+	if localized, ok := localizedMessage(language, capabilitiesIntroKey); ok {
+		intro = localized
+	}
+	lines := []string{intro}
+	for _, name := range entityHandlerNames() {
+		if handlerEnabled(name) {
+			lines = append(lines, describe(name, entityHandlers[name].description))
+		}
+	}
+	for _, local := range localAnswerers {
+		if handlerEnabled(local.name) {
+			lines = append(lines, describe(local.name, local.description))
+		}
+	}
+	for _, builtin := range capabilities {
+		if (builtin.enabled == nil || builtin.enabled()) && handlerEnabled(builtin.name) {
+			lines = append(lines, describe(builtin.name, builtin.description))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Global function for replying with the capability list, reporting whether the message was consumed
+func handleCapabilities(event *slack.MessageEvent) bool {
+	if !capabilitiesPattern.MatchString(event.Msg.Text) {
+		return false
+	}
+	metrics.inc("wolfy_intents_total", "intent", "capabilities")
+	postText(event.User, describeCapabilities(event.Channel))
+	return true
+}
@@ -0,0 +1,342 @@
+//////////////////////////////////////////////////
+// Catalog Validation Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for checking message catalogs and personality packs, at startup, on reload, and from the command line
+import (
+	"bytes"         // Permits locating of keys in catalog files
+	"encoding/json" // Permits decoding of catalog and pack files
+	"flag"          // Permits parsing of the validate command's flags
+	"fmt"           // Permits formatting of problems and the report
+	"io/ioutil"     // Permits reading of catalog and pack files
+	"log"           // Permits console logging
+	"os"            // Permits checking of catalog paths
+	"path/filepath" // Permits listing of catalog and pack directories
+	"sort"          // Permits stable ordering of problems
+	"strings"       // Permits template checks and joining of the report
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global struct holding a problem found in a catalog or pack file, at the line and column it starts (0 when unknown)
+type catalogProblem struct {
+	path    string
+	line    int
+	column  int
+	message string
+}
+
+// Method for writing a problem as "path:line:column: message", the way compilers do
+func (problem catalogProblem) String() string {
+	if problem.line == 0 {
+		return fmt.Sprintf("%s: %s", problem.path, problem.message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", problem.path, problem.line, problem.column, problem.message)
+}
+
+// Global sample values for each message variable, and the messages each is filled in for (elsewhere it would be posted
+// as written)
+var (
+	messageVariableSamples = map[string]string{"name": "Ada"}
+	messageVariableKeys    = map[string][]string{"name": {"greeting", "welcome_intro", "acknowledgment"}}
+)
+
+// Global function for turning a byte offset into a file into its 1-based line and column
+func catalogPosition(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	return line, len(before) - bytes.LastIndexByte(before, '\n')
+}
+
+// Global function for finding where a key is defined in a file, for reporting a problem with its value
+func catalogKeyPosition(data []byte, key string) (int, int) {
+	quoted, _ := json.Marshal(key)
+	offset := bytes.Index(data, quoted)
+	if offset < 0 {
+		return 0, 0
+	}
+	return catalogPosition(data, int64(offset))
+}
+
+// Global function for reading and decoding a catalog or pack file, reporting a syntax or type error where it happened
+func decodeCatalogFile(path string, target interface{}) ([]byte, []catalogProblem) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, []catalogProblem{{path: path, message: fmt.Sprintf("unable to read: %v", err)}}
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		problem := catalogProblem{path: path, message: fmt.Sprintf("invalid JSON: %v", err)}
+		switch err := err.(type) {
+		case *json.SyntaxError:
+			problem.line, problem.column = catalogPosition(data, err.Offset)
+		case *json.UnmarshalTypeError:
+			problem.line, problem.column = catalogPosition(data, err.Offset)
+		}
+		return data, []catalogProblem{problem}
+	}
+	return data, nil
+}
+
+// Global function for checking a message's template: braces must pair up, every variable must be one that is filled
+// in for the key, and the message rendered with sample values must not come out empty
+func checkMessageTemplate(path string, data []byte, key string, text string) []catalogProblem {
+	line, column := catalogKeyPosition(data, key)
+	problem := func(format string, args ...interface{}) catalogProblem {
+		return catalogProblem{path: path, line: line, column: column, message: fmt.Sprintf("%q: ", key) + fmt.Sprintf(format, args...)}
+	}
+	var problems []catalogProblem
+
+	depth := 0
+	for _, char := range text {
+		switch char {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		}
+		if depth < 0 || depth > 1 {
+			problems = append(problems, problem("unbalanced braces"))
+			break
+		}
+	}
+	if depth != 0 && len(problems) == 0 {
+		problems = append(problems, problem("unclosed {"))
+	}
+
+	for _, match := range messageVariablePattern.FindAllStringSubmatch(text, -1) {
+		variable := match[2]
+		if _, ok := messageVariableSamples[variable]; !ok {
+			problems = append(problems, problem("unknown variable {%s}", variable))
+			continue
+		}
+		filled := false
+		for _, filledKey := range messageVariableKeys[variable] {
+			filled = filled || filledKey == key
+		}
+		if !filled {
+			problems = append(problems, problem("{%s} is never filled in for this message, so it would be posted as written", variable))
+		}
+	}
+	if strings.TrimSpace(renderMessageVariables(text, messageVariableSamples)) == "" {
+		problems = append(problems, problem("renders empty"))
+	}
+	return problems
+}
+
+// Global function for reading a message overrides file ({"key": "text"}), returning the overrides that passed and the
+// problems with the rest
+func readMessageOverrides(path string) (map[string]string, []catalogProblem) {
+	var raw map[string]string
+	data, problems := decodeCatalogFile(path, &raw)
+	if problems != nil {
+		return nil, problems
+	}
+
+	known := map[string]bool{}
+	for _, key := range append(requiredMessageKeys, optionalMessageKeys...) {
+		known[key] = true
+	}
+	overrides := map[string]string{}
+	for key, text := range raw {
+		if !known[key] {
+			line, column := catalogKeyPosition(data, key)
+			problems = append(problems, catalogProblem{path: path, line: line, column: column, message: fmt.Sprintf("unknown message key %q", key)})
+			continue
+		}
+		if keyProblems := checkMessageTemplate(path, data, key, text); len(keyProblems) > 0 {
+			problems = append(problems, keyProblems...)
+			continue
+		}
+		overrides[key] = text
+	}
+	return overrides, sortCatalogProblems(problems)
+}
+
+// Global function for reading a "<code>.json" language catalog, returning its language, the messages that passed, and
+// the problems with the rest, including required keys neither it nor the built-in catalog for its language translates
+func readLanguageCatalog(path string) (string, map[string]string, []catalogProblem) {
+	language := strings.TrimSuffix(filepath.Base(path), ".json")
+	var raw map[string]string
+	data, problems := decodeCatalogFile(path, &raw)
+	if problems != nil {
+		return language, nil, problems
+	}
+	if !languageCodePattern.MatchString(language) {
+		return language, nil, []catalogProblem{{path: path, message: fmt.Sprintf("%q is not a language code like fr or pt-BR", language)}}
+	}
+
+	messages := map[string]string{}
+	for key, text := range raw {
+		if !isLanguageCatalogKey(key) {
+			line, column := catalogKeyPosition(data, key)
+			problems = append(problems, catalogProblem{path: path, line: line, column: column, message: fmt.Sprintf("unknown message key %q", key)})
+			continue
+		}
+		if keyProblems := checkMessageTemplate(path, data, key, text); len(keyProblems) > 0 {
+			problems = append(problems, keyProblems...)
+			continue
+		}
+		messages[key] = text
+	}
+	var missing []string
+	for _, key := range requiredMessageKeys {
+		if messages[key] == "" && builtinLanguageCatalogs[language][key] == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		problems = append(problems, catalogProblem{path: path, message: "missing required messages " + strings.Join(missing, ", ") + " (they'll be posted in English)"})
+	}
+	return language, messages, sortCatalogProblems(problems)
+}
+
+// Global function for checking a personality pack's keys and templates, returning its problems (a pack with any is
+// not loaded)
+func checkPersonalityPack(path string, data []byte, pack personalityPack) []catalogProblem {
+	var problems []catalogProblem
+	if missing := missingPackKeys(pack); len(missing) > 0 {
+		problems = append(problems, catalogProblem{path: path, message: "missing " + strings.Join(missing, ", ")})
+	}
+	for key, text := range pack.Messages {
+		problems = append(problems, checkMessageTemplate(path, data, key, text)...)
+	}
+	return sortCatalogProblems(problems)
+}
+
+// Global function for reading a personality pack file, named by its file when it doesn't name itself
+func readPersonalityPack(path string) (personalityPack, []catalogProblem) {
+	var pack personalityPack
+	data, problems := decodeCatalogFile(path, &pack)
+	if problems != nil {
+		return pack, problems
+	}
+	if pack.Name == "" {
+		pack.Name = strings.TrimSuffix(filepath.Base(path), ".json")
+	}
+	pack.Name = strings.ToLower(pack.Name)
+	return pack, checkPersonalityPack(path, data, pack)
+}
+
+// Global function for ordering problems by file and position, so reports read top to bottom
+func sortCatalogProblems(problems []catalogProblem) []catalogProblem {
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].path != problems[j].path {
+			return problems[i].path < problems[j].path
+		}
+		if problems[i].line != problems[j].line {
+			return problems[i].line < problems[j].line
+		}
+		return problems[i].message < problems[j].message
+	})
+	return problems
+}
+
+// Global function for logging the problems loading found
+func logCatalogProblems(problems []catalogProblem) {
+	for _, problem := range problems {
+		log.Printf("CONFIG ERROR: %s", problem)
+	}
+}
+
+// Global function for listing the JSON files in a catalog or pack directory
+func catalogFiles(dir string) ([]string, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	return filepath.Glob(filepath.Join(dir, "*.json"))
+}
+
+// Global function for validating messages files or directories of language catalogs and a directory of packs (empty
+// paths are skipped), returning how many files were checked and every problem found, by the same checks loading applies
+func validateCatalogs(messagesPaths []string, packsDir string) (int, []catalogProblem) {
+	checked := 0
+	var problems []catalogProblem
+
+	for _, messagesPath := range messagesPaths {
+		if messagesPath == "" {
+			continue
+		}
+		if info, err := os.Stat(messagesPath); err == nil && !info.IsDir() {
+			_, fileProblems := readMessageOverrides(messagesPath)
+			checked, problems = checked+1, append(problems, fileProblems...)
+		} else if paths, err := catalogFiles(messagesPath); err != nil {
+			problems = append(problems, catalogProblem{path: messagesPath, message: err.Error()})
+		} else {
+			for _, path := range paths {
+				_, _, fileProblems := readLanguageCatalog(path)
+				checked, problems = checked+1, append(problems, fileProblems...)
+			}
+		}
+	}
+	if packsDir != "" {
+		if paths, err := catalogFiles(packsDir); err != nil {
+			problems = append(problems, catalogProblem{path: packsDir, message: err.Error()})
+		} else {
+			for _, path := range paths {
+				_, fileProblems := readPersonalityPack(path)
+				checked, problems = checked+1, append(problems, fileProblems...)
+			}
+		}
+	}
+	return checked, problems
+}
+
+// Global function for running "wolfybot validate --messages <file or dir> --packs <dir>", printing every problem and
+// returning the exit status (non-zero on any problem). Paths not given default to the configured ones.
+func runValidateCommand(args []string) int {
+	config = loadConfig()
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	messagesPath := flags.String("messages", "", "message overrides file, or directory of <code>.json language catalogs (default WOLFY_MESSAGES_FILE, then WOLFY_LANGUAGES_DIR)")
+	packsDir := flags.String("packs", config.PersonalityDir, "directory of personality pack files (default WOLFY_PERSONALITY_DIR)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	messagesPaths := []string{*messagesPath}
+	if *messagesPath == "" {
+		messagesPaths = []string{config.MessagesFile, config.LanguagesDir}
+	}
+	checked, problems := validateCatalogs(messagesPaths, *packsDir)
+	if checked == 0 && len(problems) == 0 {
+		fmt.Fprintln(os.Stderr, "Nothing to validate: pass --messages and/or --packs.")
+		return 2
+	}
+
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+	if len(problems) > 0 {
+		fmt.Printf("%d problem(s) in %d file(s) checked.\n", len(problems), checked)
+		return 1
+	}
+	fmt.Printf("OK: %d file(s) checked, no problems.\n", checked)
+	return 0
+}
+
+// Global function for re-reading the messages file, language catalogs, and personality packs with the checks startup
+// applies, for "!reload messages", reporting what was left out
+func reloadMessageCatalogs(event *slack.MessageEvent) string {
+	problems := append(append(loadPersonalityPacks(), loadMessageCatalog()...), loadLanguageCatalogs()...)
+	metrics.inc("wolfy_message_reloads_total")
+	log.Printf("ADMIN: %s reloaded message catalogs and personality packs (%d problems).", event.User, len(problems))
+	if len(problems) == 0 {
+		return "Message catalogs and personality packs reloaded - no problems."
+	}
+	lines := make([]string, 0, len(problems))
+	for _, problem := range problems {
+		lines = append(lines, problem.String())
+	}
+	return "Message catalogs and personality packs reloaded, leaving out what failed validation:\n• " + strings.Join(lines, "\n• ")
+}
@@ -0,0 +1,164 @@
+//////////////////////////////////////////////////
+// Channel Cleanup Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for dropping per-channel state once the bot leaves a channel or it is archived
+import (
+	"errors"  // Permits the error returned for posts to channels known to be gone
+	"fmt"     // Permits formatting of the admin channel note
+	"log"     // Permits console logging
+	"strings" // Permits channel ID type detection
+	"sync"    // Permits concurrency-safe access to the gone channels
+	"time"    // Permits expiry of gone channels
+)
+
+// Global constant holding how long a channel Slack said was not found is skipped before posts to it are tried again
+const channelGoneTTL = 10 * time.Minute
+
+// Global constant naming the store bucket of channels the bot was removed from, skipped until it is invited back
+const inactiveChannelsBucket = "inactive_channels"
+
+// Global phrases describing why a channel's state was purged, for the admin channel note
+var channelCleanupReasons = map[string]string{
+	"left":      "was left",
+	"removed":   "removed me",
+	"not_found": "was not found",
+	"archived":  "was archived",
+	"deleted":   "was deleted",
+}
+
+// Global struct recording when and why a channel went inactive
+type inactiveChannel struct {
+	Since  time.Time
+	Reason string
+}
+
+// Global error returned for posts skipped because their channel is gone, matching Slack's own
+var errChannelGone = errors.New("channel_not_found")
+
+// Global channels Slack reported as not found (the bot was removed, or it was deleted), with when
+var (
+	goneChannels   = map[string]time.Time{}
+	goneChannelsMu sync.Mutex
+)
+
+// Global function for checking whether a post failed because the bot can't see or isn't in the channel any more
+func isChannelGoneError(err error) bool {
+	return err != nil && (err.Error() == "channel_not_found" || err.Error() == "not_in_channel")
+}
+
+// Global function for checking whether the bot was removed from a channel and hasn't been invited back
+func channelInactive(channel string) bool {
+	var inactive inactiveChannel
+	return store != nil && store.get(inactiveChannelsBucket, channel, &inactive)
+}
+
+// Global function for checking whether posts to a channel are being skipped, because the bot was removed from it or
+// Slack recently said it was gone
+func channelGone(channel string) bool {
+	if channelInactive(channel) {
+		return true
+	}
+	goneChannelsMu.Lock()
+	defer goneChannelsMu.Unlock()
+	at, ok := goneChannels[channel]
+	if ok && time.Since(at) >= channelGoneTTL {
+		delete(goneChannels, channel)
+		return false
+	}
+	return ok
+}
+
+// Global function for noting that a post failed because its channel is gone: not_in_channel means the bot was removed,
+// so the channel is retired until it is invited back, while channel_not_found only skips it for a while; either purges
+// its runtime state the first time (DMs and user IDs are left alone, since those are the fallback)
+func noteChannelGone(channel string, err error) {
+	if !strings.HasPrefix(channel, "C") && !strings.HasPrefix(channel, "G") {
+		return
+	}
+	if err.Error() == "not_in_channel" {
+		if markChannelInactive(channel, "removed") {
+			log.Printf("SLACK ERROR: Not in channel %s any more; skipping posts to it until invited back.", channel)
+			go purgeChannelState(channel, "removed", false)
+		}
+		return
+	}
+	goneChannelsMu.Lock()
+	_, known := goneChannels[channel]
+	goneChannels[channel] = time.Now()
+	goneChannelsMu.Unlock()
+	if !known {
+		log.Printf("SLACK ERROR: Channel %s was not found; skipping posts to it for %s.", channel, channelGoneTTL)
+		go purgeChannelState(channel, "not_found", false)
+	}
+}
+
+// Global function for marking a channel inactive, reporting whether it was active until now (so concurrent failed
+// posts purge it once)
+func markChannelInactive(channel string, reason string) bool {
+	goneChannelsMu.Lock()
+	defer goneChannelsMu.Unlock()
+	if channelInactive(channel) {
+		return false
+	}
+	if err := store.put(inactiveChannelsBucket, channel, inactiveChannel{Since: time.Now(), Reason: reason}); err != nil {
+		log.Printf("CHANNEL CLEANUP ERROR: Unable to mark channel %s inactive.\nError Details: %v", channel, err)
+	}
+	return true
+}
+
+// Global function for retiring a channel the bot left or was removed from, or that was archived or deleted: marking
+// it inactive, then purging its state
+func retireChannel(channel string, reason string, archived bool) {
+	if channel == "" {
+		return
+	}
+	markChannelInactive(channel, reason)
+	purgeChannelState(channel, reason, archived)
+}
+
+// Global function for posting to a channel again once the bot is invited back, reactivating it if it was retired
+func reactivateChannel(channel string) {
+	goneChannelsMu.Lock()
+	delete(goneChannels, channel)
+	wasInactive := channelInactive(channel)
+	if wasInactive {
+		store.delete(inactiveChannelsBucket, channel)
+	}
+	goneChannelsMu.Unlock()
+	if wasInactive {
+		metrics.inc("wolfy_channel_reactivations_total")
+		log.Printf("CHANNEL CLEANUP: Invited back to channel %s; posting to it again.", channel)
+	}
+}
+
+// Global function for purging a channel's state: runtime state, queued posts, and the scheduled queries bound for
+// it whenever the bot leaves, and persisted config (flag overrides, the re-invite cooldown) only once the channel is
+// archived or deleted; admins are told what was dropped
+func purgeChannelState(channel string, reason string, archived bool) {
+	if channel == "" {
+		return
+	}
+	posts := dropOutboundPosts(channel)
+	cancelled, moved := cancelChannelQueries(channel)
+	threadCount := forgetChannelThreads(channel)
+	channelSessions := forgetChannelSessions(channel)
+	cached := forgetChannelThreadParents(channel) + forgetChannelAnswers(channel)
+
+	flags := 0
+	if archived {
+		flags = forgetChannelFlags(channel)
+		store.delete(channelIntrosBucket, channel)
+		store.delete(channelLanguagesBucket, channel)
+		store.delete(channelQuietHoursBucket, channel)
+	}
+	metrics.inc("wolfy_channel_cleanups_total", "reason", reason)
+	log.Printf("CHANNEL CLEANUP: Channel %s %s; dropped %d queued posts, %d scheduled queries, %d threads, %d sessions, %d cached messages, and %d flag overrides, and moved %d scheduled queries to DMs.", channel, reason, posts, cancelled, threadCount, channelSessions, cached, flags, moved)
+	if config.AdminChannel != "" && config.AdminChannel != channel {
+		postText(config.AdminChannel, fmt.Sprintf(":door: <#%s> %s: dropped %d queued posts and cancelled %d scheduled queries bound for it.", channel, channelCleanupReasons[reason], posts, cancelled))
+	}
+}
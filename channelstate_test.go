@@ -0,0 +1,129 @@
+//////////////////////////////////////////////////
+// Channel Cleanup Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing the bot's removal from a channel with work still pending for it
+import (
+	"strings" // Permits inspection of posted notes
+	"testing" // Permits Go testing
+	"time"    // Permits due times of scheduled queries
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global function for finding the posts made to a channel so far whose text contains a phrase
+func postsContaining(channel string, phrase string) []fakeSlackCall {
+	var found []fakeSlackCall
+	for _, call := range fakeSlack.callsTo("chat.postMessage") {
+		if call.values.Get("channel") == channel && strings.Contains(call.values.Get("text"), phrase) {
+			found = append(found, call)
+		}
+	}
+	return found
+}
+
+// Test for removal dropping a channel's queued posts (failing the callers waiting on them) and cancelling its
+// thread-bound scheduled queries, held and claimed ones included, then skipping posts until the bot is invited back
+func TestChannelRemovalWithPendingWork(t *testing.T) {
+	const channel, asker = "CREMOVED", "UREMOVED"
+	withConfig(t, func(c *Config) { c.AdminChannel = "CADMIN" })
+	due := time.Now().Add(time.Hour)
+	defer reactivateChannel(channel)
+
+	// Queueing posts behind a worker that hasn't started, as during a burst
+	waiting := make(chan outboundResult, 1)
+	outboundMu.Lock()
+	outboundQueues[channel] = []outboundPost{{options: []slack.MsgOption{messageText("first")}}, {options: []slack.MsgOption{messageText("second")}, result: waiting}}
+	outboundDepth += 2
+	outboundMu.Unlock()
+	defer func() {
+		// Retiring the hand-built queue with anything posted to it after the re-invite, as no worker was started for it
+		outboundMu.Lock()
+		outboundDepth -= int64(len(outboundQueues[channel]))
+		delete(outboundQueues, channel)
+		outboundMu.Unlock()
+	}()
+
+	queries := map[string]scheduledQuery{
+		"sched-thread":  {ID: "sched-thread", User: asker, Channel: channel, ThreadTS: "7000.000001", Query: "weather in paris", Due: due},
+		"sched-held":    {ID: "sched-held", User: asker, Channel: channel, ThreadTS: "7000.000002", Query: "stock price of acme", Due: due, QuietHeld: true},
+		"sched-dm":      {ID: "sched-dm", User: asker, Channel: channel, Query: "distance to the moon", Due: due},
+		"sched-elsewhr": {ID: "sched-elsewhr", User: asker, Channel: "COTHER", ThreadTS: "7000.000003", Query: "time in tokyo", Due: due},
+	}
+	for key, query := range queries {
+		if err := store.put(scheduledQueriesBucket, key, query); err != nil {
+			t.Fatal(err)
+		}
+	}
+	claimed := claimedQuery{Query: scheduledQuery{ID: "sched-claimed", User: asker, Channel: channel, ThreadTS: "7000.000004", Query: "sunset today", Due: time.Now()}, ClaimedAt: time.Now()}
+	if err := store.put(claimedQueriesBucket, "sched-claimed", claimed); err != nil {
+		t.Fatal(err)
+	}
+	defer store.delete(scheduledQueriesBucket, "sched-dm")
+	defer store.delete(scheduledQueriesBucket, "sched-elsewhr")
+
+	dropped := metricValue("wolfy_posts_skipped_total", "reason", "channel_gone")
+	cancelled := metricValue("wolfy_scheduled_queries_cancelled_total", "reason", "channel_inactive")
+	dms, notes := len(postsContaining(asker, "I'm no longer in <#"+channel+">")), len(postsContaining("CADMIN", "<#"+channel+">"))
+	handleIncomingEvent(slack.RTMEvent{Type: "channel_left", Data: &slack.ChannelLeftEvent{Type: "channel_left", Channel: channel}})
+	waitFor(t, "the admin note", func() bool { return len(postsContaining("CADMIN", "<#"+channel+">")) > notes })
+	if !channelInactive(channel) {
+		t.Error("the channel wasn't marked inactive")
+	}
+	if note := postsContaining("CADMIN", "<#"+channel+">")[notes].values.Get("text"); !strings.Contains(note, "dropped 2 queued posts and cancelled 3 scheduled queries") {
+		t.Errorf("admin note = %q; want it to count 2 queued posts and 3 scheduled queries", note)
+	}
+
+	select {
+	case result := <-waiting:
+		if result.err != errChannelGone {
+			t.Errorf("waiting caller's result = %v; want errChannelGone", result.err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the caller waiting on a queued post was never told it was dropped")
+	}
+	outboundMu.Lock()
+	left := len(outboundQueues[channel])
+	outboundMu.Unlock()
+	if left != 0 {
+		t.Errorf("posts still queued for the channel = %d; want 0", left)
+	}
+	if got := metricValue("wolfy_posts_skipped_total", "reason", "channel_gone") - dropped; got != 2 {
+		t.Errorf("queued posts dropped = %d; want 2", got)
+	}
+
+	for key, want := range map[string]bool{"sched-thread": false, "sched-held": false, "sched-dm": true, "sched-elsewhr": true} {
+		var query scheduledQuery
+		if kept := store.get(scheduledQueriesBucket, key, &query); kept != want {
+			t.Errorf("scheduled query %s kept = %v; want %v", key, kept, want)
+		} else if key == "sched-dm" && query.Channel != "" {
+			t.Errorf("scheduled query %s channel = %q; want it moved to the asker's DM", key, query.Channel)
+		}
+	}
+	if store.get(claimedQueriesBucket, "sched-claimed", &claimed) {
+		t.Error("the claimed occurrence bound for the channel was kept")
+	}
+	if got := metricValue("wolfy_scheduled_queries_cancelled_total", "reason", "channel_inactive") - cancelled; got != 3 {
+		t.Errorf("scheduled queries cancelled = %d; want 3", got)
+	}
+	waitFor(t, "the asker's cancellation DM", func() bool { return len(postsContaining(asker, "I'm no longer in <#"+channel+">")) == dms+1 })
+
+	// Skipping posts while the bot is out, then posting again once it is invited back
+	if _, _, err := sendPost(channel, []slack.MsgOption{messageText("are you there?")}); err != errChannelGone {
+		t.Errorf("sendPost to the removed channel error = %v; want errChannelGone", err)
+	}
+	handleMemberJoinedChannel(&slack.MemberJoinedChannelEvent{User: currentBotUserID(), Channel: channel})
+	if channelInactive(channel) {
+		t.Fatal("the channel is still inactive after the bot was invited back")
+	}
+	if _, _, err := sendPost(channel, []slack.MsgOption{messageText("back again")}); err != nil {
+		t.Errorf("sendPost after the re-invite error = %v; want nil", err)
+	}
+	if len(postsContaining(channel, "first")) != 0 || len(postsContaining(channel, "second")) != 0 {
+		t.Error("a post dropped on removal was sent after all")
+	}
+}
@@ -0,0 +1,138 @@
+//////////////////////////////////////////////////
+// Chemistry Answers Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for answering chemistry questions with a formatted list of the compound's or element's properties
+import (
+	"context" // Permits tracing and bounding of the properties lookup
+	"log"     // Permits console logging
+	"net/url" // Permits building of full results parameters
+	"regexp"  // Permits recognition of chemistry questions, pods, formulas, and property rows
+	"strings" // Permits string building and normalization
+
+	wolfram "github.com/Krognol/go-wolfram" // External Wolfram|Alpha API
+	slack "github.com/nlopes/slack"         // External Slack API
+)
+
+// Global constant holding the most property rows listed, so an element's long data sheet stays readable
+const chemistryMaxProperties = 8
+
+// Global patterns recognizing chemistry questions (a chemical property asked for, or a formula like H2O or C6H12O6),
+// the pods holding chemical data, their "property | value" rows, and Wolfram's sub- and superscript notation
+var (
+	chemistryQueryPattern    = regexp.MustCompile(`(?i)\b(?:molar\s+mass|molecular\s+(?:weight|mass|formula|structure)|chemical\s+(?:formula|properties|structure|name)|atomic\s+(?:number|mass|weight|radius)|electronegativity|electron\s+configuration|oxidation\s+states?|boiling\s+point|melting\s+point|(?:properties|formula|compound|element)\s+of)\b`)
+	chemicalFormulaPattern   = regexp.MustCompile(`\b(?:[A-Z][a-z]?\d*){1,}\b`)
+	chemistryPodPattern      = regexp.MustCompile(`(?i)chemical|element|formula|properties|molar|thermodynamic|atomic`)
+	chemistryRowPattern      = regexp.MustCompile(`^\s*([^|]+?)\s*\|\s*(.+?)\s*$`)
+	chemicalSubscriptPattern = regexp.MustCompile(`_\(?(\d+)\)?`)
+	chemicalChargePattern    = regexp.MustCompile(`\^\(?(\d*[+\-\x{2212}])\)?`)
+)
+
+// Global replacers turning digits and charge signs into the Unicode sub- and superscripts Slack shows inline
+var (
+	subscriptDigits   = strings.NewReplacer("0", "₀", "1", "₁", "2", "₂", "3", "₃", "4", "₄", "5", "₅", "6", "₆", "7", "₇", "8", "₈", "9", "₉")
+	superscriptDigits = strings.NewReplacer("0", "⁰", "1", "¹", "2", "²", "3", "³", "4", "⁴", "5", "⁵", "6", "⁶", "7", "⁷", "8", "⁸", "9", "⁹", "+", "⁺", "-", "⁻", "−", "⁻")
+)
+
+// Registering the chemistry formatting flag, on unless rolled back
+func init() {
+	registerFeatureFlag(featureFlag{name: "chemistry_formatting", description: "Answer chemistry questions (\"molar mass of H2O\", \"properties of gold\") with a formatted list of formula, molar mass, and properties.", defaultOn: true})
+}
+
+// Global function for checking whether a question is about chemistry: it asks for a chemical property, or names a
+// formula (a run of element symbols with at least one count, so plain acronyms don't count)
+func isChemistryQuery(query string) bool {
+	if chemistryQueryPattern.MatchString(query) {
+		return true
+	}
+	for _, token := range chemicalFormulaPattern.FindAllString(query, -1) {
+		if strings.ContainsAny(token, "0123456789") {
+			return true
+		}
+	}
+	return false
+}
+
+// Global function for rewriting Wolfram's plaintext chemical notation ("H_2O", "SO_4^(2-)") with Unicode sub- and
+// superscripts, which also keeps Slack from reading the underscores as italics
+func formatChemicalNotation(text string) string {
+	text = chemicalSubscriptPattern.ReplaceAllStringFunc(text, func(match string) string {
+		return subscriptDigits.Replace(chemicalSubscriptPattern.FindStringSubmatch(match)[1])
+	})
+	return chemicalChargePattern.ReplaceAllStringFunc(text, func(match string) string {
+		return superscriptDigits.Replace(chemicalChargePattern.FindStringSubmatch(match)[1])
+	})
+}
+
+// Global function for fetching a chemistry question's interpretation and property rows from the full results API, in
+// the order Wolfram lists them and without repeats, reporting false when no pod holds chemical data
+func fetchChemistryProperties(ctx context.Context, client *wolfram.Client, query string, units wolfram.Unit) (string, [][2]string, bool) {
+	result, err := fetchFullResult(ctx, client, query, url.Values{"format": {"plaintext"}, "units": {unitsName(units)}})
+	if err != nil {
+		log.Printf("WOLFRAM ERROR: Unable to fetch the chemical properties for %q.\nError Details: %v", query, err)
+		return "", nil, false
+	}
+
+	var (
+		interpretation string
+		rows           [][2]string
+	)
+	seen := map[string]bool{}
+	for _, pod := range result.Pods {
+		if pod.ID == "Input" {
+			interpretation = strings.TrimSpace(strings.SplitN(pod.plaintext(), "|", 2)[0])
+			continue
+		}
+		if !chemistryPodPattern.MatchString(pod.ID) && !chemistryPodPattern.MatchString(pod.Title) {
+			continue
+		}
+		for _, line := range strings.Split(pod.plaintext(), "\n") {
+			match := chemistryRowPattern.FindStringSubmatch(line)
+			if match == nil || len(rows) >= chemistryMaxProperties {
+				continue
+			}
+			if name := strings.ToLower(match[1]); !seen[name] {
+				seen[name] = true
+				rows = append(rows, [2]string{match[1], match[2]})
+			}
+		}
+	}
+	return interpretation, rows, len(rows) > 0
+}
+
+// Global function for answering a chemistry question with a bulleted property list headed by what Wolfram took the
+// question to be about. Reports false (answer normally, in plaintext) when the flag is off, the question isn't about
+// chemistry, or Wolfram has no structured data for it.
+func answerChemistry(ctx context.Context, event *slack.MessageEvent, query string, units wolfram.Unit) (handlerResponse, bool) {
+	if !flagEnabled(ctx, "chemistry_formatting") || !isChemistryQuery(query) {
+		return handlerResponse{}, false
+	}
+	interpretation, rows, ok := fetchChemistryProperties(ctx, wolframClientFor(event), query, units)
+	if !ok {
+		traceStep(ctx, "no chemical properties found for %q", query)
+		return handlerResponse{}, false
+	}
+	metrics.inc("wolfy_chemistry_answers_total")
+	traceStep(ctx, "chemical properties for %q (%d rows)", query, len(rows))
+
+	lines := make([]string, 0, len(rows)+1)
+	if interpretation != "" {
+		lines = append(lines, ":test_tube: *"+formatChemicalNotation(interpretation)+"*")
+	}
+	for _, row := range rows {
+		name := []rune(row[0])
+		lines = append(lines, "• *"+strings.ToUpper(string(name[:1]))+string(name[1:])+":* "+formatChemicalNotation(row[1]))
+	}
+	return handlerResponse{
+		Text:  strings.Join(lines, "\n"),
+		Query: query,
+		Details: []slack.AttachmentField{
+			{Title: "Interpreted as", Value: query, Short: true},
+			{Title: "Source", Value: "Wolfram|Alpha", Short: true},
+		},
+	}, true
+}
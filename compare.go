@@ -0,0 +1,274 @@
+//////////////////////////////////////////////////
+// Comparisons Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for answering "compare X and Y" questions side by side
+import (
+	"context" // Permits per-operand API usage accounting
+	"fmt"     // Permits formatting of comparison rows
+	"log"     // Permits console logging
+	"regexp"  // Permits matching of comparison phrasings
+	"strings" // Permits string manipulation
+	"sync"    // Permits querying operands concurrently
+
+	wit "github.com/christianrondeau/go-wit" // External Wit.ai API
+	slack "github.com/nlopes/slack"          // External Slack API
+)
+
+// Global constants capping how many subjects a comparison queries and how many aligned properties it shows
+const (
+	maxComparisonSubjects = 3
+	maxComparisonRows     = 5
+	maxComparisonValue    = 200
+)
+
+// Global constant naming the entity key comparisons are dispatched under
+const comparisonEntityKey = "comparison_query"
+
+// Global patterns recognizing a comparison, its "the X of" property prefix, and the separators between subjects
+var (
+	comparisonPattern         = regexp.MustCompile(`(?i)^\s*(?:wolfy\s+)?(?:(?:can|could)\s+you\s+)?(?:please\s+)?compare\s+(.+?)\s*[?.!]*\s*$`)
+	comparisonPropertyPattern = regexp.MustCompile(`(?i)^(?:the\s+)?(.+?)\s+of\s+(.+)$`)
+	comparisonSplitPattern    = regexp.MustCompile(`(?i)\s*,\s*(?:and\s+)?|\s+(?:and|vs\.?|versus|with|to)\s+`)
+)
+
+// Global set of pod IDs describing the query rather than the subject, which never make useful comparison rows
+var comparisonSkippedPods = map[string]bool{"Input": true, "InputInterpretation": true}
+
+// Global struct holding a parsed comparison: the property compared (if named) and its subjects
+type comparison struct {
+	property string
+	subjects []string
+	dropped  int
+}
+
+// Global struct holding one aligned row of a comparison: a property and each subject's value for it
+type comparisonRow struct {
+	title  string
+	values []string
+}
+
+// Registering the comparison handler
+func init() {
+	registerEntityHandler(&entityHandler{
+		name:        comparisonEntityKey,
+		description: "Compare two or three things side by side (\"compare the mass of Earth and Mars\").",
+		local:       true,
+		handle:      handleComparison,
+	})
+}
+
+// Global function for parsing "compare X and Y [property]" or "compare the P of X, Y and Z", reporting false otherwise
+func parseComparison(text string) (comparison, bool) {
+	match := comparisonPattern.FindStringSubmatch(text)
+	if match == nil {
+		return comparison{}, false
+	}
+	body, property := match[1], ""
+	if parts := comparisonPropertyPattern.FindStringSubmatch(body); parts != nil {
+		property, body = parts[1], parts[2]
+	}
+
+	var subjects []string
+	for _, subject := range comparisonSplitPattern.Split(body, -1) {
+		if subject = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(subject), "the ")); subject != "" {
+			subjects = append(subjects, subject)
+		}
+	}
+	if len(subjects) < 2 {
+		return comparison{}, false
+	}
+
+	// Reading a trailing property off the last subject when it's longer than the first ("Python and Go popularity")
+	if first, last := strings.Fields(subjects[0]), strings.Fields(subjects[len(subjects)-1]); property == "" && len(last) > len(first) {
+		subjects[len(subjects)-1] = strings.Join(last[:len(first)], " ")
+		property = strings.Join(last[len(first):], " ")
+	}
+
+	parsed := comparison{property: property, subjects: subjects}
+	if len(parsed.subjects) > maxComparisonSubjects {
+		parsed.dropped = len(parsed.subjects) - maxComparisonSubjects
+		parsed.subjects = parsed.subjects[:maxComparisonSubjects]
+	}
+	return parsed, true
+}
+
+// Method for phrasing the Wolfram query for one subject
+func (parsed comparison) query(subject string) string {
+	if parsed.property == "" {
+		return subject
+	}
+	return parsed.property + " of " + subject
+}
+
+// Global function for lining up the pods every subject's results share, in the first subject's pod order
+func alignComparisonPods(results []*fullResult) []comparisonRow {
+	if len(results) == 0 || results[0] == nil {
+		return nil
+	}
+
+	var rows []comparisonRow
+	for _, pod := range results[0].Pods {
+		if comparisonSkippedPods[pod.ID] || pod.plaintext() == "" {
+			continue
+		}
+		row := comparisonRow{title: pod.Title, values: []string{pod.plaintext()}}
+		for _, other := range results[1:] {
+			value := ""
+			if other != nil {
+				for _, candidate := range other.Pods {
+					if candidate.ID == pod.ID || (candidate.ID == "" && strings.EqualFold(candidate.Title, pod.Title)) {
+						value = candidate.plaintext()
+						break
+					}
+				}
+			}
+			if value == "" {
+				break
+			}
+			row.values = append(row.values, value)
+		}
+		if len(row.values) == len(results) {
+			rows = append(rows, row)
+		}
+		if len(rows) == maxComparisonRows {
+			break
+		}
+	}
+	return rows
+}
+
+// Global function for a subject's headline result when pods don't line up: its primary pod, else its first real one
+func headlineResult(result *fullResult) string {
+	if result == nil {
+		return ""
+	}
+	fallback := ""
+	for _, pod := range result.Pods {
+		if comparisonSkippedPods[pod.ID] || pod.plaintext() == "" {
+			continue
+		}
+		if pod.Primary {
+			return pod.plaintext()
+		}
+		if fallback == "" {
+			fallback = pod.plaintext()
+		}
+	}
+	return fallback
+}
+
+// Global function for laying out aligned rows as attachment fields, one column per subject when there are two
+func comparisonFields(subjects []string, rows []comparisonRow) []slack.AttachmentField {
+	fields := make([]slack.AttachmentField, 0, len(rows)*len(subjects))
+	for _, row := range rows {
+		if len(subjects) == 2 {
+			for i, subject := range subjects {
+				fields = append(fields, slack.AttachmentField{Title: row.title + " - " + subject, Value: truncateGraphemes(row.values[i], maxComparisonValue), Short: true})
+			}
+			continue
+		}
+		lines := make([]string, len(subjects))
+		for i, subject := range subjects {
+			lines[i] = fmt.Sprintf("*%s:* %s", subject, truncateGraphemes(row.values[i], maxComparisonValue))
+		}
+		fields = append(fields, slack.AttachmentField{Title: row.title, Value: strings.Join(lines, "\n")})
+	}
+	return fields
+}
+
+// Global function for laying out aligned rows as plain text lines, for when attachments are turned off
+func comparisonLines(subjects []string, rows []comparisonRow) []string {
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		values := make([]string, len(subjects))
+		for i, subject := range subjects {
+			values[i] = fmt.Sprintf("%s: %s", subject, truncateGraphemes(row.values[i], maxComparisonValue))
+		}
+		lines = append(lines, fmt.Sprintf("• *%s* - %s", row.title, strings.Join(values, " | ")))
+	}
+	return lines
+}
+
+// Global function for building a side-by-side reply: the header, then one row per property and one column per subject, as
+// attachment fields or (with attachments off) plain text lines
+func sideBySideResponse(header string, columns []string, rows []comparisonRow, query string) handlerResponse {
+	if !config.AnswerAttachments {
+		return handlerResponse{Text: header + "\n" + strings.Join(comparisonLines(columns, rows), "\n"), Query: query}
+	}
+	return handlerResponse{Text: header, Query: query, Details: comparisonFields(columns, rows)}
+}
+
+// Handler answering comparisons with one full results query per subject, side by side when their pods line up
+func handleComparison(ctx context.Context, event *slack.MessageEvent, entity wit.MessageEntity) (handlerResponse, error) {
+	text, _ := entity.Value.(string)
+	parsed, ok := parseComparison(text)
+	if !ok {
+		// A Wit.ai comparison intent whose wording we can't split is still worth a single Wolfram query
+		return handleWolframQuery(ctx, event, entity)
+	}
+	metrics.inc("wolfy_comparisons_total")
+	traceStep(ctx, "comparing %q (property %q)", parsed.subjects, parsed.property)
+
+	// Querying every subject at once; each query is counted against the question's API usage as usual
+	results := make([]*fullResult, len(parsed.subjects))
+	errs := make([]error, len(parsed.subjects))
+	var wait sync.WaitGroup
+	for i, subject := range parsed.subjects {
+		wait.Add(1)
+		go func(i int, query string) {
+			defer wait.Done()
+			results[i], errs[i] = fetchFullResult(ctx, wolframClientFor(event), query, nil)
+		}(i, parsed.query(subject))
+	}
+	wait.Wait()
+	for i, err := range errs {
+		if err != nil {
+			log.Printf("WOLFRAM ERROR: Unable to get full results for comparison subject %q.\nError Details: %v", parsed.subjects[i], err)
+			return handlerResponse{Query: parsed.query(parsed.subjects[i])}, err
+		}
+	}
+
+	header := "Comparing *" + strings.Join(parsed.subjects, "* and *") + "*"
+	if parsed.property != "" {
+		header += " (" + parsed.property + ")"
+	}
+	header += ":"
+	for _, result := range results {
+		if result.Partial {
+			header += "\n" + partialResultNote
+			break
+		}
+	}
+	if parsed.dropped > 0 {
+		header += fmt.Sprintf(" _(I compare up to %d things at a time, so I left out %d.)_", maxComparisonSubjects, parsed.dropped)
+	}
+	query := parsed.query(strings.Join(parsed.subjects, " vs. "))
+
+	if rows := alignComparisonPods(results); len(rows) > 0 {
+		traceStep(ctx, "aligned %d shared properties", len(rows))
+		return sideBySideResponse(header, parsed.subjects, rows, query), nil
+	}
+
+	// Falling back to Wolfram's own answer for the comparison as a whole ("GDP of Germany vs. France"), then to each
+	// subject's headline result in turn
+	units, _ := resolveUnits(event.User)
+	if raw, err := fetchShortAnswer(ctx, wolframClientFor(event), query, units); err == nil {
+		traceStep(ctx, "no shared properties; using the short answer for %q", query)
+		return handlerResponse{Text: raw, Query: query}, nil
+	}
+	traceStep(ctx, "no shared properties; answering each subject in turn")
+	sections := []string{header}
+	for i, subject := range parsed.subjects {
+		result := headlineResult(results[i])
+		if result == "" {
+			result = "_Wolfram|Alpha had nothing on this one._"
+		}
+		sections = append(sections, fmt.Sprintf("*%s*\n%s", subject, truncateGraphemes(result, maxComparisonValue)))
+	}
+	return handlerResponse{Text: strings.Join(sections, "\n\n"), Query: query}, nil
+}
@@ -0,0 +1,118 @@
+//////////////////////////////////////////////////
+// Comparisons Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing how full results for each compared subject are lined up side by side
+import (
+	"encoding/json" // Permits decoding of full results fixtures
+	"reflect"       // Permits comparison of aligned rows
+	"testing"       // Permits Go testing
+)
+
+// Global fixtures recorded from full results queries for "mass of Earth", "mass of Mars", and "Paris", trimmed to
+// their pods
+const (
+	earthMassFixture = `{"success": true, "pods": [
+		{"title": "Input interpretation", "id": "Input", "subpods": [{"plaintext": "Earth | mass"}]},
+		{"title": "Result", "id": "Result", "primary": true, "subpods": [{"plaintext": "5.972×10^24 kg (kilograms)"}]},
+		{"title": "Unit conversions", "id": "UnitConversion", "subpods": [{"plaintext": "1.317×10^25 lb (pounds)"}]},
+		{"title": "Comparisons", "id": "Comparison", "subpods": [{"plaintext": "≈ 81 × mass of the Moon"}]},
+		{"title": "Corresponding quantities", "id": "CorrespondingQuantity", "subpods": [{"plaintext": ""}]}
+	]}`
+	marsMassFixture = `{"success": true, "pods": [
+		{"title": "Input interpretation", "id": "Input", "subpods": [{"plaintext": "Mars | mass"}]},
+		{"title": "Result", "id": "Result", "primary": true, "subpods": [{"plaintext": "6.417×10^23 kg (kilograms)"}]},
+		{"title": "Comparisons", "id": "Comparison", "subpods": [{"plaintext": "≈ 0.11 × mass of Earth"}]},
+		{"title": "Unit conversions", "id": "UnitConversion", "subpods": [{"plaintext": "1.415×10^24 lb (pounds)"}]}
+	]}`
+	parisFixture = `{"success": true, "pods": [
+		{"title": "Input interpretation", "id": "Input", "subpods": [{"plaintext": "Paris, Ile-de-France, France"}]},
+		{"title": "Population", "id": "Population:CityData", "subpods": [{"plaintext": "city population | 2.1 million people"}]},
+		{"title": "Location", "id": "Location:CityData", "subpods": [{"plaintext": "Ile-de-France, France"}]}
+	]}`
+	untitledFixture = `{"success": true, "pods": [
+		{"title": "result", "subpods": [{"plaintext": "6.417×10^23 kg"}]}
+	]}`
+)
+
+// Global function for decoding full results fixtures in order
+func comparisonFixtures(t *testing.T, fixtures ...string) []*fullResult {
+	t.Helper()
+	results := make([]*fullResult, len(fixtures))
+	for i, fixture := range fixtures {
+		if fixture == "" {
+			continue
+		}
+		results[i] = new(fullResult)
+		if err := json.Unmarshal([]byte(fixture), results[i]); err != nil {
+			t.Fatalf("decoding fixture %d: %v", i, err)
+		}
+	}
+	return results
+}
+
+// Test for shared pods lining up in the first subject's order, matched by ID or (without one) by title, with input and
+// empty pods skipped and nothing aligned when the subjects share no pods
+func TestAlignComparisonPods(t *testing.T) {
+	cases := []struct {
+		name     string
+		fixtures []string
+		want     []comparisonRow
+	}{
+		{"aligned", []string{earthMassFixture, marsMassFixture}, []comparisonRow{
+			{"Result", []string{"5.972×10^24 kg (kilograms)", "6.417×10^23 kg (kilograms)"}},
+			{"Unit conversions", []string{"1.317×10^25 lb (pounds)", "1.415×10^24 lb (pounds)"}},
+			{"Comparisons", []string{"≈ 81 × mass of the Moon", "≈ 0.11 × mass of Earth"}},
+		}},
+		{"matched by title", []string{earthMassFixture, untitledFixture}, []comparisonRow{
+			{"Result", []string{"5.972×10^24 kg (kilograms)", "6.417×10^23 kg"}},
+		}},
+		{"only pods every subject shares", []string{earthMassFixture, marsMassFixture, untitledFixture}, []comparisonRow{
+			{"Result", []string{"5.972×10^24 kg (kilograms)", "6.417×10^23 kg (kilograms)", "6.417×10^23 kg"}},
+		}},
+		{"unaligned", []string{earthMassFixture, parisFixture}, nil},
+		{"missing result", []string{earthMassFixture, ""}, nil},
+		{"missing first result", []string{"", marsMassFixture}, nil},
+	}
+	for _, c := range cases {
+		if got := alignComparisonPods(comparisonFixtures(t, c.fixtures...)); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: alignComparisonPods = %v; want %v", c.name, got, c.want)
+		}
+	}
+}
+
+// Test for alignment stopping at the row cap however many pods the subjects share
+func TestAlignComparisonPodsCapped(t *testing.T) {
+	fixture := `{"pods": [
+		{"id": "A", "title": "A", "subpods": [{"plaintext": "1"}]}, {"id": "B", "title": "B", "subpods": [{"plaintext": "2"}]},
+		{"id": "C", "title": "C", "subpods": [{"plaintext": "3"}]}, {"id": "D", "title": "D", "subpods": [{"plaintext": "4"}]},
+		{"id": "E", "title": "E", "subpods": [{"plaintext": "5"}]}, {"id": "F", "title": "F", "subpods": [{"plaintext": "6"}]}
+	]}`
+	rows := alignComparisonPods(comparisonFixtures(t, fixture, fixture))
+	if len(rows) != maxComparisonRows || rows[len(rows)-1].title != "E" {
+		t.Errorf("alignComparisonPods kept %d rows ending at %v; want %d ending at E", len(rows), rows[len(rows)-1], maxComparisonRows)
+	}
+}
+
+// Test for the headline of an unaligned subject being its primary pod, else its first pod beyond the input
+func TestHeadlineResult(t *testing.T) {
+	cases := []struct {
+		name    string
+		fixture string
+		want    string
+	}{
+		{"primary pod", marsMassFixture, "6.417×10^23 kg (kilograms)"},
+		{"first real pod", parisFixture, "city population | 2.1 million people"},
+		{"only the input", `{"pods": [{"id": "Input", "subpods": [{"plaintext": "Paris"}]}]}`, ""},
+		{"missing result", "", ""},
+	}
+	for _, c := range cases {
+		if got := headlineResult(comparisonFixtures(t, c.fixture)[0]); got != c.want {
+			t.Errorf("%s: headlineResult = %q; want %q", c.name, got, c.want)
+		}
+	}
+}
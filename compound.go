@@ -0,0 +1,112 @@
+//////////////////////////////////////////////////
+// Compound Questions Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for splitting "X and Y" questions into separate answers
+import (
+	"context" // Permits per-question API usage accounting
+	"fmt"     // Permits string formatting of replies
+	"regexp"  // Permits matching of conjunctions and question heads
+	"strings" // Permits string manipulation
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constant capping how many sub-questions a single message may spend quota on
+const maxCompoundQuestions = 3
+
+// Global patterns used by the (deliberately conservative) compound question splitter
+var (
+	compoundConjunctionPattern = regexp.MustCompile(`(?i)\s*,?\s+and\s+(?:also\s+)?`)
+	questionWordPattern        = regexp.MustCompile(`(?i)^(what|what's|whats|who|who's|where|where's|when|why|how|which|is|are|was|were|does|do|did|can|could|will|would|should)\b`)
+	attributeHeadPattern       = regexp.MustCompile(`(?i)^(the\s+)?[a-z]+(\s+[a-z]+)?\s+of\s+\S`)
+	wordPrefixPattern          = regexp.MustCompile(`(?i)^(what\s+is|what's|whats|what\s+are|what\s+was|who\s+is|who's|where\s+is|where's|how\s+(?:big|far|old|tall|many|much)\s+(?:is|are))\s+`)
+	pairedPhrasePattern        = regexp.MustCompile(`(?i)\b(between|both|either|neither|compare|comparing|from)\b`)
+)
+
+// Global function for checking whether a clause carries its own question head
+func hasQuestionHead(clause string) bool {
+	return questionWordPattern.MatchString(clause) || attributeHeadPattern.MatchString(clause)
+}
+
+// Global function for splitting a compound question, returning nil unless every clause is its own question
+func splitCompoundQuestion(text string) []string {
+	text = strings.TrimSpace(text)
+	clauses := compoundConjunctionPattern.Split(text, -1)
+	if len(clauses) < 2 {
+		return nil
+	}
+
+	// Never splitting phrasings whose "and" joins a pair ("difference between cats and dogs")
+	if pairedPhrasePattern.MatchString(clauses[0]) {
+		return nil
+	}
+
+	prefix := wordPrefixPattern.FindString(clauses[0])
+	parallelAttributes := strings.Contains(strings.ToLower(clauses[0]), " of ")
+	parts := make([]string, 0, len(clauses))
+	for i, clause := range clauses {
+		clause = strings.TrimSpace(strings.TrimRight(clause, "?.! "))
+		if len(strings.Fields(clause)) < 2 || !hasQuestionHead(clause) {
+			// "black and white" or "cats and dogs" style conjunctions stay one question
+			return nil
+		}
+		if i > 0 && !questionWordPattern.MatchString(clause) && !parallelAttributes {
+			// Bare "X of Y" clauses only count when the first clause has the same shape ("pros and cons of Go")
+			return nil
+		}
+
+		// Carrying the first clause's question word onto bare attribute clauses ("... and the GDP of France")
+		if i > 0 && prefix != "" && !questionWordPattern.MatchString(clause) {
+			clause = prefix + clause
+		}
+		parts = append(parts, clause+"?")
+	}
+	return parts
+}
+
+// Global function for answering each part of a compound question in one numbered reply
+func answerCompoundQuestion(ctx context.Context, event *slack.MessageEvent, parts []string) {
+	metrics.inc("wolfy_compound_questions_total")
+
+	lines := make([]string, 0, len(parts)+1)
+	for i, part := range parts {
+		if i >= maxCompoundQuestions {
+			lines = append(lines, fmt.Sprintf("_(I only answer %d questions at a time, so I skipped the rest.)_", maxCompoundQuestions))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%d. *%s* %s", i+1, isolateDirection(part), isolateDirection(answerSubQuestion(ctx, event, part))))
+	}
+
+	if !claimDelivery(ctx) {
+		traceStep(ctx, "cancelled by the asker; dropping the answer")
+		return
+	}
+	reply := strings.Join(lines, "\n")
+	deliverAnswer(event, answerDestination(event), "", reply, nil, "")
+	rememberInteraction(event, interaction{Text: event.Msg.Text, EntityKey: "compound_question", Answer: reply, Calls: apiCalls(ctx)})
+}
+
+// Global function for running one sub-question through the local answerers, Wit.ai, and the handlers
+func answerSubQuestion(ctx context.Context, event *slack.MessageEvent, question string) string {
+	sub := *event
+	sub.Msg.Text = question
+
+	if name, reply, ok := localAnswer(&sub); ok && !handlerEnabled(name) {
+		return handlerDisabledNotice
+	} else if ok {
+		return reply
+	}
+
+	entityKey, entity, err := classifyMessage(ctx, question)
+	if err != nil {
+		logFailure(ctx, err, "classifying a sub-question")
+		return failureReply(ctx, err)
+	}
+	metrics.inc("wolfy_intents_total", "intent", intentLabel(entityKey))
+	return runEntityHandler(ctx, &sub, entityKey, entity)
+}
@@ -0,0 +1,68 @@
+//////////////////////////////////////////////////
+// Compound Questions Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing the compound question splitter
+import (
+	"context" // Permits answering without a message deadline
+	"reflect" // Permits comparison of split questions
+	"strings" // Permits inspection of the numbered reply
+	"testing" // Permits Go testing
+)
+
+// Test for splitting coordinated questions and leaving alone every "and" that joins a pair or names one thing
+func TestSplitCompoundQuestion(t *testing.T) {
+	cases := []struct {
+		text  string
+		parts []string
+	}{
+		{"What's the population of Japan and the population of Germany?", []string{"What's the population of Japan?", "What's the population of Germany?"}},
+		{"what is the population of Japan and the GDP of France", []string{"what is the population of Japan?", "what is the GDP of France?"}},
+		{"what is the capital of France and what is the capital of Spain", []string{"what is the capital of France?", "what is the capital of Spain?"}},
+		{"how tall is Everest, and how old is the Earth?", []string{"how tall is Everest?", "how old is the Earth?"}},
+		{"how far is the moon and how far is the sun", []string{"how far is the moon?", "how far is the sun?"}},
+		{"what is 2 + 2 and what is 3 + 3", []string{"what is 2 + 2?", "what is 3 + 3?"}},
+		{"what's the difference between cats and dogs", nil},
+		{"what is the relationship between time and space", nil},
+		{"is a zebra black and white", nil},
+		{"pros and cons of Go", nil},
+		{"who wrote Romeo and Juliet", nil},
+		{"what is rock and roll", nil},
+		{"what is the sum of 2 and 3", nil},
+		{"what is the boiling point of water and of ethanol", nil},
+		{"compare France and Germany", nil},
+		{"salt and pepper", nil},
+		{"what is the speed of light", nil},
+	}
+	for _, c := range cases {
+		if parts := splitCompoundQuestion(c.text); len(parts) != len(c.parts) || (len(parts) > 0 && !reflect.DeepEqual(parts, c.parts)) {
+			t.Errorf("splitCompoundQuestion(%q) = %q; want %q", c.text, parts, c.parts)
+		}
+	}
+}
+
+// Test for answering at most three sub-questions and saying the rest were skipped
+func TestCompoundQuestionCap(t *testing.T) {
+	text := "what is 1 + 1 and what is 2 + 2 and what is 3 + 3 and what is 4 + 4"
+	parts := splitCompoundQuestion(text)
+	if len(parts) != 4 {
+		t.Fatalf("splitCompoundQuestion(%q) = %q; want 4 parts", text, parts)
+	}
+
+	fakeSlack.reset()
+	answerCompoundQuestion(context.Background(), testMessage("DCAP", "UCAP", "4000.000001", text), parts)
+	waitFor(t, "the numbered reply", func() bool { return len(fakeSlack.callsTo("chat.postMessage")) > 0 })
+	reply := fakeSlack.callsTo("chat.postMessage")[0].values.Get("text")
+	for _, want := range []string{"1 + 1 = 2", "2 + 2 = 4", "3 + 3 = 6", "only answer 3 questions"} {
+		if !strings.Contains(reply, want) {
+			t.Errorf("compound reply %q is missing %q", reply, want)
+		}
+	}
+	if strings.Contains(reply, "4 + 4 = 8") {
+		t.Errorf("compound reply %q answered a fourth sub-question", reply)
+	}
+}
@@ -0,0 +1,89 @@
+//////////////////////////////////////////////////
+// Concurrency Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for stress testing the message handler's shared state (run with -race)
+import (
+	"fmt"     // Permits building of distinct questions and timestamps
+	"strings" // Permits recognition of posted answers
+	"sync"    // Permits waiting for concurrent handlers
+	"testing" // Permits Go testing
+)
+
+// Test for answering many users' messages at once, with every answer counted and posted exactly once
+func TestConcurrentMessages(t *testing.T) {
+	const users, perUser = 20, 10
+	intents := metricValue("wolfy_intents_total", "intent", "exact_arithmetic")
+	posts := len(answerPosts())
+
+	var wg sync.WaitGroup
+	for u := 0; u < users; u++ {
+		for i := 0; i < perUser; i++ {
+			wg.Add(1)
+			go func(u int, i int) {
+				defer wg.Done()
+				user := fmt.Sprintf("UC%02d", u)
+				handleMSGEvent(testMessage("D"+user, user, fmt.Sprintf("2000.%03d%03d", u, i), fmt.Sprintf("what is %d * %d", u+2, i+2)))
+			}(u, i)
+		}
+	}
+	wg.Wait()
+
+	if got := metricValue("wolfy_intents_total", "intent", "exact_arithmetic") - intents; got != users*perUser {
+		t.Errorf("exact_arithmetic intents counted = %d; want %d", got, users*perUser)
+	}
+	waitFor(t, "every answer to be posted", func() bool { return len(answerPosts())-posts >= users*perUser })
+	if got := len(answerPosts()) - posts; got != users*perUser {
+		t.Errorf("answers posted = %d; want %d", got, users*perUser)
+	}
+}
+
+// Global function for listing the arithmetic answers posted so far (first-contact welcomes are posted alongside them)
+func answerPosts() []fakeSlackCall {
+	var answers []fakeSlackCall
+	for _, call := range fakeSlack.callsTo("chat.postMessage") {
+		if strings.Contains(call.values.Get("text"), " = ") {
+			answers = append(answers, call)
+		}
+	}
+	return answers
+}
+
+// Test for one user changing settings and asking questions from many clients at once, contending on every per-user
+// map (preferences, conversation memory, in-flight requests, rate limits)
+func TestConcurrentMessagesOneUser(t *testing.T) {
+	const user, messages = "UHOT", 200
+	settings := metricValue("wolfy_intents_total", "intent", "set_units")
+	answers := metricValue("wolfy_intents_total", "intent", "exact_arithmetic")
+
+	var wg sync.WaitGroup
+	for i := 0; i < messages; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			text := fmt.Sprintf("what is %d + %d", i, i)
+			switch i % 4 {
+			case 0:
+				text = "set units metric"
+			case 1:
+				text = "set units imperial"
+			}
+			handleMSGEvent(testMessage("D"+user, user, fmt.Sprintf("3000.%06d", i), text))
+		}(i)
+	}
+	wg.Wait()
+
+	if got := metricValue("wolfy_intents_total", "intent", "set_units") - settings; got != messages/2 {
+		t.Errorf("set_units intents counted = %d; want %d", got, messages/2)
+	}
+	if got := metricValue("wolfy_intents_total", "intent", "exact_arithmetic") - answers; got != messages/2 {
+		t.Errorf("exact_arithmetic intents counted = %d; want %d", got, messages/2)
+	}
+	if units := loadPreferences(user).Units; units != "metric" && units != "imperial" {
+		t.Errorf("units after concurrent changes = %q; want metric or imperial", units)
+	}
+}
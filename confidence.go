@@ -0,0 +1,195 @@
+//////////////////////////////////////////////////
+// Intent Confidence Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for tracking how confident Wit.ai is per intent, to guide tuning of the confidence threshold
+import (
+	"fmt"     // Permits formatting of confidence reports
+	"log"     // Permits console logging
+	"sort"    // Permits stable ordering of reported intents
+	"strconv" // Permits parsing of the report window
+	"strings" // Permits joining of report lines
+	"sync"    // Permits concurrency-safe read-modify-write of daily stats
+	"time"    // Permits daily stats periods
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constants naming the store bucket holding daily confidence stats, how finely they bin confidences, how far
+// below the threshold a rejection counts as a near miss, and how long and how far back stats are kept and reported
+const (
+	confidenceStatsBucket    = "confidence_stats"
+	confidenceBins           = 50
+	confidenceNearMissMargin = 0.1
+	confidenceRetentionDays  = 90
+	defaultConfidenceDays    = 7
+	otherIntentLabel         = "other"
+)
+
+// Global bucket upper bounds of the per-intent confidence histogram on the metrics endpoint
+var confidenceHistogramBounds = []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1}
+
+// Global struct holding one intent's classifications for a day: binned top confidences, and how many fell short of the
+// threshold, and by no more than the near-miss margin
+type confidenceStats struct {
+	Bins       []int
+	Rejected   int
+	NearMisses int
+}
+
+// Global mutex serializing read-modify-write of daily confidence stats
+var confidenceMu sync.Mutex
+
+// Global function for naming an intent in confidence stats: its entity key when routed, or "other" to cap cardinality
+// against whatever Wit.ai returns
+func confidenceIntent(entityKey string) string {
+	if _, ok := currentRouting.Load().(routingTable)[entityKey]; ok {
+		return entityKey
+	}
+	return otherIntentLabel
+}
+
+// Global function for binning a confidence in [0, 1]
+func confidenceBin(confidence float64) int {
+	bin := int(confidence * confidenceBins)
+	if bin >= confidenceBins {
+		return confidenceBins - 1
+	}
+	if bin < 0 {
+		return 0
+	}
+	return bin
+}
+
+// Global function for recording a classification's most confident entity and whether it cleared the threshold, both on
+// the metrics endpoint and in the day's persisted stats
+func recordConfidence(entityKey string, confidence float64, cleared bool) {
+	intent := confidenceIntent(entityKey)
+	result := "cleared"
+	if !cleared {
+		result = "rejected"
+	}
+	metrics.observe(confidence, confidenceHistogramBounds, "wolfy_intent_confidence", "intent", intent)
+	metrics.inc("wolfy_intent_threshold_total", "intent", intent, "result", result)
+	if store == nil {
+		return
+	}
+
+	confidenceMu.Lock()
+	defer confidenceMu.Unlock()
+
+	now := time.Now().UTC()
+	day := now.Format("2006-01-02")
+	stats := map[string]*confidenceStats{}
+	if !store.get(confidenceStatsBucket, day, &stats) {
+		pruneConfidenceStats(now)
+	}
+	entry := stats[intent]
+	if entry == nil || len(entry.Bins) != confidenceBins {
+		entry = &confidenceStats{Bins: make([]int, confidenceBins)}
+		stats[intent] = entry
+	}
+	entry.Bins[confidenceBin(confidence)]++
+	if !cleared {
+		entry.Rejected++
+		if confidence >= config.ConfidenceThreshold-confidenceNearMissMargin {
+			entry.NearMisses++
+		}
+	}
+	if err := store.put(confidenceStatsBucket, day, stats); err != nil {
+		log.Printf("CONFIDENCE ERROR: Unable to record confidence stats for %s.\nError Details: %v", day, err)
+	}
+}
+
+// Global function for dropping daily confidence stats older than the retention window
+func pruneConfidenceStats(now time.Time) {
+	cutoff := now.AddDate(0, 0, -confidenceRetentionDays).Format("2006-01-02")
+	for _, day := range store.keys(confidenceStatsBucket) {
+		if day < cutoff {
+			store.delete(confidenceStatsBucket, day)
+		}
+	}
+}
+
+// Global function for estimating a percentile from binned confidences, as the midpoint of the bin it falls in
+func binnedPercentile(bins []int, total int, percentile float64) float64 {
+	rank := int(percentile*float64(total) + 0.5)
+	if rank < 1 {
+		rank = 1
+	}
+	seen := 0
+	for bin, count := range bins {
+		seen += count
+		if seen >= rank {
+			return (float64(bin) + 0.5) / confidenceBins
+		}
+	}
+	return 1
+}
+
+// Admin command reporting p10/p50/p90 confidence per intent over the last N days, with near-threshold rejections
+func runAdminConfidence(event *slack.MessageEvent, args []string) string {
+	usage := fmt.Sprintf("Usage: `!confidence report [days]` (1-%d, default %d).", confidenceRetentionDays, defaultConfidenceDays)
+	if len(args) == 0 || strings.ToLower(args[0]) != "report" || len(args) > 2 {
+		return usage
+	}
+	days := defaultConfidenceDays
+	if len(args) == 2 {
+		parsed, err := strconv.Atoi(args[1])
+		if err != nil || parsed < 1 || parsed > confidenceRetentionDays {
+			return usage
+		}
+		days = parsed
+	}
+
+	// Merging the window's daily stats per intent
+	totals := map[string]*confidenceStats{}
+	now := time.Now().UTC()
+	confidenceMu.Lock()
+	for i := 0; i < days; i++ {
+		var stats map[string]*confidenceStats
+		if !store.get(confidenceStatsBucket, now.AddDate(0, 0, -i).Format("2006-01-02"), &stats) {
+			continue
+		}
+		for intent, entry := range stats {
+			if entry == nil || len(entry.Bins) != confidenceBins {
+				continue
+			}
+			total := totals[intent]
+			if total == nil {
+				total = &confidenceStats{Bins: make([]int, confidenceBins)}
+				totals[intent] = total
+			}
+			for bin, count := range entry.Bins {
+				total.Bins[bin] += count
+			}
+			total.Rejected += entry.Rejected
+			total.NearMisses += entry.NearMisses
+		}
+	}
+	confidenceMu.Unlock()
+
+	if len(totals) == 0 {
+		return fmt.Sprintf("No classifications recorded in the last %d days.", days)
+	}
+	intents := make([]string, 0, len(totals))
+	for intent := range totals {
+		intents = append(intents, intent)
+	}
+	sort.Strings(intents)
+
+	lines := []string{fmt.Sprintf("*Wit.ai confidence over the last %d days* (threshold %.2f, near miss within %.1f):", days, config.ConfidenceThreshold, confidenceNearMissMargin)}
+	for _, intent := range intents {
+		total, count := totals[intent], 0
+		for _, binCount := range total.Bins {
+			count += binCount
+		}
+		lines = append(lines, fmt.Sprintf("• `%s`: %d classified, p10 %.2f / p50 %.2f / p90 %.2f; %d rejected, %d within %.1f of the threshold",
+			intent, count, binnedPercentile(total.Bins, count, 0.1), binnedPercentile(total.Bins, count, 0.5), binnedPercentile(total.Bins, count, 0.9), total.Rejected, total.NearMisses, confidenceNearMissMargin))
+	}
+	return strings.Join(lines, "\n")
+}
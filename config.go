@@ -0,0 +1,1156 @@
+//////////////////////////////////////////////////
+// Configuration Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for environment-driven configuration
+import (
+	"fmt"     // Permits formatting of validation errors
+	"os"      // Permits environment variable lookups
+	"regexp"  // Permits recognition of credential settings
+	"strconv" // Permits parsing of numeric and boolean settings
+	"strings" // Permits string manipulation of list settings
+	"time"    // Permits parsing of duration settings
+)
+
+// Global variable holding the build version (overridden via -ldflags "-X main.version=...")
+var version = "dev"
+
+// Global struct holding all runtime configuration for the Slackbot
+type Config struct {
+	// API credentials for the external services
+	SlackAccessToken string
+	WitAccessToken   string
+	WolframAppID     string
+
+	// Per-user or per-team Wolfram App IDs overriding the default key
+	WolframAppIDOverrides map[string]string
+
+	// Outbound HTTP proxy, TLS, and timeout settings shared by every API client
+	HTTPProxy             string
+	HTTPTimeout           time.Duration
+	TLSCAFile             string
+	TLSInsecureSkipVerify bool
+
+	// Admin channel receiving startup and shutdown announcements
+	AdminChannel         string
+	AnnouncementsEnabled bool
+
+	// Planned maintenance windows ("start/end" pairs) and the channel their start and end are announced in (the admin
+	// channel when empty)
+	MaintenanceWindows []string
+	MaintenanceChannel string
+
+	// RTM events per window past which flood protection drops low-priority events (0 disables it)
+	FloodThreshold int
+	FloodWindow    time.Duration
+
+	// Burn multiple per stage (classify, answer, post) past which a short-window error rate alerts admins against its
+	// long-window baseline (double it for critical), the two windows, the check interval, and the events a short window needs
+	BurnRateAlerts      map[string]string
+	BurnRateShortWindow time.Duration
+	BurnRateLongWindow  time.Duration
+	BurnRateInterval    time.Duration
+	BurnRateMinEvents   int
+
+	// File recording whether the previous run shut down cleanly
+	StateFile string
+
+	// Longest a shutdown waits for questions being answered to finish before forcing an exit (0 doesn't wait)
+	ShutdownDrainTimeout time.Duration
+
+	// JSON data file backing the persistence layer and its background flush interval
+	DataFile           string
+	StoreFlushInterval time.Duration
+
+	// How long processed messages are remembered to avoid answering them twice, and how long a user's reaction to a
+	// message is remembered so the same reaction is acted on once
+	DedupWindow         time.Duration
+	ReactionDedupWindow time.Duration
+
+	// How many processed messages, and separately reactions, are remembered at most, the oldest forgotten beyond it
+	DedupCacheSize int
+
+	// Redis server replicas claim messages through so only one answers each (unset for a single instance), its
+	// password, and how long a claim holds before other replicas may take over an unanswered message
+	RedisAddr        string
+	RedisPassword    string
+	InstanceClaimTTL time.Duration
+
+	// How long the bot's error and clarification replies stay up before being deleted (0 keeps them)
+	ErrorMessageTTL time.Duration
+
+	// Channel unanswerable questions can be passed on to (disabled when empty), and how long the offer stays open
+	SupportChannel   string
+	EscalationWindow time.Duration
+
+	// Number of workers handling messages (0 gives each message its own goroutine), how long a queued message waits
+	// before its asker is told their place in line (0 disables the notice), and how long it may wait before it is
+	// skipped as stale (0 never skips)
+	Workers          int
+	QueueNoticeDelay time.Duration
+	QueueFreshness   time.Duration
+
+	// Minimum gap between proactive offers to the same user in the same channel
+	ProactiveOfferCooldown time.Duration
+
+	// Whether answers go to the channel they were asked in (rather than the asker's DM), and the length past which they move to a DM
+	AnswerInChannel    bool
+	LongAnswerDMLength int
+
+	// Whether unthreaded channel answers start with a mention of the asker, so it's clear whose question they answer
+	AddressAsker bool
+
+	// Length in characters past which an answer is split into several messages (0 disables), and whether the later ones
+	// are threaded under the first in channels rather than posted one after another
+	AnswerChunkLength  int
+	AnswerChunkThreads bool
+
+	// Whether an answer meant for a channel the bot was removed from is DMed to the asker instead
+	ChannelGoneDM bool
+
+	// Last day ("2006-01-02") users used to DM answers are told, once, that answers now land in the channel; empty for none
+	DMMigrationNoticeUntil string
+
+	// Interactive reply deadline, past which a placeholder is posted and later replaced in place by the final answer
+	// (the placeholder text defaults to the personality pack's)
+	AnswerTimeout   time.Duration
+	PlaceholderText string
+
+	// Whether a slow answer's placeholder escalates, and how long after the question it changes to "still working" and
+	// then to an offer to cancel (0 skips that stage)
+	ThinkingEscalation bool
+	StillWorkingAfter  time.Duration
+	CancelOfferAfter   time.Duration
+
+	// Emoji the bot reacts with to thanks in a thread, instead of replying (empty always replies)
+	AcknowledgmentReaction string
+
+	// JSON file whose "routing" section maps Wit.ai entity keys to handler names (reloadable with "!reload routing")
+	RoutingFile string
+
+	// JSON file mapping team IDs to the name, emoji, and signature the bot's posts in that workspace carry
+	TeamBrandingFile string
+
+	// JSON file declaring handlers run as external commands, each answering its own entity key and patterns
+	ExternalHandlersFile string
+
+	// How the Wit.ai app's entities are checked against the routing table at startup and reload ("off", "warn", or "strict",
+	// which also fails the readiness endpoint while a routed entity is missing)
+	WitEntityCheck string
+
+	// Overall deadline per message spanning classification, fallbacks, and handlers (0 disables)
+	MessageTimeout time.Duration
+
+	// How soon after a user's previous message, still being answered, their next one in the same conversation waits
+	// for it rather than being answered alongside it (0 answers every message at once)
+	MessageGroupWindow time.Duration
+
+	// Default handler deadline and per-handler overrides by name (e.g. "wolfram_search_query=30s")
+	HandlerTimeout  time.Duration
+	HandlerTimeouts map[string]string
+
+	// How long an asker's repeat of a question they were just answered gets "I just answered that" (0 disables), and
+	// per-handler overrides by handler name
+	RepeatCooldown  time.Duration
+	RepeatCooldowns map[string]string
+
+	// Deadline for each backend probe run by "!diagnose"
+	DiagnoseTimeout time.Duration
+
+	// Entity keys in the order that wins exact Wit.ai confidence ties (unlisted keys follow, alphabetically)
+	IntentPriority []string
+
+	// Phrases routed straight to an entity key, ahead of Wit.ai (e.g. "p&l=wolfram_search_query")
+	IntentSynonyms map[string]string
+
+	// Whether intents with no routed handler are tried as a Wolfram query with the raw message before giving up
+	UnknownIntentFallback bool
+
+	// Wit.ai rate limit retries, initial backoff, and what to do once they run out ("wolfram" or "busy")
+	WitRetries           int
+	WitRetryBackoff      time.Duration
+	WitRateLimitFallback string
+
+	// Most classifications waiting while Wit.ai throttles us (0 disables the queue), their pace per minute, and the longest
+	// a question waits for its turn before taking the rate limit fallback
+	WitQueueSize    int
+	WitQueueRate    int
+	WitQueueMaxWait time.Duration
+
+	// How many times a Slack call retries after being rate limited (waiting out Retry-After each time), and the most it
+	// waits in total before giving up (0 for no bound)
+	SlackPostRetries  int
+	SlackRetryMaxWait time.Duration
+
+	// Whether channel messages must @-mention the bot, and how many bot threads (for how long) count as addressed without one
+	RequireMention     bool
+	ThreadTrackingSize int
+	ThreadTrackingTTL  time.Duration
+
+	// How many fetched thread parents are cached (for how long), and the oldest thread whose parent is fetched to give
+	// replies in it context (0 turns fetching off)
+	ThreadContextSize   int
+	ThreadContextTTL    time.Duration
+	ThreadContextMaxAge time.Duration
+
+	// Prefixes ("wolfy:") that address the bot at the start of a channel message, as an alternative to a mention
+	TriggerPrefixes []string
+
+	// How often scheduled ("ask me tomorrow") queries are checked for being due (0 disables scheduling)
+	ScheduleCheckInterval time.Duration
+
+	// Whether identical scheduled queries due at the same time for askers with the same settings share one lookup
+	ScheduleBatching bool
+
+	// Most recurring subscriptions a user may hold, and whether occurrences missed while down are skipped or run once ("skip" or "run-once")
+	SubscriptionLimit   int
+	SubscriptionCatchUp string
+
+	// Whether supporting answer detail (interpretation, source) is posted as an attachment, and its bar color
+	AnswerAttachments     bool
+	AnswerAttachmentColor string
+
+	// Whether "&", "<", and ">" in posted text are escaped for Slack (mentions and links are kept)
+	EscapeReplies bool
+
+	// Whether questions Wolfram can't answer fall back to a Wikipedia summary, whether its lead image is attached, and
+	// whether fallback answers end with the sources consulted
+	WikipediaFallback bool
+	WikipediaImages   bool
+	SourcesFooter     bool
+
+	// Whether questions without a short answer are answered from Wolfram's full results, image-only ones included
+	FullResultsFallback bool
+
+	// How much detail answers get where they are posted ("terse", "normal", or "detailed"), unless the asker or the
+	// channel chose otherwise
+	ChannelVerbosity string
+	DMVerbosity      string
+
+	// Whether geography answers attach Wolfram's map of the place
+	GeographyMaps bool
+
+	// Frankfurter-compatible endpoint for converting currency at a past date's rate (disabled when empty)
+	HistoricalRatesURL string
+
+	// LibreTranslate-compatible endpoint for translating non-English questions (disabled when empty) and its API key
+	TranslationURL    string
+	TranslationAPIKey string
+
+	// Wit.ai access tokens of per-language apps (e.g. "fr=TOKEN"), used for users who chose that language
+	WitLanguageTokens map[string]string
+
+	// Whether the bot starts with Wit.ai/Wolfram/translation calls switched off (flip at runtime with "!apis")
+	ExternalAPIsDisabled bool
+
+	// Estimated price per external API call by API ("wolfram", "wit", "translation"); unpriced APIs report "n/a"
+	APIPrices map[string]float64
+
+	// Whether the previous week's estimated spend is posted to the admin channel each Monday (UTC)
+	WeeklyDigest bool
+
+	// How long a user's latest question/answer is remembered for follow-ups
+	SessionTTL time.Duration
+
+	// How many users' sessions are remembered at most, overall and separately per channel
+	SessionCacheSize int
+
+	// How many past questions are kept per user for "export my history" (0 disables recording), and the export size cap
+	HistoryLimit          int
+	HistoryExportMaxBytes int
+
+	// Follow-up phrases that re-query the previous numeric answer with more digits
+	MoreDigitsCommands []string
+
+	// How long Wolfram answers are cached by normalized query (0 disables the cache), and how many entries are kept
+	AnswerCacheTTL  time.Duration
+	AnswerCacheSize int
+
+	// Half-life over which :+1:/:-1: feedback on answers fades, and the net thumbs-down score at which a question skips
+	// the cache and is answered fresh (0 turns the bypass off)
+	FeedbackHalfLife    time.Duration
+	FeedbackBypassScore float64
+
+	// Most questions answered from one pasted list; the rest are acknowledged as skipped
+	BatchQuestionLimit int
+
+	// Per-intent cache TTLs overriding AnswerCacheTTL (e.g. "weather=10m,stocks=off")
+	AnswerCacheIntents map[string]string
+
+	// How many readings of an ambiguous question are answered when the answer_candidates flag is on, and whether
+	// readings giving the same answer are listed once
+	AnswerCandidates int
+	AnswerDedup      bool
+
+	// Timeout Wolfram is given when full results are retried after some pods timed out (0 skips the retry)
+	WolframRetryTimeout time.Duration
+
+	// Whether numeric answers note how recent Wolfram's data is ("2023 estimate"), at the cost of a full results call
+	AnswerAsOfDates bool
+
+	// Whether answers to time-sensitive questions note when their data was fetched, and the longest such answers are
+	// cached (0 leaves their intent's TTL alone)
+	AnswerFreshness       bool
+	TimeSensitiveCacheTTL time.Duration
+
+	// Wit.ai confidence an intent must exceed to be answered (below it the bot says it isn't sure), and the confidence
+	// from which answers are given without a hedge (0 for the threshold plus the hedge band)
+	ConfidenceThreshold float64
+	ConfidentThreshold  float64
+
+	// Length in characters below which a Wit.ai extraction is taken as truncated, and the whole message asked instead
+	MinEntityQueryLength int
+
+	// Width of the confidence band above the intent threshold within which answers are hedged (0 disables hedging),
+	// used when no confident threshold is set
+	HedgeBand float64
+
+	// Presentation template per answer type (e.g. "number=bold,table=codeblock"), plain text for unlisted types
+	AnswerFormats map[string]string
+
+	// Titles (or IDs) of the full results pods worth showing, in the order shown (every pod when empty)
+	DisplayPods []string
+
+	// Whether "X and Y" questions are split into separately answered parts (now also the compound_questions flag)
+	SplitCompoundQuestions bool
+
+	// Workspace-wide feature flag overrides by name (e.g. "compound_questions=on"), below runtime "!flag" overrides
+	FeatureFlags map[string]string
+
+	// How long users.info lookups are cached, the timezone assumed when a user has none, and the workspace units and locale defaults
+	UserCacheTTL    time.Duration
+	DefaultTimezone string
+	DefaultUnits    string
+	DefaultLocale   string
+
+	// How many users.info lookups are cached at most, the least recently used evicted beyond it
+	UserCacheSize int
+
+	// Whether numbers in answers are reformatted for the asker's locale, and the significant digits long decimals are rounded to (0 keeps all)
+	NumberFormatting        bool
+	NumberSignificantDigits int
+
+	// Decimal places percentage and probability answers are rounded to ("≈ 42.3%")
+	PercentDecimals int
+
+	// Whether users get a one-time intro on their first DM, and a JSON file overriding catalog message text by key
+	WelcomeMessages bool
+	MessagesFile    string
+
+	// Directory of "<code>.json" message catalogs, and the language each channel's canned messages are posted in
+	LanguagesDir     string
+	ChannelLanguages map[string]string
+
+	// Handlers switched off unless turned back on with "!handler" (protected ones only with a ":force" suffix)
+	DisabledHandlers []string
+
+	// Daily quiet hours by channel ID ("22:00-07:00 America/New_York"), during which answers go by DM and scheduled
+	// posts wait for the morning
+	ChannelQuietHours map[string]string
+
+	// Whether a user's first reply in a channel (rather than a DM) is prefixed with a brief onboarding note
+	OnboardingNote bool
+
+	// Default personality pack, per-workspace/channel pack overrides by ID, and a directory of extra JSON packs
+	Personality          string
+	PersonalityOverrides map[string]string
+	PersonalityDir       string
+
+	// Minimum time between introductions in the same channel, so kick/re-invite cycles don't spam (disable per workspace with the channel_intro flag)
+	ChannelIntroCooldown time.Duration
+
+	// Slack user IDs permitted to run "!" admin commands, each optionally with its role ("U123:viewer"; operator otherwise)
+	AdminUsers []string
+
+	// Bot IDs or usernames (e.g. a Workflow Builder workflow) whose messages are answered like a person's
+	AllowedBots []string
+
+	// Channel every question and answer is mirrored to (disabled when empty), whether askers are pseudonymized there, whether
+	// DMs and private channels are mirrored too, and the most entries posted per minute
+	ArchiveChannel     string
+	ArchiveRedactUsers bool
+	ArchivePrivate     bool
+	ArchiveRateLimit   int
+
+	// Channel a share of answers is sampled into for quality review (disabled when empty), and the percentage sampled
+	ReviewChannel       string
+	ReviewSamplePercent float64
+
+	// How long handling a message may take before it is logged as a slow query (0 disables), and whether its text is hidden there
+	SlowQueryThreshold time.Duration
+	SlowQueryMaskText  bool
+
+	// How long the RTM connection may go without any event (pongs included) before the watchdog reconnects it (0 disables)
+	RTMIdleTimeout time.Duration
+
+	// How the bot receives events ("rtm" or "socket"), and the app-level token Socket Mode connects with
+	ConnectionMode string
+	SlackAppToken  string
+
+	// Whether this is a staging instance, the tag prefixed to all its replies, and the test channel it is kept to (any when empty)
+	StagingMode    bool
+	StagingTag     string
+	StagingChannel string
+
+	// Debug mode enables verbose diagnostics such as unhandled RTM event logging
+	Debug                     bool
+	UnhandledEventLogInterval time.Duration
+	UnhandledEventSampleBytes int
+	UnhandledEventBufferSize  int
+
+	// Whether traced users, and admins in debug mode, see how long each answer took and where it came from
+	PerformanceNotes bool
+
+	// Number of recent logged errors kept for "!errors" (0 disables the buffer)
+	ErrorBufferSize int
+
+	// Number of recent handled messages kept for "!recent" and the dashboard (0 disables the buffer), and the users
+	// whose questions are hidden there because they opted out
+	RecentBufferSize int
+	OptedOutUsers    []string
+
+	// Whether backend health is mirrored into the bot's presence and status (needs users.profile:write), the consecutive
+	// failures marking a backend degraded, how long a state must hold before it shows, and the minimum gap between writes
+	HealthStatus           bool
+	HealthFailureThreshold int
+	HealthDebounce         time.Duration
+	HealthStatusInterval   time.Duration
+
+	// Recovery of a degraded backend: the synthetic probes in a row that must pass, one per probe interval, before real
+	// requests go to it again (0 turns recovery off, so requests are never held), then the period over which their
+	// pace climbs from the starting to the full requests a minute, after which they go unpaced
+	RecoveryProbes        int
+	RecoveryProbeInterval time.Duration
+	RecoveryRampPeriod    time.Duration
+	RecoveryStartRate     int
+	RecoveryFullRate      int
+
+	// Listen address for the metrics endpoint (disabled when empty)
+	MetricsAddr string
+
+	// Shared HTTP listener hardening: the bearer token guarding internal routes (loopback only when empty), the signing
+	// secret Slack-facing routes are checked against, per-IP requests allowed a minute (0 for no limit), the largest
+	// request body accepted, and the read/write timeout
+	HTTPToken          string
+	SlackSigningSecret string
+	HTTPRateLimit      int
+	HTTPMaxBodyBytes   int
+	HTTPServerTimeout  time.Duration
+
+	// Whether /wolfy answers are shown to the whole channel unless "--private" is given (else only to the asker unless
+	// "--public" is)
+	SlashCommandPublic bool
+
+	// Where metrics are exported ("prometheus", "statsd", "both", or "none"), and the StatsD endpoint, metric path prefix,
+	// and push interval
+	MetricsExporter     string
+	StatsDAddr          string
+	StatsDPrefix        string
+	StatsDFlushInterval time.Duration
+
+	// Token guarding the /dashboard page on the metrics listener (disabled when empty), and whether it hides query text
+	DashboardToken       string
+	DashboardMaskQueries bool
+}
+
+// Global variable holding the loaded configuration
+var config *Config
+
+// Global type aggregating every problem found while validating the configuration, one per offending setting
+type configErrors []string
+
+// Global list of settings that were set but could not be parsed while loading (their fallbacks were used)
+var configParseErrors configErrors
+
+// Global settings imported with "!config import", taking precedence over the environment, and the effective value of
+// every setting as last loaded (secrets as a placeholder), for export and diffs
+var (
+	configOverrides map[string]string
+	configValues    map[string]string
+)
+
+// Global pattern recognizing settings holding credentials, which are never exported or imported
+var secretSettingPattern = regexp.MustCompile(`(?:TOKEN|SECRET|API_KEY|APP_IDS?|PROXY|PASSWORD)$`)
+
+// Method for rendering every configuration problem, one per line
+func (problems configErrors) Error() string {
+	return strings.Join(problems, "\n")
+}
+
+// Global function for building the configuration from the environment, under any imported overrides
+func loadConfig() *Config {
+	overrides, problems := readConfigOverrides()
+	configOverrides, configParseErrors = overrides, append(configParseErrors, problems...)
+	return readConfig()
+}
+
+// Global function for reading every setting, recording each one's effective value as it goes
+func readConfig() *Config {
+	configValues = map[string]string{}
+	return &Config{
+		SlackAccessToken: getEnvSecret("SLACK_ACCESS_TOKEN"),
+		WitAccessToken:   getEnvSecret("WIT_AI_ACCESS_TOKEN"),
+		WolframAppID:     getEnvSecret("WOLFRAM_APP_ID"),
+
+		WolframAppIDOverrides: getEnvMap("WOLFY_WOLFRAM_APP_IDS"),
+
+		HTTPProxy:             getEnvString("WOLFY_HTTP_PROXY", ""),
+		HTTPTimeout:           getEnvDuration("WOLFY_HTTP_TIMEOUT", 90*time.Second),
+		TLSCAFile:             getEnvString("WOLFY_TLS_CA_FILE", ""),
+		TLSInsecureSkipVerify: getEnvBool("WOLFY_TLS_INSECURE_SKIP_VERIFY", false),
+
+		AdminChannel:         getEnvString("WOLFY_ADMIN_CHANNEL", ""),
+		MaintenanceWindows:   getEnvList("WOLFY_MAINTENANCE_WINDOWS"),
+		MaintenanceChannel:   getEnvString("WOLFY_MAINTENANCE_CHANNEL", ""),
+		AnnouncementsEnabled: getEnvBool("WOLFY_ANNOUNCEMENTS_ENABLED", true),
+
+		FloodThreshold: getEnvInt("WOLFY_FLOOD_THRESHOLD", 0),
+		FloodWindow:    getEnvDuration("WOLFY_FLOOD_WINDOW", 10*time.Second),
+
+		BurnRateAlerts:      getEnvMap("WOLFY_BURN_RATE_ALERTS"),
+		BurnRateShortWindow: getEnvDuration("WOLFY_BURN_RATE_SHORT_WINDOW", 5*time.Minute),
+		BurnRateLongWindow:  getEnvDuration("WOLFY_BURN_RATE_LONG_WINDOW", time.Hour),
+		BurnRateInterval:    getEnvDuration("WOLFY_BURN_RATE_INTERVAL", time.Minute),
+		BurnRateMinEvents:   getEnvInt("WOLFY_BURN_RATE_MIN_EVENTS", 10),
+
+		StateFile: getEnvString("WOLFY_STATE_FILE", "wolfybot.state.json"),
+
+		ShutdownDrainTimeout: getEnvDuration("WOLFY_SHUTDOWN_DRAIN_TIMEOUT", 30*time.Second),
+
+		DataFile:           getEnvString("WOLFY_DATA_FILE", "wolfybot.data.json"),
+		StoreFlushInterval: getEnvDuration("WOLFY_STORE_FLUSH_INTERVAL", 2*time.Second),
+
+		DedupWindow:         getEnvDuration("WOLFY_DEDUP_WINDOW", 10*time.Minute),
+		ReactionDedupWindow: getEnvDuration("WOLFY_REACTION_DEDUP_WINDOW", time.Minute),
+
+		DedupCacheSize: getEnvInt("WOLFY_DEDUP_CACHE_SIZE", 50000),
+
+		RedisAddr:        getEnvString("WOLFY_REDIS_ADDR", ""),
+		RedisPassword:    getEnvSecret("WOLFY_REDIS_PASSWORD"),
+		InstanceClaimTTL: getEnvDuration("WOLFY_INSTANCE_CLAIM_TTL", 3*time.Minute),
+
+		ErrorMessageTTL: getEnvDuration("WOLFY_ERROR_MESSAGE_TTL", 0),
+
+		SupportChannel:   getEnvString("WOLFY_SUPPORT_CHANNEL", ""),
+		EscalationWindow: getEnvDuration("WOLFY_ESCALATION_WINDOW", 10*time.Minute),
+
+		Workers:          getEnvInt("WOLFY_WORKERS", 0),
+		QueueNoticeDelay: getEnvDuration("WOLFY_QUEUE_NOTICE_DELAY", 2*time.Second),
+		QueueFreshness:   getEnvDuration("WOLFY_QUEUE_FRESHNESS", 5*time.Minute),
+
+		ProactiveOfferCooldown: getEnvDuration("WOLFY_PROACTIVE_OFFER_COOLDOWN", 10*time.Minute),
+
+		AnswerInChannel:    getEnvBool("WOLFY_ANSWER_IN_CHANNEL", false),
+		LongAnswerDMLength: getEnvInt("WOLFY_LONG_ANSWER_DM_LENGTH", 1500),
+		AddressAsker:       getEnvBool("WOLFY_ADDRESS_ASKER", false),
+		AnswerChunkLength:  getEnvInt("WOLFY_ANSWER_CHUNK_LENGTH", 3000),
+		AnswerChunkThreads: getEnvBool("WOLFY_ANSWER_CHUNK_THREADS", true),
+		ChannelGoneDM:      getEnvBool("WOLFY_CHANNEL_GONE_DM", true),
+
+		DMMigrationNoticeUntil: getEnvString("WOLFY_DM_MIGRATION_NOTICE_UNTIL", ""),
+
+		AnswerTimeout:   getEnvDuration("WOLFY_ANSWER_TIMEOUT", 10*time.Second),
+		PlaceholderText: getEnvString("WOLFY_PLACEHOLDER_TEXT", ""),
+
+		ThinkingEscalation: getEnvBool("WOLFY_THINKING_ESCALATION", true),
+		StillWorkingAfter:  getEnvDuration("WOLFY_STILL_WORKING_AFTER", 20*time.Second),
+		CancelOfferAfter:   getEnvDuration("WOLFY_CANCEL_OFFER_AFTER", 45*time.Second),
+
+		AcknowledgmentReaction: getEnvString("WOLFY_ACKNOWLEDGMENT_REACTION", "heart"),
+
+		RoutingFile: getEnvString("WOLFY_ROUTING_FILE", ""),
+
+		TeamBrandingFile: getEnvString("WOLFY_TEAM_BRANDING_FILE", ""),
+
+		ExternalHandlersFile: getEnvString("WOLFY_EXTERNAL_HANDLERS_FILE", ""),
+
+		WitEntityCheck: getEnvString("WOLFY_WIT_ENTITY_CHECK", "warn"),
+
+		MessageTimeout: getEnvDuration("WOLFY_MESSAGE_TIMEOUT", 90*time.Second),
+
+		MessageGroupWindow: getEnvDuration("WOLFY_MESSAGE_GROUP_WINDOW", 2*time.Second),
+
+		HandlerTimeout:  getEnvDuration("WOLFY_HANDLER_TIMEOUT", 60*time.Second),
+		HandlerTimeouts: getEnvMap("WOLFY_HANDLER_TIMEOUTS"),
+		RepeatCooldown:  getEnvDuration("WOLFY_REPEAT_COOLDOWN", 30*time.Second),
+		RepeatCooldowns: getEnvMap("WOLFY_REPEAT_COOLDOWNS"),
+		DiagnoseTimeout: getEnvDuration("WOLFY_DIAGNOSE_TIMEOUT", 10*time.Second),
+
+		IntentPriority: getEnvList("WOLFY_INTENT_PRIORITY"),
+		IntentSynonyms: getEnvMap("WOLFY_INTENT_SYNONYMS"),
+
+		UnknownIntentFallback: getEnvBool("WOLFY_UNKNOWN_INTENT_FALLBACK", false),
+
+		WitRetries:           getEnvInt("WOLFY_WIT_RETRIES", 2),
+		WitRetryBackoff:      getEnvDuration("WOLFY_WIT_RETRY_BACKOFF", 500*time.Millisecond),
+		WitRateLimitFallback: getEnvString("WOLFY_WIT_RATE_LIMIT_FALLBACK", "wolfram"),
+
+		WitQueueSize:    getEnvInt("WOLFY_WIT_QUEUE_SIZE", 20),
+		WitQueueRate:    getEnvInt("WOLFY_WIT_QUEUE_RATE", 60),
+		WitQueueMaxWait: getEnvDuration("WOLFY_WIT_QUEUE_MAX_WAIT", 5*time.Second),
+
+		SlackPostRetries:  getEnvInt("WOLFY_SLACK_POST_RETRIES", 5),
+		SlackRetryMaxWait: getEnvDuration("WOLFY_SLACK_RETRY_MAX_WAIT", 2*time.Minute),
+
+		RequireMention:     getEnvBool("WOLFY_REQUIRE_MENTION", false),
+		ThreadTrackingSize: getEnvInt("WOLFY_THREAD_TRACKING_SIZE", 1000),
+		ThreadTrackingTTL:  getEnvDuration("WOLFY_THREAD_TRACKING_TTL", 24*time.Hour),
+
+		ThreadContextSize:   getEnvInt("WOLFY_THREAD_CONTEXT_SIZE", 500),
+		ThreadContextTTL:    getEnvDuration("WOLFY_THREAD_CONTEXT_TTL", time.Hour),
+		ThreadContextMaxAge: getEnvDuration("WOLFY_THREAD_CONTEXT_MAX_AGE", 7*24*time.Hour),
+
+		TriggerPrefixes: getEnvList("WOLFY_TRIGGER_PREFIXES", "wolfy", "wolfy:"),
+
+		ScheduleCheckInterval: getEnvDuration("WOLFY_SCHEDULE_CHECK_INTERVAL", 30*time.Second),
+		ScheduleBatching:      getEnvBool("WOLFY_SCHEDULE_BATCHING", true),
+
+		SubscriptionLimit:   getEnvInt("WOLFY_SUBSCRIPTION_LIMIT", 5),
+		SubscriptionCatchUp: getEnvString("WOLFY_SUBSCRIPTION_CATCH_UP", "run-once"),
+
+		AnswerAttachments:     getEnvBool("WOLFY_ANSWER_ATTACHMENTS", true),
+		AnswerAttachmentColor: getEnvString("WOLFY_ANSWER_ATTACHMENT_COLOR", "#dd1100"),
+
+		EscapeReplies: getEnvBool("WOLFY_ESCAPE_REPLIES", true),
+
+		WikipediaFallback: getEnvBool("WOLFY_WIKIPEDIA_FALLBACK", false),
+		WikipediaImages:   getEnvBool("WOLFY_WIKIPEDIA_IMAGES", true),
+		SourcesFooter:     getEnvBool("WOLFY_SOURCES_FOOTER", false),
+
+		FullResultsFallback: getEnvBool("WOLFY_FULL_RESULTS_FALLBACK", true),
+
+		ChannelVerbosity: strings.ToLower(getEnvString("WOLFY_CHANNEL_VERBOSITY", "terse")),
+		DMVerbosity:      strings.ToLower(getEnvString("WOLFY_DM_VERBOSITY", "normal")),
+
+		GeographyMaps: getEnvBool("WOLFY_GEOGRAPHY_MAPS", true),
+
+		HistoricalRatesURL: getEnvString("WOLFY_HISTORICAL_RATES_URL", "https://api.frankfurter.app"),
+
+		TranslationURL:    getEnvString("WOLFY_TRANSLATION_URL", ""),
+		TranslationAPIKey: getEnvString("WOLFY_TRANSLATION_API_KEY", ""),
+
+		WitLanguageTokens: getEnvMap("WOLFY_WIT_LANGUAGE_TOKENS"),
+
+		ExternalAPIsDisabled: getEnvBool("WOLFY_EXTERNAL_APIS_DISABLED", false),
+
+		APIPrices: getEnvPrices(map[string]string{
+			"wolfram":     "WOLFY_COST_PER_WOLFRAM_CALL",
+			"wit":         "WOLFY_COST_PER_WIT_CALL",
+			"translation": "WOLFY_COST_PER_TRANSLATION_CALL",
+		}),
+		WeeklyDigest: getEnvBool("WOLFY_WEEKLY_DIGEST", false),
+
+		SessionTTL:       getEnvDuration("WOLFY_SESSION_TTL", 30*time.Minute),
+		SessionCacheSize: getEnvInt("WOLFY_SESSION_CACHE_SIZE", 10000),
+
+		HistoryLimit:          getEnvInt("WOLFY_HISTORY_LIMIT", 100),
+		HistoryExportMaxBytes: getEnvInt("WOLFY_HISTORY_EXPORT_MAX_BYTES", 256*1024),
+
+		MoreDigitsCommands: getEnvList("WOLFY_MORE_DIGITS_COMMANDS", "more digits", "more precision"),
+
+		AnswerCacheTTL:  getEnvDuration("WOLFY_ANSWER_CACHE_TTL", 0),
+		AnswerCacheSize: getEnvInt("WOLFY_ANSWER_CACHE_SIZE", 1000),
+
+		FeedbackHalfLife:    getEnvDuration("WOLFY_FEEDBACK_HALF_LIFE", 7*24*time.Hour),
+		FeedbackBypassScore: getEnvFloat("WOLFY_FEEDBACK_BYPASS_SCORE", 2),
+
+		AnswerCacheIntents: getEnvMap("WOLFY_ANSWER_CACHE_INTENTS"),
+		BatchQuestionLimit: getEnvInt("WOLFY_BATCH_QUESTION_LIMIT", 5),
+
+		AnswerCandidates:    getEnvInt("WOLFY_ANSWER_CANDIDATES", 3),
+		AnswerDedup:         getEnvBool("WOLFY_ANSWER_DEDUP", true),
+		ConfidenceThreshold: getEnvFloat("WOLFY_CONFIDENCE_THRESHOLD", 0.5),
+		ConfidentThreshold:  getEnvFloat("WOLFY_CONFIDENT_THRESHOLD", 0),
+		HedgeBand:           getEnvFloat("WOLFY_HEDGE_BAND", 0),
+		AnswerAsOfDates:     getEnvBool("WOLFY_ANSWER_AS_OF_DATES", false),
+		WolframRetryTimeout: getEnvDuration("WOLFY_WOLFRAM_RETRY_TIMEOUT", 20*time.Second),
+		AnswerFormats:       getEnvMap("WOLFY_ANSWER_FORMATS"),
+		DisplayPods:         getEnvList("WOLFY_DISPLAY_PODS"),
+
+		MinEntityQueryLength: getEnvInt("WOLFY_MIN_ENTITY_QUERY_LENGTH", 3),
+
+		AnswerFreshness:       getEnvBool("WOLFY_ANSWER_FRESHNESS", false),
+		TimeSensitiveCacheTTL: getEnvDuration("WOLFY_TIME_SENSITIVE_CACHE_TTL", 2*time.Minute),
+
+		SplitCompoundQuestions: getEnvBool("WOLFY_SPLIT_COMPOUND_QUESTIONS", false),
+		FeatureFlags:           getEnvMap("WOLFY_FEATURE_FLAGS"),
+
+		UserCacheTTL:    getEnvDuration("WOLFY_USER_CACHE_TTL", time.Hour),
+		DefaultTimezone: getEnvString("WOLFY_DEFAULT_TIMEZONE", "UTC"),
+		DefaultUnits:    getEnvString("WOLFY_DEFAULT_UNITS", ""),
+		DefaultLocale:   getEnvString("WOLFY_DEFAULT_LOCALE", ""),
+
+		UserCacheSize: getEnvInt("WOLFY_USER_CACHE_SIZE", 5000),
+
+		NumberFormatting:        getEnvBool("WOLFY_NUMBER_FORMATTING", true),
+		NumberSignificantDigits: getEnvInt("WOLFY_NUMBER_SIGNIFICANT_DIGITS", 10),
+		PercentDecimals:         getEnvInt("WOLFY_PERCENT_DECIMALS", 1),
+
+		WelcomeMessages: getEnvBool("WOLFY_WELCOME_MESSAGES", true),
+		MessagesFile:    getEnvString("WOLFY_MESSAGES_FILE", ""),
+
+		LanguagesDir:     getEnvString("WOLFY_LANGUAGES_DIR", ""),
+		ChannelLanguages: getEnvMap("WOLFY_CHANNEL_LANGUAGES"),
+
+		ChannelQuietHours: getEnvMap("WOLFY_CHANNEL_QUIET_HOURS"),
+
+		DisabledHandlers: getEnvList("WOLFY_DISABLED_HANDLERS"),
+
+		OnboardingNote: getEnvBool("WOLFY_ONBOARDING_NOTE", false),
+
+		Personality:          getEnvString("WOLFY_PERSONALITY", "classic"),
+		PersonalityOverrides: getEnvMap("WOLFY_PERSONALITY_OVERRIDES"),
+		PersonalityDir:       getEnvString("WOLFY_PERSONALITY_DIR", ""),
+
+		ChannelIntroCooldown: getEnvDuration("WOLFY_CHANNEL_INTRO_COOLDOWN", 24*time.Hour),
+
+		AdminUsers: getEnvList("WOLFY_ADMIN_USERS"),
+
+		AllowedBots: getEnvList("WOLFY_ALLOWED_BOTS"),
+
+		ArchiveChannel:     getEnvString("WOLFY_ARCHIVE_CHANNEL", ""),
+		ArchiveRedactUsers: getEnvBool("WOLFY_ARCHIVE_REDACT_USERS", true),
+		ArchivePrivate:     getEnvBool("WOLFY_ARCHIVE_PRIVATE", false),
+		ArchiveRateLimit:   getEnvInt("WOLFY_ARCHIVE_RATE_LIMIT", 20),
+
+		ReviewChannel:       getEnvString("WOLFY_REVIEW_CHANNEL", ""),
+		ReviewSamplePercent: getEnvFloat("WOLFY_REVIEW_SAMPLE_PERCENT", 5),
+
+		SlowQueryThreshold: getEnvDuration("WOLFY_SLOW_QUERY_THRESHOLD", 10*time.Second),
+		SlowQueryMaskText:  getEnvBool("WOLFY_SLOW_QUERY_MASK_TEXT", false),
+
+		RTMIdleTimeout: getEnvDuration("WOLFY_RTM_IDLE_TIMEOUT", 5*time.Minute),
+
+		ConnectionMode: strings.ToLower(getEnvString("WOLFY_CONNECTION_MODE", "rtm")),
+		SlackAppToken:  getEnvSecret("SLACK_APP_TOKEN"),
+
+		StagingMode:    getEnvBool("WOLFY_STAGING", false),
+		StagingTag:     getEnvString("WOLFY_STAGING_TAG", "[STAGING]"),
+		StagingChannel: getEnvString("WOLFY_STAGING_CHANNEL", ""),
+
+		Debug:                     getEnvBool("WOLFY_DEBUG", false),
+		PerformanceNotes:          getEnvBool("WOLFY_PERFORMANCE_NOTES", true),
+		UnhandledEventLogInterval: getEnvDuration("WOLFY_UNHANDLED_EVENT_LOG_INTERVAL", 10*time.Minute),
+		UnhandledEventSampleBytes: getEnvInt("WOLFY_UNHANDLED_EVENT_SAMPLE_BYTES", 512),
+		UnhandledEventBufferSize:  getEnvInt("WOLFY_UNHANDLED_EVENT_BUFFER_SIZE", 20),
+
+		ErrorBufferSize: getEnvInt("WOLFY_ERROR_BUFFER_SIZE", 50),
+
+		RecentBufferSize: getEnvInt("WOLFY_RECENT_BUFFER_SIZE", 200),
+		OptedOutUsers:    getEnvList("WOLFY_OPTED_OUT_USERS"),
+
+		HealthStatus:           getEnvBool("WOLFY_HEALTH_STATUS", false),
+		HealthFailureThreshold: getEnvInt("WOLFY_HEALTH_FAILURE_THRESHOLD", 3),
+		HealthDebounce:         getEnvDuration("WOLFY_HEALTH_DEBOUNCE", 2*time.Minute),
+		HealthStatusInterval:   getEnvDuration("WOLFY_HEALTH_STATUS_INTERVAL", time.Minute),
+
+		RecoveryProbes:        getEnvInt("WOLFY_RECOVERY_PROBES", 2),
+		RecoveryProbeInterval: getEnvDuration("WOLFY_RECOVERY_PROBE_INTERVAL", 10*time.Second),
+		RecoveryRampPeriod:    getEnvDuration("WOLFY_RECOVERY_RAMP_PERIOD", 2*time.Minute),
+		RecoveryStartRate:     getEnvInt("WOLFY_RECOVERY_START_RATE", 10),
+		RecoveryFullRate:      getEnvInt("WOLFY_RECOVERY_FULL_RATE", 120),
+
+		MetricsAddr: getEnvString("WOLFY_METRICS_ADDR", ""),
+
+		HTTPToken:          getEnvSecret("WOLFY_HTTP_TOKEN"),
+		SlackSigningSecret: getEnvSecret("SLACK_SIGNING_SECRET"),
+		HTTPRateLimit:      getEnvInt("WOLFY_HTTP_RATE_LIMIT", 120),
+		HTTPMaxBodyBytes:   getEnvInt("WOLFY_HTTP_MAX_BODY_BYTES", 1<<20),
+		HTTPServerTimeout:  getEnvDuration("WOLFY_HTTP_SERVER_TIMEOUT", 30*time.Second),
+
+		SlashCommandPublic: getEnvBool("WOLFY_SLASH_COMMAND_PUBLIC", false),
+
+		MetricsExporter:     strings.ToLower(getEnvString("WOLFY_METRICS_EXPORTER", "prometheus")),
+		StatsDAddr:          getEnvString("WOLFY_STATSD_ADDR", "127.0.0.1:8125"),
+		StatsDPrefix:        getEnvString("WOLFY_STATSD_PREFIX", ""),
+		StatsDFlushInterval: getEnvDuration("WOLFY_STATSD_FLUSH_INTERVAL", 10*time.Second),
+
+		DashboardToken:       getEnvString("WOLFY_DASHBOARD_TOKEN", ""),
+		DashboardMaskQueries: getEnvBool("WOLFY_DASHBOARD_MASK_QUERIES", false),
+	}
+}
+
+// Method for returning the confidence from which answers are given without a hedge: the configured confident threshold,
+// else the intent threshold plus the hedge band
+func (c *Config) confidentThreshold() float64 {
+	if c.ConfidentThreshold != 0 {
+		return c.ConfidentThreshold
+	}
+	return c.ConfidenceThreshold + c.HedgeBand
+}
+
+// Method for checking the configuration for missing, out-of-range, and contradictory settings, reporting all of them at once
+func (c *Config) Validate() error {
+	problems := append(configErrors{}, configParseErrors...)
+	fail := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	// Credentials
+	if c.SlackAccessToken == "" {
+		fail("SLACK_ACCESS_TOKEN: is required")
+	}
+	if c.ConnectionMode != "rtm" && c.ConnectionMode != "socket" {
+		fail("WOLFY_CONNECTION_MODE: must be rtm or socket, got %q", c.ConnectionMode)
+	}
+	if c.ConnectionMode == "socket" && !strings.HasPrefix(c.SlackAppToken, "xapp-") {
+		fail("SLACK_APP_TOKEN: an app-level token (xapp-...) with the connections:write scope is required when WOLFY_CONNECTION_MODE is socket")
+	}
+	if c.WitAccessToken == "" && !c.ExternalAPIsDisabled {
+		fail("WIT_AI_ACCESS_TOKEN: is required unless WOLFY_EXTERNAL_APIS_DISABLED is set")
+	}
+	if c.WolframAppID == "" && !c.ExternalAPIsDisabled {
+		fail("WOLFRAM_APP_ID: is required unless WOLFY_EXTERNAL_APIS_DISABLED is set")
+	}
+
+	// Single settings out of range
+	for _, setting := range []struct {
+		key   string
+		value int
+		min   int
+	}{
+		{"WOLFY_WIT_RETRIES", c.WitRetries, 0},
+		{"WOLFY_MIN_ENTITY_QUERY_LENGTH", c.MinEntityQueryLength, 0},
+		{"WOLFY_WIT_QUEUE_SIZE", c.WitQueueSize, 0},
+		{"WOLFY_WIT_QUEUE_RATE", c.WitQueueRate, 1},
+		{"WOLFY_SLACK_POST_RETRIES", c.SlackPostRetries, 0},
+		{"WOLFY_FLOOD_THRESHOLD", c.FloodThreshold, 0},
+		{"WOLFY_HTTP_RATE_LIMIT", c.HTTPRateLimit, 0},
+		{"WOLFY_HTTP_MAX_BODY_BYTES", c.HTTPMaxBodyBytes, 1},
+		{"WOLFY_BURN_RATE_MIN_EVENTS", c.BurnRateMinEvents, 1},
+		{"WOLFY_LONG_ANSWER_DM_LENGTH", c.LongAnswerDMLength, 0},
+		{"WOLFY_ANSWER_CHUNK_LENGTH", c.AnswerChunkLength, 0},
+		{"WOLFY_THREAD_TRACKING_SIZE", c.ThreadTrackingSize, 0},
+		{"WOLFY_THREAD_CONTEXT_SIZE", c.ThreadContextSize, 0},
+		{"WOLFY_HISTORY_LIMIT", c.HistoryLimit, 0},
+		{"WOLFY_ANSWER_CACHE_SIZE", c.AnswerCacheSize, 0},
+		{"WOLFY_DEDUP_CACHE_SIZE", c.DedupCacheSize, 1},
+		{"WOLFY_SESSION_CACHE_SIZE", c.SessionCacheSize, 1},
+		{"WOLFY_USER_CACHE_SIZE", c.UserCacheSize, 1},
+		{"WOLFY_NUMBER_SIGNIFICANT_DIGITS", c.NumberSignificantDigits, 0},
+		{"WOLFY_PERCENT_DECIMALS", c.PercentDecimals, 0},
+		{"WOLFY_HEALTH_FAILURE_THRESHOLD", c.HealthFailureThreshold, 1},
+		{"WOLFY_RECOVERY_PROBES", c.RecoveryProbes, 0},
+		{"WOLFY_RECOVERY_START_RATE", c.RecoveryStartRate, 1},
+		{"WOLFY_RECOVERY_FULL_RATE", c.RecoveryFullRate, 1},
+		{"WOLFY_WORKERS", c.Workers, 0},
+		{"WOLFY_ERROR_BUFFER_SIZE", c.ErrorBufferSize, 0},
+		{"WOLFY_RECENT_BUFFER_SIZE", c.RecentBufferSize, 0},
+		{"WOLFY_BATCH_QUESTION_LIMIT", c.BatchQuestionLimit, 1},
+		{"WOLFY_SUBSCRIPTION_LIMIT", c.SubscriptionLimit, 0},
+		{"WOLFY_ARCHIVE_RATE_LIMIT", c.ArchiveRateLimit, 1},
+	} {
+		if setting.value < setting.min {
+			fail("%s: must be at least %d, got %d", setting.key, setting.min, setting.value)
+		}
+	}
+	if c.NumberSignificantDigits > 17 {
+		fail("WOLFY_NUMBER_SIGNIFICANT_DIGITS: must be at most 17 (a float64's precision), got %d", c.NumberSignificantDigits)
+	}
+	if c.PercentDecimals > 10 {
+		fail("WOLFY_PERCENT_DECIMALS: must be at most 10, got %d", c.PercentDecimals)
+	}
+	if c.ConfidenceThreshold < 0 || c.ConfidenceThreshold >= 1 {
+		fail("WOLFY_CONFIDENCE_THRESHOLD: must be at least 0 and under 1, got %g", c.ConfidenceThreshold)
+	}
+	if c.ConfidentThreshold != 0 && (c.ConfidentThreshold < c.ConfidenceThreshold || c.ConfidentThreshold > 1) {
+		fail("WOLFY_CONFIDENT_THRESHOLD: must be between WOLFY_CONFIDENCE_THRESHOLD (%g) and 1, got %g", c.ConfidenceThreshold, c.ConfidentThreshold)
+	}
+	if c.HedgeBand < 0 || c.HedgeBand > 1-c.ConfidenceThreshold {
+		fail("WOLFY_HEDGE_BAND: must be between 0 and %g, got %g", 1-c.ConfidenceThreshold, c.HedgeBand)
+	}
+	if c.FeedbackBypassScore < 0 {
+		fail("WOLFY_FEEDBACK_BYPASS_SCORE: must not be negative, got %g", c.FeedbackBypassScore)
+	}
+	if c.ReviewSamplePercent < 0 || c.ReviewSamplePercent > 100 {
+		fail("WOLFY_REVIEW_SAMPLE_PERCENT: must be between 0 and 100, got %g", c.ReviewSamplePercent)
+	}
+	for _, setting := range []struct {
+		key   string
+		value time.Duration
+	}{
+		{"WOLFY_HTTP_TIMEOUT", c.HTTPTimeout},
+		{"WOLFY_SHUTDOWN_DRAIN_TIMEOUT", c.ShutdownDrainTimeout},
+		{"WOLFY_MESSAGE_TIMEOUT", c.MessageTimeout},
+		{"WOLFY_MESSAGE_GROUP_WINDOW", c.MessageGroupWindow},
+		{"WOLFY_ANSWER_TIMEOUT", c.AnswerTimeout},
+		{"WOLFY_STILL_WORKING_AFTER", c.StillWorkingAfter},
+		{"WOLFY_CANCEL_OFFER_AFTER", c.CancelOfferAfter},
+		{"WOLFY_HANDLER_TIMEOUT", c.HandlerTimeout},
+		{"WOLFY_ERROR_MESSAGE_TTL", c.ErrorMessageTTL},
+		{"WOLFY_ANSWER_CACHE_TTL", c.AnswerCacheTTL},
+		{"WOLFY_TIME_SENSITIVE_CACHE_TTL", c.TimeSensitiveCacheTTL},
+		{"WOLFY_FEEDBACK_HALF_LIFE", c.FeedbackHalfLife},
+		{"WOLFY_THREAD_CONTEXT_TTL", c.ThreadContextTTL},
+		{"WOLFY_THREAD_CONTEXT_MAX_AGE", c.ThreadContextMaxAge},
+		{"WOLFY_SESSION_TTL", c.SessionTTL},
+		{"WOLFY_DEDUP_WINDOW", c.DedupWindow},
+		{"WOLFY_REACTION_DEDUP_WINDOW", c.ReactionDedupWindow},
+		{"WOLFY_QUEUE_NOTICE_DELAY", c.QueueNoticeDelay},
+		{"WOLFY_QUEUE_FRESHNESS", c.QueueFreshness},
+		{"WOLFY_WOLFRAM_RETRY_TIMEOUT", c.WolframRetryTimeout},
+		{"WOLFY_RTM_IDLE_TIMEOUT", c.RTMIdleTimeout},
+		{"WOLFY_REPEAT_COOLDOWN", c.RepeatCooldown},
+		{"WOLFY_SLACK_RETRY_MAX_WAIT", c.SlackRetryMaxWait},
+		{"WOLFY_WIT_QUEUE_MAX_WAIT", c.WitQueueMaxWait},
+		{"WOLFY_SLOW_QUERY_THRESHOLD", c.SlowQueryThreshold},
+		{"WOLFY_RECOVERY_RAMP_PERIOD", c.RecoveryRampPeriod},
+	} {
+		if setting.value < 0 {
+			fail("%s: must not be negative, got %s", setting.key, setting.value)
+		}
+	}
+
+	// Settings naming one of a fixed set of values
+	for _, entry := range c.AdminUsers {
+		if id, role := parseAdminEntry(entry); id == "" || adminRoleRanks[role] == 0 {
+			fail("WOLFY_ADMIN_USERS: %q must be a user ID, optionally followed by \":viewer\" or \":operator\"", entry)
+		}
+	}
+	if fallback := strings.ToLower(c.WitRateLimitFallback); fallback != "wolfram" && fallback != "busy" {
+		fail("WOLFY_WIT_RATE_LIMIT_FALLBACK: must be \"wolfram\" or \"busy\", got %q", c.WitRateLimitFallback)
+	}
+	if check := strings.ToLower(c.WitEntityCheck); check != "off" && check != "warn" && check != "strict" {
+		fail("WOLFY_WIT_ENTITY_CHECK: must be \"off\", \"warn\", or \"strict\", got %q", c.WitEntityCheck)
+	}
+	if catchUp := strings.ToLower(c.SubscriptionCatchUp); catchUp != "skip" && catchUp != "run-once" {
+		fail("WOLFY_SUBSCRIPTION_CATCH_UP: must be \"skip\" or \"run-once\", got %q", c.SubscriptionCatchUp)
+	}
+	if _, ok := parseUnits(c.DefaultUnits); c.DefaultUnits != "" && !ok {
+		fail("WOLFY_DEFAULT_UNITS: must be \"metric\" or \"imperial\", got %q", c.DefaultUnits)
+	}
+	if location, err := time.LoadLocation(c.DefaultTimezone); err != nil {
+		fail("WOLFY_DEFAULT_TIMEZONE: %q is not a known IANA timezone", c.DefaultTimezone)
+	} else if _, err := parseMaintenanceWindows(c.MaintenanceWindows, location); err != nil {
+		fail("WOLFY_MAINTENANCE_WINDOWS: %v", err)
+	} else if _, err := parseDMMigrationDeadline(c.DMMigrationNoticeUntil, location); c.DMMigrationNoticeUntil != "" && err != nil {
+		fail("WOLFY_DM_MIGRATION_NOTICE_UNTIL: %v", err)
+	}
+	for channel, spec := range c.ChannelQuietHours {
+		if _, err := parseQuietHours(spec, c.DefaultTimezone); err != nil {
+			fail("WOLFY_CHANNEL_QUIET_HOURS: %s: %v", channel, err)
+		}
+	}
+	for _, entry := range c.DisabledHandlers {
+		if name, forced := parseDisabledHandler(entry); protectedHandlers[name] && !forced {
+			fail("WOLFY_DISABLED_HANDLERS: %s is a core handler; write %s%s to switch it off anyway", name, name, forceDisableSuffix)
+		}
+	}
+
+	// Contradictory combinations
+	if c.TLSInsecureSkipVerify && c.TLSCAFile != "" {
+		fail("WOLFY_TLS_INSECURE_SKIP_VERIFY: can't be combined with WOLFY_TLS_CA_FILE (skipping verification ignores the CA bundle)")
+	}
+	if c.RedisAddr != "" && (c.InstanceClaimTTL <= 0 || c.InstanceClaimTTL <= c.MessageTimeout) {
+		fail("WOLFY_INSTANCE_CLAIM_TTL: must be longer than WOLFY_MESSAGE_TIMEOUT (%s) when WOLFY_REDIS_ADDR is set, or replicas take over messages still being answered, got %s", c.MessageTimeout, c.InstanceClaimTTL)
+	}
+	if c.MessageTimeout > 0 && c.AnswerTimeout >= c.MessageTimeout {
+		fail("WOLFY_ANSWER_TIMEOUT: must be shorter than WOLFY_MESSAGE_TIMEOUT (%s), got %s, so the placeholder could never show", c.MessageTimeout, c.AnswerTimeout)
+	}
+	if c.HTTPTimeout > 0 && c.WolframRetryTimeout >= c.HTTPTimeout {
+		fail("WOLFY_WOLFRAM_RETRY_TIMEOUT: must be shorter than WOLFY_HTTP_TIMEOUT (%s), got %s, or the retry would be cut off", c.HTTPTimeout, c.WolframRetryTimeout)
+	}
+	if c.RTMIdleTimeout > 0 && c.RTMIdleTimeout < 2*rtmPingInterval {
+		fail("WOLFY_RTM_IDLE_TIMEOUT: must be 0 or at least %s (two RTM pings), got %s, or a healthy connection could look stalled", 2*rtmPingInterval, c.RTMIdleTimeout)
+	}
+	if c.FloodThreshold > 0 && c.FloodWindow <= 0 {
+		fail("WOLFY_FLOOD_WINDOW: must be positive when WOLFY_FLOOD_THRESHOLD is set, got %s", c.FloodWindow)
+	}
+	for intent, setting := range c.AnswerCacheIntents {
+		if ttl, err := time.ParseDuration(setting); !strings.EqualFold(setting, "off") && (err != nil || ttl < 0) {
+			fail("WOLFY_ANSWER_CACHE_INTENTS: %s must be a non-negative duration or \"off\", got %q", intent, setting)
+		}
+	}
+	for _, setting := range []struct {
+		key   string
+		value string
+	}{
+		{"WOLFY_CHANNEL_VERBOSITY", c.ChannelVerbosity},
+		{"WOLFY_DM_VERBOSITY", c.DMVerbosity},
+	} {
+		if !isAnswerStyle(setting.value) {
+			fail("%s: must be \"terse\", \"normal\", or \"detailed\", got %q", setting.key, setting.value)
+		}
+	}
+	for language, token := range c.WitLanguageTokens {
+		if _, known := languageNames[language]; !known || token == "" {
+			fail("WOLFY_WIT_LANGUAGE_TOKENS: %q must be a known language code with a token", language)
+		}
+	}
+	if len(c.BurnRateAlerts) > 0 && c.BurnRateShortWindow >= c.BurnRateLongWindow {
+		fail("WOLFY_BURN_RATE_SHORT_WINDOW: must be shorter than WOLFY_BURN_RATE_LONG_WINDOW (%s), got %s", c.BurnRateLongWindow, c.BurnRateShortWindow)
+	}
+	if c.RecoveryProbes > 0 && c.RecoveryProbeInterval <= 0 {
+		fail("WOLFY_RECOVERY_PROBE_INTERVAL: must be positive when WOLFY_RECOVERY_PROBES is set, got %s", c.RecoveryProbeInterval)
+	}
+	if c.RecoveryFullRate < c.RecoveryStartRate {
+		fail("WOLFY_RECOVERY_FULL_RATE: must be at least WOLFY_RECOVERY_START_RATE (%d), got %d", c.RecoveryStartRate, c.RecoveryFullRate)
+	}
+	if c.HealthStatus && c.HealthStatusInterval <= 0 {
+		fail("WOLFY_HEALTH_STATUS_INTERVAL: must be positive when WOLFY_HEALTH_STATUS is on, got %s", c.HealthStatusInterval)
+	}
+	switch c.MetricsExporter {
+	case "prometheus", "statsd", "both", "none":
+	default:
+		fail("WOLFY_METRICS_EXPORTER: must be prometheus, statsd, both, or none, got %q", c.MetricsExporter)
+	}
+	if (c.MetricsExporter == "statsd" || c.MetricsExporter == "both") && c.StatsDFlushInterval <= 0 {
+		fail("WOLFY_STATSD_FLUSH_INTERVAL: must be positive when exporting to StatsD, got %s", c.StatsDFlushInterval)
+	}
+	if c.MetricsAddr != "" && c.HTTPServerTimeout <= 0 {
+		fail("WOLFY_HTTP_SERVER_TIMEOUT: must be positive when WOLFY_METRICS_ADDR is set, got %s", c.HTTPServerTimeout)
+	}
+	if c.DashboardToken != "" && c.MetricsAddr == "" {
+		fail("WOLFY_DASHBOARD_TOKEN: needs WOLFY_METRICS_ADDR, since the dashboard is served on the metrics listener")
+	}
+	if c.AnnouncementsEnabled && c.AdminChannel == "" && configEnv("WOLFY_ANNOUNCEMENTS_ENABLED") != "" {
+		fail("WOLFY_ANNOUNCEMENTS_ENABLED: needs WOLFY_ADMIN_CHANNEL to announce to")
+	}
+	if c.WeeklyDigest && c.AdminChannel == "" {
+		fail("WOLFY_WEEKLY_DIGEST: needs WOLFY_ADMIN_CHANNEL to post the digest to")
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return problems
+}
+
+// Global function for noting a setting that was set but could not be parsed
+func noteConfigParseError(key string, kind string) {
+	configParseErrors = append(configParseErrors, fmt.Sprintf("%s: %q is not a valid %s", key, configEnv(key), kind))
+}
+
+// Global function for reading a setting's raw value: its imported override when it has one, else the environment's
+func configEnv(key string) string {
+	if value, ok := configOverrides[key]; ok {
+		return value
+	}
+	return os.Getenv(key)
+}
+
+// Global function for recording a setting's effective value, hiding credentials
+func noteConfigValue(key string, value string) {
+	if configValues == nil {
+		return
+	}
+	if secretSettingPattern.MatchString(key) && value != "" {
+		value = secretPlaceholder
+	}
+	configValues[key] = value
+}
+
+// Global function for reading a credential from its environment variable or the secret file named by "<KEY>_FILE"
+// (never from imported overrides)
+func getEnvSecret(key string) string {
+	value, err := readSecret(key)
+	if err != nil {
+		configParseErrors = append(configParseErrors, fmt.Sprintf("%s_FILE: unable to read %q (%v)", key, os.Getenv(key+"_FILE"), err))
+	}
+	noteConfigValue(key, value)
+	return value
+}
+
+// Global function for reading a string setting with a fallback value
+func getEnvString(key string, fallback string) string {
+	value := configEnv(key)
+	if value == "" {
+		value = fallback
+	}
+	noteConfigValue(key, value)
+	return value
+}
+
+// Global function for reading a boolean setting with a fallback value
+func getEnvBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(configEnv(key))
+	if err != nil {
+		if configEnv(key) != "" {
+			noteConfigParseError(key, "boolean (true/false)")
+		}
+		value = fallback
+	}
+	noteConfigValue(key, strconv.FormatBool(value))
+	return value
+}
+
+// Global function for reading an integer setting with a fallback value
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(configEnv(key))
+	if err != nil {
+		if configEnv(key) != "" {
+			noteConfigParseError(key, "integer")
+		}
+		value = fallback
+	}
+	noteConfigValue(key, strconv.Itoa(value))
+	return value
+}
+
+// Global function for reading a decimal setting with a fallback value
+func getEnvFloat(key string, fallback float64) float64 {
+	value, err := strconv.ParseFloat(configEnv(key), 64)
+	if err != nil {
+		if configEnv(key) != "" {
+			noteConfigParseError(key, "number")
+		}
+		value = fallback
+	}
+	noteConfigValue(key, strconv.FormatFloat(value, 'g', -1, 64))
+	return value
+}
+
+// Global function for reading a duration setting (e.g. "30s") with a fallback value
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(configEnv(key))
+	if err != nil {
+		if configEnv(key) != "" {
+			noteConfigParseError(key, "duration (e.g. \"30s\")")
+		}
+		value = fallback
+	}
+	noteConfigValue(key, value.String())
+	return value
+}
+
+// Global function for reading the optional per-call API prices, leaving out any that are unset or invalid
+func getEnvPrices(keys map[string]string) map[string]float64 {
+	prices := map[string]float64{}
+	for api, key := range keys {
+		noteConfigValue(key, configEnv(key))
+		if price, err := strconv.ParseFloat(configEnv(key), 64); err == nil && price >= 0 {
+			prices[api] = price
+		}
+	}
+	return prices
+}
+
+// Global function for reading a comma-separated list setting, with optional fallback values
+func getEnvList(key string, fallback ...string) []string {
+	var values []string
+	for _, value := range strings.Split(configEnv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		values = fallback
+	}
+	noteConfigValue(key, strings.Join(values, ","))
+	return values
+}
+
+// Global function for reading a comma-separated list of "key=value" pairs
+func getEnvMap(key string) map[string]string {
+	values := map[string]string{}
+	for _, pair := range getEnvList(key) {
+		if i := strings.Index(pair, "="); i > 0 {
+			values[strings.TrimSpace(pair[:i])] = strings.TrimSpace(pair[i+1:])
+		}
+	}
+	return values
+}
@@ -0,0 +1,90 @@
+//////////////////////////////////////////////////
+// Configuration Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing configuration validation
+import (
+	"reflect" // Permits comparison of reported problems
+	"testing" // Permits Go testing
+	"time"    // Permits duration settings
+)
+
+// Global function for building a valid configuration from the defaults, with every credential set
+func validTestConfig() Config {
+	valid := *config
+	valid.SlackAccessToken, valid.WitAccessToken, valid.WolframAppID = "xoxb-test", "wit-test", "wolfram-test"
+	valid.ExternalAPIsDisabled = false
+	return valid
+}
+
+// Test for accepting the defaults once credentials are set
+func TestValidateDefaults(t *testing.T) {
+	valid := validTestConfig()
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate on the defaults = %v; want no problems", err)
+	}
+}
+
+// Test for reporting every invalid setting, each with a message naming the offending field
+func TestValidateProblems(t *testing.T) {
+	cases := []struct {
+		name   string
+		change func(*Config)
+		want   configErrors
+	}{
+		{"missing token", func(c *Config) { c.SlackAccessToken = "" }, configErrors{
+			"SLACK_ACCESS_TOKEN: is required",
+		}},
+		{"threshold out of range", func(c *Config) { c.ConfidenceThreshold = -0.2 }, configErrors{
+			"WOLFY_CONFIDENCE_THRESHOLD: must be at least 0 and under 1, got -0.2",
+		}},
+		{"negative count and duration", func(c *Config) { c.WitRetries = -1; c.HTTPTimeout = -time.Second }, configErrors{
+			"WOLFY_WIT_RETRIES: must be at least 0, got -1",
+			"WOLFY_HTTP_TIMEOUT: must not be negative, got -1s",
+		}},
+		{"unknown choice", func(c *Config) { c.WitEntityCheck = "loud" }, configErrors{
+			`WOLFY_WIT_ENTITY_CHECK: must be "off", "warn", or "strict", got "loud"`,
+		}},
+		{"contradictory TLS settings", func(c *Config) { c.TLSInsecureSkipVerify = true; c.TLSCAFile = "ca.pem" }, configErrors{
+			"WOLFY_TLS_INSECURE_SKIP_VERIFY: can't be combined with WOLFY_TLS_CA_FILE (skipping verification ignores the CA bundle)",
+		}},
+		{"answer timeout past message timeout", func(c *Config) { c.MessageTimeout = 10 * time.Second; c.AnswerTimeout = 10 * time.Second }, configErrors{
+			"WOLFY_ANSWER_TIMEOUT: must be shorter than WOLFY_MESSAGE_TIMEOUT (10s), got 10s, so the placeholder could never show",
+		}},
+		{"several at once", func(c *Config) {
+			c.ConnectionMode = "carrier-pigeon"
+			c.DefaultTimezone = "Mars/Olympus"
+			c.ReviewSamplePercent = 101
+		}, configErrors{
+			`WOLFY_CONNECTION_MODE: must be rtm or socket, got "carrier-pigeon"`,
+			"WOLFY_REVIEW_SAMPLE_PERCENT: must be between 0 and 100, got 101",
+			`WOLFY_DEFAULT_TIMEZONE: "Mars/Olympus" is not a known IANA timezone`,
+		}},
+	}
+	for _, c := range cases {
+		invalid := validTestConfig()
+		c.change(&invalid)
+		err := invalid.Validate()
+		if problems, _ := err.(configErrors); !reflect.DeepEqual(problems, c.want) {
+			t.Errorf("%s: Validate = %q; want %q", c.name, problems, c.want)
+		}
+	}
+}
+
+// Test for reporting settings that were set but could not be parsed alongside the invalid ones
+func TestValidateParseErrors(t *testing.T) {
+	previous := configParseErrors
+	t.Cleanup(func() { configParseErrors = previous })
+	configParseErrors = nil
+	t.Setenv("WOLFY_WIT_RETRIES", "several")
+	getEnvInt("WOLFY_WIT_RETRIES", 2)
+
+	valid := validTestConfig()
+	if problems, _ := valid.Validate().(configErrors); len(problems) != 1 || problems[0] != configParseErrors[0] {
+		t.Errorf("Validate after a parse error = %q; want only %q", problems, configParseErrors)
+	}
+}
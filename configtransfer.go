@@ -0,0 +1,514 @@
+//////////////////////////////////////////////////
+// Config Transfer Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for exporting the effective configuration as YAML and importing it into another workspace
+import (
+	"fmt"       // Permits formatting of the document, diffs, and problems
+	"io"        // Permits capping of downloaded files
+	"io/ioutil" // Permits reading and writing of the overrides file
+	"log"       // Permits console logging
+	"net/http"  // Permits downloading of uploaded files
+	"os"        // Permits locating of the overrides file
+	"regexp"    // Permits checking of channel IDs
+	"sort"      // Permits stable ordering of settings and diffs
+	"strconv"   // Permits quoting and unquoting of values
+	"strings"   // Permits line-by-line parsing
+	"sync"      // Permits serializing candidate loads and guarding pending imports
+	"time"      // Permits expiry of pending imports
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constants holding what exported credentials are replaced with, how long an import waits for confirmation, the
+// largest file accepted, the only host uploads are fetched from, and the default overrides file
+const (
+	secretPlaceholder          = "<secret>"
+	configImportTTL            = 10 * time.Minute
+	configFileLimit            = 256 * 1024
+	slackFilesHost             = "https://files.slack.com/"
+	defaultConfigOverridesFile = "wolfybot.overrides.yaml"
+)
+
+// Global pattern recognizing the channel IDs accepted under "channels"
+var configChannelPattern = regexp.MustCompile(`^[CG][A-Z0-9]+$`)
+
+// Global struct holding a parsed configuration document: its settings, its per-channel settings, and the line each
+// came from, so problems found later can point at it
+type configDocument struct {
+	settings map[string]string
+	channels map[string]map[string]string
+	lines    map[string]int
+}
+
+// Global struct holding an import waiting for its admin's confirmation
+type pendingConfigImport struct {
+	document configDocument
+	at       time.Time
+}
+
+// Global lock serializing loads of candidate configurations, which borrow the loader's globals, and the imports
+// waiting for confirmation by admin
+var (
+	configLoadMu         sync.Mutex
+	pendingConfigImports = map[string]pendingConfigImport{}
+	pendingConfigMu      sync.Mutex
+)
+
+// Global function for locating the file imported settings persist in (read before anything else, so it can't be
+// overridden itself)
+func configOverridesPath() string {
+	if path := os.Getenv("WOLFY_CONFIG_OVERRIDES_FILE"); path != "" {
+		return path
+	}
+	return defaultConfigOverridesFile
+}
+
+// Global function for reading the imported settings at startup, checked the same way an import is
+func readConfigOverrides() (map[string]string, configErrors) {
+	data, err := ioutil.ReadFile(configOverridesPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, configErrors{fmt.Sprintf("WOLFY_CONFIG_OVERRIDES_FILE: unable to read %s (%v)", configOverridesPath(), err)}
+	}
+	document, problems := parseConfigDocument(string(data))
+	for key, value := range document.settings {
+		if secretSettingPattern.MatchString(key) && value != secretPlaceholder {
+			problems = append(problems, fmt.Sprintf("line %d: %s is a credential; set it in the environment instead", document.lines[key], key))
+		}
+		if secretSettingPattern.MatchString(key) {
+			delete(document.settings, key)
+		}
+	}
+	for i := range problems {
+		problems[i] = configOverridesPath() + ": " + problems[i]
+	}
+	return document.settings, problems
+}
+
+// Global function for parsing a configuration document: a small subset of YAML with a "settings" mapping of setting
+// names to values and a "channels" mapping of channel IDs to their settings, indented by two spaces per level. Values
+// may be double-quoted (with Go escapes), single-quoted, or plain; full-line comments are ignored.
+func parseConfigDocument(text string) (configDocument, configErrors) {
+	document := configDocument{settings: map[string]string{}, channels: map[string]map[string]string{}, lines: map[string]int{}}
+	var problems configErrors
+	section, channel := "", ""
+	for i, raw := range strings.Split(text, "\n") {
+		line := i + 1
+		trimmed := strings.TrimSpace(raw)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indented := strings.TrimLeft(raw, " ")
+		if strings.HasPrefix(indented, "\t") {
+			problems = append(problems, fmt.Sprintf("line %d: indent with spaces, not tabs", line))
+			continue
+		}
+		indent := len(raw) - len(indented)
+		colon := strings.Index(trimmed, ":")
+		if colon <= 0 {
+			problems = append(problems, fmt.Sprintf("line %d: expected \"name: value\"", line))
+			continue
+		}
+		key, rest := strings.TrimSpace(trimmed[:colon]), strings.TrimSpace(trimmed[colon+1:])
+
+		switch {
+		case indent == 0:
+			section, channel = key, ""
+			if key != "settings" && key != "channels" {
+				problems = append(problems, fmt.Sprintf("line %d: unknown section %q (expected settings or channels)", line, key))
+				section = ""
+			} else if rest != "" {
+				problems = append(problems, fmt.Sprintf("line %d: %s takes its entries on the following lines", line, key))
+			}
+		case indent == 2 && section == "settings":
+			value, err := parseConfigValue(rest)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("line %d: %s: %v", line, key, err))
+				continue
+			}
+			if _, duplicate := document.settings[key]; duplicate {
+				problems = append(problems, fmt.Sprintf("line %d: %s is set twice (first on line %d)", line, key, document.lines[key]))
+				continue
+			}
+			document.settings[key], document.lines[key] = value, line
+		case indent == 2 && section == "channels":
+			if !configChannelPattern.MatchString(key) {
+				problems = append(problems, fmt.Sprintf("line %d: %q is not a channel ID (like C0123ABCD)", line, key))
+				channel = ""
+				continue
+			}
+			if rest != "" {
+				problems = append(problems, fmt.Sprintf("line %d: %s takes its settings on the following lines", line, key))
+			}
+			channel = key
+			if _, ok := document.channels[channel]; !ok {
+				document.channels[channel] = map[string]string{}
+			}
+		case indent == 4 && section == "channels" && channel != "":
+			value, err := parseConfigValue(rest)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("line %d: %s: %v", line, key, err))
+				continue
+			}
+			if key != "language" {
+				problems = append(problems, fmt.Sprintf("line %d: unknown channel setting %q (expected language)", line, key))
+				continue
+			}
+			if value != "" && !languageCodePattern.MatchString(value) {
+				problems = append(problems, fmt.Sprintf("line %d: %q is not a language code (like fr or pt-BR)", line, value))
+				continue
+			}
+			document.channels[channel][key], document.lines[channel+"."+key] = value, line
+		case section == "":
+			// Entries of an unknown section were already reported with it
+		default:
+			problems = append(problems, fmt.Sprintf("line %d: unexpected indentation (two spaces per level)", line))
+		}
+	}
+	return document, problems
+}
+
+// Global function for reading a scalar value: double-quoted, single-quoted, or plain (up to any trailing comment)
+func parseConfigValue(raw string) (string, error) {
+	switch {
+	case strings.HasPrefix(raw, `"`):
+		end := strings.LastIndex(raw, `"`)
+		if end == 0 || (end < len(raw)-1 && !strings.HasPrefix(strings.TrimSpace(raw[end+1:]), "#")) {
+			return "", fmt.Errorf("unterminated or malformed quoted value")
+		}
+		value, err := strconv.Unquote(raw[:end+1])
+		if err != nil {
+			return "", fmt.Errorf("invalid quoted value (%v)", err)
+		}
+		return value, nil
+	case strings.HasPrefix(raw, "'"):
+		end := strings.LastIndex(raw, "'")
+		if end == 0 {
+			return "", fmt.Errorf("unterminated quoted value")
+		}
+		return strings.Replace(raw[1:end], "''", "'", -1), nil
+	}
+	if i := strings.Index(raw, " #"); i >= 0 {
+		raw = raw[:i]
+	}
+	return strings.TrimSpace(raw), nil
+}
+
+// Global function for building and validating the configuration an import would produce, with the same loader and
+// checks as startup, returning its effective values and anything wrong with it
+func candidateConfig(overrides map[string]string) (map[string]string, configErrors) {
+	configLoadMu.Lock()
+	defer configLoadMu.Unlock()
+
+	savedOverrides, savedValues, savedErrors := configOverrides, configValues, configParseErrors
+	defer func() { configOverrides, configValues, configParseErrors = savedOverrides, savedValues, savedErrors }()
+
+	configOverrides, configParseErrors = overrides, nil
+	candidate := readConfig()
+	values := configValues
+	if err := candidate.Validate(); err != nil {
+		return values, err.(configErrors)
+	}
+	return values, nil
+}
+
+// Global function for copying the effective settings, under the loader's lock
+func currentConfigValues() map[string]string {
+	configLoadMu.Lock()
+	defer configLoadMu.Unlock()
+	return copyConfigSettings(configValues)
+}
+
+// Global function for copying the imported settings merged with a document's, under the loader's lock
+func mergedConfigOverrides(settings map[string]string) map[string]string {
+	configLoadMu.Lock()
+	defer configLoadMu.Unlock()
+	merged := copyConfigSettings(configOverrides)
+	for key, value := range settings {
+		merged[key] = value
+	}
+	return merged
+}
+
+// Global function for copying a map of settings
+func copyConfigSettings(settings map[string]string) map[string]string {
+	copied := make(map[string]string, len(settings))
+	for key, value := range settings {
+		copied[key] = value
+	}
+	return copied
+}
+
+// Global function for checking an uploaded document against the settings the bot has: unknown settings and credentials
+// are refused (credential placeholders are dropped, keeping the current ones), and the resulting configuration must
+// pass the same validation as startup. Returns the settings to apply along with the candidate's effective values.
+func validateConfigImport(document configDocument) (configDocument, map[string]string, configErrors) {
+	current := currentConfigValues()
+	var problems configErrors
+	for key, value := range document.settings {
+		_, known := current[key]
+		switch {
+		case !known:
+			problems = append(problems, fmt.Sprintf("line %d: unknown setting %s", document.lines[key], key))
+		case secretSettingPattern.MatchString(key) && value != secretPlaceholder && value != "":
+			problems = append(problems, fmt.Sprintf("line %d: %s is a credential; set it in the environment instead", document.lines[key], key))
+		}
+		if secretSettingPattern.MatchString(key) || !known {
+			delete(document.settings, key)
+		}
+	}
+
+	values, invalid := candidateConfig(mergedConfigOverrides(document.settings))
+	for _, problem := range invalid {
+		// Pointing validation problems at the line of the setting they name, when the document set it
+		if key := strings.SplitN(problem, ":", 2)[0]; document.lines[key] > 0 {
+			problem = fmt.Sprintf("line %d: %s", document.lines[key], problem)
+		}
+		problems = append(problems, problem)
+	}
+	sort.Strings(problems)
+	return document, values, problems
+}
+
+// Global function for describing what an import would change: each setting whose effective value differs, and each
+// channel setting
+func describeConfigChanges(document configDocument, candidate map[string]string) []string {
+	current := currentConfigValues()
+	var changes []string
+	for key, value := range candidate {
+		if current[key] != value {
+			changes = append(changes, fmt.Sprintf("`%s`: %s → %s", key, displayConfigValue(current[key]), displayConfigValue(value)))
+		}
+	}
+	for channel, settings := range document.channels {
+		if language, ok := settings["language"]; ok && language != channelLanguage(channel) {
+			changes = append(changes, fmt.Sprintf("<#%s> language: %s → %s", channel, displayConfigValue(channelLanguage(channel)), displayConfigValue(language)))
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
+
+// Global function for showing a value in a diff, marking empty ones
+func displayConfigValue(value string) string {
+	if value == "" {
+		return "_(unset)_"
+	}
+	return "`" + value + "`"
+}
+
+// Global function for rendering a configuration document from the effective settings and the channels' runtime
+// languages, credentials replaced by placeholders
+func renderConfigDocument(values map[string]string, exporter string) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	lines := []string{
+		fmt.Sprintf("# WolfyBot configuration exported %s by %s.", time.Now().UTC().Format(time.RFC3339), exporter),
+		"# Credentials read " + strconv.Quote(secretPlaceholder) + "; importing them keeps the importing bot's own.",
+		"settings:",
+	}
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf("  %s: %s", key, strconv.Quote(values[key])))
+	}
+	channels := store.keys(channelLanguagesBucket)
+	sort.Strings(channels)
+	if len(channels) > 0 {
+		lines = append(lines, "channels:")
+		for _, channel := range channels {
+			lines = append(lines, "  "+channel+":", "    language: "+strconv.Quote(channelLanguage(channel)))
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Global function for writing the merged settings to the overrides file, replacing it whole
+func writeConfigOverrides(settings map[string]string) error {
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	lines := []string{"# Settings imported with \"!config import\", taking precedence over the environment.", "settings:"}
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf("  %s: %s", key, strconv.Quote(settings[key])))
+	}
+
+	path := configOverridesPath()
+	if err := ioutil.WriteFile(path+".tmp", []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// Global function for downloading a file uploaded to the bot, only from Slack's file host and only up to the limit
+func downloadSlackFile(file slack.File) (string, error) {
+	if file.Size > configFileLimit {
+		return "", fmt.Errorf("the file is larger than %d KiB", configFileLimit/1024)
+	}
+	if !strings.HasPrefix(file.URLPrivateDownload, slackFilesHost) {
+		return "", fmt.Errorf("refusing the file URL %q", file.URLPrivateDownload)
+	}
+	req, err := http.NewRequest("GET", file.URLPrivateDownload, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+currentCredentials.Load().(apiCredentials).slackToken)
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("file download returned status %s", res.Status)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(res.Body, configFileLimit+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > configFileLimit {
+		return "", fmt.Errorf("the file is larger than %d KiB", configFileLimit/1024)
+	}
+	return string(data), nil
+}
+
+// Admin command exporting the configuration to the admin's DM, or importing one uploaded there
+func runAdminConfig(event *slack.MessageEvent, args []string) string {
+	usage := "Usage: `!config export`, or upload a YAML file to me in a DM with the comment `!config import`, then `!config import confirm` (or `cancel`)"
+	switch {
+	case len(args) == 1 && strings.EqualFold(args[0], "export"):
+		return exportConfig(event)
+	case len(args) == 1 && strings.EqualFold(args[0], "import"):
+		return stageConfigImport(event)
+	case len(args) == 2 && strings.EqualFold(args[0], "import") && strings.EqualFold(args[1], "confirm"):
+		return applyConfigImport(event)
+	case len(args) == 2 && strings.EqualFold(args[0], "import") && strings.EqualFold(args[1], "cancel"):
+		pendingConfigMu.Lock()
+		_, pending := pendingConfigImports[event.User]
+		delete(pendingConfigImports, event.User)
+		pendingConfigMu.Unlock()
+		if !pending {
+			return "You have no import waiting for confirmation."
+		}
+		return "Import cancelled; nothing was changed."
+	}
+	return usage
+}
+
+// Global function for uploading the effective configuration to the admin's DM
+func exportConfig(event *slack.MessageEvent) string {
+	_, _, channelID, err := slackAPI().OpenIMChannel(event.User)
+	if err != nil {
+		log.Printf("CONFIG ERROR: Unable to open DM with %s for a config export.\nError Details: %v", event.User, err)
+		return "Sorry, I couldn't prepare the export right now. :-("
+	}
+	_, err = slackAPI().UploadFile(slack.FileUploadParameters{
+		Content:        renderConfigDocument(currentConfigValues(), "<@"+event.User+">"),
+		Filetype:       "yaml",
+		Filename:       "wolfybot-config.yaml",
+		Title:          "WolfyBot configuration",
+		InitialComment: "Here's my effective configuration, credentials left out. Upload it to another WolfyBot in a DM with the comment `!config import`.",
+		Channels:       []string{channelID},
+	})
+	if err != nil {
+		log.Printf("CONFIG ERROR: Unable to upload a config export for %s.\nError Details: %v", event.User, err)
+		return "Sorry, I couldn't upload the export right now. :-("
+	}
+	metrics.inc("wolfy_config_transfers_total", "direction", "export")
+	log.Printf("ADMIN: %s exported the configuration.", event.User)
+	return "Sent you the configuration as `wolfybot-config.yaml`."
+}
+
+// Global function for checking an uploaded document and showing what it would change, holding it for confirmation
+func stageConfigImport(event *slack.MessageEvent) string {
+	if !isDirectMessage(event.Channel) {
+		return "Configuration can only be imported in a DM with me, so it never passes through a shared channel."
+	}
+	if len(event.Msg.Files) != 1 {
+		return "Upload exactly one YAML file (like one from `!config export`) with the comment `!config import`."
+	}
+	text, err := downloadSlackFile(event.Msg.Files[0])
+	if err != nil {
+		log.Printf("CONFIG ERROR: Unable to download the config import from %s.\nError Details: %v", event.User, err)
+		return fmt.Sprintf("Sorry, I couldn't read that file: %v", err)
+	}
+
+	document, problems := parseConfigDocument(text)
+	document, candidate, invalid := validateConfigImport(document)
+	if problems = append(problems, invalid...); len(problems) > 0 {
+		metrics.inc("wolfy_config_transfers_total", "direction", "import", "result", "invalid")
+		return "That configuration can't be imported; nothing was changed:\n• " + strings.Join(problems, "\n• ")
+	}
+	changes := describeConfigChanges(document, candidate)
+	if len(changes) == 0 {
+		return "That configuration matches mine; there's nothing to change."
+	}
+
+	pendingConfigMu.Lock()
+	pendingConfigImports[event.User] = pendingConfigImport{document: document, at: time.Now()}
+	pendingConfigMu.Unlock()
+	return fmt.Sprintf("Importing it would change:\n• %s\nRun `!config import confirm` within %s to apply it, or `!config import cancel`.", strings.Join(changes, "\n• "), configImportTTL)
+}
+
+// Global function for applying a confirmed import: settings persist to the overrides file and take effect on restart
+// (they are read once at startup), while channel settings apply at once
+func applyConfigImport(event *slack.MessageEvent) string {
+	pendingConfigMu.Lock()
+	pending, ok := pendingConfigImports[event.User]
+	delete(pendingConfigImports, event.User)
+	pendingConfigMu.Unlock()
+	if !ok || time.Since(pending.at) > configImportTTL {
+		return "You have no import waiting for confirmation; upload the file again with `!config import`."
+	}
+
+	// Re-checking against the configuration as it is now, in case another import landed meanwhile
+	document, _, problems := validateConfigImport(pending.document)
+	if len(problems) > 0 {
+		return "That configuration no longer validates; nothing was changed:\n• " + strings.Join(problems, "\n• ")
+	}
+
+	if len(document.settings) > 0 {
+		settings := mergedConfigOverrides(document.settings)
+		if err := writeConfigOverrides(settings); err != nil {
+			log.Printf("CONFIG ERROR: Unable to write the overrides file %s.\nError Details: %v", configOverridesPath(), err)
+			return "Sorry, I couldn't save the settings; nothing was changed. :-("
+		}
+		// Later imports build on this one; the running configuration itself only changes on restart
+		configLoadMu.Lock()
+		configOverrides = settings
+		configLoadMu.Unlock()
+	}
+	for channel, channelSettings := range document.channels {
+		language, ok := channelSettings["language"]
+		var err error
+		switch {
+		case !ok:
+			continue
+		case language == "":
+			store.delete(channelLanguagesBucket, channel)
+		default:
+			err = store.put(channelLanguagesBucket, channel, language)
+		}
+		if err != nil {
+			log.Printf("CONFIG ERROR: Unable to persist the language for %s.\nError Details: %v", channel, err)
+		}
+	}
+
+	metrics.inc("wolfy_config_transfers_total", "direction", "import", "result", "applied")
+	log.Printf("ADMIN: %s imported %d settings and %d channels' settings.", event.User, len(document.settings), len(document.channels))
+	reply := "Imported. Channel settings apply now"
+	if len(document.settings) > 0 {
+		reply += fmt.Sprintf("; the %d settings are saved to `%s` and take effect when I restart", len(document.settings), configOverridesPath())
+	}
+	return reply + "."
+}
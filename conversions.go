@@ -0,0 +1,134 @@
+//////////////////////////////////////////////////
+// Unit Conversions Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for answering common unit conversions locally
+import (
+	"fmt"     // Permits string formatting of replies
+	"regexp"  // Permits matching of conversion phrasings
+	"strconv" // Permits parsing and formatting of quantities
+	"strings" // Permits string normalization
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global struct describing a unit as a linear mapping onto its dimension's base unit: base = (value + offset) * factor
+type conversionUnit struct {
+	symbol    string
+	dimension string
+	factor    float64
+	offset    float64
+}
+
+// Global patterns recognizing "100 F in C and K" style conversions and splitting their targets
+var (
+	conversionPattern       = regexp.MustCompile(`(?i)^\s*(?:(?:what\s+is|what's|whats|convert)\s+)?(-?\d+(?:\.\d+)?)\s*(.+?)\s+(?:in|to|into|as)\s+(.+?)\s*[?.!]*\s*$`)
+	conversionTargetPattern = regexp.MustCompile(`(?i)\s*(?:,\s*(?:and\s+)?|\s+and\s+|&)\s*`)
+	unitPrefixPattern       = regexp.MustCompile(`^(?:degrees?\s+|deg\s+|°|º)`)
+)
+
+// Global table of units we convert locally by every name they are asked for (anything else falls back to Wolfram)
+var conversionUnits = map[string]conversionUnit{}
+
+// Registering the supported units and their aliases
+func init() {
+	for _, unit := range []struct {
+		conversionUnit
+		aliases []string
+	}{
+		{conversionUnit{"°C", "temperature", 1, 273.15}, []string{"c", "celsius", "centigrade"}},
+		{conversionUnit{"°F", "temperature", 5.0 / 9.0, 459.67}, []string{"f", "fahrenheit"}},
+		{conversionUnit{"K", "temperature", 1, 0}, []string{"k", "kelvin", "kelvins"}},
+
+		{conversionUnit{"mm", "length", 0.001, 0}, []string{"mm", "millimeter", "millimeters", "millimetre", "millimetres"}},
+		{conversionUnit{"cm", "length", 0.01, 0}, []string{"cm", "centimeter", "centimeters", "centimetre", "centimetres"}},
+		{conversionUnit{"m", "length", 1, 0}, []string{"m", "meter", "meters", "metre", "metres"}},
+		{conversionUnit{"km", "length", 1000, 0}, []string{"km", "kilometer", "kilometers", "kilometre", "kilometres"}},
+		{conversionUnit{"in", "length", 0.0254, 0}, []string{"inch", "inches", "\""}},
+		{conversionUnit{"ft", "length", 0.3048, 0}, []string{"ft", "foot", "feet", "'"}},
+		{conversionUnit{"yd", "length", 0.9144, 0}, []string{"yd", "yard", "yards"}},
+		{conversionUnit{"mi", "length", 1609.344, 0}, []string{"mi", "mile", "miles"}},
+
+		{conversionUnit{"g", "mass", 0.001, 0}, []string{"g", "gram", "grams"}},
+		{conversionUnit{"kg", "mass", 1, 0}, []string{"kg", "kilogram", "kilograms", "kilo", "kilos"}},
+		{conversionUnit{"oz", "mass", 0.028349523125, 0}, []string{"oz", "ounce", "ounces"}},
+		{conversionUnit{"lb", "mass", 0.45359237, 0}, []string{"lb", "lbs", "pound", "pounds"}},
+		{conversionUnit{"st", "mass", 6.35029318, 0}, []string{"st", "stone", "stones"}},
+
+		{conversionUnit{"mL", "volume", 0.001, 0}, []string{"ml", "milliliter", "milliliters", "millilitre", "millilitres"}},
+		{conversionUnit{"L", "volume", 1, 0}, []string{"l", "liter", "liters", "litre", "litres"}},
+		{conversionUnit{"fl oz", "volume", 0.0295735295625, 0}, []string{"fl oz", "fluid ounce", "fluid ounces"}},
+		{conversionUnit{"cup", "volume", 0.2365882365, 0}, []string{"cup", "cups"}},
+		{conversionUnit{"gal", "volume", 3.785411784, 0}, []string{"gal", "gallon", "gallons"}},
+
+		{conversionUnit{"m/s", "speed", 1, 0}, []string{"m/s", "meters per second", "metres per second"}},
+		{conversionUnit{"km/h", "speed", 1000.0 / 3600.0, 0}, []string{"km/h", "kph", "kmh", "kilometers per hour", "kilometres per hour"}},
+		{conversionUnit{"mph", "speed", 0.44704, 0}, []string{"mph", "miles per hour"}},
+		{conversionUnit{"kn", "speed", 1852.0 / 3600.0, 0}, []string{"kn", "knot", "knots"}},
+	} {
+		for _, alias := range unit.aliases {
+			conversionUnits[alias] = unit.conversionUnit
+		}
+	}
+}
+
+// Global function for looking up a unit by any of its names ("degrees Fahrenheit", "°F", "f")
+func lookupConversionUnit(name string) (conversionUnit, bool) {
+	name = strings.ToLower(strings.TrimSpace(strings.TrimRight(strings.TrimSpace(name), ".")))
+	name = strings.TrimSpace(unitPrefixPattern.ReplaceAllString(name, ""))
+	unit, ok := conversionUnits[name]
+	return unit, ok
+}
+
+// Global function for converting a value between two units of the same dimension
+func convertUnits(value float64, from conversionUnit, to conversionUnit) float64 {
+	return (value+from.offset)*from.factor/to.factor - to.offset
+}
+
+// Global function for formatting a converted quantity to six significant digits without exponents
+func formatQuantity(value float64, unit conversionUnit) string {
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(value, 'g', 6, 64), 64)
+	if rounded == 0 {
+		rounded = 0 // Avoiding "-0"
+	}
+	return strconv.FormatFloat(rounded, 'f', -1, 64) + " " + unit.symbol
+}
+
+// Local answerer for conversions to one or more target units ("100 F in C and K"), declining when any unit is unsupported
+func answerUnitConversion(event *slack.MessageEvent) (string, bool) {
+	match := conversionPattern.FindStringSubmatch(event.Msg.Text)
+	if match == nil {
+		return "", false
+	}
+	value, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return "", false
+	}
+	from, ok := lookupConversionUnit(match[2])
+	if !ok {
+		return "", false
+	}
+
+	var conversions []string
+	for _, name := range conversionTargetPattern.Split(match[3], -1) {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		to, ok := lookupConversionUnit(name)
+		if !ok || to.dimension != from.dimension {
+			// Wolfram handles anything we can't convert ourselves, so the whole question goes there
+			return "", false
+		}
+		conversions = append(conversions, formatQuantity(convertUnits(value, from, to), to))
+	}
+	if len(conversions) == 0 {
+		return "", false
+	}
+
+	reply := fmt.Sprintf("%s = %s", formatQuantity(value, from), strings.Join(conversions, " = "))
+	return localizeAnswerNumbers(reply, event.User, config.NumberSignificantDigits), true
+}
@@ -0,0 +1,70 @@
+//////////////////////////////////////////////////
+// Repeat Cooldown Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for skipping a question its asker was just answered, rather than querying again
+import (
+	"log"     // Permits console logging
+	"strings" // Permits building of cooldown keys
+	"time"    // Permits cooldown windows
+)
+
+// Global constant holding the reply to a question repeated within its cooldown
+const repeatCooldownNotice = "I just answered that! :point_up: Scroll up for my answer, or rephrase the question to ask something new."
+
+// Global recent answers by user, handler, and normalized question, with when they were given, each kept for the longest
+// cooldown any handler is under and the oldest forgotten beyond the dedup cache size
+var recentAnswers = newBoundedCache("recent_answers", func() int { return config.DedupCacheSize }, func(interface{}) time.Duration { return longestRepeatCooldown() })
+
+// Global function for reading a handler's repeat cooldown: the configured override, else the default; repeatable handlers
+// have none unless overridden
+func repeatCooldown(handler *entityHandler) time.Duration {
+	if override, ok := config.RepeatCooldowns[handler.name]; ok {
+		if cooldown, err := time.ParseDuration(override); err == nil && cooldown >= 0 {
+			return cooldown
+		}
+		log.Printf("CONFIG ERROR: Ignoring invalid repeat cooldown %q for handler %s.", override, handler.name)
+	}
+	if handler.repeatable {
+		return 0
+	}
+	return config.RepeatCooldown
+}
+
+// Global function for building the key a question is remembered under
+func recentAnswerKey(user string, handler *entityHandler, text string) string {
+	return strings.Join([]string{user, handler.name, normalizeCacheQuery(text)}, "\x00")
+}
+
+// Global function for checking whether the asker got an answer to this question from this handler within its cooldown
+func answeredRecently(user string, handler *entityHandler, text string) bool {
+	cooldown := repeatCooldown(handler)
+	if cooldown <= 0 {
+		return false
+	}
+	answeredAt, ok := recentAnswers.get(recentAnswerKey(user, handler, text))
+	return ok && time.Since(answeredAt.(time.Time)) < cooldown
+}
+
+// Global function for remembering an answer for the handler's cooldown
+func rememberRecentAnswer(user string, handler *entityHandler, text string) {
+	if repeatCooldown(handler) <= 0 {
+		return
+	}
+	recentAnswers.put(recentAnswerKey(user, handler, text), time.Now())
+}
+
+// Global function for finding the longest cooldown any handler is under, so pruning never forgets an answer too early
+func longestRepeatCooldown() time.Duration {
+	longest := config.RepeatCooldown
+	for _, override := range config.RepeatCooldowns {
+		if cooldown, err := time.ParseDuration(override); err == nil && cooldown > longest {
+			longest = cooldown
+		}
+	}
+	return longest
+}
@@ -0,0 +1,198 @@
+//////////////////////////////////////////////////
+// Instance Coordination Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for letting replicas sharing a Redis server agree on which of them answers each message
+import (
+	"bufio"   // Permits reading of Redis replies
+	"fmt"     // Permits encoding of Redis commands
+	"io"      // Permits reading of bulk replies
+	"log"     // Permits console logging
+	"net"     // Permits connecting to Redis
+	"os"      // Permits naming of this instance
+	"strconv" // Permits parsing of Redis reply lengths
+	"strings" // Permits trimming of Redis replies
+	"sync"    // Permits sharing of the Redis connection
+	"time"    // Permits claim and answer record expiry
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constant bounding each Redis round trip, so a slow server delays a message by at most this much
+const redisTimeout = 2 * time.Second
+
+// Global struct holding one Redis connection, reopened after any failure, used one command at a time
+type redisClient struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// Global Redis connection shared by the instances, and the name this instance claims messages under
+var (
+	coordination = &redisClient{}
+	instanceName = func() string {
+		host, _ := os.Hostname()
+		return fmt.Sprintf("%s:%d", host, os.Getpid())
+	}()
+)
+
+// Global function for checking whether replicas coordinate through Redis (unset for a single instance)
+func coordinationEnabled() bool {
+	return config.RedisAddr != ""
+}
+
+// Method for running one command and returning its reply: a status or bulk string, with false for a nil reply
+func (c *redisClient) do(args ...string) (string, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		if err := c.connect(); err != nil {
+			return "", false, err
+		}
+	}
+	reply, ok, err := c.roundTrip(args)
+	if err != nil {
+		// Dropping the connection, since a half-read reply would misalign every later one
+		c.conn.Close()
+		c.conn = nil
+	}
+	return reply, ok, err
+}
+
+// Method for connecting and authenticating (callers hold the lock)
+func (c *redisClient) connect() error {
+	conn, err := net.DialTimeout("tcp", config.RedisAddr, redisTimeout)
+	if err != nil {
+		return err
+	}
+	c.conn, c.reader = conn, bufio.NewReader(conn)
+	if config.RedisPassword != "" {
+		if _, _, err := c.roundTrip([]string{"AUTH", config.RedisPassword}); err != nil {
+			c.conn.Close()
+			c.conn = nil
+			return fmt.Errorf("redis AUTH failed: %v", err)
+		}
+	}
+	return nil
+}
+
+// Method for writing a command in the Redis protocol and reading its reply (callers hold the lock)
+func (c *redisClient) roundTrip(args []string) (string, bool, error) {
+	c.conn.SetDeadline(time.Now().Add(redisTimeout))
+	command := fmt.Sprintf("*%d\r\n", len(args))
+	for _, arg := range args {
+		command += fmt.Sprintf("$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := c.conn.Write([]byte(command)); err != nil {
+		return "", false, err
+	}
+
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return "", false, err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return "", false, fmt.Errorf("redis sent an empty reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], true, nil
+	case '-':
+		return "", false, fmt.Errorf("redis error: %s", line[1:])
+	case ':':
+		return line[1:], true, nil
+	case '$':
+		length, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return "", false, fmt.Errorf("redis sent a bad bulk length %q", line)
+		}
+		if length < 0 {
+			return "", false, nil
+		}
+		data := make([]byte, length+2)
+		if _, err := io.ReadFull(c.reader, data); err != nil {
+			return "", false, err
+		}
+		return string(data[:length]), true, nil
+	}
+	return "", false, fmt.Errorf("redis sent an unexpected reply %q", line)
+}
+
+// Global function for claiming a message for this instance with SET NX, reporting whether it won. Messages are claimed
+// as a worker picks them up rather than on arrival, so time spent queued doesn't count against the claim. A claim expires
+// after the claim TTL, so a winner that crashes doesn't leave the message unanswered: the losers look again then
+// and, when nobody recorded an answer, claim and answer it themselves. Without Redis every message is this
+// instance's, and when Redis can't be reached the message is answered anyway (a duplicate beats silence).
+func claimMessage(event *slack.MessageEvent) bool {
+	if !coordinationEnabled() {
+		return true
+	}
+	key := messageEventKey(event)
+	won, err := tryClaim(key)
+	if err != nil {
+		metrics.inc("wolfy_instance_claims_total", "result", "error")
+		log.Printf("COORDINATION ERROR: Unable to claim message %s; answering it anyway.\nError Details: %v", key, err)
+		return true
+	}
+	if won {
+		metrics.inc("wolfy_instance_claims_total", "result", "won")
+		return true
+	}
+
+	metrics.inc("wolfy_instance_claims_total", "result", "lost")
+	time.AfterFunc(config.InstanceClaimTTL, func() { retryUnansweredMessage(event) })
+	return false
+}
+
+// Global function for setting a message's claim, reporting whether this instance now holds it (including a claim it
+// already took, as when it takes over a message and queues it again)
+func tryClaim(key string) (bool, error) {
+	_, ok, err := coordination.do("SET", "wolfy:claim:"+key, instanceName, "NX", "PX", strconv.FormatInt(int64(config.InstanceClaimTTL/time.Millisecond), 10))
+	if err != nil || ok {
+		return ok, err
+	}
+	holder, _, err := coordination.do("GET", "wolfy:claim:"+key)
+	return err == nil && holder == instanceName, err
+}
+
+// Global function for answering a message another instance claimed and never recorded an answer for, once its claim
+// has expired
+func retryUnansweredMessage(event *slack.MessageEvent) {
+	key := messageEventKey(event)
+	_, answered, err := coordination.do("GET", "wolfy:answered:"+key)
+	if err != nil {
+		log.Printf("COORDINATION ERROR: Unable to check whether message %s was answered.\nError Details: %v", key, err)
+		return
+	}
+	if answered {
+		return
+	}
+	if won, err := tryClaim(key); err != nil || !won {
+		return
+	}
+	metrics.inc("wolfy_instance_claims_total", "result", "took_over")
+	log.Printf("COORDINATION: Answering message %s, whose claim expired without an answer.", key)
+	dispatchMessage(event)
+}
+
+// Global function for recording that this instance finished with a message, so other instances don't take it over,
+// kept as long as messages are deduplicated
+func markMessageAnswered(key string) {
+	if !coordinationEnabled() {
+		return
+	}
+	ttl := config.DedupWindow
+	if ttl < config.InstanceClaimTTL*2 {
+		ttl = config.InstanceClaimTTL * 2
+	}
+	if _, _, err := coordination.do("SET", "wolfy:answered:"+key, instanceName, "PX", strconv.FormatInt(int64(ttl/time.Millisecond), 10)); err != nil {
+		log.Printf("COORDINATION ERROR: Unable to record message %s as answered.\nError Details: %v", key, err)
+	}
+}
@@ -0,0 +1,161 @@
+//////////////////////////////////////////////////
+// Instance Coordination Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing replicas coordinating through an in-process Redis server
+import (
+	"net"     // Permits finding of an unused port
+	"testing" // Permits Go testing
+	"time"    // Permits claim expiry
+
+	miniredis "github.com/alicebob/miniredis/v2" // External in-process Redis server
+)
+
+// Global function for starting an in-process Redis server requiring a password for one test, pointing the bot at it as
+// the given instance. Its keys only expire as the test fast-forwards its clock.
+func withMiniRedis(t *testing.T, name string, ttl time.Duration) *miniredis.Miniredis {
+	server := miniredis.RunT(t)
+	server.RequireAuth("wolfy-test")
+	withConfig(t, func(c *Config) {
+		c.RedisAddr = server.Addr()
+		c.RedisPassword = "wolfy-test"
+		c.InstanceClaimTTL = ttl
+	})
+	withInstanceName(t, name)
+	previous := coordination
+	coordination = &redisClient{}
+	t.Cleanup(func() { coordination = previous })
+	return server
+}
+
+// Global function for running one test as a named instance, restoring the real name afterwards
+func withInstanceName(t *testing.T, name string) {
+	previous := instanceName
+	instanceName = name
+	t.Cleanup(func() { instanceName = previous })
+}
+
+// Global function for counting the answers posted to one user's DM so far (posted with the user as the channel)
+func answersTo(user string) int {
+	count := 0
+	for _, call := range answerPosts() {
+		if call.values.Get("channel") == user {
+			count++
+		}
+	}
+	return count
+}
+
+// Test for one instance winning a message's claim while another drops it without answering, and for the winner
+// keeping its claim when it queues the message again
+func TestClaimMessageWinnerAndLoser(t *testing.T) {
+	server := withMiniRedis(t, "replica-a", time.Hour)
+	event := testMessage("DCLAIM1", "UCLAIM1", "6000.000001", "what is 3 * 4")
+	key := messageEventKey(event)
+
+	if !claimMessage(event) {
+		t.Fatal("claimMessage as the first replica = false; want true")
+	}
+	if holder, _ := server.Get("wolfy:claim:" + key); holder != "replica-a" {
+		t.Errorf("claim holder = %q; want replica-a", holder)
+	}
+	if !claimMessage(event) {
+		t.Error("claimMessage again as the holder = false; want true")
+	}
+
+	instanceName = "replica-b"
+	lost, answers := metricValue("wolfy_instance_claims_total", "result", "lost"), answersTo("UCLAIM1")
+	handleMSGEvent(event)
+	if got := metricValue("wolfy_instance_claims_total", "result", "lost") - lost; got != 1 {
+		t.Errorf("lost claims counted = %d; want 1", got)
+	}
+	if got := answersTo("UCLAIM1") - answers; got != 0 {
+		t.Errorf("answers posted by the losing replica = %d; want 0", got)
+	}
+	if server.Exists("wolfy:answered:" + key) {
+		t.Error("the losing replica recorded an answer")
+	}
+}
+
+// Test for a losing instance taking over a message once the winner's claim expires without an answer
+func TestClaimMessageTakeover(t *testing.T) {
+	server := withMiniRedis(t, "replica-a", 200*time.Millisecond)
+	event := testMessage("DCLAIM2", "UCLAIM2", "6000.000002", "what is 5 * 6")
+	key := messageEventKey(event)
+	if won, err := tryClaim(key); err != nil || !won {
+		t.Fatalf("tryClaim as the first replica = %v, %v; want true", won, err)
+	}
+
+	instanceName = "replica-b"
+	tookOver, answers := metricValue("wolfy_instance_claims_total", "result", "took_over"), answersTo("UCLAIM2")
+	if claimMessage(event) {
+		t.Fatal("claimMessage while another replica holds the claim = true; want false")
+	}
+	server.FastForward(200 * time.Millisecond)
+	waitFor(t, "the losing replica to answer", func() bool { return answersTo("UCLAIM2") == answers+1 })
+	waitFor(t, "the answer to be recorded", func() bool {
+		holder, _ := server.Get("wolfy:answered:" + key)
+		return holder == "replica-b"
+	})
+	if got := metricValue("wolfy_instance_claims_total", "result", "took_over") - tookOver; got != 1 {
+		t.Errorf("take-overs counted = %d; want 1", got)
+	}
+}
+
+// Test for a losing instance leaving a message alone when the winner recorded its answer before the claim expired
+func TestClaimMessageNoTakeoverWhenAnswered(t *testing.T) {
+	server := withMiniRedis(t, "replica-a", 100*time.Millisecond)
+	event := testMessage("DCLAIM3", "UCLAIM3", "6000.000003", "what is 7 * 8")
+	key := messageEventKey(event)
+	if won, err := tryClaim(key); err != nil || !won {
+		t.Fatalf("tryClaim as the first replica = %v, %v; want true", won, err)
+	}
+	markMessageAnswered(key)
+
+	instanceName = "replica-b"
+	tookOver, answers := metricValue("wolfy_instance_claims_total", "result", "took_over"), answersTo("UCLAIM3")
+	if claimMessage(event) {
+		t.Fatal("claimMessage while another replica holds the claim = true; want false")
+	}
+	server.FastForward(100 * time.Millisecond)
+	commands := server.CommandCount()
+	waitFor(t, "the losing replica to look again", func() bool { return server.CommandCount() > commands })
+	if got := answersTo("UCLAIM3") - answers; got != 0 {
+		t.Errorf("answers posted after the winner answered = %d; want 0", got)
+	}
+	if got := metricValue("wolfy_instance_claims_total", "result", "took_over") - tookOver; got != 0 {
+		t.Errorf("take-overs counted = %d; want 0", got)
+	}
+}
+
+// Test for answering anyway when Redis can't be reached, and for skipping claims without Redis
+func TestClaimMessageWithoutRedis(t *testing.T) {
+	event := testMessage("DCLAIM4", "UCLAIM4", "6000.000004", "what is 9 * 9")
+	withConfig(t, func(c *Config) { c.RedisAddr = "" })
+	if !claimMessage(event) {
+		t.Error("claimMessage without Redis = false; want true")
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+	withConfig(t, func(c *Config) { c.RedisAddr = addr })
+	previous := coordination
+	coordination = &redisClient{}
+	defer func() { coordination = previous }()
+
+	errors := metricValue("wolfy_instance_claims_total", "result", "error")
+	if !claimMessage(event) {
+		t.Error("claimMessage with Redis down = false; want true")
+	}
+	if got := metricValue("wolfy_instance_claims_total", "result", "error") - errors; got != 1 {
+		t.Errorf("claim errors counted = %d; want 1", got)
+	}
+}
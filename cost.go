@@ -0,0 +1,240 @@
+//////////////////////////////////////////////////
+// API Cost Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for estimating what each question costs in paid API calls
+import (
+	"context" // Permits carrying per-question call counts through the pipeline
+	"fmt"     // Permits formatting of spend reports
+	"log"     // Permits console logging
+	"sort"    // Permits stable ordering of spend breakdowns
+	"strconv" // Permits formatting of the digest claim's expiry
+	"strings" // Permits joining of report lines
+	"sync"    // Permits concurrency-safe call counting
+	"time"    // Permits monthly and weekly spend periods
+)
+
+// Global constant naming the store bucket holding monthly spend totals
+const spendBucket = "spend"
+
+// Global struct counting the external API calls made while answering one question
+type apiUsage struct {
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+// Global context key type for the per-question API usage
+type apiUsageKey struct{}
+
+// Global struct holding one month's or week's estimated spend, and how many questions it covers
+type spendTotals struct {
+	Total     float64
+	Unpriced  int
+	Questions int
+	CacheHits int
+	ByChannel map[string]float64
+	ByHandler map[string]float64
+}
+
+// Global mutex serializing read-modify-write of spend totals
+var spendMu sync.Mutex
+
+// Global function for attaching a fresh API usage counter to a context (keeping any existing one)
+func withAPIUsage(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(apiUsageKey{}).(*apiUsage); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, apiUsageKey{}, &apiUsage{calls: map[string]int{}})
+}
+
+// Global function for counting an external API call against the question being answered
+func recordAPICall(ctx context.Context, api string) {
+	metrics.inc("wolfy_api_calls_total", "api", api)
+	if usage, ok := ctx.Value(apiUsageKey{}).(*apiUsage); ok {
+		usage.mu.Lock()
+		usage.calls[api]++
+		usage.mu.Unlock()
+	}
+}
+
+// Global function for copying the API calls counted so far for a question (nil when none)
+func apiCalls(ctx context.Context) map[string]int {
+	usage, ok := ctx.Value(apiUsageKey{}).(*apiUsage)
+	if !ok {
+		return nil
+	}
+	usage.mu.Lock()
+	defer usage.mu.Unlock()
+
+	if len(usage.calls) == 0 {
+		return nil
+	}
+	calls := make(map[string]int, len(usage.calls))
+	for api, count := range usage.calls {
+		calls[api] = count
+	}
+	return calls
+}
+
+// Global function for pricing a set of calls, reporting false when any called API has no configured price
+func priceCalls(calls map[string]int) (float64, bool) {
+	total := 0.0
+	for api, count := range calls {
+		price, ok := config.APIPrices[api]
+		if !ok {
+			return 0, false
+		}
+		total += price * float64(count)
+	}
+	return total, true
+}
+
+// Global function for formatting a cost, or "n/a" when it could not be priced
+func formatCost(cost float64, known bool) string {
+	if !known {
+		return "n/a"
+	}
+	return fmt.Sprintf("$%.4f", cost)
+}
+
+// Global function for keying the month and the ISO week an interaction's spend counts toward
+func spendPeriods(at time.Time) []string {
+	return []string{at.UTC().Format("2006-01"), spendWeek(at)}
+}
+
+// Global function for keying the ISO week (e.g. "2026-W07") that a time falls in
+func spendWeek(at time.Time) string {
+	year, week := at.UTC().ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// Global function for adding an interaction's cost to the current month's and week's persisted totals
+func recordSpend(entry interaction) {
+	if store == nil {
+		return
+	}
+	spendMu.Lock()
+	defer spendMu.Unlock()
+
+	for _, period := range spendPeriods(entry.At) {
+		totals := spendTotals{ByChannel: map[string]float64{}, ByHandler: map[string]float64{}}
+		store.get(spendBucket, period, &totals)
+		if totals.ByChannel == nil {
+			totals.ByChannel = map[string]float64{}
+		}
+		if totals.ByHandler == nil {
+			totals.ByHandler = map[string]float64{}
+		}
+
+		totals.Questions++
+		if entry.Cached {
+			totals.CacheHits++
+		}
+		if !entry.CostKnown {
+			totals.Unpriced++
+		} else {
+			totals.Total += entry.Cost
+			totals.ByChannel[entry.Channel] += entry.Cost
+			totals.ByHandler[intentLabel(entry.EntityKey)] += entry.Cost
+		}
+		if err := store.put(spendBucket, period, totals); err != nil {
+			log.Printf("COST ERROR: Unable to record spend for %s.\nError Details: %v", period, err)
+		}
+	}
+}
+
+// Global function for describing a spend breakdown, largest first
+func describeSpendBreakdown(breakdown map[string]float64) string {
+	keys := make([]string, 0, len(breakdown))
+	for key := range breakdown {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return breakdown[keys[i]] > breakdown[keys[j]] })
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s %s", key, formatCost(breakdown[key], true)))
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Global function for summarizing a period's estimated spend, with its breakdowns once anything was recorded
+func describeSpend(period string) string {
+	if len(config.APIPrices) == 0 {
+		return "Estimated spend: n/a (no API prices configured)"
+	}
+
+	var totals spendTotals
+	if store == nil || !store.get(spendBucket, period, &totals) {
+		return fmt.Sprintf("Estimated spend for %s: %s", period, formatCost(0, true))
+	}
+
+	lines := []string{
+		fmt.Sprintf("Estimated spend for %s: %s", period, formatCost(totals.Total, true)),
+		"By channel: " + describeSpendBreakdown(totals.ByChannel),
+		"By handler: " + describeSpendBreakdown(totals.ByHandler),
+	}
+	if totals.Questions > 0 {
+		lines = append(lines, fmt.Sprintf("Questions: %d (%d answered from the cache at no cost)", totals.Questions, totals.CacheHits))
+	}
+	if totals.Unpriced > 0 {
+		lines = append(lines, fmt.Sprintf("Unpriced questions (n/a): %d", totals.Unpriced))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Global function for summarizing the current month's estimated spend for "!stats"
+func describeMonthlySpend(now time.Time) string {
+	return describeSpend(now.UTC().Format("2006-01"))
+}
+
+// Global function for building the digest of the week before now's, or "" once this instance or (with Redis) any
+// other has already posted it or nothing was recorded that week
+func weeklySpendDigest(now time.Time) string {
+	week := spendWeek(now.AddDate(0, 0, -7))
+	var totals spendTotals
+	if store == nil || store.get(spendBucket, spendDigestKey(week), new(bool)) || !store.get(spendBucket, week, &totals) {
+		return ""
+	}
+	if coordinationEnabled() {
+		_, won, err := coordination.do("SET", "wolfy:digest:"+week, instanceName, "NX", "PX", strconv.FormatInt(int64(8*24*time.Hour/time.Millisecond), 10))
+		if err != nil {
+			log.Printf("COST ERROR: Unable to claim the %s digest; posting it anyway.\nError Details: %v", week, err)
+		} else if !won {
+			store.put(spendBucket, spendDigestKey(week), true)
+			return ""
+		}
+	}
+	if err := store.put(spendBucket, spendDigestKey(week), true); err != nil {
+		log.Printf("COST ERROR: Unable to mark the %s digest as posted.\nError Details: %v", week, err)
+	}
+	return ":moneybag: *Weekly spend digest*\n" + describeSpend(week)
+}
+
+// Global function for keying the marker recording that a week's digest was posted
+func spendDigestKey(week string) string {
+	return "digest:" + week
+}
+
+// Global function for starting the hourly check that posts last week's spend digest to the admin channel once
+// a new week begins
+func startWeeklyDigest() {
+	if !config.WeeklyDigest || config.AdminChannel == "" {
+		return
+	}
+	go func() {
+		for now := range time.Tick(time.Hour) {
+			if digest := weeklySpendDigest(now); digest != "" {
+				metrics.inc("wolfy_weekly_digests_total")
+				postText(config.AdminChannel, digest)
+			}
+		}
+	}()
+}
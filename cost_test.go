@@ -0,0 +1,135 @@
+//////////////////////////////////////////////////
+// API Cost Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing how estimated spend is totalled per month and week, and posted as a weekly digest
+import (
+	"fmt"     // Permits formatting of expected summaries
+	"testing" // Permits Go testing
+	"time"    // Permits the fake clock
+)
+
+// Global function for clearing a period's spend totals and digest marker before and after a test
+func withoutSpend(t *testing.T, periods ...string) {
+	for _, period := range periods {
+		store.delete(spendBucket, period)
+		store.delete(spendBucket, spendDigestKey(period))
+	}
+	t.Cleanup(func() {
+		for _, period := range periods {
+			store.delete(spendBucket, period)
+			store.delete(spendBucket, spendDigestKey(period))
+		}
+	})
+}
+
+// Global function for recording a paid answer, a cache hit, a local answer, and an answer from an unpriced API in one week
+func recordSpendWeek(at time.Time) {
+	for _, entry := range []interaction{
+		{EntityKey: "wolfram_query", Channel: "CSPEND", Calls: map[string]int{"wolfram": 2, "wit": 1}},
+		{EntityKey: "wolfram_query", Channel: "CSPEND", Cached: true},
+		{EntityKey: "unit_conversion", Channel: "COTHER"},
+		{EntityKey: "translated", Channel: "COTHER", Calls: map[string]int{"translation": 2, "wit": 1}},
+	} {
+		entry.At = at
+		entry.Cost, entry.CostKnown = priceCalls(entry.Calls)
+		recordSpend(entry)
+	}
+}
+
+// Test for spend counting toward both its month and its ISO week, cache hits and local handlers costing nothing, and
+// unpriced calls reported as n/a without breaking the totals
+func TestRecordSpendPeriods(t *testing.T) {
+	withConfig(t, func(config *Config) { config.APIPrices = map[string]float64{"wolfram": 0.002, "wit": 0.0005} })
+	withoutSpend(t, "2020-03", "2020-W10", "2020-W11")
+	recordSpendWeek(time.Date(2020, 3, 4, 9, 0, 0, 0, time.UTC))
+
+	want := "Estimated spend for %s: $0.0045\n" +
+		"By channel: CSPEND $0.0045, COTHER $0.0000\n" +
+		"By handler: wolfram_query $0.0045, unit_conversion $0.0000\n" +
+		"Questions: 4 (1 answered from the cache at no cost)\n" +
+		"Unpriced questions (n/a): 1"
+	for _, period := range []string{"2020-03", "2020-W10"} {
+		if got := describeSpend(period); got != fmt.Sprintf(want, period) {
+			t.Errorf("describeSpend(%s) = %q; want %q", period, got, fmt.Sprintf(want, period))
+		}
+	}
+	if got := describeMonthlySpend(time.Date(2020, 3, 31, 23, 0, 0, 0, time.UTC)); got != fmt.Sprintf(want, "2020-03") {
+		t.Errorf("describeMonthlySpend = %q; want the March totals", got)
+	}
+	if got := describeSpend("2020-W11"); got != "Estimated spend for 2020-W11: $0.0000" {
+		t.Errorf("describeSpend(2020-W11) = %q; want nothing spent", got)
+	}
+
+	withConfig(t, func(config *Config) { config.APIPrices = nil })
+	if got := describeSpend("2020-W10"); got != "Estimated spend: n/a (no API prices configured)" {
+		t.Errorf("describeSpend without prices = %q; want n/a", got)
+	}
+}
+
+// Test for ISO weeks keying spend across the turn of a year
+func TestSpendWeek(t *testing.T) {
+	cases := []struct {
+		at   time.Time
+		want string
+	}{
+		{time.Date(2020, 3, 4, 9, 0, 0, 0, time.UTC), "2020-W10"},
+		{time.Date(2020, 3, 8, 23, 59, 0, 0, time.UTC), "2020-W10"},
+		{time.Date(2020, 3, 9, 0, 0, 0, 0, time.UTC), "2020-W11"},
+		{time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC), "2020-W53"},
+		{time.Date(2024, 12, 30, 12, 0, 0, 0, time.UTC), "2025-W01"},
+		{time.Date(2020, 3, 9, 0, 30, 0, 0, time.FixedZone("CET", 3600)), "2020-W10"},
+	}
+	for _, c := range cases {
+		if got := spendWeek(c.at); got != c.want {
+			t.Errorf("spendWeek(%s) = %s; want %s", c.at, got, c.want)
+		}
+	}
+}
+
+// Test for the digest reporting the week before, posted once however often it is checked, and skipped for a week
+// with nothing recorded
+func TestWeeklySpendDigest(t *testing.T) {
+	withConfig(t, func(config *Config) { config.APIPrices = map[string]float64{"wolfram": 0.002, "wit": 0.0005} })
+	withoutSpend(t, "2020-03", "2020-W10", "2020-W11")
+	recordSpendWeek(time.Date(2020, 3, 4, 9, 0, 0, 0, time.UTC))
+
+	monday := time.Date(2020, 3, 9, 0, 30, 0, 0, time.UTC)
+	want := ":moneybag: *Weekly spend digest*\n" + describeSpend("2020-W10")
+	if got := weeklySpendDigest(monday); got != want {
+		t.Fatalf("weeklySpendDigest(%s) = %q; want %q", monday, got, want)
+	}
+	if got := weeklySpendDigest(monday.Add(time.Hour)); got != "" {
+		t.Errorf("weeklySpendDigest an hour later = %q; want it posted only once", got)
+	}
+	if got := weeklySpendDigest(monday.AddDate(0, 0, 7)); got != "" {
+		t.Errorf("weeklySpendDigest for an empty week = %q; want none", got)
+	}
+}
+
+// Test for only one of several instances posting a week's digest when they coordinate through Redis
+func TestWeeklySpendDigestClaimed(t *testing.T) {
+	withConfig(t, func(config *Config) { config.APIPrices = map[string]float64{"wolfram": 0.002, "wit": 0.0005} })
+	withoutSpend(t, "2020-03", "2020-W10", "2020-W11")
+	recordSpendWeek(time.Date(2020, 3, 4, 9, 0, 0, 0, time.UTC))
+	server := withMiniRedis(t, "wolfy-a", time.Minute)
+
+	monday := time.Date(2020, 3, 9, 0, 30, 0, 0, time.UTC)
+	server.Set("wolfy:digest:2020-W10", "wolfy-b")
+	if got := weeklySpendDigest(monday); got != "" {
+		t.Errorf("weeklySpendDigest claimed by another instance = %q; want none", got)
+	}
+
+	store.delete(spendBucket, spendDigestKey("2020-W10"))
+	server.Del("wolfy:digest:2020-W10")
+	if got := weeklySpendDigest(monday); got == "" {
+		t.Errorf("weeklySpendDigest unclaimed = %q; want the digest", got)
+	}
+	if holder, _ := server.Get("wolfy:digest:2020-W10"); holder != "wolfy-a" {
+		t.Errorf("digest claimed by %q; want wolfy-a", holder)
+	}
+}
@@ -0,0 +1,200 @@
+//////////////////////////////////////////////////
+// Credential Rotation Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for re-reading API credentials and swapping clients without dropping the RTM connection
+import (
+	"fmt"         // Permits formatting of rotation reports
+	"io/ioutil"   // Permits reading of secret files
+	"log"         // Permits console logging
+	"os"          // Permits environment variable lookups
+	"strings"     // Permits trimming of secret file contents
+	"sync"        // Permits serializing rotations
+	"sync/atomic" // Permits swapping the clients whole
+	"time"        // Permits bounding the new connection's handshake
+
+	wit "github.com/christianrondeau/go-wit" // External Wit.ai API
+	websocket "github.com/gorilla/websocket" // External WebSocket API
+	slack "github.com/nlopes/slack"          // External Slack API
+)
+
+// Global constant bounding how long a rotated Slack token's RTM connection may take to come up
+const rotationConnectTimeout = 30 * time.Second
+
+// Global struct holding the API clients and the credentials they were built from, swapped whole on rotation
+type apiCredentials struct {
+	slackToken   string
+	slack        *slack.Client
+	witToken     string
+	wit          *wit.Client
+	wolframAppID string
+}
+
+// Global holder of the current credentials, the dialer new RTM connections use, and the hand-off to the RTM loop
+var (
+	currentCredentials atomic.Value
+	rotationMu         sync.Mutex
+	rtmDialer          *websocket.Dialer
+	rtmSwitches        = make(chan *slack.RTM)
+)
+
+// Global function for reading a secret from "<KEY>_FILE" (a mounted secret file) when set, else from "<KEY>" itself
+func readSecret(key string) (string, error) {
+	path := os.Getenv(key + "_FILE")
+	if path == "" {
+		return os.Getenv(key), nil
+	}
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(contents)), nil
+}
+
+// Global function for building the clients for a set of credentials
+func buildCredentials(slackToken string, witToken string, wolframAppID string) apiCredentials {
+	return apiCredentials{
+		slackToken:   slackToken,
+		slack:        slack.New(slackToken, slack.OptionHTTPClient(httpClient)),
+		witToken:     witToken,
+		wit:          wit.NewClient(witToken),
+		wolframAppID: wolframAppID,
+	}
+}
+
+// Global function for returning the current Slack client, retrying rate limited calls (callers keep the one they got
+// for the whole operation)
+func slackAPI() retryingSlack {
+	return retryingSlack{currentCredentials.Load().(apiCredentials).slack}
+}
+
+// Global function for returning the current Wit.ai client
+func witAPI() *wit.Client {
+	return currentCredentials.Load().(apiCredentials).wit
+}
+
+// Global function for returning the current shared Wolfram App ID
+func wolframAppID() string {
+	return currentCredentials.Load().(apiCredentials).wolframAppID
+}
+
+// Global function for re-reading every credential from its source and swapping in the ones that changed, returning a report.
+// A new Slack token must pass AuthTest as the same bot and bring up its own RTM connection before the RTM loop switches over;
+// otherwise the old token stays in use and admins are alerted.
+func rotateCredentials(trigger string) string {
+	rotationMu.Lock()
+	defer rotationMu.Unlock()
+
+	current := currentCredentials.Load().(apiCredentials)
+	var problems []string
+	read := func(key string, fallback string) string {
+		value, err := readSecret(key)
+		if err != nil || value == "" {
+			if err == nil {
+				err = fmt.Errorf("%s is empty", key)
+			}
+			problems = append(problems, fmt.Sprintf("%s: unable to read, keeping the current one (%v)", key, err))
+			return fallback
+		}
+		return value
+	}
+	slackToken := read("SLACK_ACCESS_TOKEN", current.slackToken)
+	witToken := read("WIT_AI_ACCESS_TOKEN", current.witToken)
+	appID := read("WOLFRAM_APP_ID", current.wolframAppID)
+
+	var changes []string
+	next := current
+	if witToken != current.witToken {
+		next.witToken, next.wit = witToken, wit.NewClient(witToken)
+		changes = append(changes, "Wit.ai token")
+	}
+	if appID != current.wolframAppID {
+		next.wolframAppID = appID
+		changes = append(changes, "Wolfram App ID")
+	}
+
+	var rtm *slack.RTM
+	if slackToken != current.slackToken {
+		candidate := slack.New(slackToken, slack.OptionHTTPClient(httpClient))
+		var err error
+		if rtm, err = connectRotatedSlack(candidate); err != nil {
+			problems = append(problems, fmt.Sprintf("SLACK_ACCESS_TOKEN: the new token was rejected, keeping the current one (%v)", err))
+		} else {
+			next.slackToken, next.slack = slackToken, candidate
+			changes = append(changes, "Slack token")
+		}
+	}
+
+	// Swapping the clients first so new work uses them, then moving the RTM loop to the new connection
+	currentCredentials.Store(next)
+	if rtm != nil {
+		rtmSwitches <- rtm
+	}
+
+	metrics.inc("wolfy_credential_rotations_total", "result", map[bool]string{true: "ok", false: "partial"}[len(problems) == 0])
+	report := fmt.Sprintf("Credential rotation (%s): ", trigger)
+	if len(changes) == 0 {
+		report += "nothing changed."
+	} else {
+		report += "rotated " + strings.Join(changes, ", ") + "."
+	}
+	log.Printf("CREDENTIALS: %s", report)
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			log.Printf("CREDENTIALS ERROR: %s", problem)
+		}
+		report += "\n:warning: " + strings.Join(problems, "\n:warning: ")
+		if config.AdminChannel != "" {
+			postText(config.AdminChannel, report)
+		}
+	}
+	return report
+}
+
+// Global function for checking a rotated Slack token (or the current one, when the watchdog reconnects) and bringing up
+// its RTM connection, returning it once connected. Under Socket Mode the connection rides on the app-level token, so
+// only the check is made and no connection is returned.
+func connectRotatedSlack(candidate *slack.Client) (*slack.RTM, error) {
+	identity, err := candidate.AuthTest()
+	if err != nil {
+		return nil, err
+	}
+	if currentBotUserID() != "" && identity.UserID != currentBotUserID() {
+		return nil, fmt.Errorf("it belongs to %s rather than this bot (%s)", identity.UserID, currentBotUserID())
+	}
+	if usesSocketMode() {
+		return nil, nil
+	}
+
+	rtm := candidate.NewRTM(slack.RTMOptionDialer(rtmDialer))
+	go rtm.ManageConnection()
+	deadline := time.After(rotationConnectTimeout)
+	for {
+		select {
+		case msg := <-rtm.IncomingEvents:
+			// Anything arriving before the switch also reaches the old connection, which is still being read
+			switch event := msg.Data.(type) {
+			case *slack.ConnectedEvent:
+				return rtm, nil
+			case *slack.InvalidAuthEvent:
+				rtm.Disconnect()
+				return nil, fmt.Errorf("RTM rejected the token")
+			case *slack.ConnectionErrorEvent:
+				log.Printf("CREDENTIALS ERROR: New RTM connection attempt %d failed.\nError Details: %v", event.Attempt, event.ErrorObj)
+			}
+		case <-deadline:
+			rtm.Disconnect()
+			return nil, fmt.Errorf("RTM didn't connect within %s", rotationConnectTimeout)
+		}
+	}
+}
+
+// Admin command re-reading the Slack, Wit.ai, and Wolfram credentials and swapping in any that changed
+func runAdminRotate(event *slack.MessageEvent, args []string) string {
+	log.Printf("ADMIN: %s requested a credential rotation.", event.User)
+	return rotateCredentials("!rotate by <@" + event.User + ">")
+}
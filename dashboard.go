@@ -0,0 +1,90 @@
+//////////////////////////////////////////////////
+// Admin Dashboard Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for the embedded, token-protected activity dashboard
+import (
+	_ "embed"       // Permits embedding of the dashboard template
+	"fmt"           // Permits formatting of counter lines
+	"html/template" // Permits safe server-side rendering
+	"log"           // Permits console logging
+	"net/http"      // Permits serving the dashboard
+	"time"          // Permits timestamp formatting
+)
+
+// Global constant holding how often the dashboard page refreshes itself
+const dashboardRefreshSeconds = 5
+
+// Global embedded dashboard template (no external assets)
+//
+//go:embed templates/dashboard.html
+var dashboardTemplateSource string
+
+// Global parsed dashboard template
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(dashboardTemplateSource))
+
+// Global struct holding one activity row as rendered (query masked when configured)
+type dashboardRow struct {
+	At      string
+	User    string
+	Channel string
+	Query   string
+	Intent  string
+	Outcome string
+	Latency string
+}
+
+// Global function for rendering the dashboard from in-memory state only (feed, metrics, caches), behind the dashboard
+// token checked by the route's middleware
+func serveDashboard(w http.ResponseWriter, r *http.Request) {
+	rows := []dashboardRow{}
+	for _, entry := range recentActivity.snapshot() {
+		row := dashboardRow{
+			At:      entry.At.Format("15:04:05"),
+			User:    entry.User,
+			Channel: entry.Channel,
+			Query:   entry.Query,
+			Intent:  entry.Intent,
+			Outcome: entry.Outcome,
+			Latency: "-",
+		}
+		if config.DashboardMaskQueries {
+			row.Query = fmt.Sprintf("(%d characters hidden)", len(graphemeClusters(entry.Query)))
+		}
+		if entry.Latency > 0 {
+			row.Latency = entry.Latency.Round(time.Millisecond).String()
+		}
+		rows = append(rows, row)
+	}
+
+	keys, values := metrics.sortedKeys()
+	counters := make([]string, 0, len(keys))
+	for _, key := range keys {
+		counters = append(counters, fmt.Sprintf("%s %d", key, values[key]))
+	}
+
+	userCacheSize := userInfo.len()
+
+	state, since := currentConnectionState()
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	err := dashboardTemplate.Execute(w, map[string]interface{}{
+		"Version":         version,
+		"RefreshSeconds":  dashboardRefreshSeconds,
+		"Connection":      state,
+		"ConnectionSince": since.Format(time.RFC1123),
+		"APIsAllowed":     externalAPIsAllowed(),
+		"APIsStatus":      externalAPIsStatus(),
+		"UserCacheSize":   userCacheSize,
+		"Started":         startedAt.Format(time.RFC1123),
+		"Spend":           describeMonthlySpend(time.Now()),
+		"Activity":        rows,
+		"Counters":        counters,
+	})
+	if err != nil {
+		log.Printf("DASHBOARD ERROR: Unable to render dashboard.\nError Details: %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+//////////////////////////////////////////////////
+// Date Math Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for answering relative date questions locally
+import (
+	"fmt"     // Permits string formatting of replies
+	"regexp"  // Permits matching of date phrasings
+	"strconv" // Permits parsing of day and year numbers
+	"strings" // Permits string normalization
+	"time"    // Permits calendar arithmetic
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global struct holding a recognized calendar target
+type dateTarget struct {
+	label string
+	month time.Month
+	day   int
+	year  int // zero for dates that recur every year
+}
+
+// Global patterns recognizing "how many days until X" style questions and explicit dates
+var (
+	dateMathPattern   = regexp.MustCompile(`(?i)^\s*(?:how\s+many\s+(days|weeks)|how\s+long|(days|weeks))\s+(?:is\s+it\s+|are\s+there\s+|has\s+it\s+been\s+)?(until|till|til|to|before|since)\s+(.+?)\s*[?.!]*\s*$`)
+	monthDayPattern   = regexp.MustCompile(`^([a-z]+)\.?\s+(\d{1,2})(?:st|nd|rd|th)?(?:,?\s+(\d{4}))?$`)
+	dayMonthPattern   = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)?\s+(?:of\s+)?([a-z]+)(?:,?\s+(\d{4}))?$`)
+	isoDatePattern    = regexp.MustCompile(`^(\d{4})-(\d{2})-(\d{2})$`)
+	weekdayPattern    = regexp.MustCompile(`^(?:next\s+)?(monday|tuesday|wednesday|thursday|friday|saturday|sunday)$`)
+	apostropheRemover = strings.NewReplacer("'", "", "’", "")
+)
+
+// Global map of fixed-date holidays we can compute locally (moving holidays fall back to Wolfram)
+var fixedHolidays = map[string]dateTarget{
+	"christmas":          {"Christmas", time.December, 25, 0},
+	"christmas day":      {"Christmas", time.December, 25, 0},
+	"christmas eve":      {"Christmas Eve", time.December, 24, 0},
+	"new year":           {"New Year's Day", time.January, 1, 0},
+	"new years":          {"New Year's Day", time.January, 1, 0},
+	"new years day":      {"New Year's Day", time.January, 1, 0},
+	"new years eve":      {"New Year's Eve", time.December, 31, 0},
+	"halloween":          {"Halloween", time.October, 31, 0},
+	"valentines":         {"Valentine's Day", time.February, 14, 0},
+	"valentines day":     {"Valentine's Day", time.February, 14, 0},
+	"st patricks day":    {"St. Patrick's Day", time.March, 17, 0},
+	"independence day":   {"Independence Day", time.July, 4, 0},
+	"the fourth of july": {"Independence Day", time.July, 4, 0},
+	"fourth of july":     {"Independence Day", time.July, 4, 0},
+}
+
+// Global function for parsing a month name or abbreviation
+func parseMonth(name string) (time.Month, bool) {
+	for month := time.January; month <= time.December; month++ {
+		full := strings.ToLower(month.String())
+		if name == full || (len(name) >= 3 && strings.HasPrefix(full, name)) {
+			return month, true
+		}
+	}
+	return 0, false
+}
+
+// Global function for recognizing a holiday, explicit date, or weekday (ok is false when we can't)
+func parseDateTarget(text string, today time.Time) (dateTarget, bool) {
+	text = strings.TrimPrefix(apostropheRemover.Replace(strings.ToLower(strings.TrimSpace(text))), "the next ")
+	if holiday, ok := fixedHolidays[strings.TrimPrefix(text, "the ")]; ok {
+		return holiday, true
+	}
+
+	if match := isoDatePattern.FindStringSubmatch(text); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		month, _ := strconv.Atoi(match[2])
+		day, _ := strconv.Atoi(match[3])
+		return dateTarget{month: time.Month(month), day: day, year: year}, month >= 1 && month <= 12
+	}
+
+	var monthName, dayText, yearText string
+	if match := monthDayPattern.FindStringSubmatch(text); match != nil {
+		monthName, dayText, yearText = match[1], match[2], match[3]
+	} else if match := dayMonthPattern.FindStringSubmatch(text); match != nil {
+		dayText, monthName, yearText = match[1], match[2], match[3]
+	}
+	if month, ok := parseMonth(monthName); ok {
+		day, _ := strconv.Atoi(dayText)
+		year, _ := strconv.Atoi(yearText)
+		return dateTarget{month: month, day: day, year: year}, true
+	}
+
+	// Weekdays always refer to the next occurrence strictly after today
+	if match := weekdayPattern.FindStringSubmatch(text); match != nil {
+		for offset := 1; offset <= 7; offset++ {
+			candidate := today.AddDate(0, 0, offset)
+			if strings.ToLower(candidate.Weekday().String()) == match[1] {
+				return dateTarget{label: candidate.Weekday().String(), month: candidate.Month(), day: candidate.Day(), year: candidate.Year()}, true
+			}
+		}
+	}
+	return dateTarget{}, false
+}
+
+// Global function for resolving a target to a concrete calendar date relative to today
+func resolveDateTarget(target dateTarget, today time.Time, looksBack bool) (time.Time, bool) {
+	valid := func(candidate time.Time) bool {
+		return candidate.Month() == target.month && candidate.Day() == target.day
+	}
+
+	if target.year != 0 {
+		candidate := time.Date(target.year, target.month, target.day, 0, 0, 0, 0, time.UTC)
+		return candidate, valid(candidate)
+	}
+
+	// Recurring dates roll to next year once passed (or back a year for "since"), skipping years without the date (Feb 29)
+	step := 1
+	if looksBack {
+		step = -1
+	}
+	for offset := 0; offset <= 8; offset++ {
+		candidate := time.Date(today.Year()+offset*step, target.month, target.day, 0, 0, 0, 0, time.UTC)
+		if !valid(candidate) {
+			continue
+		}
+		if (!looksBack && !candidate.Before(today)) || (looksBack && !candidate.After(today)) {
+			return candidate, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Global function for describing a day count in days or weeks
+func describeDayCount(days int, unit string) string {
+	plural := func(count int, noun string) string {
+		if count == 1 {
+			return fmt.Sprintf("1 %s", noun)
+		}
+		return fmt.Sprintf("%d %ss", count, noun)
+	}
+
+	if unit == "weeks" && days >= 7 {
+		if days%7 == 0 {
+			return plural(days/7, "week")
+		}
+		return plural(days/7, "week") + " and " + plural(days%7, "day")
+	}
+	return plural(days, "day")
+}
+
+// Local answerer computing "how many days until/since X" in the asker's timezone
+func answerDateMath(event *slack.MessageEvent) (string, bool) {
+	match := dateMathPattern.FindStringSubmatch(event.Msg.Text)
+	if match == nil {
+		return "", false
+	}
+	unit := strings.ToLower(match[1] + match[2])
+	looksBack := strings.EqualFold(match[3], "since")
+
+	// Comparing calendar dates at UTC midnight so daylight saving shifts never skew the count
+	now := time.Now().In(userLocation(event.User))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	target, ok := parseDateTarget(match[4], today)
+	if !ok {
+		return "", false
+	}
+	date, ok := resolveDateTarget(target, today, looksBack)
+	if !ok {
+		return "", false
+	}
+
+	label := target.label
+	if label == "" {
+		label = date.Format("January 2, 2006")
+	}
+	when := date.Format("Monday, January 2, 2006")
+	days := int(date.Sub(today).Hours() / 24)
+
+	switch {
+	case days == 0:
+		return fmt.Sprintf("%s is today (%s)! :tada:", label, when), true
+	case days > 0 && looksBack:
+		return fmt.Sprintf("%s hasn't happened yet - it's %s away (%s).", label, describeDayCount(days, unit), when), true
+	case days > 0:
+		return fmt.Sprintf("There are %s until %s (%s).", describeDayCount(days, unit), label, when), true
+	case !looksBack:
+		return fmt.Sprintf("%s has already passed - it was %s ago (%s).", label, describeDayCount(-days, unit), when), true
+	default:
+		return fmt.Sprintf("It has been %s since %s (%s).", describeDayCount(-days, unit), label, when), true
+	}
+}
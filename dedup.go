@@ -0,0 +1,80 @@
+//////////////////////////////////////////////////
+// Event Deduplication Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for tracking already-processed messages across restarts
+import (
+	"log"  // Permits console logging
+	"time" // Permits expiry of old records
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constant naming the storage bucket holding processed message records
+const processedEventsBucket = "processed_events"
+
+// Global dedup window consulted before every message is handled, a record leaving it (or evicted beyond capacity)
+// deleted from storage too
+var processedEvents = newBoundedCache("processed_events", func() int { return config.DedupCacheSize }, func(interface{}) time.Duration { return config.DedupWindow }).evictedBy(func(key string, value interface{}) {
+	store.delete(processedEventsBucket, key)
+})
+
+// Global function for building the dedup key of a message (RTM messages carry no event ID)
+func messageEventKey(event *slack.MessageEvent) string {
+	return event.Channel + ":" + event.Timestamp
+}
+
+// Global function for seeding the dedup window from the storage layer on startup
+func loadProcessedEvents() {
+	cutoff := time.Now().Add(-config.DedupWindow)
+
+	for _, key := range store.keys(processedEventsBucket) {
+		var processedAt time.Time
+		if store.get(processedEventsBucket, key, &processedAt) && processedAt.After(cutoff) {
+			processedEvents.putAt(key, processedAt, processedAt)
+		} else {
+			store.delete(processedEventsBucket, key)
+		}
+	}
+	log.Printf("Loaded %d processed message records from storage.", processedEvents.len())
+}
+
+// Global function for recording a message as processed, reporting false when it already was
+func markEventProcessed(key string) bool {
+	if config.DedupWindow <= 0 {
+		return true
+	}
+	now := time.Now()
+	if !processedEvents.add(key, now) {
+		metrics.inc("wolfy_duplicate_events_total")
+		return false
+	}
+
+	// Record is written to the store before handling begins, so a crash mid-answer never re-answers
+	if err := store.put(processedEventsBucket, key, now); err != nil {
+		log.Printf("STORE ERROR: Unable to record processed message %s.\nError Details: %v", key, err)
+	}
+	return true
+}
+
+// Global window of reactions already acted on, keyed by user, message, and reaction, so a quick double-reaction (or a
+// redelivered event) is only handled once
+var processedReactions = newBoundedCache("processed_reactions", func() int { return config.DedupCacheSize }, func(interface{}) time.Duration { return config.ReactionDedupWindow })
+
+// Global function for recording a reaction as handled, reporting false when the same user added the same reaction to
+// the same message within the reaction dedup window
+func markReactionProcessed(event *slack.ReactionAddedEvent) bool {
+	if config.ReactionDedupWindow <= 0 {
+		return true
+	}
+	key := event.User + ":" + event.Item.Channel + ":" + event.Item.Timestamp + ":" + event.Reaction
+	if !processedReactions.add(key, time.Now()) {
+		metrics.inc("wolfy_duplicate_reactions_total", "reaction", event.Reaction)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,183 @@
+//////////////////////////////////////////////////
+// Answer Delivery Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for choosing where answers go and keeping channels tidy
+import (
+	"log"     // Permits console logging
+	"regexp"  // Permits checking of asker user IDs
+	"strings" // Permits channel type detection
+	"time"    // Permits checking of quiet hours
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constant holding the in-channel notice posted when the full answer went to a DM instead
+const dmedAnswerNotice = "That answer is a long one, so I've DMed you the full answer. :envelope_with_arrow:"
+
+// Global function for checking whether a channel ID is a direct message
+func isDirectMessage(channelID string) bool {
+	return strings.HasPrefix(channelID, "D")
+}
+
+// Global function for checking whether a user's answers go to the channel they asked in: their delivery preference,
+// else the workspace default
+func answersInChannel(user string) bool {
+	switch loadPreferences(user).Delivery {
+	case "dm":
+		return false
+	case "channel":
+		return true
+	}
+	return config.AnswerInChannel
+}
+
+// Global function for choosing where an answer is posted: the asker's DM by default, or the channel it was asked in
+// unless that channel is in its quiet hours
+func answerDestination(event *slack.MessageEvent) string {
+	if event.Channel != "" && !isDirectMessage(event.Channel) && answersInChannel(event.User) && !inQuietHours(event.Channel, time.Now()) {
+		return event.Channel
+	}
+	return event.User
+}
+
+// Global pattern recognizing a Slack user ID, the only thing put in an asker mention
+var slackUserIDPattern = regexp.MustCompile(`^[UW][A-Z0-9]+$`)
+
+// Global function for starting a channel answer's top-level message with a mention of its asker, built from their user
+// ID (not their display name) so Slack notifies them. DMs are left alone, as are the threaded continuations of a long
+// answer, their addressee being plain already.
+func addressAsker(event *slack.MessageEvent, channel string, reply string) string {
+	if !config.AddressAsker || channel != event.Channel || isDirectMessage(channel) || !slackUserIDPattern.MatchString(event.User) {
+		return reply
+	}
+	return "<@" + event.User + "> " + reply
+}
+
+// Global function for showing a reply only to the asker: ephemerally in a channel, falling back to their DM
+func postPrivately(event *slack.MessageEvent, text string) {
+	if event.Channel != "" && !isDirectMessage(event.Channel) {
+		_, err := slackAPI().PostEphemeral(event.Channel, event.User, messageText(text), slack.MsgOptionAsUser(true))
+		if err == nil {
+			return
+		}
+		log.Printf("ERROR: Unable to post ephemeral message to %s. Error Msg: %v", event.Channel, err)
+	}
+	postText(event.User, text)
+}
+
+// Global function for splitting an answer into chunks of at most the limit in characters, breaking between lines where it
+// can and closing and reopening code blocks cut in two, so each chunk renders on its own (one chunk when the limit is 0)
+func splitAnswerChunks(text string, limit int) []string {
+	if limit <= 0 || len(graphemeClusters(text)) <= limit {
+		return []string{text}
+	}
+
+	// Leaving room in every chunk for a code block to be reopened at its start and closed at its end
+	budget := limit - 2*len("```\n")
+	if budget < 1 {
+		budget = limit
+	}
+	var pieces [][]string
+	for _, line := range strings.Split(text, "\n") {
+		clusters := graphemeClusters(line)
+		for len(clusters) > budget {
+			pieces = append(pieces, clusters[:budget])
+			clusters = clusters[budget:]
+		}
+		pieces = append(pieces, clusters)
+	}
+
+	var (
+		chunks  []string
+		current []string
+		size    int
+		inFence bool
+	)
+	for _, piece := range pieces {
+		if len(current) > 0 && size+1+len(piece) > budget {
+			chunk := strings.Join(current, "\n")
+			if inFence {
+				chunk += "\n```"
+			}
+			chunks = append(chunks, chunk)
+			current, size = nil, 0
+			if inFence {
+				current = []string{"```"}
+			}
+		}
+		line := strings.Join(piece, "")
+		if len(current) > 0 {
+			size++
+		}
+		current, size = append(current, line), size+len(piece)
+		if strings.Count(line, "```")%2 == 1 {
+			inFence = !inFence
+		}
+	}
+	return append(chunks, strings.Join(current, "\n"))
+}
+
+// Global function for posting an answer's chunks in order, the first replacing the placeholder and the rest threaded
+// under it (when threading is on and the answer is in a channel) with the detail attached to the last; returns where
+// the first landed
+func postAnswerChunks(channel string, placeholderTS string, chunks []string, threaded bool, details []slack.AttachmentField, imageURL string) (string, string) {
+	if len(chunks) == 1 {
+		return replaceMessage(channel, placeholderTS, answerMessageOptions(chunks[0], details, imageURL)...)
+	}
+	metrics.inc("wolfy_chunked_answers_total", "threaded", map[bool]string{true: "yes", false: "no"}[threaded])
+	firstChannel, firstTS := replaceMessage(channel, placeholderTS, messageText(chunks[0]))
+	for i, chunk := range chunks[1:] {
+		options := []slack.MsgOption{messageText(chunk)}
+		if i == len(chunks)-2 {
+			options = answerMessageOptions(chunk, details, imageURL)
+		}
+		if threaded && firstTS != "" {
+			options = append(options, slack.MsgOptionTS(firstTS))
+		}
+		if _, _, err := postMessage(firstChannel, options...); err != nil {
+			log.Printf("ERROR: Unable to post part %d of %d of an answer in %s. Error Msg: %v", i+2, len(chunks), firstChannel, err)
+			break
+		}
+	}
+	return firstChannel, firstTS
+}
+
+// Global function for delivering an answer (replacing a placeholder when given), moving overly long channel answers to the asker's DM,
+// splitting answers too long for one message into chunks, and returning where the reply landed (noted so the answer can be saved later)
+func deliverAnswer(event *slack.MessageEvent, channel string, placeholderTS string, reply string, details []slack.AttachmentField, imageURL string) (replyChannel string, replyTS string) {
+	defer func() { noteAnswerMessage(replyChannel, replyTS, event.Msg.Text, reply) }()
+	reply += quietHoursNote(event, channel)
+	inChannel := channel == event.Channel && !isDirectMessage(channel)
+	if !inChannel || config.LongAnswerDMLength <= 0 || len(graphemeClusters(reply)) <= config.LongAnswerDMLength {
+		chunks := splitAnswerChunks(reply, config.AnswerChunkLength)
+		addressed := append([]string{addressAsker(event, channel, chunks[0])}, chunks[1:]...)
+		respChannel, respTimestamp := postAnswerChunks(channel, placeholderTS, addressed, inChannel && config.AnswerChunkThreads, details, imageURL)
+		if respTimestamp != "" && inChannel {
+			noticeDMMigration(event)
+		}
+		if respTimestamp == "" && inChannel && config.ChannelGoneDM && channelGone(channel) {
+			// Reaching the asker by DM when the bot has been removed from the channel they asked in
+			metrics.inc("wolfy_channel_gone_dms_total")
+			return postAnswerChunks(event.User, "", chunks, false, details, imageURL)
+		}
+		return respChannel, respTimestamp
+	}
+
+	metrics.inc("wolfy_long_answers_total")
+	if _, _, dmChannel, err := slackAPI().OpenIMChannel(event.User); err == nil {
+		if respChannel, respTimestamp := postAnswerChunks(dmChannel, "", splitAnswerChunks(reply, config.AnswerChunkLength), false, details, imageURL); respTimestamp != "" {
+			replaceMessage(channel, placeholderTS, messageText(addressAsker(event, channel, dmedAnswerNotice)))
+			return respChannel, respTimestamp
+		}
+	} else {
+		log.Printf("ERROR: Unable to open DM with %s for a long answer. Error Msg: %v", event.User, err)
+	}
+
+	// Falling back to a truncated answer in the channel when the DM can't be reached
+	return replaceMessage(channel, placeholderTS, answerMessageOptions(addressAsker(event, channel, truncateGraphemes(reply, config.LongAnswerDMLength)), details, imageURL)...)
+}
@@ -0,0 +1,68 @@
+//////////////////////////////////////////////////
+// Answer Delivery Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing how channel answers address their asker
+import (
+	"strings" // Permits building of long answers
+	"testing" // Permits Go testing
+)
+
+// Test for channel and thread answers mentioning their asker by ID, while DMs, answers sent elsewhere, askers that
+// aren't users, and the flag turned off are left alone
+func TestAddressAsker(t *testing.T) {
+	cases := []struct {
+		name    string
+		flag    bool
+		channel string
+		user    string
+		thread  string
+		answer  string
+		want    string
+	}{
+		{"channel", true, "CADDRESS", "UADDRESS", "", "CADDRESS", "<@UADDRESS> 42"},
+		{"thread", true, "CADDRESS", "UADDRESS", "8200.000001", "CADDRESS", "<@UADDRESS> 42"},
+		{"enterprise user", true, "CADDRESS", "WADDRESS", "", "CADDRESS", "<@WADDRESS> 42"},
+		{"DM", true, "DADDRESS", "UADDRESS", "", "DADDRESS", "42"},
+		{"sent to the asker's DM", true, "CADDRESS", "UADDRESS", "", "DADDRESS", "42"},
+		{"bot ID", true, "CADDRESS", "B0123", "", "CADDRESS", "42"},
+		{"empty ID", true, "CADDRESS", "", "", "CADDRESS", "42"},
+		{"injected ID", true, "CADDRESS", "U1> <!channel", "", "CADDRESS", "42"},
+		{"flag off", false, "CADDRESS", "UADDRESS", "", "CADDRESS", "42"},
+	}
+	for _, c := range cases {
+		withConfig(t, func(config *Config) { config.AddressAsker = c.flag })
+		event := testMessage(c.channel, c.user, "8200.000002", "what is 6 * 7")
+		event.ThreadTimestamp = c.thread
+		if got := addressAsker(event, c.answer, "42"); got != c.want {
+			t.Errorf("%s: addressAsker = %q; want %q", c.name, got, c.want)
+		}
+	}
+}
+
+// Test for only the first message of a chunked channel answer mentioning its asker, its continuations left plain
+func TestDeliverAnswerAddressesFirstChunk(t *testing.T) {
+	withConfig(t, func(config *Config) {
+		config.AddressAsker = true
+		config.AnswerChunkLength = 40
+		config.AnswerChunkThreads = true
+		config.LongAnswerDMLength = 0
+	})
+	before := len(postsContaining("CADDRESSCHUNK", "line"))
+	reply := strings.Repeat("a fairly long answer line\n", 3) + "last line"
+	deliverAnswer(testMessage("CADDRESSCHUNK", "UADDRESS", "8200.000003", "tell me lines"), "CADDRESSCHUNK", "", reply, nil, "")
+
+	posts := postsContaining("CADDRESSCHUNK", "line")[before:]
+	if len(posts) < 2 {
+		t.Fatalf("posted %d chunks; want the answer split into several", len(posts))
+	}
+	for i, post := range posts {
+		if addressed := strings.HasPrefix(post.values.Get("text"), "<@UADDRESS> "); addressed != (i == 0) {
+			t.Errorf("chunk %d = %q; want only the first to mention the asker", i+1, post.values.Get("text"))
+		}
+	}
+}
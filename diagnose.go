@@ -0,0 +1,111 @@
+//////////////////////////////////////////////////
+// Backend Diagnostics Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for probing each backend on demand
+import (
+	"context" // Permits bounding each probe with a deadline
+	"fmt"     // Permits formatting of the report
+	"strings" // Permits joining of report lines
+	"time"    // Permits latency measurement
+
+	wolfram "github.com/Krognol/go-wolfram" // External Wolfram API
+	slack "github.com/nlopes/slack"         // External Slack API
+)
+
+// Global struct holding the outcome of one backend probe
+type backendProbe struct {
+	name    string
+	latency time.Duration
+	detail  string
+	err     error
+}
+
+// Global list of backends probed by "!diagnose", each with the trivial call it makes
+var diagnosticProbes = []struct {
+	name  string
+	probe func(ctx context.Context, event *slack.MessageEvent) (string, error)
+}{
+	{"Wit.ai", probeWit},
+	{"Wolfram|Alpha", probeWolfram},
+	{"Slack", probeSlack},
+}
+
+// Global function for classifying a throwaway greeting with Wit.ai
+func probeWit(ctx context.Context, event *slack.MessageEvent) (string, error) {
+	if !externalAPIsAllowed() {
+		return "", errExternalAPIsDisabled
+	}
+	recordAPICall(ctx, "wit")
+	res, err := witMessageWithin(ctx, "hello")
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d entities", len(res.Entities)), nil
+}
+
+// Global function for asking Wolfram|Alpha a question with a known answer, using the admin's own App ID
+func probeWolfram(ctx context.Context, event *slack.MessageEvent) (string, error) {
+	answer, err := fetchShortAnswer(ctx, wolframClientFor(event), "2+2", wolfram.Metric)
+	if err != nil {
+		return "", err
+	}
+	if answer != "4" {
+		return "", fmt.Errorf("unexpected reply %q", truncateGraphemes(answer, 80))
+	}
+	return "2+2 = 4", nil
+}
+
+// Global function for checking our Slack token is still accepted
+func probeSlack(ctx context.Context, event *slack.MessageEvent) (string, error) {
+	identity, err := slackAPI().AuthTestContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("authenticated as %s on %s", identity.User, identity.Team), nil
+}
+
+// Global function for running every probe concurrently, each under its own deadline, in the listed order
+func runDiagnosticProbes(event *slack.MessageEvent) []backendProbe {
+	results := make([]backendProbe, len(diagnosticProbes))
+	done := make(chan struct{}, len(diagnosticProbes))
+	for i, check := range diagnosticProbes {
+		go func(i int, name string, probe func(context.Context, *slack.MessageEvent) (string, error)) {
+			ctx, cancel := context.WithTimeout(context.Background(), config.DiagnoseTimeout)
+			defer cancel()
+
+			started := time.Now()
+			detail, err := probe(ctx, event)
+			if ctx.Err() == context.DeadlineExceeded {
+				err = fmt.Errorf("no response within %s", config.DiagnoseTimeout)
+			}
+			results[i] = backendProbe{name: name, latency: time.Since(started), detail: detail, err: err}
+			done <- struct{}{}
+		}(i, check.name, check.probe)
+	}
+	for range diagnosticProbes {
+		<-done
+	}
+	return results
+}
+
+// Admin command probing Wit.ai, Wolfram|Alpha, and Slack live and reporting latency and status for each
+func runAdminDiagnose(event *slack.MessageEvent, args []string) string {
+	lines := []string{fmt.Sprintf("*Backend diagnostics* (timeout %s):", config.DiagnoseTimeout)}
+	for _, result := range runDiagnosticProbes(event) {
+		latency := result.latency.Round(time.Millisecond)
+		switch {
+		case result.err == errExternalAPIsDisabled:
+			lines = append(lines, fmt.Sprintf(":double_vertical_bar: %s - skipped (external APIs are off)", result.name))
+		case result.err != nil:
+			lines = append(lines, fmt.Sprintf(":x: %s - failed after %s: %v", result.name, latency, result.err))
+		default:
+			lines = append(lines, fmt.Sprintf(":white_check_mark: %s - ok in %s (%s)", result.name, latency, result.detail))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,87 @@
+//////////////////////////////////////////////////
+// DM Migration Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for telling users used to DM answers that answers now land in the channel they asked in
+import (
+	"fmt"  // Permits formatting of the notice deadline error
+	"log"  // Permits console logging
+	"sync" // Permits serialized claiming of notices
+	"time" // Permits the notice period and notice timestamps
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constants naming the store bucket recording who has had the DM migration notice, and its text
+const (
+	dmMigrationNoticesBucket = "dm_migration_notices"
+	dmMigrationNotice        = ":mailbox_with_mail: Heads up: I now answer questions in the channel they're asked in, rather than by DM, so everyone there can see the answer. If you'd rather keep getting my answers here, say \"wolfy set delivery dm\" (or pick it from \"wolfy preferences\")."
+)
+
+// Global mutex serializing notice claims so two quick channel answers only send one notice
+var dmMigrationMu sync.Mutex
+
+// Global function for parsing the last day the notice is sent ("2006-01-02"), returning the moment it stops: the start
+// of the following day in the given location
+func parseDMMigrationDeadline(value string, location *time.Location) (time.Time, error) {
+	day, err := time.ParseInLocation("2006-01-02", value, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a date like 2006-01-02", value)
+	}
+	return day.AddDate(0, 0, 1), nil
+}
+
+// Global function for checking whether the migration period is still running (it is off when no last day is set)
+func dmMigrationActive(now time.Time) bool {
+	if config.DMMigrationNoticeUntil == "" {
+		return false
+	}
+	location, err := time.LoadLocation(config.DefaultTimezone)
+	if err != nil {
+		location = time.UTC
+	}
+	deadline, err := parseDMMigrationDeadline(config.DMMigrationNoticeUntil, location)
+	return err == nil && now.Before(deadline)
+}
+
+// Global function for checking whether every answer in a user's history reached them by DM (history from before
+// answers were placed in channels always did), and there is history to go on
+func answeredOnlyByDM(user string) bool {
+	entries := userHistory(user)
+	for _, entry := range entries {
+		if entry.AnsweredInChannel {
+			return false
+		}
+	}
+	return len(entries) > 0
+}
+
+// Global function for claiming a user's migration notice, persisting it so restarts never send it twice
+func claimDMMigrationNotice(user string) bool {
+	dmMigrationMu.Lock()
+	defer dmMigrationMu.Unlock()
+
+	var noticed time.Time
+	if store.get(dmMigrationNoticesBucket, user, &noticed) || !answeredOnlyByDM(user) {
+		return false
+	}
+	if err := store.put(dmMigrationNoticesBucket, user, time.Now()); err != nil {
+		log.Printf("DM MIGRATION ERROR: Unable to record the migration notice for %s.\nError Details: %v", user, err)
+		return false
+	}
+	return true
+}
+
+// Global function for DMing the one-time migration notice to an asker just answered in a channel for the first time,
+// while the migration period runs
+func noticeDMMigration(event *slack.MessageEvent) {
+	if store == nil || !dmMigrationActive(time.Now()) || !claimDMMigrationNotice(event.User) {
+		return
+	}
+	metrics.inc("wolfy_dm_migration_notices_total")
+	postText(event.User, dmMigrationNotice)
+}
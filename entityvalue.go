@@ -0,0 +1,63 @@
+//////////////////////////////////////////////////
+// Entity Value Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for turning whatever Wit.ai extracted into a query handlers can use
+import (
+	"context" // Permits tracing of the resolution
+	"log"     // Permits console logging
+	"strconv" // Permits formatting of numeric values
+	"strings" // Permits cleaning of the message text
+
+	wit "github.com/christianrondeau/go-wit" // External Wit.ai API
+	slack "github.com/nlopes/slack"          // External Slack API
+)
+
+// Global function for resolving an entity's value into a query string, reporting why it fell back to the message text
+// ("" when the value was used). Plain string values pass through; numbers and booleans are written out; intervals
+// (which carry from/to rather than a value), datetime and other structured values, and extractions shorter than the
+// configured minimum are never the question itself, so the whole message is asked instead.
+func entityQuery(entity wit.MessageEntity, message string) (string, string) {
+	text := strings.Join(strings.Fields(message), " ")
+	switch value := entity.Value.(type) {
+	case string:
+		value = strings.TrimSpace(value)
+		switch {
+		case value == "":
+			return text, "empty"
+		case len(graphemeClusters(value)) < config.MinEntityQueryLength && len(graphemeClusters(text)) > len(graphemeClusters(value)):
+			return text, "short"
+		}
+		return value, ""
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), ""
+	case bool:
+		return strconv.FormatBool(value), ""
+	case nil:
+		if entity.Type != nil && *entity.Type == "interval" {
+			return text, "interval"
+		}
+		return text, "missing"
+	}
+	return text, "structured"
+}
+
+// Global function for replacing a Wit.ai entity's value with its resolved query before it is dispatched, so no handler
+// is handed a value that isn't a string, logging when the message text stood in for it
+func resolveEntityValue(ctx context.Context, event *slack.MessageEvent, entityKey string, entity wit.MessageEntity) wit.MessageEntity {
+	if entityKey == "" {
+		return entity
+	}
+	query, fallback := entityQuery(entity, event.Msg.Text)
+	if fallback != "" {
+		metrics.inc("wolfy_entity_value_fallbacks_total", "intent", entityKey, "reason", fallback)
+		log.Printf("ROUTING: Wit.ai gave %s a %s value (%v); asking the message text %q instead.", entityKey, fallback, entity.Value, query)
+		traceStep(ctx, "wit.ai value for %s was %s; using the message text", entityKey, fallback)
+	}
+	entity.Value = query
+	return entity
+}
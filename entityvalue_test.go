@@ -0,0 +1,70 @@
+//////////////////////////////////////////////////
+// Entity Value Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing the queries made from each shape of value Wit.ai extracts
+import (
+	"context"       // Permits resolution without a route trace
+	"encoding/json" // Permits decoding of Wit.ai fixtures
+	"testing"       // Permits Go testing
+
+	wit "github.com/christianrondeau/go-wit" // External Wit.ai API
+)
+
+// Test for every shape of entity Wit.ai returns resolving to a string query, its own value when that is usable and the
+// message text otherwise, counted by why it fell back
+func TestResolveEntityValue(t *testing.T) {
+	withConfig(t, func(c *Config) { c.MinEntityQueryLength = 3 })
+	const message = "how  far is the moon from earth"
+	cases := []struct {
+		name     string
+		fixture  string
+		query    string
+		fallback string
+	}{
+		{"string", `{"confidence": 0.97, "value": " distance to the moon ", "type": "value"}`, "distance to the moon", ""},
+		{"empty string", `{"confidence": 0.51, "value": "  ", "type": "value"}`, "how far is the moon from earth", "empty"},
+		{"short string", `{"confidence": 0.62, "value": "mo", "type": "value"}`, "how far is the moon from earth", "short"},
+		{"number", `{"confidence": 0.9, "value": 384400.5, "type": "value", "unit": "kilometre"}`, "384400.5", ""},
+		{"boolean", `{"confidence": 0.8, "value": true}`, "true", ""},
+		{"interval", `{"confidence": 0.9, "type": "interval", "from": {"value": "2026-10-14T00:00:00.000-07:00", "grain": "day"}, "to": {"value": "2026-10-16T00:00:00.000-07:00", "grain": "day"}}`, "how far is the moon from earth", "interval"},
+		{"missing value", `{"confidence": 0.4, "type": "value"}`, "how far is the moon from earth", "missing"},
+		{"structured value", `{"confidence": 0.9, "type": "value", "value": {"value": "moon", "grain": "second"}}`, "how far is the moon from earth", "structured"},
+		{"nested values", `{"confidence": 0.9, "type": "value", "value": [{"value": "moon"}, {"value": ["earth", 1]}]}`, "how far is the moon from earth", "structured"},
+	}
+	for _, c := range cases {
+		var entity wit.MessageEntity
+		if err := json.Unmarshal([]byte(c.fixture), &entity); err != nil {
+			t.Fatalf("%s: decoding the fixture: %v", c.name, err)
+		}
+		before := metricValue("wolfy_entity_value_fallbacks_total", "intent", "wolfram_search_query", "reason", c.fallback)
+		resolved := resolveEntityValue(context.Background(), testMessage("CENTITY", "UENTITY", "7700.000001", message), "wolfram_search_query", entity)
+		if query, ok := resolved.Value.(string); !ok || query != c.query {
+			t.Errorf("%s: resolveEntityValue(%s) value = %#v; want %q", c.name, c.fixture, resolved.Value, c.query)
+		}
+		if c.fallback != "" {
+			if got := metricValue("wolfy_entity_value_fallbacks_total", "intent", "wolfram_search_query", "reason", c.fallback) - before; got != 1 {
+				t.Errorf("%s: %s fallbacks counted = %d; want 1", c.name, c.fallback, got)
+			}
+		}
+	}
+}
+
+// Test for values of types no Wit.ai fixture produces still resolving to the message text rather than panicking, and
+// an entity with no key being passed through untouched
+func TestResolveEntityValueUnexpectedTypes(t *testing.T) {
+	event := testMessage("CENTITY", "UENTITY", "7700.000002", "distance to mars")
+	for _, value := range []interface{}{42, int64(-7), []string{"mars"}, map[string]string{"value": "mars"}, struct{}{}, json.Number("12"), &event.Msg} {
+		resolved := resolveEntityValue(context.Background(), event, "wolfram_search_query", wit.MessageEntity{Value: value})
+		if resolved.Value != "distance to mars" {
+			t.Errorf("resolveEntityValue(%T) value = %#v; want the message text", value, resolved.Value)
+		}
+	}
+	if resolved := resolveEntityValue(context.Background(), event, "", wit.MessageEntity{Value: 42}); resolved.Value != 42 {
+		t.Errorf("resolveEntityValue without an entity key = %#v; want the value untouched", resolved.Value)
+	}
+}
@@ -0,0 +1,189 @@
+//////////////////////////////////////////////////
+// Recent Errors Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for keeping the most recent logged errors on hand for "!errors"
+import (
+	"crypto/rand" // Permits generation of correlation IDs
+	"fmt"         // Permits formatting of the error listing
+	"io"          // Permits wrapping the log output
+	"regexp"      // Permits recognizing error lines and secrets within them
+	"strconv"     // Permits parsing of the listing size
+	"strings"     // Permits string manipulation
+	"sync"        // Permits concurrency-safe buffer access
+	"time"        // Permits error timestamps
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constant capping how much of an error's details is kept
+const recentErrorDetailBytes = 300
+
+// Global patterns recognizing a logged error's category ("WOLFRAM ERROR: ...") and secrets that must not be shown
+var (
+	errorLinePattern   = regexp.MustCompile(`(?:^|\s)((?:[A-Z][A-Z0-9./|-]*\s)*?)ERROR:\s*`)
+	secretTokenPattern = regexp.MustCompile(`xox[abposr]-[A-Za-z0-9-]+|(?i)(appid=|bearer\s+|token=)[^&\s"]+`)
+)
+
+// Global struct holding one logged error
+type recentError struct {
+	ID       string
+	At       time.Time
+	Category string
+	Message  string
+	Details  string
+}
+
+// Global struct holding the ring buffer of recent errors, fed by the log output it wraps
+type recentErrorLog struct {
+	mu     sync.Mutex
+	out    io.Writer
+	recent []recentError
+	next   int
+}
+
+// Global buffer of recent errors shared by the logger and the admin commands
+var recentErrors = &recentErrorLog{}
+
+// Global function for generating a short correlation ID tying a listed error to its full log line
+func newCorrelationID() string {
+	random := make([]byte, 4)
+	if _, err := rand.Read(random); err != nil {
+		return fmt.Sprintf("e-%x", time.Now().UnixNano()&0xffffffff)
+	}
+	return fmt.Sprintf("e-%x", random)
+}
+
+// Global function for redacting credentials (configured or token-shaped) from error text before it is shown in Slack
+func redactSecrets(text string) string {
+	if credentials, ok := currentCredentials.Load().(apiCredentials); ok {
+		for _, secret := range []string{credentials.slackToken, credentials.witToken, credentials.wolframAppID} {
+			if len(secret) >= 6 {
+				text = strings.Replace(text, secret, "[redacted]", -1)
+			}
+		}
+	}
+	return secretTokenPattern.ReplaceAllStringFunc(text, func(secret string) string {
+		if prefix := secretTokenPattern.FindStringSubmatch(secret)[1]; prefix != "" {
+			return prefix + "[redacted]"
+		}
+		return "[redacted]"
+	})
+}
+
+// Global function for routing log output through the error buffer, which tags each error line with its correlation ID
+func captureRecentErrors(out io.Writer) io.Writer {
+	recentErrors.mu.Lock()
+	recentErrors.out = out
+	recentErrors.mu.Unlock()
+	return recentErrors
+}
+
+// Method for writing a log line through, buffering it first when it's an error
+func (buffer *recentErrorLog) Write(line []byte) (int, error) {
+	text := string(line)
+	location := errorLinePattern.FindStringSubmatchIndex(text)
+	if location == nil {
+		return buffer.out.Write(line)
+	}
+
+	category := strings.TrimSpace(text[location[2]:location[3]])
+	if category == "" {
+		category = "GENERAL"
+	}
+	message, details := strings.TrimRight(text[location[1]:], "\n"), ""
+	if newline := strings.Index(message, "\n"); newline >= 0 {
+		message, details = message[:newline], strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(message[newline+1:]), "Error Details:"))
+	}
+	if truncated, ok := truncateBytes(details, recentErrorDetailBytes); ok {
+		details = truncated + "..."
+	}
+	entry := recentError{ID: newCorrelationID(), At: time.Now(), Category: category, Message: redactSecrets(message), Details: redactSecrets(details)}
+	buffer.add(entry)
+	metrics.inc("wolfy_logged_errors_total", "category", strings.ToLower(category))
+
+	// Tagging the first line of the log entry with the ID, so "!errors" output can be found in the full logs
+	tagged := text
+	if newline := strings.Index(text, "\n"); newline >= 0 {
+		tagged = text[:newline] + " [" + entry.ID + "]" + text[newline:]
+	}
+	if _, err := buffer.out.Write([]byte(tagged)); err != nil {
+		return 0, err
+	}
+	return len(line), nil
+}
+
+// Method for storing an error in the fixed-size ring buffer, overwriting the oldest entry once full
+func (buffer *recentErrorLog) add(entry recentError) {
+	buffer.mu.Lock()
+	defer buffer.mu.Unlock()
+
+	size := 0
+	if config != nil {
+		size = config.ErrorBufferSize
+	}
+	if size <= 0 {
+		return
+	}
+	if len(buffer.recent) > size {
+		// Dropping entries past a size lowered since they were buffered
+		buffer.recent, buffer.next = buffer.recent[:0], 0
+	}
+	if len(buffer.recent) < size {
+		buffer.recent = append(buffer.recent, entry)
+	} else {
+		buffer.recent[buffer.next] = entry
+	}
+	buffer.next = (buffer.next + 1) % size
+}
+
+// Method for listing the buffered errors, newest first
+func (buffer *recentErrorLog) snapshot() []recentError {
+	buffer.mu.Lock()
+	defer buffer.mu.Unlock()
+
+	entries := make([]recentError, 0, len(buffer.recent))
+	for i := 1; i <= len(buffer.recent); i++ {
+		entries = append(entries, buffer.recent[(buffer.next-i+len(buffer.recent))%len(buffer.recent)])
+	}
+	return entries
+}
+
+// Admin command listing recent errors, optionally only one category or the newest few: `!errors [count] [category]`
+func runAdminErrors(event *slack.MessageEvent, args []string) string {
+	limit, category := 10, ""
+	for _, arg := range args {
+		if count, err := strconv.Atoi(arg); err == nil && count > 0 {
+			limit = count
+		} else {
+			category = strings.ToUpper(arg)
+		}
+	}
+
+	entries := recentErrors.snapshot()
+	lines := make([]string, 0, limit)
+	for _, entry := range entries {
+		if len(lines) == limit {
+			break
+		}
+		if category != "" && entry.Category != category {
+			continue
+		}
+		line := fmt.Sprintf("`%s` %s *%s* %s", entry.ID, entry.At.Format("Jan 2 15:04:05"), entry.Category, entry.Message)
+		if entry.Details != "" {
+			line += "\n> " + strings.Replace(entry.Details, "\n", "\n> ", -1)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		if category != "" {
+			return fmt.Sprintf("No %s errors buffered.", category)
+		}
+		return "No errors logged since startup (or the buffer is off, see WOLFY_ERROR_BUFFER_SIZE). :tada:"
+	}
+	return fmt.Sprintf("*Recent errors (newest first, %d of %d buffered):*\n%s", len(lines), len(entries), strings.Join(lines, "\n"))
+}
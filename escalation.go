@@ -0,0 +1,142 @@
+//////////////////////////////////////////////////
+// Human Escalation Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for offering to pass unanswerable questions on to a human support channel
+import (
+	"context" // Permits scoped message lookups
+	"fmt"     // Permits formatting of offers and forwarded questions
+	"log"     // Permits console logging
+	"regexp"  // Permits matching of confirmations
+	"sync"    // Permits serializing escalation updates
+	"time"    // Permits offer expiry
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constant naming the store bucket holding each user's latest escalation
+const escalationsBucket = "escalations"
+
+// Global constants naming the states an escalation moves through
+const (
+	escalationOffered   = "offered"
+	escalationForwarded = "forwarded"
+	escalationDeclined  = "declined"
+)
+
+// Global struct holding an unanswered question we offered to pass on, and what became of it
+type escalation struct {
+	Question   string
+	Reply      string
+	Channel    string
+	Timestamp  string
+	State      string
+	OfferedAt  time.Time
+	ResolvedAt time.Time
+	SupportTS  string
+}
+
+// Global mutex serializing read-modify-write of escalations
+var escalationsMu sync.Mutex
+
+// Global patterns recognizing a yes or no to an escalation offer
+var (
+	escalateYesPattern = regexp.MustCompile(`(?i)^\s*(?:wolfy\s+)?(?:yes(?:\s+please)?|yep|yeah|sure|ask\s+a\s+human|escalate(?:\s+(?:it|this|that))?)\s*[.!]*\s*$`)
+	escalateNoPattern  = regexp.MustCompile(`(?i)^\s*(?:wolfy\s+)?(?:no(?:\s+thanks)?|nope|don't\s+bother)\s*[.!]*\s*$`)
+)
+
+// Global function for offering to pass a question on when our reply is a dead end, returning the reply with the offer added
+func withEscalationOffer(ctx context.Context, event *slack.MessageEvent, reply string) string {
+	if config.SupportChannel == "" || event.Channel == config.SupportChannel || store == nil {
+		return reply
+	}
+	if !isPackMessage(reply, "unclear_input", "wolfram_unclear") {
+		return reply
+	}
+
+	escalationsMu.Lock()
+	defer escalationsMu.Unlock()
+	pending := escalation{Question: event.Msg.Text, Reply: reply, Channel: event.Channel, Timestamp: event.Timestamp, State: escalationOffered, OfferedAt: time.Now()}
+	if err := store.put(escalationsBucket, event.User, pending); err != nil {
+		log.Printf("ESCALATION ERROR: Unable to record an escalation offer for %s.\nError Details: %v", event.User, err)
+		return reply
+	}
+	metrics.inc("wolfy_escalations_total", "state", escalationOffered)
+	traceStep(ctx, "offered to escalate to the support channel")
+	return fmt.Sprintf("%s\n_Want a human to take a look? Reply \"ask a human\" (or \"yes\") within %s and I'll pass your question to <#%s>._", reply, config.EscalationWindow, config.SupportChannel)
+}
+
+// Global function for loading a user's escalation offer that is still open
+func pendingEscalation(user string) (escalation, bool) {
+	var pending escalation
+	if store == nil || !store.get(escalationsBucket, user, &pending) {
+		return escalation{}, false
+	}
+	if pending.State != escalationOffered || time.Since(pending.OfferedAt) > config.EscalationWindow {
+		return escalation{}, false
+	}
+	return pending, true
+}
+
+// Global function for describing where a question was asked, for the support channel
+func askedWhere(pending escalation) string {
+	if isDirectMessage(pending.Channel) {
+		return "a DM"
+	}
+	return "<#" + pending.Channel + ">"
+}
+
+// Global function for forwarding a user's open escalation to the support channel, returning the reply for the user
+func forwardEscalation(user string, pending escalation) string {
+	text := fmt.Sprintf(":raising_hand: *<@%s> asked something I couldn't answer* (in %s):\n>%s\nI replied: %s", user, askedWhere(pending), pending.Question, pending.Reply)
+	if !isDirectMessage(pending.Channel) && pending.Timestamp != "" {
+		if permalink, err := slackAPI().GetPermalink(&slack.PermalinkParameters{Channel: pending.Channel, Ts: pending.Timestamp}); err == nil {
+			text += "\n<" + permalink + "|View the question>"
+		}
+	}
+	_, timestamp, err := postMessage(config.SupportChannel, messageText(text))
+	if err != nil {
+		log.Printf("ESCALATION ERROR: Unable to forward %s's question to %s.\nError Details: %v", user, config.SupportChannel, err)
+		return "Sorry, I couldn't reach the support channel just now. Please try again in a bit. :-("
+	}
+
+	pending.State, pending.ResolvedAt, pending.SupportTS = escalationForwarded, time.Now(), timestamp
+	if err := store.put(escalationsBucket, user, pending); err != nil {
+		log.Printf("ESCALATION ERROR: Unable to record the forwarded escalation for %s.\nError Details: %v", user, err)
+	}
+	metrics.inc("wolfy_escalations_total", "state", escalationForwarded)
+	return fmt.Sprintf("Done - I've passed your question to <#%s>, and someone will follow up with you there or by DM.", config.SupportChannel)
+}
+
+// Global function for handling a yes or no to an open escalation offer, reporting whether the message was consumed
+func handleEscalationReply(event *slack.MessageEvent) bool {
+	yes, no := escalateYesPattern.MatchString(event.Msg.Text), escalateNoPattern.MatchString(event.Msg.Text)
+	if !yes && !no {
+		return false
+	}
+
+	escalationsMu.Lock()
+	defer escalationsMu.Unlock()
+	pending, ok := pendingEscalation(event.User)
+	if !ok {
+		// Without an open offer, "yes" and "no" are ordinary messages
+		return false
+	}
+
+	metrics.inc("wolfy_intents_total", "intent", "escalation_reply")
+	if yes {
+		postText(answerDestination(event), forwardEscalation(event.User, pending))
+		return true
+	}
+	pending.State, pending.ResolvedAt = escalationDeclined, time.Now()
+	if err := store.put(escalationsBucket, event.User, pending); err != nil {
+		log.Printf("ESCALATION ERROR: Unable to record the declined escalation for %s.\nError Details: %v", event.User, err)
+	}
+	metrics.inc("wolfy_escalations_total", "state", escalationDeclined)
+	postText(answerDestination(event), "No problem - I won't pass it on.")
+	return true
+}
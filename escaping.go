@@ -0,0 +1,61 @@
+//////////////////////////////////////////////////
+// Slack Escaping Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for escaping Slack's control characters in posted text without breaking our own mentions and links
+import (
+	"regexp"  // Permits recognition of intended Slack markup
+	"strings" // Permits building of the escaped text
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global pattern recognizing what Slack should still interpret: mentions and channel links ("<@U123>", "<#C123|general>",
+// "<!date^...>"), links ("<https://...|label>"), and characters we already escaped ("&amp;")
+var slackMarkupPattern = regexp.MustCompile(`<(?:[@#!][A-Za-z0-9^|-][^<>]*|(?:https?|mailto):[^\s<>|]+(?:\|[^<>]*)?)>|&(?:amp|lt|gt);`)
+
+// Global pattern recognizing the broadcast mentions ("<!here>", "<!channel>", "<!everyone>"), which are escaped like
+// plain text so a question echoed back in a reply can never notify a whole channel
+var broadcastMentionPattern = regexp.MustCompile(`^<!(?:here|channel|everyone)(?:\|[^<>]*)?>$`)
+
+// Global replacer escaping the three characters Slack treats as control characters in message text
+var slackTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Global function for escaping "&", "<", and ">" in text bound for Slack, so answers like "x < 5 & y > 2" render as written
+// while intended markup, other than broadcast mentions, passes through
+func escapeSlackText(text string) string {
+	if !config.EscapeReplies {
+		return text
+	}
+	var escaped strings.Builder
+	last := 0
+	for _, span := range slackMarkupPattern.FindAllStringIndex(text, -1) {
+		if broadcastMentionPattern.MatchString(text[span[0]:span[1]]) {
+			continue
+		}
+		escaped.WriteString(slackTextEscaper.Replace(text[last:span[0]]))
+		escaped.WriteString(text[span[0]:span[1]])
+		last = span[1]
+	}
+	escaped.WriteString(slackTextEscaper.Replace(text[last:]))
+	return escaped.String()
+}
+
+// Global function for building a post's text: escaped for Slack, then tagged when running as a staging instance
+func messageText(text string) slack.MsgOption {
+	return slack.MsgOptionText(stagingTagged(escapeSlackText(text)), false)
+}
+
+// Global function for escaping an attachment's fallback and field text the same way as message text
+func escapeAttachmentFields(fields []slack.AttachmentField) []slack.AttachmentField {
+	escaped := make([]slack.AttachmentField, len(fields))
+	for i, field := range fields {
+		field.Title, field.Value = escapeSlackText(field.Title), escapeSlackText(field.Value)
+		escaped[i] = field
+	}
+	return escaped
+}
@@ -0,0 +1,36 @@
+//////////////////////////////////////////////////
+// Slack Escaping Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing escaping of posted text
+import (
+	"testing" // Permits Go testing
+)
+
+// Test for escaping Slack's control characters while intended markup passes through, except broadcast mentions
+func TestEscapeSlackText(t *testing.T) {
+	withConfig(t, func(c *Config) { c.EscapeReplies = true })
+	cases := []struct {
+		text string
+		want string
+	}{
+		{"x < 5 & y > 2", "x &lt; 5 &amp; y &gt; 2"},
+		{"<@U123> asked: is 1 < 2?", "<@U123> asked: is 1 &lt; 2?"},
+		{"see <#C123|general> or <https://example.com|the docs>", "see <#C123|general> or <https://example.com|the docs>"},
+		{"already &amp; escaped &lt;3", "already &amp; escaped &lt;3"},
+		{"<!date^1500000000^{date_short}|July 14>", "<!date^1500000000^{date_short}|July 14>"},
+		{"<!here> what is 2 + 2", "&lt;!here&gt; what is 2 + 2"},
+		{"<@U1> asked: _<!channel|channel> pi_", "<@U1> asked: _&lt;!channel|channel&gt; pi_"},
+		{"<!everyone> <@U2> <!here>", "&lt;!everyone&gt; <@U2> &lt;!here&gt;"},
+		{"<not a link>", "&lt;not a link&gt;"},
+	}
+	for _, c := range cases {
+		if got := escapeSlackText(c.text); got != c.want {
+			t.Errorf("escapeSlackText(%q) = %q; want %q", c.text, got, c.want)
+		}
+	}
+}
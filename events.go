@@ -0,0 +1,96 @@
+//////////////////////////////////////////////////
+// Unhandled Events Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for tracking RTM events the Slackbot does not handle
+import (
+	"encoding/json" // Permits serialization of event payload samples
+	"log"           // Permits console logging
+	"sync"          // Permits concurrency-safe tracking state
+	"time"          // Permits per-type log rate limiting
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global struct holding a single sampled unhandled event
+type unhandledEvent struct {
+	Type       string
+	ReceivedAt time.Time
+	Sample     string
+}
+
+// Global struct holding the rate-limit state and ring buffer of unhandled events
+type unhandledEventTracker struct {
+	mu         sync.Mutex
+	lastLogged map[string]time.Time
+	recent     []unhandledEvent
+	next       int
+}
+
+// Global tracker shared by the event loop and the admin commands
+var unhandledEvents = &unhandledEventTracker{lastLogged: map[string]time.Time{}}
+
+// Global function for counting (and in debug mode, sampling) an event the loop does not handle
+func recordUnhandledEvent(msg slack.RTMEvent) {
+	metrics.inc("wolfy_rtm_unhandled_events_total", "type", msg.Type)
+
+	if !config.Debug {
+		return
+	}
+
+	event := unhandledEvent{Type: msg.Type, ReceivedAt: time.Now(), Sample: sampleEventPayload(msg.Data)}
+	if unhandledEvents.add(event) {
+		log.Printf("DEBUG: Unhandled RTM event type %q. Sample payload: %s", event.Type, event.Sample)
+	}
+}
+
+// Global function for serializing an event payload, capped to the configured sample size
+func sampleEventPayload(data interface{}) string {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return "<unserializable payload>"
+	}
+	if sample, truncated := truncateBytes(string(payload), config.UnhandledEventSampleBytes); truncated {
+		return sample + "...(truncated)"
+	}
+	return string(payload)
+}
+
+// Method for buffering an event, reporting whether its type is due to be logged again
+func (tracker *unhandledEventTracker) add(event unhandledEvent) bool {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	// Storing the event in the fixed-size ring buffer, overwriting the oldest entry once full
+	if size := config.UnhandledEventBufferSize; size > 0 {
+		if len(tracker.recent) < size {
+			tracker.recent = append(tracker.recent, event)
+		} else {
+			tracker.recent[tracker.next] = event
+		}
+		tracker.next = (tracker.next + 1) % size
+	}
+
+	// Logging each event type at most once per interval to avoid spamming busy workspaces
+	if last, seen := tracker.lastLogged[event.Type]; seen && event.ReceivedAt.Sub(last) < config.UnhandledEventLogInterval {
+		return false
+	}
+	tracker.lastLogged[event.Type] = event.ReceivedAt
+	return true
+}
+
+// Method for listing the buffered events, newest first
+func (tracker *unhandledEventTracker) snapshot() []unhandledEvent {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	events := make([]unhandledEvent, 0, len(tracker.recent))
+	for i := 1; i <= len(tracker.recent); i++ {
+		events = append(events, tracker.recent[(tracker.next-i+len(tracker.recent))%len(tracker.recent)])
+	}
+	return events
+}
@@ -0,0 +1,70 @@
+//////////////////////////////////////////////////
+// Example External Handler Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for an example handler the bot runs as an external command (see WOLFY_EXTERNAL_HANDLERS_FILE)
+package main
+
+// Global imports for reading the bot's request and writing a response
+import (
+	"encoding/json" // Permits decoding of the request and encoding of the response
+	"fmt"           // Permits formatting of the answer
+	"os"            // Permits reading of stdin and writing of stdout
+	"strings"       // Permits matching of knowledge base topics
+)
+
+// Global struct holding the request the bot writes to stdin
+type request struct {
+	Handler    string  `json:"handler"`
+	Text       string  `json:"text"`
+	Value      string  `json:"value"`
+	Confidence float64 `json:"confidence"`
+	User       string  `json:"user"`
+	Channel    string  `json:"channel"`
+	ThreadTS   string  `json:"thread_ts,omitempty"`
+}
+
+// Global struct holding one detail shown under the answer
+type detail struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// Global struct holding the response the bot reads from stdout
+type response struct {
+	Text    string   `json:"text"`
+	Details []detail `json:"details,omitempty"`
+	Image   string   `json:"image,omitempty"`
+}
+
+// Global map standing in for an internal knowledge base, by topic
+var knowledgeBase = map[string]string{
+	"vpn":     "Connect to the VPN with the company client, using your SSO login.",
+	"wifi":    "The office Wi-Fi is \"corp\"; sign in with your SSO login.",
+	"expense": "Expenses are filed in the finance portal within 30 days.",
+}
+
+// Main function for answering one request: a non-zero exit when it can't be read, else the matching article (or a
+// note that there is none)
+func main() {
+	var req request
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to read the request: %v\n", err)
+		os.Exit(1)
+	}
+
+	question := strings.ToLower(req.Text)
+	res := response{Text: "I couldn't find anything in the knowledge base about that."}
+	for topic, article := range knowledgeBase {
+		if strings.Contains(question, topic) {
+			res = response{Text: article, Details: []detail{{Title: "Topic", Value: topic}, {Title: "Source", Value: "Knowledge base"}}}
+			break
+		}
+	}
+
+	if err := json.NewEncoder(os.Stdout).Encode(res); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to write the response: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,96 @@
+//////////////////////////////////////////////////
+// Error Message Expiry Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for tidying away the bot's own error and clarification replies once they've gone stale
+import (
+	"log"  // Permits console logging
+	"time" // Permits deletion delays
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constants naming the store bucket for pending deletions, the delay past which they are persisted, and how often they are swept
+const (
+	expiringMessagesBucket   = "expiring_messages"
+	persistedExpiryThreshold = 5 * time.Minute
+	expirySweepInterval      = time.Minute
+)
+
+// Global struct holding a reply waiting to be deleted
+type expiringMessage struct {
+	Channel   string
+	Timestamp string
+	DeleteAt  time.Time
+}
+
+// Global function for checking whether a reply is an error or clarification rather than an answer
+func isTransientReply(text string) bool {
+	return isPackMessage(text, failureReplyKeys...)
+}
+
+// Global function for posting an error or clarification reply that deletes itself after the grace period
+func postTransientText(channelID string, text string) {
+	respChannel, respTimestamp, err := postMessage(channelID, messageText(text))
+	if err == nil && isTransientReply(text) {
+		expireMessage(respChannel, respTimestamp)
+	}
+}
+
+// Global function for scheduling a reply's deletion, persisting it when the delay is long enough to span a restart
+func expireMessage(channel string, timestamp string) {
+	if config.ErrorMessageTTL <= 0 || channel == "" || timestamp == "" {
+		return
+	}
+	if config.ErrorMessageTTL <= persistedExpiryThreshold || store == nil {
+		time.AfterFunc(config.ErrorMessageTTL, func() { deleteUntouchedMessage(channel, timestamp) })
+		return
+	}
+
+	pending := expiringMessage{Channel: channel, Timestamp: timestamp, DeleteAt: time.Now().Add(config.ErrorMessageTTL)}
+	if err := store.put(expiringMessagesBucket, channel+":"+timestamp, pending); err != nil {
+		log.Printf("EXPIRY ERROR: Unable to persist deletion of %s in %s.\nError Details: %v", timestamp, channel, err)
+	}
+}
+
+// Global function for deleting one of our replies unless someone reacted to it or replied in its thread (failures are silent)
+func deleteUntouchedMessage(channel string, timestamp string) {
+	history, err := slackAPI().GetConversationHistory(&slack.GetConversationHistoryParameters{ChannelID: channel, Latest: timestamp, Inclusive: true, Limit: 1})
+	if err != nil || len(history.Messages) == 0 || history.Messages[0].Timestamp != timestamp {
+		// Already gone, or out of reach; either way there's nothing left to tidy
+		return
+	}
+	if message := history.Messages[0]; len(message.Reactions) > 0 || message.ReplyCount > 0 {
+		metrics.inc("wolfy_error_message_expiry_total", "result", "kept")
+		return
+	}
+	if _, _, err := slackAPI().DeleteMessage(channel, timestamp); err == nil {
+		metrics.inc("wolfy_error_message_expiry_total", "result", "deleted")
+	}
+}
+
+// Global function for starting the background loop deleting persisted replies as they come due, including any from before a restart
+func startMessageExpirySweeper() {
+	if config.ErrorMessageTTL <= persistedExpiryThreshold {
+		return
+	}
+	go func() {
+		for range time.Tick(expirySweepInterval) {
+			now := time.Now()
+			for _, key := range store.keys(expiringMessagesBucket) {
+				var pending expiringMessage
+				if store.get(expiringMessagesBucket, key, &pending) && pending.DeleteAt.After(now) {
+					continue
+				}
+				store.delete(expiringMessagesBucket, key)
+				if pending.Timestamp != "" {
+					go deleteUntouchedMessage(pending.Channel, pending.Timestamp)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,78 @@
+//////////////////////////////////////////////////
+// Answer Explanation Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for explaining how the asker's last message was classified and answered
+import (
+	"context" // Permits routing table lookups
+	"fmt"     // Permits string formatting of the explanation
+	"regexp"  // Permits matching of "why" follow-ups
+	"strings" // Permits joining of explanation lines
+	"time"    // Permits rounding of cached answer ages
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global pattern recognizing "wolfy why" style follow-ups
+var whyPattern = regexp.MustCompile(`(?i)^\s*(?:wolfy\s+)?why(?:\s+(?:that(?:\s+answer)?|did\s+you\s+say\s+that))?\s*[?!.]*\s*$`)
+
+// Registering "why" among the built-in capabilities
+func init() {
+	registerCapability("why", "Say \"wolfy why\" after an answer to see how I understood your last message here.", nil)
+}
+
+// Global function for describing how an interaction was classified and where its answer came from
+func explainInteraction(last interaction) string {
+	lines := []string{
+		"*Here's how I handled your last message here:*",
+		fmt.Sprintf("• I read it as: \"%s\"", isolateDirection(last.Text)),
+	}
+
+	_, routed := routeEntity(context.Background(), last.EntityKey)
+	switch {
+	case last.EntityKey == "":
+		lines = append(lines, fmt.Sprintf("• Intent: none - nothing Wit.ai found cleared the %.2f confidence threshold", config.ConfidenceThreshold))
+	case last.Outcome == "unhandled":
+		lines = append(lines, fmt.Sprintf("• Intent: `%s` with %.2f confidence, but I don't know how to answer that kind of question yet", last.EntityKey, last.Confidence))
+	case routed && last.Confidence > 0:
+		lines = append(lines, fmt.Sprintf("• Intent: `%s` with %.2f confidence (it needed more than %.2f)", last.EntityKey, last.Confidence, config.ConfidenceThreshold))
+	case routed:
+		lines = append(lines, fmt.Sprintf("• Intent: `%s`, picked without Wit.ai (it was busy, so I asked Wolfram|Alpha directly)", last.EntityKey))
+	default:
+		lines = append(lines, fmt.Sprintf("• Answered by my built-in `%s` skill, without Wit.ai", last.EntityKey))
+	}
+
+	if last.Query != "" {
+		lines = append(lines, fmt.Sprintf("• Query sent to Wolfram|Alpha: \"%s\"", isolateDirection(last.Query)))
+		if last.Cached && !last.AnsweredAt.IsZero() {
+			lines = append(lines, fmt.Sprintf("• Answer source: my answer cache (a Wolfram|Alpha answer fetched %s before you asked)", last.At.Sub(last.AnsweredAt).Round(time.Second)))
+		} else if last.Cached {
+			lines = append(lines, "• Answer source: my answer cache (an earlier Wolfram|Alpha answer)")
+		} else {
+			lines = append(lines, "• Answer source: Wolfram|Alpha, fetched live")
+		}
+	}
+	lines = append(lines, fmt.Sprintf("• Outcome: %s", last.Outcome))
+	return strings.Join(lines, "\n")
+}
+
+// Global function for answering "wolfy why" from the asker's own session in this channel, reporting whether the message was consumed
+func handleWhy(event *slack.MessageEvent) bool {
+	if !whyPattern.MatchString(event.Msg.Text) {
+		return false
+	}
+	metrics.inc("wolfy_intents_total", "intent", "why")
+
+	// Only ever the asker's own interaction, so nobody can see how someone else's question was handled
+	last, ok := lastInteractionIn(event.User, event.Channel)
+	if !ok {
+		postPrivately(event, "I don't have a recent answer of yours here to explain. Ask me something first! :-)")
+		return true
+	}
+	postPrivately(event, explainInteraction(last))
+	return true
+}
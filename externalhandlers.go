@@ -0,0 +1,253 @@
+//////////////////////////////////////////////////
+// External Handlers Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for handlers run as external commands, declared in a file rather than compiled into the bot
+import (
+	"bytes"         // Permits passing of the request on stdin
+	"context"       // Permits the per-invocation deadline
+	"encoding/json" // Permits decoding of the handlers file and each invocation's request and response
+	"fmt"           // Permits formatting of handler failures
+	"io/ioutil"     // Permits reading of the handlers file
+	"log"           // Permits console logging
+	"os"            // Permits passing of allowed environment variables
+	"os/exec"       // Permits running of handler commands
+	"regexp"        // Permits handler patterns and refusal of the bot's own environment
+	"strings"       // Permits trimming of handler output
+	"time"          // Permits handler timeouts
+
+	wit "github.com/christianrondeau/go-wit" // External Wit.ai API
+	slack "github.com/nlopes/slack"          // External Slack API
+)
+
+// Global constant holding the most a handler may write to stdout (its response, past which it fails) or stderr (logged)
+const externalHandlerOutputLimit = 64 * 1024
+
+// Global bucket upper bounds (in seconds) of the external handler latency histogram
+var externalHandlerLatencyBounds = []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 30}
+
+// Global environment variables every handler gets, on top of those its declaration allows
+var externalHandlerBaseEnv = []string{"PATH", "HOME", "LANG", "TZ", "TMPDIR"}
+
+// Global pattern recognizing the bot's own settings and credentials, which no handler may be passed
+var botEnvPattern = regexp.MustCompile(`^(?:SLACK|WIT|WOLFRAM|WOLFY)_`)
+
+// Global struct holding one handler declaration from the handlers file
+type externalHandlerSpec struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Command     []string `json:"command"`
+	Timeout     string   `json:"timeout"`
+	Patterns    []string `json:"patterns"`
+	Env         []string `json:"env"`
+}
+
+// Global struct holding what a handler is sent on stdin: the message as classified, and who asked where
+type externalHandlerRequest struct {
+	Handler    string  `json:"handler"`
+	Text       string  `json:"text"`
+	Value      string  `json:"value"`
+	Confidence float64 `json:"confidence"`
+	User       string  `json:"user"`
+	Channel    string  `json:"channel"`
+	ThreadTS   string  `json:"thread_ts,omitempty"`
+}
+
+// Global struct holding what a handler writes to stdout: its reply, supporting detail, and an optional image
+type externalHandlerResponse struct {
+	Text    string `json:"text"`
+	Details []struct {
+		Title string `json:"title"`
+		Value string `json:"value"`
+	} `json:"details"`
+	Image string `json:"image"`
+}
+
+// Global struct pairing a handler with a pattern that dispatches to it without asking Wit.ai
+type externalHandlerPattern struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// Global patterns of the loaded external handlers, tried in file order
+var externalHandlerPatterns []externalHandlerPattern
+
+// Global struct holding output capped at a byte limit, noting whether anything was cut off
+type cappedBuffer struct {
+	bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+// Method for keeping writes up to the limit and discarding (but acknowledging) the rest, so a chatty handler can't
+// exhaust memory or block on a full pipe
+func (buffer *cappedBuffer) Write(data []byte) (int, error) {
+	if room := buffer.limit - buffer.Len(); len(data) > room {
+		buffer.truncated = true
+		if room > 0 {
+			buffer.Buffer.Write(data[:room])
+		}
+		return len(data), nil
+	}
+	return buffer.Buffer.Write(data)
+}
+
+// Global function for loading the external handlers file and registering its handlers, skipping (and logging) any
+// declaration that is unusable. Runs before the routing table is built, so routing file entries may name them.
+func loadExternalHandlers() {
+	if config.ExternalHandlersFile == "" {
+		return
+	}
+	data, err := ioutil.ReadFile(config.ExternalHandlersFile)
+	if err != nil {
+		log.Printf("CONFIG ERROR: Unable to read external handlers file %s.\nError Details: %v", config.ExternalHandlersFile, err)
+		return
+	}
+	var source struct {
+		Handlers []externalHandlerSpec `json:"handlers"`
+	}
+	if err := json.Unmarshal(data, &source); err != nil {
+		log.Printf("CONFIG ERROR: Unable to parse external handlers file %s.\nError Details: %v", config.ExternalHandlersFile, err)
+		return
+	}
+	for _, spec := range source.Handlers {
+		handler, patterns, err := externalHandlerFrom(spec)
+		if err != nil {
+			log.Printf("CONFIG ERROR: Skipping external handler %q.\nError Details: %v", spec.Name, err)
+			continue
+		}
+		registerEntityHandler(handler)
+		externalHandlerPatterns = append(externalHandlerPatterns, patterns...)
+		log.Printf("HANDLERS: Registered external handler %s (%s).", spec.Name, strings.Join(spec.Command, " "))
+	}
+}
+
+// Global function for checking a handler declaration and building its registry entry and patterns
+func externalHandlerFrom(spec externalHandlerSpec) (*entityHandler, []externalHandlerPattern, error) {
+	if spec.Name == "" || len(spec.Command) == 0 {
+		return nil, nil, fmt.Errorf("a name and a command are required")
+	}
+	if _, taken := entityHandlers[spec.Name]; taken {
+		return nil, nil, fmt.Errorf("the name is already taken by a built-in handler")
+	}
+	for _, name := range spec.Env {
+		if botEnvPattern.MatchString(name) {
+			return nil, nil, fmt.Errorf("the bot's own setting %s can't be passed to a handler", name)
+		}
+	}
+	var timeout time.Duration
+	if spec.Timeout != "" {
+		parsed, err := time.ParseDuration(spec.Timeout)
+		if err != nil || parsed <= 0 {
+			return nil, nil, fmt.Errorf("timeout %q is not a positive duration", spec.Timeout)
+		}
+		timeout = parsed
+	}
+	var patterns []externalHandlerPattern
+	for _, source := range spec.Patterns {
+		pattern, err := regexp.Compile(source)
+		if err != nil {
+			return nil, nil, fmt.Errorf("pattern %q: %v", source, err)
+		}
+		patterns = append(patterns, externalHandlerPattern{name: spec.Name, pattern: pattern})
+	}
+
+	description := spec.Description
+	if description == "" {
+		description = "Answer with the external command " + spec.Command[0] + "."
+	}
+	return &entityHandler{
+		name:        spec.Name,
+		description: description,
+		timeout:     timeout,
+		local:       len(patterns) > 0,
+		handle: func(ctx context.Context, event *slack.MessageEvent, entity wit.MessageEntity) (handlerResponse, error) {
+			return runExternalHandler(ctx, spec, event, entity)
+		},
+	}, patterns, nil
+}
+
+// Global function for finding the external handler whose pattern matches a message, if any
+func matchExternalHandler(text string) (string, bool) {
+	for _, candidate := range externalHandlerPatterns {
+		if candidate.pattern.MatchString(text) {
+			return candidate.name, true
+		}
+	}
+	return "", false
+}
+
+// Global function for building a handler's environment: the base variables and its allowed ones, nothing else
+func externalHandlerEnv(spec externalHandlerSpec) []string {
+	var env []string
+	for _, name := range append(append([]string(nil), externalHandlerBaseEnv...), spec.Env...) {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	return env
+}
+
+// Global function for running a handler's command once for a message: the request on stdin, the response on stdout,
+// within the handler's deadline and the output limit. Failures of any kind are internal errors, apart from running out
+// of time, which is a timeout like any other handler's.
+func runExternalHandler(ctx context.Context, spec externalHandlerSpec, event *slack.MessageEvent, entity wit.MessageEntity) (handlerResponse, error) {
+	value, _ := entity.Value.(string)
+	request, err := json.Marshal(externalHandlerRequest{
+		Handler:    spec.Name,
+		Text:       event.Msg.Text,
+		Value:      value,
+		Confidence: entity.Confidence,
+		User:       event.User,
+		Channel:    event.Channel,
+		ThreadTS:   event.ThreadTimestamp,
+	})
+	if err != nil {
+		return handlerResponse{}, wrapFailure(errInternal, err)
+	}
+
+	stdout := &cappedBuffer{limit: externalHandlerOutputLimit}
+	stderr := &cappedBuffer{limit: externalHandlerOutputLimit}
+	command := exec.CommandContext(ctx, spec.Command[0], spec.Command[1:]...)
+	command.Env = externalHandlerEnv(spec)
+	command.Stdin = bytes.NewReader(request)
+	command.Stdout, command.Stderr = stdout, stderr
+	// Not waiting on output pipes held open by anything the command left running once it has been killed
+	command.WaitDelay = time.Second
+
+	started := time.Now()
+	err = command.Run()
+	metrics.observe(time.Since(started).Seconds(), externalHandlerLatencyBounds, "wolfy_external_handler_seconds", "handler", spec.Name)
+	if stderr.Len() > 0 {
+		log.Printf("HANDLERS: External handler %s wrote to stderr: %s", spec.Name, truncateGraphemes(strings.TrimSpace(stderr.String()), 500))
+	}
+	if ctx.Err() != nil {
+		metrics.inc("wolfy_external_handler_runs_total", "handler", spec.Name, "result", "timeout")
+		return handlerResponse{}, ctx.Err()
+	}
+	if err != nil {
+		metrics.inc("wolfy_external_handler_runs_total", "handler", spec.Name, "result", "failed")
+		return handlerResponse{}, wrapFailure(errInternal, fmt.Errorf("external handler %s failed: %v", spec.Name, err))
+	}
+	if stdout.truncated {
+		metrics.inc("wolfy_external_handler_runs_total", "handler", spec.Name, "result", "oversized")
+		return handlerResponse{}, wrapFailure(errInternal, fmt.Errorf("external handler %s wrote over %d bytes", spec.Name, externalHandlerOutputLimit))
+	}
+
+	var response externalHandlerResponse
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil || strings.TrimSpace(response.Text) == "" {
+		metrics.inc("wolfy_external_handler_runs_total", "handler", spec.Name, "result", "invalid")
+		return handlerResponse{}, wrapFailure(errInternal, fmt.Errorf("external handler %s gave no usable response (%v)", spec.Name, err))
+	}
+	metrics.inc("wolfy_external_handler_runs_total", "handler", spec.Name, "result", "answered")
+
+	answer := handlerResponse{Text: response.Text, Query: value, Image: response.Image}
+	for _, detail := range response.Details {
+		answer.Details = append(answer.Details, slack.AttachmentField{Title: detail.Title, Value: detail.Value, Short: true})
+	}
+	return answer, nil
+}
@@ -0,0 +1,137 @@
+//////////////////////////////////////////////////
+// External Handlers Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing handlers run as external commands
+import (
+	"context"       // Permits handler deadlines
+	"io/ioutil"     // Permits writing of the handlers file
+	"os/exec"       // Permits building of the example handler
+	"path/filepath" // Permits temporary file paths
+	"strings"       // Permits inspection of replies
+	"testing"       // Permits Go testing
+	"time"          // Permits handler timeouts
+
+	wit "github.com/christianrondeau/go-wit" // External Wit.ai API
+)
+
+// Global function for loading an external handlers file for one test, unregistering its handlers afterwards
+func withExternalHandlersFile(t *testing.T, contents string) {
+	path := filepath.Join(t.TempDir(), "handlers.json")
+	if err := ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+	withConfig(t, func(c *Config) { c.ExternalHandlersFile = path })
+	before := map[string]bool{}
+	for name := range entityHandlers {
+		before[name] = true
+	}
+	loadExternalHandlers()
+	loadRouting()
+	t.Cleanup(func() {
+		for name := range entityHandlers {
+			if !before[name] {
+				delete(entityHandlers, name)
+			}
+		}
+		externalHandlerPatterns = nil
+		loadRouting()
+	})
+}
+
+// Test for registering the example handler from a handlers file and round-tripping a message through it
+func TestExternalHandlerRoundTrip(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("the go tool is needed to build the example handler")
+	}
+	binary := filepath.Join(t.TempDir(), "kb-lookup")
+	if output, err := exec.Command("go", "build", "-o", binary, "./examples/external_handler").CombinedOutput(); err != nil {
+		t.Fatalf("building the example handler: %v\n%s", err, output)
+	}
+	withExternalHandlersFile(t, `{"handlers": [{"name": "kb_lookup", "command": ["`+binary+`"], "timeout": "10s", "patterns": ["(?i)^kb\\s+"]}]}`)
+	if _, ok := entityHandlers["kb_lookup"]; !ok {
+		t.Fatal("kb_lookup was not registered")
+	}
+	if name, ok := matchExternalHandler("kb how do I get on the vpn"); !ok || name != "kb_lookup" {
+		t.Fatalf("matchExternalHandler = %q, %v; want kb_lookup", name, ok)
+	}
+
+	fakeSlack.reset()
+	answered := metricValue("wolfy_external_handler_runs_total", "handler", "kb_lookup", "result", "answered")
+	handleMSGEvent(testMessage("DKB", "UKB", "5000.000001", "kb how do I get on the vpn"))
+	waitFor(t, "the handler's answer", func() bool {
+		for _, call := range fakeSlack.callsTo("chat.postMessage") {
+			if strings.Contains(call.values.Get("text"), "Connect to the VPN") {
+				return true
+			}
+		}
+		return false
+	})
+	if got := metricValue("wolfy_external_handler_runs_total", "handler", "kb_lookup", "result", "answered") - answered; got != 1 {
+		t.Errorf("kb_lookup runs answered = %d; want exactly 1", got)
+	}
+}
+
+// Test for mapping a handler's failures onto internal errors and timeouts, and for scrubbing the bot's credentials
+// from its environment
+func TestExternalHandlerFailures(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("a POSIX shell is needed for the scripted handlers")
+	}
+	t.Setenv("SLACK_ACCESS_TOKEN", "xoxb-secret")
+	event := testMessage("DEXT", "UEXT", "5100.000001", "anything")
+	cases := []struct {
+		name   string
+		script string
+		answer string
+		want   *failureClass
+	}{
+		{"answers", `cat >/dev/null; echo '{"text": "hello"}'`, "hello", nil},
+		{"scrubbed environment", `cat >/dev/null; printf '{"text": "%s"}' "${SLACK_ACCESS_TOKEN:-scrubbed}"`, "scrubbed", nil},
+		{"non-zero exit", `exit 3`, "", errInternal},
+		{"unusable JSON", `echo 'not json'`, "", errInternal},
+		{"empty answer", `echo '{"text": ""}'`, "", errInternal},
+		{"oversized output", `head -c 70000 /dev/zero | tr '\0' 'x'`, "", errInternal},
+		{"past its deadline", `sleep 5`, "", errBackendTimeout},
+	}
+	for _, c := range cases {
+		spec := externalHandlerSpec{Name: "test_script", Command: []string{"sh", "-c", c.script}}
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		response, err := runExternalHandler(ctx, spec, event, wit.MessageEntity{Value: "anything", Confidence: 1})
+		cancel()
+		if c.want == nil {
+			if err != nil || response.Text != c.answer {
+				t.Errorf("%s: runExternalHandler = %q, %v; want %q", c.name, response.Text, err, c.answer)
+			}
+			continue
+		}
+		if err == nil || classifyFailure(err) != c.want {
+			t.Errorf("%s: runExternalHandler = %q, %v; want a %s error", c.name, response.Text, err, c.want.code)
+		}
+	}
+}
+
+// Test for refusing declarations that would pass the bot's own settings to a handler or take a built-in handler's name
+func TestExternalHandlerDeclarations(t *testing.T) {
+	cases := []struct {
+		spec externalHandlerSpec
+		ok   bool
+	}{
+		{externalHandlerSpec{Name: "kb", Command: []string{"kb"}, Env: []string{"KB_TOKEN"}, Timeout: "5s", Patterns: []string{"^kb "}}, true},
+		{externalHandlerSpec{Name: "kb", Command: []string{"kb"}, Env: []string{"SLACK_ACCESS_TOKEN"}}, false},
+		{externalHandlerSpec{Name: "kb", Command: []string{"kb"}, Env: []string{"WOLFY_ADMIN_USERS"}}, false},
+		{externalHandlerSpec{Name: "greetings", Command: []string{"kb"}}, false},
+		{externalHandlerSpec{Name: "kb"}, false},
+		{externalHandlerSpec{Name: "kb", Command: []string{"kb"}, Timeout: "-1s"}, false},
+		{externalHandlerSpec{Name: "kb", Command: []string{"kb"}, Patterns: []string{"("}}, false},
+	}
+	for _, c := range cases {
+		if _, _, err := externalHandlerFrom(c.spec); (err == nil) != c.ok {
+			t.Errorf("externalHandlerFrom(%+v) error = %v; want ok %v", c.spec, err, c.ok)
+		}
+	}
+}
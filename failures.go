@@ -0,0 +1,116 @@
+//////////////////////////////////////////////////
+// Failure Taxonomy Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for classifying failures, wording them for users, and tagging them with short codes
+import (
+	"context" // Permits recognizing deadlines and finding the in-flight request
+	"errors"  // Permits unwrapping of classified errors
+	"fmt"     // Permits formatting of failure log lines
+	"log"     // Permits console logging
+	"net"     // Permits recognizing network timeouts
+	"regexp"  // Permits recognizing a failure code appended to a reply
+	"strings" // Permits recognizing apologetic replies
+)
+
+// Global struct describing one class of failure: the short code shown to users and logged, the catalog message
+// explaining it, the log category, and whether it is the question's fault rather than ours
+type failureClass struct {
+	name     string
+	code     string
+	key      string
+	category string
+	expected bool
+}
+
+// Method for describing a failure class as an error
+func (class *failureClass) Error() string {
+	return class.name
+}
+
+// Global failure classes every classifier, provider, and handler error maps onto
+var (
+	errNLPUnavailable      = &failureClass{name: "wit.ai is unavailable", code: "N-DOWN", key: "nlp_unavailable", category: "WIT.AI"}
+	errNLPLowConfidence    = &failureClass{name: "no intent above the confidence threshold", code: "N-UNCLEAR", key: "unclear_input", category: "WIT.AI", expected: true}
+	errRateLimited         = &failureClass{name: "wit.ai rate limit exceeded", code: "N-BUSY", key: "wit_busy", category: "WIT.AI"}
+	errAnswerNotUnderstood = &failureClass{name: "wolfram|alpha did not understand the input", code: "W-UNCLEAR", key: "wolfram_unclear", category: "WOLFRAM", expected: true}
+	errAnswerTooLong       = &failureClass{name: "wolfram|alpha has no short answer", code: "W-TOOLONG", key: "wolfram_too_long", category: "WOLFRAM", expected: true}
+	errQuotaExceeded       = &failureClass{name: "wolfram|alpha refused the app id", code: "W-QUOTA", key: "quota_exceeded", category: "WOLFRAM"}
+	errBackendTimeout      = &failureClass{name: "backend timed out", code: "W-TIMEOUT", key: "timeout", category: "MESSAGE HANDLING"}
+	errInternal            = &failureClass{name: "internal error", code: "X-INTERNAL", key: "internal_error", category: "MESSAGE HANDLING"}
+)
+
+// Global pattern recognizing the failure code appended to a reply (" `[W-TIMEOUT]`")
+var failureCodePattern = regexp.MustCompile(" `\\[[A-Z]-[A-Z]+\\]`$")
+
+// Global list of the catalog keys whose messages stand in for an answer that couldn't be given
+var failureReplyKeys = []string{"unclear_input", "timeout", "wit_busy", "apis_disabled", "wolfram_unclear", "wolfram_too_long", "nlp_unavailable", "quota_exceeded", "internal_error"}
+
+// Global struct wrapping an underlying error with the class it belongs to
+type classifiedError struct {
+	class *failureClass
+	cause error
+}
+
+// Method for describing a classified error with its cause
+func (err *classifiedError) Error() string {
+	return err.class.name + ": " + err.cause.Error()
+}
+
+// Method for unwrapping a classified error to its class, so errors.Is matches the class
+func (err *classifiedError) Unwrap() error {
+	return err.class
+}
+
+// Global function for tagging an underlying error with its failure class
+func wrapFailure(class *failureClass, cause error) error {
+	return &classifiedError{class: class, cause: cause}
+}
+
+// Global function for mapping any error onto its failure class, treating deadlines as timeouts and anything unknown as internal
+func classifyFailure(err error) *failureClass {
+	var class *failureClass
+	if errors.As(err, &class) {
+		return class
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return errBackendTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return errBackendTimeout
+	}
+	return errInternal
+}
+
+// Global function for wording a failure for the user: its catalog message followed by its short code
+func failureReply(ctx context.Context, err error) string {
+	class := classifyFailure(err)
+	return scopedMessage(ctx, class.key) + " `[" + class.code + "]`"
+}
+
+// Global function for logging a failure with its code and the request it belongs to, returning its class; failures that are
+// the question's fault are logged without the ERROR marker so they stay out of "!errors"
+func logFailure(ctx context.Context, err error, during string) *failureClass {
+	class := classifyFailure(err)
+	request := "-"
+	if inFlight := inFlightFrom(ctx); inFlight != nil {
+		request = fmt.Sprintf("#%d", inFlight.id)
+	}
+	metrics.inc("wolfy_failures_total", "code", class.code)
+	if class.expected {
+		log.Printf("%s: [%s] Request %s got no answer while %s: %v.", class.category, class.code, request, during, err)
+	} else {
+		log.Printf("%s ERROR: [%s] Request %s failed while %s.\nError Details: %v", class.category, class.code, request, during, err)
+	}
+	return class
+}
+
+// Global function for checking whether a sub-question's reply is a failure rather than an answer
+func isFailureReply(reply string) bool {
+	return isPackMessage(reply, failureReplyKeys...) || strings.HasPrefix(reply, "Sorry,")
+}
@@ -0,0 +1,133 @@
+//////////////////////////////////////////////////
+// Failure Classes Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing the typed error taxonomy
+import (
+	"bytes"     // Permits building of fake Wolfram replies
+	"context"   // Permits deadline errors
+	"errors"    // Permits plain errors
+	"io/ioutil" // Permits fake Wolfram reply bodies
+	"net"       // Permits network timeout errors
+	"net/http"  // Permits fake Wolfram replies
+	"strings"   // Permits inspection of replies
+	"testing"   // Permits Go testing
+
+	wolfram "github.com/Krognol/go-wolfram" // External Wolfram API
+)
+
+// Global function type adapting a plain function into an HTTP transport
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+// Method for answering a request with the adapted function
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Global function for letting one test reach external APIs, all answered by a fake transport that, like a real one,
+// fails requests whose context is already done
+func withExternalAPIs(t *testing.T, reply func(*http.Request) (int, string)) {
+	previous := httpClient
+	httpClient = &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if err := req.Context().Err(); err != nil {
+			return nil, err
+		}
+		status, body := reply(req)
+		return &http.Response{StatusCode: status, Status: http.StatusText(status), Body: ioutil.NopCloser(bytes.NewBufferString(body)), Request: req}, nil
+	})}
+	setExternalAPIsDisabled(false)
+	t.Cleanup(func() {
+		httpClient = previous
+		setExternalAPIsDisabled(true)
+	})
+}
+
+// Global struct holding a timeout as a net.Error reports it
+type netTimeoutError struct{}
+
+// Method for describing the timeout
+func (netTimeoutError) Error() string { return "i/o timeout" }
+
+// Method for reporting that the error is a timeout
+func (netTimeoutError) Timeout() bool { return true }
+
+// Method for reporting that the error is temporary
+func (netTimeoutError) Temporary() bool { return true }
+
+// Test for mapping errors onto their failure classes
+func TestClassifyFailure(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want *failureClass
+	}{
+		{"bare class", errAnswerNotUnderstood, errAnswerNotUnderstood},
+		{"wrapped class", wrapFailure(errQuotaExceeded, errors.New("status 403")), errQuotaExceeded},
+		{"kill-switch", errExternalAPIsDisabled, errExternalAPIsDisabled},
+		{"deadline", context.DeadlineExceeded, errBackendTimeout},
+		{"network timeout", &net.OpError{Op: "read", Err: netTimeoutError{}}, errBackendTimeout},
+		{"anything else", errors.New("boom"), errInternal},
+	}
+	for _, c := range cases {
+		if got := classifyFailure(c.err); got != c.want {
+			t.Errorf("%s: classifyFailure(%v) = %s; want %s", c.name, c.err, got.code, c.want.code)
+		}
+	}
+}
+
+// Test for giving every class its own catalog message followed by its code, and counting it under that code when logged
+func TestFailureReplies(t *testing.T) {
+	ctx := context.Background()
+	seen := map[string]*failureClass{}
+	for _, class := range []*failureClass{errNLPUnavailable, errNLPLowConfidence, errRateLimited, errAnswerNotUnderstood, errAnswerTooLong, errQuotaExceeded, errBackendTimeout, errInternal, errExternalAPIsDisabled} {
+		reply := failureReply(ctx, wrapFailure(class, errors.New("cause")))
+		message := scopedMessage(ctx, class.key)
+		if reply != message+" `["+class.code+"]`" {
+			t.Errorf("failureReply for %s = %q; want its %s message and code", class.code, reply, class.key)
+		}
+		if other, ok := seen[message]; ok {
+			t.Errorf("%s and %s share the message %q", class.code, other.code, message)
+		}
+		seen[message] = class
+		if !failureCodePattern.MatchString(reply) || !isFailureReply(strings.TrimSuffix(reply, " `["+class.code+"]`")) {
+			t.Errorf("failureReply for %s = %q isn't recognized as a failure", class.code, reply)
+		}
+
+		before := metricValue("wolfy_failures_total", "code", class.code)
+		if logged := logFailure(ctx, class, "testing"); logged != class || metricValue("wolfy_failures_total", "code", class.code) != before+1 {
+			t.Errorf("logFailure for %s didn't count it under its code", class.code)
+		}
+	}
+}
+
+// Test for turning Wolfram's "no answer" replies and refused App IDs into typed errors rather than answers
+func TestShortAnswerFailures(t *testing.T) {
+	cases := []struct {
+		status int
+		body   string
+		answer string
+		want   *failureClass
+	}{
+		{http.StatusOK, "42", "42", nil},
+		{http.StatusNotImplemented, wolframNotUnderstoodReply, "", errAnswerNotUnderstood},
+		{http.StatusNotImplemented, wolframNoShortAnswerReply, "", errAnswerTooLong},
+		{http.StatusForbidden, "Error 1: Invalid appid", "", errQuotaExceeded},
+	}
+	for _, c := range cases {
+		withExternalAPIs(t, func(*http.Request) (int, string) { return c.status, c.body })
+		answer, err := fetchShortAnswer(context.Background(), &wolfram.Client{AppID: "test"}, "meaning of life", wolfram.Metric)
+		if c.want == nil {
+			if err != nil || answer != c.answer {
+				t.Errorf("fetchShortAnswer on %d %q = %q, %v; want %q", c.status, c.body, answer, err, c.answer)
+			}
+			continue
+		}
+		if got := classifyFailure(err); err == nil || got != c.want {
+			t.Errorf("fetchShortAnswer on %d %q = %q, %v; want a %s error", c.status, c.body, answer, err, c.want.code)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+//////////////////////////////////////////////////
+// Answer Feedback Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for remembering :+1: and :-1: reactions on answers, and answering poorly rated questions afresh
+import (
+	"fmt"     // Permits formatting of the feedback report
+	"log"     // Permits console logging
+	"math"    // Permits exponential decay of scores
+	"sort"    // Permits ranking of the worst-rated questions
+	"strings" // Permits joining of the feedback report
+	"sync"    // Permits serialized score updates
+	"time"    // Permits decay of scores
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constants naming the store bucket for feedback scores and how many questions the report lists
+const (
+	feedbackBucket      = "answer_feedback"
+	feedbackReportLimit = 10
+)
+
+// Global constant holding the note on an answer re-queried because an earlier one was poorly rated
+const poorlyRatedNote = "_(Earlier answers to this got thumbs-down, so I looked it up fresh. React :+1: or :-1: to tell me how this one did.)_"
+
+// Global reactions counted as feedback, and which way
+var feedbackReactions = map[string]float64{"+1": 1, "thumbsup": 1, "-1": -1, "thumbsdown": -1}
+
+// Global struct holding a question's feedback tallies, as of when they were last decayed
+type feedbackScore struct {
+	Question  string
+	Positive  float64
+	Negative  float64
+	UpdatedAt time.Time
+}
+
+// Global mutex serializing read-modify-write of feedback scores
+var feedbackMu sync.Mutex
+
+// Global function for keying feedback by a question's loosened normalized form, so rephrasings share a score
+func feedbackKey(question string) string {
+	return fuzzyCacheQuery(normalizeCacheQuery(question))
+}
+
+// Method for decaying the tallies to a moment, halving them every configured half-life
+func (score feedbackScore) decayedTo(now time.Time) feedbackScore {
+	if config.FeedbackHalfLife > 0 && now.After(score.UpdatedAt) {
+		factor := math.Pow(0.5, float64(now.Sub(score.UpdatedAt))/float64(config.FeedbackHalfLife))
+		score.Positive, score.Negative = score.Positive*factor, score.Negative*factor
+	}
+	score.UpdatedAt = now
+	return score
+}
+
+// Method for returning the net score: positive minus negative feedback
+func (score feedbackScore) net() float64 {
+	return score.Positive - score.Negative
+}
+
+// Global function for recording one reaction on an answer to a question
+func recordFeedback(question string, direction float64, now time.Time) {
+	key := feedbackKey(question)
+	if key == "" {
+		return
+	}
+	feedbackMu.Lock()
+	defer feedbackMu.Unlock()
+
+	var score feedbackScore
+	store.get(feedbackBucket, key, &score)
+	score = score.decayedTo(now)
+	score.Question = question
+	if direction > 0 {
+		score.Positive += direction
+	} else {
+		score.Negative -= direction
+	}
+	if err := store.put(feedbackBucket, key, score); err != nil {
+		log.Printf("FEEDBACK ERROR: Unable to record feedback on %q.\nError Details: %v", question, err)
+	}
+}
+
+// Global function for checking whether a question's answers have been rated poorly enough (net score at or below minus
+// the bypass threshold, after decay) that it should skip the cache and be answered fresh
+func poorlyRated(question string, now time.Time) bool {
+	if config.FeedbackBypassScore <= 0 {
+		return false
+	}
+	var score feedbackScore
+	if !store.get(feedbackBucket, feedbackKey(question), &score) {
+		return false
+	}
+	return score.decayedTo(now).net() <= -config.FeedbackBypassScore
+}
+
+// Handler for :+1: and :-1: reactions on the bot's own recent answers, recording them against the question asked
+func handleFeedbackReaction(event *slack.ReactionAddedEvent) {
+	direction, ok := feedbackReactions[event.Reaction]
+	if !ok || event.Item.Type != "message" || event.User == currentBotUserID() || (currentBotUserID() != "" && event.ItemUser != currentBotUserID()) {
+		return
+	}
+	message, ok := lookupAnswerMessage(event.Item.Channel, event.Item.Timestamp)
+	if !ok {
+		return
+	}
+	metrics.inc("wolfy_answer_feedback_total", "direction", map[bool]string{true: "positive", false: "negative"}[direction > 0])
+	recordFeedback(message.question, direction, time.Now())
+}
+
+// Admin command listing the worst-rated questions by net feedback score after decay
+func runAdminFeedback(event *slack.MessageEvent, args []string) string {
+	now := time.Now()
+	var scores []feedbackScore
+	for _, key := range store.keys(feedbackBucket) {
+		var score feedbackScore
+		if store.get(feedbackBucket, key, &score) {
+			if score = score.decayedTo(now); score.Negative >= 0.5 {
+				scores = append(scores, score)
+			}
+		}
+	}
+	if len(scores) == 0 {
+		return "No answers have been rated down recently."
+	}
+	sort.Slice(scores, func(i, j int) bool { return scores[i].net() < scores[j].net() })
+	if len(scores) > feedbackReportLimit {
+		scores = scores[:feedbackReportLimit]
+	}
+
+	lines := []string{fmt.Sprintf("*Worst-rated questions* (net score after a %s half-life; at or below -%g skips the cache):", config.FeedbackHalfLife, config.FeedbackBypassScore)}
+	for _, score := range scores {
+		lines = append(lines, fmt.Sprintf("• %+.1f (%.1f :+1: / %.1f :-1:) \"%s\"", score.net(), score.Positive, score.Negative, truncateGraphemes(score.Question, 80)))
+	}
+	return strings.Join(lines, "\n")
+}
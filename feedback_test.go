@@ -0,0 +1,147 @@
+//////////////////////////////////////////////////
+// Answer Feedback Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing when thumbs-down feedback sends a question past the answer cache
+import (
+	"context"  // Permits the handler's request context
+	"net/http" // Permits the fake Wolfram APIs
+	"strings"  // Permits routing of fake requests
+	"testing"  // Permits Go testing
+	"time"     // Permits the fake clock
+
+	wit "github.com/christianrondeau/go-wit" // External Wit.ai API
+)
+
+// Global function for clearing a question's feedback score before and after a test
+func withoutFeedback(t *testing.T, question string) {
+	store.delete(feedbackBucket, feedbackKey(question))
+	t.Cleanup(func() { store.delete(feedbackBucket, feedbackKey(question)) })
+}
+
+// Test for tallies halving every half-life, and staying put without a half-life or when the clock runs backwards
+func TestFeedbackDecay(t *testing.T) {
+	updated := time.Date(2026, 6, 10, 9, 0, 0, 0, time.UTC)
+	cases := []struct {
+		halfLife time.Duration
+		elapsed  time.Duration
+		positive float64
+		negative float64
+	}{
+		{7 * 24 * time.Hour, 0, 4, 8},
+		{7 * 24 * time.Hour, 7 * 24 * time.Hour, 2, 4},
+		{7 * 24 * time.Hour, 14 * 24 * time.Hour, 1, 2},
+		{7 * 24 * time.Hour, -time.Hour, 4, 8},
+		{0, 14 * 24 * time.Hour, 4, 8},
+	}
+	for _, c := range cases {
+		withConfig(t, func(config *Config) { config.FeedbackHalfLife = c.halfLife })
+		now := updated.Add(c.elapsed)
+		got := feedbackScore{Positive: 4, Negative: 8, UpdatedAt: updated}.decayedTo(now)
+		if got.Positive != c.positive || got.Negative != c.negative || !got.UpdatedAt.Equal(now) {
+			t.Errorf("decayedTo after %s with a %s half-life = %+v; want %g up, %g down as of %s", c.elapsed, c.halfLife, got, c.positive, c.negative, now)
+		}
+	}
+}
+
+// Test for a question being answered fresh once its net score reaches minus the threshold, upvotes offsetting
+// downvotes, old downvotes decaying back under it, rephrasings sharing a score, and a threshold of 0 turning it off
+func TestPoorlyRatedThreshold(t *testing.T) {
+	base := time.Date(2026, 6, 10, 9, 0, 0, 0, time.UTC)
+	week := 7 * 24 * time.Hour
+	cases := []struct {
+		name      string
+		threshold float64
+		votes     []float64
+		asked     string
+		elapsed   time.Duration
+		want      bool
+	}{
+		{"at the threshold", 2, []float64{-1, -1}, "", 0, true},
+		{"under the threshold", 2, []float64{-1}, "", 0, false},
+		{"past the threshold", 2, []float64{-1, -1, -1}, "", 0, true},
+		{"offset by upvotes", 2, []float64{-1, -1, +1}, "", 0, false},
+		{"net of upvotes", 2, []float64{-1, -1, -1, +1}, "", 0, true},
+		{"decayed under the threshold", 2, []float64{-1, -1, -1}, "", week, false},
+		{"decayed to the threshold", 2, []float64{-1, -1, -1, -1}, "", week, true},
+		{"decayed past a half-life", 2, []float64{-1, -1, -1, -1}, "", week + time.Hour, false},
+		{"rephrased", 2, []float64{-1, -1}, "What is the population of France?", 0, true},
+		{"no feedback", 2, nil, "", 0, false},
+		{"turned off", 0, []float64{-1, -1, -1}, "", 0, false},
+	}
+	for _, c := range cases {
+		withConfig(t, func(config *Config) {
+			config.FeedbackBypassScore = c.threshold
+			config.FeedbackHalfLife = week
+		})
+		question := "population of france"
+		withoutFeedback(t, question)
+		for _, vote := range c.votes {
+			recordFeedback(question, vote, base)
+		}
+		asked := question
+		if c.asked != "" {
+			asked = c.asked
+		}
+		if got := poorlyRated(asked, base.Add(c.elapsed)); got != c.want {
+			t.Errorf("%s: poorlyRated(%q) = %v; want %v", c.name, asked, got, c.want)
+		}
+	}
+}
+
+// Test for a poorly rated question skipping its cached answer and being fetched fresh, and being served from the cache
+// again once its feedback is gone
+func TestPoorlyRatedBypassesCache(t *testing.T) {
+	withConfig(t, func(config *Config) {
+		config.FeedbackBypassScore = 2
+		config.AnswerCacheTTL = time.Hour
+	})
+	previous := answers
+	answers = newMemoryAnswerCache()
+	t.Cleanup(func() { answers = previous })
+	question := "boiling point of lead"
+	withoutFeedback(t, question)
+	fetches := 0
+	withExternalAPIs(t, func(req *http.Request) (int, string) {
+		if strings.HasPrefix(req.URL.String(), wolframShortAnswerURL) {
+			fetches++
+			return http.StatusOK, "1749 degrees Celsius"
+		}
+		return http.StatusNotImplemented, ""
+	})
+	ask := func() handlerResponse {
+		event := testMessage("CFEEDBACK", "UFEEDBACK", "8600.000001", question)
+		response, err := handleWolframQuery(context.Background(), event, wit.MessageEntity{Value: question})
+		if err != nil {
+			t.Fatalf("handleWolframQuery: %v", err)
+		}
+		return response
+	}
+
+	if ask(); fetches != 1 {
+		t.Fatalf("fetched %d times for the first answer; want 1", fetches)
+	}
+	if response := ask(); !response.Cached || fetches != 1 {
+		t.Fatalf("second answer cached %v after %d fetches; want a cache hit", response.Cached, fetches)
+	}
+
+	for i := 0; i < 3; i++ {
+		recordFeedback(question, -1, time.Now())
+	}
+	bypassed := metricValue("wolfy_answer_cache_total", "result", "poorly_rated")
+	if response := ask(); response.Cached || fetches != 2 {
+		t.Errorf("poorly rated answer cached %v after %d fetches; want a fresh fetch", response.Cached, fetches)
+	}
+	if got := metricValue("wolfy_answer_cache_total", "result", "poorly_rated") - bypassed; got != 1 {
+		t.Errorf("counted %d cache bypasses; want 1", got)
+	}
+
+	store.delete(feedbackBucket, feedbackKey(question))
+	if response := ask(); !response.Cached || fetches != 2 {
+		t.Errorf("answer with its feedback cleared cached %v after %d fetches; want a cache hit", response.Cached, fetches)
+	}
+}
@@ -0,0 +1,279 @@
+//////////////////////////////////////////////////
+// Feature Flags Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for rolling features out to a subset of users, channels, or workspaces
+import (
+	"context" // Permits carrying the flag scope through the pipeline
+	"fmt"     // Permits formatting of admin output
+	"log"     // Permits console logging
+	"regexp"  // Permits parsing of Slack channel and user references
+	"sort"    // Permits stable ordering of flag listings
+	"strings" // Permits parsing of admin arguments
+	"sync"    // Permits concurrency-safe override updates
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constants naming the store bucket for runtime overrides and the key for all workspaces
+const (
+	featureFlagsBucket = "feature_flags"
+	allWorkspaces      = "*"
+)
+
+// Global struct describing a feature flag defined in code
+type featureFlag struct {
+	name        string
+	description string
+	defaultOn   bool
+}
+
+// Global struct holding a flag's overrides at each level
+type flagOverrides struct {
+	Users      map[string]bool
+	Channels   map[string]bool
+	Workspaces map[string]bool
+}
+
+// Global struct identifying who a flag is being evaluated for
+type flagScope struct {
+	user    string
+	channel string
+	team    string
+}
+
+// Global context key type for the flag scope
+type flagScopeKey struct{}
+
+// Global registry of flags defined in code, and the in-memory overrides evaluated on every message
+var (
+	featureFlags      = map[string]featureFlag{}
+	flagOverrideTable = map[string]*flagOverrides{}
+	flagsMu           sync.RWMutex
+)
+
+// Global patterns recognizing Slack's "<#C123|name>" channel and "<@U123>" user references
+var (
+	channelReferencePattern = regexp.MustCompile(`^<#([A-Z0-9]+)(?:\|[^>]*)?>$`)
+	userReferencePattern    = regexp.MustCompile(`^<@([A-Z0-9]+)(?:\|[^>]*)?>$`)
+)
+
+// Registering the built-in feature flags
+func init() {
+	registerFeatureFlag(featureFlag{name: "compound_questions", description: "Split \"X and Y\" questions into separately answered parts."})
+}
+
+// Global function for defining a feature flag
+func registerFeatureFlag(flag featureFlag) {
+	featureFlags[flag.name] = flag
+}
+
+// Global function for loading workspace-wide overrides from config, then runtime overrides persisted by "!flag"
+func loadFeatureFlags() {
+	flagsMu.Lock()
+	defer flagsMu.Unlock()
+
+	for name, value := range config.FeatureFlags {
+		if _, ok := featureFlags[name]; !ok {
+			log.Printf("CONFIG ERROR: Ignoring unknown feature flag %q.", name)
+			continue
+		}
+		overridesFor(name).Workspaces[allWorkspaces] = value == "on" || value == "true"
+	}
+	if config.SplitCompoundQuestions {
+		// Honoring the older dedicated setting as a workspace-wide override
+		overridesFor("compound_questions").Workspaces[allWorkspaces] = true
+	}
+	for _, name := range store.keys(featureFlagsBucket) {
+		var persisted flagOverrides
+		if _, ok := featureFlags[name]; ok && store.get(featureFlagsBucket, name, &persisted) {
+			overrides := overridesFor(name)
+			for user, value := range persisted.Users {
+				overrides.Users[user] = value
+			}
+			for channel, value := range persisted.Channels {
+				overrides.Channels[channel] = value
+			}
+			for team, value := range persisted.Workspaces {
+				overrides.Workspaces[team] = value
+			}
+		}
+	}
+}
+
+// Global function for returning a flag's overrides, creating them on first use (callers hold flagsMu)
+func overridesFor(name string) *flagOverrides {
+	overrides, ok := flagOverrideTable[name]
+	if !ok {
+		overrides = &flagOverrides{Users: map[string]bool{}, Channels: map[string]bool{}, Workspaces: map[string]bool{}}
+		flagOverrideTable[name] = overrides
+	}
+	return overrides
+}
+
+// Global function for attaching the asker's flag scope to a context
+func withFlagScope(ctx context.Context, event *slack.MessageEvent) context.Context {
+	return context.WithValue(ctx, flagScopeKey{}, flagScope{user: event.User, channel: event.Channel, team: event.Team})
+}
+
+// Global function for evaluating a flag for the scope carried by a context (in memory only, never touching storage)
+func flagEnabled(ctx context.Context, name string) bool {
+	scope, _ := ctx.Value(flagScopeKey{}).(flagScope)
+	return evaluateFlag(name, scope)
+}
+
+// Global function for evaluating a flag with user > channel > workspace > default precedence
+func evaluateFlag(name string, scope flagScope) bool {
+	flagsMu.RLock()
+	defer flagsMu.RUnlock()
+
+	flag, ok := featureFlags[name]
+	if !ok {
+		return false
+	}
+	overrides, ok := flagOverrideTable[name]
+	if !ok {
+		return flag.defaultOn
+	}
+	if value, ok := overrides.Users[scope.user]; ok && scope.user != "" {
+		return value
+	}
+	if value, ok := overrides.Channels[scope.channel]; ok && scope.channel != "" {
+		return value
+	}
+	if value, ok := overrides.Workspaces[scope.team]; ok && scope.team != "" {
+		return value
+	}
+	if value, ok := overrides.Workspaces[allWorkspaces]; ok {
+		return value
+	}
+	return flag.defaultOn
+}
+
+// Global function for describing a flag's default and overrides for "!flags"
+func describeFlag(flag featureFlag) string {
+	parts := []string{fmt.Sprintf("default %s", onOff(flag.defaultOn))}
+	if overrides, ok := flagOverrideTable[flag.name]; ok {
+		for _, level := range []struct {
+			label  string
+			format string
+			values map[string]bool
+		}{
+			{"workspace", "%s", overrides.Workspaces},
+			{"channel", "<#%s>", overrides.Channels},
+			{"user", "<@%s>", overrides.Users},
+		} {
+			keys := make([]string, 0, len(level.values))
+			for key := range level.values {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				parts = append(parts, fmt.Sprintf("%s "+level.format+" %s", level.label, key, onOff(level.values[key])))
+			}
+		}
+	}
+	return fmt.Sprintf("`%s` - %s (%s)", flag.name, flag.description, strings.Join(parts, ", "))
+}
+
+// Global function for rendering a flag value
+func onOff(value bool) string {
+	if value {
+		return "on"
+	}
+	return "off"
+}
+
+// Admin command listing every feature flag with its overrides
+func runAdminFlags(event *slack.MessageEvent, args []string) string {
+	flagsMu.RLock()
+	defer flagsMu.RUnlock()
+
+	names := make([]string, 0, len(featureFlags))
+	for name := range featureFlags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := []string{"*Feature flags* (user > channel > workspace > default):"}
+	for _, name := range names {
+		lines = append(lines, describeFlag(featureFlags[name]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Admin command enabling, disabling, or clearing a flag for a channel, user, or this workspace, persisting the change
+func runAdminFlag(event *slack.MessageEvent, args []string) string {
+	usage := "Usage: `!flag enable|disable|clear <flag> [#channel|@user|workspace|all]`"
+	if len(args) < 2 {
+		return usage
+	}
+	action, name := strings.ToLower(args[0]), args[1]
+	if action != "enable" && action != "disable" && action != "clear" {
+		return usage
+	}
+	if _, ok := featureFlags[name]; !ok {
+		return fmt.Sprintf("Unknown flag `%s`. Try `!flags`.", name)
+	}
+
+	target := "workspace"
+	if len(args) > 2 {
+		target = args[2]
+	}
+
+	flagsMu.Lock()
+	defer flagsMu.Unlock()
+	overrides := overridesFor(name)
+
+	var level map[string]bool
+	var key, label string
+	if match := channelReferencePattern.FindStringSubmatch(target); match != nil {
+		level, key, label = overrides.Channels, match[1], target
+	} else if match := userReferencePattern.FindStringSubmatch(target); match != nil {
+		level, key, label = overrides.Users, match[1], target
+	} else if strings.EqualFold(target, "workspace") && event.Team != "" {
+		level, key, label = overrides.Workspaces, event.Team, "this workspace"
+	} else if strings.EqualFold(target, "workspace") || strings.EqualFold(target, "all") {
+		level, key, label = overrides.Workspaces, allWorkspaces, "all workspaces"
+	} else {
+		return usage
+	}
+
+	if action == "clear" {
+		delete(level, key)
+	} else {
+		level[key] = action == "enable"
+	}
+	if err := store.put(featureFlagsBucket, name, overrides); err != nil {
+		log.Printf("FLAG ERROR: Unable to persist overrides for %s.\nError Details: %v", name, err)
+	}
+	log.Printf("ADMIN: %s ran %s on flag %s for %s.", event.User, action, name, label)
+
+	if action == "clear" {
+		return fmt.Sprintf("Cleared `%s` override for %s.", name, label)
+	}
+	return fmt.Sprintf("`%s` is now %s for %s.", name, onOff(level[key]), label)
+}
+
+// Global function for dropping every flag override for a channel (persisting the change), returning how many were dropped
+func forgetChannelFlags(channel string) int {
+	flagsMu.Lock()
+	defer flagsMu.Unlock()
+
+	dropped := 0
+	for name, overrides := range flagOverrideTable {
+		if _, ok := overrides.Channels[channel]; !ok {
+			continue
+		}
+		delete(overrides.Channels, channel)
+		dropped++
+		if err := store.put(featureFlagsBucket, name, overrides); err != nil {
+			log.Printf("FLAG ERROR: Unable to persist overrides for %s.\nError Details: %v", name, err)
+		}
+	}
+	return dropped
+}
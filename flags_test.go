@@ -0,0 +1,162 @@
+//////////////////////////////////////////////////
+// Feature Flags Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing flag rollouts by user, channel, and workspace
+import (
+	"testing" // Permits Go testing
+)
+
+// Global function for defining a flag no other test uses, forgetting it and its overrides afterwards
+func withTestFlag(t *testing.T, name string, defaultOn bool) {
+	flagsMu.Lock()
+	registerFeatureFlag(featureFlag{name: name, description: "A flag for tests.", defaultOn: defaultOn})
+	flagsMu.Unlock()
+	t.Cleanup(func() {
+		flagsMu.Lock()
+		delete(featureFlags, name)
+		delete(flagOverrideTable, name)
+		flagsMu.Unlock()
+		store.delete(featureFlagsBucket, name)
+	})
+}
+
+// Global functions for picking one level out of a flag's overrides
+func channelOverrides(overrides *flagOverrides) map[string]bool   { return overrides.Channels }
+func userOverrides(overrides *flagOverrides) map[string]bool      { return overrides.Users }
+func workspaceOverrides(overrides *flagOverrides) map[string]bool { return overrides.Workspaces }
+
+// Test for "!flag" reading channel and user references, "workspace" as the asking workspace or every workspace when
+// none is known, and "all" as every workspace, refusing anything else
+func TestRunAdminFlagTargets(t *testing.T) {
+	const name = "test_targets"
+	withTestFlag(t, name, false)
+	cases := []struct {
+		team   string
+		args   []string
+		reply  string
+		level  func(*flagOverrides) map[string]bool
+		key    string
+		stored bool
+	}{
+		{"", []string{"enable", name, "<#CFLAG|general>"}, "`test_targets` is now on for <#CFLAG|general>.", channelOverrides, "CFLAG", true},
+		{"", []string{"disable", name, "<#CFLAG>"}, "`test_targets` is now off for <#CFLAG>.", channelOverrides, "CFLAG", true},
+		{"", []string{"ENABLE", name, "<@UFLAG>"}, "`test_targets` is now on for <@UFLAG>.", userOverrides, "UFLAG", true},
+		{"TFLAG", []string{"enable", name, "workspace"}, "`test_targets` is now on for this workspace.", workspaceOverrides, "TFLAG", true},
+		{"TFLAG", []string{"disable", name}, "`test_targets` is now off for this workspace.", workspaceOverrides, "TFLAG", true},
+		{"", []string{"enable", name, "Workspace"}, "`test_targets` is now on for all workspaces.", workspaceOverrides, allWorkspaces, true},
+		{"TFLAG", []string{"disable", name, "all"}, "`test_targets` is now off for all workspaces.", workspaceOverrides, allWorkspaces, true},
+		{"", []string{"clear", name, "<@UFLAG>"}, "Cleared `test_targets` override for <@UFLAG>.", userOverrides, "UFLAG", false},
+		{"", []string{"enable", name, "#general"}, "Usage: `!flag enable|disable|clear <flag> [#channel|@user|workspace|all]`", channelOverrides, "general", false},
+		{"", []string{"toggle", name}, "Usage: `!flag enable|disable|clear <flag> [#channel|@user|workspace|all]`", workspaceOverrides, "toggle", false},
+		{"", []string{"enable", "test_missing"}, "Unknown flag `test_missing`. Try `!flags`.", workspaceOverrides, "test_missing", false},
+	}
+	for _, c := range cases {
+		event := testMessage("CADMIN", "UADMIN", "7400.000001", "")
+		event.Team = c.team
+		if reply := runAdminFlag(event, c.args); reply != c.reply {
+			t.Errorf("runAdminFlag(%q) in team %q = %q; want %q", c.args, c.team, reply, c.reply)
+		}
+		flagsMu.Lock()
+		_, stored := c.level(overridesFor(name))[c.key]
+		flagsMu.Unlock()
+		if stored != c.stored {
+			t.Errorf("runAdminFlag(%q) in team %q left an override for %q = %v; want %v", c.args, c.team, c.key, stored, c.stored)
+		}
+	}
+}
+
+// Test for a flag evaluating as the asker's override, then their channel's, their workspace's, every workspace's, and
+// the default, in that order
+func TestEvaluateFlagPrecedence(t *testing.T) {
+	const name, onByDefault = "test_precedence", "test_default_on"
+	withTestFlag(t, name, false)
+	withTestFlag(t, onByDefault, true)
+	for _, args := range [][]string{
+		{"disable", name, "<@UOFF>"},
+		{"enable", name, "<#CON>"},
+		{"disable", name, "workspace"},
+		{"enable", name, "all"},
+	} {
+		event := testMessage("CADMIN", "UADMIN", "7400.000002", "")
+		event.Team = "TOFF"
+		runAdminFlag(event, args)
+	}
+
+	cases := []struct {
+		name  string
+		scope flagScope
+		want  bool
+	}{
+		{name, flagScope{user: "UOFF", channel: "CON", team: "TOFF"}, false},
+		{name, flagScope{user: "UOTHER", channel: "CON", team: "TOFF"}, true},
+		{name, flagScope{user: "UOTHER", channel: "COTHER", team: "TOFF"}, false},
+		{name, flagScope{user: "UOTHER", channel: "COTHER", team: "TOTHER"}, true},
+		{name, flagScope{}, true},
+		{onByDefault, flagScope{user: "UOFF", channel: "CON", team: "TOFF"}, true},
+		{"test_missing", flagScope{user: "UOFF"}, false},
+	}
+	for _, c := range cases {
+		if got := evaluateFlag(c.name, c.scope); got != c.want {
+			t.Errorf("evaluateFlag(%q, %+v) = %v; want %v", c.name, c.scope, got, c.want)
+		}
+	}
+
+	runAdminFlag(testMessage("CADMIN", "UADMIN", "7400.000003", ""), []string{"clear", name, "all"})
+	if evaluateFlag(name, flagScope{user: "UOTHER", channel: "COTHER", team: "TOTHER"}) {
+		t.Error("evaluateFlag with every matching override cleared = true; want the default of false")
+	}
+}
+
+// Test for overrides set by "!flag" reaching the data file and being loaded again on restart, on top of the
+// workspace-wide values in config
+func TestFeatureFlagOverridesPersisted(t *testing.T) {
+	const name = "test_persisted"
+	withTestFlag(t, name, false)
+	withConfig(t, func(c *Config) {
+		c.FeatureFlags = map[string]string{}
+		for flag, value := range config.FeatureFlags {
+			c.FeatureFlags[flag] = value
+		}
+		c.FeatureFlags[name] = "on"
+	})
+	event := testMessage("CADMIN", "UADMIN", "7400.000004", "")
+	event.Team = "TSAVED"
+	runAdminFlag(event, []string{"enable", name, "<@USAVED>"})
+	runAdminFlag(event, []string{"disable", name, "workspace"})
+	if err := store.flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	reopened, err := openStore(config.DataFile)
+	if err != nil {
+		t.Fatalf("reopening the data file: %v", err)
+	}
+	var saved flagOverrides
+	if !reopened.get(featureFlagsBucket, name, &saved) || !saved.Users["USAVED"] || saved.Workspaces["TSAVED"] {
+		t.Errorf("overrides in the data file = %+v; want USAVED on and TSAVED off", saved)
+	}
+
+	// Starting over from nothing in memory, as a restart would
+	flagsMu.Lock()
+	delete(flagOverrideTable, name)
+	flagsMu.Unlock()
+	loadFeatureFlags()
+	cases := []struct {
+		scope flagScope
+		want  bool
+	}{
+		{flagScope{user: "USAVED", team: "TSAVED"}, true},
+		{flagScope{user: "UOTHER", team: "TSAVED"}, false},
+		{flagScope{user: "UOTHER", team: "TOTHER"}, true},
+	}
+	for _, c := range cases {
+		if got := evaluateFlag(name, c.scope); got != c.want {
+			t.Errorf("after reloading, evaluateFlag(%q, %+v) = %v; want %v", name, c.scope, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+//////////////////////////////////////////////////
+// Flood Protection Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for shedding low-priority work while RTM events arrive faster than we can sensibly handle
+import (
+	"fmt"     // Permits formatting of alerts
+	"log"     // Permits console logging
+	"strings" // Permits mention detection
+	"time"    // Permits rate windows
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global state of the flood detector, only touched by the RTM loop
+var floodState struct {
+	windowStart time.Time
+	count       int
+	protecting  bool
+}
+
+// Global function for counting an incoming RTM event, reporting whether flood protection is active
+func noteIncomingEvent() bool {
+	if config.FloodThreshold <= 0 {
+		return false
+	}
+	now := time.Now()
+	if now.Sub(floodState.windowStart) >= config.FloodWindow {
+		// Leaving protective mode once a whole window stays at or under half the threshold (or passes with no events at all)
+		quiet := floodState.count <= config.FloodThreshold/2 || now.Sub(floodState.windowStart) >= 2*config.FloodWindow
+		if floodState.protecting && quiet {
+			setFloodProtection(false, floodState.count)
+		}
+		floodState.windowStart, floodState.count = now, 0
+	}
+	floodState.count++
+	if !floodState.protecting && floodState.count > config.FloodThreshold {
+		setFloodProtection(true, floodState.count)
+	}
+	return floodState.protecting
+}
+
+// Global function for entering or leaving protective mode, logging and alerting admins either way
+func setFloodProtection(on bool, count int) {
+	floodState.protecting = on
+	var gauge int64
+	alert := fmt.Sprintf(":ocean: Flood protection lifted: events are back to %d per %s.", count, config.FloodWindow)
+	if on {
+		gauge = 1
+		alert = fmt.Sprintf(":ocean: *Flood protection on*: over %d RTM events per %s. Only DMs, mentions, and admin messages are handled until it subsides.", config.FloodThreshold, config.FloodWindow)
+		metrics.inc("wolfy_flood_protection_entered_total")
+	}
+	metrics.set(gauge, "wolfy_flood_protection_active")
+	log.Printf("FLOOD ALERT: %s", alert)
+	if config.AdminChannel != "" {
+		go postText(config.AdminChannel, alert)
+	}
+}
+
+// Global function for checking whether an event is still handled during a flood: connection and channel lifecycle
+// events, and messages that DM, mention, or come from an admin
+func isPriorityEvent(msg slack.RTMEvent) bool {
+	switch event := msg.Data.(type) {
+	case *slack.ConnectingEvent, *slack.ConnectedEvent, *slack.DisconnectedEvent,
+		*slack.ChannelLeftEvent, *slack.GroupLeftEvent, *slack.ChannelArchiveEvent, *slack.GroupArchiveEvent, *slack.ChannelDeletedEvent:
+		return true
+	case *slack.MessageEvent:
+		return isDirectMessage(event.Channel) || isAuthorized(event.User, roleViewer) || (currentBotUserID() != "" && strings.Contains(event.Text, "<@"+currentBotUserID()+">"))
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+//////////////////////////////////////////////////
+// Answer Freshness Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for telling askers when the data behind a fast-changing answer was fetched
+import (
+	"context" // Permits tracing of freshness notes
+	"time"    // Permits formatting of fetch times
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global layout of the fetch time shown under time-sensitive answers, in the asker's timezone
+const freshnessLayout = "15:04 MST"
+
+// Global function for checking whether a query is about something that changes by the minute (weather, prices, stocks,
+// the time), judged by the same topic patterns the answer cache uses
+func isTimeSensitiveQuery(query string) bool {
+	for _, topic := range volatileTopics {
+		if topic.pattern.MatchString(query) {
+			return true
+		}
+	}
+	return false
+}
+
+// Global function for capping a time-sensitive query's cache TTL at the configured short one, leaving uncached
+// intents uncached
+func timeSensitiveCacheTTL(query string, ttl time.Duration) time.Duration {
+	if ttl <= 0 || config.TimeSensitiveCacheTTL <= 0 || !isTimeSensitiveQuery(query) || ttl <= config.TimeSensitiveCacheTTL {
+		return ttl
+	}
+	return config.TimeSensitiveCacheTTL
+}
+
+// Global function for noting when a time-sensitive answer's data was fetched ("as of 14:05 PDT"), whether that was just
+// now or when it was cached. Answers without a fetch time (local ones, for instance) get no note.
+func freshnessNote(user string, response handlerResponse) string {
+	if !config.AnswerFreshness || response.AnsweredAt.IsZero() || !isTimeSensitiveQuery(response.Query) {
+		return ""
+	}
+	return "_as of " + response.AnsweredAt.In(userLocation(user)).Format(freshnessLayout) + "_"
+}
+
+// Global function for adding the freshness note to a handler's reply, on its own line
+func withFreshness(ctx context.Context, event *slack.MessageEvent, response handlerResponse, reply string) string {
+	note := freshnessNote(event.User, response)
+	if note == "" || isTransientReply(reply) {
+		return reply
+	}
+	metrics.inc("wolfy_freshness_notes_total", "source", map[bool]string{true: "cache", false: "live"}[response.Cached])
+	traceStep(ctx, "noting the answer's data is from %s", response.AnsweredAt.Format(time.RFC3339))
+	return reply + "\n" + note
+}
@@ -0,0 +1,109 @@
+//////////////////////////////////////////////////
+// Answer Freshness Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing the "as of" notes under time-sensitive answers, fetched just now or served from the cache
+import (
+	"context"  // Permits the handler's request context
+	"net/http" // Permits the fake Wolfram APIs
+	"strings"  // Permits matching of notes
+	"testing"  // Permits Go testing
+	"time"     // Permits fixed fetch times
+
+	wit "github.com/christianrondeau/go-wit" // External Wit.ai API
+	slack "github.com/nlopes/slack"          // External Slack API
+)
+
+// Test for the note showing a time-sensitive answer's fetch time in the asker's timezone, and no note for other
+// answers, answers without a fetch time, or with the flag off
+func TestFreshnessNote(t *testing.T) {
+	userInfo.put("UFRESH", &slack.User{ID: "UFRESH", TZ: "America/Los_Angeles"})
+	t.Cleanup(func() { userInfo.remove("UFRESH") })
+	answeredAt := time.Date(2026, 6, 10, 21, 5, 0, 0, time.UTC)
+	cases := []struct {
+		name     string
+		flag     bool
+		response handlerResponse
+		want     string
+	}{
+		{"weather", true, handlerResponse{Query: "weather in Paris", AnsweredAt: answeredAt}, "_as of 14:05 PDT_"},
+		{"exchange rate", true, handlerResponse{Query: "USD to EUR", AnsweredAt: answeredAt, Cached: true}, "_as of 14:05 PDT_"},
+		{"not time-sensitive", true, handlerResponse{Query: "mass of Mars", AnsweredAt: answeredAt}, ""},
+		{"no fetch time", true, handlerResponse{Query: "weather in Paris"}, ""},
+		{"flag off", false, handlerResponse{Query: "weather in Paris", AnsweredAt: answeredAt}, ""},
+	}
+	for _, c := range cases {
+		withConfig(t, func(config *Config) { config.AnswerFreshness = c.flag })
+		if got := freshnessNote("UFRESH", c.response); got != c.want {
+			t.Errorf("%s: freshnessNote = %q; want %q", c.name, got, c.want)
+		}
+	}
+}
+
+// Test for a freshly fetched answer being noted as of now, and the same question answered from the cache being noted
+// as of when it was cached, each counted under its source
+func TestFreshnessNoteSources(t *testing.T) {
+	withConfig(t, func(config *Config) {
+		config.AnswerFreshness = true
+		config.AnswerCacheIntents = map[string]string{"weather": "10m"}
+	})
+	previous := answers
+	answers = newMemoryAnswerCache()
+	t.Cleanup(func() { answers = previous })
+	fetches := 0
+	withExternalAPIs(t, func(req *http.Request) (int, string) {
+		if strings.HasPrefix(req.URL.String(), wolframShortAnswerURL) {
+			fetches++
+			return http.StatusOK, "18 degrees Celsius and clear"
+		}
+		return http.StatusNotImplemented, ""
+	})
+
+	ask := func() (handlerResponse, string) {
+		event := testMessage("CFRESH", "UFRESH", "8300.000001", "weather in Paris")
+		response, err := handleWolframQuery(context.Background(), event, wit.MessageEntity{Value: "weather in Paris"})
+		if err != nil {
+			t.Fatalf("handleWolframQuery: %v", err)
+		}
+		return response, withFreshness(context.Background(), event, response, response.Text)
+	}
+
+	live := metricValue("wolfy_freshness_notes_total", "source", "live")
+	fresh, reply := ask()
+	if fresh.Cached || fetches != 1 || time.Since(fresh.AnsweredAt) > time.Minute {
+		t.Fatalf("first answer cached %v after %d fetches, answered at %s; want a live fetch just now", fresh.Cached, fetches, fresh.AnsweredAt)
+	}
+	if want := "\n_as of " + fresh.AnsweredAt.UTC().Format(freshnessLayout) + "_"; !strings.HasSuffix(reply, want) {
+		t.Errorf("fresh reply = %q; want it to end with %q", reply, want)
+	}
+	if got := metricValue("wolfy_freshness_notes_total", "source", "live") - live; got != 1 {
+		t.Errorf("counted %d live freshness notes; want 1", got)
+	}
+
+	// Backdating the cached entry, as if it had been fetched a few minutes ago
+	storedAt := time.Now().Add(-5 * time.Minute).Truncate(time.Minute)
+	units, _ := resolveUnits("UFRESH")
+	key := answerCacheKey("weather in Paris", units)
+	entry, ok := answers.lookup(key)
+	if !ok {
+		t.Fatalf("the fresh answer was not cached under %q", key)
+	}
+	entry.StoredAt = storedAt
+	answers.store(key, entry, nil)
+
+	fromCache := metricValue("wolfy_freshness_notes_total", "source", "cache")
+	cached, reply := ask()
+	if !cached.Cached || fetches != 1 || !cached.AnsweredAt.Equal(storedAt) {
+		t.Fatalf("second answer cached %v after %d fetches, answered at %s; want a cache hit answered at %s", cached.Cached, fetches, cached.AnsweredAt, storedAt)
+	}
+	if want := "\n_as of " + storedAt.UTC().Format(freshnessLayout) + "_"; !strings.HasSuffix(reply, want) {
+		t.Errorf("cached reply = %q; want it to end with %q", reply, want)
+	}
+	if got := metricValue("wolfy_freshness_notes_total", "source", "cache") - fromCache; got != 1 {
+		t.Errorf("counted %d cached freshness notes; want 1", got)
+	}
+}
@@ -0,0 +1,139 @@
+//////////////////////////////////////////////////
+// Geography Answers Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for answering geography questions with a place's location, its neighbors as a list, and a map
+import (
+	"context" // Permits tracing and bounding of the geography lookup
+	"fmt"     // Permits formatting of the neighbors heading
+	"log"     // Permits console logging
+	"net/url" // Permits building of full results parameters
+	"regexp"  // Permits recognition of geography questions, pods, and property rows
+	"strings" // Permits string building and normalization
+
+	wolfram "github.com/Krognol/go-wolfram" // External Wolfram|Alpha API
+	slack "github.com/nlopes/slack"         // External Slack API
+)
+
+// Global constant holding the most property rows listed beside the neighbors
+const geographyMaxProperties = 6
+
+// Global patterns recognizing geography questions ("where is Mount Everest", "what countries border France"), the pods
+// describing a place, the pods listing its neighbors, and the pods drawing it on a map
+var (
+	geographyQueryPattern     = regexp.MustCompile(`(?i)\b(?:where\s+(?:is|are)|(?:located|location|coordinates|latitude|longitude)\b|(?:countries|states|nations)\s+(?:that\s+)?borders?|borders?\s+of|bordering|neighbou?r(?:ing|s)?|capital\s+of|elevation\s+of|how\s+(?:big|large)\s+is)`)
+	geographyPodPattern       = regexp.MustCompile(`(?i)location|coordinates|position|geographic|capital|elevation|area|border|neighbo`)
+	geographyNeighborsPattern = regexp.MustCompile(`(?i)border|neighbo`)
+	geographyMapPattern       = regexp.MustCompile(`(?i)map`)
+)
+
+// Global struct holding a geography answer: what Wolfram took the question to be about, its property rows, its
+// neighbors, and a map image URL (empty when there is none or maps are off)
+type geographyAnswer struct {
+	interpretation string
+	properties     [][2]string
+	neighbors      []string
+	mapURL         string
+}
+
+// Registering the geography formatting flag, on unless rolled back
+func init() {
+	registerFeatureFlag(featureFlag{name: "geography_formatting", description: "Answer geography questions (\"where is Mount Everest\", \"what countries border France\") with the location, neighbors as a list, and a map.", defaultOn: true})
+}
+
+// Global function for checking whether a question asks where a place is, what borders it, or how it sits on a map
+func isGeographyQuery(query string) bool {
+	return geographyQueryPattern.MatchString(query)
+}
+
+// Global function for fetching a geography question's location rows, neighbors, and map from the full results API,
+// reporting false when no pod describes a place
+func fetchGeographyAnswer(ctx context.Context, client *wolfram.Client, query string, units wolfram.Unit) (geographyAnswer, bool) {
+	result, err := fetchFullResult(ctx, client, query, url.Values{"format": {"image,plaintext"}, "units": {unitsName(units)}})
+	if err != nil {
+		log.Printf("WOLFRAM ERROR: Unable to fetch the geography for %q.\nError Details: %v", query, err)
+		return geographyAnswer{}, false
+	}
+
+	var answer geographyAnswer
+	seen := map[string]bool{}
+	for _, pod := range result.Pods {
+		name := pod.ID + " " + pod.Title
+		switch {
+		case pod.ID == "Input":
+			answer.interpretation = strings.TrimSpace(strings.SplitN(pod.plaintext(), "|", 2)[0])
+		case geographyMapPattern.MatchString(name):
+			for _, subPod := range pod.SubPods {
+				if config.GeographyMaps && answer.mapURL == "" && subPod.Img.Src != "" {
+					answer.mapURL = subPod.Img.Src
+				}
+			}
+		case geographyNeighborsPattern.MatchString(name) && answer.neighbors == nil:
+			// Neighbors come as one row of "|"-separated names, sometimes wrapped over several lines
+			for _, line := range strings.Split(pod.plaintext(), "\n") {
+				for _, neighbor := range strings.Split(line, "|") {
+					if neighbor = strings.TrimSpace(neighbor); neighbor != "" && !strings.HasPrefix(neighbor, "(") {
+						answer.neighbors = append(answer.neighbors, neighbor)
+					}
+				}
+			}
+		case geographyPodPattern.MatchString(name):
+			for _, line := range strings.Split(pod.plaintext(), "\n") {
+				label, value := pod.Title, strings.TrimSpace(line)
+				if parts := strings.SplitN(line, "|", 2); len(parts) == 2 {
+					label, value = strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+				}
+				if value == "" || seen[strings.ToLower(label)] || len(answer.properties) >= geographyMaxProperties {
+					continue
+				}
+				seen[strings.ToLower(label)] = true
+				answer.properties = append(answer.properties, [2]string{label, value})
+			}
+		}
+	}
+	if len(answer.properties) == 0 && len(answer.neighbors) == 0 {
+		return geographyAnswer{}, false
+	}
+	return answer, true
+}
+
+// Global function for answering a geography question with the place's location rows and neighbors as bullets, and a map
+// attached when configured. Reports false (answer normally, in plaintext) when the flag is off, the question isn't
+// about geography, or Wolfram has no place data for it.
+func answerGeography(ctx context.Context, event *slack.MessageEvent, query string, units wolfram.Unit) (handlerResponse, bool) {
+	if !flagEnabled(ctx, "geography_formatting") || !isGeographyQuery(query) {
+		return handlerResponse{}, false
+	}
+	answer, ok := fetchGeographyAnswer(ctx, wolframClientFor(event), query, units)
+	if !ok {
+		traceStep(ctx, "no geography found for %q", query)
+		return handlerResponse{}, false
+	}
+	metrics.inc("wolfy_geography_answers_total", "map", map[bool]string{true: "attached", false: "none"}[answer.mapURL != ""])
+	traceStep(ctx, "geography for %q (%d properties, %d neighbors, map %t)", query, len(answer.properties), len(answer.neighbors), answer.mapURL != "")
+
+	var lines []string
+	if answer.interpretation != "" {
+		lines = append(lines, ":round_pushpin: *"+answer.interpretation+"*")
+	}
+	for _, property := range answer.properties {
+		label := []rune(property[0])
+		lines = append(lines, "• *"+strings.ToUpper(string(label[:1]))+string(label[1:])+":* "+property[1])
+	}
+	if len(answer.neighbors) > 0 {
+		lines = append(lines, fmt.Sprintf("*Bordered by (%d):* %s", len(answer.neighbors), strings.Join(answer.neighbors, ", ")))
+	}
+	return handlerResponse{
+		Text:  strings.Join(lines, "\n"),
+		Query: query,
+		Details: []slack.AttachmentField{
+			{Title: "Interpreted as", Value: query, Short: true},
+			{Title: "Source", Value: "Wolfram|Alpha", Short: true},
+		},
+		Image: answer.mapURL,
+	}, true
+}
@@ -1,9 +1,16 @@
 module github.com/AakashSudhakar/wolfybot
 
+go 1.27.1
+
 require (
 	github.com/Krognol/go-wolfram v0.0.0-20180610151123-5b91101b92a8
+	github.com/alicebob/miniredis/v2 v2.39.0
 	github.com/christianrondeau/go-wit v0.0.0-20170831224739-d540d3cc2c3d
-	github.com/gorilla/websocket v1.4.0 // indirect
+	github.com/gorilla/websocket v1.4.0
 	github.com/nlopes/slack v0.5.0
+)
+
+require (
 	github.com/pkg/errors v0.8.1 // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
 )
@@ -0,0 +1,171 @@
+//////////////////////////////////////////////////
+// Message Grouping Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for answering a user's rapid-fire messages one after another instead of all at once
+import (
+	"log"     // Permits console logging
+	"regexp"  // Permits recognition of follow-ups
+	"strings" // Permits detection of other users' mentions and building of resolved follow-ups
+	"sync"    // Permits concurrency-safe access to the groups
+	"time"    // Permits the grouping window
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global struct holding one user's run of messages in a conversation, kept while one of them is being answered: when
+// the latest arrived, and those waiting their turn
+type messageGroup struct {
+	lastArrival time.Time
+	pending     []*slack.MessageEvent
+}
+
+// Global groups by user and conversation, the group each message answered as part of one belongs to, and the messages
+// that waited behind another in their group
+var messageGroups = struct {
+	sync.Mutex
+	groups  map[string]*messageGroup
+	members map[string]string
+	held    map[string]bool
+}{groups: map[string]*messageGroup{}, members: map[string]string{}, held: map[string]bool{}}
+
+// Global pattern recognizing a follow-up that continues the question before it ("now from 0 to 3", "and in celsius"),
+// capturing the continuation
+var groupContinuationPattern = regexp.MustCompile(`(?i)^\s*(?:(?:and|now|then)\s+)+((?:from|to|in|at|for|with|over|between|as|without)\s.+?)\s*[?.!]*\s*$`)
+
+// Global pattern recognizing a follow-up about the answer before it ("and double it", "now what is that times 2"),
+// capturing the words around the reference
+var groupResultPattern = regexp.MustCompile(`(?i)^\s*(?:(?:and|now|then)\s+)*(.*?)\b(?:it|that|the result|the answer)\b(.*?)\s*[?.!]*\s*$`)
+
+// Global constant capping how long an answer may be to stand in for "it" in the follow-up after it
+const maxGroupResultLength = 40
+
+// Global function for keying a user's group in one conversation (a channel, or a thread within it)
+func messageGroupKey(event *slack.MessageEvent) string {
+	return event.User + ":" + event.Channel + ":" + event.ThreadTimestamp
+}
+
+// Global function for checking whether a message may join its asker's group: not when it mentions someone other than
+// the bot, since it's then about something else
+func groupableMessage(event *slack.MessageEvent) bool {
+	text := event.Text
+	if currentBotUserID() != "" {
+		text = strings.Replace(text, "<@"+currentBotUserID()+">", "", -1)
+	}
+	return !strings.Contains(text, "<@")
+}
+
+// Global function for dispatching a message, or holding it behind its asker's previous one in the same conversation
+// when that is still being answered and arrived within the grouping window, so "integrate x^2", "now from 0 to 3",
+// "and double it" are answered in order, each seeing the answer before it. A message with nothing still being
+// answered ahead of it is dispatched at once, so a lone question waits for nothing.
+func dispatchGroupedMessage(event *slack.MessageEvent) {
+	if config.MessageGroupWindow <= 0 || !groupableMessage(event) {
+		dispatchMessage(event)
+		return
+	}
+	key := messageGroupKey(event)
+	now := time.Now()
+
+	messageGroups.Lock()
+	group, ok := messageGroups.groups[key]
+	if ok && now.Sub(group.lastArrival) <= config.MessageGroupWindow {
+		group.pending = append(group.pending, event)
+		group.lastArrival = now
+		messageGroups.members[messageEventKey(event)] = key
+		messageGroups.held[messageEventKey(event)] = true
+		messageGroups.Unlock()
+		metrics.inc("wolfy_grouped_messages_total")
+		return
+	}
+	if !ok {
+		messageGroups.groups[key] = &messageGroup{lastArrival: now}
+		messageGroups.members[messageEventKey(event)] = key
+	}
+	messageGroups.Unlock()
+	dispatchMessage(event)
+}
+
+// Global function for handing the next waiting message of a group to the workers once the one before it is done
+// (answered, or dropped as stale), and closing the group when none wait
+func finishGroupedMessage(event *slack.MessageEvent) {
+	messageGroups.Lock()
+	key, ok := messageGroups.members[messageEventKey(event)]
+	if !ok {
+		messageGroups.Unlock()
+		return
+	}
+	delete(messageGroups.members, messageEventKey(event))
+	delete(messageGroups.held, messageEventKey(event))
+	group := messageGroups.groups[key]
+	if len(group.pending) == 0 {
+		delete(messageGroups.groups, key)
+		messageGroups.Unlock()
+		return
+	}
+	next := group.pending[0]
+	group.pending = group.pending[1:]
+	messageGroups.Unlock()
+	dispatchMessage(next)
+}
+
+// Global function for giving a message that waited behind its asker's previous one the context of that exchange, so
+// the run shares one line of questioning: a continuation extends the previous query ("now from 0 to 3" after
+// "integrate x^2" asks "integrate x^2 from 0 to 3") and a reference to the previous answer is replaced by it ("and
+// double it" after an answer of 9 asks "double 9")
+func attachGroupContext(event *slack.MessageEvent) {
+	messageGroups.Lock()
+	held := messageGroups.held[messageEventKey(event)]
+	messageGroups.Unlock()
+	if !held {
+		return
+	}
+	last, ok := lastInteractionIn(event.User, event.Channel)
+	if !ok {
+		return
+	}
+	resolved, ok := resolveGroupFollowUp(event.Msg.Text, last)
+	if !ok {
+		return
+	}
+	log.Printf("GROUPING: Resolved the follow-up %q from %s to %q.", event.Msg.Text, event.User, resolved)
+	metrics.inc("wolfy_grouped_followups_resolved_total")
+	event.Msg.Text = resolved
+}
+
+// Global function for resolving a follow-up against the exchange before it, reporting false when it stands alone
+func resolveGroupFollowUp(text string, last interaction) (string, bool) {
+	previous := last.Query
+	if previous == "" {
+		previous = last.Text
+	}
+	if match := groupContinuationPattern.FindStringSubmatch(text); match != nil && previous != "" {
+		return strings.TrimRight(previous, "?. ") + " " + match[1], true
+	}
+	if result := groupResult(last.Answer); result != "" {
+		if match := groupResultPattern.FindStringSubmatch(text); match != nil && strings.TrimSpace(match[1]+match[2]) != "" {
+			return strings.TrimSpace(match[1] + result + match[2]), true
+		}
+	}
+	return "", false
+}
+
+// Global function for reading the value an answer gave (after its last "=", as in "5 * 6 = 30"), or nothing when the
+// answer was a failure or too long to stand in for "it"
+func groupResult(answer string) string {
+	if answer == "" || failureCodePattern.MatchString(answer) || isFailureReply(answer) || strings.Contains(answer, "\n") {
+		return ""
+	}
+	if i := strings.LastIndex(answer, " = "); i >= 0 {
+		answer = answer[i+3:]
+	}
+	answer = strings.TrimSpace(strings.Trim(answer, "*_`"))
+	if len(answer) > maxGroupResultLength {
+		return ""
+	}
+	return answer
+}
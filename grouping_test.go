@@ -0,0 +1,87 @@
+//////////////////////////////////////////////////
+// Message Grouping Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing rapid-fire messages answered in order with shared context
+import (
+	"strings" // Permits inspection of posted answers
+	"testing" // Permits Go testing
+	"time"    // Permits the grouping window
+)
+
+// Test for resolving a held follow-up against the exchange before it, and for leaving standalone messages alone
+func TestResolveGroupFollowUp(t *testing.T) {
+	integral := interaction{Text: "integrate x^2", Query: "integrate x^2", Answer: "x^3/3 + constant"}
+	product := interaction{Text: "what is 5 * 6", Answer: "5 * 6 = 30"}
+	failed := interaction{Text: "population of mars", Query: "population of mars", Answer: "Sorry, I couldn't get an answer for that in time. :-( `[W-TIMEOUT]`"}
+	cases := []struct {
+		text string
+		last interaction
+		want string
+		ok   bool
+	}{
+		{"now from 0 to 3", integral, "integrate x^2 from 0 to 3", true},
+		{"and then in celsius?", interaction{Text: "boiling point of water?"}, "boiling point of water in celsius", true},
+		{"and double it", product, "double 30", true},
+		{"now what is that times 2", product, "what is 30 times 2", true},
+		{"and double it", failed, "", false},
+		{"and double it", interaction{Text: "tell me a story", Answer: strings.Repeat("once upon a time ", 5)}, "", false},
+		{"distance to mars", integral, "", false},
+		{"it", product, "", false},
+	}
+	for _, c := range cases {
+		if got, ok := resolveGroupFollowUp(c.text, c.last); got != c.want || ok != c.ok {
+			t.Errorf("resolveGroupFollowUp(%q, %q) = %q, %v; want %q, %v", c.text, c.last.Text, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+// Test for a rapid-fire follow-up being held behind the message before it and then asked with its answer
+func TestGroupedFollowUpSharesContext(t *testing.T) {
+	first := testMessage("DGROUP", "UGROUP", "6200.000001", "what is 5 * 6")
+	second := testMessage("DGROUP", "UGROUP", "6200.000002", "and what is it times 2")
+	key := messageGroupKey(first)
+
+	// Opening the group as dispatching the first message would, so the second waits behind it
+	messageGroups.Lock()
+	messageGroups.groups[key] = &messageGroup{lastArrival: time.Now()}
+	messageGroups.members[messageEventKey(first)] = key
+	messageGroups.Unlock()
+	dispatchGroupedMessage(second)
+	messageGroups.Lock()
+	held := messageGroups.held[messageEventKey(second)]
+	pending := len(messageGroups.groups[key].pending)
+	// Handing the follow-up over by hand rather than when the first finishes, so both are answered synchronously
+	messageGroups.groups[key].pending = nil
+	delete(messageGroups.members, messageEventKey(first))
+	messageGroups.Unlock()
+	if !held || pending != 1 {
+		t.Fatalf("after dispatching the follow-up, held = %v and pending = %d; want true and 1", held, pending)
+	}
+
+	resolved := metricValue("wolfy_grouped_followups_resolved_total")
+	handleMSGEvent(first)
+	handleMSGEvent(second)
+	if got := metricValue("wolfy_grouped_followups_resolved_total") - resolved; got != 1 {
+		t.Errorf("follow-ups resolved = %d; want 1", got)
+	}
+	found := false
+	for _, call := range answerPosts() {
+		found = found || (call.values.Get("channel") == "UGROUP" && call.values.Get("text") == "30 times 2 = 60")
+	}
+	if !found {
+		t.Error("the follow-up wasn't answered as \"what is 30 times 2\"")
+	}
+
+	messageGroups.Lock()
+	_, open := messageGroups.groups[key]
+	held = messageGroups.held[messageEventKey(second)]
+	messageGroups.Unlock()
+	if open || held {
+		t.Errorf("after both answers, group open = %v and follow-up held = %v; want both false", open, held)
+	}
+}
@@ -0,0 +1,327 @@
+//////////////////////////////////////////////////
+// Entity Handlers Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for the entity handler registry and dispatcher
+import (
+	"context" // Permits per-handler deadlines
+	"fmt"     // Permits formatting of hedged answers
+	"log"     // Permits console logging
+	"sort"    // Permits stable ordering of handler names
+	"time"    // Permits handler timeouts
+
+	wit "github.com/christianrondeau/go-wit" // External Wit.ai API
+	slack "github.com/nlopes/slack"          // External Slack API
+)
+
+// Global struct holding a handler's reply, the backend query it made (if any), and supporting detail shown secondarily
+type handlerResponse struct {
+	Text    string
+	Query   string
+	Cached  bool
+	Details []slack.AttachmentField
+	Image   string
+
+	// When the data behind the answer was fetched: just now for a live answer, when it was stored for a cached one
+	// (zero for answers not fetched from anywhere)
+	AnsweredAt time.Time
+}
+
+// Global struct describing a handler registered for a Wit.ai entity key (local handlers are dispatched by the bot itself,
+// so their key needn't exist in the Wit.ai app; repeatable handlers answer repeats without the default cooldown)
+type entityHandler struct {
+	name        string
+	description string
+	timeout     time.Duration
+	local       bool
+	repeatable  bool
+	handle      func(ctx context.Context, event *slack.MessageEvent, entity wit.MessageEntity) (handlerResponse, error)
+}
+
+// Global struct holding the outcome of a handler invocation
+type handlerResult struct {
+	response handlerResponse
+	err      error
+}
+
+// Global registry of handlers keyed by the Wit.ai entity key they answer
+var entityHandlers = map[string]*entityHandler{}
+
+// Registering the built-in handlers
+func init() {
+	registerEntityHandler(&entityHandler{
+		name:        "greetings",
+		description: "Say hello.",
+		timeout:     2 * time.Second,
+		repeatable:  true,
+		handle:      handleGreeting,
+	})
+	registerEntityHandler(&entityHandler{
+		name:        "wolfram_search_query",
+		description: "Answer factual and math questions via Wolfram|Alpha.",
+		handle:      handleWolframQuery,
+	})
+}
+
+// Global function for adding a handler to the registry
+func registerEntityHandler(handler *entityHandler) {
+	entityHandlers[handler.name] = handler
+}
+
+// Global function for listing registered handler names in a stable order
+func entityHandlerNames() []string {
+	names := make([]string, 0, len(entityHandlers))
+	for name := range entityHandlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Global function for resolving a handler's timeout: config override, then declared timeout, then global default
+func handlerTimeout(handler *entityHandler) time.Duration {
+	if override, ok := config.HandlerTimeouts[handler.name]; ok {
+		if timeout, err := time.ParseDuration(override); err == nil && timeout > 0 {
+			return timeout
+		}
+		log.Printf("CONFIG ERROR: Ignoring invalid timeout %q for handler %s.", override, handler.name)
+	}
+	if handler.timeout > 0 {
+		return handler.timeout
+	}
+	return config.HandlerTimeout
+}
+
+// Global function for routing a classified intent with no handler to Wolfram with the raw message, when that fallback is
+// on, reporting false (give up as before) when it is off, nothing cleared the confidence threshold, or Wolfram itself
+// isn't routed
+func unknownIntentFallback(ctx context.Context, event *slack.MessageEvent, entityKey string, entity wit.MessageEntity) (*entityHandler, wit.MessageEntity, bool) {
+	if !config.UnknownIntentFallback || entityKey == "" {
+		return nil, entity, false
+	}
+	handler, ok := routeEntity(ctx, "wolfram_search_query")
+	if !ok || !handlerEnabled(handler.name) {
+		return nil, entity, false
+	}
+	metrics.inc("wolfy_unknown_intent_fallbacks_total", "intent", intentLabel(entityKey))
+	log.Printf("ROUTING: No handler for intent %s; trying the message as a Wolfram query.", intentLabel(entityKey))
+	traceStep(ctx, "no handler registered for %s, falling back to Wolfram with the raw message", intentLabel(entityKey))
+	return handler, wit.MessageEntity{Value: event.Msg.Text, Confidence: entity.Confidence}, true
+}
+
+// Global function for running the handler for an entity key and delivering its reply
+func dispatchEntity(parent context.Context, event *slack.MessageEvent, entityKey string, entity wit.MessageEntity) {
+	handler, ok := routeEntity(parent, entityKey)
+	if !ok {
+		handler, entity, ok = unknownIntentFallback(parent, event, entityKey, entity)
+	}
+	if !ok {
+		traceStep(parent, "no handler registered for %s", intentLabel(entityKey))
+		logFailure(parent, errNLPLowConfidence, "routing "+intentLabel(entityKey))
+		reply := unclearReply(parent)
+		if offered := withEscalationOffer(parent, event, reply); offered != reply {
+			postText(answerDestination(event), offered)
+		} else {
+			postTransientText(answerDestination(event), reply)
+		}
+		rememberInteraction(event, interaction{Text: event.Msg.Text, EntityKey: entityKey, Confidence: entity.Confidence, Answer: reply, Outcome: "unhandled", Calls: apiCalls(parent)})
+		return
+	}
+
+	// Telling the asker, only them, when the handler is switched off here
+	if !handlerEnabled(handler.name) {
+		traceStep(parent, "handler %s is disabled", handler.name)
+		noteHandlerDisabled(event, handler.name)
+		rememberInteraction(event, interaction{Text: event.Msg.Text, EntityKey: entityKey, Confidence: entity.Confidence, Answer: handlerDisabledNotice, Outcome: "disabled", Calls: apiCalls(parent)})
+		return
+	}
+
+	// Pointing the asker back at an answer they were just given rather than querying again
+	if answeredRecently(event.User, handler, event.Msg.Text) {
+		traceStep(parent, "answered %q for this asker within the %s cooldown", event.Msg.Text, repeatCooldown(handler))
+		metrics.inc("wolfy_repeat_cooldowns_total", "handler", handler.name)
+		postTransientText(answerDestination(event), repeatCooldownNotice)
+		return
+	}
+
+	// Running the handler against its own deadline, independent of the interactive reply deadline
+	ctx, cancel := context.WithTimeout(withCacheIntent(parent, entityKey), handlerTimeout(handler))
+	defer cancel()
+	traceStep(ctx, "dispatching to handler %s (timeout %s)", handler.name, handlerTimeout(handler))
+	noteStageIntent(ctx, entityKey)
+	endAnswer := timeStage(ctx, "answer")
+
+	results := make(chan handlerResult, 1)
+	go func() {
+		response, err := handler.handle(ctx, event, entity)
+		results <- handlerResult{response: response, err: err}
+	}()
+
+	var (
+		result   handlerResult
+		noticeTS string
+		channel  = answerDestination(event)
+	)
+	select {
+	case result = <-results:
+	case <-ctx.Done():
+		result = handlerResult{err: ctx.Err()}
+	case <-time.After(config.AnswerTimeout):
+		// Letting the user know we're still working, then swapping the placeholder for the eventual answer
+		metrics.inc("wolfy_slow_answers_total", "handler", handler.name)
+		noticeChannel, ts, err := postMessage(channel, messageText(placeholderText(ctx)))
+		if err == nil {
+			channel, noticeTS = noticeChannel, ts
+			if request := inFlightFrom(ctx); request != nil {
+				request.notePlaceholder(channel, noticeTS)
+			}
+		}
+		escalation := startThinkingEscalation(config.AnswerTimeout)
+	waiting:
+		for {
+			select {
+			case result = <-results:
+				break waiting
+			case <-ctx.Done():
+				// Giving up on handlers that ignore their context once the handler or message deadline passes
+				result = handlerResult{err: ctx.Err()}
+				break waiting
+			case <-escalation.stillWorking:
+				channel, noticeTS = escalatePlaceholder(ctx, channel, noticeTS, "still_working")
+			case <-escalation.cancelOffer:
+				channel, noticeTS = escalatePlaceholder(ctx, channel, noticeTS, "cancel_offer")
+			}
+		}
+		escalation.stop()
+	}
+	endAnswer()
+
+	// Settling the race with "wolfy cancel": whichever claims the request first wins
+	if !claimDelivery(ctx) {
+		traceStep(ctx, "handler %s cancelled by the asker", handler.name)
+		if noticeTS != "" {
+			replaceMessage(channel, noticeTS, messageText(cancelledNotice))
+		}
+		return
+	}
+	reply := handlerReplyText(ctx, handler, result)
+	if result.err == nil {
+		reply = hedgeAnswer(ctx, entity, result.response, reply)
+		reply = withFreshness(ctx, event, result.response, reply)
+	}
+	reply = withEscalationOffer(ctx, event, reply)
+	reply = withOnboardingNote(ctx, event, reply)
+	reply = withQuoteCredit(ctx, reply)
+	traceStep(ctx, "handler %s finished: %s", handler.name, handlerOutcome(ctx, result))
+	var details []slack.AttachmentField
+	var image string
+	if result.err == nil {
+		details, image = result.response.Details, result.response.Image
+		reply, details = applyVerbosity(ctx, event.User, channel, reply, details)
+		if note := performanceNote(ctx, event.User, result.response); note != "" {
+			reply += "\n" + note
+		}
+	}
+	endDeliver := timeStage(ctx, "deliver")
+	if replyChannel, replyTS := deliverAnswer(event, channel, noticeTS, reply, details, image); isTransientReply(reply) {
+		expireMessage(replyChannel, replyTS)
+	}
+	endDeliver()
+	if result.err == nil && !isTransientReply(reply) {
+		rememberRecentAnswer(event.User, handler, event.Msg.Text)
+	}
+
+	// Remembering the exchange so the user can ask about it later
+	rememberInteraction(event, interaction{
+		Text:       event.Msg.Text,
+		EntityKey:  entityKey,
+		Confidence: entity.Confidence,
+		Query:      result.response.Query,
+		Cached:     result.response.Cached,
+		AnsweredAt: result.response.AnsweredAt,
+		Answer:     reply,
+		Outcome:    handlerOutcome(ctx, result),
+		Calls:      apiCalls(ctx),
+	})
+}
+
+// Global function for running the handler for an entity key synchronously, returning its reply text
+func runEntityHandler(parent context.Context, event *slack.MessageEvent, entityKey string, entity wit.MessageEntity) string {
+	handler, ok := routeEntity(parent, entityKey)
+	if !ok {
+		handler, entity, ok = unknownIntentFallback(parent, event, entityKey, entity)
+	}
+	if !ok {
+		return unclearReply(parent)
+	}
+	if !handlerEnabled(handler.name) {
+		return handlerDisabledNotice
+	}
+
+	ctx, cancel := context.WithTimeout(parent, handlerTimeout(handler))
+	defer cancel()
+	response, err := handler.handle(ctx, event, entity)
+	reply := handlerReplyText(ctx, handler, handlerResult{response: response, err: err})
+	if err == nil {
+		reply = withFreshness(ctx, event, response, reply)
+	}
+	return reply
+}
+
+// Global function for turning a handler result into reply text, accounting for timeouts and failures
+func handlerReplyText(ctx context.Context, handler *entityHandler, result handlerResult) string {
+	if !requestCancelled(ctx) {
+		noteStageResult("answer", result.err != nil && !classifyFailure(result.err).expected)
+	}
+	if result.err == nil {
+		return result.response.Text
+	}
+
+	err := result.err
+	if ctx.Err() == context.DeadlineExceeded {
+		metrics.inc("wolfy_handler_timeouts_total", "handler", handler.name)
+		err = wrapFailure(errBackendTimeout, fmt.Errorf("handler %s timed out after %s", handler.name, handlerTimeout(handler)))
+	} else if !classifyFailure(err).expected {
+		metrics.inc("wolfy_handler_errors_total", "handler", handler.name)
+	}
+	logFailure(ctx, err, "running handler "+handler.name)
+	return failureReply(ctx, err)
+}
+
+// Global function for prefacing answers to moderately confident classifications (above the intent threshold, below the
+// confident one) with what we think was asked
+func hedgeAnswer(ctx context.Context, entity wit.MessageEntity, response handlerResponse, reply string) string {
+	// Zero confidence means Wit.ai was bypassed (e.g. the rate limit fallback), not an uncertain reading
+	if entity.Confidence <= 0 || entity.Confidence >= config.confidentThreshold() || isTransientReply(reply) {
+		return reply
+	}
+	metrics.inc("wolfy_hedged_answers_total")
+	traceStep(ctx, "hedging: confidence %.2f is under the %.2f confident threshold", entity.Confidence, config.confidentThreshold())
+	if response.Query != "" {
+		return fmt.Sprintf("I think you're asking about \"%s\" - here's what I found:\n%s", isolateDirection(response.Query), reply)
+	}
+	return "I think I understood that - here's what I found:\n" + reply
+}
+
+// Global function for classifying a handler result as answered, unanswered (the question's fault), timeout, or error
+func handlerOutcome(ctx context.Context, result handlerResult) string {
+	if result.err == nil {
+		return "answered"
+	}
+	if ctx.Err() == context.DeadlineExceeded || classifyFailure(result.err) == errBackendTimeout {
+		return "timeout"
+	}
+	if classifyFailure(result.err).expected {
+		return "unanswered"
+	}
+	return "error"
+}
+
+// Handler replying to greetings
+func handleGreeting(ctx context.Context, event *slack.MessageEvent, entity wit.MessageEntity) (handlerResponse, error) {
+	return handlerResponse{Text: personalizedMessage(ctx, "greeting", event.User)}, nil
+}
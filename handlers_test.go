@@ -0,0 +1,108 @@
+//////////////////////////////////////////////////
+// Entity Handlers Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing the handler registry's deadlines
+import (
+	"context" // Permits handler deadlines
+	"sync"    // Permits running handlers side by side
+	"testing" // Permits Go testing
+	"time"    // Permits handler timeouts
+
+	wit "github.com/christianrondeau/go-wit" // External Wit.ai API
+	slack "github.com/nlopes/slack"          // External Slack API
+)
+
+// Global function for registering a handler for one test, routed by its own name
+func withTestHandler(t *testing.T, handler *entityHandler) context.Context {
+	registerEntityHandler(handler)
+	t.Cleanup(func() { delete(entityHandlers, handler.name) })
+	table := defaultRouting()
+	table[handler.name] = handler.name
+	return context.WithValue(context.Background(), routingTableKey{}, table)
+}
+
+// Test for resolving a handler's timeout from config overrides, its declaration, and the global default
+func TestHandlerTimeoutResolution(t *testing.T) {
+	withConfig(t, func(c *Config) {
+		c.HandlerTimeout = time.Minute
+		c.HandlerTimeouts = map[string]string{"overridden": "5s", "invalid": "soon"}
+	})
+	cases := []struct {
+		handler *entityHandler
+		want    time.Duration
+	}{
+		{&entityHandler{name: "overridden", timeout: 2 * time.Second}, 5 * time.Second},
+		{&entityHandler{name: "invalid", timeout: 2 * time.Second}, 2 * time.Second},
+		{&entityHandler{name: "declared", timeout: 30 * time.Second}, 30 * time.Second},
+		{&entityHandler{name: "undeclared"}, time.Minute},
+	}
+	for _, c := range cases {
+		if got := handlerTimeout(c.handler); got != c.want {
+			t.Errorf("handlerTimeout(%s) = %s; want %s", c.handler.name, got, c.want)
+		}
+	}
+}
+
+// Test for cancelling a slow handler at its own deadline while a handler running alongside it, with a longer one,
+// finishes unaffected
+func TestHandlerOwnDeadline(t *testing.T) {
+	cancelledAfter := make(chan time.Duration, 1)
+	slowCtx := withTestHandler(t, &entityHandler{
+		name:    "test_slow",
+		timeout: 50 * time.Millisecond,
+		handle: func(ctx context.Context, event *slack.MessageEvent, entity wit.MessageEntity) (handlerResponse, error) {
+			started := time.Now()
+			<-ctx.Done()
+			cancelledAfter <- time.Since(started)
+			return handlerResponse{}, ctx.Err()
+		},
+	})
+	patientCtx := withTestHandler(t, &entityHandler{
+		name:    "test_patient",
+		timeout: 2 * time.Second,
+		handle: func(ctx context.Context, event *slack.MessageEvent, entity wit.MessageEntity) (handlerResponse, error) {
+			select {
+			case <-time.After(200 * time.Millisecond):
+				return handlerResponse{Text: "done"}, nil
+			case <-ctx.Done():
+				return handlerResponse{}, ctx.Err()
+			}
+		},
+	})
+	slowTimeouts := metricValue("wolfy_handler_timeouts_total", "handler", "test_slow")
+	patientTimeouts := metricValue("wolfy_handler_timeouts_total", "handler", "test_patient")
+
+	var wg sync.WaitGroup
+	var slowReply, patientReply string
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		slowReply = runEntityHandler(slowCtx, testMessage("D1", "U1", "1.1", "slow"), "test_slow", wit.MessageEntity{Value: "slow", Confidence: 1})
+	}()
+	go func() {
+		defer wg.Done()
+		patientReply = runEntityHandler(patientCtx, testMessage("D2", "U2", "1.2", "patient"), "test_patient", wit.MessageEntity{Value: "patient", Confidence: 1})
+	}()
+	wg.Wait()
+
+	if elapsed := <-cancelledAfter; elapsed > time.Second {
+		t.Errorf("slow handler cancelled after %s; want about its 50ms deadline", elapsed)
+	}
+	if slowReply == "" || slowReply == "done" {
+		t.Errorf("slow handler reply = %q; want a timeout apology", slowReply)
+	}
+	if patientReply != "done" {
+		t.Errorf("patient handler reply = %q; want %q", patientReply, "done")
+	}
+	if got := metricValue("wolfy_handler_timeouts_total", "handler", "test_slow") - slowTimeouts; got != 1 {
+		t.Errorf("test_slow timeouts counted = %d; want 1", got)
+	}
+	if got := metricValue("wolfy_handler_timeouts_total", "handler", "test_patient") - patientTimeouts; got != 0 {
+		t.Errorf("test_patient timeouts counted = %d; want 0", got)
+	}
+}
@@ -0,0 +1,161 @@
+//////////////////////////////////////////////////
+// Handler Switches Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for turning individual features off without touching the routing
+import (
+	"fmt"     // Permits formatting of admin output
+	"log"     // Permits console logging
+	"sort"    // Permits stable ordering of handler listings
+	"strings" // Permits parsing of admin arguments and config entries
+	"time"    // Permits timestamps on runtime switches
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constants naming the store bucket of handlers switched at runtime with "!handler", and the suffix a config
+// entry needs to switch off a protected handler
+const (
+	handlerSwitchesBucket = "handler_switches"
+	forceDisableSuffix    = ":force"
+)
+
+// Global constant holding the reply shown only to the asker when a switched-off handler would have answered
+const handlerDisabledNotice = "Sorry, that feature is disabled here. :-)"
+
+// Global set of handlers the bot is no use without (the capability list, the Wolfram answerer, admin commands),
+// which are only switched off when forced
+var protectedHandlers = map[string]bool{"capabilities": true, "wolfram_search_query": true, "admin_command": true}
+
+// Global struct holding a handler switched at runtime, by whom and when
+type handlerSwitch struct {
+	Enabled bool
+	By      string
+	At      time.Time
+}
+
+// Global set of switchable built-in command names, filled at init time (the interceptor list reaches this file
+// through the admin commands, so it can't be read while package variables initialize)
+var interceptorNames map[string]bool
+
+// Registering the built-in commands as switchable handlers
+func init() {
+	interceptorNames = map[string]bool{}
+	for _, interceptor := range messageInterceptors {
+		interceptorNames[interceptor.name] = true
+	}
+}
+
+// Global function for parsing a WOLFY_DISABLED_HANDLERS entry into its handler name and whether it was forced
+func parseDisabledHandler(entry string) (string, bool) {
+	name := strings.TrimSuffix(entry, forceDisableSuffix)
+	return name, name != entry
+}
+
+// Global function for listing every switchable handler with its kind, in a stable order
+func switchableHandlers() ([]string, map[string]string) {
+	kinds := map[string]string{}
+	for name := range entityHandlers {
+		kinds[name] = "intent"
+	}
+	for _, local := range localAnswerers {
+		kinds[local.name] = "local answer"
+	}
+	for name := range interceptorNames {
+		kinds[name] = "command"
+	}
+	names := make([]string, 0, len(kinds))
+	for name := range kinds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, kinds
+}
+
+// Global function for checking whether a handler is switched on and why: a runtime "!handler" switch, else
+// WOLFY_DISABLED_HANDLERS, else on (protected handlers listed there without ":force" stay on)
+func handlerEnabledWhy(name string) (bool, string) {
+	var override handlerSwitch
+	if store != nil && store.get(handlerSwitchesBucket, name, &override) {
+		return override.Enabled, "switched by <@" + override.By + ">"
+	}
+	for _, entry := range config.DisabledHandlers {
+		if disabled, forced := parseDisabledHandler(entry); disabled == name && (forced || !protectedHandlers[name]) {
+			return false, "WOLFY_DISABLED_HANDLERS"
+		}
+	}
+	return true, "default"
+}
+
+// Global function for checking whether a handler is switched on
+func handlerEnabled(name string) bool {
+	enabled, _ := handlerEnabledWhy(name)
+	return enabled
+}
+
+// Global function for telling an asker, only them, that the handler their message matched is switched off
+func noteHandlerDisabled(event *slack.MessageEvent, name string) {
+	metrics.inc("wolfy_disabled_handler_hits_total", "handler", name)
+	log.Printf("ROUTING: %s matched %s, which is disabled.", messageEventKey(event), name)
+	postPrivately(event, handlerDisabledNotice)
+}
+
+// Admin command listing the switchable handlers and whether each is on
+func runAdminHandlers(event *slack.MessageEvent, args []string) string {
+	names, kinds := switchableHandlers()
+	lines := []string{"*Handlers:*"}
+	for _, name := range names {
+		enabled, source := handlerEnabledWhy(name)
+		line := fmt.Sprintf("• `%s` (%s): %s (%s)", name, kinds[name], map[bool]string{true: "enabled", false: "disabled"}[enabled], source)
+		if protectedHandlers[name] {
+			line += " - protected"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Admin command switching a handler on or off at runtime, or back to its configured state; switching off a protected
+// handler takes "--force"
+func runAdminHandler(event *slack.MessageEvent, args []string) string {
+	usage := "Usage: `!handler enable|disable|clear <handler> [--force]` (see `!handlers` for the list)"
+	force := len(args) == 3 && args[2] == "--force"
+	if len(args) != 2 && !force {
+		return usage
+	}
+	action, name := strings.ToLower(args[0]), args[1]
+	_, kinds := switchableHandlers()
+	if _, known := kinds[name]; !known {
+		return fmt.Sprintf("There's no handler called `%s`. %s", name, usage)
+	}
+
+	switch action {
+	case "clear":
+		store.delete(handlerSwitchesBucket, name)
+		log.Printf("ADMIN: %s cleared the switch on handler %s.", event.User, name)
+		return fmt.Sprintf("Cleared the switch on `%s`; it's %s as configured.", name, map[bool]string{true: "enabled", false: "disabled"}[handlerEnabled(name)])
+	case "enable", "disable":
+	default:
+		return usage
+	}
+	enabled := action == "enable"
+	if !enabled && protectedHandlers[name] && !force {
+		return fmt.Sprintf(":warning: `%s` is a core handler; switching it off leaves people without it. Run `!handler disable %s --force` if you're sure.", name, name)
+	}
+	if err := store.put(handlerSwitchesBucket, name, handlerSwitch{Enabled: enabled, By: event.User, At: time.Now()}); err != nil {
+		log.Printf("ADMIN ERROR: Unable to persist the switch on handler %s.\nError Details: %v", name, err)
+		return "Sorry, I couldn't save that setting. :-("
+	}
+	log.Printf("ADMIN: %s %sd handler %s.", event.User, action, name)
+	if enabled {
+		return fmt.Sprintf("`%s` is enabled.", name)
+	}
+	if kinds[name] == "command" {
+		return fmt.Sprintf("`%s` is disabled; messages it would have handled are answered as ordinary questions.", name)
+	}
+	return fmt.Sprintf("`%s` is disabled; messages it would have answered get a note that the feature is off here.", name)
+}
@@ -0,0 +1,137 @@
+//////////////////////////////////////////////////
+// Handler Switches Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing individual features switched off by config or at runtime
+import (
+	"strings" // Permits inspection of admin replies
+	"testing" // Permits Go testing
+)
+
+// Global function for clearing any runtime switch on the given handlers once a test is done
+func clearHandlerSwitches(t *testing.T, names ...string) {
+	t.Cleanup(func() {
+		for _, name := range names {
+			store.delete(handlerSwitchesBucket, name)
+		}
+	})
+}
+
+// Test for config switching off ordinary handlers outright and protected ones only when forced, with a runtime
+// switch winning over both
+func TestHandlerEnabledSources(t *testing.T) {
+	withConfig(t, func(c *Config) {
+		c.DisabledHandlers = []string{"greetings", "wolfram_search_query", "capabilities:force"}
+	})
+	clearHandlerSwitches(t, "greetings", "date_math")
+	if err := store.put(handlerSwitchesBucket, "greetings", handlerSwitch{Enabled: true, By: "UADMIN"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.put(handlerSwitchesBucket, "date_math", handlerSwitch{Enabled: false, By: "UADMIN"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name    string
+		enabled bool
+		source  string
+	}{
+		{"greetings", true, "switched by <@UADMIN>"},
+		{"date_math", false, "switched by <@UADMIN>"},
+		{"wolfram_search_query", true, "default"},
+		{"capabilities", false, "WOLFY_DISABLED_HANDLERS"},
+		{"unit_conversion", true, "default"},
+	}
+	for _, c := range cases {
+		if enabled, source := handlerEnabledWhy(c.name); enabled != c.enabled || source != c.source {
+			t.Errorf("handlerEnabledWhy(%q) = %v, %q; want %v, %q", c.name, enabled, source, c.enabled, c.source)
+		}
+	}
+
+	// Dropping the runtime switch so config applies again
+	store.delete(handlerSwitchesBucket, "greetings")
+	if handlerEnabled("greetings") {
+		t.Error("greetings is enabled after its runtime switch was cleared; want WOLFY_DISABLED_HANDLERS to apply")
+	}
+}
+
+// Test for "!handler disable" refusing a protected handler without --force, saving nothing, and "clear" undoing a
+// forced switch
+func TestRunAdminHandlerProtection(t *testing.T) {
+	withConfig(t, func(c *Config) { c.DisabledHandlers = nil })
+	clearHandlerSwitches(t, "wolfram_search_query", "exact_arithmetic")
+	admin := testMessage("CADMIN", "UADMIN", "7100.000001", "!handler disable wolfram_search_query")
+
+	cases := []struct {
+		args    []string
+		want    string
+		enabled bool
+	}{
+		{[]string{"disable", "wolfram_search_query"}, ":warning: `wolfram_search_query` is a core handler", true},
+		{[]string{"disable", "wolfram_search_query", "--force"}, "`wolfram_search_query` is disabled", false},
+		{[]string{"clear", "wolfram_search_query"}, "Cleared the switch on `wolfram_search_query`; it's enabled", true},
+		{[]string{"disable", "exact_arithmetic"}, "`exact_arithmetic` is disabled", false},
+		{[]string{"enable", "exact_arithmetic"}, "`exact_arithmetic` is enabled.", true},
+		{[]string{"disable", "no_such_handler"}, "There's no handler called `no_such_handler`", true},
+		{[]string{"toggle", "exact_arithmetic"}, "Usage: `!handler", true},
+	}
+	for _, c := range cases {
+		got := runAdminHandler(admin, c.args)
+		if !strings.HasPrefix(got, c.want) {
+			t.Errorf("runAdminHandler(%q) = %q; want it to start %q", c.args, got, c.want)
+		}
+		if name := c.args[1]; handlerEnabled(name) != c.enabled {
+			t.Errorf("after runAdminHandler(%q), handlerEnabled(%q) = %v; want %v", c.args, name, !c.enabled, c.enabled)
+		}
+	}
+	var saved handlerSwitch
+	runAdminHandler(admin, []string{"disable", "wolfram_search_query"})
+	if store.get(handlerSwitchesBucket, "wolfram_search_query", &saved) {
+		t.Errorf("refused switch on a protected handler was saved as %+v", saved)
+	}
+}
+
+// Test for a handler switched off at runtime being checked as each message is dispatched: the asker is told, nothing
+// is answered, and switching it back on answers the next message without a reload
+func TestDispatchTimeDisabledCheck(t *testing.T) {
+	withConfig(t, func(c *Config) { c.DisabledHandlers = nil })
+	clearHandlerSwitches(t, "exact_arithmetic")
+	admin := testMessage("CADMIN", "UADMIN", "7200.000001", "!handler disable exact_arithmetic")
+	countAnswers := func() (answers int, notices int) {
+		for _, call := range fakeSlack.callsTo("chat.postMessage") {
+			if call.values.Get("channel") != "USWITCH" {
+				continue
+			}
+			switch call.values.Get("text") {
+			case "5 * 6 = 30":
+				answers++
+			case handlerDisabledNotice:
+				notices++
+			}
+		}
+		return answers, notices
+	}
+
+	hits := metricValue("wolfy_disabled_handler_hits_total", "handler", "exact_arithmetic")
+	answers, notices := countAnswers()
+	runAdminHandler(admin, []string{"disable", "exact_arithmetic"})
+	handleMSGEvent(testMessage("DSWITCH", "USWITCH", "7200.000002", "what is 5 * 6"))
+	waitFor(t, "the disabled notice", func() bool { _, got := countAnswers(); return got > notices })
+	if gotAnswers, gotNotices := countAnswers(); gotAnswers != answers || gotNotices != notices+1 {
+		t.Errorf("while disabled, answers = %d and notices = %d; want %d and %d", gotAnswers-answers, gotNotices-notices, 0, 1)
+	}
+	if got := metricValue("wolfy_disabled_handler_hits_total", "handler", "exact_arithmetic") - hits; got != 1 {
+		t.Errorf("disabled handler hits = %d; want 1", got)
+	}
+
+	runAdminHandler(admin, []string{"enable", "exact_arithmetic"})
+	handleMSGEvent(testMessage("DSWITCH", "USWITCH", "7200.000003", "what is 5 * 6"))
+	waitFor(t, "the answer once re-enabled", func() bool { got, _ := countAnswers(); return got > answers })
+	if gotAnswers, gotNotices := countAnswers(); gotAnswers != answers+1 || gotNotices != notices+1 {
+		t.Errorf("after re-enabling, answers = %d and notices = %d; want %d and %d", gotAnswers-answers, gotNotices-notices, 1, 1)
+	}
+}
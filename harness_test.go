@@ -0,0 +1,177 @@
+//////////////////////////////////////////////////
+// Test Harness Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for running the bot's handlers against a fake Slack and no external APIs
+import (
+	"bytes"         // Permits building of fake Slack replies
+	"io/ioutil"     // Permits reading of fake Slack request bodies
+	"log"           // Permits silencing of console logging
+	"net/http"      // Permits fake Slack replies
+	"net/url"       // Permits decoding of fake Slack requests
+	"os"            // Permits the temporary data directory
+	"path/filepath" // Permits temporary data file paths
+	"strings"       // Permits matching of Slack API methods
+	"sync"          // Permits concurrency-safe recording of Slack calls
+	"testing"       // Permits Go testing
+	"time"          // Permits waiting for asynchronous posts
+
+	wit "github.com/christianrondeau/go-wit" // External Wit.ai API
+	slack "github.com/nlopes/slack"          // External Slack API
+)
+
+// Global struct holding one Slack Web API call made by the bot under test
+type fakeSlackCall struct {
+	method string
+	values url.Values
+}
+
+// Global struct standing in for Slack's Web API: every method succeeds, and every call is recorded (posts to a held
+// channel wait, unrecorded, until it is released)
+type fakeSlackClient struct {
+	mu    sync.Mutex
+	calls []fakeSlackCall
+	next  int
+	held  map[string]chan struct{}
+}
+
+// Global fake Slack every test talks to
+var fakeSlack = &fakeSlackClient{}
+
+// Method for answering a Slack Web API request with a canned success reply
+func (client *fakeSlackClient) Do(req *http.Request) (*http.Response, error) {
+	method := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+	body, _ := ioutil.ReadAll(req.Body)
+	values, _ := url.ParseQuery(string(body))
+	for key, list := range req.URL.Query() {
+		values[key] = append(values[key], list...)
+	}
+
+	client.mu.Lock()
+	hold := client.held[values.Get("channel")]
+	client.mu.Unlock()
+	if hold != nil && method == "chat.postMessage" {
+		<-hold
+	}
+
+	client.mu.Lock()
+	client.calls = append(client.calls, fakeSlackCall{method: method, values: values})
+	client.next++
+	ts := time.Unix(1500000000, 0).Add(time.Duration(client.next) * time.Second).Format("1504.000000")
+	client.mu.Unlock()
+
+	reply := `{"ok":true}`
+	switch method {
+	case "chat.postMessage", "chat.update", "chat.postEphemeral":
+		reply = `{"ok":true,"channel":"` + values.Get("channel") + `","ts":"` + ts + `"}`
+	case "auth.test":
+		reply = `{"ok":true,"user_id":"UBOT","team_id":"T1"}`
+	case "users.info":
+		reply = `{"ok":true,"user":{"id":"` + values.Get("user") + `","name":"tester","tz":"UTC","locale":"en-US"}}`
+	}
+	return &http.Response{StatusCode: http.StatusOK, Header: http.Header{"Content-Type": {"application/json"}}, Body: ioutil.NopCloser(bytes.NewBufferString(reply)), Request: req}, nil
+}
+
+// Method for listing the recorded calls to one Slack method
+func (client *fakeSlackClient) callsTo(method string) []fakeSlackCall {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	var matching []fakeSlackCall
+	for _, call := range client.calls {
+		if call.method == method {
+			matching = append(matching, call)
+		}
+	}
+	return matching
+}
+
+// Method for holding posts to a channel inside Slack, as a slow or rate-limited post would be, until the returned
+// function releases them
+func (client *fakeSlackClient) holdPosts(channel string) func() {
+	hold := make(chan struct{})
+	client.mu.Lock()
+	if client.held == nil {
+		client.held = map[string]chan struct{}{}
+	}
+	client.held[channel] = hold
+	client.mu.Unlock()
+	return func() {
+		client.mu.Lock()
+		delete(client.held, channel)
+		client.mu.Unlock()
+		close(hold)
+	}
+}
+
+// Method for forgetting every recorded call
+func (client *fakeSlackClient) reset() {
+	client.mu.Lock()
+	client.calls = nil
+	client.mu.Unlock()
+}
+
+// Global function for setting the bot up once for every test: default configuration, a throwaway data file, the fake
+// Slack, and external APIs switched off so nothing leaves the process
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "wolfybot-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	os.Setenv("WOLFY_DATA_FILE", filepath.Join(dir, "wolfy.json"))
+	config = loadConfig()
+	log.SetOutput(ioutil.Discard)
+	loadPersonalityPacks()
+	loadMessageCatalog()
+	loadLanguageCatalogs()
+	loadExternalHandlers()
+	loadRouting()
+	loadTeamBranding()
+	loadIntentSynonyms()
+	if store, err = openStore(config.DataFile); err != nil {
+		log.Fatal(err)
+	}
+	loadFeatureFlags()
+	openAnswerCache()
+	setExternalAPIsDisabled(true)
+	currentCredentials.Store(apiCredentials{slackToken: "xoxb-test", slack: slack.New("xoxb-test", slack.OptionHTTPClient(fakeSlack)), wit: wit.NewClient("")})
+	setBotUserID("UBOT")
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// Global function for replacing the configuration for one test, restoring it afterwards
+func withConfig(t *testing.T, change func(*Config)) {
+	previous := config
+	changed := *config
+	change(&changed)
+	config = &changed
+	t.Cleanup(func() { config = previous })
+}
+
+// Global function for waiting until a condition holds, failing the test if it doesn't within a few seconds
+func waitFor(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// Global function for reading one counter's current value
+func metricValue(name string, labels ...string) int64 {
+	return metrics.snapshot()[metricKey(name, labels...)]
+}
+
+// Global function for building a message event as Slack would deliver it
+func testMessage(channel string, user string, ts string, text string) *slack.MessageEvent {
+	return &slack.MessageEvent{Msg: slack.Msg{Type: "message", Channel: channel, User: user, Timestamp: ts, Text: text}}
+}
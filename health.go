@@ -0,0 +1,134 @@
+//////////////////////////////////////////////////
+// Backend Health Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for tracking backend health and reflecting it in the bot's Slack presence and status
+import (
+	"log"     // Permits console logging
+	"sort"    // Permits stable ordering of degraded backends
+	"strings" // Permits joining of status text
+	"sync"    // Permits concurrency-safe health updates
+	"time"    // Permits debouncing of health transitions
+)
+
+// Global struct holding one backend's recent results and its debounced state
+type backendHealth struct {
+	failures int       // Consecutive failures
+	degraded bool      // Raw state, flipping as soon as the threshold is crossed or a call succeeds
+	changed  time.Time // When the raw state last flipped
+	reported bool      // Debounced state, following the raw state only once it has held for the debounce window
+}
+
+// Global display names for the tracked backends
+var backendNames = map[string]string{"wit": "Wit.ai", "wolfram": "Wolfram"}
+
+// Global registry of backend health
+var (
+	healthMu sync.Mutex
+	backends = map[string]*backendHealth{}
+)
+
+// Global function for recording the outcome of a backend call (failed marks an outage-style failure, not a bad question)
+func noteBackendResult(api string, failed bool) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+
+	backend, ok := backends[api]
+	if !ok {
+		backend = &backendHealth{}
+		backends[api] = backend
+	}
+	if failed {
+		backend.failures++
+	} else {
+		backend.failures = 0
+	}
+	if degraded := backend.failures >= config.HealthFailureThreshold; degraded != backend.degraded {
+		backend.degraded = degraded
+		backend.changed = time.Now()
+		if degraded {
+			openRecovery(api, backend.changed)
+		}
+	}
+}
+
+// Global function for listing the backends that are degraded once transient blips are debounced away
+func degradedBackends(now time.Time) []string {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+
+	var degraded []string
+	for api, backend := range backends {
+		if backend.degraded != backend.reported && now.Sub(backend.changed) >= config.HealthDebounce {
+			backend.reported = backend.degraded
+			if backend.reported {
+				log.Printf("HEALTH: %s is now degraded.", backendNames[api])
+			} else {
+				log.Printf("HEALTH: %s has recovered.", backendNames[api])
+			}
+		}
+		value := int64(0)
+		if backend.reported {
+			value = 1
+			degraded = append(degraded, backendNames[api])
+		}
+		metrics.set(value, "wolfy_backend_degraded", "api", api)
+	}
+	sort.Strings(degraded)
+	return degraded
+}
+
+// Global function for describing the bot's health as Slack status text (empty when everything is fine)
+func healthStatusText(now time.Time) string {
+	if !externalAPIsAllowed() {
+		return "Limited to local answers"
+	}
+	if degraded := degradedBackends(now); len(degraded) > 0 {
+		return strings.Join(degraded, " and ") + " degraded"
+	}
+	return ""
+}
+
+// Global function for publishing a health status to Slack's presence and profile status, reporting whether it took
+func publishHealthStatus(status string) bool {
+	presence := "auto"
+	var err error
+	if status == "" {
+		err = slackAPI().UnsetUserCustomStatus()
+	} else {
+		presence = "away"
+		err = slackAPI().SetUserCustomStatus(status, strings.TrimSpace(packEmoji(flagScope{}, "degraded")))
+	}
+	if err != nil {
+		log.Printf("HEALTH ERROR: Unable to update the bot's status (the token needs users.profile:write).\nError Details: %v", err)
+		return false
+	}
+	if err := slackAPI().SetUserPresence(presence); err != nil {
+		log.Printf("HEALTH ERROR: Unable to update the bot's presence.\nError Details: %v", err)
+	}
+	metrics.inc("wolfy_health_status_updates_total")
+	return true
+}
+
+// Global function for starting the loop that mirrors health into the bot's presence/status, writing at most once per interval
+func startHealthStatus() {
+	if !config.HealthStatus || config.HealthStatusInterval <= 0 {
+		return
+	}
+	go func() {
+		var publishedStatus *string
+		for now := range time.Tick(config.HealthStatusInterval) {
+			status := healthStatusText(now)
+			if publishedStatus != nil && *publishedStatus == status {
+				continue
+			}
+			if publishHealthStatus(status) {
+				publishedStatus = &status
+			}
+		}
+	}()
+}
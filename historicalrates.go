@@ -0,0 +1,130 @@
+//////////////////////////////////////////////////
+// Historical Exchange Rates Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for converting currency at the rate on a past date ("100 USD to EUR on 2020-01-01")
+import (
+	"context"       // Permits honoring of the handler deadline
+	"encoding/json" // Permits decoding of the rates response
+	"fmt"           // Permits formatting of conversions
+	"log"           // Permits console logging
+	"net/http"      // Permits calling the rates endpoint
+	"net/url"       // Permits building of the rates query string
+	"regexp"        // Permits recognition of dated conversions
+	"strconv"       // Permits parsing of the amount
+	"strings"       // Permits string normalization
+	"time"          // Permits date validation
+)
+
+// Global pattern recognizing "convert 100 USD to EUR on 2020-01-01" and "what was 50 gbp in usd on March 3, 2015?"
+var historicalConversionPattern = regexp.MustCompile(`(?i)^\s*(?:convert\s+|what\s+(?:was|were|is)\s+)?(\d[\d,]*(?:\.\d+)?)\s*([a-z]{3})\s+(?:to|in|into)\s+([a-z]{3})\s+(?:on|as\s+of)\s+(.+?)\s*[?.!]*\s*$`)
+
+// Global struct holding a dated conversion: the amount, the ISO currency codes, and the date (UTC midnight)
+type historicalConversion struct {
+	amount float64
+	from   string
+	to     string
+	date   time.Time
+}
+
+// Global function for recognizing a dated conversion, resolving dates without a year to their latest past occurrence
+func parseHistoricalConversion(query string, today time.Time) (historicalConversion, bool) {
+	if config.HistoricalRatesURL == "" {
+		return historicalConversion{}, false
+	}
+	match := historicalConversionPattern.FindStringSubmatch(query)
+	if match == nil {
+		return historicalConversion{}, false
+	}
+	amount, err := strconv.ParseFloat(strings.Replace(match[1], ",", "", -1), 64)
+	if err != nil {
+		return historicalConversion{}, false
+	}
+	target, ok := parseDateTarget(match[4], today)
+	if !ok {
+		return historicalConversion{}, false
+	}
+	date, ok := resolveDateTarget(target, today, true)
+	if !ok {
+		return historicalConversion{}, false
+	}
+	return historicalConversion{amount: amount, from: strings.ToUpper(match[2]), to: strings.ToUpper(match[3]), date: date}, true
+}
+
+// Method for rewording a dated conversion as the undated one Wolfram answers at today's rate
+func (conversion historicalConversion) currentQuery() string {
+	return fmt.Sprintf("%s %s to %s", strconv.FormatFloat(conversion.amount, 'f', -1, 64), conversion.from, conversion.to)
+}
+
+// Global function for fetching a conversion at a past date's rate, returning the converted amount and the date of the rate
+// used (the last published before the date, for weekends and holidays)
+func fetchHistoricalRate(ctx context.Context, conversion historicalConversion) (float64, time.Time, error) {
+	if !externalAPIsAllowed() {
+		return 0, time.Time{}, errExternalAPIsDisabled
+	}
+	params := url.Values{
+		"amount": {strconv.FormatFloat(conversion.amount, 'f', -1, 64)},
+		"from":   {conversion.from},
+		"to":     {conversion.to},
+	}
+	req, err := http.NewRequest("GET", strings.TrimRight(config.HistoricalRatesURL, "/")+"/"+conversion.date.Format("2006-01-02")+"?"+params.Encode(), nil)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	res, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return 0, time.Time{}, fmt.Errorf("rates endpoint returned %s", res.Status)
+	}
+
+	var body struct {
+		Date  string             `json:"date"`
+		Rates map[string]float64 `json:"rates"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return 0, time.Time{}, err
+	}
+	converted, ok := body.Rates[conversion.to]
+	if !ok {
+		return 0, time.Time{}, fmt.Errorf("no %s rate for %s on %s", conversion.to, conversion.from, conversion.date.Format("2006-01-02"))
+	}
+	rateDate, err := time.Parse("2006-01-02", body.Date)
+	if err != nil {
+		rateDate = conversion.date
+	}
+	return converted, rateDate, nil
+}
+
+// Global function for answering a dated conversion, reporting false (with a note for the current-rate answer) when the
+// historical rate can't be had. Today's date is answered at today's rate without a note.
+func answerHistoricalConversion(ctx context.Context, conversion historicalConversion, today time.Time) (string, string, bool) {
+	day := conversion.date.Format("January 2, 2006")
+	if conversion.date.After(today) {
+		metrics.inc("wolfy_historical_rates_total", "result", "future")
+		return fmt.Sprintf("I can't convert at the rate on %s yet; that's in the future.", day), "", true
+	}
+	if !conversion.date.Before(today) {
+		return "", "", false
+	}
+
+	converted, rateDate, err := fetchHistoricalRate(ctx, conversion)
+	if err != nil {
+		metrics.inc("wolfy_historical_rates_total", "result", "unavailable")
+		log.Printf("RATES ERROR: Unable to fetch the %s/%s rate for %s; answering at today's rate.\nError Details: %v", conversion.from, conversion.to, day, err)
+		return "", fmt.Sprintf("I couldn't get the rate for %s, so this uses today's rate", day), false
+	}
+	metrics.inc("wolfy_historical_rates_total", "result", "answered")
+	traceStep(ctx, "final source: historical rates (rate of %s)", rateDate.Format("2006-01-02"))
+	answer := fmt.Sprintf("%s %s = %s %s on %s", strconv.FormatFloat(conversion.amount, 'f', -1, 64), conversion.from, strconv.FormatFloat(converted, 'f', 2, 64), conversion.to, day)
+	if !rateDate.Equal(conversion.date) {
+		answer += fmt.Sprintf(" _(using the rate published on %s, the last before then)_", rateDate.Format("January 2, 2006"))
+	}
+	return answer, "", true
+}
@@ -0,0 +1,139 @@
+//////////////////////////////////////////////////
+// Answer History Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for recording and exporting each user's question history
+import (
+	"fmt"     // Permits string formatting of export lines
+	"log"     // Permits console logging
+	"regexp"  // Permits matching of export phrasings
+	"strings" // Permits building of the export file
+	"sync"    // Permits serialized read-modify-write of history records
+	"time"    // Permits timestamp formatting
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constant naming the store bucket holding per-user history
+const historyBucket = "history"
+
+// Global pattern recognizing "export my history" style requests
+var historyExportPattern = regexp.MustCompile(`(?i)^\s*(export|download|send\s+me)\s+(my\s+)?(question\s+|query\s+|answer\s+)?history\s*[.!?]*\s*$`)
+
+// Global mutex serializing appends to a user's history record
+var historyMu sync.Mutex
+
+// Registering history export among the built-in capabilities
+func init() {
+	registerCapability("history_export", "Say \"export my history\" to get a file of the questions I've recorded from you.", func() bool { return config.HistoryLimit > 0 })
+}
+
+// Global function for appending an interaction to the user's persisted history, keeping only the newest entries
+func recordHistory(user string, entry interaction) {
+	if config.HistoryLimit <= 0 || store == nil {
+		return
+	}
+
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	var entries []interaction
+	store.get(historyBucket, user, &entries)
+	entries = append(entries, entry)
+	if len(entries) > config.HistoryLimit {
+		entries = entries[len(entries)-config.HistoryLimit:]
+	}
+	if err := store.put(historyBucket, user, entries); err != nil {
+		log.Printf("HISTORY ERROR: Unable to record history for %s.\nError Details: %v", user, err)
+	}
+}
+
+// Global function for loading a user's persisted history, oldest first
+func userHistory(user string) []interaction {
+	var entries []interaction
+	if store != nil {
+		store.get(historyBucket, user, &entries)
+	}
+	return entries
+}
+
+// Global function for formatting history as a text file, dropping the oldest entries to stay under maxBytes
+func formatHistoryExport(entries []interaction, maxBytes int) string {
+	blocks := make([]string, 0, len(entries))
+	size := 0
+	omitted := 0
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		lines := []string{
+			fmt.Sprintf("[%s]", entry.At.UTC().Format(time.RFC1123)),
+			"Q: " + entry.Text,
+		}
+		if entry.Query != "" && entry.Query != entry.Text {
+			lines = append(lines, "Searched for: "+entry.Query)
+		}
+		lines = append(lines, "A: "+entry.Answer, "")
+		block := strings.Join(lines, "\n")
+
+		if maxBytes > 0 && size+len(block) > maxBytes {
+			omitted = i + 1
+			break
+		}
+		size += len(block)
+		blocks = append(blocks, block)
+	}
+
+	// Restoring chronological order after walking newest first
+	for i, j := 0, len(blocks)-1; i < j; i, j = i+1, j-1 {
+		blocks[i], blocks[j] = blocks[j], blocks[i]
+	}
+
+	header := fmt.Sprintf("WolfyBot question history (%d entries)\n\n", len(blocks))
+	if omitted > 0 {
+		header += fmt.Sprintf("(%d older entries were left out to keep this file small.)\n\n", omitted)
+	}
+	return header + strings.Join(blocks, "\n")
+}
+
+// Global function for uploading a user's history as a text file, reporting whether the message was consumed
+func handleHistoryExport(event *slack.MessageEvent) bool {
+	if !historyExportPattern.MatchString(event.Msg.Text) {
+		return false
+	}
+	metrics.inc("wolfy_intents_total", "intent", "history_export")
+
+	if config.HistoryLimit <= 0 {
+		postText(event.User, "I don't keep a history of your questions, so there's nothing to export. :lock:")
+		return true
+	}
+	entries := userHistory(event.User)
+	if len(entries) == 0 {
+		postText(event.User, "I haven't recorded any questions from you yet. Ask me something first! :-)")
+		return true
+	}
+
+	// Always uploading into the user's DM so history never lands in a shared channel
+	_, _, channelID, err := slackAPI().OpenIMChannel(event.User)
+	if err != nil {
+		log.Printf("HISTORY ERROR: Unable to open DM with %s.\nError Details: %v", event.User, err)
+		postText(event.User, "Sorry, I couldn't prepare your history export right now. :-(")
+		return true
+	}
+
+	_, err = slackAPI().UploadFile(slack.FileUploadParameters{
+		Content:        formatHistoryExport(entries, config.HistoryExportMaxBytes),
+		Filetype:       "text",
+		Filename:       "wolfybot-history.txt",
+		Title:          "Your WolfyBot history",
+		InitialComment: fmt.Sprintf("Here's everything I've recorded from you (I keep your latest %d questions).", config.HistoryLimit),
+		Channels:       []string{channelID},
+	})
+	if err != nil {
+		log.Printf("HISTORY ERROR: Unable to upload history export for %s.\nError Details: %v", event.User, err)
+		postText(event.User, "Sorry, I couldn't upload your history export right now. :-(")
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+//////////////////////////////////////////////////
+// Outbound HTTP Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for the shared outbound HTTP client
+import (
+	"crypto/tls"  // Permits custom TLS settings
+	"crypto/x509" // Permits loading of custom CA bundles
+	"errors"      // Permits construction of configuration errors
+	"io/ioutil"   // Permits reading of CA bundle files
+	"net/http"    // Permits construction of HTTP clients
+	"net/url"     // Permits parsing of proxy URLs
+
+	websocket "github.com/gorilla/websocket" // External WebSocket library used by the Slack RTM
+)
+
+// Global shared HTTP client used for every outbound API call we control
+var httpClient = http.DefaultClient
+
+// Global function for building the shared HTTP client from the proxy, TLS, and timeout settings
+func configureHTTPClient() (*websocket.Dialer, error) {
+	// Honoring HTTP(S)_PROXY/NO_PROXY from the environment unless an explicit proxy is configured
+	proxy := http.ProxyFromEnvironment
+	if config.HTTPProxy != "" {
+		proxyURL, err := url.Parse(config.HTTPProxy)
+		if err != nil {
+			return nil, err
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: config.TLSInsecureSkipVerify}
+	if config.TLSCAFile != "" {
+		pem, err := ioutil.ReadFile(config.TLSCAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no certificates found in " + config.TLSCAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	transport.TLSClientConfig = tlsConfig
+
+	// Replacing the default transport too, since go-wit and go-wolfram only ever use the defaults
+	http.DefaultTransport = transport
+	httpClient = &http.Client{Transport: transport, Timeout: config.HTTPTimeout}
+	http.DefaultClient.Timeout = config.HTTPTimeout
+
+	return &websocket.Dialer{Proxy: proxy, TLSClientConfig: tlsConfig, HandshakeTimeout: config.HTTPTimeout}, nil
+}
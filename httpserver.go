@@ -0,0 +1,236 @@
+//////////////////////////////////////////////////
+// HTTP Server Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for the middleware every HTTP listener shares: access logging, authentication, rate limiting, body
+// limits, timeouts, and JSON errors
+import (
+	"bytes"         // Permits restoring a request body read for its signature
+	"crypto/hmac"   // Permits Slack request signature checks
+	"crypto/sha256" // Permits Slack request signature checks
+	"crypto/subtle" // Permits constant-time token comparison
+	"encoding/hex"  // Permits decoding of Slack request signatures
+	"encoding/json" // Permits encoding of error replies
+	"fmt"           // Permits formatting of auth failures
+	"io/ioutil"     // Permits reading of request bodies
+	"log"           // Permits console logging
+	"net"           // Permits parsing of remote addresses
+	"net/http"      // Permits serving and wrapping of routes
+	"strconv"       // Permits parsing of Slack request timestamps
+	"strings"       // Permits parsing of the Authorization header
+	"sync"          // Permits concurrency-safe rate limit windows
+	"time"          // Permits latencies, timeouts, and timestamp skew
+)
+
+// Global constants holding how far a Slack request's timestamp may be from now before it is refused as a replay, and
+// how long an accepted delivery is remembered so Slack's retries of it (sent over the next few minutes when a response
+// is slow) are acknowledged without being handled again
+const (
+	slackSignatureMaxSkew = 5 * time.Minute
+	slackDeliveryWindow   = 10 * time.Minute
+)
+
+// Global window of Slack deliveries already accepted, by route and the delivery's unique ID
+var slackDeliveries = newBoundedCache("slack_deliveries", func() int { return config.DedupCacheSize }, func(interface{}) time.Duration { return slackDeliveryWindow })
+
+// Global bucket upper bounds (in seconds) of the HTTP request latency histogram
+var httpLatencyBounds = []float64{0.005, 0.01, 0.05, 0.1, 0.5, 1, 5}
+
+// Global type naming the authentication a route needs. Its zero value is refused at registration, so every route
+// states its class rather than shipping unauthenticated by accident.
+type routeAuth int
+
+// Global constants listing the auth classes: open to anyone (probes), internal (a bearer token, or loopback callers
+// only when the route has no token), and Slack-facing (the Slack signing secret)
+const (
+	routeAuthUndeclared routeAuth = iota
+	routePublic
+	routeInternal
+	routeSlack
+)
+
+// Global struct holding one HTTP route: its path, auth class, the token guarding it when internal, and its handler
+type httpRoute struct {
+	path   string
+	auth   routeAuth
+	token  string
+	handle http.HandlerFunc
+}
+
+// Global struct recording the status a handler wrote, for the access log
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// Method for recording the written status before passing it on
+func (recorder *statusRecorder) WriteHeader(status int) {
+	recorder.status = status
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+// Global per-IP request counts for the current one-minute rate limit window
+var (
+	httpRateWindowStart time.Time
+	httpRateCounts      = map[string]int{}
+	httpRateMu          sync.Mutex
+)
+
+// Global function for registering a route on a mux behind the shared middleware, refusing routes with no auth class
+func registerRoute(mux *http.ServeMux, route httpRoute) {
+	if route.auth == routeAuthUndeclared {
+		panic(fmt.Sprintf("HTTP route %s registered without an auth class", route.path))
+	}
+	mux.HandleFunc(route.path, func(w http.ResponseWriter, r *http.Request) {
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		started := time.Now()
+		serveRoute(recorder, r, route)
+		latency := time.Since(started)
+
+		metrics.inc("wolfy_http_requests_total", "route", route.path, "status", strconv.Itoa(recorder.status))
+		metrics.observe(latency.Seconds(), httpLatencyBounds, "wolfy_http_request_seconds", "route", route.path)
+		log.Printf("HTTP: %s %s %d %s from %s", r.Method, r.URL.Path, recorder.status, latency.Round(time.Microsecond), remoteIP(r))
+	})
+}
+
+// Global function for running a request through rate limiting, the body limit, and its route's authentication before
+// its handler
+func serveRoute(w http.ResponseWriter, r *http.Request, route httpRoute) {
+	if !allowHTTPRequest(remoteIP(r)) {
+		metrics.inc("wolfy_http_rate_limited_total", "route", route.path)
+		w.Header().Set("Retry-After", "60")
+		writeHTTPError(w, http.StatusTooManyRequests, "rate_limited")
+		return
+	}
+	if r.ContentLength > int64(config.HTTPMaxBodyBytes) {
+		writeHTTPError(w, http.StatusRequestEntityTooLarge, "body_too_large")
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, int64(config.HTTPMaxBodyBytes))
+
+	switch route.auth {
+	case routeInternal:
+		if !internalAuthorized(r, route.token) {
+			writeHTTPError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+	case routeSlack:
+		// Reading the whole body for its signature, then handing the handler a fresh copy
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeHTTPError(w, http.StatusRequestEntityTooLarge, "body_too_large")
+			return
+		}
+		if err := verifySlackSignature(r.Header, body, config.SlackSigningSecret, time.Now()); err != nil {
+			log.Printf("HTTP ERROR: Refused a Slack request to %s from %s.\nError Details: %v", route.path, remoteIP(r), err)
+			writeHTTPError(w, http.StatusUnauthorized, "invalid_signature")
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	route.handle(w, r)
+}
+
+// Global function for writing a consistent JSON error reply
+func writeHTTPError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{"ok": false, "error": code})
+}
+
+// Global function for returning a request's remote IP (the connecting peer; forwarding headers aren't trusted)
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// Global function for counting a request against its IP's per-minute limit, reporting whether it is allowed
+func allowHTTPRequest(ip string) bool {
+	if config.HTTPRateLimit <= 0 {
+		return true
+	}
+	httpRateMu.Lock()
+	defer httpRateMu.Unlock()
+	if now := time.Now(); now.Sub(httpRateWindowStart) >= time.Minute {
+		httpRateWindowStart, httpRateCounts = now, map[string]int{}
+	}
+	httpRateCounts[ip]++
+	return httpRateCounts[ip] <= config.HTTPRateLimit
+}
+
+// Global function for checking an internal route's token from a Bearer header or a "token" query parameter, allowing
+// only loopback callers when the route has no token
+func internalAuthorized(r *http.Request, expected string) bool {
+	if expected == "" {
+		ip := net.ParseIP(remoteIP(r))
+		return ip != nil && ip.IsLoopback()
+	}
+	token := r.URL.Query().Get("token")
+	if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		token = strings.TrimPrefix(header, "Bearer ")
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
+
+// Global function for accepting a Slack delivery once, reporting false for a retry (X-Slack-Retry-Num) or replay of
+// one already accepted, which the caller acknowledges without handling. Deliveries without an ID are always accepted.
+func acceptSlackDelivery(r *http.Request, route string, id string) bool {
+	if id == "" || slackDeliveries.add(route+":"+id, time.Now()) {
+		return true
+	}
+	retry := r.Header.Get("X-Slack-Retry-Num")
+	metrics.inc("wolfy_duplicate_deliveries_total", "route", route)
+	log.Printf("HTTP: Acknowledged a repeat of delivery %s to %s without handling it again (retry %q, reason %q).", id, route, retry, r.Header.Get("X-Slack-Retry-Reason"))
+	return false
+}
+
+// Global function for checking a Slack request's v0 signature over its timestamp and body, refusing timestamps more than
+// five minutes from now so captured requests can't be replayed
+func verifySlackSignature(header http.Header, body []byte, secret string, now time.Time) error {
+	if secret == "" {
+		return fmt.Errorf("no signing secret is configured")
+	}
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("missing or malformed timestamp %q", timestamp)
+	}
+	if skew := now.Sub(time.Unix(seconds, 0)); skew > slackSignatureMaxSkew || skew < -slackSignatureMaxSkew {
+		return fmt.Errorf("timestamp is %s from now, over the %s allowed", skew, slackSignatureMaxSkew)
+	}
+
+	signature := header.Get("X-Slack-Signature")
+	if !strings.HasPrefix(signature, "v0=") {
+		return fmt.Errorf("missing or unversioned signature")
+	}
+	given, err := hex.DecodeString(strings.TrimPrefix(signature, "v0="))
+	if err != nil {
+		return fmt.Errorf("malformed signature")
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	if !hmac.Equal(given, mac.Sum(nil)) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+// Global function for building a listener's server with the configured timeouts
+func newHTTPServer(addr string, mux *http.ServeMux) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: config.HTTPServerTimeout,
+		ReadTimeout:       config.HTTPServerTimeout,
+		WriteTimeout:      config.HTTPServerTimeout,
+		IdleTimeout:       2 * config.HTTPServerTimeout,
+	}
+}
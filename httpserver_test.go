@@ -0,0 +1,237 @@
+//////////////////////////////////////////////////
+// HTTP Server Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing the Slack-facing HTTP routes behind the shared middleware
+import (
+	"crypto/hmac"       // Permits signing of fake Slack requests
+	"crypto/sha256"     // Permits signing of fake Slack requests
+	"encoding/hex"      // Permits encoding of request signatures
+	"encoding/json"     // Permits encoding of interaction payloads
+	"io/ioutil"         // Permits reading of posted responses
+	"net/http"          // Permits building of requests
+	"net/http/httptest" // Permits recording of responses
+	"net/url"           // Permits encoding of form bodies
+	"strconv"           // Permits formatting of request timestamps
+	"strings"           // Permits form bodies and inspection of responses
+	"sync"              // Permits concurrency-safe response capture
+	"sync/atomic"       // Permits counting of handler runs
+	"testing"           // Permits Go testing
+	"time"              // Permits request timestamps
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constant holding the signing secret fake Slack requests are signed with
+const testSigningSecret = "test-signing-secret"
+
+// Global function for serving the Slack-facing routes behind the shared middleware, signed with the test secret
+func slackTestMux(t *testing.T) *http.ServeMux {
+	withConfig(t, func(c *Config) {
+		c.SlackSigningSecret = testSigningSecret
+		c.HTTPRateLimit = 0
+	})
+	mux := http.NewServeMux()
+	registerRoute(mux, httpRoute{path: interactivityPath, auth: routeSlack, handle: serveInteractivity})
+	registerRoute(mux, httpRoute{path: slashCommandPath, auth: routeSlack, handle: serveSlashCommand})
+	return mux
+}
+
+// Global function for computing the v0 signature Slack sends for a body at a timestamp
+func slackSignature(secret string, timestamp string, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":" + body))
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Global function for building a form request to a Slack-facing route, signed as Slack signs it at the given time,
+// marked as Slack's given retry of it (0 for the first delivery)
+func signedSlackRequest(path string, form url.Values, at time.Time, retry int) *http.Request {
+	body := form.Encode()
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", slackSignature(testSigningSecret, timestamp, body))
+	if retry > 0 {
+		req.Header.Set("X-Slack-Retry-Num", strconv.Itoa(retry))
+		req.Header.Set("X-Slack-Retry-Reason", "http_timeout")
+	}
+	return req
+}
+
+// Global counter keeping delivery IDs made in the same instant distinct
+var deliverySequence int64
+
+// Global function for naming a delivery no earlier test run has used, since accepted deliveries are remembered
+func uniqueDeliveryID(prefix string) string {
+	return prefix + "-" + strconv.FormatInt(atomic.AddInt64(&deliverySequence, 1), 10) + "-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+}
+
+// Test for answering a slash command once when Slack retries its delivery, acknowledging every retry with a 200
+func TestSlashCommandRetriesAnsweredOnce(t *testing.T) {
+	var mu sync.Mutex
+	var responses []string
+	withExternalAPIs(t, func(req *http.Request) (int, string) {
+		body, _ := ioutil.ReadAll(req.Body)
+		mu.Lock()
+		responses = append(responses, string(body))
+		mu.Unlock()
+		return http.StatusOK, "ok"
+	})
+	mux := slackTestMux(t)
+	form := url.Values{
+		"command":      {"/wolfy"},
+		"text":         {"what is 6 * 7"},
+		"user_id":      {"URETRY"},
+		"channel_id":   {"CRETRY"},
+		"response_url": {interactionResponseHost + "commands/retry"},
+		"trigger_id":   {uniqueDeliveryID("trigger-retry")},
+	}
+
+	duplicates := metricValue("wolfy_duplicate_deliveries_total", "route", slashCommandPath)
+	for retry := 0; retry <= 2; retry++ {
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, signedSlackRequest(slashCommandPath, form, time.Now(), retry))
+		if recorder.Code != http.StatusOK {
+			t.Errorf("delivery %d status = %d; want 200", retry, recorder.Code)
+		}
+	}
+	if got := metricValue("wolfy_duplicate_deliveries_total", "route", slashCommandPath) - duplicates; got != 2 {
+		t.Errorf("duplicate deliveries counted = %d; want 2", got)
+	}
+	waitFor(t, "the slash command's answer", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(responses) > 0
+	})
+	mu.Lock()
+	if len(responses) != 1 || !strings.Contains(responses[0], "6 * 7 = 42") {
+		t.Errorf("slash command responses = %q; want exactly one answer of 42", responses)
+	}
+	mu.Unlock()
+}
+
+// Test for running an interactive action's handler once when Slack retries its delivery, while a second click (a new
+// trigger) runs it again
+func TestInteractionRetriesHandledOnce(t *testing.T) {
+	var runs int64
+	registerInteraction("test_retry", func(*slack.InteractionCallback) *interactionReply {
+		atomic.AddInt64(&runs, 1)
+		return nil
+	})
+	t.Cleanup(func() { delete(interactionHandlers, "test_retry") })
+	mux := slackTestMux(t)
+
+	deliver := func(trigger string, retry int) {
+		payload, _ := json.Marshal(map[string]interface{}{"type": "interactive_message", "callback_id": "test_retry", "trigger_id": trigger})
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, signedSlackRequest(interactivityPath, url.Values{"payload": {string(payload)}}, time.Now(), retry))
+		if recorder.Code != http.StatusOK {
+			t.Errorf("delivery of %s (retry %d) status = %d; want 200", trigger, retry, recorder.Code)
+		}
+	}
+	first, second := uniqueDeliveryID("trigger-click"), uniqueDeliveryID("trigger-click")
+	for retry := 0; retry <= 3; retry++ {
+		deliver(first, retry)
+	}
+	waitFor(t, "the first click to be handled", func() bool { return atomic.LoadInt64(&runs) >= 1 })
+	deliver(second, 0)
+	waitFor(t, "the second click to be handled", func() bool { return atomic.LoadInt64(&runs) >= 2 })
+	if got := atomic.LoadInt64(&runs); got != 2 {
+		t.Errorf("handler runs = %d; want 2 (one per click)", got)
+	}
+}
+
+// Test for accepting Slack's signature only over the exact body, with the right secret, and within five minutes
+func TestVerifySlackSignature(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := "command=%2Fwolfy&text=distance+to+mars"
+	cases := []struct {
+		name      string
+		signedAt  time.Time
+		timestamp string
+		signature string
+		body      string
+		secret    string
+		ok        bool
+	}{
+		{name: "valid", signedAt: now, ok: true},
+		{name: "just inside the skew", signedAt: now.Add(-slackSignatureMaxSkew + time.Second), ok: true},
+		{name: "slightly ahead of our clock", signedAt: now.Add(slackSignatureMaxSkew - time.Second), ok: true},
+		{name: "too old (replay)", signedAt: now.Add(-slackSignatureMaxSkew - time.Second)},
+		{name: "too far ahead", signedAt: now.Add(slackSignatureMaxSkew + time.Second)},
+		{name: "tampered body", signedAt: now, body: body + "&user_id=UADMIN"},
+		{name: "wrong secret", signedAt: now, signature: slackSignature("another-secret", "1700000000", body)},
+		{name: "unversioned signature", signedAt: now, signature: strings.TrimPrefix(slackSignature(testSigningSecret, "1700000000", body), "v0=")},
+		{name: "malformed signature", signedAt: now, signature: "v0=not-hex"},
+		{name: "malformed timestamp", signedAt: now, timestamp: "yesterday"},
+		{name: "no signing secret configured", signedAt: now, secret: "-"},
+	}
+	for _, c := range cases {
+		timestamp := strconv.FormatInt(c.signedAt.Unix(), 10)
+		signature := slackSignature(testSigningSecret, timestamp, body)
+		if c.timestamp != "" {
+			timestamp = c.timestamp
+		}
+		if c.signature != "" {
+			signature = c.signature
+		}
+		received, secret := body, testSigningSecret
+		if c.body != "" {
+			received = c.body
+		}
+		if c.secret == "-" {
+			secret = ""
+		}
+		header := http.Header{}
+		header.Set("X-Slack-Request-Timestamp", timestamp)
+		header.Set("X-Slack-Signature", signature)
+		if err := verifySlackSignature(header, []byte(received), secret, now); (err == nil) != c.ok {
+			t.Errorf("%s: verifySlackSignature error = %v; want ok %v", c.name, err, c.ok)
+		}
+	}
+}
+
+// Test for refusing bodies over the configured limit, whether declared up front or only found while reading, and
+// unsigned or stale requests, before any handler runs
+func TestSlackRouteRefusals(t *testing.T) {
+	mux := slackTestMux(t)
+	withConfig(t, func(c *Config) { c.HTTPMaxBodyBytes = 256 })
+	huge := url.Values{"text": {strings.Repeat("x", 1024)}, "trigger_id": {uniqueDeliveryID("trigger-huge")}}
+	small := url.Values{"command": {"/wolfy"}, "text": {"what is 6 * 7"}, "trigger_id": {uniqueDeliveryID("trigger-small")}}
+
+	declared := signedSlackRequest(slashCommandPath, huge, time.Now(), 0)
+	streamed := signedSlackRequest(slashCommandPath, huge, time.Now(), 0)
+	streamed.ContentLength = -1
+	unsigned := signedSlackRequest(slashCommandPath, small, time.Now(), 0)
+	unsigned.Header.Del("X-Slack-Signature")
+	stale := signedSlackRequest(slashCommandPath, small, time.Now().Add(-slackSignatureMaxSkew-time.Minute), 0)
+
+	cases := []struct {
+		name string
+		req  *http.Request
+		want int
+		code string
+	}{
+		{"declared oversized body", declared, http.StatusRequestEntityTooLarge, "body_too_large"},
+		{"streamed oversized body", streamed, http.StatusRequestEntityTooLarge, "body_too_large"},
+		{"unsigned request", unsigned, http.StatusUnauthorized, "invalid_signature"},
+		{"stale timestamp", stale, http.StatusUnauthorized, "invalid_signature"},
+	}
+	accepted := metricValue("wolfy_slash_commands_total", "visibility", slashOnlyForAsker) + metricValue("wolfy_slash_commands_total", "visibility", slashInChannel)
+	for _, c := range cases {
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, c.req)
+		if recorder.Code != c.want || !strings.Contains(recorder.Body.String(), `"error":"`+c.code+`"`) {
+			t.Errorf("%s: status %d %s; want %d with %s", c.name, recorder.Code, strings.TrimSpace(recorder.Body.String()), c.want, c.code)
+		}
+	}
+	if got := metricValue("wolfy_slash_commands_total", "visibility", slashOnlyForAsker) + metricValue("wolfy_slash_commands_total", "visibility", slashInChannel) - accepted; got != 0 {
+		t.Errorf("slash commands handled after refusals = %d; want 0", got)
+	}
+}
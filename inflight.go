@@ -0,0 +1,196 @@
+//////////////////////////////////////////////////
+// In-Flight Requests Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for tracking questions being answered so their askers can cancel them
+import (
+	"context"     // Permits cancelling a question's whole pipeline
+	"fmt"         // Permits formatting of replies
+	"regexp"      // Permits matching of the cancel command
+	"sync"        // Permits concurrency-safe tracking
+	"sync/atomic" // Permits settling the race between cancelling and answering
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constants naming a request's states; each request leaves "running" exactly once, by answering or by being cancelled
+const (
+	requestStateRunning int32 = iota
+	requestStateDelivering
+	requestStateCancelled
+)
+
+// Global constant holding the text a cancelled question's placeholder is replaced with
+const cancelledNotice = "Cancelled. :x:"
+
+// Global struct describing one question being answered
+type inFlightRequest struct {
+	id       uint64
+	user     string
+	question string
+	cancel   context.CancelFunc
+	state    int32
+
+	mu                 sync.Mutex
+	placeholderChannel string
+	placeholderTS      string
+}
+
+// Global context key type for the question's in-flight request
+type inFlightKey struct{}
+
+// Global registry of in-flight requests by user and request ID
+var (
+	inFlightMu     sync.Mutex
+	inFlight       = map[string]map[uint64]*inFlightRequest{}
+	inFlightLastID uint64
+)
+
+// Global pattern recognizing "wolfy cancel" and friends
+var cancelPattern = regexp.MustCompile(`(?i)^\s*(?:wolfy\s+)?(?:cancel|stop|never\s*mind)(?:\s+(?:that|it|this))?\s*[.!]*\s*$`)
+
+// Registering the cancel command in the capabilities list
+func init() {
+	registerCapability("cancel", "Say \"wolfy cancel\" (or react :x: to my \"working on it\" message) to stop a question I'm still answering.", nil)
+}
+
+// Global function for tracking a question as in flight, returning a cancellable context carrying it
+func trackInFlight(parent context.Context, event *slack.MessageEvent) (context.Context, *inFlightRequest) {
+	ctx, cancel := context.WithCancel(parent)
+	request := &inFlightRequest{id: atomic.AddUint64(&inFlightLastID, 1), user: event.User, question: event.Msg.Text, cancel: cancel}
+
+	inFlightMu.Lock()
+	if inFlight[event.User] == nil {
+		inFlight[event.User] = map[uint64]*inFlightRequest{}
+	}
+	inFlight[event.User][request.id] = request
+	inFlightMu.Unlock()
+	metrics.set(int64(countInFlight()), "wolfy_in_flight_requests")
+	return context.WithValue(ctx, inFlightKey{}, request), request
+}
+
+// Global function for counting in-flight requests across all users
+func countInFlight() int {
+	inFlightMu.Lock()
+	defer inFlightMu.Unlock()
+
+	count := 0
+	for _, requests := range inFlight {
+		count += len(requests)
+	}
+	return count
+}
+
+// Global function for returning the in-flight request a context carries (nil when untracked)
+func inFlightFrom(ctx context.Context) *inFlightRequest {
+	request, _ := ctx.Value(inFlightKey{}).(*inFlightRequest)
+	return request
+}
+
+// Method for forgetting a finished request and releasing its context
+func (request *inFlightRequest) finish() {
+	inFlightMu.Lock()
+	delete(inFlight[request.user], request.id)
+	if len(inFlight[request.user]) == 0 {
+		delete(inFlight, request.user)
+	}
+	inFlightMu.Unlock()
+	request.cancel()
+	metrics.set(int64(countInFlight()), "wolfy_in_flight_requests")
+}
+
+// Method for noting where a request's placeholder went, so a :x: on it cancels the request
+func (request *inFlightRequest) notePlaceholder(channel string, timestamp string) {
+	request.mu.Lock()
+	request.placeholderChannel, request.placeholderTS = channel, timestamp
+	request.mu.Unlock()
+}
+
+// Method for claiming the right to deliver an answer, reporting false when the request was cancelled first
+func (request *inFlightRequest) claimDelivery() bool {
+	return atomic.CompareAndSwapInt32(&request.state, requestStateRunning, requestStateDelivering)
+}
+
+// Method for cancelling a request unless its answer is already being delivered, reporting whether it was cancelled
+func (request *inFlightRequest) abort() bool {
+	if !atomic.CompareAndSwapInt32(&request.state, requestStateRunning, requestStateCancelled) {
+		return false
+	}
+	request.cancel()
+	metrics.inc("wolfy_cancelled_requests_total")
+	return true
+}
+
+// Global function for checking whether a context's request was cancelled by its asker (rather than timing out)
+func requestCancelled(ctx context.Context) bool {
+	request := inFlightFrom(ctx)
+	return request != nil && atomic.LoadInt32(&request.state) == requestStateCancelled
+}
+
+// Global function for claiming delivery for a context's request (untracked contexts always may deliver)
+func claimDelivery(ctx context.Context) bool {
+	request := inFlightFrom(ctx)
+	return request == nil || request.claimDelivery()
+}
+
+// Global function for listing a user's in-flight requests, optionally only the one behind a placeholder
+func inFlightRequests(user string, placeholderChannel string, placeholderTS string) []*inFlightRequest {
+	inFlightMu.Lock()
+	defer inFlightMu.Unlock()
+
+	var requests []*inFlightRequest
+	for _, request := range inFlight[user] {
+		request.mu.Lock()
+		matches := placeholderTS == "" || (request.placeholderChannel == placeholderChannel && request.placeholderTS == placeholderTS)
+		request.mu.Unlock()
+		if matches {
+			requests = append(requests, request)
+		}
+	}
+	return requests
+}
+
+// Global function for handling "wolfy cancel", reporting whether the message was consumed
+func handleCancel(event *slack.MessageEvent) bool {
+	if !cancelPattern.MatchString(event.Msg.Text) {
+		return false
+	}
+	metrics.inc("wolfy_intents_total", "intent", "cancel")
+
+	requests := inFlightRequests(event.User, "", "")
+	if len(requests) == 0 {
+		postText(answerDestination(event), "There's nothing of yours in progress to cancel.")
+		return true
+	}
+	cancelled := 0
+	question := ""
+	for _, request := range requests {
+		if request.abort() {
+			cancelled++
+			question = request.question
+		}
+	}
+	switch {
+	case cancelled == 0:
+		postText(answerDestination(event), "Too late - that answer was already on its way.")
+	case cancelled == 1:
+		postText(answerDestination(event), fmt.Sprintf("Cancelled \"%s\".", truncateGraphemes(question, 80)))
+	default:
+		postText(answerDestination(event), fmt.Sprintf("Cancelled %d questions.", cancelled))
+	}
+	return true
+}
+
+// Global function for cancelling the request behind a placeholder its asker reacted to with :x:
+func handleCancelReaction(event *slack.ReactionAddedEvent) {
+	if event.Reaction != "x" || event.Item.Type != "message" || event.User == currentBotUserID() {
+		return
+	}
+	for _, request := range inFlightRequests(event.User, event.Item.Channel, event.Item.Timestamp) {
+		request.abort()
+	}
+}
@@ -0,0 +1,69 @@
+//////////////////////////////////////////////////
+// Inline Units Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for honoring "in fahrenheit" style unit requests for a single question
+import (
+	"regexp"  // Permits matching of trailing unit phrases and answer quantities
+	"strconv" // Permits parsing of answer quantities
+	"strings" // Permits string normalization
+
+	wolfram "github.com/Krognol/go-wolfram" // External Wolfram API
+)
+
+// Global patterns recognizing a trailing "in <unit>" phrase and the first quantity in an answer ("about 238900 miles")
+var (
+	inlineUnitPattern     = regexp.MustCompile(`(?i)\s(?:in|into|to)\s+((?:degrees?\s+|fl\s+|fluid\s+)?[a-z°º/]+(?:\s+per\s+(?:second|hour))?)\s*[?.!]*\s*$`)
+	answerQuantityPattern = regexp.MustCompile(`(?i)(-?\d+(?:,\d{3})*(?:\.\d+)?)\s*((?:°|º|degrees?\s+)?[a-z]+(?:/[a-z]+|\s+per\s+(?:second|hour))?)`)
+)
+
+// Global set of unit symbols asking for Wolfram's imperial system (anything else asks for metric, except knots)
+var imperialUnitSymbols = map[string]bool{
+	"°F": true, "in": true, "ft": true, "yd": true, "mi": true,
+	"oz": true, "lb": true, "st": true, "fl oz": true, "cup": true, "gal": true, "mph": true,
+}
+
+// Global function for finding the unit a question asks for inline ("boiling point of water in fahrenheit"), leaving
+// phrases naming anything else ("population growth in France") alone
+func inlineUnitOverride(query string) (conversionUnit, bool) {
+	match := inlineUnitPattern.FindStringSubmatch(query)
+	if match == nil {
+		return conversionUnit{}, false
+	}
+	return lookupConversionUnit(match[1])
+}
+
+// Global function for choosing the Wolfram unit system for a question, preferring an inline unit over the asker's units
+func inlineUnitSystem(unit conversionUnit, fallback wolfram.Unit) wolfram.Unit {
+	switch {
+	case imperialUnitSymbols[unit.symbol]:
+		return wolfram.Imperial
+	case unit.symbol == "kn":
+		return fallback
+	}
+	return wolfram.Metric
+}
+
+// Global function for converting an answer's leading quantity to the requested unit when Wolfram answered in another
+// unit of the same dimension, leaving the answer untouched otherwise
+func convertAnswerToUnit(answer string, unit conversionUnit) string {
+	location := answerQuantityPattern.FindStringSubmatchIndex(answer)
+	if location == nil {
+		return answer
+	}
+	from, ok := lookupConversionUnit(answer[location[4]:location[5]])
+	if !ok || from.dimension != unit.dimension || from.symbol == unit.symbol {
+		return answer
+	}
+	value, err := strconv.ParseFloat(strings.Replace(answer[location[2]:location[3]], ",", "", -1), 64)
+	if err != nil {
+		return answer
+	}
+
+	metrics.inc("wolfy_inline_unit_conversions_total")
+	return answer[:location[0]] + formatQuantity(convertUnits(value, from, unit), unit) + answer[location[1]:]
+}
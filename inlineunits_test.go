@@ -0,0 +1,97 @@
+//////////////////////////////////////////////////
+// Inline Units Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing "in fahrenheit" style unit requests against "in France" style phrases
+import (
+	"testing" // Permits Go testing
+
+	wolfram "github.com/Krognol/go-wolfram" // External Wolfram API
+)
+
+// Test for a trailing unit phrase being taken as the unit the question asks for, and one naming a place, a year, or
+// anything else not being taken as a unit at all
+func TestInlineUnitOverride(t *testing.T) {
+	cases := []struct {
+		query  string
+		symbol string
+	}{
+		{"boiling point of water in fahrenheit", "°F"},
+		{"boiling point of water in degrees celsius?", "°C"},
+		{"average temperature on mars in Kelvin.", "K"},
+		{"distance to the moon in miles", "mi"},
+		{"height of everest in feet", "ft"},
+		{"speed of sound into km/h", "km/h"},
+		{"speed of light in meters per second", "m/s"},
+		{"volume of a teaspoon in fluid ounces", "fl oz"},
+		{"population growth in France", ""},
+		{"population growth in France in 2010", ""},
+		{"gdp of japan in 1990", ""},
+		{"weather in Nice", ""},
+		{"who won the world cup in Brazil", ""},
+		{"flights from London to Paris", ""},
+		{"distance to mars", ""},
+		{"how many feet in a mile", ""},
+		{"in fahrenheit", ""},
+	}
+	for _, c := range cases {
+		unit, ok := inlineUnitOverride(c.query)
+		if ok != (c.symbol != "") || unit.symbol != c.symbol {
+			t.Errorf("inlineUnitOverride(%q) = %q, %v; want %q, %v", c.query, unit.symbol, ok, c.symbol, c.symbol != "")
+		}
+	}
+}
+
+// Test for an inline unit choosing Wolfram's unit system, knots keeping the asker's own
+func TestInlineUnitSystem(t *testing.T) {
+	cases := []struct {
+		query    string
+		fallback wolfram.Unit
+		want     wolfram.Unit
+	}{
+		{"boiling point of water in fahrenheit", wolfram.Metric, wolfram.Imperial},
+		{"distance to the moon in miles", wolfram.Metric, wolfram.Imperial},
+		{"height of everest in meters", wolfram.Imperial, wolfram.Metric},
+		{"cruising speed of a 747 in knots", wolfram.Imperial, wolfram.Imperial},
+		{"cruising speed of a 747 in knots", wolfram.Metric, wolfram.Metric},
+	}
+	for _, c := range cases {
+		unit, ok := inlineUnitOverride(c.query)
+		if !ok {
+			t.Errorf("inlineUnitOverride(%q) found no unit", c.query)
+			continue
+		}
+		if got := inlineUnitSystem(unit, c.fallback); got != c.want {
+			t.Errorf("inlineUnitSystem(%q, %v) = %v; want %v", unit.symbol, c.fallback, got, c.want)
+		}
+	}
+}
+
+// Test for converting an answer's leading quantity to the unit asked for, leaving answers in that unit already, in
+// another dimension, or without a quantity alone
+func TestConvertAnswerToUnit(t *testing.T) {
+	cases := []struct {
+		answer string
+		unit   string
+		want   string
+	}{
+		{"about 384,400 km (average)", "miles", "about 238855 mi (average)"},
+		{"100 °C (at sea level)", "fahrenheit", "212 °F (at sea level)"},
+		{"238855 mi", "miles", "238855 mi"},
+		{"8848 m", "fahrenheit", "8848 m"},
+		{"France", "miles", "France"},
+	}
+	for _, c := range cases {
+		unit, ok := lookupConversionUnit(c.unit)
+		if !ok {
+			t.Fatalf("lookupConversionUnit(%q) found no unit", c.unit)
+		}
+		if got := convertAnswerToUnit(c.answer, unit); got != c.want {
+			t.Errorf("convertAnswerToUnit(%q, %q) = %q; want %q", c.answer, unit.symbol, got, c.want)
+		}
+	}
+}
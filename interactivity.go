@@ -0,0 +1,124 @@
+//////////////////////////////////////////////////
+// Interactivity Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for receiving interactive message actions (menu selections and button presses), over HTTP or Socket
+// Mode, and answering them in place
+import (
+	"bytes"         // Permits posting of replacement messages
+	"encoding/json" // Permits decoding of action payloads and encoding of replies
+	"fmt"           // Permits formatting of response URL failures
+	"log"           // Permits console logging
+	"net/http"      // Permits serving of the interactivity route and posting to response URLs
+	"strings"       // Permits checking of response URLs
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constant holding the interactivity route's path
+const interactivityPath = "/slack/interactivity"
+
+// Global variable holding the only host response URLs may point at, so a forged payload can't make the bot post
+// elsewhere (a variable so tests can point it at a fake response server)
+var interactionResponseHost = "https://hooks.slack.com/"
+
+// Global struct holding the message replacing the one whose action was handled
+type interactionReply struct {
+	Text        string
+	Attachments []slack.Attachment
+}
+
+// Global type for a handler of one interactive message's actions, returning its replacement (nil leaves it unchanged)
+type interactionHandler func(callback *slack.InteractionCallback) *interactionReply
+
+// Global map of interactive message handlers by the callback ID their messages were posted with
+var interactionHandlers = map[string]interactionHandler{}
+
+// Global function for registering the handler of an interactive message's callback ID
+func registerInteraction(callbackID string, handler interactionHandler) {
+	interactionHandlers[callbackID] = handler
+}
+
+// Global function for checking whether Slack can deliver interactive actions to the bot: over Socket Mode, or to the
+// signed interactivity route on the metrics listener
+func interactivityAvailable() bool {
+	return usesSocketMode() || (config.MetricsAddr != "" && config.SlackSigningSecret != "")
+}
+
+// Global function for serving the interactivity route: acknowledging at once, since Slack allows three seconds, and
+// answering through the action's response URL
+func serveInteractivity(w http.ResponseWriter, r *http.Request) {
+	var callback slack.InteractionCallback
+	if err := json.Unmarshal([]byte(r.PostFormValue("payload")), &callback); err != nil {
+		writeHTTPError(w, http.StatusBadRequest, "invalid_payload")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	if !acceptSlackDelivery(r, interactivityPath, interactionDeliveryID(&callback)) {
+		return
+	}
+	go handleInteraction(&callback)
+}
+
+// Global function for identifying one delivery of an action: its trigger ID, else the time of its first action
+func interactionDeliveryID(callback *slack.InteractionCallback) string {
+	if callback.TriggerID != "" {
+		return callback.TriggerID
+	}
+	if callback.ActionTs != "" {
+		return callback.CallbackID + ":" + callback.ActionTs
+	}
+	return ""
+}
+
+// Global function for running an action's handler and replacing its message with the reply
+func handleInteraction(callback *slack.InteractionCallback) {
+	handler, ok := interactionHandlers[callback.CallbackID]
+	if !ok {
+		metrics.inc("wolfy_interactions_total", "callback", "unknown")
+		log.Printf("INTERACTIVITY ERROR: No handler for the callback %q.", callback.CallbackID)
+		return
+	}
+	metrics.inc("wolfy_interactions_total", "callback", callback.CallbackID)
+	reply := handler(callback)
+	if reply == nil {
+		return
+	}
+	if err := respondToInteraction(callback.ResponseURL, *reply); err != nil {
+		log.Printf("INTERACTIVITY ERROR: Unable to answer the %q action for %s.\nError Details: %v", callback.CallbackID, callback.User.ID, err)
+	}
+}
+
+// Global function for replacing an action's message through its response URL, which (unlike chat.update) also reaches
+// ephemeral messages
+func respondToInteraction(responseURL string, reply interactionReply) error {
+	return postToResponseURL(responseURL, map[string]interface{}{
+		"replace_original": true,
+		"text":             stagingTagged(escapeSlackText(reply.Text)),
+		"attachments":      reply.Attachments,
+	})
+}
+
+// Global function for posting a message payload to a response URL, refusing any not on Slack's hooks host
+func postToResponseURL(responseURL string, payload map[string]interface{}) error {
+	if !strings.HasPrefix(responseURL, interactionResponseHost) {
+		return fmt.Errorf("refusing the response URL %q", responseURL)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	res, err := httpClient.Post(responseURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("response URL returned status %s", res.Status)
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+//////////////////////////////////////////////////
+// Exact Arithmetic Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Answer package for answers worked out locally, free of any Slack or NLP concerns
+package answer
+
+// Global imports for answering big-number arithmetic exactly without any external API
+import (
+	"errors"   // Permits the parse and limit errors
+	"fmt"      // Permits string formatting of answers
+	"math/big" // Permits arbitrary precision integers and rationals
+	"regexp"   // Permits matching of arithmetic phrasings
+	"strings"  // Permits string normalization
+)
+
+// Global constants bounding local work, past which a question goes to Wolfram instead
+const (
+	maxExactFactorial  = 3000  // 3000! has 9131 digits
+	maxExactResultBits = 40000 // About 12000 decimal digits
+	maxExactExpression = 200   // Characters in the expression itself
+)
+
+// Global errors for expressions we decline to evaluate locally
+var (
+	ErrNotArithmetic   = errors.New("not a plain arithmetic expression")
+	ErrArithmeticLimit = errors.New("arithmetic result too large to compute locally")
+)
+
+// Global patterns recognizing the question around an expression and its spelled-out operators
+var (
+	arithmeticQuestionPattern = regexp.MustCompile(`(?i)^\s*(?:(?:what\s+is|what's|whats|calculate|compute|evaluate)\s+)?(?:the\s+)?(.+?)\s*=?\s*[?.]*\s*$`)
+	thousandsSeparatorPattern = regexp.MustCompile(`(\d),(\d{3})\b`)
+	isoDateLikePattern        = regexp.MustCompile(`^\d{4}-\d{1,2}-\d{1,2}$`)
+	factorialOfPattern        = regexp.MustCompile(`(?i)\bfactorial\s+(?:of\s+)?(\d+)`)
+	trailingFactorialPattern  = regexp.MustCompile(`(?i)(\d+)\s+factorial\b`)
+	arithmeticWordReplacer    = strings.NewReplacer(
+		" to the power of ", "^", " divided by ", "/", " multiplied by ", "*", " times ", "*", " plus ", "+", " minus ", "-",
+		" over ", "/", "×", "*", "÷", "/", "**", "^",
+	)
+)
+
+// Global struct holding the state of a recursive-descent parse over an expression
+type arithmeticParser struct {
+	text     string
+	position int
+}
+
+// Method for skipping whitespace and peeking at the next character (0 at the end)
+func (parser *arithmeticParser) peek() byte {
+	for parser.position < len(parser.text) && parser.text[parser.position] == ' ' {
+		parser.position++
+	}
+	if parser.position >= len(parser.text) {
+		return 0
+	}
+	return parser.text[parser.position]
+}
+
+// Method for parsing a sum or difference of terms
+func (parser *arithmeticParser) expression() (*big.Rat, error) {
+	value, err := parser.term()
+	for err == nil {
+		operator := parser.peek()
+		if operator != '+' && operator != '-' {
+			break
+		}
+		parser.position++
+		var right *big.Rat
+		if right, err = parser.term(); err == nil {
+			if operator == '+' {
+				value.Add(value, right)
+			} else {
+				value.Sub(value, right)
+			}
+		}
+	}
+	return value, err
+}
+
+// Method for parsing a product or quotient of signed factors
+func (parser *arithmeticParser) term() (*big.Rat, error) {
+	value, err := parser.unary()
+	for err == nil {
+		operator := parser.peek()
+		if operator != '*' && operator != '/' {
+			break
+		}
+		parser.position++
+		var right *big.Rat
+		if right, err = parser.unary(); err == nil {
+			if operator == '*' {
+				value.Mul(value, right)
+			} else if right.Sign() == 0 {
+				// Leaving division by zero to Wolfram, which explains it better than an error would
+				return nil, ErrNotArithmetic
+			} else {
+				value.Quo(value, right)
+			}
+			if value.Num().BitLen()+value.Denom().BitLen() > maxExactResultBits {
+				return nil, ErrArithmeticLimit
+			}
+		}
+	}
+	return value, err
+}
+
+// Method for parsing a signed factor (so "-2^2" is -4, as usual)
+func (parser *arithmeticParser) unary() (*big.Rat, error) {
+	switch parser.peek() {
+	case '-':
+		parser.position++
+		value, err := parser.unary()
+		if err != nil {
+			return nil, err
+		}
+		return value.Neg(value), nil
+	case '+':
+		parser.position++
+		return parser.unary()
+	}
+	return parser.power()
+}
+
+// Method for parsing a right-associative power of factorials
+func (parser *arithmeticParser) power() (*big.Rat, error) {
+	base, err := parser.postfix()
+	if err != nil || parser.peek() != '^' {
+		return base, err
+	}
+	parser.position++
+	exponent, err := parser.unary()
+	if err != nil {
+		return nil, err
+	}
+	if !exponent.IsInt() || !exponent.Num().IsInt64() {
+		// Irrational powers (square roots and the like) need Wolfram
+		return nil, ErrNotArithmetic
+	}
+
+	n := exponent.Num().Int64()
+	magnitude := n
+	if magnitude < 0 {
+		magnitude = -magnitude
+	}
+	if bits := int64(base.Num().BitLen() + base.Denom().BitLen()); bits > 2 && (magnitude > maxExactResultBits || bits*magnitude > maxExactResultBits) {
+		return nil, ErrArithmeticLimit
+	}
+	if n < 0 && base.Sign() == 0 {
+		return nil, ErrNotArithmetic
+	}
+	numerator := new(big.Int).Exp(base.Num(), big.NewInt(magnitude), nil)
+	denominator := new(big.Int).Exp(base.Denom(), big.NewInt(magnitude), nil)
+	if n < 0 {
+		numerator, denominator = denominator, numerator
+	}
+	return new(big.Rat).SetFrac(numerator, denominator), nil
+}
+
+// Method for parsing a primary followed by any number of "!" factorials
+func (parser *arithmeticParser) postfix() (*big.Rat, error) {
+	value, err := parser.primary()
+	for err == nil && parser.peek() == '!' {
+		parser.position++
+		if !value.IsInt() || value.Sign() < 0 || !value.Num().IsInt64() || value.Num().Int64() > maxExactFactorial {
+			if value.IsInt() && value.Sign() >= 0 {
+				return nil, ErrArithmeticLimit
+			}
+			return nil, ErrNotArithmetic
+		}
+		value = new(big.Rat).SetInt(new(big.Int).MulRange(1, value.Num().Int64()))
+	}
+	return value, err
+}
+
+// Method for parsing a number or a parenthesized expression
+func (parser *arithmeticParser) primary() (*big.Rat, error) {
+	if parser.peek() == '(' {
+		parser.position++
+		value, err := parser.expression()
+		if err != nil {
+			return nil, err
+		}
+		if parser.peek() != ')' {
+			return nil, ErrNotArithmetic
+		}
+		parser.position++
+		return value, nil
+	}
+
+	start := parser.position
+	for parser.position < len(parser.text) && (parser.text[parser.position] >= '0' && parser.text[parser.position] <= '9' || parser.text[parser.position] == '.') {
+		parser.position++
+	}
+	value, ok := new(big.Rat).SetString(parser.text[start:parser.position])
+	if start == parser.position || !ok {
+		return nil, ErrNotArithmetic
+	}
+	return value, nil
+}
+
+// Global function for evaluating an arithmetic expression exactly, declining anything symbolic or too large
+func EvaluateExact(expression string) (*big.Rat, error) {
+	parser := &arithmeticParser{text: expression}
+	value, err := parser.expression()
+	if err != nil {
+		return nil, err
+	}
+	if parser.peek() != 0 {
+		return nil, ErrNotArithmetic
+	}
+	return value, nil
+}
+
+// Global function for writing an exact value: an integer, a terminating decimal, or a fraction with an approximation
+func FormatExact(value *big.Rat) string {
+	if value.IsInt() {
+		digits := value.Num().String()
+		if length := len(strings.TrimPrefix(digits, "-")); length > 30 {
+			return fmt.Sprintf("%s _(%d digits)_", digits, length)
+		}
+		return digits
+	}
+
+	// Counting the factors of 2 and 5 in the denominator, which is all a terminating decimal may have
+	denominator := new(big.Int).Set(value.Denom())
+	places := 0
+	for _, prime := range []int64{2, 5} {
+		count := 0
+		divisor, remainder := big.NewInt(prime), new(big.Int)
+		for {
+			quotient, rest := new(big.Int).QuoRem(denominator, divisor, remainder)
+			if rest.Sign() != 0 {
+				break
+			}
+			denominator, count = quotient, count+1
+		}
+		if count > places {
+			places = count
+		}
+	}
+	if denominator.IsInt64() && denominator.Int64() == 1 {
+		return value.FloatString(places)
+	}
+	approximation := strings.TrimRight(strings.TrimRight(value.FloatString(12), "0"), ".")
+	return fmt.Sprintf("%s ≈ %s", value.String(), approximation)
+}
+
+// Global function for pulling a plain arithmetic expression out of a question, returning it as asked and as parsed,
+// and reporting false for anything else
+func ArithmeticExpression(text string) (string, string, bool) {
+	match := arithmeticQuestionPattern.FindStringSubmatch(text)
+	if match == nil || isoDateLikePattern.MatchString(match[1]) {
+		return "", "", false
+	}
+	expression := " " + strings.ToLower(match[1]) + " "
+	for previous := ""; previous != expression; {
+		previous, expression = expression, thousandsSeparatorPattern.ReplaceAllString(expression, "$1$2")
+	}
+	expression = factorialOfPattern.ReplaceAllString(expression, "($1)!")
+	expression = trailingFactorialPattern.ReplaceAllString(expression, "($1)!")
+	expression = strings.TrimSpace(arithmeticWordReplacer.Replace(expression))
+	expression = strings.Replace(expression, " x ", "*", -1)
+
+	// Requiring a real operation, so a bare "+1" or "-5" isn't "answered"
+	if len(expression) == 0 || len(expression) > maxExactExpression || !strings.ContainsAny(strings.TrimLeft(expression, "+- "), "+-*/^!") {
+		return "", "", false
+	}
+	if strings.IndexFunc(expression, func(r rune) bool { return !strings.ContainsRune("0123456789.+-*/^!() ", r) }) >= 0 {
+		return "", "", false
+	}
+	return strings.TrimSpace(match[1]), expression, true
+}
@@ -0,0 +1,66 @@
+//////////////////////////////////////////////////
+// Exact Arithmetic Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Answer package for answers worked out locally, free of any Slack or NLP concerns
+package answer
+
+// Global imports for testing exact arithmetic
+import (
+	"testing" // Permits Go testing
+)
+
+// Test for pulling expressions out of questions and declining anything that isn't plain arithmetic
+func TestArithmeticExpression(t *testing.T) {
+	cases := []struct {
+		text       string
+		expression string
+		ok         bool
+	}{
+		{"what is 2 + 2?", "2 + 2", true},
+		{"factorial of 20", "(20)!", true},
+		{"1,000,000 times 3", "1000000*3", true},
+		{"2 to the power of 10", "2^10", true},
+		{"2024-01-15", "", false},
+		{"-5", "", false},
+		{"sqrt(2)", "", false},
+		{"population of France", "", false},
+	}
+	for _, c := range cases {
+		_, expression, ok := ArithmeticExpression(c.text)
+		if ok != c.ok || expression != c.expression {
+			t.Errorf("ArithmeticExpression(%q) = %q, %v; want %q, %v", c.text, expression, ok, c.expression, c.ok)
+		}
+	}
+}
+
+// Test for evaluating and formatting exact results, and declining symbolic or oversized ones
+func TestEvaluateExact(t *testing.T) {
+	cases := []struct {
+		expression string
+		want       string
+		err        error
+	}{
+		{"2+3*4", "14", nil},
+		{"-2^2", "-4", nil},
+		{"2^-2", "0.25", nil},
+		{"1/3", "1/3 ≈ 0.333333333333", nil},
+		{"(20)!", "2432902008176640000", nil},
+		{"1/0", "", ErrNotArithmetic},
+		{"2^0.5", "", ErrNotArithmetic},
+		{"(5000)!", "", ErrArithmeticLimit},
+		{"2^100000", "", ErrArithmeticLimit},
+		{"2+", "", ErrNotArithmetic},
+	}
+	for _, c := range cases {
+		value, err := EvaluateExact(c.expression)
+		if err != c.err {
+			t.Errorf("EvaluateExact(%q) error = %v; want %v", c.expression, err, c.err)
+			continue
+		}
+		if err == nil && FormatExact(value) != c.want {
+			t.Errorf("FormatExact(EvaluateExact(%q)) = %q; want %q", c.expression, FormatExact(value), c.want)
+		}
+	}
+}
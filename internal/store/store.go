@@ -0,0 +1,154 @@
+//////////////////////////////////////////////////
+// Storage Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Store package for the JSON-file persistence layer, free of any Slack or answering concerns
+package store
+
+// Global imports for the JSON-file persistence layer
+import (
+	"encoding/json" // Permits (de)serialization of stored records
+	"io/ioutil"     // Permits reading and writing of the data file
+	"log"           // Permits console logging
+	"os"            // Permits atomic file replacement
+	"sort"          // Permits stable ordering of bucket keys
+	"sync"          // Permits concurrency-safe access to buckets
+	"time"          // Permits periodic flushing
+)
+
+// Global struct holding a bucketed key/value store persisted to a single JSON file; flushMu lets one flush at a time
+// snapshot, write, and rename, so an older snapshot never replaces a newer one, and changes counts every write so a
+// flush only marks the store clean when nothing changed since its snapshot
+type File struct {
+	mu      sync.Mutex
+	flushMu sync.Mutex
+	path    string
+	buckets map[string]map[string]json.RawMessage
+	dirty   bool
+	changes uint64
+}
+
+// Global function writing the temp file a flush renames into place (a variable so tests can hold a flush mid-write)
+var writeFile = ioutil.WriteFile
+
+// Global function for opening the data file, starting empty when it does not exist yet
+func Open(path string) (*File, error) {
+	opened := &File{path: path, buckets: map[string]map[string]json.RawMessage{}}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return opened, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &opened.buckets); err != nil {
+		return nil, err
+	}
+	return opened, nil
+}
+
+// Method for decoding a stored record into target, reporting whether it exists
+func (s *File) Get(bucket string, key string, target interface{}) bool {
+	s.mu.Lock()
+	raw, ok := s.buckets[bucket][key]
+	s.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal(raw, target); err != nil {
+		log.Printf("STORE ERROR: Unable to decode %s/%s.\nError Details: %v", bucket, key, err)
+		return false
+	}
+	return true
+}
+
+// Method for saving a record in memory; it reaches disk on the next flush
+func (s *File) Put(bucket string, key string, value interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.buckets[bucket] == nil {
+		s.buckets[bucket] = map[string]json.RawMessage{}
+	}
+	s.buckets[bucket][key] = raw
+	s.dirty = true
+	s.changes++
+	return nil
+}
+
+// Method for removing a record
+func (s *File) Delete(bucket string, key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.buckets[bucket][key]; ok {
+		delete(s.buckets[bucket], key)
+		s.dirty = true
+		s.changes++
+	}
+}
+
+// Method for listing the keys of a bucket in sorted order
+func (s *File) Keys(bucket string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]string, 0, len(s.buckets[bucket]))
+	for key := range s.buckets[bucket] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Method for writing pending changes to disk atomically (write to a temp file, then rename), leaving the store dirty
+// when the write fails so the next flush retries it
+func (s *File) Flush() error {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	s.mu.Lock()
+	if !s.dirty {
+		s.mu.Unlock()
+		return nil
+	}
+	data, err := json.Marshal(s.buckets)
+	snapshot := s.changes
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	tmpPath := s.path + ".tmp"
+	if err := writeFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return err
+	}
+
+	// Marking the store clean only when no write landed after the snapshot, which the next flush then saves
+	s.mu.Lock()
+	if s.changes == snapshot {
+		s.dirty = false
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+// Method for flushing pending changes in the background on a fixed interval
+func (s *File) StartFlusher(interval time.Duration) {
+	go func() {
+		for range time.Tick(interval) {
+			if err := s.Flush(); err != nil {
+				log.Printf("STORE ERROR: Unable to flush data file.\nError Details: %v", err)
+			}
+		}
+	}()
+}
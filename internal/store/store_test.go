@@ -0,0 +1,214 @@
+//////////////////////////////////////////////////
+// Storage Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Store package for the JSON-file persistence layer, free of any Slack or answering concerns
+package store
+
+// Global imports for testing the persistence layer
+import (
+	"fmt"           // Permits naming of concurrent records
+	"io/ioutil"     // Permits writing of a corrupt data file
+	"os"            // Permits creating the data file's directory late
+	"path/filepath" // Permits temporary data file paths
+	"reflect"       // Permits comparison of key listings
+	"sync"          // Permits concurrent writes and flushes
+	"testing"       // Permits Go testing
+	"time"          // Permits releasing a held flush
+)
+
+// Test for saving, listing, and removing records, and for reading them back after a flush
+func TestFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wolfy.json")
+	file, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open(%q) on a missing file: %v", path, err)
+	}
+	if err := file.Put("prefs", "U2", map[string]string{"units": "metric"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Put("prefs", "U1", map[string]string{"units": "imperial"}); err != nil {
+		t.Fatal(err)
+	}
+	file.Delete("prefs", "missing")
+	if keys := file.Keys("prefs"); !reflect.DeepEqual(keys, []string{"U1", "U2"}) {
+		t.Errorf("Keys = %v; want [U1 U2]", keys)
+	}
+	if err := file.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var prefs map[string]string
+	if !reopened.Get("prefs", "U2", &prefs) || prefs["units"] != "metric" {
+		t.Errorf("Get after reopening = %v; want metric units", prefs)
+	}
+	reopened.Delete("prefs", "U2")
+	if reopened.Get("prefs", "U2", &prefs) {
+		t.Error("Get found a deleted record")
+	}
+	var wrongType int
+	if reopened.Get("prefs", "U1", &wrongType) {
+		t.Error("Get decoded a record into the wrong type")
+	}
+}
+
+// Test for refusing a corrupt data file rather than starting over on top of it
+func TestOpenCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wolfy.json")
+	if err := ioutil.WriteFile(path, []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path); err == nil {
+		t.Error("Open accepted a corrupt data file")
+	}
+}
+
+// Test for flushes racing each other and ongoing writes, as the background flusher and the shutdown flush do, never
+// leaving an older snapshot on disk or a write marked as saved when it wasn't
+func TestConcurrentFlushes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wolfy.json")
+	file, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const writers, records = 4, 50
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for flusher := 0; flusher < 2; flusher++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if err := file.Flush(); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	var writes sync.WaitGroup
+	for writer := 0; writer < writers; writer++ {
+		writes.Add(1)
+		go func(writer int) {
+			defer writes.Done()
+			for record := 0; record < records; record++ {
+				if err := file.Put("records", fmt.Sprintf("%d-%d", writer, record), record); err != nil {
+					t.Error(err)
+				}
+			}
+		}(writer)
+	}
+	writes.Wait()
+	close(done)
+	wg.Wait()
+	if err := file.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(reopened.Keys("records")); got != writers*records {
+		t.Errorf("records on disk after racing flushes = %d; want %d", got, writers*records)
+	}
+}
+
+// Test for a flush that started first, and is slow to write, never replacing a later flush's newer snapshot
+func TestSlowFlushDoesNotWinRename(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wolfy.json")
+	file, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Put("prefs", "U1", "metric"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Holding the first flush's write until it is released
+	writing, release := make(chan struct{}), make(chan struct{})
+	var once sync.Once
+	writeFile = func(name string, data []byte, perm os.FileMode) error {
+		once.Do(func() {
+			close(writing)
+			<-release
+		})
+		return ioutil.WriteFile(name, data, perm)
+	}
+	defer func() { writeFile = ioutil.WriteFile }()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if err := file.Flush(); err != nil {
+			t.Error(err)
+		}
+	}()
+	<-writing
+	if err := file.Put("prefs", "U2", "imperial"); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		defer wg.Done()
+		if err := file.Flush(); err != nil {
+			t.Error(err)
+		}
+	}()
+	time.AfterFunc(50*time.Millisecond, func() { close(release) })
+	wg.Wait()
+
+	for attempt := 0; attempt < 2; attempt++ {
+		reopened, err := Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var units string
+		if !reopened.Get("prefs", "U2", &units) {
+			t.Errorf("the write made during the slow flush is missing from disk (after %d more flushes)", attempt)
+		}
+		if err := file.Flush(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// Test for a failed flush leaving the store dirty, so the next flush saves what the failed one couldn't
+func TestFlushRetriesAfterFailure(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "missing")
+	file, err := Open(filepath.Join(dir, "wolfy.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Put("prefs", "U1", "metric"); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Flush(); err == nil {
+		t.Fatal("Flush into a missing directory succeeded")
+	}
+
+	if err := os.Mkdir(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := Open(filepath.Join(dir, "wolfy.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var units string
+	if !reopened.Get("prefs", "U1", &units) || units != "metric" {
+		t.Errorf("Get after the retried flush = %q; want metric", units)
+	}
+}
@@ -0,0 +1,68 @@
+//////////////////////////////////////////////////
+// Channel Introduction Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for introducing the bot when it is invited to a channel
+import (
+	"fmt"  // Permits string formatting of the introduction
+	"log"  // Permits console logging
+	"sync" // Permits serialized cooldown checks
+	"time" // Permits the re-invite cooldown
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constant naming the store bucket recording when each channel was last introduced to
+const channelIntrosBucket = "channel_intros"
+
+// Global mutex serializing cooldown checks so a burst of join events only gets one introduction
+var channelIntrosMu sync.Mutex
+
+// Registering the per-workspace switch for channel introductions
+func init() {
+	registerFeatureFlag(featureFlag{name: "channel_intro", description: "Introduce the bot when it is invited to a channel.", defaultOn: true})
+}
+
+// Global function for describing how the bot is triggered and where it answers
+func channelTriggerRules() string {
+	rules := "I answer questions posted in this channel"
+	if config.RequireMention {
+		rules = fmt.Sprintf("I only answer here when you mention me (<@%s>), plus follow-ups in threads I've replied in", currentBotUserID())
+	}
+	if config.AnswerInChannel {
+		return rules + ", and I reply right here."
+	}
+	return rules + ", and I send the answer to you by DM."
+}
+
+// Global function for posting an introduction when the bot itself joins a channel, at most once per cooldown, and for
+// reactivating a channel once the bot is back in it
+func handleMemberJoinedChannel(event *slack.MemberJoinedChannelEvent) {
+	if currentBotUserID() == "" || event.User != currentBotUserID() {
+		return
+	}
+	reactivateChannel(event.Channel)
+	if !evaluateFlag("channel_intro", flagScope{channel: event.Channel, team: event.Team}) {
+		return
+	}
+
+	channelIntrosMu.Lock()
+	var lastIntro time.Time
+	if store.get(channelIntrosBucket, event.Channel, &lastIntro) && time.Since(lastIntro) < config.ChannelIntroCooldown {
+		channelIntrosMu.Unlock()
+		metrics.inc("wolfy_channel_intros_total", "result", "cooldown")
+		return
+	}
+	if err := store.put(channelIntrosBucket, event.Channel, time.Now()); err != nil {
+		log.Printf("INTRO ERROR: Unable to record introduction for %s.\nError Details: %v", event.Channel, err)
+	}
+	channelIntrosMu.Unlock()
+
+	metrics.inc("wolfy_channel_intros_total", "result", "posted")
+	scope := flagScope{channel: event.Channel, team: event.Team}
+	postText(event.Channel, fmt.Sprintf("%s\n%s\n%s", packMessage(scope, "channel_intro"), channelTriggerRules(), packMessage(scope, "welcome_examples")))
+}
@@ -0,0 +1,64 @@
+//////////////////////////////////////////////////
+// External API Kill-Switch Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for globally disabling calls to Wit.ai, Wolfram, and translation backends
+import (
+	"log"         // Permits console logging
+	"strings"     // Permits parsing of admin arguments
+	"sync/atomic" // Permits lock-free reads of the switch
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global error returned by every external API call site while the kill-switch is on
+var errExternalAPIsDisabled = &failureClass{name: "external API calls are disabled", code: "X-OFFLINE", key: "apis_disabled", category: "MESSAGE HANDLING", expected: true}
+
+// Global flag (1 when disabled) checked before every Wit.ai, Wolfram, and translation request
+var externalAPIsDisabled int32
+
+// Global function for flipping the kill-switch and reflecting its state in metrics
+func setExternalAPIsDisabled(disabled bool) {
+	value := int64(0)
+	if disabled {
+		value = 1
+	}
+	atomic.StoreInt32(&externalAPIsDisabled, int32(value))
+	metrics.set(value, "wolfy_external_apis_disabled")
+}
+
+// Global function for checking whether external API calls are currently allowed
+func externalAPIsAllowed() bool {
+	return atomic.LoadInt32(&externalAPIsDisabled) == 0
+}
+
+// Global function for describing the kill-switch state for status output
+func externalAPIsStatus() string {
+	if externalAPIsAllowed() {
+		return "enabled"
+	}
+	return "DISABLED (local answers only)"
+}
+
+// Admin command showing or flipping the external API kill-switch
+func runAdminAPIs(event *slack.MessageEvent, args []string) string {
+	if len(args) > 0 {
+		switch strings.ToLower(args[0]) {
+		case "off", "disable":
+			setExternalAPIsDisabled(true)
+			metrics.inc("wolfy_external_apis_toggles_total", "state", "disabled")
+			log.Printf("ADMIN: External API calls disabled by %s.", event.User)
+		case "on", "enable":
+			setExternalAPIsDisabled(false)
+			metrics.inc("wolfy_external_apis_toggles_total", "state", "enabled")
+			log.Printf("ADMIN: External API calls enabled by %s.", event.User)
+		default:
+			return "Usage: `!apis [on|off]`"
+		}
+	}
+	return "External API calls are " + externalAPIsStatus() + "."
+}
@@ -0,0 +1,226 @@
+//////////////////////////////////////////////////
+// Channel Language Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for posting the bot's canned messages in a channel's own language
+import (
+	"fmt"     // Permits formatting of command replies
+	"log"     // Permits console logging
+	"regexp"  // Permits validation of language codes
+	"sort"    // Permits stable ordering of language listings
+	"strings" // Permits string normalization
+	"sync"    // Permits concurrency-safe catalog access
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constant naming the store bucket holding each channel's language set at runtime
+const channelLanguagesBucket = "channel_languages"
+
+// Global constant naming the catalog key introducing the capability list (capability descriptions use "capability.<name>")
+const capabilitiesIntroKey = "capabilities_intro"
+
+// Global pattern recognizing a language code ("fr", "pt-BR")
+var languageCodePattern = regexp.MustCompile(`^[a-z]{2,3}(?:-[A-Za-z]{2,4})?$`)
+
+// Global message catalogs built into the binary by language code
+var builtinLanguageCatalogs = map[string]map[string]string{
+	"fr": {
+		"greeting":           "Bonjour {name} ! Je suis WolfyBot et je suis là pour répondre à vos questions. :-)",
+		"welcome_intro":      "Salut {name}, je suis WolfyBot ! :wave: Posez-moi presque n'importe quelle question factuelle ou numérique et je chercherai la réponse sur Wolfram|Alpha.",
+		"channel_intro":      "Bonjour à tous, je suis WolfyBot ! :wave: Merci pour l'invitation. Je cherche des faits, des chiffres et des conversions sur Wolfram|Alpha.",
+		"welcome_examples":   "Vous ne savez pas par où commencer ? Essayez l'une de ces questions :\n• _What is the population of France?_\n• _100 F in C and K_\n• _How many days until Christmas?_\nDites « what can you do » à tout moment pour la liste complète.",
+		"onboarding_note":    "_Bonjour ! Je suis WolfyBot - posez-moi des questions factuelles, ou dites « what can you do » pour obtenir de l'aide._ :-)",
+		"placeholder":        "Je m'en occupe... :hourglass:",
+		"unclear_input":      "ATTENTION : la question n'est pas claire. :-/ Pouvez-vous la reformuler ?",
+		"timeout":            "Désolé, je n'ai pas pu obtenir de réponse à temps. :-( Réessayez dans un moment ?",
+		"wit_busy":           "Je reçois beaucoup de questions en ce moment ! :sweat_smile: Réessayez dans une minute.",
+		"apis_disabled":      "Je suis temporairement limité aux réponses que je peux trouver moi-même (calculs de dates, etc.) - réessayez votre question plus tard. :construction:",
+		"wolfram_unclear":    "Oups, on dirait que je n'ai pas bien compris ! :-O",
+		"wolfram_too_long":   "Oups ! J'ai trouvé votre réponse, mais elle est un peu trop longue pour que je la transmette. :-P",
+		"nlp_unavailable":    "Désolé, je ne peux pas comprendre les questions en ce moment - mon service de langage ne répond pas. :-( Réessayez dans un moment ?",
+		"quota_exceeded":     "Désolé, j'ai épuisé mon quota Wolfram|Alpha pour le moment. :-( Réessayez plus tard ?",
+		"internal_error":     "Désolé, quelque chose s'est mal passé de mon côté. :-( Réessayez, et citez ce code si le problème persiste.",
+		"empty_mention":      "Vous m'avez appelé ? :-) Posez une question après la mention, ou tapez « help » pour voir ce que je sais faire.",
+		capabilitiesIntroKey: "Voici ce que je sais faire en ce moment :",
+	},
+}
+
+// Global message catalogs by language code, the built-in ones extended from the catalog directory
+var (
+	languageCatalogsMu sync.RWMutex
+	languageCatalogs   = copyLanguageCatalogs(builtinLanguageCatalogs)
+)
+
+// Global function for checking whether a key may appear in a language catalog
+func isLanguageCatalogKey(key string) bool {
+	if key == capabilitiesIntroKey || strings.HasPrefix(key, "capability.") {
+		return true
+	}
+	for _, known := range append(requiredMessageKeys, optionalMessageKeys...) {
+		if key == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Global function for copying catalogs, so loading never edits the built-in ones
+func copyLanguageCatalogs(catalogs map[string]map[string]string) map[string]map[string]string {
+	copied := make(map[string]map[string]string, len(catalogs))
+	for language, messages := range catalogs {
+		copied[language] = make(map[string]string, len(messages))
+		for key, text := range messages {
+			copied[language][key] = text
+		}
+	}
+	return copied
+}
+
+// Global function for loading "<code>.json" catalogs ({"key": "text"}) from the configured directory on top of the
+// built-in ones, swapping them in whole and returning the problems found (messages with one are left out)
+func loadLanguageCatalogs() []catalogProblem {
+	catalogs := copyLanguageCatalogs(builtinLanguageCatalogs)
+	var problems []catalogProblem
+	if config.LanguagesDir != "" {
+		paths, err := catalogFiles(config.LanguagesDir)
+		if err != nil {
+			problems = append(problems, catalogProblem{path: config.LanguagesDir, message: err.Error()})
+		}
+		for _, path := range paths {
+			language, messages, fileProblems := readLanguageCatalog(path)
+			problems = append(problems, fileProblems...)
+			if catalogs[language] == nil {
+				catalogs[language] = map[string]string{}
+			}
+			for key, text := range messages {
+				catalogs[language][key] = text
+			}
+		}
+	}
+	logCatalogProblems(problems)
+
+	languageCatalogsMu.Lock()
+	languageCatalogs = catalogs
+	languageCatalogsMu.Unlock()
+	return problems
+}
+
+// Global function for listing the languages with a catalog
+func catalogLanguages() []string {
+	languageCatalogsMu.RLock()
+	defer languageCatalogsMu.RUnlock()
+
+	languages := make([]string, 0, len(languageCatalogs))
+	for language := range languageCatalogs {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Global function for looking up a channel's language: one set at runtime with "!channel", else the configured one ("" for none)
+func channelLanguage(channel string) string {
+	if channel == "" {
+		return ""
+	}
+	var language string
+	if store != nil && store.get(channelLanguagesBucket, channel, &language) {
+		return language
+	}
+	return config.ChannelLanguages[channel]
+}
+
+// Global function for looking up a message in a language's catalog, reporting false when it isn't translated
+func localizedMessage(language string, key string) (string, bool) {
+	if language == "" || language == "en" {
+		return "", false
+	}
+	languageCatalogsMu.RLock()
+	defer languageCatalogsMu.RUnlock()
+	text, ok := languageCatalogs[language][key]
+	return text, ok && text != ""
+}
+
+// Global function for checking whether a text is any catalog's translation of one of the given keys
+func isLocalizedMessage(text string, keys ...string) bool {
+	languageCatalogsMu.RLock()
+	defer languageCatalogsMu.RUnlock()
+
+	for _, messages := range languageCatalogs {
+		for _, key := range keys {
+			if messages[key] == text {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Admin command showing or setting a channel's configuration: `!channel language <code>|clear [#channel]`,
+// `!channel quiet <HH:MM-HH:MM> [timezone]|clear [#channel]`, or `!channel show [#channel]`
+func runAdminChannel(event *slack.MessageEvent, args []string) string {
+	usage := "Usage: `!channel language <code>|clear [#channel]` (languages: " + strings.Join(catalogLanguages(), ", ") + "), `!channel quiet <HH:MM-HH:MM> [timezone]|clear [#channel]`, `!channel style terse|normal|detailed|clear [#channel]`, or `!channel show [#channel]`"
+	if len(args) == 0 {
+		return usage
+	}
+
+	// Defaulting to the channel the command was run in, unless that's a DM
+	channel, rest := event.Channel, args[1:]
+	if len(rest) > 0 {
+		if match := channelReferencePattern.FindStringSubmatch(rest[len(rest)-1]); match != nil {
+			channel, rest = match[1], rest[:len(rest)-1]
+		}
+	}
+	if isDirectMessage(channel) {
+		return "Name the channel to configure, e.g. `!channel language fr #general`."
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "show":
+		language := channelLanguage(channel)
+		if language == "" {
+			language = "English (default)"
+		}
+		style := channelAnswerStyle(channel)
+		if style == "" {
+			style = config.ChannelVerbosity + " (default)"
+		}
+		return fmt.Sprintf("<#%s>:\n• Language: %s\n• Quiet hours: %s\n• Answer style: %s", channel, language, describeChannelQuietHours(channel, event.User), style)
+	case "quiet":
+		return setChannelQuietHours(event, channel, rest, usage)
+	case "style":
+		return setChannelAnswerStyle(event, channel, rest, usage)
+	case "language":
+		if len(rest) != 1 {
+			return usage
+		}
+		language := rest[0]
+		if strings.EqualFold(language, "clear") {
+			store.delete(channelLanguagesBucket, channel)
+			log.Printf("ADMIN: %s cleared the language for %s.", event.User, channel)
+			if configured := config.ChannelLanguages[channel]; configured != "" {
+				return fmt.Sprintf("Cleared the runtime language for <#%s>; it's back to the configured `%s`.", channel, configured)
+			}
+			return fmt.Sprintf("Cleared the language for <#%s>; it's back to English.", channel)
+		}
+		if !languageCodePattern.MatchString(language) {
+			return usage
+		}
+		if err := store.put(channelLanguagesBucket, channel, language); err != nil {
+			log.Printf("ADMIN ERROR: Unable to persist the language for %s.\nError Details: %v", channel, err)
+			return "Sorry, I couldn't save that setting. :-("
+		}
+		log.Printf("ADMIN: %s set the language for %s to %s.", event.User, channel, language)
+		reply := fmt.Sprintf("My canned messages in <#%s> are now in `%s`.", channel, language)
+		if _, ok := localizedMessage(language, "greeting"); !ok && language != "en" {
+			reply += fmt.Sprintf(" There's no `%s` catalog yet, so they'll stay in English until one is added to WOLFY_LANGUAGES_DIR.", language)
+		}
+		return reply
+	}
+	return usage
+}
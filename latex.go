@@ -0,0 +1,285 @@
+//////////////////////////////////////////////////
+// LaTeX Input Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for rewriting pasted LaTeX into plaintext Wolfram understands
+import (
+	"regexp"  // Permits detection of LaTeX-ish input and whitespace cleanup
+	"strings" // Permits building of the rewritten query
+)
+
+// Global patterns recognizing LaTeX-ish input and tidying the rewritten text
+var (
+	latexPattern        = regexp.MustCompile(`\\[a-zA-Z(\[]|[\^_]\{`)
+	latexWhitespace     = regexp.MustCompile(`\s+`)
+	latexPaddedParens   = regexp.MustCompile(`\(\s+|\s+\)`)
+	latexAtomicArgument = regexp.MustCompile(`^[A-Za-z0-9.]+$`)
+	latexMathEnd        = regexp.MustCompile(`\$|\\\)|\\\]`)
+	latexDifferential   = regexp.MustCompile(`^(.*?\bd[a-zA-Z])\b\s*(.*)$`)
+)
+
+// Global table of LaTeX commands that simply stand for a word or symbol
+var latexSymbols = map[string]string{
+	"alpha": "alpha", "beta": "beta", "gamma": "gamma", "delta": "delta", "epsilon": "epsilon", "varepsilon": "epsilon",
+	"zeta": "zeta", "eta": "eta", "theta": "theta", "vartheta": "theta", "iota": "iota", "kappa": "kappa",
+	"lambda": "lambda", "mu": "mu", "nu": "nu", "xi": "xi", "pi": "pi", "varpi": "pi", "rho": "rho", "sigma": "sigma",
+	"tau": "tau", "upsilon": "upsilon", "phi": "phi", "varphi": "phi", "chi": "chi", "psi": "psi", "omega": "omega",
+	"Gamma": "Gamma", "Delta": "Delta", "Theta": "Theta", "Lambda": "Lambda", "Xi": "Xi", "Pi": "Pi",
+	"Sigma": "Sigma", "Phi": "Phi", "Psi": "Psi", "Omega": "Omega",
+
+	"sin": "sin", "cos": "cos", "tan": "tan", "sec": "sec", "csc": "csc", "cot": "cot",
+	"arcsin": "arcsin", "arccos": "arccos", "arctan": "arctan", "sinh": "sinh", "cosh": "cosh", "tanh": "tanh",
+	"log": "log", "ln": "ln", "exp": "exp", "min": "min", "max": "max", "det": "det", "gcd": "gcd",
+
+	"cdot": "*", "times": "*", "div": "/", "pm": "+-", "mp": "-+", "infty": "infinity", "to": "->", "rightarrow": "->",
+	"le": "<=", "leq": "<=", "ge": ">=", "geq": ">=", "neq": "!=", "ne": "!=", "approx": "~", "partial": "d",
+	"cdots": "...", "ldots": "...", "dots": "...",
+
+	// Sizing, spacing, and display-style commands carry no meaning for Wolfram
+	"left": "", "right": "", "big": "", "Big": "", "bigg": "", "Bigg": "", "displaystyle": "", "limits": "",
+	"quad": " ", "qquad": " ",
+}
+
+// Global constant listing the characters that would run into a preceding command's word
+const latexRunTogether = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789{\\"
+
+// Global table of LaTeX commands whose argument is kept as-is ("\text{if}", "\mathrm{d}")
+var latexTextCommands = map[string]bool{"text": true, "mathrm": true, "mathit": true, "mathbf": true, "operatorname": true}
+
+// Global table of big operators and the word Wolfram uses for them
+var latexBigOperators = map[string]string{"int": "integrate", "iint": "integrate", "sum": "sum", "prod": "product", "lim": "limit"}
+
+// Global struct holding the state of one LaTeX rewrite: the input, where we are in it, and whether we've lost confidence
+type latexConverter struct {
+	text      string
+	position  int
+	uncertain bool
+}
+
+// Global function for detecting LaTeX-ish input (backslash commands, or braced exponents and subscripts)
+func looksLikeLaTeX(text string) bool {
+	return latexPattern.MatchString(text)
+}
+
+// Global function for rewriting LaTeX-ish input ("\frac{d}{dx} e^{x^2}") as Wolfram-friendly plaintext ("d/dx e^(x^2)"),
+// reporting false (and leaving the text alone) when it isn't LaTeX or the rewrite can't be trusted
+func convertLaTeX(text string) (string, bool) {
+	if !looksLikeLaTeX(text) {
+		return text, false
+	}
+	converter := &latexConverter{text: text}
+	converted := converter.sequence(0)
+	if converter.uncertain || converter.position < len(text) || strings.Contains(converted, "\\") {
+		metrics.inc("wolfy_latex_conversions_total", "result", "kept_raw")
+		return text, false
+	}
+	metrics.inc("wolfy_latex_conversions_total", "result", "converted")
+	return tidyLaTeXOutput(converted), true
+}
+
+// Global function for collapsing the spacing a rewrite leaves behind
+func tidyLaTeXOutput(text string) string {
+	text = latexWhitespace.ReplaceAllString(text, " ")
+	text = latexPaddedParens.ReplaceAllStringFunc(text, strings.TrimSpace)
+	return strings.TrimSpace(text)
+}
+
+// Global function for wrapping a rewritten argument in parentheses unless it is a single number or name
+func wrapLaTeXArgument(argument string) string {
+	argument = tidyLaTeXOutput(argument)
+	if latexAtomicArgument.MatchString(argument) {
+		return argument
+	}
+	return "(" + argument + ")"
+}
+
+// Method for rewriting text until the closing brace of the current group (or the end, at depth 0)
+func (converter *latexConverter) sequence(depth int) string {
+	var output strings.Builder
+	for converter.position < len(converter.text) {
+		character := converter.text[converter.position]
+		switch character {
+		case '}':
+			if depth == 0 {
+				converter.uncertain = true
+				converter.position++
+				continue
+			}
+			return output.String()
+		case '{':
+			converter.position++
+			output.WriteString(converter.sequence(depth + 1))
+			converter.closeGroup()
+		case '^', '_':
+			converter.position++
+			output.WriteByte(character)
+			output.WriteString(wrapLaTeXArgument(converter.argument()))
+		case '$':
+			// Inline and display math delimiters ("$x^2$", "$$...$$") just mark where the math is
+			converter.position++
+			output.WriteByte(' ')
+		case '\\':
+			output.WriteString(converter.command(depth))
+		default:
+			converter.position++
+			output.WriteByte(character)
+		}
+	}
+	if depth > 0 {
+		converter.uncertain = true
+	}
+	return output.String()
+}
+
+// Method for consuming the closing brace of a group, losing confidence when it's missing
+func (converter *latexConverter) closeGroup() {
+	if converter.position < len(converter.text) && converter.text[converter.position] == '}' {
+		converter.position++
+		return
+	}
+	converter.uncertain = true
+}
+
+// Method for skipping whitespace between a command and its arguments
+func (converter *latexConverter) skipSpaces() {
+	for converter.position < len(converter.text) && converter.text[converter.position] == ' ' {
+		converter.position++
+	}
+}
+
+// Method for rewriting one argument: a braced group, a command, or a single character
+func (converter *latexConverter) argument() string {
+	converter.skipSpaces()
+	if converter.position >= len(converter.text) {
+		converter.uncertain = true
+		return ""
+	}
+	switch converter.text[converter.position] {
+	case '{':
+		converter.position++
+		argument := converter.sequence(1)
+		converter.closeGroup()
+		return argument
+	case '\\':
+		return converter.command(1)
+	}
+	converter.position++
+	return converter.text[converter.position-1 : converter.position]
+}
+
+// Method for reading a command's name after its backslash (letters, or a single symbol like "\," or "\{")
+func (converter *latexConverter) commandName() string {
+	converter.position++
+	start := converter.position
+	for converter.position < len(converter.text) && (converter.text[converter.position] >= 'a' && converter.text[converter.position] <= 'z' || converter.text[converter.position] >= 'A' && converter.text[converter.position] <= 'Z') {
+		converter.position++
+	}
+	if converter.position == start && converter.position < len(converter.text) {
+		converter.position++
+	}
+	return converter.text[start:converter.position]
+}
+
+// Method for rewriting one command, including any arguments or limits it takes
+func (converter *latexConverter) command(depth int) string {
+	name := converter.commandName()
+	switch {
+	case name == "frac" || name == "dfrac" || name == "tfrac":
+		numerator := wrapLaTeXArgument(converter.argument())
+		denominator := wrapLaTeXArgument(converter.argument())
+		return " " + numerator + "/" + denominator + " "
+	case name == "sqrt":
+		converter.skipSpaces()
+		if converter.position < len(converter.text) && converter.text[converter.position] == '[' {
+			end := strings.IndexByte(converter.text[converter.position:], ']')
+			if end < 0 {
+				converter.uncertain = true
+				return ""
+			}
+			index := tidyLaTeXOutput((&latexConverter{text: converter.text[converter.position+1 : converter.position+end]}).sequence(0))
+			converter.position += end + 1
+			return " " + wrapLaTeXArgument(converter.argument()) + "^(1/" + wrapLaTeXArgument(index) + ") "
+		}
+		return " sqrt(" + tidyLaTeXOutput(converter.argument()) + ") "
+	case latexBigOperators[name] != "":
+		return converter.bigOperator(latexBigOperators[name], depth)
+	case latexTextCommands[name]:
+		return converter.argument()
+	case name == "," || name == ";" || name == ":" || name == "!" || name == " " || name == "\\":
+		return " "
+	case name == "(" || name == ")" || name == "[" || name == "]":
+		// "\( ... \)" and "\[ ... \]" delimit inline and display math
+		return " "
+	case name == "{" || name == "}" || name == "$" || name == "%":
+		return name
+	}
+	if symbol, ok := latexSymbols[name]; ok {
+		// Spacing a word off from what follows only when they'd otherwise run together ("\alpha\beta", but "\sin(x)")
+		if next := converter.position; next < len(converter.text) && strings.ContainsRune(latexRunTogether, rune(converter.text[next])) {
+			return " " + symbol + " "
+		}
+		return " " + symbol
+	}
+	converter.uncertain = true
+	return ""
+}
+
+// Method for rewriting an integral, sum, product, or limit: its sub- and superscript limits, then the rest of the
+// current group as its body ("\int_0^1 x^2 dx" becomes "integrate x^2 dx from 0 to 1")
+func (converter *latexConverter) bigOperator(word string, depth int) string {
+	var lower, upper string
+	for {
+		converter.skipSpaces()
+		if converter.position >= len(converter.text) {
+			break
+		}
+		if marker := converter.text[converter.position]; marker == '_' || marker == '^' {
+			converter.position++
+			if marker == '_' {
+				lower = tidyLaTeXOutput(converter.argument())
+			} else {
+				upper = tidyLaTeXOutput(converter.argument())
+			}
+			continue
+		}
+		if strings.HasPrefix(converter.text[converter.position:], `\limits`) {
+			converter.position += len(`\limits`)
+			continue
+		}
+		break
+	}
+	if depth == 0 {
+		// Stopping the body at a closing math delimiter so trailing prose stays outside it
+		if location := latexMathEnd.FindStringIndex(converter.text[converter.position:]); location != nil {
+			end := location[0]
+			body := (&latexConverter{text: converter.text[converter.position : converter.position+end]}).sequence(0)
+			converter.position += end
+			return converter.bigOperatorText(word, tidyLaTeXOutput(body), lower, upper)
+		}
+	}
+	body := tidyLaTeXOutput(converter.sequence(depth))
+	if match := latexDifferential.FindStringSubmatch(body); word == "integrate" && match != nil && match[2] != "" {
+		// Ending the integrand at its differential, so prose after it ("... dx please") stays outside the integral
+		return converter.bigOperatorText(word, match[1], lower, upper) + match[2]
+	}
+	return converter.bigOperatorText(word, body, lower, upper)
+}
+
+// Method for phrasing a big operator the way Wolfram reads it
+func (converter *latexConverter) bigOperatorText(word string, body string, lower string, upper string) string {
+	switch {
+	case word == "limit" && lower != "":
+		return " limit " + body + " as " + strings.Replace(lower, " ", "", -1) + " "
+	case lower != "" && upper != "" && strings.Contains(lower, "="):
+		return " " + word + " " + body + ", " + strings.Replace(lower, " ", "", -1) + " to " + upper + " "
+	case lower != "" && upper != "":
+		return " " + word + " " + body + " from " + lower + " to " + upper + " "
+	case lower != "":
+		return " " + word + " " + body + " over " + lower + " "
+	}
+	return " " + word + " " + body + " "
+}
@@ -0,0 +1,67 @@
+//////////////////////////////////////////////////
+// LaTeX Input Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing the LaTeX rewriter
+import (
+	"testing" // Permits Go testing
+)
+
+// Test for rewriting the common LaTeX constructs, nested braces, and inline math inside prose
+func TestConvertLaTeX(t *testing.T) {
+	cases := []struct {
+		text string
+		want string
+	}{
+		// Fractions, roots, exponents, and subscripts
+		{`\frac{d}{dx} e^{x^2}`, "d/dx e^(x^2)"},
+		{`\frac{\frac{1}{2}}{3}`, "(1/2)/3"},
+		{`\sqrt{2}`, "sqrt(2)"},
+		{`\sqrt{x^2 + 1}`, "sqrt(x^2 + 1)"},
+		{`\sqrt[3]{27}`, "27^(1/3)"},
+		{`x^{y^{z}}`, "x^(y^z)"},
+		{`e^{i\pi} + 1`, "e^(i pi) + 1"},
+		{`x_{1} + x_{2}`, "x_1 + x_2"},
+		{`\left( \frac{1}{2} \right)^{2}`, "(1/2)^2"},
+
+		// Big operators, with and without bounds
+		{`\int_0^1 x^2 dx`, "integrate x^2 dx from 0 to 1"},
+		{`\int_{0}^{\pi} \sin(x) \, dx`, "integrate sin(x) dx from 0 to pi"},
+		{`\int x^2 dx`, "integrate x^2 dx"},
+		{`\sum_{n=1}^{\infty} \frac{1}{n^2}`, "sum 1/(n^2), n=1 to infinity"},
+		{`\prod_{k=1}^{5} k`, "product k, k=1 to 5"},
+		{`\lim_{x \to 0} \frac{\sin x}{x}`, "limit (sin x)/x as x->0"},
+
+		// Symbols, Greek letters, and text
+		{`\alpha + \beta`, "alpha + beta"},
+		{`2 \cdot 3`, "2 * 3"},
+		{`\text{area of a circle with radius } 3`, "area of a circle with radius 3"},
+
+		// Inline math inside prose
+		{`what is $\frac{1}{3} + \frac{1}{6}$?`, "what is 1/3 + 1/6 ?"},
+		{`solve \(x^2 - 4 = 0\) for x`, "solve x^2 - 4 = 0 for x"},
+	}
+	for _, c := range cases {
+		if got, ok := convertLaTeX(c.text); !ok || got != c.want {
+			t.Errorf("convertLaTeX(%q) = %q, %v; want %q, true", c.text, got, ok, c.want)
+		}
+	}
+}
+
+// Test for leaving text alone when it isn't LaTeX or the rewrite can't be trusted
+func TestConvertLaTeXKeepsRaw(t *testing.T) {
+	for _, text := range []string{
+		"what is 2 + 2",
+		`C:\Users\me`,
+		`\frac{1}{2`,
+		`\unknowncommand{x}`,
+	} {
+		if got, ok := convertLaTeX(text); ok || got != text {
+			t.Errorf("convertLaTeX(%q) = %q, %v; want it unchanged, false", text, got, ok)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+//////////////////////////////////////////////////
+// Local Answers Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for answering simple questions without any external API
+import (
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global type for a local answerer, returning a reply and whether it recognized the message
+type localAnswerer func(event *slack.MessageEvent) (string, bool)
+
+// Global ordered list of local answerers consulted before Wit.ai
+var localAnswerers = []struct {
+	name        string
+	description string
+	answer      localAnswerer
+}{
+	{"date_math", "Count days or weeks until/since a date or holiday, in your timezone.", answerDateMath},
+	{"unit_conversion", "Convert common units, including several targets at once (\"100 F in C and K\").", answerUnitConversion},
+	{"quantity_comparison", "Settle comparisons between quantities with a yes or no (\"is a mile longer than a kilometer?\").", answerQuantityComparison},
+	{"exact_arithmetic", "Work out big-number arithmetic exactly and instantly (\"factorial of 100\", \"2^512 - 1\").", answerExactArithmetic},
+}
+
+// Global function for replying from a local answerer, reporting whether the message was consumed
+func handleLocalAnswer(event *slack.MessageEvent) bool {
+	name, reply, ok := localAnswer(event)
+	if !ok {
+		return false
+	}
+	if !handlerEnabled(name) {
+		noteHandlerDisabled(event, name)
+		return true
+	}
+
+	metrics.inc("wolfy_intents_total", "intent", name)
+	deliverAnswer(event, answerDestination(event), "", reply, nil, "")
+	rememberInteraction(event, interaction{Text: event.Msg.Text, EntityKey: name, Answer: reply})
+	return true
+}
+
+// Global function for finding the first local answerer that recognizes a message
+func localAnswer(event *slack.MessageEvent) (string, string, bool) {
+	for _, local := range localAnswerers {
+		if reply, ok := local.answer(event); ok {
+			return local.name, reply, true
+		}
+	}
+	return "", "", false
+}
@@ -8,121 +8,461 @@ package main
 
 // Global imports, including Slack and Wolfram API
 import (
-	"log" // Permits console logging
-	"os"  // Permits OS operations/functionality
+	"context"   // Permits per-question API usage accounting
+	"log"       // Permits console logging
+	"os"        // Permits OS operations/functionality
+	"os/signal" // Permits interception of shutdown signals
+	"sort"      // Permits deterministic entity tie-breaking
+	"syscall"   // Permits referencing of termination signals
 
 	wolfram "github.com/Krognol/go-wolfram"  // External Wolfram API
 	wit "github.com/christianrondeau/go-wit" // External Wit.ai API
 	slack "github.com/nlopes/slack"          // External Slack API
 )
 
-// Global constant holding message entity ideal confidence threshold
-const optimalEntityConfidenceThreshold = 0.5
-
-// Initializing the client APIs
-var (
-	slackClient   *slack.Client
-	witClient     *wit.Client
-	wolframClient *wolfram.Client
-)
+// Global ordered list of built-in commands and local answers consulted before Wit.ai, each reporting whether it consumed the message
+var messageInterceptors = []struct {
+	name   string
+	handle func(event *slack.MessageEvent) bool
+}{
+	{"admin_command", handleAdminCommand},
+	{"maintenance", handleMaintenance},
+	{"cancel", handleCancel},
+	{"escalation_reply", handleEscalationReply},
+	{"capabilities", handleCapabilities},
+	{"status", handleStatus},
+	{"settings", handleUserSettings},
+	{"conversation_recall", handleConversationRecall},
+	{"why", handleWhy},
+	{"saved_answers", handleSavedAnswers},
+	{"history_export", handleHistoryExport},
+	{"full_answer_post", handlePostThat},
+	{"scheduled_query", handleScheduledQuery},
+	{"more_digits", handleMoreDigits},
+	{"local_answer", handleLocalAnswer},
+}
 
 // Main run function
 func main() {
-	// Setting our client APIs to communicate across Make School's Slack
-	slackClient = slack.New(os.Getenv("SLACK_ACCESS_TOKEN"))
-	witClient = wit.NewClient(os.Getenv("WIT_AI_ACCESS_TOKEN"))
-	wolframClient = &wolfram.Client{AppID: os.Getenv("WOLFRAM_APP_ID")}
+	// Checking catalog and pack files without starting the bot ("wolfybot validate --messages path/ --packs path/")
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		os.Exit(runValidateCommand(os.Args[2:]))
+	}
+
+	// Loading runtime configuration and recording this run in the state file
+	var err error
+	config = loadConfig()
+	log.SetOutput(captureRecentErrors(os.Stderr))
+	if err := config.Validate(); err != nil {
+		log.Fatalf("CONFIG ERROR: Invalid configuration; fix these settings and restart.\nError Details:\n%v", err)
+	}
+	setExternalAPIsDisabled(config.ExternalAPIsDisabled)
+	loadPersonalityPacks()
+	loadMessageCatalog()
+	loadLanguageCatalogs()
+	loadExternalHandlers()
+	loadRouting()
+	loadTeamBranding()
+	loadIntentSynonyms()
+	recordStartup()
+	startMetricsServer()
+
+	// Opening the persistence layer and restoring the processed message window
+	if store, err = openStore(config.DataFile); err != nil {
+		log.Fatalf("STORE ERROR: Unable to open data file %s.\nError Details: %v", config.DataFile, err)
+	}
+	store.startFlusher(config.StoreFlushInterval)
+	loadProcessedEvents()
+	loadFeatureFlags()
+	openAnswerCache()
+	startCacheSweeper()
+	startMessageExpirySweeper()
+
+	// Building the shared outbound HTTP client (proxy, TLS, timeouts) before any API client
+	dialer, err := configureHTTPClient()
+	if err != nil {
+		log.Fatalf("HTTP CLIENT ERROR: Invalid proxy/TLS configuration.\nError Details: %v", err)
+	}
+
+	// Setting our client APIs to communicate across Make School's Slack (swapped whole by credential rotation;
+	// Wolfram clients are built per request, see wolframClientFor)
+	currentCredentials.Store(buildCredentials(config.SlackAccessToken, config.WitAccessToken, config.WolframAppID))
+	rtmDialer = dialer
+	go checkWitEntities("startup")
+	resolveBotUserID()
+	startHealthStatus()
+	startBurnRateAlerts()
+	startWeeklyDigest()
+	startWorkerPool()
+	startRTMWatchdog()
+
+	// Instantiating real-time messaging with our Slackbot, or Socket Mode for apps with an app-level token (RTM events
+	// are then never delivered, as reading a nil channel blocks)
+	var realTimeMSG *slack.RTM
+	var rtmEvents chan slack.RTMEvent
+	if usesSocketMode() {
+		go runSocketMode()
+	} else {
+		realTimeMSG = slackAPI().NewRTM(slack.RTMOptionDialer(rtmDialer))
+		rtmEvents = realTimeMSG.IncomingEvents
+
+		// Wrapping our RTM connection in a concurrent Go Routine
+		go realTimeMSG.ManageConnection()
+	}
 
-	// Instantiating real-time messaging with our Slackbot
-	realTimeMSG := slackClient.NewRTM()
+	// Running scheduled queries as they come due, including any persisted before a restart
+	startScheduler()
+	startMaintenanceScheduler()
 
-	// Wrapping our RTM connection in a concurrent Go Routine
-	go realTimeMSG.ManageConnection()
+	// Listening for termination signals to shut down gracefully
+	shutdownSignals := make(chan os.Signal, 1)
+	signal.Notify(shutdownSignals, os.Interrupt, syscall.SIGTERM)
+	rotationSignals := make(chan os.Signal, 1)
+	signal.Notify(rotationSignals, syscall.SIGUSR1)
 
 	// Checking for real-time messages hitting the Slackbot
-	for msg := range realTimeMSG.IncomingEvents {
-		switch event := msg.Data.(type) {
-		case *slack.MessageEvent:
-			if len(event.BotID) == 0 {
-				// Handling real-time messaging event via Go Routine
-				go handleMSGEvent(event)
+	for {
+		select {
+		case msg := <-rtmEvents:
+			handleIncomingEvent(msg)
+		case msg := <-socketModeEvents:
+			handleIncomingEvent(msg.event)
+			if msg.ack != nil {
+				// Acknowledging off the loop, now the event's processed record is written
+				go msg.ack()
 			}
+		case <-rotationSignals:
+			go rotateCredentials("SIGUSR1")
+		case next := <-rtmSwitches:
+			// Moving to a rotated token's connection, already up, and letting the old one go
+			previous := realTimeMSG
+			realTimeMSG, rtmEvents = next, next.IncomingEvents
+			go previous.Disconnect()
+		case sig := <-shutdownSignals:
+			log.Printf("Received %v signal, shutting down.", sig)
+			announceShutdown()
+			if realTimeMSG != nil {
+				realTimeMSG.Disconnect()
+			} else {
+				stopSocketMode()
+			}
+
+			// Letting questions already being answered finish, but not forever
+			drained := drainForShutdown(config.ShutdownDrainTimeout)
+			if err := store.flush(); err != nil {
+				log.Printf("STORE ERROR: Unable to flush data file on shutdown.\nError Details: %v", err)
+			}
+			if !drained {
+				os.Exit(1)
+			}
+			recordCleanShutdown()
+			return
+		}
+	}
+}
+
+// Global function for handling one Slack event, arriving over RTM or Socket Mode alike
+func handleIncomingEvent(msg slack.RTMEvent) {
+	noteRTMActivity()
+	if noteIncomingEvent() && !isPriorityEvent(msg) {
+		metrics.inc("wolfy_flood_dropped_events_total", "type", msg.Type)
+		return
+	}
+	switch event := msg.Data.(type) {
+	case *slack.ConnectingEvent:
+		setConnectionState("connecting")
+	case *slack.DisconnectedEvent:
+		setConnectionState("disconnected")
+	case *slack.ConnectedEvent:
+		setConnectionState("connected")
+		if event.Info != nil && event.Info.User != nil {
+			setBotUserID(event.Info.User.ID)
+		}
+		if event.ConnectionCount == 1 {
+			announceStartup()
+		}
+	case *slack.MemberJoinedChannelEvent:
+		go handleMemberJoinedChannel(event)
+	case *slack.ReactionAddedEvent:
+		if markReactionProcessed(event) {
+			go handleBookmarkReaction(event)
+			go handleCancelReaction(event)
+			go handleFeedbackReaction(event)
 		}
+	case *slack.ChannelLeftEvent:
+		go retireChannel(event.Channel, "left", false)
+	case *slack.GroupLeftEvent:
+		go retireChannel(event.Channel, "left", false)
+	case *slack.ChannelArchiveEvent:
+		go retireChannel(event.Channel, "archived", true)
+	case *slack.GroupArchiveEvent:
+		go retireChannel(event.Channel, "archived", true)
+	case *slack.ChannelDeletedEvent:
+		go retireChannel(event.Channel, "deleted", true)
+	case *slack.MessageEvent:
+		if isOwnMessage(event) {
+			noteOwnMessage(event)
+		} else if !acceptsChannel(event) {
+			// Leaving channels outside a staging instance's test channel to production
+			return
+		} else if acceptsSender(event) && isAddressedToBot(event) && markEventProcessed(messageEventKey(event)) {
+			// Handling real-time messaging event via the worker pool, behind the asker's previous rapid-fire message
+			dispatchGroupedMessage(event)
+		} else if acceptsSender(event) && len(event.BotID) == 0 {
+			go offerProactiveAnswer(event)
+		}
+	default:
+		recordUnhandledEvent(msg)
 	}
 }
 
 // Global function for handling real-time messaging events via the Slackbot
 func handleMSGEvent(event *slack.MessageEvent) {
 	// fmt.Printf("%v\n", event) 	// Quick & dirty debugger code
+	if !claimMessage(event) {
+		// Leaving the message to the replica that claimed it, and moving the asker's next rapid-fire message along
+		finishGroupedMessage(event)
+		return
+	}
+	defer markMessageAnswered(messageEventKey(event))
+	defer finishGroupedMessage(event)
+	noteConversationTeam(event)
+	raw := event.Msg.Text
+	event.Msg.Text = normalizeQueryText(stripCodeFormatting(stripTriggerPrefix(event.Channel, stripBotMention(event.Msg.Text))))
+	attachThreadParent(event)
+	attachThreadContext(event)
+	attachGroupContext(event)
+
+	// Counting every external API call made while answering (for cost estimates) and tracing the route for opted-in maintainers
+	ctx := withStageTimings(withRouteTrace(withRoutingTable(withFlagScope(withAPIUsage(context.Background()), event)), event))
+	defer sendRouteTrace(ctx, event)
+	ctx = welcomeFirstContact(ctx, event)
+	ctx = attachQuotedQuestion(ctx, event)
+	ctx = detectSimpleModifier(ctx, event)
+
+	// Bounding the whole message (classification, fallbacks, and handlers) by one deadline every sub-call derives from
+	if config.MessageTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.MessageTimeout)
+		defer cancel()
+	}
+
+	// Prompting a bare mention or trigger prefix for a question rather than classifying empty text
+	if isEmptyMention(raw, event.Msg.Text) {
+		traceStep(ctx, "mentioned with no question")
+		metrics.inc("wolfy_intents_total", "intent", "empty_mention")
+		postText(answerDestination(event), scopedMessage(ctx, "empty_mention"))
+		return
+	}
+
+	for _, interceptor := range messageInterceptors {
+		if !handlerEnabled(interceptor.name) && interceptor.name != "admin_command" {
+			// Passing switched-off commands over, so the message is answered as an ordinary question (admin commands
+			// check for themselves, keeping "!handler" so they can be switched back on)
+			continue
+		}
+		if interceptor.handle(event) {
+			traceStep(ctx, "handled locally by %s", interceptor.name)
+			return
+		}
+	}
+
+	// Thanking the bot needs no answer from Wolfram|Alpha, only a "you're welcome"
+	if handleAcknowledgment(ctx, event, raw) {
+		return
+	}
+
+	// Tracking the question from here on so its asker can cancel it ("wolfy cancel" or :x: on the placeholder)
+	ctx, request := trackInFlight(ctx, event)
+	defer request.finish()
+	defer logSlowQuery(ctx, event)
+
+	// Answering pasted lists item by item, before compound splitting can mistake them for "X and Y" questions
+	if items := splitQuestionList(event.Msg.Text); len(items) > 1 {
+		traceStep(ctx, "split a list into %d questions", len(items))
+		answerQuestionList(ctx, event, items)
+		return
+	}
+
+	if flagEnabled(ctx, "compound_questions") {
+		if parts := splitCompoundQuestion(event.Msg.Text); len(parts) > 1 {
+			traceStep(ctx, "split into %d sub-questions: %q", len(parts), parts)
+			answerCompoundQuestion(ctx, event, parts)
+			return
+		}
+	}
+
+	// Comparisons skip Wit.ai, whose single search query would collapse the subjects into one
+	if _, ok := parseComparison(event.Msg.Text); ok {
+		traceStep(ctx, "recognized a comparison")
+		metrics.inc("wolfy_intents_total", "intent", comparisonEntityKey)
+		dispatchEntity(ctx, event, comparisonEntityKey, wit.MessageEntity{Value: event.Msg.Text, Confidence: 1})
+		return
+	}
+
+	// Messages matching an external handler's pattern skip Wit.ai too
+	if name, ok := matchExternalHandler(event.Msg.Text); ok {
+		traceStep(ctx, "matched external handler %s", name)
+		metrics.inc("wolfy_intents_total", "intent", name)
+		dispatchEntity(ctx, event, name, wit.MessageEntity{Value: event.Msg.Text, Confidence: 1})
+		return
+	}
+
 	textRTM := event.Msg.Text
-	res, err := witClient.Message(textRTM)
+	endClassify := timeStage(ctx, "classify")
+	optimalEntityKey, optimalEntity, err := classifyMessage(ctx, textRTM)
+	endClassify()
+	if requestCancelled(ctx) {
+		traceStep(ctx, "cancelled by the asker during classification")
+		return
+	}
 
-	// Error handling for response retrieval failure
+	// Error handling for response retrieval failure, telling the user what went wrong and the code to quote
 	if err != nil {
-		log.Printf("MESSAGE HANDLING ERROR: Unable to get response from Wit.ai server.\nError Details: %v", err)
+		if logFailure(ctx, err, "classifying the message") == errBackendTimeout {
+			metrics.inc("wolfy_message_timeouts_total")
+		}
+		postTransientText(event.User, failureReply(ctx, err))
 		return
 	}
 
-	// Initializing variables to hold ideal message characteristic entity for NLP
+	// Responding to user based on characterized ideal MSG entity
+	sendUserResponse(ctx, event, optimalEntityKey, optimalEntity)
+}
+
+// Global function for characterizing a message via Wit.ai, returning its ideal entity key and entity
+func classifyMessage(ctx context.Context, textRTM string) (string, wit.MessageEntity, error) {
+	// Configured synonyms take precedence over Wit.ai, which isn't asked at all when one matches
+	if entityKey, entity, phrase, ok := matchIntentSynonym(textRTM); ok {
+		metrics.inc("wolfy_intent_synonym_hits_total", "intent", entityKey)
+		traceStep(ctx, "synonym %q routes to %s; skipping wit.ai", phrase, entityKey)
+		return entityKey, entity, nil
+	}
+
+	res, err := witMessage(ctx, textRTM)
+	if !requestCancelled(ctx) {
+		noteStageResult("classify", err != nil && err != errExternalAPIsDisabled)
+	}
+	if err == errRateLimited {
+		if entityKey, entity, ok := witRateLimitFallback(textRTM); ok {
+			traceStep(ctx, "wit.ai rate limited; falling back to %s", entityKey)
+			return entityKey, entity, nil
+		}
+		traceStep(ctx, "wit.ai rate limited; no fallback")
+		return "", wit.MessageEntity{}, err
+	} else if err != nil {
+		traceStep(ctx, "wit.ai failed: %v", err)
+		if ctx.Err() == nil && classifyFailure(err) == errInternal {
+			err = wrapFailure(errNLPUnavailable, err)
+		}
+		return "", wit.MessageEntity{}, err
+	}
+
+	for entityKey, entityValueMap := range res.Entities {
+		for _, entity := range entityValueMap {
+			traceStep(ctx, "wit.ai entity %s=%v (confidence %.3f)", entityKey, entity.Value, entity.Confidence)
+		}
+	}
+	optimalEntityKey, optimalEntity := selectEntity(res.Entities, config.IntentPriority)
+	traceStep(ctx, "chose %s (threshold %.2f)", intentLabel(optimalEntityKey), config.ConfidenceThreshold)
+	if topEntityKey, topEntity := mostConfidentEntity(res.Entities, config.IntentPriority, 0); topEntityKey != "" {
+		recordConfidence(topEntityKey, topEntity.Confidence, optimalEntityKey != "")
+	}
+	return optimalEntityKey, optimalEntity, nil
+}
+
+// Global function for picking the most confident entity above the threshold, breaking exact ties
+// by the configured intent priority, then alphabetically, so the choice never depends on map order
+func selectEntity(entities map[string][]wit.MessageEntity, priority []string) (string, wit.MessageEntity) {
+	return mostConfidentEntity(entities, priority, config.ConfidenceThreshold)
+}
+
+// Global function for picking the most confident entity above a given floor, with the same tie-breaks as selectEntity
+func mostConfidentEntity(entities map[string][]wit.MessageEntity, priority []string, floor float64) (string, wit.MessageEntity) {
+	rank := func(entityKey string) int {
+		for i, name := range priority {
+			if name == entityKey {
+				return i
+			}
+		}
+		return len(priority)
+	}
+
+	entityKeys := make([]string, 0, len(entities))
+	for entityKey := range entities {
+		entityKeys = append(entityKeys, entityKey)
+	}
+	sort.Slice(entityKeys, func(i, j int) bool {
+		if rank(entityKeys[i]) != rank(entityKeys[j]) {
+			return rank(entityKeys[i]) < rank(entityKeys[j])
+		}
+		return entityKeys[i] < entityKeys[j]
+	})
+
+	// Visiting keys in tie-break order, so the strict comparison keeps the preferred entity on a tie
 	var (
 		optimalEntityKey string
 		optimalEntity    wit.MessageEntity
 	)
-
-	// Mapping over all message entities to grab ideal entity for NLP based on highest confidence
-	for entityKey, entityValueMap := range res.Entities {
-		for _, entity := range entityValueMap {
-			if (entity.Confidence > optimalEntityConfidenceThreshold) && (entity.Confidence > optimalEntity.Confidence) {
+	for _, entityKey := range entityKeys {
+		for _, entity := range entities[entityKey] {
+			if (entity.Confidence > floor) && (entity.Confidence > optimalEntity.Confidence) {
 				optimalEntityKey = entityKey
 				optimalEntity = entity
 			}
 		}
 	}
-
-	// Responding to user based on characterized ideal MSG entity
-	sendUserResponse(event, optimalEntityKey, optimalEntity)
+	return optimalEntityKey, optimalEntity
 }
 
 // Global function for sending replies to user based on RTM NLP characterization
-func sendUserResponse(event *slack.MessageEvent, optimalEntityKey string, optimalEntity wit.MessageEntity) {
-	switch optimalEntityKey {
-	case "greetings":
-		slackClient.PostMessage(
-			event.User,
-			slack.MsgOptionText("Hello! I am WolfyBot and I am here to answer your questions. :-)", false),
-			slack.MsgOptionAsUser(true),
-		)
-		return
-	case "wolfram_search_query":
-		res, err := wolframClient.GetShortAnswerQuery(optimalEntity.Value.(string), wolfram.Metric, 1000)
-		if err == nil {
-			if res == "Wolfram|Alpha did not understand your input" {
-				slackClient.PostMessage(
-					event.User,
-					slack.MsgOptionText("Oops, looks like I didn't quite understand that! :-O", false),
-					slack.MsgOptionAsUser(true),
-				)
-			} else if res == "No short answer available" {
-				slackClient.PostMessage(
-					event.User,
-					slack.MsgOptionText("Whoops! I'm still learning the ropes and while I got your answer, it's a little long for me to communicate. :-P", false),
-					slack.MsgOptionAsUser(true),
-				)
-			} else {
-				slackClient.PostMessage(
-					event.User,
-					slack.MsgOptionText(res, false),
-					slack.MsgOptionAsUser(true),
-				)
-			}
-			return
+func sendUserResponse(ctx context.Context, event *slack.MessageEvent, optimalEntityKey string, optimalEntity wit.MessageEntity) {
+	metrics.inc("wolfy_intents_total", "intent", intentLabel(optimalEntityKey))
+	dispatchEntity(ctx, event, optimalEntityKey, resolveEntityValue(ctx, event, optimalEntityKey, optimalEntity))
+}
+
+// Global function for naming an entity key in metrics, grouping unclassified messages under "none"
+func intentLabel(entityKey string) string {
+	if entityKey == "" {
+		return "none"
+	}
+	return entityKey
+}
+
+// Global function for building a Wolfram client using the asker's own App ID when one is configured
+func wolframClientFor(event *slack.MessageEvent) *wolfram.Client {
+	// Preferring a user-specific key, then a team-wide key, then the shared default
+	for _, owner := range []string{event.User, event.Team} {
+		if appID, ok := config.WolframAppIDOverrides[owner]; ok && owner != "" && appID != "" {
+			return &wolfram.Client{AppID: appID}
 		}
-		log.Printf("ERROR: Unable to retrieve relevant data from the Wolfram database. Error Msg: %v", err)
 	}
+	return &wolfram.Client{AppID: wolframAppID()}
+}
 
-	slackClient.PostMessage(
-		event.User,
-		slack.MsgOptionText("WARNING: User input is unclear. :-/ Try clarifying your question?", false),
-		slack.MsgOptionAsUser(true),
-	)
+// Global function for posting a message as the Slackbot through the channel's outbound queue, waiting for the channel and timestamp it landed at
+func postMessage(channelID string, options ...slack.MsgOption) (string, string, error) {
+	result := make(chan outboundResult, 1)
+	enqueuePost(channelID, outboundPost{options: options, result: result})
+	posted := <-result
+	return posted.channel, posted.timestamp, posted.err
+}
+
+// Global function for queueing a plain-text message as the Slackbot without waiting for it to be sent
+func postText(channelID string, text string) {
+	enqueuePost(channelID, outboundPost{options: []slack.MsgOption{messageText(text)}})
+}
+
+// Global function for replacing a placeholder message with its final content, posting a new message if the update fails
+func replaceMessage(channelID string, timestamp string, options ...slack.MsgOption) (string, string) {
+	if timestamp != "" {
+		respChannel, respTimestamp, _, err := slackAPI().UpdateMessage(channelID, timestamp, append(brandedOptions(channelID, "chat.update", options), slack.MsgOptionAsUser(true))...)
+		if err == nil {
+			return respChannel, respTimestamp
+		}
+		metrics.inc("wolfy_message_update_failures_total")
+		log.Printf("ERROR: Unable to update message %s in %s. Error Msg: %v", timestamp, channelID, err)
+	}
+	respChannel, respTimestamp, _ := postMessage(channelID, options...)
+	return respChannel, respTimestamp
 }
@@ -0,0 +1,51 @@
+//////////////////////////////////////////////////
+// Main Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing entity selection
+import (
+	"testing" // Permits Go testing
+
+	wit "github.com/christianrondeau/go-wit" // External Wit.ai API
+)
+
+// Test for breaking exact confidence ties by the configured priority, then alphabetically, whatever the map order
+func TestSelectEntityTies(t *testing.T) {
+	withConfig(t, func(c *Config) { c.ConfidenceThreshold = 0.5 })
+	tied := map[string][]wit.MessageEntity{
+		"wolfram_search_query": {{Value: "weather", Confidence: 0.8}},
+		"greetings":            {{Value: "hi", Confidence: 0.8}},
+		"reminder":             {{Value: "tomorrow", Confidence: 0.8}},
+	}
+	cases := []struct {
+		priority []string
+		want     string
+	}{
+		{nil, "greetings"},
+		{[]string{"wolfram_search_query"}, "wolfram_search_query"},
+		{[]string{"reminder", "wolfram_search_query"}, "reminder"},
+		{[]string{"unrelated"}, "greetings"},
+	}
+	for _, c := range cases {
+		// Repeating each selection, since map iteration order changes from one range to the next
+		for i := 0; i < 20; i++ {
+			if got, _ := selectEntity(tied, c.priority); got != c.want {
+				t.Fatalf("selectEntity with priority %v = %q; want %q", c.priority, got, c.want)
+			}
+		}
+	}
+
+	// A strictly more confident entity still wins over the priority list
+	tied["greetings"][0].Confidence = 0.9
+	if got, _ := selectEntity(tied, []string{"wolfram_search_query"}); got != "greetings" {
+		t.Errorf("selectEntity with a more confident greeting = %q; want greetings", got)
+	}
+	// Entities at or under the threshold are never chosen
+	if got, _ := selectEntity(map[string][]wit.MessageEntity{"greetings": {{Confidence: 0.5}}}, nil); got != "" {
+		t.Errorf("selectEntity at the threshold = %q; want none", got)
+	}
+}
@@ -0,0 +1,209 @@
+//////////////////////////////////////////////////
+// Maintenance Windows Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for entering and leaving maintenance mode on a schedule planned in advance
+import (
+	"fmt"         // Permits formatting of announcements and schedules
+	"log"         // Permits console logging
+	"sort"        // Permits ordering of windows by start
+	"strings"     // Permits parsing of window specs
+	"sync"        // Permits concurrency-safe access to the active window
+	"sync/atomic" // Permits lock-free reads of maintenance mode
+	"time"        // Permits window times and the schedule check
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constant holding how often the schedule is checked for a window starting or ending
+const maintenanceCheckInterval = 30 * time.Second
+
+// Global layouts accepted for window times: with an explicit offset, or local to the default timezone
+var maintenanceTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02 15:04"}
+
+// Global struct holding one maintenance window
+type maintenanceWindow struct {
+	start time.Time
+	end   time.Time
+}
+
+// Global maintenance state: whether it is on (1) for message handling, and the window it is on for
+var (
+	maintenanceActive int32
+	activeWindow      maintenanceWindow
+	activeWindowMu    sync.Mutex
+)
+
+// Global function for parsing a window time, reading times without an offset in the given location
+func parseMaintenanceTime(value string, location *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range maintenanceTimeLayouts {
+		if parsed, err := time.ParseInLocation(layout, value, location); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a time like 2006-01-02T15:04 or 2006-01-02T15:04:00-07:00", value)
+}
+
+// Global function for parsing "start/end" window specs, sorted by start with overlapping or touching windows merged
+// into one, so back-to-back windows enter and leave maintenance once
+func parseMaintenanceWindows(specs []string, location *time.Location) ([]maintenanceWindow, error) {
+	var windows []maintenanceWindow
+	for _, spec := range specs {
+		parts := strings.Split(spec, "/")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%q is not a start/end pair", spec)
+		}
+		start, err := parseMaintenanceTime(parts[0], location)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseMaintenanceTime(parts[1], location)
+		if err != nil {
+			return nil, err
+		}
+		if !end.After(start) {
+			return nil, fmt.Errorf("%q ends before it starts", spec)
+		}
+		windows = append(windows, maintenanceWindow{start: start, end: end})
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i].start.Before(windows[j].start) })
+
+	merged := windows[:0]
+	for _, window := range windows {
+		if last := len(merged) - 1; last >= 0 && !window.start.After(merged[last].end) {
+			if window.end.After(merged[last].end) {
+				merged[last].end = window.end
+			}
+			continue
+		}
+		merged = append(merged, window)
+	}
+	return merged, nil
+}
+
+// Global function for loading the configured windows (already checked by config validation)
+func configuredMaintenanceWindows() []maintenanceWindow {
+	location, err := time.LoadLocation(config.DefaultTimezone)
+	if err != nil {
+		location = time.UTC
+	}
+	windows, err := parseMaintenanceWindows(config.MaintenanceWindows, location)
+	if err != nil {
+		log.Printf("CONFIG ERROR: Ignoring maintenance windows.\nError Details: %v", err)
+		return nil
+	}
+	return windows
+}
+
+// Global function for checking whether the bot is in a maintenance window
+func inMaintenance() bool {
+	return atomic.LoadInt32(&maintenanceActive) == 1
+}
+
+// Global function for checking the schedule on startup and then periodically, entering and leaving maintenance mode
+// as windows start and end
+func startMaintenanceScheduler() {
+	windows := configuredMaintenanceWindows()
+	if len(windows) == 0 {
+		return
+	}
+	checkMaintenanceWindows(windows, time.Now())
+	go func() {
+		for now := range time.Tick(maintenanceCheckInterval) {
+			checkMaintenanceWindows(windows, now)
+		}
+	}()
+}
+
+// Global function for entering maintenance when a window covers now, and leaving it once none does
+func checkMaintenanceWindows(windows []maintenanceWindow, now time.Time) {
+	for _, window := range windows {
+		if !now.Before(window.start) && now.Before(window.end) {
+			if !inMaintenance() {
+				setMaintenance(window, true)
+			}
+			return
+		}
+	}
+	if inMaintenance() {
+		setMaintenance(maintenanceWindow{}, false)
+	}
+}
+
+// Global function for switching maintenance mode, announcing the change to the maintenance channel (else the admin channel)
+func setMaintenance(window maintenanceWindow, active bool) {
+	activeWindowMu.Lock()
+	previous := activeWindow
+	activeWindow = window
+	activeWindowMu.Unlock()
+
+	value := int32(0)
+	if active {
+		value = 1
+	}
+	atomic.StoreInt32(&maintenanceActive, value)
+	metrics.set(int64(value), "wolfy_maintenance_active")
+
+	var announcement string
+	if active {
+		log.Printf("MAINTENANCE: Entering the window ending %s.", window.end.Format(time.RFC3339))
+		announcement = fmt.Sprintf(":construction: WolfyBot is entering scheduled maintenance until %s. Questions will get a short notice until then.", formatMaintenanceTime(window.end, window.end.Location()))
+	} else {
+		log.Printf("MAINTENANCE: Leaving the window that ended %s.", previous.end.Format(time.RFC3339))
+		announcement = ":white_check_mark: Scheduled maintenance is over - WolfyBot is answering questions again."
+	}
+	channel := config.MaintenanceChannel
+	if channel == "" {
+		channel = config.AdminChannel
+	}
+	if channel != "" {
+		postText(channel, announcement)
+	}
+}
+
+// Global function for formatting a window time in a timezone, with Slack's date token so readers see their own time
+func formatMaintenanceTime(at time.Time, location *time.Location) string {
+	fallback := at.In(location).Format("Mon Jan 2 3:04 PM MST")
+	return fmt.Sprintf("<!date^%d^{date_short_pretty} at {time}|%s>", at.Unix(), fallback)
+}
+
+// Global function for answering every question with the maintenance notice during a window, reporting whether the
+// message was consumed (admin commands run ahead of this, so operators keep control)
+func handleMaintenance(event *slack.MessageEvent) bool {
+	if !inMaintenance() {
+		return false
+	}
+	activeWindowMu.Lock()
+	window := activeWindow
+	activeWindowMu.Unlock()
+
+	metrics.inc("wolfy_intents_total", "intent", "maintenance")
+	postTransientText(answerDestination(event), fmt.Sprintf(":construction: I'm down for scheduled maintenance until %s. Please ask again then!", formatMaintenanceTime(window.end, userLocation(event.User))))
+	return true
+}
+
+// Admin command listing the current and upcoming maintenance windows
+func runAdminMaintenance(event *slack.MessageEvent, args []string) string {
+	location := userLocation(event.User)
+	now := time.Now()
+	var lines []string
+	for _, window := range configuredMaintenanceWindows() {
+		if !window.end.After(now) {
+			continue
+		}
+		line := fmt.Sprintf("• %s to %s", window.start.In(location).Format("Mon Jan 2 3:04 PM MST"), window.end.In(location).Format("Mon Jan 2 3:04 PM MST"))
+		if !now.Before(window.start) {
+			line += " _(in progress)_"
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return "No maintenance windows are scheduled."
+	}
+	return "*Scheduled maintenance windows:*\n" + strings.Join(lines, "\n")
+}
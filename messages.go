@@ -0,0 +1,61 @@
+//////////////////////////////////////////////////
+// Message Catalog Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for user-facing message text that operators can reword without a rebuild
+import (
+	"sync" // Permits concurrency-safe catalog access
+)
+
+// Global operator overrides of message text by key, applied on top of every personality pack
+var (
+	messageCatalogMu sync.RWMutex
+	messageOverrides = map[string]string{}
+)
+
+// Global function for loading message overrides from the configured JSON file ({"key": "text"}), swapping them in whole
+// and returning the problems found (overrides with one are left out, keeping pack wording)
+func loadMessageCatalog() []catalogProblem {
+	overrides, problems := map[string]string{}, []catalogProblem(nil)
+	if config.MessagesFile != "" {
+		var loaded map[string]string
+		if loaded, problems = readMessageOverrides(config.MessagesFile); loaded != nil {
+			overrides = loaded
+		}
+	}
+	logCatalogProblems(problems)
+
+	messageCatalogMu.Lock()
+	messageOverrides = overrides
+	messageCatalogMu.Unlock()
+	return problems
+}
+
+// Global function for looking up a message for a scope: the channel language's translation, else an operator override,
+// else the scope's personality pack wording (the classic pack's for optional keys the pack leaves out)
+func packMessage(scope flagScope, key string) string {
+	if text, ok := localizedMessage(channelLanguage(scope.channel), key); ok {
+		return text
+	}
+	messageCatalogMu.RLock()
+	override, ok := messageOverrides[key]
+	messageCatalogMu.RUnlock()
+	if ok {
+		return override
+	}
+	if text := personalityFor(scope).Messages[key]; text != "" {
+		return text
+	}
+	personalityPacksMu.RLock()
+	defer personalityPacksMu.RUnlock()
+	return personalityPacks["classic"].Messages[key]
+}
+
+// Global function for looking up a catalog message by key in the default personality pack
+func catalogMessage(key string) string {
+	return packMessage(flagScope{}, key)
+}
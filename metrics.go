@@ -0,0 +1,219 @@
+//////////////////////////////////////////////////
+// Metrics Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for in-process counters and the metrics endpoint
+import (
+	"fmt"      // Permits string formatting of metric lines
+	"io"       // Permits writing metrics to arbitrary outputs
+	"log"      // Permits console logging
+	"net/http" // Permits serving the metrics endpoint
+	"sort"     // Permits stable ordering of metric output
+	"strings"  // Permits string manipulation of metric keys
+	"sync"     // Permits concurrency-safe counter updates
+)
+
+// Global struct holding all counters (and the few gauges and histograms) recorded by the Slackbot
+type metricRegistry struct {
+	mu         sync.Mutex
+	counters   map[string]int64
+	gauges     map[string]bool
+	histograms map[string]*metricHistogram
+}
+
+// Global struct holding one labelled histogram: its bucket upper bounds, per-bucket (non-cumulative) counts, and totals
+type metricHistogram struct {
+	name   string
+	labels string
+	bounds []float64
+	counts []int64
+	sum    float64
+	count  int64
+}
+
+// Global registry shared by every instrumented code path
+var metrics = &metricRegistry{counters: map[string]int64{}, gauges: map[string]bool{}, histograms: map[string]*metricHistogram{}}
+
+// Global replacer escaping label values per the Prometheus text format
+var metricLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Global function for building a metric key from a name and label key/value pairs
+func metricKey(name string, labels ...string) string {
+	if len(labels) < 2 {
+		return name
+	}
+
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, labels[i], metricLabelEscaper.Replace(labels[i+1])))
+	}
+	return name + "{" + strings.Join(pairs, ",") + "}"
+}
+
+// Method for incrementing a counter by one
+func (registry *metricRegistry) inc(name string, labels ...string) {
+	registry.add(1, name, labels...)
+}
+
+// Method for incrementing a counter by an arbitrary amount
+func (registry *metricRegistry) add(delta int64, name string, labels ...string) {
+	key := metricKey(name, labels...)
+
+	registry.mu.Lock()
+	registry.counters[key] += delta
+	registry.mu.Unlock()
+}
+
+// Method for setting a gauge to an absolute value
+func (registry *metricRegistry) set(value int64, name string, labels ...string) {
+	key := metricKey(name, labels...)
+
+	registry.mu.Lock()
+	registry.counters[key] = value
+	registry.gauges[name] = true
+	registry.mu.Unlock()
+}
+
+// Method for recording an observation in a histogram with the given bucket upper bounds (fixed on first use)
+func (registry *metricRegistry) observe(value float64, bounds []float64, name string, labels ...string) {
+	key := metricKey(name, labels...)
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	histogram, ok := registry.histograms[key]
+	if !ok {
+		inner := strings.TrimSuffix(strings.TrimPrefix(metricKey("", labels...), "{"), "}")
+		histogram = &metricHistogram{name: name, labels: inner, bounds: bounds, counts: make([]int64, len(bounds))}
+		registry.histograms[key] = histogram
+	}
+	for i, bound := range histogram.bounds {
+		if value <= bound {
+			histogram.counts[i]++
+			break
+		}
+	}
+	histogram.sum += value
+	histogram.count++
+	queueStatsDObservation(key, value)
+}
+
+// Method for reporting whether a metric name is a gauge rather than a counter
+func (registry *metricRegistry) isGauge(name string) bool {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	return registry.gauges[name]
+}
+
+// Method for copying the current counter values
+func (registry *metricRegistry) snapshot() map[string]int64 {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	values := make(map[string]int64, len(registry.counters))
+	for key, value := range registry.counters {
+		values[key] = value
+	}
+	return values
+}
+
+// Method for listing counter keys in a stable order
+func (registry *metricRegistry) sortedKeys() ([]string, map[string]int64) {
+	values := registry.snapshot()
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, values
+}
+
+// Method for writing all counters in the Prometheus text exposition format
+func (registry *metricRegistry) writePrometheus(w io.Writer) {
+	keys, values := registry.sortedKeys()
+
+	lastName := ""
+	for _, key := range keys {
+		name := key
+		if i := strings.Index(key, "{"); i >= 0 {
+			name = key[:i]
+		}
+		if name != lastName {
+			metricType := "counter"
+			if registry.isGauge(name) {
+				metricType = "gauge"
+			}
+			fmt.Fprintf(w, "# TYPE %s %s\n", name, metricType)
+			lastName = name
+		}
+		fmt.Fprintf(w, "%s %d\n", key, values[key])
+	}
+	registry.writeHistograms(w)
+}
+
+// Method for writing all histograms as cumulative buckets plus their sum and count
+func (registry *metricRegistry) writeHistograms(w io.Writer) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	keys := make([]string, 0, len(registry.histograms))
+	for key := range registry.histograms {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	lastName := ""
+	for _, key := range keys {
+		histogram := registry.histograms[key]
+		if histogram.name != lastName {
+			fmt.Fprintf(w, "# TYPE %s histogram\n", histogram.name)
+			lastName = histogram.name
+		}
+		prefix := ""
+		if histogram.labels != "" {
+			prefix = histogram.labels + ","
+		}
+		cumulative := int64(0)
+		for i, bound := range histogram.bounds {
+			cumulative += histogram.counts[i]
+			fmt.Fprintf(w, "%s_bucket{%sle=\"%g\"} %d\n", histogram.name, prefix, bound, cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket{%sle=\"+Inf\"} %d\n", histogram.name, prefix, histogram.count)
+		fmt.Fprintf(w, "%s_sum%s %g\n", histogram.name, strings.TrimPrefix(key, histogram.name), histogram.sum)
+		fmt.Fprintf(w, "%s_count%s %d\n", histogram.name, strings.TrimPrefix(key, histogram.name), histogram.count)
+	}
+}
+
+// Global function for serving the metrics endpoint (and the dashboard, interactivity, and slash command routes, when
+// their secrets are set) when an address is configured, and pushing to StatsD when that exporter is selected
+func startMetricsServer() {
+	startStatsDExporter()
+	if config.MetricsAddr == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	if prometheusEnabled() {
+		registerRoute(mux, httpRoute{path: "/metrics", auth: routeInternal, token: config.HTTPToken, handle: func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+			metrics.writePrometheus(w)
+		}})
+	}
+	registerRoute(mux, httpRoute{path: "/ready", auth: routePublic, handle: serveReadiness})
+	if config.DashboardToken != "" {
+		registerRoute(mux, httpRoute{path: "/dashboard", auth: routeInternal, token: config.DashboardToken, handle: serveDashboard})
+	}
+	if config.SlackSigningSecret != "" {
+		registerRoute(mux, httpRoute{path: interactivityPath, auth: routeSlack, handle: serveInteractivity})
+		registerRoute(mux, httpRoute{path: slashCommandPath, auth: routeSlack, handle: serveSlashCommand})
+	}
+
+	go func() {
+		if err := newHTTPServer(config.MetricsAddr, mux).ListenAndServe(); err != nil {
+			log.Printf("METRICS ERROR: Metrics endpoint stopped.\nError Details: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,233 @@
+//////////////////////////////////////////////////
+// Number Formatting Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for reformatting numbers in answers to match the asker's locale
+import (
+	"fmt"          // Permits string formatting of locale sources
+	"log"          // Permits console logging
+	"regexp"       // Permits number detection
+	"strconv"      // Permits year range checks
+	"strings"      // Permits string building and normalization
+	"unicode"      // Permits classification of neighbouring characters
+	"unicode/utf8" // Permits rune-level inspection around matches
+)
+
+// Global struct holding a locale's decimal mark and thousands separator
+type numberStyle struct {
+	decimal string
+	group   string
+}
+
+// Global style used when a locale is unknown (and for English)
+var defaultNumberStyle = numberStyle{decimal: ".", group: ","}
+
+// Global number styles by language, with region-specific exceptions looked up first, and the locales grouping digits past
+// the first thousand in pairs as in South Asia ("12,34,567" - lakhs and crores)
+var (
+	languageNumberStyles = map[string]numberStyle{
+		"de": {",", "."}, "es": {",", "."}, "it": {",", "."}, "nl": {",", "."}, "pt": {",", "."},
+		"da": {",", "."}, "tr": {",", "."}, "id": {",", "."}, "el": {",", "."}, "ro": {",", "."},
+		"fr": {",", "\u202f"}, "ru": {",", "\u202f"}, "uk": {",", "\u202f"}, "pl": {",", "\u202f"},
+		"cs": {",", "\u202f"}, "sk": {",", "\u202f"}, "sv": {",", "\u202f"}, "nb": {",", "\u202f"},
+		"no": {",", "\u202f"}, "fi": {",", "\u202f"}, "hu": {",", "\u202f"}, "bg": {",", "\u202f"},
+	}
+	regionNumberStyles = map[string]numberStyle{
+		"de-CH": {".", "'"}, "it-CH": {".", "'"}, "fr-CH": {",", "\u202f"}, "es-MX": {".", ","}, "es-US": {".", ","},
+	}
+	lakhGroupingLocales = map[string]bool{
+		"en-IN": true, "hi": true, "bn": true, "mr": true, "gu": true, "pa": true, "ta": true, "te": true, "kn": true,
+		"ml": true, "ne": true, "ur-IN": true,
+	}
+)
+
+// Global pattern finding plain numbers (optionally US-grouped) with an optional "×10^n" exponent as Wolfram writes them
+var answerNumberPattern = regexp.MustCompile(`(\d{1,3}(?:,\d{3})+|\d+)(\.\d+)?(\s*×\s*10\^-?\d+)?`)
+
+// Global function for normalizing a locale tag to "ll-RR" form
+func normalizeLocale(locale string) string {
+	parts := strings.SplitN(strings.Replace(strings.TrimSpace(locale), "_", "-", -1), "-", 2)
+	if len(parts) == 2 {
+		return strings.ToLower(parts[0]) + "-" + strings.ToUpper(parts[1])
+	}
+	return strings.ToLower(parts[0])
+}
+
+// Global function for resolving a user's number-format locale and where that value came from:
+// explicit preference, then Slack locale, then workspace default, then en-US
+func resolveLocale(user string) (string, string) {
+	if locale := loadPreferences(user).Locale; locale != "" {
+		return normalizeLocale(locale), "your preference"
+	}
+
+	if profile, err := lookupUser(user); err == nil && profile.Locale != "" {
+		return normalizeLocale(profile.Locale), fmt.Sprintf("your Slack locale (%s)", profile.Locale)
+	} else if err != nil {
+		log.Printf("USER INFO ERROR: Unable to look up %s.\nError Details: %v", user, err)
+	}
+
+	if config.DefaultLocale != "" {
+		return normalizeLocale(config.DefaultLocale), "the workspace default"
+	}
+	return "en-US", "the built-in default"
+}
+
+// Global function for looking up the number style of a locale
+func localeNumberStyle(locale string) numberStyle {
+	locale = normalizeLocale(locale)
+	if style, ok := regionNumberStyles[locale]; ok {
+		return style
+	}
+	if style, ok := languageNumberStyles[strings.SplitN(locale, "-", 2)[0]]; ok {
+		return style
+	}
+	return defaultNumberStyle
+}
+
+// Global function for checking whether a locale groups digits in lakhs and crores
+func usesLakhGrouping(locale string) bool {
+	locale = normalizeLocale(locale)
+	return lakhGroupingLocales[locale] || lakhGroupingLocales[strings.SplitN(locale, "-", 2)[0]]
+}
+
+// Global function for checking whether a rune glues a number into a word or identifier (e.g. "CO2", "3D", "x_1")
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// Global function for checking whether the number spanning text[start:end] stands alone, rather than sitting
+// inside a date, time, version, phone number, exponent, URL, or identifier
+func isStandaloneNumber(text string, start int, end int) bool {
+	if start > 0 {
+		before, size := utf8.DecodeLastRuneInString(text[:start])
+		beforeThat, _ := utf8.DecodeLastRuneInString(text[:start-size])
+		switch {
+		case isWordRune(before) || strings.ContainsRune("^.,:/#+@&=<|", before):
+			return false
+		case before == '-' && isWordRune(beforeThat):
+			// Part of a range, date, or code such as "2024-01" or "ABC-123" (a leading minus is fine)
+			return false
+		case unicode.IsSpace(before) && unicode.IsDigit(beforeThat):
+			// One block of a space-separated sequence such as "555 123 4567"
+			return false
+		}
+	}
+	if end < len(text) {
+		after, size := utf8.DecodeRuneInString(text[end:])
+		afterThat, _ := utf8.DecodeRuneInString(text[end+size:])
+		switch {
+		case isWordRune(after) || after == '^':
+			return false
+		case (strings.ContainsRune(".,:/-", after) || unicode.IsSpace(after)) && unicode.IsDigit(afterThat):
+			return false
+		}
+	}
+	return true
+}
+
+// Global function for checking whether an integer reads as an identifier rather than a quantity (e.g. a year or "007")
+func looksLikeIdentifier(integer string) bool {
+	if len(integer) > 1 && integer[0] == '0' {
+		return true
+	}
+	if year, err := strconv.Atoi(integer); err == nil && len(integer) == 4 && year >= 1000 && year <= 2999 {
+		return true
+	}
+	return false
+}
+
+// Global function for rounding a decimal to at most maxDigits significant digits, never rounding the integer part away
+func roundSignificant(integer string, fraction string, maxDigits int) (string, string) {
+	significantInteger := strings.TrimLeft(integer, "0")
+	keep := maxDigits - len(significantInteger)
+	if significantInteger == "" {
+		// Leading zeros after the decimal mark are not significant ("0.000123")
+		keep = maxDigits + len(fraction) - len(strings.TrimLeft(fraction, "0"))
+	}
+	if keep < 0 {
+		keep = 0
+	}
+	if keep >= len(fraction) {
+		return integer, fraction
+	}
+
+	digits := []byte(integer + fraction[:keep])
+	if fraction[keep] >= '5' {
+		i := len(digits) - 1
+		for ; i >= 0 && digits[i] == '9'; i-- {
+			digits[i] = '0'
+		}
+		if i >= 0 {
+			digits[i]++
+		} else {
+			digits = append([]byte{'1'}, digits...)
+		}
+	}
+	split := len(digits) - keep
+	return string(digits[:split]), strings.TrimRight(string(digits[split:]), "0")
+}
+
+// Global function for writing a number's digits in a locale's style, grouped in lakhs and crores when asked
+func formatNumberDigits(integer string, fraction string, style numberStyle, lakh bool) string {
+	var formatted strings.Builder
+	for i, digit := range integer {
+		remaining := len(integer) - i
+		if i > 0 && (remaining == 3 || (remaining > 3 && lakh && (remaining-3)%2 == 0) || (!lakh && remaining%3 == 0)) {
+			formatted.WriteString(style.group)
+		}
+		formatted.WriteRune(digit)
+	}
+	if fraction != "" {
+		formatted.WriteString(style.decimal + fraction)
+	}
+	return formatted.String()
+}
+
+// Global function for reformatting the standalone numbers in an answer for a locale, capping long decimals at maxDigits (0 keeps all)
+func localizeNumbers(text string, locale string, maxDigits int) string {
+	style, lakh := localeNumberStyle(locale), usesLakhGrouping(locale)
+	var localized strings.Builder
+	last := 0
+	for _, match := range answerNumberPattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := match[0], match[1]
+		if !isStandaloneNumber(text, start, end) {
+			continue
+		}
+
+		grouped := text[match[2]:match[3]]
+		integer := strings.Replace(grouped, ",", "", -1)
+		fraction, exponent := "", ""
+		if match[4] >= 0 {
+			fraction = text[match[4]+1 : match[5]]
+		}
+		if match[6] >= 0 {
+			exponent = text[match[6]:match[7]]
+		}
+		if fraction == "" && exponent == "" && grouped == integer && looksLikeIdentifier(integer) {
+			continue
+		}
+
+		if maxDigits > 0 && fraction != "" {
+			integer, fraction = roundSignificant(integer, fraction, maxDigits)
+		}
+		localized.WriteString(text[last:start])
+		localized.WriteString(formatNumberDigits(integer, fraction, style, lakh))
+		localized.WriteString(exponent)
+		last = end
+	}
+	localized.WriteString(text[last:])
+	return localized.String()
+}
+
+// Global function for localizing an answer's numbers for the asker when number formatting is enabled
+func localizeAnswerNumbers(text string, user string, maxDigits int) string {
+	if !config.NumberFormatting {
+		return text
+	}
+	locale, _ := resolveLocale(user)
+	return localizeNumbers(text, locale, maxDigits)
+}
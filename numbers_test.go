@@ -0,0 +1,112 @@
+//////////////////////////////////////////////////
+// Number Formatting Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing which numbers in an answer are reformatted for the asker's locale
+import (
+	"testing" // Permits Go testing
+)
+
+// Test for quantities being written in the locale's style while years, dates, times, versions, units glued to their
+// numbers, phone numbers, identifiers, and URLs are left as they are
+func TestLocalizeNumbersDetection(t *testing.T) {
+	cases := []struct {
+		text string
+		want string
+	}{
+		{"about 1,234,567.89 people", "about 1.234.567,89 people"},
+		{"3.14159 and 2 more", "3,14159 and 2 more"},
+		{"6.022 × 10^23 per mole", "6,022 × 10^23 per mole"},
+		{"a drop of -4521.5 points", "a drop of -4.521,5 points"},
+		{"8848 m tall", "8.848 m tall"},
+		{"founded in 1998", "founded in 1998"},
+		{"between 1914 and 1918", "between 1914 and 1918"},
+		{"on 2024-01-15", "on 2024-01-15"},
+		{"on 15/01/2024", "on 15/01/2024"},
+		{"on 15.01.2024", "on 15.01.2024"},
+		{"at 12:30:45", "at 12:30:45"},
+		{"version 1.21.3", "version 1.21.3"},
+		{"a 5km run at 100mph", "a 5km run at 100mph"},
+		{"CO2 and H2O in 3D", "CO2 and H2O in 3D"},
+		{"x^2 + 10^6", "x^2 + 10^6"},
+		{"call 555-123-4567", "call 555-123-4567"},
+		{"call 555 123 4567", "call 555 123 4567"},
+		{"call +1 5551234567", "call +1 5551234567"},
+		{"agent 007", "agent 007"},
+		{"ticket #12345 and ABC-12345", "ticket #12345 and ABC-12345"},
+		{"see example.com/items/12345", "see example.com/items/12345"},
+	}
+	for _, c := range cases {
+		if got := localizeNumbers(c.text, "de-DE", 0); got != c.want {
+			t.Errorf("localizeNumbers(%q, de-DE) = %q; want %q", c.text, got, c.want)
+		}
+	}
+}
+
+// Test for each locale's decimal mark and separator, region exceptions, and the cap on long decimals
+func TestLocalizeNumbersStyles(t *testing.T) {
+	cases := []struct {
+		text      string
+		locale    string
+		maxDigits int
+		want      string
+	}{
+		{"1234567.891", "en-US", 0, "1,234,567.891"},
+		{"1234567.891", "de_de", 0, "1.234.567,891"},
+		{"1234567.891", "fr-FR", 0, "1\u202f234\u202f567,891"},
+		{"1234567.891", "de-CH", 0, "1'234'567.891"},
+		{"1234567.891", "es-MX", 0, "1,234,567.891"},
+		{"1234567.891", "xx-YY", 0, "1,234,567.891"},
+		{"3.14159265", "en-US", 4, "3.142"},
+		{"0.000123456", "de-DE", 3, "0,000123"},
+		{"99.96", "en-US", 3, "100"},
+	}
+	for _, c := range cases {
+		if got := localizeNumbers(c.text, c.locale, c.maxDigits); got != c.want {
+			t.Errorf("localizeNumbers(%q, %s, %d) = %q; want %q", c.text, c.locale, c.maxDigits, got, c.want)
+		}
+	}
+}
+
+// Test for South Asian locales grouping past the first thousand in pairs (lakhs and crores) at the 1e5 and 1e7
+// boundaries, negatives and decimals included, while en-US and de-DE keep grouping in thousands
+func TestLakhCroreGrouping(t *testing.T) {
+	cases := []struct {
+		text   string
+		locale string
+		want   string
+	}{
+		{"99999", "en-IN", "99,999"},
+		{"100000", "en-IN", "1,00,000"},
+		{"9999999", "en-IN", "99,99,999"},
+		{"10000000", "en-IN", "1,00,00,000"},
+		{"1234567890", "en-IN", "1,23,45,67,890"},
+		{"-1234567.5", "en-IN", "-12,34,567.5"},
+		{"12,345,678.90", "en-IN", "1,23,45,678.90"},
+		{"100000", "hi-IN", "1,00,000"},
+		{"10000000", "hi-IN", "1,00,00,000"},
+		{"-10000000.25", "hi_in", "-1,00,00,000.25"},
+		{"100000", "en-US", "100,000"},
+		{"10000000", "en-US", "10,000,000"},
+		{"-1234567.5", "en-US", "-1,234,567.5"},
+		{"100000", "de-DE", "100.000"},
+		{"10000000", "de-DE", "10.000.000"},
+		{"-1234567.5", "de-DE", "-1.234.567,5"},
+		{"999", "en-IN", "999"},
+		{"3000", "en-IN", "3,000"},
+	}
+	for _, c := range cases {
+		if got := localizeNumbers(c.text, c.locale, 0); got != c.want {
+			t.Errorf("localizeNumbers(%q, %s) = %q; want %q", c.text, c.locale, got, c.want)
+		}
+	}
+	for locale, want := range map[string]bool{"en-IN": true, "hi-IN": true, "hi": true, "ur-IN": true, "ur-PK": false, "en-US": false, "de-DE": false} {
+		if got := usesLakhGrouping(locale); got != want {
+			t.Errorf("usesLakhGrouping(%s) = %v; want %v", locale, got, want)
+		}
+	}
+}
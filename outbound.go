@@ -0,0 +1,119 @@
+//////////////////////////////////////////////////
+// Outbound Queue Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for serializing posts per channel so bursts respect Slack's rate limits and arrive in order
+import (
+	"log"  // Permits console logging
+	"sync" // Permits concurrency-safe queue access
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global struct holding a queued post and, for synchronous callers, where to report where it landed
+type outboundPost struct {
+	options []slack.MsgOption
+	result  chan outboundResult
+}
+
+// Global struct holding the outcome of a post
+type outboundResult struct {
+	channel   string
+	timestamp string
+	err       error
+}
+
+// Global per-channel queues of pending posts, each drained in order by at most one worker, and how many posts taken
+// off them are still being sent (counted as pending until Slack answers, so a shutdown drain waits for them)
+var (
+	outboundQueues  = map[string][]outboundPost{}
+	outboundDepth   int64
+	outboundSending int64
+	outboundMu      sync.Mutex
+)
+
+// Global function for adding a post to its channel's queue, starting the channel's worker when none is running
+func enqueuePost(channelID string, post outboundPost) {
+	outboundMu.Lock()
+	defer outboundMu.Unlock()
+
+	pending, running := outboundQueues[channelID]
+	outboundQueues[channelID] = append(pending, post)
+	outboundDepth++
+	metrics.set(outboundDepth, "wolfy_outbound_queue_depth")
+	if !running {
+		go drainOutboundQueue(channelID)
+	}
+}
+
+// Global function for sending a channel's queued posts one at a time, retiring the queue once it empties
+func drainOutboundQueue(channelID string) {
+	for {
+		outboundMu.Lock()
+		pending := outboundQueues[channelID]
+		if len(pending) == 0 {
+			delete(outboundQueues, channelID)
+			outboundMu.Unlock()
+			return
+		}
+		post := pending[0]
+		outboundQueues[channelID] = pending[1:]
+		outboundDepth--
+		outboundSending++
+		metrics.set(outboundDepth, "wolfy_outbound_queue_depth")
+		outboundMu.Unlock()
+
+		respChannel, respTimestamp, err := sendPost(channelID, post.options)
+		outboundMu.Lock()
+		outboundSending--
+		outboundMu.Unlock()
+		if post.result != nil {
+			post.result <- outboundResult{channel: respChannel, timestamp: respTimestamp, err: err}
+		}
+	}
+}
+
+// Global function for dropping a channel's queued posts, failing any caller waiting on one as if the post were
+// skipped, and returning how many were dropped (the worker, if running, finds the queue empty and retires it)
+func dropOutboundPosts(channelID string) int {
+	outboundMu.Lock()
+	pending, running := outboundQueues[channelID]
+	if running {
+		outboundQueues[channelID] = nil
+		outboundDepth -= int64(len(pending))
+		metrics.set(outboundDepth, "wolfy_outbound_queue_depth")
+	}
+	outboundMu.Unlock()
+
+	for _, post := range pending {
+		if post.result != nil {
+			post.result <- outboundResult{err: errChannelGone}
+		}
+	}
+	metrics.add(int64(len(pending)), "wolfy_posts_skipped_total", "reason", "channel_gone")
+	return len(pending)
+}
+
+// Global function for posting as the Slackbot, waiting out Slack's Retry-After when rate limited (on the client the
+// post started with, even if credentials rotate meanwhile), and skipping channels the bot was removed from or Slack
+// recently said were gone
+func sendPost(channelID string, options []slack.MsgOption) (string, string, error) {
+	if channelGone(channelID) {
+		metrics.inc("wolfy_posts_skipped_total", "reason", "channel_gone")
+		return "", "", errChannelGone
+	}
+	options = append(brandedOptions(channelID, "chat.postMessage", options), slack.MsgOptionAsUser(true))
+	respChannel, respTimestamp, err := slackAPI().PostMessage(channelID, options...)
+	noteStageResult("post", err != nil)
+	if err != nil {
+		log.Printf("ERROR: Unable to post message to %s. Error Msg: %v", channelID, err)
+	}
+	if isChannelGoneError(err) {
+		noteChannelGone(channelID, err)
+	}
+	return respChannel, respTimestamp, err
+}
@@ -0,0 +1,60 @@
+//////////////////////////////////////////////////
+// Percentages Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for presenting percentage and probability answers as rounded percentages
+import (
+	"regexp"  // Permits recognition of percentage answers and probability questions
+	"strconv" // Permits parsing and rounding of the answer's value
+	"strings" // Permits string normalization
+)
+
+// Global patterns recognizing an answer carrying a percent unit ("42.2857%", "16.7 percent"), a bare number, and questions
+// whose answer is a probability ("what are the chances...") or a percentage ("what percent of...")
+var (
+	percentAnswerPattern    = regexp.MustCompile(`(?i)^([-+]?\d+(?:\.\d+)?)\s*(?:%|percent)$`)
+	bareNumberAnswerPattern = regexp.MustCompile(`^([-+]?\d*\.?\d+)$`)
+	probabilityQueryPattern = regexp.MustCompile(`(?i)\b(?:probability|chances?|likelihood)\b`)
+	percentageQueryPattern  = regexp.MustCompile(`(?i)\b(?:what|which)\s+(?:percent|percentage)\b`)
+)
+
+// Global function for formatting a percentage or probability answer as a percentage rounded to the configured decimals
+// ("≈ 42.3%"), reporting false (so the raw answer is kept) unless the answer or the question clearly calls for one
+func formatPercentageAnswer(query string, answer string) (string, bool) {
+	answer = strings.TrimSpace(answer)
+	var percent float64
+	if match := percentAnswerPattern.FindStringSubmatch(answer); match != nil {
+		percent, _ = strconv.ParseFloat(match[1], 64)
+	} else if match := bareNumberAnswerPattern.FindStringSubmatch(answer); match != nil {
+		value, err := strconv.ParseFloat(match[1], 64)
+		switch {
+		case err != nil:
+			return "", false
+		// A bare number answering "what percent..." is already a percentage
+		case percentageQueryPattern.MatchString(query):
+			percent = value
+		// A bare number answering "what's the probability..." is one only when it is between 0 and 1
+		case probabilityQueryPattern.MatchString(query) && value >= 0 && value <= 1:
+			percent = value * 100
+		default:
+			return "", false
+		}
+	} else {
+		return "", false
+	}
+
+	// Dropping float noise from scaling a probability ("0.07" is 7%, not 7.000000000000001%) before rounding
+	percent, _ = strconv.ParseFloat(strconv.FormatFloat(percent, 'g', 12, 64), 64)
+	rounded := strconv.FormatFloat(percent, 'f', config.PercentDecimals, 64)
+	if strings.Contains(rounded, ".") {
+		rounded = strings.TrimRight(strings.TrimRight(rounded, "0"), ".")
+	}
+	if exact, _ := strconv.ParseFloat(rounded, 64); exact != percent {
+		return "≈ " + rounded + "%", true
+	}
+	return rounded + "%", true
+}
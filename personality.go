@@ -0,0 +1,327 @@
+//////////////////////////////////////////////////
+// Personality Packs Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for named bundles of message wording and emoji, chosen per workspace or channel
+import (
+	"context" // Permits resolving the pack for the scope carried by a context
+	"log"     // Permits console logging
+	"regexp"  // Permits substitution of message variables
+	"sort"    // Permits stable reporting of missing keys
+	"strings" // Permits pack name normalization
+	"sync"    // Permits concurrency-safe pack access
+)
+
+// Global pattern recognizing a message variable ("{name}") with the comma or space leading into it, dropped along with
+// the variable when it has no value ("Hello {name}!" becomes "Hello!")
+var messageVariablePattern = regexp.MustCompile(`(,?[ \t]*)\{(\w+)\}`)
+
+// Global struct holding a personality pack: every required message, and the emoji decorating scheduled replies and statuses
+type personalityPack struct {
+	Name     string            `json:"name"`
+	Messages map[string]string `json:"messages"`
+	Emoji    map[string]string `json:"emoji"`
+}
+
+// Global lists of the message and emoji keys every pack must define (emoji may be empty to go without)
+var (
+	requiredMessageKeys = []string{
+		"greeting", "welcome_intro", "welcome_examples", "onboarding_note", "channel_intro", "placeholder",
+		"unclear_input", "timeout", "wit_busy", "apis_disabled", "wolfram_unclear", "wolfram_too_long",
+	}
+	requiredEmojiKeys = []string{"reminder", "recurring", "degraded"}
+)
+
+// Global list of message keys packs may leave out, falling back to the classic pack's wording
+var optionalMessageKeys = []string{"nlp_unavailable", "quota_exceeded", "internal_error", "empty_mention", "acknowledgment", "still_working", "cancel_offer"}
+
+// Global packs built into the binary, keyed by name
+var builtinPersonalityPacks = []personalityPack{
+	{
+		Name: "classic",
+		Messages: map[string]string{
+			"greeting":      "Hello {name}! I am WolfyBot and I am here to answer your questions. :-)",
+			"welcome_intro": "Hi there {name}, I'm WolfyBot! :wave: Ask me almost anything factual or numeric and I'll look it up on Wolfram|Alpha for you.",
+			"channel_intro": "Hi everyone, I'm WolfyBot! :wave: Thanks for the invite. I look up facts, numbers, and conversions on Wolfram|Alpha.",
+			"welcome_examples": "Not sure where to start? Try one of these:\n" +
+				"• _What is the population of France?_\n" +
+				"• _100 F in C and K_\n" +
+				"• _How many days until Christmas?_\n" +
+				"Say \"what can you do\" any time for the full list.",
+			"onboarding_note":  "_Hi! I'm WolfyBot - ask me factual questions, or say \"what can you do\" for help._ :-)",
+			"placeholder":      "Working on it... :hourglass:",
+			"unclear_input":    "WARNING: User input is unclear. :-/ Try clarifying your question?",
+			"timeout":          "Sorry, I couldn't get an answer for that in time. :-( Try asking again in a bit?",
+			"wit_busy":         "I'm getting a lot of questions right now! :sweat_smile: Please try again in a minute.",
+			"apis_disabled":    "I'm temporarily limited to answers I can work out on my own (date math and the like) - please try your question again later. :construction:",
+			"wolfram_unclear":  "Oops, looks like I didn't quite understand that! :-O",
+			"wolfram_too_long": "Whoops! I'm still learning the ropes and while I got your answer, it's a little long for me to communicate. :-P",
+			"nlp_unavailable":  "Sorry, I can't make sense of questions right now - my language service isn't answering. :-( Try again in a bit?",
+			"quota_exceeded":   "Sorry, I've used up my Wolfram|Alpha allowance for now. :-( Try again later?",
+			"internal_error":   "Sorry, something went wrong on my end. :-( Try again, and quote this code if it keeps happening.",
+			"empty_mention":    "You rang? :-) Ask me a question after the mention, like _@WolfyBot what is the population of France?_, or type \"help\" to see what I can do.",
+			"acknowledgment":   "You're welcome! :-)\nAnytime!\nHappy to help! :-)\nGlad I could help!",
+			"still_working":    "Still working on it... :hourglass_flowing_sand:",
+			"cancel_offer":     "This one is taking a while. :hourglass_flowing_sand: Say \"wolfy cancel\" or react with :x: to stop it.",
+		},
+		Emoji: map[string]string{"reminder": ":alarm_clock:", "recurring": ":repeat:", "degraded": ":warning:"},
+	},
+	{
+		Name: "professional",
+		Messages: map[string]string{
+			"greeting":      "Hello {name}. I'm WolfyBot; ask me a factual or numeric question and I'll look it up.",
+			"welcome_intro": "Hello {name}, I'm WolfyBot. I answer factual and numeric questions using Wolfram|Alpha.",
+			"channel_intro": "Hello, I'm WolfyBot. I answer factual, numeric, and unit conversion questions using Wolfram|Alpha.",
+			"welcome_examples": "Example questions:\n" +
+				"• What is the population of France?\n" +
+				"• 100 F in C and K\n" +
+				"• How many days until Christmas?\n" +
+				"Say \"what can you do\" for the full list of capabilities.",
+			"onboarding_note":  "_I'm WolfyBot. I answer factual questions; say \"what can you do\" for help._",
+			"placeholder":      "Working on it...",
+			"unclear_input":    "I couldn't interpret that question. Please rephrase it.",
+			"timeout":          "I couldn't retrieve an answer in time. Please try again shortly.",
+			"wit_busy":         "I'm handling a high volume of questions. Please try again in a minute.",
+			"apis_disabled":    "External lookups are temporarily unavailable; only locally computed answers (such as date math) are available. Please try again later.",
+			"wolfram_unclear":  "Wolfram|Alpha couldn't interpret that question. Please rephrase it.",
+			"wolfram_too_long": "The answer is too long to summarize here. Try a more specific question.",
+			"nlp_unavailable":  "The language service is unavailable. Please try again shortly.",
+			"quota_exceeded":   "The Wolfram|Alpha usage limit has been reached. Please try again later.",
+			"internal_error":   "An internal error occurred. Please try again, and quote this code if it persists.",
+			"empty_mention":    "Please include a question after the mention, or type \"help\" for a list of capabilities.",
+			"acknowledgment":   "You're welcome.\nGlad to help.\nHappy to assist.",
+			"still_working":    "Still working on it.",
+			"cancel_offer":     "This is taking longer than usual. Say \"wolfy cancel\" or react with :x: to stop it.",
+		},
+		Emoji: map[string]string{"reminder": "", "recurring": "", "degraded": ":warning:"},
+	},
+	{
+		Name: "concise",
+		Messages: map[string]string{
+			"greeting":      "Hi {name}. Ask me a question.",
+			"welcome_intro": "I'm WolfyBot: factual and numeric answers via Wolfram|Alpha.",
+			"channel_intro": "I'm WolfyBot: factual, numeric, and conversion answers via Wolfram|Alpha.",
+			"welcome_examples": "Try:\n" +
+				"• What is the population of France?\n" +
+				"• 100 F in C and K\n" +
+				"• How many days until Christmas?",
+			"onboarding_note":  "_WolfyBot: ask factual questions; \"what can you do\" for help._",
+			"placeholder":      "Working...",
+			"unclear_input":    "Didn't understand. Rephrase?",
+			"timeout":          "Timed out. Try again later.",
+			"wit_busy":         "Busy. Try again in a minute.",
+			"apis_disabled":    "Lookups are off; local answers only. Try later.",
+			"wolfram_unclear":  "Wolfram|Alpha didn't understand. Rephrase?",
+			"wolfram_too_long": "Answer too long. Be more specific.",
+			"nlp_unavailable":  "Language service down. Try later.",
+			"quota_exceeded":   "Wolfram|Alpha limit reached. Try later.",
+			"internal_error":   "Internal error. Try again.",
+			"empty_mention":    "Ask a question, or type \"help\".",
+			"acknowledgment":   "Welcome.\nAnytime.",
+			"still_working":    "Still working...",
+			"cancel_offer":     "Slow one. \"wolfy cancel\" or :x: to stop.",
+		},
+		Emoji: map[string]string{"reminder": "", "recurring": "", "degraded": ":warning:"},
+	},
+	{
+		Name: "playful",
+		Messages: map[string]string{
+			"greeting":      "Heyyy {name}! :wave: :wolf: WolfyBot here, ready to fetch answers! :sparkles:",
+			"welcome_intro": "Hi hi {name}, I'm WolfyBot! :wolf: :wave: Throw me any fact or number question and I'll go fetch it from Wolfram|Alpha! :tada:",
+			"channel_intro": "Hello, friends! :wolf: :tada: Thanks for inviting me! I sniff out facts, numbers, and conversions on Wolfram|Alpha. :mag:",
+			"welcome_examples": "Need ideas? :bulb: Try these:\n" +
+				"• _What is the population of France?_ :fr:\n" +
+				"• _100 F in C and K_ :thermometer:\n" +
+				"• _How many days until Christmas?_ :christmas_tree:\n" +
+				"Say \"what can you do\" any time for all my tricks! :dog2:",
+			"onboarding_note":  "_Hiya, I'm WolfyBot! :wolf: Ask me anything factual, or say \"what can you do\" to see my tricks!_ :sparkles:",
+			"placeholder":      "On the hunt... :wolf: :mag:",
+			"unclear_input":    "Hmm, my ears perked up but I didn't catch that! :thinking_face: Try asking another way?",
+			"timeout":          "Aw, that answer ran away before I could catch it! :cry: Try again in a bit?",
+			"wit_busy":         "Whoa, so many questions! :exploding_head: Give me a minute and ask again!",
+			"apis_disabled":    "I'm on a short leash right now and can only do answers I work out myself (date math and the like)! :construction: :wolf: Try again later!",
+			"wolfram_unclear":  "Oopsie, that one went right over my ears! :see_no_evil: Try rephrasing?",
+			"wolfram_too_long": "I found your answer but it's way too big to carry! :weight_lifter: Try something more specific? :sweat_smile:",
+			"nlp_unavailable":  "My ears have stopped working for a bit - I can't understand anyone right now! :hear_no_evil: Try again soon?",
+			"quota_exceeded":   "I've fetched so many answers I'm all out of treats for today! :bone: Try again later?",
+			"internal_error":   "Uh oh, I tripped over my own paws! :dizzy_face: Try again, and share this code if it keeps happening.",
+			"empty_mention":    "Woof? :wolf: My ears perked up but there's no question! Ask me something, or type \"help\" to see my tricks! :sparkles:",
+			"acknowledgment":   "Aww, anytime! :wolf: :heart:\nHappy to fetch! :sparkles:\nYou're welcome! :tada:",
+			"still_working":    "Still sniffing around... :wolf: :hourglass_flowing_sand:",
+			"cancel_offer":     "This trail is a long one! :feet: Say \"wolfy cancel\" or react with :x: and I'll come back. :wolf:",
+		},
+		Emoji: map[string]string{"reminder": ":alarm_clock: :wolf:", "recurring": ":repeat: :sparkles:", "degraded": ":rotating_light:"},
+	},
+}
+
+// Global map of the tones users can pick for themselves to the pack that words them
+var toneTable = map[string]string{"concise": "concise", "friendly": "classic", "formal": "professional"}
+
+// Global registry of loaded packs by name
+var (
+	personalityPacks   = map[string]personalityPack{}
+	personalityPacksMu sync.RWMutex
+)
+
+// Registering the built-in packs so messages resolve even before startup loading
+func init() {
+	for _, pack := range builtinPersonalityPacks {
+		personalityPacks[pack.Name] = pack
+	}
+}
+
+// Global function for listing the required keys a pack leaves undefined
+func missingPackKeys(pack personalityPack) []string {
+	var missing []string
+	for _, key := range requiredMessageKeys {
+		if pack.Messages[key] == "" {
+			missing = append(missing, "messages."+key)
+		}
+	}
+	for _, key := range requiredEmojiKeys {
+		if _, ok := pack.Emoji[key]; !ok {
+			missing = append(missing, "emoji."+key)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// Global function for validating the built-in packs and loading external ones (one JSON pack per file), swapping them in
+// whole and returning the problems found (packs with one are skipped)
+func loadPersonalityPacks() []catalogProblem {
+	packs := map[string]personalityPack{}
+	var problems []catalogProblem
+	for _, pack := range builtinPersonalityPacks {
+		problems = append(problems, checkPersonalityPack("built-in pack "+pack.Name, nil, pack)...)
+		packs[pack.Name] = pack
+	}
+
+	if config.PersonalityDir != "" {
+		paths, err := catalogFiles(config.PersonalityDir)
+		if err != nil {
+			problems = append(problems, catalogProblem{path: config.PersonalityDir, message: err.Error()})
+		}
+		for _, path := range paths {
+			pack, packProblems := readPersonalityPack(path)
+			if len(packProblems) > 0 {
+				problems = append(problems, packProblems...)
+				continue
+			}
+			packs[pack.Name] = pack
+		}
+	}
+	logCatalogProblems(problems)
+
+	if _, ok := packs[strings.ToLower(config.Personality)]; !ok {
+		log.Printf("CONFIG ERROR: Unknown personality pack %q; using \"classic\".", config.Personality)
+	}
+	for owner, name := range config.PersonalityOverrides {
+		if _, ok := packs[strings.ToLower(name)]; !ok {
+			log.Printf("CONFIG ERROR: Unknown personality pack %q for %s; using the default.", name, owner)
+		}
+	}
+
+	personalityPacksMu.Lock()
+	personalityPacks = packs
+	personalityPacksMu.Unlock()
+	return problems
+}
+
+// Global function for choosing the pack for a scope with user tone > channel > workspace > default precedence
+func personalityFor(scope flagScope) personalityPack {
+	tone := ""
+	if scope.user != "" && store != nil {
+		tone = loadPreferences(scope.user).Tone
+	}
+
+	personalityPacksMu.RLock()
+	defer personalityPacksMu.RUnlock()
+
+	if pack, ok := personalityPacks[toneTable[tone]]; ok && tone != "" {
+		return pack
+	}
+	for _, owner := range []string{scope.channel, scope.team} {
+		if name, ok := config.PersonalityOverrides[owner]; ok && owner != "" {
+			if pack, ok := personalityPacks[strings.ToLower(name)]; ok {
+				return pack
+			}
+		}
+	}
+	if pack, ok := personalityPacks[strings.ToLower(config.Personality)]; ok {
+		return pack
+	}
+	return personalityPacks["classic"]
+}
+
+// Global function for looking up a pack emoji for a scope, followed by a space when set so it can prefix a reply
+func packEmoji(scope flagScope, key string) string {
+	if emoji := personalityFor(scope).Emoji[key]; emoji != "" {
+		return emoji + " "
+	}
+	return ""
+}
+
+// Global function for looking up a message in the pack of the scope carried by a context
+func scopedMessage(ctx context.Context, key string) string {
+	scope, _ := ctx.Value(flagScopeKey{}).(flagScope)
+	return packMessage(scope, key)
+}
+
+// Global function for filling in a message's variables, leaving unknown ones as written
+func renderMessageVariables(text string, variables map[string]string) string {
+	return messageVariablePattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := messageVariablePattern.FindStringSubmatch(match)
+		value, ok := variables[parts[2]]
+		if !ok {
+			return match
+		}
+		if value == "" {
+			return ""
+		}
+		return parts[1] + value
+	})
+}
+
+// Global function for looking up a message addressed to a user, with {name} filled in from their Slack profile (escaped,
+// since a name like "<!channel>" is theirs to choose and must read as written rather than as markup)
+func personalizedMessage(ctx context.Context, key string, user string) string {
+	return renderMessageVariables(scopedMessage(ctx, key), map[string]string{"name": slackTextEscaper.Replace(userDisplayName(user))})
+}
+
+// Global function for choosing the slow-answer placeholder: the configured text, else the scope's pack wording
+func placeholderText(ctx context.Context) string {
+	if config.PlaceholderText != "" {
+		return config.PlaceholderText
+	}
+	return scopedMessage(ctx, "placeholder")
+}
+
+// Global function for checking whether text is any pack's (or the overrides') wording of one of the given message keys
+func isPackMessage(text string, keys ...string) bool {
+	// Ignoring the failure code appended to error replies
+	text = failureCodePattern.ReplaceAllString(text, "")
+	messageCatalogMu.RLock()
+	defer messageCatalogMu.RUnlock()
+	personalityPacksMu.RLock()
+	defer personalityPacksMu.RUnlock()
+
+	if isLocalizedMessage(text, keys...) {
+		return true
+	}
+	for _, key := range keys {
+		if override, ok := messageOverrides[key]; ok && override == text {
+			return true
+		}
+		for _, pack := range personalityPacks {
+			if pack.Messages[key] == text {
+				return true
+			}
+		}
+	}
+	return false
+}
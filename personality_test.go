@@ -0,0 +1,47 @@
+//////////////////////////////////////////////////
+// Personality Packs Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing how greetings address users by their Slack name
+import (
+	"context" // Permits the handler's request context
+	"testing" // Permits Go testing
+
+	wit "github.com/christianrondeau/go-wit" // External Wit.ai API
+	slack "github.com/nlopes/slack"          // External Slack API
+)
+
+// Test for greetings using the asker's display name, else their real name, else no name at all, with names that look
+// like Slack markup escaped so they read as written and never mention anyone
+func TestGreetingName(t *testing.T) {
+	withConfig(t, func(config *Config) {
+		config.Personality = "classic"
+		config.EscapeReplies = true
+	})
+	cases := []struct {
+		name    string
+		profile *slack.User
+		want    string
+	}{
+		{"display name", &slack.User{RealName: "Alice Smith", Profile: slack.UserProfile{DisplayName: "ally"}}, "Hello ally! I am WolfyBot and I am here to answer your questions. :-)"},
+		{"real name", &slack.User{RealName: "Alice Smith"}, "Hello Alice Smith! I am WolfyBot and I am here to answer your questions. :-)"},
+		{"no name", &slack.User{}, "Hello! I am WolfyBot and I am here to answer your questions. :-)"},
+		{"broadcast", &slack.User{Profile: slack.UserProfile{DisplayName: "<!channel>"}}, "Hello &lt;!channel&gt;! I am WolfyBot and I am here to answer your questions. :-)"},
+		{"mention", &slack.User{Profile: slack.UserProfile{DisplayName: "<@UADMIN>"}}, "Hello &lt;@UADMIN&gt;! I am WolfyBot and I am here to answer your questions. :-)"},
+		{"link", &slack.User{RealName: "<https://example.com|click me>"}, "Hello &lt;https://example.com|click me&gt;! I am WolfyBot and I am here to answer your questions. :-)"},
+		{"ampersand", &slack.User{Profile: slack.UserProfile{DisplayName: "R&D Bob"}}, "Hello R&amp;D Bob! I am WolfyBot and I am here to answer your questions. :-)"},
+	}
+	t.Cleanup(func() { userInfo.remove("UGREET") })
+	for _, c := range cases {
+		c.profile.ID = "UGREET"
+		userInfo.put("UGREET", c.profile)
+		response, _ := handleGreeting(context.Background(), testMessage("DGREET", "UGREET", "8700.000001", "hi"), wit.MessageEntity{Value: "hi"})
+		if got := escapeSlackText(response.Text); got != c.want {
+			t.Errorf("%s: greeting = %q; want %q", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+//////////////////////////////////////////////////
+// Embeddable Engine Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Package wolfy runs WolfyBot's question pipeline (classify, route, answer) inside another Go program, with no Slack
+// involved. Build an Engine with NewEngine and the options for the classifier, answer providers, cache, and store it
+// should use, then call Ask.
+package wolfy
+
+// Global imports for classifying, routing, and answering questions without any chat platform
+import (
+	"context" // Permits cancellation of classification and answering
+	"errors"  // Permits the pipeline's errors
+	"strings" // Permits normalization of questions and cache keys
+)
+
+// Global constant holding the module's semantic version, raised with every change to this package's API
+const Version = "0.1.0"
+
+// Global constant naming the intent the default classifier gives every question
+const DefaultIntent = "question"
+
+// Global errors returned by Ask
+var (
+	ErrEmptyQuestion = errors.New("wolfy: the question is empty")
+	ErrLowConfidence = errors.New("wolfy: the classifier is not confident enough in any intent")
+	ErrNoProvider    = errors.New("wolfy: no answer provider handles the intent")
+	ErrNoAnswer      = errors.New("wolfy: the answer provider has no answer")
+)
+
+// Global struct holding one question put to the engine
+type Request struct {
+	Text string // The question as asked
+	User string // Optional asker ID; when set, the answer is saved to the store under it
+}
+
+// Global struct holding the engine's answer to a question
+type Response struct {
+	Text       string  // The answer
+	Intent     string  // The intent the question was routed by
+	Query      string  // The query the answer provider was asked
+	Confidence float64 // The classifier's confidence in the intent
+	Cached     bool    // Whether the answer came from the cache
+}
+
+// Global struct holding what the classifier made of a question: the intent to route by, the query to answer (the
+// question itself when empty), and how confident it is
+type Intent struct {
+	Name       string
+	Query      string
+	Confidence float64
+}
+
+// Global interface for anything that classifies a question into an intent
+type Classifier interface {
+	Classify(ctx context.Context, text string) (Intent, error)
+}
+
+// Global function type adapting a plain function into a Classifier
+type ClassifierFunc func(ctx context.Context, text string) (Intent, error)
+
+// Method for classifying with the adapted function
+func (f ClassifierFunc) Classify(ctx context.Context, text string) (Intent, error) {
+	return f(ctx, text)
+}
+
+// Global interface for anything that answers a query, returning ErrNoAnswer when it has none
+type AnswerProvider interface {
+	Answer(ctx context.Context, query string) (string, error)
+}
+
+// Global function type adapting a plain function into an AnswerProvider
+type AnswerFunc func(ctx context.Context, query string) (string, error)
+
+// Method for answering with the adapted function
+func (f AnswerFunc) Answer(ctx context.Context, query string) (string, error) {
+	return f(ctx, query)
+}
+
+// Global interface for a cache of answers by normalized query
+type Cache interface {
+	Get(key string) (string, bool)
+	Set(key string, answer string)
+}
+
+// Global interface for a bucketed key/value store the engine saves answers in
+type Store interface {
+	Put(bucket string, key string, value interface{}) error
+}
+
+// Global constant naming the store bucket the latest answer to each asker is saved in
+const AnswersBucket = "wolfy_answers"
+
+// Global struct holding a configured pipeline; it is safe for concurrent use when its classifier, providers, cache,
+// and store are
+type Engine struct {
+	classifier    Classifier
+	providers     map[string]AnswerProvider
+	fallback      AnswerProvider
+	cache         Cache
+	store         Store
+	minConfidence float64
+}
+
+// Global function type configuring an Engine
+type Option func(*Engine)
+
+// Global function for choosing the classifier (by default every question gets DefaultIntent with full confidence)
+func WithClassifier(classifier Classifier) Option {
+	return func(engine *Engine) { engine.classifier = classifier }
+}
+
+// Global function for routing an intent to an answer provider
+func WithProvider(intent string, provider AnswerProvider) Option {
+	return func(engine *Engine) { engine.providers[intent] = provider }
+}
+
+// Global function for choosing the provider that answers intents without a route of their own (by default, exact
+// arithmetic only)
+func WithAnswerProvider(provider AnswerProvider) Option {
+	return func(engine *Engine) { engine.fallback = provider }
+}
+
+// Global function for caching answers by normalized query (by default nothing is cached)
+func WithCache(cache Cache) Option {
+	return func(engine *Engine) { engine.cache = cache }
+}
+
+// Global function for saving each asker's latest answer (by default nothing is saved)
+func WithStore(store Store) Option {
+	return func(engine *Engine) { engine.store = store }
+}
+
+// Global function for setting the confidence below which a classification is rejected with ErrLowConfidence
+func WithMinConfidence(confidence float64) Option {
+	return func(engine *Engine) { engine.minConfidence = confidence }
+}
+
+// Global function for building an Engine from its options
+func NewEngine(options ...Option) *Engine {
+	engine := &Engine{
+		classifier: ClassifierFunc(func(ctx context.Context, text string) (Intent, error) {
+			return Intent{Name: DefaultIntent, Confidence: 1}, nil
+		}),
+		providers: map[string]AnswerProvider{},
+		fallback:  Arithmetic(),
+	}
+	for _, option := range options {
+		option(engine)
+	}
+	return engine
+}
+
+// Method for answering a question: classifying it, routing its intent to a provider, and answering from the cache
+// when it can
+func (engine *Engine) Ask(ctx context.Context, request Request) (Response, error) {
+	text := strings.Join(strings.Fields(request.Text), " ")
+	if text == "" {
+		return Response{}, ErrEmptyQuestion
+	}
+	intent, err := engine.classifier.Classify(ctx, text)
+	if err != nil {
+		return Response{}, err
+	}
+	if intent.Confidence < engine.minConfidence {
+		return Response{Intent: intent.Name, Confidence: intent.Confidence}, ErrLowConfidence
+	}
+	query := intent.Query
+	if strings.TrimSpace(query) == "" {
+		query = text
+	}
+	response := Response{Intent: intent.Name, Query: query, Confidence: intent.Confidence}
+
+	provider, routed := engine.providers[intent.Name]
+	if !routed {
+		provider = engine.fallback
+	}
+	if provider == nil {
+		return response, ErrNoProvider
+	}
+
+	key := intent.Name + "|" + strings.ToLower(query)
+	if engine.cache != nil {
+		if answer, ok := engine.cache.Get(key); ok {
+			response.Text, response.Cached = answer, true
+			return response, engine.save(request, response)
+		}
+	}
+	answer, err := provider.Answer(ctx, query)
+	if err != nil {
+		return response, err
+	}
+	response.Text = answer
+	if engine.cache != nil {
+		engine.cache.Set(key, answer)
+	}
+	return response, engine.save(request, response)
+}
+
+// Method for saving an answer under its asker, when there is one and a store to save it in
+func (engine *Engine) save(request Request, response Response) error {
+	if engine.store == nil || request.User == "" {
+		return nil
+	}
+	return engine.store.Put(AnswersBucket, request.User, response)
+}
@@ -0,0 +1,123 @@
+//////////////////////////////////////////////////
+// Embeddable Engine Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Wolfy package for running WolfyBot's question pipeline inside another Go program
+package wolfy
+
+// Global imports for testing the engine
+import (
+	"context" // Permits the request contexts
+	"errors"  // Permits a failing classifier
+	"testing" // Permits Go testing
+)
+
+// Global struct holding a map-backed Cache and Store for tests
+type memory struct {
+	answers map[string]string
+	saved   map[string]interface{}
+}
+
+// Method for looking up a cached answer
+func (m *memory) Get(key string) (string, bool) {
+	answer, ok := m.answers[key]
+	return answer, ok
+}
+
+// Method for caching an answer
+func (m *memory) Set(key string, answer string) {
+	m.answers[key] = answer
+}
+
+// Method for saving a record
+func (m *memory) Put(bucket string, key string, value interface{}) error {
+	m.saved[bucket+"/"+key] = value
+	return nil
+}
+
+// Test for each way a question is routed, rejected, or answered
+func TestEngineAsk(t *testing.T) {
+	classifier := ClassifierFunc(func(ctx context.Context, text string) (Intent, error) {
+		switch text {
+		case "broken":
+			return Intent{}, errors.New("classifier down")
+		case "mumble":
+			return Intent{Name: "wolfram_query", Confidence: 0.2}, nil
+		case "weather in Paris":
+			return Intent{Name: "weather", Query: "Paris", Confidence: 0.9}, nil
+		case "tell me a joke":
+			return Intent{Name: "joke", Confidence: 0.9}, nil
+		}
+		return Intent{Name: "wolfram_query", Confidence: 0.9}, nil
+	})
+	weather := AnswerFunc(func(ctx context.Context, query string) (string, error) { return "Sunny in " + query, nil })
+	engine := NewEngine(WithClassifier(classifier), WithMinConfidence(0.5), WithProvider("weather", weather), WithProvider("joke", nil))
+
+	cases := []struct {
+		text   string
+		answer string
+		intent string
+		err    error
+	}{
+		{"   ", "", "", ErrEmptyQuestion},
+		{"mumble", "", "wolfram_query", ErrLowConfidence},
+		{"weather   in Paris", "Sunny in Paris", "weather", nil},
+		{"2 + 2", "2 + 2 = 4", "wolfram_query", nil},
+		{"population of France", "", "wolfram_query", ErrNoAnswer},
+		{"tell me a joke", "", "joke", ErrNoProvider},
+	}
+	for _, c := range cases {
+		response, err := engine.Ask(context.Background(), Request{Text: c.text})
+		if err != c.err || response.Text != c.answer || response.Intent != c.intent {
+			t.Errorf("Ask(%q) = %q (%s), %v; want %q (%s), %v", c.text, response.Text, response.Intent, err, c.answer, c.intent, c.err)
+		}
+	}
+	if _, err := engine.Ask(context.Background(), Request{Text: "broken"}); err == nil || err.Error() != "classifier down" {
+		t.Errorf("Ask passed over a classifier error: %v", err)
+	}
+}
+
+// Test for answering repeats from the cache and saving answers under their askers
+func TestEngineCacheAndStore(t *testing.T) {
+	calls := 0
+	provider := AnswerFunc(func(ctx context.Context, query string) (string, error) {
+		calls++
+		return "Paris", nil
+	})
+	mem := &memory{answers: map[string]string{}, saved: map[string]interface{}{}}
+	engine := NewEngine(WithAnswerProvider(provider), WithCache(mem), WithStore(mem))
+
+	first, err := engine.Ask(context.Background(), Request{Text: "Capital of France", User: "U1"})
+	if err != nil || first.Cached {
+		t.Fatalf("first Ask = %+v, %v; want an uncached answer", first, err)
+	}
+	second, err := engine.Ask(context.Background(), Request{Text: "capital of  france"})
+	if err != nil || !second.Cached || second.Text != "Paris" || calls != 1 {
+		t.Errorf("second Ask = %+v, %v after %d provider calls; want the cached answer after 1", second, err, calls)
+	}
+	if saved, ok := mem.saved[AnswersBucket+"/U1"].(Response); !ok || saved.Text != "Paris" {
+		t.Errorf("saved answer for U1 = %+v; want Paris", mem.saved[AnswersBucket+"/U1"])
+	}
+	if len(mem.saved) != 1 {
+		t.Errorf("saved %d answers; want only the one with an asker", len(mem.saved))
+	}
+}
+
+// Test for falling through providers that have no answer, and stopping at one that fails
+func TestFirstAnswer(t *testing.T) {
+	failure := errors.New("quota exceeded")
+	none := AnswerFunc(func(ctx context.Context, query string) (string, error) { return "", ErrNoAnswer })
+	failing := AnswerFunc(func(ctx context.Context, query string) (string, error) { return "", failure })
+	echo := AnswerFunc(func(ctx context.Context, query string) (string, error) { return query, nil })
+
+	if answer, err := FirstAnswer(none, echo).Answer(context.Background(), "x"); answer != "x" || err != nil {
+		t.Errorf("FirstAnswer(none, echo) = %q, %v; want x", answer, err)
+	}
+	if _, err := FirstAnswer(none, failing, echo).Answer(context.Background(), "x"); err != failure {
+		t.Errorf("FirstAnswer(none, failing, echo) error = %v; want %v", err, failure)
+	}
+	if _, err := FirstAnswer(none).Answer(context.Background(), "x"); err != ErrNoAnswer {
+		t.Errorf("FirstAnswer(none) error = %v; want ErrNoAnswer", err)
+	}
+}
@@ -0,0 +1,59 @@
+//////////////////////////////////////////////////
+// Embeddable Engine Examples Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Examples of embedding the engine, shown in the package documentation
+package wolfy_test
+
+// Global imports for the engine examples
+import (
+	"context" // Permits the request contexts
+	"fmt"     // Permits printing of answers
+	"strings" // Permits the example classifier's matching
+
+	"github.com/AakashSudhakar/wolfybot/pkg/wolfy" // The embeddable engine
+)
+
+// Example answering plain arithmetic with no configuration at all
+func ExampleNewEngine() {
+	engine := wolfy.NewEngine()
+	response, err := engine.Ask(context.Background(), wolfy.Request{Text: "what is 2^64 - 1?"})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(response.Text)
+	// Output: 2^64 - 1 = 18446744073709551615
+}
+
+// Example routing a custom intent to its own provider and everything else to arithmetic
+func ExampleWithProvider() {
+	greetings := wolfy.ClassifierFunc(func(ctx context.Context, text string) (wolfy.Intent, error) {
+		if strings.HasPrefix(strings.ToLower(text), "hello") {
+			return wolfy.Intent{Name: "greeting", Confidence: 0.9}, nil
+		}
+		return wolfy.Intent{Name: wolfy.DefaultIntent, Confidence: 1}, nil
+	})
+	engine := wolfy.NewEngine(
+		wolfy.WithClassifier(greetings),
+		wolfy.WithProvider("greeting", wolfy.AnswerFunc(func(ctx context.Context, query string) (string, error) {
+			return "Hi there!", nil
+		})),
+	)
+	for _, question := range []string{"hello wolfy", "factorial of 10"} {
+		response, _ := engine.Ask(context.Background(), wolfy.Request{Text: question})
+		fmt.Printf("%s: %s\n", response.Intent, response.Text)
+	}
+	// Output:
+	// greeting: Hi there!
+	// question: factorial of 10 = 3628800
+}
+
+// Example trying exact arithmetic before Wolfram|Alpha, so only what can't be worked out locally costs an API call
+func ExampleFirstAnswer() {
+	engine := wolfy.NewEngine(wolfy.WithAnswerProvider(wolfy.FirstAnswer(wolfy.Arithmetic(), wolfy.Wolfram("YOUR-APP-ID", "metric"))))
+	response, _ := engine.Ask(context.Background(), wolfy.Request{Text: "1/8 + 1/8"})
+	fmt.Println(response.Text)
+	// Output: 1/8 + 1/8 = 0.25
+}
@@ -0,0 +1,87 @@
+//////////////////////////////////////////////////
+// Engine Answer Providers Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Wolfy package for running WolfyBot's question pipeline inside another Go program
+package wolfy
+
+// Global imports for the answer providers the engine ships with
+import (
+	"context"   // Permits cancellation of Wolfram|Alpha requests
+	"fmt"       // Permits formatting of answers and errors
+	"io/ioutil" // Permits reading of Wolfram|Alpha replies
+	"net/http"  // Permits Wolfram|Alpha requests
+	"net/url"   // Permits encoding of Wolfram|Alpha parameters
+	"strings"   // Permits trimming of replies
+
+	answer "github.com/AakashSudhakar/wolfybot/internal/answer" // Internal local answers
+)
+
+// Global constants holding the Wolfram|Alpha Short Answers endpoint and the replies that mean it has no answer
+const (
+	wolframShortAnswerURL     = "https://api.wolframalpha.com/v1/result"
+	wolframNotUnderstoodReply = "Wolfram|Alpha did not understand your input"
+	wolframNoShortAnswerReply = "No short answer available"
+)
+
+// Global function for the provider answering plain arithmetic exactly ("2^64 - 1", "factorial of 30"), with
+// ErrNoAnswer for anything else
+func Arithmetic() AnswerProvider {
+	return AnswerFunc(func(ctx context.Context, query string) (string, error) {
+		asked, expression, ok := answer.ArithmeticExpression(query)
+		if !ok {
+			return "", ErrNoAnswer
+		}
+		value, err := answer.EvaluateExact(expression)
+		if err != nil {
+			return "", ErrNoAnswer
+		}
+		return fmt.Sprintf("%s = %s", asked, answer.FormatExact(value)), nil
+	})
+}
+
+// Global function for the provider asking Wolfram|Alpha's Short Answers API with an App ID, in metric or imperial
+// units ("" lets Wolfram choose), with ErrNoAnswer when it has no short answer
+func Wolfram(appID string, units string) AnswerProvider {
+	return AnswerFunc(func(ctx context.Context, query string) (string, error) {
+		params := url.Values{"appid": {appID}, "i": {query}}
+		if units != "" {
+			params.Set("units", units)
+		}
+		req, err := http.NewRequest("GET", wolframShortAnswerURL+"?"+params.Encode(), nil)
+		if err != nil {
+			return "", err
+		}
+		res, err := http.DefaultClient.Do(req.WithContext(ctx))
+		if err != nil {
+			return "", err
+		}
+		defer res.Body.Close()
+		body, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return "", err
+		}
+		switch reply := strings.TrimSpace(string(body)); {
+		case reply == wolframNotUnderstoodReply || reply == wolframNoShortAnswerReply || res.StatusCode == http.StatusNotImplemented:
+			return "", ErrNoAnswer
+		case res.StatusCode != http.StatusOK:
+			return "", fmt.Errorf("wolfy: Wolfram|Alpha request failed with status %s", res.Status)
+		default:
+			return reply, nil
+		}
+	})
+}
+
+// Global function for trying providers in order, answering with the first that has an answer
+func FirstAnswer(providers ...AnswerProvider) AnswerProvider {
+	return AnswerFunc(func(ctx context.Context, query string) (string, error) {
+		for _, provider := range providers {
+			reply, err := provider.Answer(ctx, query)
+			if err != ErrNoAnswer {
+				return reply, err
+			}
+		}
+		return "", ErrNoAnswer
+	})
+}
@@ -0,0 +1,181 @@
+//////////////////////////////////////////////////
+// Pod Content Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for making sense of full results pods that carry no plaintext: image-only, failed, asynchronous,
+// and oversized ones
+import (
+	"context"       // Permits bounding of asynchronous pod fetches
+	"encoding/json" // Permits decoding of asynchronous pods and error attributes
+	"fmt"           // Permits formatting of asynchronous pod failures
+	"log"           // Permits console logging
+	"net/http"      // Permits fetching of asynchronous pods
+	"net/url"       // Permits building of full results parameters
+	"regexp"        // Permits recognition of oversized results
+	"strings"       // Permits string normalization
+	"time"          // Permits the asynchronous pod timeout
+
+	wolfram "github.com/Krognol/go-wolfram" // External Wolfram|Alpha API
+)
+
+// Global constants bounding the extra fetches made for asynchronous pods: how many, and how long each may take
+const (
+	asyncPodLimit   = 3
+	asyncPodTimeout = 5 * time.Second
+)
+
+// Global constants holding the honest replies for results with nothing textual to show
+const (
+	imageOnlyResultNote = "Wolfram|Alpha only has this result as an image, attached below."
+	imageOnlyLinkNote   = "Wolfram|Alpha only has this result as an image: <%s|view it here>."
+	tooLargeResultNote  = "Wolfram|Alpha's result for this is too large to show here. Try narrowing the question down."
+)
+
+// Global pattern recognizing the placeholder text Wolfram puts in a pod whose result was too large to render
+var tooLargeResultPattern = regexp.MustCompile(`(?i)\b(?:result|output|data)\s+(?:is\s+)?too\s+(?:large|big|long)\b`)
+
+// Global struct holding what a full result's answer pod amounts to: its text, its image, and which shape it had
+// ("text", "image", "too_large", "error", or "empty")
+type podAnswer struct {
+	text     string
+	imageURL string
+	shape    string
+}
+
+// Global function for checking whether a Wolfram error attribute is set: it is false when all is well, and true or an
+// object with a code and message when not
+func wolframErrorSet(raw json.RawMessage) bool {
+	value := strings.TrimSpace(string(raw))
+	return value != "" && value != "false" && value != "null"
+}
+
+// Method for returning the first image in a pod, if any
+func (pod fullResultPod) imageURL() string {
+	for _, subPod := range pod.SubPods {
+		if subPod.Img.Src != "" {
+			return subPod.Img.Src
+		}
+	}
+	return ""
+}
+
+// Method for checking whether a pod stands in for a result too large to render
+func (pod fullResultPod) tooLarge() bool {
+	return tooLargeResultPattern.MatchString(pod.plaintext()) || tooLargeResultPattern.Match(pod.Error)
+}
+
+// Method for finding the pod holding a full result's answer: the primary pod, else the "Result" pod
+func (result *fullResult) answerPod() (fullResultPod, bool) {
+	for _, pod := range result.Pods {
+		if pod.Primary {
+			return pod, true
+		}
+	}
+	for _, pod := range result.Pods {
+		if pod.ID == "Result" {
+			return pod, true
+		}
+	}
+	return fullResultPod{}, false
+}
+
+// Method for classifying a full result's answer: its text when it has some, else its image, else an oversized or
+// failed result, so callers never mistake a pod without plaintext for an empty answer
+func (result *fullResult) answerContent() podAnswer {
+	pod, ok := result.answerPod()
+	switch {
+	case !ok && tooLargeResultPattern.Match(result.Error):
+		return podAnswer{text: tooLargeResultNote, shape: "too_large"}
+	case !ok:
+		return podAnswer{shape: "empty"}
+	case pod.tooLarge():
+		return podAnswer{text: tooLargeResultNote, shape: "too_large"}
+	case strings.TrimSpace(pod.plaintext()) != "":
+		return podAnswer{text: pod.plaintext(), shape: "text"}
+	case pod.imageURL() != "" && !config.AnswerAttachments:
+		// Plaintext-only mode has no attachment to show the image in, so it is linked instead
+		return podAnswer{text: fmt.Sprintf(imageOnlyLinkNote, pod.imageURL()), shape: "image"}
+	case pod.imageURL() != "":
+		return podAnswer{text: imageOnlyResultNote, imageURL: pod.imageURL(), shape: "image"}
+	case wolframErrorSet(pod.Error):
+		return podAnswer{shape: "error"}
+	}
+	return podAnswer{shape: "empty"}
+}
+
+// Global function for filling in asynchronous pods (those Wolfram left as a URL to fetch later), at most a few and each
+// within a short timeout, leaving any that fail as they were
+func resolveAsyncPods(ctx context.Context, result *fullResult) {
+	fetched := 0
+	for i := range result.Pods {
+		pod := &result.Pods[i]
+		if pod.Async == "" || strings.TrimSpace(pod.plaintext()) != "" || pod.imageURL() != "" {
+			continue
+		}
+		if fetched++; fetched > asyncPodLimit {
+			metrics.inc("wolfy_wolfram_async_pods_total", "result", "skipped")
+			continue
+		}
+		resolved, err := fetchAsyncPod(ctx, pod.Async)
+		if err != nil {
+			metrics.inc("wolfy_wolfram_async_pods_total", "result", "failed")
+			log.Printf("WOLFRAM ERROR: Unable to fetch the asynchronous pod %q.\nError Details: %v", pod.ID, err)
+			continue
+		}
+		metrics.inc("wolfy_wolfram_async_pods_total", "result", "resolved")
+		pod.SubPods, pod.Error, pod.Async = resolved.SubPods, resolved.Error, ""
+	}
+}
+
+// Global function for fetching one asynchronous pod, which arrives either on its own or wrapped like a full result
+func fetchAsyncPod(ctx context.Context, podURL string) (fullResultPod, error) {
+	ctx, cancel := context.WithTimeout(ctx, asyncPodTimeout)
+	defer cancel()
+	recordAPICall(ctx, "wolfram")
+	req, err := http.NewRequest("GET", podURL, nil)
+	if err != nil {
+		return fullResultPod{}, err
+	}
+	res, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return fullResultPod{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fullResultPod{}, fmt.Errorf("asynchronous pod request failed with status %s", res.Status)
+	}
+
+	envelope := struct {
+		fullResultPod
+		QueryResult fullResult `json:"queryresult"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(&envelope); err != nil {
+		return fullResultPod{}, err
+	}
+	if len(envelope.QueryResult.Pods) > 0 {
+		return envelope.QueryResult.Pods[0], nil
+	}
+	return envelope.fullResultPod, nil
+}
+
+// Global function for answering from full results when the short answer API has none, reporting false when the result
+// has nothing to offer (not even an image or an explanation of why)
+func fullResultFallback(ctx context.Context, client *wolfram.Client, query string, units wolfram.Unit) (podAnswer, bool) {
+	if !config.FullResultsFallback {
+		return podAnswer{}, false
+	}
+	result, err := fetchFullResult(ctx, client, query, url.Values{"format": {"plaintext,image"}, "units": {unitsName(units)}})
+	if err != nil {
+		log.Printf("WOLFRAM ERROR: Unable to fetch full results for %q.\nError Details: %v", query, err)
+		return podAnswer{}, false
+	}
+	resolveAsyncPods(ctx, result)
+	answer := result.answerContent()
+	metrics.inc("wolfy_full_results_fallbacks_total", "shape", answer.shape)
+	traceStep(ctx, "full results fallback for %q found a %s answer", query, answer.shape)
+	return answer, answer.text != ""
+}
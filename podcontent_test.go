@@ -0,0 +1,141 @@
+//////////////////////////////////////////////////
+// Pod Content Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing answers built from image-only, failed, asynchronous, and oversized full results
+import (
+	"context"       // Permits the fallback's request context
+	"encoding/json" // Permits decoding of full results fixtures
+	"net/http"      // Permits the fake Wolfram APIs
+	"strings"       // Permits routing of fake requests
+	"testing"       // Permits Go testing
+
+	wolfram "github.com/Krognol/go-wolfram" // External Wolfram API
+)
+
+// Global fixtures shaped like the full results Wolfram returns for each kind of answer pod, trimmed to their pods
+const (
+	textPodFixture = `{"success": true, "pods": [
+		{"title": "Input", "id": "Input", "subpods": [{"plaintext": "population of France"}]},
+		{"title": "Result", "id": "Result", "primary": true, "subpods": [{"plaintext": "68 million people"}]}
+	]}`
+	imageOnlyPodFixture = `{"success": true, "pods": [
+		{"title": "Input", "id": "Input", "subpods": [{"plaintext": "plot sin x"}]},
+		{"title": "Plot", "id": "Plot", "primary": true, "subpods": [{"plaintext": "", "img": {"src": "https://www.wolframalpha.com/Calculate/MSP/MSP1.gif", "alt": "Plot"}}]}
+	]}`
+	failedPodFixture = `{"success": true, "pods": [
+		{"title": "Result", "id": "Result", "error": {"code": "1", "msg": "Computation failed"}, "subpods": [{"plaintext": ""}]}
+	]}`
+	asyncPodFixture = `{"success": true, "pods": [
+		{"title": "Input", "id": "Input", "subpods": [{"plaintext": "digits of pi"}]},
+		{"title": "Result", "id": "Result", "primary": true, "async": "https://www.wolframalpha.com/api/v1/async?id=MSP2", "subpods": []}
+	]}`
+	oversizedPodFixture = `{"success": true, "pods": [
+		{"title": "Result", "id": "Result", "subpods": [{"plaintext": "(result too large to display)"}]}
+	]}`
+	oversizedResultFixture = `{"success": false, "error": {"code": "1000", "msg": "Output is too large"}, "pods": []}`
+	noAnswerPodFixture     = `{"success": true, "pods": [{"title": "Input", "id": "Input", "subpods": [{"plaintext": "xyzzy"}]}]}`
+)
+
+// Test for each shape of answer pod being classified, with image-only answers attached or (without attachments) linked
+func TestAnswerContentShapes(t *testing.T) {
+	cases := []struct {
+		name        string
+		fixture     string
+		attachments bool
+		want        podAnswer
+	}{
+		{"text", textPodFixture, true, podAnswer{text: "68 million people", shape: "text"}},
+		{"image attached", imageOnlyPodFixture, true, podAnswer{text: imageOnlyResultNote, imageURL: "https://www.wolframalpha.com/Calculate/MSP/MSP1.gif", shape: "image"}},
+		{"image linked", imageOnlyPodFixture, false, podAnswer{text: "Wolfram|Alpha only has this result as an image: <https://www.wolframalpha.com/Calculate/MSP/MSP1.gif|view it here>.", shape: "image"}},
+		{"failed", failedPodFixture, true, podAnswer{shape: "error"}},
+		{"unresolved async", asyncPodFixture, true, podAnswer{shape: "empty"}},
+		{"oversized pod", oversizedPodFixture, true, podAnswer{text: tooLargeResultNote, shape: "too_large"}},
+		{"oversized result", oversizedResultFixture, true, podAnswer{text: tooLargeResultNote, shape: "too_large"}},
+		{"no answer pod", noAnswerPodFixture, true, podAnswer{shape: "empty"}},
+	}
+	for _, c := range cases {
+		withConfig(t, func(config *Config) { config.AnswerAttachments = c.attachments })
+		var result fullResult
+		if err := json.Unmarshal([]byte(c.fixture), &result); err != nil {
+			t.Fatalf("%s: decoding the fixture: %v", c.name, err)
+		}
+		if got := result.answerContent(); got != c.want {
+			t.Errorf("%s: answerContent = %+v; want %+v", c.name, got, c.want)
+		}
+	}
+}
+
+// Test for asynchronous pods being filled in whether they arrive bare or wrapped like a full result, failed fetches
+// leaving the pod as it was, and fetches stopping at the limit
+func TestResolveAsyncPods(t *testing.T) {
+	var fetched []string
+	withExternalAPIs(t, func(req *http.Request) (int, string) {
+		id := req.URL.Query().Get("id")
+		fetched = append(fetched, id)
+		switch id {
+		case "bare":
+			return http.StatusOK, `{"title": "Result", "id": "Result", "subpods": [{"plaintext": "3.14159"}]}`
+		case "wrapped":
+			return http.StatusOK, `{"queryresult": {"pods": [{"title": "Result", "id": "Result", "subpods": [{"plaintext": "2.71828"}]}]}}`
+		}
+		return http.StatusInternalServerError, ""
+	})
+
+	var result fullResult
+	for _, id := range []string{"bare", "wrapped", "broken", "skipped"} {
+		result.Pods = append(result.Pods, fullResultPod{ID: id, Async: "https://www.wolframalpha.com/api/v1/async?id=" + id})
+	}
+	resolveAsyncPods(context.Background(), &result)
+
+	want := []struct{ text, async string }{{"3.14159", ""}, {"2.71828", ""}, {"", "broken"}, {"", "skipped"}}
+	for i, pod := range result.Pods {
+		if pod.plaintext() != want[i].text || (pod.Async == "") != (want[i].async == "") {
+			t.Errorf("pod %s = %q (async %q); want %q, still async %v", pod.ID, pod.plaintext(), pod.Async, want[i].text, want[i].async != "")
+		}
+	}
+	if len(fetched) != asyncPodLimit {
+		t.Errorf("fetched %q; want the first %d pods only", fetched, asyncPodLimit)
+	}
+}
+
+// Test for the full results fallback answering image-only, asynchronous, and oversized results honestly, and not
+// answering failed ones at all
+func TestFullResultFallbackShapes(t *testing.T) {
+	cases := []struct {
+		name    string
+		fixture string
+		text    string
+		shape   string
+		ok      bool
+	}{
+		{"image-only", imageOnlyPodFixture, imageOnlyResultNote, "image", true},
+		{"failed", failedPodFixture, "", "error", false},
+		{"async", asyncPodFixture, "3.1415926535", "text", true},
+		{"oversized", oversizedPodFixture, tooLargeResultNote, "too_large", true},
+	}
+	for _, c := range cases {
+		withConfig(t, func(config *Config) {
+			config.FullResultsFallback = true
+			config.AnswerAttachments = true
+		})
+		withExternalAPIs(t, func(req *http.Request) (int, string) {
+			if strings.Contains(req.URL.Path, "async") {
+				return http.StatusOK, `{"title": "Result", "id": "Result", "subpods": [{"plaintext": "3.1415926535"}]}`
+			}
+			return http.StatusOK, `{"queryresult": ` + c.fixture + `}`
+		})
+		before := metricValue("wolfy_full_results_fallbacks_total", "shape", c.shape)
+		answer, ok := fullResultFallback(context.Background(), &wolfram.Client{AppID: "test"}, "query", wolfram.Metric)
+		if answer.text != c.text || answer.shape != c.shape || ok != c.ok {
+			t.Errorf("%s: fullResultFallback = %q, %s, %v; want %q, %s, %v", c.name, answer.text, answer.shape, ok, c.text, c.shape, c.ok)
+		}
+		if got := metricValue("wolfy_full_results_fallbacks_total", "shape", c.shape) - before; got != 1 {
+			t.Errorf("%s: counted %v fallbacks with shape %s; want 1", c.name, got, c.shape)
+		}
+	}
+}
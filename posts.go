@@ -0,0 +1,99 @@
+//////////////////////////////////////////////////
+// Full Answer Posts Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for publishing a long answer's full results as a Slack post
+import (
+	"context" // Permits bounding the full results call
+	"fmt"     // Permits formatting of the post and reply
+	"log"     // Permits console logging
+	"net/url" // Permits passing the asker's units
+	"regexp"  // Permits matching of "post that"
+	"strings" // Permits building of post content
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global pattern recognizing "wolfy post that" style requests
+var postThatPattern = regexp.MustCompile(`(?i)^\s*(?:wolfy\s+)?(?:post|publish)\s+(?:that|this|the\s+full\s+answer)(?:\s+as\s+a\s+post)?\s*[!.]*\s*$`)
+
+// Global replacer escaping Slack's control characters and the markup characters posts would otherwise interpret
+var postTextEscaper = strings.NewReplacer(
+	"&", "&amp;", "<", "&lt;", ">", "&gt;",
+	"*", "\\*", "_", "\\_", "`", "\\`", "~", "\\~", "#", "\\#",
+)
+
+// Registering "post that" among the built-in capabilities
+func init() {
+	registerCapability("full_answer_post", "Say \"wolfy post that\" after a Wolfram|Alpha answer to publish every section of the full result as a Slack post here.", nil)
+}
+
+// Global function for escaping Wolfram plaintext for the post format, keeping its line breaks
+func escapePostText(text string) string {
+	return postTextEscaper.Replace(text)
+}
+
+// Global function for building post content: the query as the title heading, then one heading and body per pod shown
+func formatFullAnswerPost(query string, result *fullResult) (string, bool) {
+	sections := []string{"# " + escapePostText(query)}
+	for _, pod := range result.displayPods() {
+		sections = append(sections, fmt.Sprintf("## %s\n%s", escapePostText(pod.Title), escapePostText(strings.TrimSpace(pod.plaintext()))))
+	}
+	if result.Partial {
+		sections = append(sections, partialResultNote)
+	}
+	sections = append(sections, "_Source: Wolfram|Alpha_")
+	return strings.Join(sections, "\n\n"), len(sections) > 2
+}
+
+// Global function for publishing the asker's last answer in this channel as a post, reporting whether the message was consumed
+func handlePostThat(event *slack.MessageEvent) bool {
+	if !postThatPattern.MatchString(event.Msg.Text) {
+		return false
+	}
+	metrics.inc("wolfy_intents_total", "intent", "full_answer_post")
+
+	last, ok := lastInteractionIn(event.User, event.Channel)
+	if !ok || last.Query == "" {
+		postPrivately(event, "Ask me something Wolfram|Alpha can answer first, then say \"wolfy post that\" to publish the full result. :-)")
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(withAPIUsage(context.Background()), config.HandlerTimeout)
+	defer cancel()
+	units, _ := resolveUnits(event.User)
+	result, err := fetchFullResult(ctx, wolframClientFor(event), last.Query, url.Values{"units": {unitsName(units)}})
+	if err != nil {
+		log.Printf("POST ERROR: Unable to fetch full results for %q.\nError Details: %v", last.Query, err)
+		postPrivately(event, "Sorry, I couldn't fetch the full answer for that right now. :-(")
+		return true
+	}
+	content, ok := formatFullAnswerPost(last.Query, result)
+	if !ok {
+		postPrivately(event, "Wolfram|Alpha doesn't have anything more to add beyond the answer I gave. :-/")
+		return true
+	}
+
+	file, err := slackAPI().UploadFile(slack.FileUploadParameters{
+		Content:  content,
+		Filetype: "post",
+		Title:    last.Query,
+		Channels: []string{event.Channel},
+	})
+	if err != nil {
+		log.Printf("POST ERROR: Unable to upload the full answer for %q.\nError Details: %v", last.Query, err)
+		postPrivately(event, "Sorry, I couldn't publish the full answer right now. :-(")
+		return true
+	}
+	postText(event.Channel, fmt.Sprintf(":page_facing_up: Full answer for \"%s\": <%s|%s>", isolateDirection(last.Query), file.Permalink, escapePostTitle(last.Query)))
+	return true
+}
+
+// Global function for escaping a query for use as Slack link text
+func escapePostTitle(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "|", "\u2223").Replace(text)
+}
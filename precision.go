@@ -0,0 +1,118 @@
+//////////////////////////////////////////////////
+// More Precision Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for "more digits" follow-ups on numeric answers
+import (
+	"context" // Permits deadlines on the re-query
+	"fmt"     // Permits string formatting of replies
+	"log"     // Permits console logging
+	"net/url" // Permits building of podstate parameters
+	"regexp"  // Permits numeric answer detection
+	"strings" // Permits string normalization
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global list of pods whose "More digits" state we request, most specific first
+var moreDigitsPods = []string{"DecimalApproximation", "Result"}
+
+// Global pattern recognizing answers that are a (possibly approximate, scientific, unit-bearing, or locale-formatted) number
+var numericAnswerPattern = regexp.MustCompile(`^(?:about\s+|approximately\s+)?[≈~]?\s*-?\d(?:[\d,.'\x{202f}]*\d)?(?:\.\.\.)?(?:\s*(?:×|x|\*)\s*10\^\(?-?\d+\)?)?(?:\s+[^\d\s][^\d]*)?$`)
+
+// Registering the "more digits" follow-up among the built-in capabilities
+func init() {
+	registerCapability("more_digits", "Reply \"more digits\" after a numeric answer to get extra precision.", nil)
+}
+
+// Global function for checking whether an answer is numeric (what "more digits" can improve)
+func isNumericAnswer(answer string) bool {
+	return numericAnswerPattern.MatchString(strings.TrimSpace(answer))
+}
+
+// Global function for checking whether a message is one of the configured "more digits" commands
+func isMoreDigitsCommand(text string) bool {
+	text = strings.ToLower(strings.TrimSpace(strings.TrimRight(strings.TrimSpace(text), "?!.")))
+	for _, command := range config.MoreDigitsCommands {
+		if text == strings.ToLower(command) {
+			return true
+		}
+	}
+	return false
+}
+
+// Global function for building the full results parameters requesting extra digits for the numeric pods
+func moreDigitsParams() url.Values {
+	params := url.Values{}
+	for _, podID := range moreDigitsPods {
+		params.Add("podstate", podID+"__More digits")
+	}
+	params.Set("includepodid", strings.Join(moreDigitsPods, ","))
+	return params
+}
+
+// Global function for picking the most precise numeric plaintext among the returned pods
+func mostPreciseValue(result *fullResult) string {
+	best := ""
+	for _, podID := range moreDigitsPods {
+		for _, pod := range result.Pods {
+			if pod.ID == podID && isNumericAnswer(pod.plaintext()) && len(pod.plaintext()) > len(best) {
+				best = pod.plaintext()
+			}
+		}
+	}
+	return best
+}
+
+// Global function for re-querying the previous numeric answer with more digits, reporting whether the message was consumed
+func handleMoreDigits(event *slack.MessageEvent) bool {
+	if !isMoreDigitsCommand(event.Msg.Text) {
+		return false
+	}
+	metrics.inc("wolfy_intents_total", "intent", "more_digits")
+
+	explanation := fmt.Sprintf("\"%s\" re-runs your previous numeric answer with extra digits of precision. Ask me something numeric first (e.g. \"what is pi?\"), then try it again!", config.MoreDigitsCommands[0])
+	last, ok := lastInteraction(event.User)
+	if ok {
+		// Following the routing table, so entity keys renamed to the Wolfram handler still qualify
+		handler, routed := routeEntity(context.Background(), last.EntityKey)
+		ok = routed && handler.name == "wolfram_search_query"
+	}
+	if !ok || last.Query == "" || !isNumericAnswer(last.Answer) {
+		postText(event.User, explanation)
+		return true
+	}
+
+	// Full results are slow, so a placeholder goes up right away and is replaced by the outcome
+	channel, placeholderTS, err := postMessage(event.User, messageText(placeholderText(withFlagScope(context.Background(), event))))
+	if err != nil {
+		channel, placeholderTS = event.User, ""
+	}
+	respond := func(text string) {
+		replaceMessage(channel, placeholderTS, messageText(text))
+	}
+
+	ctx, cancel := context.WithTimeout(withAPIUsage(context.Background()), config.HandlerTimeout)
+	defer cancel()
+	result, err := fetchFullResult(ctx, wolframClientFor(event), last.Query, moreDigitsParams())
+	if err != nil {
+		log.Printf("ERROR: Unable to re-query %q with more digits. Error Msg: %v", last.Query, err)
+		respond("Sorry, I couldn't fetch more digits for that right now. :-(")
+		return true
+	}
+
+	precise := mostPreciseValue(result)
+	if precise == "" || precise == last.Answer {
+		respond(fmt.Sprintf("That's as precise as I can get for \"%s\": %s", last.Query, last.Answer))
+		return true
+	}
+
+	// Formatting for the asker's locale without the significant-digit cap, which would undo the extra precision
+	respond(fmt.Sprintf("With more precision: %s", localizeAnswerNumbers(precise, event.User, 0)))
+	rememberInteraction(event, interaction{Text: event.Msg.Text, EntityKey: last.EntityKey, Query: last.Query, Answer: precise, Calls: apiCalls(ctx)})
+	return true
+}
@@ -0,0 +1,115 @@
+//////////////////////////////////////////////////
+// More Precision Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing "more digits" follow-ups on numeric answers
+import (
+	"encoding/json" // Permits decoding of full results fixtures
+	"reflect"       // Permits comparison of query parameters
+	"testing"       // Permits Go testing
+)
+
+// Test for the re-query asking for the "More digits" state of each numeric pod, and only those pods
+func TestMoreDigitsParams(t *testing.T) {
+	params := moreDigitsParams()
+	if got, want := params["podstate"], []string{"DecimalApproximation__More digits", "Result__More digits"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("podstate = %q; want %q", got, want)
+	}
+	if got := params.Get("includepodid"); got != "DecimalApproximation,Result" {
+		t.Errorf("includepodid = %q; want DecimalApproximation,Result", got)
+	}
+}
+
+// Test for telling numeric answers, approximate, scientific, unit-bearing, or locale-formatted ones included, from
+// answers more digits can't improve
+func TestIsNumericAnswer(t *testing.T) {
+	cases := []struct {
+		answer string
+		want   bool
+	}{
+		{"3.14159", true},
+		{"  -42  ", true},
+		{"about 384400 km", true},
+		{"approximately 1.618...", true},
+		{"≈ 2.71828", true},
+		{"6.022×10^23 per mole", true},
+		{"1.6 x 10^(-19) coulombs", true},
+		{"1,234,567.89", true},
+		{"1.234.567,89", true},
+		{"1\u202f234\u202f567,89", true},
+		{"1'234'567.89", true},
+		{"7", true},
+		{"Paris", false},
+		{"", false},
+		{"x = 3", false},
+		{"3 to 4 hours", false},
+		{"between 5 and 6", false},
+		{"1998 - 2004", false},
+	}
+	for _, c := range cases {
+		if got := isNumericAnswer(c.answer); got != c.want {
+			t.Errorf("isNumericAnswer(%q) = %v; want %v", c.answer, got, c.want)
+		}
+	}
+}
+
+// Test for picking the longest numeric plaintext among the numeric pods, ignoring other pods and text answers
+func TestMostPreciseValue(t *testing.T) {
+	cases := []struct {
+		name    string
+		fixture string
+		want    string
+	}{
+		{"decimal approximation longest", `{"pods": [
+			{"id": "Input", "subpods": [{"plaintext": "3.14159265358979323846264338327950288419716939937510"}]},
+			{"id": "Result", "subpods": [{"plaintext": "3.14159"}]},
+			{"id": "DecimalApproximation", "subpods": [{"plaintext": "3.1415926535897932384626433832795028841971693993751..."}]}
+		]}`, "3.1415926535897932384626433832795028841971693993751..."},
+		{"result longest", `{"pods": [
+			{"id": "DecimalApproximation", "subpods": [{"plaintext": "1.41421"}]},
+			{"id": "Result", "subpods": [{"plaintext": "1.4142135623730950488"}]}
+		]}`, "1.4142135623730950488"},
+		{"text result skipped", `{"pods": [
+			{"id": "Result", "subpods": [{"plaintext": "the square root of two, an irrational number"}]},
+			{"id": "DecimalApproximation", "subpods": [{"plaintext": "1.41421356"}]}
+		]}`, "1.41421356"},
+		{"no numeric pod", `{"pods": [
+			{"id": "Input", "subpods": [{"plaintext": "42"}]},
+			{"id": "Result", "subpods": [{"plaintext": "Paris, France"}]}
+		]}`, ""},
+		{"no pods", `{"pods": []}`, ""},
+	}
+	for _, c := range cases {
+		var result fullResult
+		if err := json.Unmarshal([]byte(c.fixture), &result); err != nil {
+			t.Fatalf("%s: decoding the fixture: %v", c.name, err)
+		}
+		if got := mostPreciseValue(&result); got != c.want {
+			t.Errorf("%s: mostPreciseValue = %q; want %q", c.name, got, c.want)
+		}
+	}
+}
+
+// Test for recognizing the configured "more digits" commands whatever their case and trailing punctuation
+func TestIsMoreDigitsCommand(t *testing.T) {
+	withConfig(t, func(c *Config) { c.MoreDigitsCommands = []string{"more digits", "More Precision"} })
+	cases := []struct {
+		text string
+		want bool
+	}{
+		{"more digits", true},
+		{"  More digits?! ", true},
+		{"more precision.", true},
+		{"more digits of pi", false},
+		{"digits", false},
+	}
+	for _, c := range cases {
+		if got := isMoreDigitsCommand(c.text); got != c.want {
+			t.Errorf("isMoreDigitsCommand(%q) = %v; want %v", c.text, got, c.want)
+		}
+	}
+}
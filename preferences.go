@@ -0,0 +1,267 @@
+//////////////////////////////////////////////////
+// User Preferences Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for per-user settings such as preferred units
+import (
+	"fmt"     // Permits string formatting of replies
+	"log"     // Permits console logging
+	"regexp"  // Permits matching of settings commands
+	"strings" // Permits string normalization
+	"sync"    // Permits per-user serialization of preference changes
+
+	wolfram "github.com/Krognol/go-wolfram" // External Wolfram API
+	slack "github.com/nlopes/slack"         // External Slack API
+)
+
+// Global constant naming the store bucket holding per-user preferences
+const preferencesBucket = "preferences"
+
+// Global per-user locks serializing preference changes
+var preferenceLocks sync.Map
+
+// Global struct holding a user's explicit settings (empty fields mean "not set")
+type userPreferences struct {
+	Units    string
+	Locale   string
+	Tone     string
+	Delivery string
+	Language string
+	Detail   string
+}
+
+// Global patterns recognizing the settings commands
+var (
+	setUnitsPattern    = regexp.MustCompile(`(?i)^\s*(?:wolfy\s+)?set\s+(?:my\s+)?units\s+(?:to\s+)?(metric|imperial|default)\s*[.!]*\s*$`)
+	setLocalePattern   = regexp.MustCompile(`(?i)^\s*(?:wolfy\s+)?set\s+(?:my\s+)?(?:locale|number\s+format)\s+(?:to\s+)?([a-z]{2,3}(?:[-_][a-z]{2})?|default)\s*[.!]*\s*$`)
+	setTonePattern     = regexp.MustCompile(`(?i)^\s*(?:wolfy\s+)?set\s+(?:my\s+)?(?:tone|persona)\s+(?:to\s+)?(concise|friendly|formal|default)\s*[.!]*\s*$`)
+	setDetailPattern   = regexp.MustCompile(`(?i)^\s*(?:wolfy\s+)?set\s+(?:my\s+)?(?:detail|verbosity|style)\s+(?:to\s+)?(terse|normal|detailed|default)\s*[.!]*\s*$`)
+	setDeliveryPattern = regexp.MustCompile(`(?i)^\s*(?:wolfy\s+)?set\s+(?:my\s+)?(?:delivery|answers)\s+(?:to\s+)?(dm|channel|default)\s*[.!]*\s*$`)
+	setLanguagePattern = regexp.MustCompile(`(?i)^\s*(?:wolfy\s+)?(?:speak|(?:answer|reply|talk)\s+(?:to\s+me\s+)?in|set\s+(?:my\s+)?language\s+(?:to\s+)?)\s*(\S+)\s*(?:please)?\s*[.!]*\s*$`)
+	settingsPattern    = regexp.MustCompile(`(?i)^\s*(?:wolfy\s+)?(?:show\s+)?(?:my\s+)?settings\s*[?.!]*\s*$`)
+)
+
+// Registering the settings commands among the built-in capabilities
+func init() {
+	registerCapability("settings", "Say \"wolfy set units imperial\" (or metric/default) to choose units, \"wolfy set locale de-DE\" (or default) to choose number formatting, \"wolfy set tone concise\" (or friendly/formal/default) to choose how I word my replies, \"wolfy set style terse\" (or normal/detailed/default) to choose how much I say, \"wolfy set delivery dm\" (or channel/default) to choose where I answer questions asked in channels, \"wolfy speak French\" (or default) to choose the language I answer in, and \"wolfy settings\" to see yours.", nil)
+}
+
+// Global function for loading a user's explicit preferences
+func loadPreferences(user string) userPreferences {
+	var preferences userPreferences
+	store.get(preferencesBucket, user, &preferences)
+	return preferences
+}
+
+// Global function for changing a user's preferences under their lock, so settings changed at nearly the same time from
+// several clients each land on the latest saved state and none is lost; returns the preferences as saved
+func updatePreferences(user string, update func(*userPreferences)) (userPreferences, error) {
+	lock, _ := preferenceLocks.LoadOrStore(user, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	preferences := loadPreferences(user)
+	update(&preferences)
+	return preferences, store.put(preferencesBucket, user, preferences)
+}
+
+// Global function for parsing a units name
+func parseUnits(name string) (wolfram.Unit, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "metric":
+		return wolfram.Metric, true
+	case "imperial":
+		return wolfram.Imperial, true
+	}
+	return wolfram.Metric, false
+}
+
+// Global function for naming a units value
+func unitsName(units wolfram.Unit) string {
+	if units == wolfram.Imperial {
+		return "imperial"
+	}
+	return "metric"
+}
+
+// Global function for resolving a user's units and where that value came from:
+// explicit preference, then Slack locale (imperial for en-US), then workspace default, then metric
+func resolveUnits(user string) (wolfram.Unit, string) {
+	if units, ok := parseUnits(loadPreferences(user).Units); ok {
+		return units, "your preference"
+	}
+
+	if profile, err := lookupUser(user); err == nil && profile.Locale != "" {
+		locale := strings.Replace(profile.Locale, "_", "-", -1)
+		if strings.EqualFold(locale, "en-US") {
+			return wolfram.Imperial, fmt.Sprintf("your Slack locale (%s)", profile.Locale)
+		}
+		return wolfram.Metric, fmt.Sprintf("your Slack locale (%s)", profile.Locale)
+	} else if err != nil {
+		log.Printf("USER INFO ERROR: Unable to look up %s.\nError Details: %v", user, err)
+	}
+
+	if units, ok := parseUnits(config.DefaultUnits); ok {
+		return units, "the workspace default"
+	}
+	return wolfram.Metric, "the built-in default"
+}
+
+// Global function for describing where a user's channel questions are answered and where that came from
+func answerDelivery(user string) (string, string) {
+	where := map[bool]string{true: "in the channel", false: "by DM"}[answersInChannel(user)]
+	if loadPreferences(user).Delivery != "" {
+		return where, "your preference"
+	}
+	return where, "the workspace default"
+}
+
+// Global function for handling "set units", "set locale", "settings", and "preferences", reporting whether the message
+// was consumed
+func handleUserSettings(event *slack.MessageEvent) bool {
+	if preferencesMenuPattern.MatchString(event.Msg.Text) {
+		metrics.inc("wolfy_intents_total", "intent", "preferences_menu")
+		postPreferencesMenu(event)
+		return true
+	}
+
+	if match := setUnitsPattern.FindStringSubmatch(event.Msg.Text); match != nil {
+		metrics.inc("wolfy_intents_total", "intent", "set_units")
+		value := strings.ToLower(match[1])
+		if value == "default" {
+			value = ""
+		}
+		if _, err := updatePreferences(event.User, func(preferences *userPreferences) { preferences.Units = value }); err != nil {
+			log.Printf("PREFERENCES ERROR: Unable to save preferences for %s.\nError Details: %v", event.User, err)
+			postText(event.User, "Sorry, I couldn't save that setting right now. :-(")
+			return true
+		}
+
+		units, source := resolveUnits(event.User)
+		postText(event.User, fmt.Sprintf("Got it! I'll answer in %s units (from %s).", unitsName(units), source))
+		return true
+	}
+
+	if match := setLocalePattern.FindStringSubmatch(event.Msg.Text); match != nil {
+		metrics.inc("wolfy_intents_total", "intent", "set_locale")
+		value := normalizeLocale(match[1])
+		if value == "default" {
+			value = ""
+		}
+		if _, err := updatePreferences(event.User, func(preferences *userPreferences) { preferences.Locale = value }); err != nil {
+			log.Printf("PREFERENCES ERROR: Unable to save preferences for %s.\nError Details: %v", event.User, err)
+			postText(event.User, "Sorry, I couldn't save that setting right now. :-(")
+			return true
+		}
+
+		locale, source := resolveLocale(event.User)
+		postText(event.User, fmt.Sprintf("Got it! I'll format numbers like %s (%s, from %s).", localizeNumbers("1234567.89", locale, 0), locale, source))
+		return true
+	}
+
+	if match := setTonePattern.FindStringSubmatch(event.Msg.Text); match != nil {
+		metrics.inc("wolfy_intents_total", "intent", "set_tone")
+		value := strings.ToLower(match[1])
+		if value == "default" {
+			value = ""
+		}
+		preferences, err := updatePreferences(event.User, func(preferences *userPreferences) { preferences.Tone = value })
+		if err != nil {
+			log.Printf("PREFERENCES ERROR: Unable to save preferences for %s.\nError Details: %v", event.User, err)
+			postText(event.User, "Sorry, I couldn't save that setting right now. :-(")
+			return true
+		}
+
+		if preferences.Tone == "" {
+			postText(event.User, "Got it! I'll go back to the workspace's usual tone.")
+			return true
+		}
+		postText(event.User, fmt.Sprintf("Got it! I'll use a %s tone with you from now on.", preferences.Tone))
+		return true
+	}
+
+	if match := setDetailPattern.FindStringSubmatch(event.Msg.Text); match != nil {
+		metrics.inc("wolfy_intents_total", "intent", "set_detail")
+		value := strings.ToLower(match[1])
+		if value == "default" {
+			value = ""
+		}
+		preferences, err := updatePreferences(event.User, func(preferences *userPreferences) { preferences.Detail = value })
+		if err != nil {
+			log.Printf("PREFERENCES ERROR: Unable to save preferences for %s.\nError Details: %v", event.User, err)
+			postText(event.User, "Sorry, I couldn't save that setting right now. :-(")
+			return true
+		}
+
+		if preferences.Detail == "" {
+			postText(event.User, fmt.Sprintf("Got it! I'll keep answers %s in channels (or as the channel chose) and %s in DMs.", config.ChannelVerbosity, config.DMVerbosity))
+			return true
+		}
+		postText(event.User, fmt.Sprintf("Got it! I'll keep my answers %s everywhere.", preferences.Detail))
+		return true
+	}
+
+	if match := setDeliveryPattern.FindStringSubmatch(event.Msg.Text); match != nil {
+		metrics.inc("wolfy_intents_total", "intent", "set_delivery")
+		value := strings.ToLower(match[1])
+		if value == "default" {
+			value = ""
+		}
+		if _, err := updatePreferences(event.User, func(preferences *userPreferences) { preferences.Delivery = value }); err != nil {
+			log.Printf("PREFERENCES ERROR: Unable to save preferences for %s.\nError Details: %v", event.User, err)
+			postText(event.User, "Sorry, I couldn't save that setting right now. :-(")
+			return true
+		}
+
+		delivery, source := answerDelivery(event.User)
+		postText(event.User, fmt.Sprintf("Got it! I'll answer questions you ask in channels %s (from %s).", delivery, source))
+		return true
+	}
+
+	if match := setLanguagePattern.FindStringSubmatch(event.Msg.Text); match != nil {
+		metrics.inc("wolfy_intents_total", "intent", "set_language")
+		language, known := resolveLanguageName(match[1])
+		if strings.EqualFold(match[1], "default") {
+			language, known = "", true
+		}
+		if !known || (language != "" && !languageSupported(language)) {
+			postText(event.User, fmt.Sprintf("Sorry, I can't answer in %s yet. :-/ I can speak %s.", match[1], strings.Join(supportedLanguageNames(), ", ")))
+			return true
+		}
+		if _, err := updatePreferences(event.User, func(preferences *userPreferences) { preferences.Language = language }); err != nil {
+			log.Printf("PREFERENCES ERROR: Unable to save preferences for %s.\nError Details: %v", event.User, err)
+			postText(event.User, "Sorry, I couldn't save that setting right now. :-(")
+			return true
+		}
+
+		if language == "" {
+			postText(event.User, "Got it! I'll answer in whatever language you ask in.")
+			return true
+		}
+		postText(event.User, fmt.Sprintf("Got it! I'll answer you in %s from now on.", languageNames[language]))
+		return true
+	}
+
+	if settingsPattern.MatchString(event.Msg.Text) {
+		metrics.inc("wolfy_intents_total", "intent", "settings")
+		units, unitsSource := resolveUnits(event.User)
+		locale, localeSource := resolveLocale(event.User)
+		preferences := loadPreferences(event.User)
+		tone, language := preferences.Tone, "the language you ask in"
+		if tone == "" {
+			tone = "workspace default"
+		}
+		if preferences.Language != "" {
+			language = languageNames[preferences.Language]
+		}
+		detail, detailSource := answerVerbosity(event.User, event.Channel)
+		delivery, deliverySource := answerDelivery(event.User)
+		postText(event.User, fmt.Sprintf("*Your settings:*\n• Units: %s (from %s)\n• Number format: %s (from %s)\n• Tone: %s\n• Language: %s\n• Answer style: %s (from %s)\n• Channel questions answered: %s (from %s)", unitsName(units), unitsSource, locale, localeSource, tone, language, detail, detailSource, delivery, deliverySource))
+		return true
+	}
+	return false
+}
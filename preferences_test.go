@@ -0,0 +1,135 @@
+//////////////////////////////////////////////////
+// User Preferences Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing settings changed at nearly the same time from several clients
+import (
+	"strings" // Permits naming of synthetic users
+	"sync"    // Permits concurrent settings changes
+	"testing" // Permits Go testing
+	"time"    // Permits slow changes
+
+	wolfram "github.com/Krognol/go-wolfram" // External Wolfram API
+	slack "github.com/nlopes/slack"         // External Slack API
+)
+
+// Test for every setting changed concurrently by one user landing in the saved preferences, none overwritten by a
+// change made from a stale copy, and for each change returning the state it saved
+func TestConcurrentPreferenceUpdates(t *testing.T) {
+	const user, rounds = "UCONCURRENT", 10
+	t.Cleanup(func() { store.delete(preferencesBucket, user) })
+	cases := []struct {
+		values [2]string
+		set    func(*userPreferences, string)
+		get    func(userPreferences) string
+	}{
+		{[2]string{"metric", "imperial"}, func(p *userPreferences, v string) { p.Units = v }, func(p userPreferences) string { return p.Units }},
+		{[2]string{"en-GB", "de-DE"}, func(p *userPreferences, v string) { p.Locale = v }, func(p userPreferences) string { return p.Locale }},
+		{[2]string{"friendly", "formal"}, func(p *userPreferences, v string) { p.Tone = v }, func(p userPreferences) string { return p.Tone }},
+		{[2]string{"normal", "terse"}, func(p *userPreferences, v string) { p.Detail = v }, func(p userPreferences) string { return p.Detail }},
+		{[2]string{"channel", "dm"}, func(p *userPreferences, v string) { p.Delivery = v }, func(p userPreferences) string { return p.Delivery }},
+		{[2]string{"es", "fr"}, func(p *userPreferences, v string) { p.Language = v }, func(p userPreferences) string { return p.Language }},
+	}
+
+	// Toggling each setting from its own client, ending on the second value, with every change slow enough that the
+	// others start theirs while it is under way
+	var wg sync.WaitGroup
+	for _, c := range cases {
+		wg.Add(1)
+		go func(values [2]string, set func(*userPreferences, string), get func(userPreferences) string) {
+			defer wg.Done()
+			for round := 0; round < 2*rounds; round++ {
+				value := values[round%2]
+				saved, err := updatePreferences(user, func(p *userPreferences) {
+					time.Sleep(time.Millisecond)
+					set(p, value)
+				})
+				if err != nil || get(saved) != value {
+					t.Errorf("updatePreferences to %q = %q, %v; want the saved change back", value, get(saved), err)
+				}
+			}
+		}(c.values, c.set, c.get)
+	}
+	wg.Wait()
+
+	saved := loadPreferences(user)
+	for _, c := range cases {
+		if got := c.get(saved); got != c.values[1] {
+			t.Errorf("after toggling between %q and %q alongside the other settings, saved value = %q; want %q", c.values[0], c.values[1], got, c.values[1])
+		}
+	}
+}
+
+// Test for units resolving from the asker's preference, then their Slack locale (imperial only for en-US), then the
+// workspace default, then metric, each reporting where it came from
+func TestResolveUnitsOrder(t *testing.T) {
+	cases := []struct {
+		name      string
+		preferred string
+		locale    string
+		workspace string
+		units     wolfram.Unit
+		source    string
+	}{
+		{"preference over everything", "metric", "en-US", "imperial", wolfram.Metric, "your preference"},
+		{"imperial preference", "imperial", "de-DE", "metric", wolfram.Imperial, "your preference"},
+		{"US Slack locale", "", "en-US", "metric", wolfram.Imperial, "your Slack locale (en-US)"},
+		{"underscored Slack locale", "", "en_US", "metric", wolfram.Imperial, "your Slack locale (en_US)"},
+		{"other Slack locale", "", "en-GB", "imperial", wolfram.Metric, "your Slack locale (en-GB)"},
+		{"workspace default", "", "", "imperial", wolfram.Imperial, "the workspace default"},
+		{"unknown workspace default", "", "", "furlongs", wolfram.Metric, "the built-in default"},
+		{"built-in default", "", "", "", wolfram.Metric, "the built-in default"},
+	}
+	for i, c := range cases {
+		user := "UUNITS" + strings.ToUpper(string(rune('A'+i)))
+		withConfig(t, func(cfg *Config) { cfg.DefaultUnits = c.workspace })
+		if c.preferred != "" {
+			if err := store.put(preferencesBucket, user, userPreferences{Units: c.preferred}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		userInfo.put(user, &slack.User{ID: user, Locale: c.locale})
+		t.Cleanup(func() {
+			store.delete(preferencesBucket, user)
+			userInfo.remove(user)
+		})
+
+		if units, source := resolveUnits(user); units != c.units || source != c.source {
+			t.Errorf("%s: resolveUnits = %s from %q; want %s from %q", c.name, unitsName(units), source, unitsName(c.units), c.source)
+		}
+	}
+}
+
+// Test for "set units" confirming the units now in effect and where they came from, falling back down the order once
+// the preference is cleared
+func TestSetUnitsReportsSource(t *testing.T) {
+	const user = "UUNITSSET"
+	withConfig(t, func(c *Config) { c.DefaultUnits = "imperial" })
+	userInfo.put(user, &slack.User{ID: user, Locale: "de-DE"})
+	t.Cleanup(func() {
+		store.delete(preferencesBucket, user)
+		userInfo.remove(user)
+	})
+
+	cases := []struct {
+		text  string
+		reply string
+	}{
+		{"set units imperial", "Got it! I'll answer in imperial units (from your preference)."},
+		{"set units default", "Got it! I'll answer in metric units (from your Slack locale (de-DE))."},
+	}
+	for _, c := range cases {
+		before := len(postsContaining(user, "Got it!"))
+		if !handleUserSettings(testMessage(user, user, "7800.000001", c.text)) {
+			t.Fatalf("handleUserSettings(%q) didn't handle it", c.text)
+		}
+		waitFor(t, "the confirmation to be posted", func() bool { return len(postsContaining(user, "Got it!")) > before })
+		if reply := postsContaining(user, "Got it!")[before].values.Get("text"); reply != c.reply {
+			t.Errorf("reply to %q = %q; want %q", c.text, reply, c.reply)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+//////////////////////////////////////////////////
+// Preferences Menu Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for choosing preferences from select menus rather than typed commands
+import (
+	"fmt"     // Permits formatting of confirmations
+	"log"     // Permits console logging
+	"regexp"  // Permits matching of the preferences command
+	"sort"    // Permits ordering of the language menu
+	"strings" // Permits title-casing of option labels
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constant holding the callback ID the preferences menu is posted with
+const preferencesMenuCallback = "preferences_menu"
+
+// Global pattern recognizing "wolfy preferences"
+var preferencesMenuPattern = regexp.MustCompile(`(?i)^\s*(?:wolfy\s+)?(?:preferences|prefs)\s*[?.!]*\s*$`)
+
+// Global struct holding one menu: the preference it sets, the values it offers ("default" clears the preference), and
+// how it reads and writes the stored value
+type preferenceMenu struct {
+	name    string
+	label   string
+	options func() []slack.AttachmentActionOption
+	current func(userPreferences) string
+	apply   func(*userPreferences, string)
+}
+
+// Global menus offered, in the order shown
+var preferenceMenus = []preferenceMenu{
+	{"units", "Units", fixedMenuOptions("metric", "imperial"),
+		func(preferences userPreferences) string { return preferences.Units },
+		func(preferences *userPreferences, value string) { preferences.Units = value }},
+	{"tone", "Tone", fixedMenuOptions("concise", "friendly", "formal"),
+		func(preferences userPreferences) string { return preferences.Tone },
+		func(preferences *userPreferences, value string) { preferences.Tone = value }},
+	{"detail", "Answer style", fixedMenuOptions(answerStyles...),
+		func(preferences userPreferences) string { return preferences.Detail },
+		func(preferences *userPreferences, value string) { preferences.Detail = value }},
+	{"delivery", "Delivery", deliveryMenuOptions,
+		func(preferences userPreferences) string { return preferences.Delivery },
+		func(preferences *userPreferences, value string) { preferences.Delivery = value }},
+	{"language", "Language", languageMenuOptions,
+		func(preferences userPreferences) string { return preferences.Language },
+		func(preferences *userPreferences, value string) { preferences.Language = value }},
+}
+
+// Registering the preferences menu among the built-in capabilities, and its menus' handler
+func init() {
+	registerCapability("preferences_menu", "Say \"wolfy preferences\" to pick your units, tone, answer style, delivery, and language from menus, saved as soon as you choose.", nil)
+	registerInteraction(preferencesMenuCallback, handlePreferencesMenuAction)
+}
+
+// Global function for building a menu's options from fixed values, after the "default" choice
+func fixedMenuOptions(values ...string) func() []slack.AttachmentActionOption {
+	return func() []slack.AttachmentActionOption {
+		options := []slack.AttachmentActionOption{{Text: "Default", Value: "default"}}
+		for _, value := range values {
+			options = append(options, slack.AttachmentActionOption{Text: strings.Title(value), Value: value})
+		}
+		return options
+	}
+}
+
+// Global function for building the delivery menu, its values worded for reading
+func deliveryMenuOptions() []slack.AttachmentActionOption {
+	return []slack.AttachmentActionOption{{Text: "Default", Value: "default"}, {Text: "By DM", Value: "dm"}, {Text: "In the channel", Value: "channel"}}
+}
+
+// Global function for building the language menu from the languages the bot can answer in right now
+func languageMenuOptions() []slack.AttachmentActionOption {
+	var codes []string
+	for code := range languageNames {
+		if languageSupported(code) {
+			codes = append(codes, code)
+		}
+	}
+	sort.Slice(codes, func(i, j int) bool { return languageNames[codes[i]] < languageNames[codes[j]] })
+
+	options := []slack.AttachmentActionOption{{Text: "The language I'm asked in", Value: "default"}}
+	for _, code := range codes {
+		options = append(options, slack.AttachmentActionOption{Text: languageNames[code], Value: code})
+	}
+	return options
+}
+
+// Method for finding one of a menu's options by value, so only values the menu offers are ever saved
+func (menu preferenceMenu) option(value string) (slack.AttachmentActionOption, bool) {
+	for _, option := range menu.options() {
+		if option.Value == value {
+			return option, true
+		}
+	}
+	return slack.AttachmentActionOption{}, false
+}
+
+// Global function for building the menus, each showing the user's current value
+func preferencesMenuAttachments(preferences userPreferences) []slack.Attachment {
+	attachment := slack.Attachment{
+		CallbackID: preferencesMenuCallback,
+		Color:      config.AnswerAttachmentColor,
+		Fallback:   "Say \"wolfy settings\" to see your settings.",
+		Text:       "Pick a value to change it; it's saved right away.",
+	}
+	for _, menu := range preferenceMenus {
+		current := menu.current(preferences)
+		if current == "" {
+			current = "default"
+		}
+		action := slack.AttachmentAction{Name: menu.name, Text: menu.label, Type: "select", Options: menu.options()}
+		if selected, ok := menu.option(current); ok {
+			action.SelectedOptions = []slack.AttachmentActionOption{selected}
+		}
+		attachment.Actions = append(attachment.Actions, action)
+	}
+	return []slack.Attachment{attachment}
+}
+
+// Global function for posting the preferences menu where it was asked for: privately in a channel, else by DM. Without
+// a way for Slack to deliver selections, the typed commands are listed instead.
+func postPreferencesMenu(event *slack.MessageEvent) {
+	if !interactivityAvailable() {
+		postText(event.User, "Menus aren't set up here yet, but you can type your preferences: \"wolfy set units imperial\", \"wolfy set tone concise\", \"wolfy set detail terse\", or \"wolfy speak French\" (or \"default\" for any of them). \"wolfy settings\" shows yours.")
+		return
+	}
+	options := []slack.MsgOption{messageText("*Your preferences:*"), slack.MsgOptionAttachments(preferencesMenuAttachments(loadPreferences(event.User))...)}
+	if event.Channel != "" && !isDirectMessage(event.Channel) {
+		_, err := slackAPI().PostEphemeral(event.Channel, event.User, append(options, slack.MsgOptionAsUser(true))...)
+		if err == nil {
+			return
+		}
+		log.Printf("SLACK ERROR: Unable to post the preferences menu in %s.\nError Details: %v", event.Channel, err)
+	}
+	if _, _, err := postMessage(event.User, options...); err != nil {
+		log.Printf("SLACK ERROR: Unable to DM the preferences menu to %s.\nError Details: %v", event.User, err)
+	}
+}
+
+// Global function for saving a menu selection, checking it against the menu's own options so a forged payload can't
+// store anything else, and replacing the menu with a confirmation above the updated menus
+func handlePreferencesMenuAction(callback *slack.InteractionCallback) *interactionReply {
+	user := callback.User.ID
+	if user == "" || len(callback.Actions) == 0 || len(callback.Actions[0].SelectedOptions) == 0 {
+		return nil
+	}
+	action := callback.Actions[0]
+	value := action.SelectedOptions[0].Value
+
+	var (
+		menu   preferenceMenu
+		option slack.AttachmentActionOption
+		valid  bool
+	)
+	for _, candidate := range preferenceMenus {
+		if candidate.name == action.Name {
+			menu = candidate
+			option, valid = menu.option(value)
+		}
+	}
+	if !valid {
+		metrics.inc("wolfy_preferences_menu_total", "result", "invalid")
+		log.Printf("INTERACTIVITY ERROR: Refused the preference %q=%q from %s.", action.Name, value, user)
+		return &interactionReply{Text: "Sorry, that isn't a setting I can save. :-/", Attachments: preferencesMenuAttachments(loadPreferences(user))}
+	}
+	if value == "default" {
+		value = ""
+	}
+
+	preferences, err := updatePreferences(user, func(preferences *userPreferences) { menu.apply(preferences, value) })
+	if err != nil {
+		metrics.inc("wolfy_preferences_menu_total", "result", "failed")
+		log.Printf("PREFERENCES ERROR: Unable to save preferences for %s.\nError Details: %v", user, err)
+		return &interactionReply{Text: "Sorry, I couldn't save that setting right now. :-(", Attachments: preferencesMenuAttachments(loadPreferences(user))}
+	}
+	metrics.inc("wolfy_preferences_menu_total", "result", "saved", "preference", menu.name)
+	return &interactionReply{Text: fmt.Sprintf(":white_check_mark: Saved: %s is now %s.", strings.ToLower(menu.label), option.Text), Attachments: preferencesMenuAttachments(preferences)}
+}
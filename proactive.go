@@ -0,0 +1,120 @@
+//////////////////////////////////////////////////
+// Proactive Offers Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for privately offering answers to computable things said in opted-in channels
+import (
+	"fmt"     // Permits formatting of offers
+	"log"     // Permits console logging
+	"regexp"  // Permits spotting quantities and arithmetic in passing
+	"strings" // Permits string normalization
+	"sync"    // Permits concurrency-safe offer cooldowns
+	"time"    // Permits offer cooldowns
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global patterns spotting a quantity with a unit ("26.2 miles", "350°F") or bare arithmetic ("12 * 34") anywhere in a message
+var (
+	mentionedQuantityPattern   = regexp.MustCompile(`(?i)(?:^|[\s(])(-?\d+(?:\.\d+)?)\s*((?:°|º|degrees?\s+)?[a-z]+(?:/[a-z]+|\s+per\s+(?:second|hour))?)\b`)
+	mentionedArithmeticPattern = regexp.MustCompile(`(?:^|\s)(\d+(?:\.\d+)?\s*[*×^]\s*\d+(?:\.\d+)?(?:\s*[-+*×/^]\s*\d+(?:\.\d+)?)*)(?:\s|[?.!,]|$)`)
+)
+
+// Global table of the unit each unit is most usefully converted to (metric to imperial and back)
+var counterpartUnits = map[string]string{
+	"°C": "°F", "°F": "°C", "K": "°C",
+	"mm": "in", "cm": "in", "m": "ft", "km": "mi", "in": "cm", "ft": "m", "yd": "m", "mi": "km",
+	"g": "oz", "kg": "lb", "oz": "g", "lb": "kg", "st": "kg",
+	"mL": "fl oz", "L": "gal", "fl oz": "mL", "cup": "mL", "gal": "L",
+	"m/s": "mph", "km/h": "mph", "mph": "km/h", "kn": "km/h",
+}
+
+// Global record of when each user was last offered an answer in each channel
+var (
+	proactiveOffersMu sync.Mutex
+	proactiveOffers   = map[string]time.Time{}
+)
+
+// Registering the channel opt-in for proactive offers (off everywhere until enabled with "!flag enable proactive_offers #channel")
+func init() {
+	registerFeatureFlag(featureFlag{name: "proactive_offers", description: "Privately offer answers to quantities and arithmetic mentioned in passing, without an @-mention."})
+}
+
+// Global function for finding a computable question in a message not addressed to us, with a locally computed preview when we have one
+func proactiveQuestion(event *slack.MessageEvent) (string, string, bool) {
+	for _, match := range mentionedQuantityPattern.FindAllStringSubmatch(event.Text, -1) {
+		name := strings.ToLower(match[2])
+		// Skipping one-letter names ("5 m", "3 k") which are far more often words or shorthand than units
+		// (and "st", which is usually an ordinal like "1st")
+		if bare := strings.TrimSpace(unitPrefixPattern.ReplaceAllString(name, "")); (len(bare) < 2 && !unitPrefixPattern.MatchString(name)) || bare == "st" {
+			continue
+		}
+		unit, ok := lookupConversionUnit(name)
+		if !ok {
+			continue
+		}
+		question := fmt.Sprintf("%s %s in %s", match[1], unit.symbol, counterpartUnits[unit.symbol])
+		probe := *event
+		probe.Msg.Text = question
+		preview, _ := answerUnitConversion(&probe)
+		return question, preview, true
+	}
+	if match := mentionedArithmeticPattern.FindStringSubmatch(event.Text); match != nil {
+		question := strings.TrimSpace(match[1])
+		probe := *event
+		probe.Msg.Text = question
+		preview, _ := answerExactArithmetic(&probe)
+		return question, preview, true
+	}
+	return "", "", false
+}
+
+// Global function for claiming a user's offer cooldown in a channel, reporting whether an offer may be made now
+func claimProactiveOffer(user string, channel string, now time.Time) bool {
+	proactiveOffersMu.Lock()
+	defer proactiveOffersMu.Unlock()
+
+	key := channel + ":" + user
+	if last, ok := proactiveOffers[key]; ok && now.Sub(last) < config.ProactiveOfferCooldown {
+		return false
+	}
+	for offered, at := range proactiveOffers {
+		if now.Sub(at) >= config.ProactiveOfferCooldown {
+			delete(proactiveOffers, offered)
+		}
+	}
+	proactiveOffers[key] = now
+	return true
+}
+
+// Global function for privately offering an answer to a computable message in a channel that opted in
+func offerProactiveAnswer(event *slack.MessageEvent) {
+	if isDirectMessage(event.Channel) || event.SubType != "" || event.ThreadTimestamp != "" {
+		return
+	}
+	if !evaluateFlag("proactive_offers", flagScope{channel: event.Channel, team: event.Team}) {
+		return
+	}
+	question, preview, ok := proactiveQuestion(event)
+	if !ok || !claimProactiveOffer(event.User, event.Channel, time.Now()) {
+		return
+	}
+
+	metrics.inc("wolfy_proactive_offers_total")
+	mention := "me"
+	if currentBotUserID() != "" {
+		mention = "<@" + currentBotUserID() + ">"
+	}
+	offer := fmt.Sprintf("Want me to work that out? Mention %s with \"%s\" and I'll answer. _(Only you can see this.)_", mention, question)
+	if preview != "" {
+		offer = fmt.Sprintf("FYI, %s _(Only you can see this; mention %s with \"%s\" to share it.)_", preview, mention, question)
+	}
+	_, err := slackAPI().PostEphemeral(event.Channel, event.User, messageText(offer), slack.MsgOptionAsUser(true))
+	if err != nil {
+		log.Printf("ERROR: Unable to post proactive offer to %s. Error Msg: %v", event.Channel, err)
+	}
+}
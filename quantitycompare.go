@@ -0,0 +1,88 @@
+//////////////////////////////////////////////////
+// Quantity Comparisons Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for answering "is X bigger than Y" between quantities locally, with a definite yes or no
+import (
+	"fmt"     // Permits string formatting of replies
+	"math"    // Permits tolerance when quantities are equal
+	"regexp"  // Permits matching of comparison phrasings
+	"strconv" // Permits parsing of quantities
+	"strings" // Permits string normalization
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global pattern recognizing "is a mile longer than a kilometer" or "is 100 F hotter than 40 C": each side an optional
+// number (one when left out) and a unit, around a comparative
+var quantityComparisonPattern = regexp.MustCompile(`(?i)^\s*(?:is|are)\s+(?:an?\s+|one\s+|the\s+)?(-?\d+(?:\.\d+)?)?\s*(.+?)\s+(bigger|larger|greater|more|smaller|less|fewer|longer|taller|shorter|heavier|lighter|hotter|warmer|colder|cooler|faster|slower)\s+than\s+(?:an?\s+|one\s+|the\s+)?(-?\d+(?:\.\d+)?)?\s*(.+?)\s*[?.!]*\s*$`)
+
+// Global comparatives by whether they ask if the first quantity is the greater, with the dimension each fits ("" for any)
+var quantityComparatives = map[string]struct {
+	greater   bool
+	dimension string
+	opposite  string
+}{
+	"bigger": {true, "", "smaller"}, "larger": {true, "", "smaller"}, "greater": {true, "", "less"}, "more": {true, "", "less"},
+	"smaller": {false, "", "bigger"}, "less": {false, "", "more"}, "fewer": {false, "", "more"},
+	"longer": {true, "length", "shorter"}, "taller": {true, "length", "shorter"}, "shorter": {false, "length", "longer"},
+	"heavier": {true, "mass", "lighter"}, "lighter": {false, "mass", "heavier"},
+	"hotter": {true, "temperature", "colder"}, "warmer": {true, "temperature", "colder"},
+	"colder": {false, "temperature", "warmer"}, "cooler": {false, "temperature", "warmer"},
+	"faster": {true, "speed", "slower"}, "slower": {false, "speed", "faster"},
+}
+
+// Global function for parsing one side of a comparison, reading a missing number as one
+func parseComparedQuantity(number string, unitName string) (float64, conversionUnit, bool) {
+	value := 1.0
+	if number != "" {
+		parsed, err := strconv.ParseFloat(number, 64)
+		if err != nil {
+			return 0, conversionUnit{}, false
+		}
+		value = parsed
+	}
+	unit, ok := lookupConversionUnit(unitName)
+	return value, unit, ok
+}
+
+// Local answerer for comparisons between quantities in known units of one dimension, declining (so Wolfram answers)
+// when either unit is unknown, the units can't be compared, or the comparative doesn't fit them
+func answerQuantityComparison(event *slack.MessageEvent) (string, bool) {
+	match := quantityComparisonPattern.FindStringSubmatch(event.Msg.Text)
+	if match == nil {
+		return "", false
+	}
+	comparative := strings.ToLower(match[3])
+	meaning := quantityComparatives[comparative]
+	leftValue, left, ok := parseComparedQuantity(match[1], match[2])
+	if !ok {
+		return "", false
+	}
+	rightValue, right, ok := parseComparedQuantity(match[4], match[5])
+	if !ok || left.dimension != right.dimension || (meaning.dimension != "" && meaning.dimension != left.dimension) {
+		return "", false
+	}
+
+	// Comparing in the right-hand unit, so the reply shows the left quantity in terms the asker compared it to
+	converted := convertUnits(leftValue, left, right)
+	leftText, rightText := formatQuantity(leftValue, left), formatQuantity(rightValue, right)
+	if left.symbol != right.symbol {
+		leftText += " (" + formatQuantity(converted, right) + ")"
+	}
+
+	var reply string
+	switch difference := converted - rightValue; {
+	case math.Abs(difference) <= 1e-9*math.Max(math.Abs(converted), math.Abs(rightValue)):
+		reply = fmt.Sprintf("No - they're the same: %s equals %s.", leftText, rightText)
+	case (difference > 0) == meaning.greater:
+		reply = fmt.Sprintf("Yes - %s is %s than %s.", leftText, comparative, rightText)
+	default:
+		reply = fmt.Sprintf("No - %s is %s than %s.", leftText, meaning.opposite, rightText)
+	}
+	return localizeAnswerNumbers(reply, event.User, config.NumberSignificantDigits), true
+}
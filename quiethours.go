@@ -0,0 +1,198 @@
+//////////////////////////////////////////////////
+// Quiet Hours Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for keeping the bot out of a channel's main view outside its working hours
+import (
+	"fmt"     // Permits formatting of notes and errors
+	"log"     // Permits console logging
+	"strings" // Permits parsing of quiet hours specs
+	"time"    // Permits window arithmetic in the channel's timezone
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constant naming the store bucket holding quiet hours set at runtime with "!channel quiet"
+const channelQuietHoursBucket = "channel_quiet_hours"
+
+// Global struct holding a channel's daily quiet hours as local "15:04" times in a timezone; an end at or before the
+// start runs past midnight into the next day
+type quietHours struct {
+	Start    string
+	End      string
+	Timezone string
+}
+
+// Global function for parsing a quiet hours spec like "22:00-07:00 America/New_York", the timezone defaulting to the
+// one given
+func parseQuietHours(spec string, timezone string) (quietHours, error) {
+	fields := strings.Fields(spec)
+	if len(fields) < 1 || len(fields) > 2 {
+		return quietHours{}, fmt.Errorf("%q is not \"HH:MM-HH:MM [timezone]\"", spec)
+	}
+	bounds := strings.Split(fields[0], "-")
+	if len(bounds) != 2 {
+		return quietHours{}, fmt.Errorf("%q is not a start-end pair like 22:00-07:00", fields[0])
+	}
+	for _, bound := range bounds {
+		if _, err := time.Parse("15:04", bound); err != nil {
+			return quietHours{}, fmt.Errorf("%q is not a time like 22:00", bound)
+		}
+	}
+	if bounds[0] == bounds[1] {
+		return quietHours{}, fmt.Errorf("%q starts and ends at the same time", fields[0])
+	}
+
+	hours := quietHours{Start: bounds[0], End: bounds[1], Timezone: timezone}
+	if len(fields) == 2 {
+		hours.Timezone = fields[1]
+	}
+	if _, err := time.LoadLocation(hours.Timezone); err != nil {
+		return quietHours{}, fmt.Errorf("%q is not a known IANA timezone", hours.Timezone)
+	}
+	return hours, nil
+}
+
+// Method for describing quiet hours as they're written ("22:00-07:00 America/New_York")
+func (hours quietHours) String() string {
+	return fmt.Sprintf("%s-%s %s", hours.Start, hours.End, hours.Timezone)
+}
+
+// Method for finding the end of the quiet window a moment falls in, reporting false outside one. Each window is laid
+// out on the local calendar day it starts (yesterday's may still be running past midnight), so a DST change shifts
+// it with the clock rather than by a fixed number of hours.
+func (hours quietHours) until(now time.Time) (time.Time, bool) {
+	location, err := time.LoadLocation(hours.Timezone)
+	if err != nil {
+		return time.Time{}, false
+	}
+	start, errStart := time.Parse("15:04", hours.Start)
+	end, errEnd := time.Parse("15:04", hours.End)
+	if errStart != nil || errEnd != nil {
+		return time.Time{}, false
+	}
+
+	local := now.In(location)
+	for _, offset := range []int{-1, 0} {
+		day := local.Day() + offset
+		from := time.Date(local.Year(), local.Month(), day, start.Hour(), start.Minute(), 0, 0, location)
+		to := time.Date(local.Year(), local.Month(), day, end.Hour(), end.Minute(), 0, 0, location)
+		if !to.After(from) {
+			to = time.Date(local.Year(), local.Month(), day+1, end.Hour(), end.Minute(), 0, 0, location)
+		}
+		if !now.Before(from) && now.Before(to) {
+			return to, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Global function for looking up a channel's quiet hours: those set at runtime with "!channel", else the configured
+// ones. DMs never have any.
+func channelQuietHours(channel string) (quietHours, bool) {
+	if channel == "" || isDirectMessage(channel) {
+		return quietHours{}, false
+	}
+	var hours quietHours
+	if store != nil && store.get(channelQuietHoursBucket, channel, &hours) {
+		return hours, true
+	}
+	if spec, ok := config.ChannelQuietHours[channel]; ok {
+		if hours, err := parseQuietHours(spec, config.DefaultTimezone); err == nil {
+			return hours, true
+		}
+	}
+	return quietHours{}, false
+}
+
+// Global function for finding when a channel's current quiet hours end, reporting false when it isn't in them
+func quietHoursEnd(channel string, now time.Time) (time.Time, bool) {
+	hours, ok := channelQuietHours(channel)
+	if !ok {
+		return time.Time{}, false
+	}
+	return hours.until(now)
+}
+
+// Global function for checking whether a channel is in its quiet hours
+func inQuietHours(channel string, now time.Time) bool {
+	_, quiet := quietHoursEnd(channel, now)
+	return quiet
+}
+
+// Global function for noting on an answer sent to the asker's DM instead of the channel they asked in that quiet hours
+// are why
+func quietHoursNote(event *slack.MessageEvent, channel string) string {
+	if channel == event.Channel || isDirectMessage(event.Channel) || !answersInChannel(event.User) {
+		return ""
+	}
+	end, quiet := quietHoursEnd(event.Channel, time.Now())
+	if !quiet {
+		return ""
+	}
+	metrics.inc("wolfy_quiet_hours_redirects_total")
+	return fmt.Sprintf("\n_(<#%s> is in quiet hours until %s, so I answered here.)_", event.Channel, formatMaintenanceTime(end, userLocation(event.User)))
+}
+
+// Global function for holding a scheduled query bound for a channel thread until the channel's quiet hours end,
+// storing it due then so a restart keeps it held; reports whether it was held (callers hold scheduleMu)
+func holdForQuietHours(query scheduledQuery, now time.Time) bool {
+	if query.ThreadTS == "" {
+		// Delivered by DM, which quiet hours never hold
+		return false
+	}
+	end, quiet := quietHoursEnd(query.Channel, now)
+	if !quiet {
+		return false
+	}
+	held := query
+	held.Due, held.QuietHeld = end, true
+	if err := store.put(scheduledQueriesBucket, query.ID, held); err != nil {
+		log.Printf("SCHEDULE ERROR: Unable to hold scheduled query %s for quiet hours.\nError Details: %v", query.ID, err)
+		return false
+	}
+	metrics.inc("wolfy_scheduled_queries_held_total")
+	return true
+}
+
+// Global function for describing a channel's quiet hours for "!channel show", with when the current ones end in the
+// viewer's timezone
+func describeChannelQuietHours(channel string, viewer string) string {
+	hours, ok := channelQuietHours(channel)
+	if !ok {
+		return "none"
+	}
+	if end, quiet := hours.until(time.Now()); quiet {
+		return fmt.Sprintf("%s (in effect until %s)", hours, formatMaintenanceTime(end, userLocation(viewer)))
+	}
+	return hours.String()
+}
+
+// Global function for setting or clearing a channel's quiet hours at runtime, for "!channel quiet"
+func setChannelQuietHours(event *slack.MessageEvent, channel string, args []string, usage string) string {
+	if len(args) == 1 && strings.EqualFold(args[0], "clear") {
+		store.delete(channelQuietHoursBucket, channel)
+		log.Printf("ADMIN: %s cleared the quiet hours for %s.", event.User, channel)
+		if configured, ok := channelQuietHours(channel); ok {
+			return fmt.Sprintf("Cleared the runtime quiet hours for <#%s>; it's back to the configured `%s`.", channel, configured)
+		}
+		return fmt.Sprintf("Cleared the quiet hours for <#%s>; I'll answer there at any hour.", channel)
+	}
+	if len(args) == 0 || len(args) > 2 {
+		return usage
+	}
+	hours, err := parseQuietHours(strings.Join(args, " "), config.DefaultTimezone)
+	if err != nil {
+		return fmt.Sprintf("Sorry, %v. %s", err, usage)
+	}
+	if err := store.put(channelQuietHoursBucket, channel, hours); err != nil {
+		log.Printf("ADMIN ERROR: Unable to persist the quiet hours for %s.\nError Details: %v", channel, err)
+		return "Sorry, I couldn't save that setting. :-("
+	}
+	log.Printf("ADMIN: %s set the quiet hours for %s to %s.", event.User, channel, hours)
+	return fmt.Sprintf("<#%s> now has quiet hours of `%s`: during them I'll answer by DM and hold scheduled posts until they end.", channel, hours)
+}
@@ -0,0 +1,198 @@
+//////////////////////////////////////////////////
+// Quiet Hours Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing quiet windows over midnight and DST, their exemptions, and held scheduled posts
+import (
+	"strings" // Permits inspection of notes and posts
+	"testing" // Permits Go testing
+	"time"    // Permits the fake clock
+)
+
+// Global function for giving channels quiet hours in config for one test, each spec taken as written
+func withQuietHours(t *testing.T, specs map[string]string) {
+	withConfig(t, func(c *Config) {
+		c.ChannelQuietHours = specs
+		c.DefaultTimezone = "UTC"
+	})
+}
+
+// Global function for building a quiet hours spec in UTC running from an hour before a moment to an hour after it
+func quietAround(now time.Time) string {
+	now = now.UTC()
+	return now.Add(-time.Hour).Format("15:04") + "-" + now.Add(time.Hour).Format("15:04") + " UTC"
+}
+
+// Test for a window spanning midnight taking in both sides of it and ending on the next local day, a daytime window
+// ending the same day, and both ending exactly at their end time
+func TestQuietHoursWindow(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no timezone data: %v", err)
+	}
+	overnight := quietHours{Start: "22:00", End: "07:00", Timezone: "America/New_York"}
+	lunch := quietHours{Start: "12:00", End: "13:30", Timezone: "UTC"}
+	local := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, newYork)
+	}
+	cases := []struct {
+		hours quietHours
+		now   time.Time
+		quiet bool
+		until time.Time
+	}{
+		{overnight, local(time.June, 10, 21, 59), false, time.Time{}},
+		{overnight, local(time.June, 10, 22, 0), true, local(time.June, 11, 7, 0)},
+		{overnight, local(time.June, 10, 23, 59), true, local(time.June, 11, 7, 0)},
+		{overnight, local(time.June, 11, 0, 0), true, local(time.June, 11, 7, 0)},
+		{overnight, local(time.June, 11, 6, 59), true, local(time.June, 11, 7, 0)},
+		{overnight, local(time.June, 11, 7, 0), false, time.Time{}},
+		{overnight, local(time.December, 31, 23, 0), true, local(time.January, 1, 7, 0).AddDate(1, 0, 0)},
+		{overnight, local(time.June, 10, 12, 0).In(time.UTC), false, time.Time{}},
+		{overnight, local(time.June, 11, 3, 0).In(time.UTC), true, local(time.June, 11, 7, 0)},
+		{lunch, time.Date(2026, time.June, 10, 11, 59, 0, 0, time.UTC), false, time.Time{}},
+		{lunch, time.Date(2026, time.June, 10, 13, 0, 0, 0, time.UTC), true, time.Date(2026, time.June, 10, 13, 30, 0, 0, time.UTC)},
+		{lunch, time.Date(2026, time.June, 10, 13, 30, 0, 0, time.UTC), false, time.Time{}},
+	}
+	for _, c := range cases {
+		until, quiet := c.hours.until(c.now)
+		if quiet != c.quiet || !until.Equal(c.until) {
+			t.Errorf("%s at %s: until = %s, %v; want %s, %v", c.hours, c.now.Format(time.RFC3339), until.Format(time.RFC3339), quiet, c.until.Format(time.RFC3339), c.quiet)
+		}
+	}
+}
+
+// Test for an overnight window following the local clock across a DST change, so the night it springs forward is an
+// hour shorter and the night it falls back an hour longer, with the window still ending at 07:00 local
+func TestQuietHoursAcrossDST(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no timezone data: %v", err)
+	}
+	overnight := quietHours{Start: "22:00", End: "07:00", Timezone: "America/New_York"}
+	cases := []struct {
+		name   string
+		start  time.Time
+		length time.Duration
+	}{
+		{"spring forward", time.Date(2026, time.March, 7, 22, 0, 0, 0, newYork), 8 * time.Hour},
+		{"fall back", time.Date(2026, time.October, 31, 22, 0, 0, 0, newYork), 10 * time.Hour},
+		{"ordinary night", time.Date(2026, time.June, 10, 22, 0, 0, 0, newYork), 9 * time.Hour},
+	}
+	for _, c := range cases {
+		until, quiet := overnight.until(c.start)
+		if !quiet || until.Sub(c.start) != c.length {
+			t.Errorf("%s: window from %s ends %s (quiet %v), %s long; want %s", c.name, c.start.Format(time.RFC3339), until.Format(time.RFC3339), quiet, until.Sub(c.start), c.length)
+		}
+		if local := until.In(newYork); local.Hour() != 7 || local.Minute() != 0 {
+			t.Errorf("%s: the window ends at %s local; want 07:00", c.name, local.Format("15:04"))
+		}
+		// Just before the end, the window is still running; at the end, it isn't
+		if _, quiet := overnight.until(until.Add(-time.Minute)); !quiet {
+			t.Errorf("%s: not quiet a minute before the window ends", c.name)
+		}
+		if _, quiet := overnight.until(until); quiet {
+			t.Errorf("%s: still quiet once the window ends", c.name)
+		}
+	}
+}
+
+// Test for answers asked in a quiet channel going to the asker's DM with a note, while DMs are never quiet and admin
+// alerts still reach an admin channel in its quiet hours
+func TestQuietHoursExemptions(t *testing.T) {
+	now := time.Now()
+	withQuietHours(t, map[string]string{"CQUIET": quietAround(now), "DQUIET": quietAround(now), "CQUIETADMIN": quietAround(now)})
+	withConfig(t, func(c *Config) {
+		c.AnswerInChannel = true
+		c.AdminChannel = "CQUIETADMIN"
+		c.AnnouncementsEnabled = true
+	})
+
+	asked := testMessage("CQUIET", "UQUIET", "7600.000001", "what is 5 * 6")
+	if channel := answerDestination(asked); channel != "UQUIET" {
+		t.Errorf("answerDestination in quiet hours = %q; want the asker's DM", channel)
+	}
+	if note := quietHoursNote(asked, "UQUIET"); !strings.Contains(note, "<#CQUIET> is in quiet hours") {
+		t.Errorf("quietHoursNote on the redirected answer = %q; want the quiet hours named", note)
+	}
+	if note := quietHoursNote(asked, "CQUIET"); note != "" {
+		t.Errorf("quietHoursNote on an answer in the channel = %q; want none", note)
+	}
+
+	direct := testMessage("DQUIET", "UQUIET", "7600.000002", "what is 5 * 6")
+	if inQuietHours("DQUIET", now) {
+		t.Error("a DM configured with quiet hours is in them; want DMs exempt")
+	}
+	if note := quietHoursNote(direct, "UQUIET"); note != "" {
+		t.Errorf("quietHoursNote on a DM answer = %q; want none", note)
+	}
+
+	if !inQuietHours("CQUIETADMIN", now) {
+		t.Fatal("the admin channel isn't in its quiet hours")
+	}
+	before := len(postsContaining("CQUIETADMIN", "going down for maintenance"))
+	announceShutdown()
+	waitFor(t, "the admin alert to be posted", func() bool {
+		return len(postsContaining("CQUIETADMIN", "going down for maintenance")) > before
+	})
+}
+
+// Test for a scheduled answer due in a quiet channel's thread being held in the store until the hours end and then
+// released, while one delivered by DM runs on time
+func TestQuietHoursHoldScheduledQueries(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	withQuietHours(t, map[string]string{"CQUIETSCHEDULE": quietAround(now)})
+	end, quiet := quietHoursEnd("CQUIETSCHEDULE", now)
+	if !quiet {
+		t.Fatal("the channel isn't in its quiet hours")
+	}
+	queries := []scheduledQuery{
+		{ID: "quiet-thread", User: "UQUIET", Channel: "CQUIETSCHEDULE", ThreadTS: "7600.000003", Query: "weather in Paris", Due: now.Add(-time.Minute)},
+		{ID: "quiet-dm", User: "UQUIET", Channel: "CQUIETSCHEDULE", Query: "weather in Paris", Due: now.Add(-time.Minute)},
+	}
+	for _, query := range queries {
+		if err := store.put(scheduledQueriesBucket, query.ID, query); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() {
+		for _, query := range queries {
+			store.delete(scheduledQueriesBucket, query.ID)
+			for _, key := range store.keys(claimedQueriesBucket) {
+				if strings.HasPrefix(key, query.ID+"@") {
+					store.delete(claimedQueriesBucket, key)
+				}
+			}
+		}
+	})
+	claimed := func(at time.Time) []string {
+		var ids []string
+		for _, query := range claimDueQueries(at) {
+			if strings.HasPrefix(query.ID, "quiet-") {
+				ids = append(ids, query.ID)
+			}
+		}
+		return ids
+	}
+
+	if ids := claimed(now); len(ids) != 1 || ids[0] != "quiet-dm" {
+		t.Errorf("claimed during quiet hours = %v; want only [quiet-dm]", ids)
+	}
+	var held scheduledQuery
+	if !store.get(scheduledQueriesBucket, "quiet-thread", &held) || !held.QuietHeld || !held.Due.Equal(end) {
+		t.Fatalf("the thread-bound query in the store = %+v; want it held until %s", held, end)
+	}
+	if ids := claimed(end.Add(-time.Second)); len(ids) != 0 {
+		t.Errorf("claimed just before the quiet hours end = %v; want none", ids)
+	}
+	if ids := claimed(end); len(ids) != 1 || ids[0] != "quiet-thread" {
+		t.Errorf("claimed once the quiet hours end = %v; want [quiet-thread]", ids)
+	}
+	if store.get(scheduledQueriesBucket, "quiet-thread", &held) {
+		t.Errorf("the released query is still scheduled: %+v", held)
+	}
+}
@@ -0,0 +1,184 @@
+//////////////////////////////////////////////////
+// Quoted Questions Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for answering the question someone quoted or shared rather than the hand-off around it
+import (
+	"context" // Permits carrying the quoted question's author to the reply
+	"fmt"     // Permits formatting of the credit note
+	"log"     // Permits console logging
+	"regexp"  // Permits recognition of hand-off phrases
+	"strings" // Permits splitting of quote lines
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constants holding Slack's escaped quote markers: one quoted line ("&gt; ..."), and a block quoting the rest
+// of the message ("&gt;&gt;&gt; ...")
+const (
+	quoteLineMarker  = "&gt;"
+	quoteBlockMarker = "&gt;&gt;&gt;"
+)
+
+// Global struct holding the question a message quoted or shared, and who first asked it when that is known
+type quotedQuestion struct {
+	Text   string
+	Author string // Slack user ID of the original author, when the shared message carried one
+	Name   string // Display name of the original author, when it carried no ID
+}
+
+// Global context key type carrying the quoted question a message is answered from
+type quotedQuestionKey struct{}
+
+// Global patterns recognizing a hand-off around a quote: the filler words dropped before matching ("hey", "please",
+// "wolfy"), and the phrases that only pass the quote on ("can you answer this", "thoughts?"); anything else counts as
+// a question of the asker's own
+var (
+	handOffFillerPattern = regexp.MustCompile(`\b(?:hey|hi|yo|ok|okay|so|please|pls|plz|wolfy)\b`)
+	handOffPattern       = regexp.MustCompile(`^(?:` +
+		`(?:can|could|would|will) you (?:answer|take|handle|check|field|get|solve) (?:this|that|it)(?: one| question)?` +
+		`|(?:can|could|would|will) you help(?: me)?(?: with)? (?:this|that|it)(?: one| question)?` +
+		`|(?:can|could|would|will) you (?:look|have a look|take a look) at (?:this|that|it)(?: one| question)?` +
+		`|answer (?:this|that)(?: one| question)?` +
+		`|any (?:idea|ideas|thoughts)` +
+		`|thoughts|help|this|this one|see above|over to you|question for you|for you` +
+		`|what do you think|do you know(?: this| that| the answer)?|know (?:this|that)` +
+		`|)$`)
+	handOffSeparatorPattern = regexp.MustCompile(`\s+(?:—|–|--|-)\s+|,\s+`)
+)
+
+// Global function for checking whether the text around a quote only hands it on to the bot ("can you answer this,
+// wolfy?", a bare "wolfy?", nothing at all) rather than asking something of its own
+func isHandOffPhrase(text string) bool {
+	text = strings.ToLower(trimSpaceAndPunct(text))
+	for _, prefix := range config.TriggerPrefixes {
+		if prefix = strings.ToLower(trimSpaceAndPunct(prefix)); prefix != "" {
+			text = regexp.MustCompile(`\b`+regexp.QuoteMeta(prefix)+`\b`).ReplaceAllString(text, " ")
+		}
+	}
+	text = handOffFillerPattern.ReplaceAllString(text, " ")
+	text = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(",.!?;:", r) {
+			return ' '
+		}
+		return r
+	}, text)
+	return handOffPattern.MatchString(strings.Join(strings.Fields(text), " "))
+}
+
+// Global function for trimming a hand-off clause trailing inside a quote ("what's the escape velocity of Mars — can
+// you answer this, wolfy?"), leaving the quote as it was when the tail is a question of its own
+func stripHandOffClause(text string) string {
+	separators := handOffSeparatorPattern.FindAllStringIndex(text, -1)
+	for i := 0; i < len(separators); i++ {
+		head, tail := text[:separators[i][0]], text[separators[i][1]:]
+		if strings.TrimSpace(head) != "" && trimSpaceAndPunct(tail) != "" && isHandOffPhrase(tail) {
+			return strings.TrimSpace(head)
+		}
+	}
+	return text
+}
+
+// Global function for splitting a message's quoted lines from the text around them, reporting false when it quotes
+// nothing ("&gt;&gt;&gt;" quotes every line after it)
+func splitQuotedText(text string) (string, string, bool) {
+	var quoted, rest []string
+	block := false
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case block:
+			quoted = append(quoted, trimmed)
+		case strings.HasPrefix(trimmed, quoteBlockMarker):
+			block = true
+			quoted = append(quoted, strings.TrimSpace(strings.TrimPrefix(trimmed, quoteBlockMarker)))
+		case strings.HasPrefix(trimmed, quoteLineMarker):
+			quoted = append(quoted, strings.TrimSpace(strings.TrimPrefix(trimmed, quoteLineMarker)))
+		default:
+			rest = append(rest, trimmed)
+		}
+	}
+	if len(quoted) == 0 {
+		return "", text, false
+	}
+	return strings.Join(strings.Fields(strings.Join(quoted, " ")), " "), strings.Join(strings.Fields(strings.Join(rest, " ")), " "), true
+}
+
+// Global function for finding a message shared into this one: an attachment with an author and text but no title
+// (link previews carry a title), skipping the bot's own answers
+func sharedMessage(event *slack.MessageEvent) (slack.Attachment, bool) {
+	for _, attachment := range event.Msg.Attachments {
+		if strings.TrimSpace(attachment.Text) == "" || attachment.Title != "" || (attachment.AuthorID == "" && attachment.AuthorName == "") {
+			continue
+		}
+		if currentBotUserID() != "" && attachment.AuthorID == currentBotUserID() {
+			continue
+		}
+		return attachment, true
+	}
+	return slack.Attachment{}, false
+}
+
+// Global function for choosing the question a message asks when it quotes or shares someone else's: the quote when
+// the text around it only hands it on, the asker's own text otherwise (the quote markers dropped either way),
+// reporting what was decided for logging ("" when the message quotes nothing)
+func resolveQuotedQuestion(event *slack.MessageEvent) (quotedQuestion, string, string) {
+	quoted, rest, ok := splitQuotedText(event.Msg.Text)
+	var quote quotedQuestion
+	if shared, found := sharedMessage(event); found && !ok {
+		quoted, ok = strings.Join(strings.Fields(shared.Text), " "), true
+		quote.Author, quote.Name = shared.AuthorID, shared.AuthorName
+	}
+	if !ok {
+		return quote, event.Msg.Text, ""
+	}
+	quote.Text = stripHandOffClause(quoted)
+
+	switch {
+	case quote.Text == "":
+		return quotedQuestion{}, rest, "empty"
+	case strings.HasPrefix(quote.Text, "!"):
+		// Never running someone else's quoted command as if the asker had typed it
+		return quotedQuestion{}, rest, "command"
+	case !isHandOffPhrase(rest):
+		return quotedQuestion{}, rest, "own_question"
+	}
+	if quote.Author == event.User {
+		quote.Author, quote.Name = "", ""
+	}
+	return quote, quote.Text, "quoted"
+}
+
+// Global function for answering the question a message quotes when the asker only handed it on, rewriting the
+// message text and carrying the quote (and its author) to the reply
+func attachQuotedQuestion(ctx context.Context, event *slack.MessageEvent) context.Context {
+	quote, text, result := resolveQuotedQuestion(event)
+	if result == "" {
+		return ctx
+	}
+	metrics.inc("wolfy_quoted_questions_total", "result", result)
+	log.Printf("QUOTES: %s quoted a message (%s); asking %q.", messageEventKey(event), result, text)
+	event.Msg.Text = text
+	if result != "quoted" {
+		traceStep(ctx, "dropped a quote (%s); asking %q", result, text)
+		return ctx
+	}
+	traceStep(ctx, "answering the quoted question %q", text)
+	return context.WithValue(ctx, quotedQuestionKey{}, quote)
+}
+
+// Global function for crediting the quoted question's original author below its answer, when they are known
+func withQuoteCredit(ctx context.Context, reply string) string {
+	quote, _ := ctx.Value(quotedQuestionKey{}).(quotedQuestion)
+	switch {
+	case quote.Author != "":
+		return reply + fmt.Sprintf("\n_(Answering <@%s>'s question.)_", quote.Author)
+	case quote.Name != "":
+		return reply + fmt.Sprintf("\n_(Answering %s's question.)_", escapeSlackText(quote.Name))
+	}
+	return reply
+}
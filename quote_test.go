@@ -0,0 +1,124 @@
+//////////////////////////////////////////////////
+// Quoted Questions Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing the hand-off heuristics deciding which question a quoting message asks
+import (
+	"context" // Permits carrying of the quote to the credit note
+	"testing" // Permits Go testing
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Test for telling a bare hand-off of a quote from a question of the asker's own, filler words and trigger prefixes
+// aside
+func TestIsHandOffPhrase(t *testing.T) {
+	withConfig(t, func(c *Config) { c.TriggerPrefixes = []string{"wolfbot"} })
+	cases := []struct {
+		text string
+		want bool
+	}{
+		{"", true},
+		{"wolfy?", true},
+		{"Thoughts?", true},
+		{"hey wolfy, can you answer this?", true},
+		{"could you take a look at this one please", true},
+		{"any ideas", true},
+		{"wolfbot: what do you think?", true},
+		{"over to you!", true},
+		{"do you know the answer?", true},
+		{"what about Venus?", false},
+		{"can you answer this for Jupiter instead", false},
+		{"is this right", false},
+		{"thoughtsmith", false},
+	}
+	for _, c := range cases {
+		if got := isHandOffPhrase(c.text); got != c.want {
+			t.Errorf("isHandOffPhrase(%q) = %v; want %v", c.text, got, c.want)
+		}
+	}
+}
+
+// Test for trimming a hand-off clause trailing inside a quote, and only a hand-off
+func TestStripHandOffClause(t *testing.T) {
+	cases := []struct {
+		text string
+		want string
+	}{
+		{"what's the escape velocity of Mars — can you answer this, wolfy?", "what's the escape velocity of Mars"},
+		{"distance to the moon - thoughts?", "distance to the moon"},
+		{"population of France, any ideas?", "population of France"},
+		{"population of France, Germany, and Spain", "population of France, Germany, and Spain"},
+		{"boiling point of water - in celsius", "boiling point of water - in celsius"},
+		{"thoughts?", "thoughts?"},
+	}
+	for _, c := range cases {
+		if got := stripHandOffClause(c.text); got != c.want {
+			t.Errorf("stripHandOffClause(%q) = %q; want %q", c.text, got, c.want)
+		}
+	}
+}
+
+// Test for choosing the quoted or shared question when the message only hands it on, and the asker's own otherwise
+func TestResolveQuotedQuestion(t *testing.T) {
+	withConfig(t, func(c *Config) { c.TriggerPrefixes = nil })
+	shared := []slack.Attachment{{AuthorID: "UORIGINAL", Text: "how tall is   Everest?"}}
+	cases := []struct {
+		name        string
+		text        string
+		attachments []slack.Attachment
+		user        string
+		result      string
+		asked       string
+		quote       quotedQuestion
+	}{
+		{name: "quote alone", text: "&gt; distance to mars", result: "quoted", asked: "distance to mars", quote: quotedQuestion{Text: "distance to mars"}},
+		{name: "quote with a hand-off", text: "&gt; distance to mars\ncan you answer this, wolfy?", result: "quoted", asked: "distance to mars", quote: quotedQuestion{Text: "distance to mars"}},
+		{name: "block quote over lines", text: "thoughts?\n&gt;&gt;&gt; how many moons\ndoes jupiter have", result: "quoted", asked: "how many moons does jupiter have", quote: quotedQuestion{Text: "how many moons does jupiter have"}},
+		{name: "hand-off trailing inside the quote", text: "&gt; distance to mars - any ideas?", result: "quoted", asked: "distance to mars", quote: quotedQuestion{Text: "distance to mars"}},
+		{name: "own question", text: "&gt; distance to mars\nwhat about venus?", result: "own_question", asked: "what about venus?"},
+		{name: "quoted command", text: "&gt; !handler disable capabilities --force\nwolfy?", result: "command", asked: "wolfy?"},
+		{name: "empty quote", text: "&gt;\nwhat is 2 + 2", result: "empty", asked: "what is 2 + 2"},
+		{name: "no quote", text: "distance to mars", result: "", asked: "distance to mars"},
+		{name: "shared message", text: "wolfy?", attachments: shared, result: "quoted", asked: "how tall is Everest?", quote: quotedQuestion{Text: "how tall is Everest?", Author: "UORIGINAL"}},
+		{name: "own shared message", text: "", attachments: shared, user: "UORIGINAL", result: "quoted", asked: "how tall is Everest?", quote: quotedQuestion{Text: "how tall is Everest?"}},
+		{name: "shared by name only", text: "", attachments: []slack.Attachment{{AuthorName: "Ada", Text: "speed of light"}}, result: "quoted", asked: "speed of light", quote: quotedQuestion{Text: "speed of light", Name: "Ada"}},
+		{name: "link preview", text: "wolfy?", attachments: []slack.Attachment{{AuthorName: "Wikipedia", Title: "Mars", Text: "Mars is the fourth planet"}}, result: "", asked: "wolfy?"},
+	}
+	for _, c := range cases {
+		event := testMessage("CQUOTE", "UASKER", "7300.000001", c.text)
+		if c.user != "" {
+			event.User = c.user
+		}
+		event.Msg.Attachments = c.attachments
+		quote, asked, result := resolveQuotedQuestion(event)
+		if result != c.result || asked != c.asked || quote != c.quote {
+			t.Errorf("%s: resolveQuotedQuestion(%q) = %+v, %q, %q; want %+v, %q, %q", c.name, c.text, quote, asked, result, c.quote, c.asked, c.result)
+		}
+	}
+}
+
+// Test for crediting a quoted question's author by mention, or by escaped name when only that is known
+func TestWithQuoteCredit(t *testing.T) {
+	cases := []struct {
+		quote quotedQuestion
+		want  string
+	}{
+		{quotedQuestion{Text: "speed of light", Author: "UORIGINAL"}, "299792 km/s\n_(Answering <@UORIGINAL>'s question.)_"},
+		{quotedQuestion{Text: "speed of light", Name: "Ada <Admin>"}, "299792 km/s\n_(Answering Ada &lt;Admin&gt;'s question.)_"},
+		{quotedQuestion{Text: "speed of light"}, "299792 km/s"},
+	}
+	for _, c := range cases {
+		ctx := context.WithValue(context.Background(), quotedQuestionKey{}, c.quote)
+		if got := withQuoteCredit(ctx, "299792 km/s"); got != c.want {
+			t.Errorf("withQuoteCredit(%+v) = %q; want %q", c.quote, got, c.want)
+		}
+	}
+	if got := withQuoteCredit(context.Background(), "299792 km/s"); got != "299792 km/s" {
+		t.Errorf("withQuoteCredit without a quote = %q; want the reply unchanged", got)
+	}
+}
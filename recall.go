@@ -0,0 +1,50 @@
+//////////////////////////////////////////////////
+// Conversation Recall Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for answering questions about the current conversation
+import (
+	"fmt"    // Permits string formatting of replies
+	"regexp" // Permits matching of recall phrasings
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global patterns recognizing "what did I ask?" and "what did you answer?" style questions
+var (
+	recallQuestionPattern = regexp.MustCompile(`(?i)\bwhat\s+(did|was)\s+(i\s+(just\s+)?ask(ed)?|my\s+(last|previous)\s+question)\b`)
+	recallAnswerPattern   = regexp.MustCompile(`(?i)\bwhat\s+(was|is)\s+(the|your)\s+(last|previous)\s+answer\b|\bwhat\s+did\s+you\s+(just\s+)?(say|answer)\b`)
+)
+
+// Registering recall among the built-in capabilities
+func init() {
+	registerCapability("conversation_recall", "Ask \"what did I ask?\" or \"what was your last answer?\" to recall our recent exchange.", nil)
+}
+
+// Global function for replying from the user's session memory, reporting whether the message was consumed
+func handleConversationRecall(event *slack.MessageEvent) bool {
+	askedQuestion := recallQuestionPattern.MatchString(event.Msg.Text)
+	askedAnswer := recallAnswerPattern.MatchString(event.Msg.Text)
+	if !askedQuestion && !askedAnswer {
+		return false
+	}
+
+	metrics.inc("wolfy_intents_total", "intent", "conversation_recall")
+
+	last, ok := lastInteraction(event.User)
+	if !ok {
+		postText(event.User, "I don't have anything from our recent conversation yet. Ask me a question first! :-)")
+		return true
+	}
+
+	if askedQuestion {
+		postText(event.User, fmt.Sprintf("You asked: \"%s\"", isolateDirection(last.Text)))
+	} else {
+		postText(event.User, fmt.Sprintf("My last answer to you was: %s", isolateDirection(last.Answer)))
+	}
+	return true
+}
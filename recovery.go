@@ -0,0 +1,298 @@
+//////////////////////////////////////////////////
+// Backend Recovery Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for easing a degraded backend back into use rather than releasing the whole backlog at it at once
+import (
+	"context" // Permits marking probes and giving up on waiting requests
+	"fmt"     // Permits formatting of the recovery summary
+	"log"     // Permits console logging
+	"regexp"  // Permits matching of "wolfy status"
+	"sort"    // Permits stable ordering of the recovery summary
+	"strings" // Permits joining of status lines
+	"sync"    // Permits concurrency-safe ramp state
+	"time"    // Permits probe pacing and the ramp schedule
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constants naming the recovery states: requests flow freely when closed, wait while the backend is probed
+// when open, and are paced at a climbing rate while ramping back up
+const (
+	recoveryClosed  = "closed"
+	recoveryOpen    = "open"
+	recoveryRamping = "ramping"
+)
+
+// Global struct holding one backend's recovery state
+type recoveryRamp struct {
+	state    string
+	since    time.Time     // When the state was entered
+	probesOK int           // Consecutive successful probes while open
+	nextSlot time.Time     // When the next paced request may go while ramping
+	waiting  int64         // Requests waiting for the backend
+	changed  chan struct{} // Closed at each transition, waking requests waiting for the backend to reopen
+}
+
+// Global context key marking a request as a synthetic probe, which never waits on the ramp it is probing for
+type recoveryProbeKey struct{}
+
+// Global registry of recovery state by backend
+var (
+	recoveryMu    sync.Mutex
+	recoveryRamps = map[string]*recoveryRamp{}
+)
+
+// Global cheap synthetic calls probing each backend, the same ones "!diagnose" makes, filled at init time (the
+// probes reach back here through the backends' health, so they can't be set while package variables initialize)
+var recoveryProbes map[string]func(ctx context.Context) error
+
+// Global gauge values for each recovery state
+var recoveryStateValues = map[string]int64{recoveryClosed: 0, recoveryRamping: 1, recoveryOpen: 2}
+
+// Global pattern recognizing "wolfy status"
+var statusPattern = regexp.MustCompile(`(?i)^\s*(?:wolfy\s+)?status\s*[?.!]*\s*$`)
+
+// Registering the status command among the built-in capabilities, and the recovery probes
+func init() {
+	recoveryProbes = map[string]func(ctx context.Context) error{
+		"wit": func(ctx context.Context) error {
+			_, err := probeWit(ctx, &slack.MessageEvent{})
+			return err
+		},
+		"wolfram": func(ctx context.Context) error {
+			_, err := probeWolfram(ctx, &slack.MessageEvent{})
+			return err
+		},
+	}
+	registerCapability("status", "Say \"wolfy status\" to see whether Wit.ai and Wolfram|Alpha are healthy, or being eased back in after an outage.", nil)
+}
+
+// Global function for looking up a backend's recovery state, creating it closed (callers hold recoveryMu)
+func rampFor(api string) *recoveryRamp {
+	ramp, ok := recoveryRamps[api]
+	if !ok {
+		ramp = &recoveryRamp{state: recoveryClosed, changed: make(chan struct{})}
+		recoveryRamps[api] = ramp
+	}
+	return ramp
+}
+
+// Method for moving to a state, waking any request waiting on the last one
+func (ramp *recoveryRamp) transition(api string, state string, now time.Time) {
+	log.Printf("RECOVERY: %s went from %s to %s.", backendNames[api], ramp.state, state)
+	metrics.inc("wolfy_recovery_transitions_total", "api", api, "to", state)
+	metrics.set(recoveryStateValues[state], "wolfy_recovery_state", "api", api)
+	ramp.state, ramp.since, ramp.probesOK, ramp.nextSlot = state, now, 0, now
+	close(ramp.changed)
+	ramp.changed = make(chan struct{})
+}
+
+// Method for finding the paced rate, in requests a minute, a ramp allows at a moment: climbing in a straight line from
+// the starting rate to the full one over the ramp period
+func (ramp *recoveryRamp) rate(now time.Time) int {
+	elapsed := now.Sub(ramp.since)
+	if config.RecoveryRampPeriod <= 0 || elapsed >= config.RecoveryRampPeriod {
+		return config.RecoveryFullRate
+	}
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	climb := float64(config.RecoveryFullRate-config.RecoveryStartRate) * float64(elapsed) / float64(config.RecoveryRampPeriod)
+	return config.RecoveryStartRate + int(climb)
+}
+
+// Method for reserving a request's turn at a moment: how long it waits while ramping (closing the ramp once its
+// period is over), or true when the backend is open and it must wait for the probes
+func (ramp *recoveryRamp) reserve(api string, now time.Time) (time.Duration, bool) {
+	switch ramp.state {
+	case recoveryOpen:
+		return 0, true
+	case recoveryRamping:
+		if now.Sub(ramp.since) >= config.RecoveryRampPeriod {
+			ramp.transition(api, recoveryClosed, now)
+			return 0, false
+		}
+		slot := ramp.nextSlot
+		if slot.Before(now) {
+			slot = now
+		}
+		ramp.nextSlot = slot.Add(time.Minute / time.Duration(ramp.rate(slot)))
+		return slot.Sub(now), false
+	}
+	return 0, false
+}
+
+// Method for counting a probe's result, reporting whether enough have passed in a row to let requests through again,
+// ramping up (or straight to closed when there is no ramp period)
+func (ramp *recoveryRamp) noteProbe(api string, ok bool, now time.Time) bool {
+	if ramp.state != recoveryOpen {
+		return true
+	}
+	if !ok {
+		ramp.probesOK = 0
+		return false
+	}
+	ramp.probesOK++
+	if ramp.probesOK < config.RecoveryProbes {
+		return false
+	}
+	if config.RecoveryRampPeriod > 0 {
+		ramp.transition(api, recoveryRamping, now)
+	} else {
+		ramp.transition(api, recoveryClosed, now)
+	}
+	return true
+}
+
+// Global function for opening a backend's recovery once it is marked degraded, holding real requests back while
+// synthetic probes check it (a backend already open is left probing)
+func openRecovery(api string, now time.Time) {
+	if config.RecoveryProbes <= 0 || recoveryProbes[api] == nil {
+		return
+	}
+	recoveryMu.Lock()
+	defer recoveryMu.Unlock()
+	ramp := rampFor(api)
+	if ramp.state == recoveryOpen {
+		return
+	}
+	ramp.transition(api, recoveryOpen, now)
+	go probeUntilRecovered(api)
+}
+
+// Global function for probing an open backend once per probe interval until enough probes pass in a row
+func probeUntilRecovered(api string) {
+	ticker := time.NewTicker(config.RecoveryProbeInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), recoveryProbeKey{}, true), config.DiagnoseTimeout)
+		err := recoveryProbes[api](ctx)
+		cancel()
+		if noteRecoveryProbe(api, err, time.Now()) {
+			return
+		}
+	}
+}
+
+// Global function for recording a probe's result at a moment, reporting whether the backend may take requests again
+func noteRecoveryProbe(api string, err error, now time.Time) bool {
+	metrics.inc("wolfy_recovery_probes_total", "api", api, "result", map[bool]string{true: "ok", false: "failed"}[err == nil])
+	if err != nil {
+		log.Printf("RECOVERY ERROR: Probe of %s failed.\nError Details: %v", backendNames[api], err)
+	}
+
+	recoveryMu.Lock()
+	defer recoveryMu.Unlock()
+	return rampFor(api).noteProbe(api, err == nil, now)
+}
+
+// Global function for waiting for a request's turn at a recovering backend: until the probes pass while it is open,
+// then at the ramp's pace; probes and backends in no recovery go straight through
+func awaitRecovery(ctx context.Context, api string) error {
+	if config.RecoveryProbes <= 0 || ctx.Value(recoveryProbeKey{}) != nil {
+		return nil
+	}
+	for {
+		recoveryMu.Lock()
+		ramp := rampFor(api)
+		wait, open := ramp.reserve(api, time.Now())
+		if !open && wait <= 0 {
+			recoveryMu.Unlock()
+			return nil
+		}
+		changed := ramp.changed
+		ramp.waiting++
+		metrics.set(ramp.waiting, "wolfy_recovery_waiting", "api", api)
+		recoveryMu.Unlock()
+
+		var err error
+		if open {
+			traceStep(ctx, "%s is recovering; waiting for its probes", backendNames[api])
+			select {
+			case <-changed:
+			case <-ctx.Done():
+				err = ctx.Err()
+			}
+		} else {
+			traceStep(ctx, "%s is ramping back up; paced for %s", backendNames[api], wait.Round(time.Millisecond))
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				err = ctx.Err()
+			}
+		}
+
+		recoveryMu.Lock()
+		ramp.waiting--
+		metrics.set(ramp.waiting, "wolfy_recovery_waiting", "api", api)
+		recoveryMu.Unlock()
+		if err != nil || !open {
+			return err
+		}
+	}
+}
+
+// Global function for describing each backend's recovery, one line apiece (empty when none is recovering)
+func describeRecovery(now time.Time) []string {
+	recoveryMu.Lock()
+	defer recoveryMu.Unlock()
+
+	apis := make([]string, 0, len(recoveryRamps))
+	for api := range recoveryRamps {
+		apis = append(apis, api)
+	}
+	sort.Strings(apis)
+	var lines []string
+	for _, api := range apis {
+		ramp := recoveryRamps[api]
+		if ramp.state == recoveryRamping && now.Sub(ramp.since) >= config.RecoveryRampPeriod {
+			ramp.transition(api, recoveryClosed, now)
+		}
+		switch {
+		case ramp.state == recoveryOpen:
+			lines = append(lines, fmt.Sprintf("%s: recovering since %s, %d of %d probes passed, %d requests waiting", backendNames[api], ramp.since.Format("15:04:05"), ramp.probesOK, config.RecoveryProbes, ramp.waiting))
+		case ramp.state == recoveryRamping && now.Sub(ramp.since) < config.RecoveryRampPeriod:
+			lines = append(lines, fmt.Sprintf("%s: ramping back up at %d/min of %d/min, %s to go, %d requests waiting", backendNames[api], ramp.rate(now), config.RecoveryFullRate, (config.RecoveryRampPeriod-now.Sub(ramp.since)).Round(time.Second), ramp.waiting))
+		}
+	}
+	return lines
+}
+
+// Global function for summarizing recovery under way for "!stats", each line after a line break (empty when none is)
+func describeRecoveryStats(now time.Time) string {
+	var summary string
+	for _, line := range describeRecovery(now) {
+		summary += "\n" + line
+	}
+	return summary
+}
+
+// Global function for replying with backend health and any recovery under way, reporting whether the message was consumed
+func handleStatus(event *slack.MessageEvent) bool {
+	if !statusPattern.MatchString(event.Msg.Text) {
+		return false
+	}
+	metrics.inc("wolfy_intents_total", "intent", "status")
+
+	now := time.Now()
+	lines := []string{"*Status:*"}
+	if !externalAPIsAllowed() {
+		lines = append(lines, "• External APIs are off; I'm answering from local answers only.")
+	}
+	for _, name := range degradedBackends(now) {
+		lines = append(lines, "• "+name+" is degraded.")
+	}
+	for _, line := range describeRecovery(now) {
+		lines = append(lines, "• "+line+".")
+	}
+	if len(lines) == 1 {
+		lines = append(lines, "• Wit.ai and Wolfram|Alpha are both healthy. :white_check_mark:")
+	}
+	postText(event.User, strings.Join(lines, "\n"))
+	return true
+}
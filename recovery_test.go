@@ -0,0 +1,160 @@
+//////////////////////////////////////////////////
+// Backend Recovery Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing degraded backends being probed and ramped back into use
+import (
+	"context" // Permits waiting requests and probe contexts
+	"errors"  // Permits scripted probe failures
+	"strings" // Permits inspection of the recovery summary
+	"testing" // Permits Go testing
+	"time"    // Permits the fake clock and the ramp schedule
+)
+
+// Global function for opening a recovery for a backend no other test uses, at a given moment, without starting the
+// probe loop, and forgetting it afterwards
+func withOpenRecovery(t *testing.T, api string, now time.Time) {
+	recoveryMu.Lock()
+	rampFor(api).transition(api, recoveryOpen, now)
+	recoveryMu.Unlock()
+	t.Cleanup(func() {
+		recoveryMu.Lock()
+		delete(recoveryRamps, api)
+		recoveryMu.Unlock()
+	})
+}
+
+// Global function for reserving a request's turn at a backend at a moment, reporting the wait and whether it is open
+func reserveRecovery(api string, now time.Time) (time.Duration, bool) {
+	recoveryMu.Lock()
+	defer recoveryMu.Unlock()
+	return rampFor(api).reserve(api, now)
+}
+
+// Global function for reading a backend's recovery state
+func recoveryState(api string) string {
+	recoveryMu.Lock()
+	defer recoveryMu.Unlock()
+	return rampFor(api).state
+}
+
+// Test for a scripted backend being held open until enough probes pass in a row, then paced at a rate climbing from
+// the starting rate to the full one over the ramp period, then let through unpaced, all on a fake clock
+func TestRecoveryRampSchedule(t *testing.T) {
+	const api = "test_ramp"
+	withConfig(t, func(c *Config) {
+		c.RecoveryProbes = 2
+		c.RecoveryProbeInterval = 10 * time.Second
+		c.RecoveryRampPeriod = 2 * time.Minute
+		c.RecoveryStartRate = 10
+		c.RecoveryFullRate = 130
+	})
+	clock := time.Unix(1700000000, 0)
+	withOpenRecovery(t, api, clock)
+	if wait, open := reserveRecovery(api, clock); !open || wait != 0 {
+		t.Fatalf("reserve while open = %s, %v; want 0s, true", wait, open)
+	}
+
+	// Probing once per interval, a failure resetting the passes in a row
+	failure := errors.New("probe timed out")
+	script := []struct {
+		err  error
+		done bool
+	}{{failure, false}, {nil, false}, {failure, false}, {nil, false}, {nil, true}}
+	for i, probe := range script {
+		clock = clock.Add(10 * time.Second)
+		if done := noteRecoveryProbe(api, probe.err, clock); done != probe.done {
+			t.Fatalf("probe %d (err %v) done = %v; want %v", i+1, probe.err, done, probe.done)
+		}
+	}
+	if state := recoveryState(api); state != recoveryRamping {
+		t.Fatalf("state after the probes passed = %q; want %q", state, recoveryRamping)
+	}
+	ramped := clock
+
+	// Pacing a burst at the start of the ramp, 10/min climbing by 1/min a second, then the middle at 70/min
+	cases := []struct {
+		at   time.Duration
+		wait time.Duration
+	}{
+		{0, 0},
+		{0, 6 * time.Second},
+		{0, 9750 * time.Millisecond},
+		{time.Minute, 0},
+		{time.Minute, time.Minute / 70},
+	}
+	for i, c := range cases {
+		if wait, open := reserveRecovery(api, ramped.Add(c.at)); open || wait != c.wait {
+			t.Errorf("request %d at %s into the ramp waits %s (open %v); want %s", i+1, c.at, wait, open, c.wait)
+		}
+	}
+	if summary := strings.Join(describeRecovery(ramped.Add(time.Minute)), "\n"); !strings.Contains(summary, "ramping back up at 70/min of 130/min, 1m0s to go") {
+		t.Errorf("recovery summary halfway through = %q; want the rate and time left", summary)
+	}
+
+	// Closing the ramp once its period is over
+	if wait, open := reserveRecovery(api, ramped.Add(2*time.Minute)); open || wait != 0 {
+		t.Errorf("request at the end of the ramp waits %s (open %v); want 0s", wait, open)
+	}
+	if state := recoveryState(api); state != recoveryClosed {
+		t.Errorf("state after the ramp period = %q; want %q", state, recoveryClosed)
+	}
+}
+
+// Test for requests waiting on an open backend until its probes pass, giving up when their context ends, while
+// probes themselves go straight through
+func TestAwaitRecoveryWaitsForProbes(t *testing.T) {
+	const api = "test_await"
+	withConfig(t, func(c *Config) {
+		c.RecoveryProbes = 1
+		c.RecoveryRampPeriod = 0
+	})
+	withOpenRecovery(t, api, time.Now())
+
+	if err := awaitRecovery(context.WithValue(context.Background(), recoveryProbeKey{}, true), api); err != nil {
+		t.Errorf("awaitRecovery for a probe = %v; want nil without waiting", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	abandoned, released := make(chan error, 1), make(chan error, 1)
+	go func() { abandoned <- awaitRecovery(ctx, api) }()
+	go func() { released <- awaitRecovery(context.Background(), api) }()
+	waitFor(t, "both requests to wait", func() bool {
+		recoveryMu.Lock()
+		defer recoveryMu.Unlock()
+		return rampFor(api).waiting == 2
+	})
+
+	cancel()
+	select {
+	case err := <-abandoned:
+		if err != context.Canceled {
+			t.Errorf("awaitRecovery after its context ended = %v; want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the abandoned request kept waiting")
+	}
+	select {
+	case err := <-released:
+		t.Fatalf("a request went through before the probe passed (err %v)", err)
+	default:
+	}
+
+	if !noteRecoveryProbe(api, nil, time.Now()) {
+		t.Fatal("a passing probe didn't close the recovery")
+	}
+	select {
+	case err := <-released:
+		if err != nil {
+			t.Errorf("awaitRecovery once the probe passed = %v; want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the waiting request wasn't released once the probe passed")
+	}
+	if state := recoveryState(api); state != recoveryClosed {
+		t.Errorf("state after the probe passed with no ramp period = %q; want %q", state, recoveryClosed)
+	}
+}
@@ -0,0 +1,61 @@
+//////////////////////////////////////////////////
+// Review Sampling Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for sampling a share of answers into a review channel where maintainers spot-check their quality
+import (
+	"crypto/sha256"   // Permits deterministic sampling by correlation ID
+	"encoding/binary" // Permits reading a sampling bucket from the hash
+	"fmt"             // Permits formatting of review entries
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global function for building a message's correlation ID, the Slack channel and timestamp it was sent at (stable across
+// restarts and redeliveries)
+func reviewCorrelationID(event *slack.MessageEvent) string {
+	return messageEventKey(event)
+}
+
+// Global function for deciding whether a correlation ID falls in the review sample, the same way every time it is asked
+func sampledForReview(correlationID string, percent float64) bool {
+	if percent <= 0 {
+		return false
+	}
+	sum := sha256.Sum256([]byte(correlationID))
+	return float64(binary.BigEndian.Uint64(sum[:8])%10000) < percent*100
+}
+
+// Global function for posting a sampled exchange to the review channel in the background, with its question, intent,
+// confidence, interpretation, and answer, redacted and kept out of private conversations as the archive is
+func sampleInteractionForReview(event *slack.MessageEvent, entry interaction) {
+	if config.ReviewChannel == "" || event.Channel == config.ReviewChannel || event.Timestamp == "" || entry.Text == "" {
+		return
+	}
+	correlationID := reviewCorrelationID(event)
+	if !sampledForReview(correlationID, config.ReviewSamplePercent) {
+		return
+	}
+	go func() {
+		if !config.ArchivePrivate && isPrivateConversation(event.Channel) {
+			metrics.inc("wolfy_review_samples_total", "result", "private")
+			return
+		}
+
+		where := "a DM"
+		if event.Channel != "" && !isDirectMessage(event.Channel) {
+			where = "<#" + event.Channel + ">"
+		}
+		text := fmt.Sprintf(":mag: *Review sample* `%s` (intent `%s`, %.2f confidence, %s)\n*Q* (%s in %s): %s", correlationID, intentLabel(entry.EntityKey), entry.Confidence, entry.Outcome, archiveUserName(event.User), where, redactSecrets(archiveText(entry.Text)))
+		if entry.Query != "" && entry.Query != entry.Text {
+			text += "\n*Interpreted as:* " + redactSecrets(archiveText(entry.Query))
+		}
+		text += "\n*A*: " + redactSecrets(archiveText(entry.Answer))
+		postText(config.ReviewChannel, text)
+		metrics.inc("wolfy_review_samples_total", "result", "posted")
+	}()
+}
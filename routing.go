@@ -0,0 +1,153 @@
+//////////////////////////////////////////////////
+// Entity Routing Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for the reloadable Wit.ai entity to handler routing table
+import (
+	"context"       // Permits pinning a message to the table it started with
+	"encoding/json" // Permits decoding of the routing file
+	"fmt"           // Permits formatting of reload reports
+	"io/ioutil"     // Permits reading of the routing file
+	"log"           // Permits console logging
+	"sort"          // Permits stable ordering of reload reports
+	"strings"       // Permits joining of reload reports
+	"sync/atomic"   // Permits lock-free swapping of the table
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global type mapping Wit.ai entity keys to registered handler names
+type routingTable map[string]string
+
+// Global context key type for the routing table a message started with
+type routingTableKey struct{}
+
+// Global holder of the current routing table, swapped whole on reload so readers never see a partial table
+var currentRouting atomic.Value
+
+// Global function for building the default table, where every handler answers the entity key matching its name
+func defaultRouting() routingTable {
+	table := routingTable{}
+	for name := range entityHandlers {
+		table[name] = name
+	}
+	return table
+}
+
+// Global function for reading the routing file's "routing" section on top of the defaults, reporting every bad entry
+func readRouting() (routingTable, []string, error) {
+	table := defaultRouting()
+	if config.RoutingFile == "" {
+		return table, nil, nil
+	}
+	data, err := ioutil.ReadFile(config.RoutingFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	var source struct {
+		Routing map[string]string `json:"routing"`
+	}
+	if err := json.Unmarshal(data, &source); err != nil {
+		return nil, nil, err
+	}
+
+	var problems []string
+	for entityKey, handlerName := range source.Routing {
+		if _, ok := entityHandlers[handlerName]; !ok {
+			problems = append(problems, fmt.Sprintf("`%s` -> unknown handler `%s`", entityKey, handlerName))
+			continue
+		}
+		table[entityKey] = handlerName
+	}
+	sort.Strings(problems)
+	return table, problems, nil
+}
+
+// Global function for loading the routing table at startup, falling back to the defaults when the file is unusable
+func loadRouting() {
+	table, problems, err := readRouting()
+	if err != nil {
+		log.Printf("CONFIG ERROR: Unable to load routing file %s.\nError Details: %v", config.RoutingFile, err)
+		table = defaultRouting()
+	} else if len(problems) > 0 {
+		log.Printf("CONFIG ERROR: Routing file %s has bad entries, using defaults: %s", config.RoutingFile, strings.Join(problems, "; "))
+		table = defaultRouting()
+	}
+	currentRouting.Store(table)
+}
+
+// Global function for pinning the current routing table to a context so a message finishes on the table it started with
+func withRoutingTable(ctx context.Context) context.Context {
+	return context.WithValue(ctx, routingTableKey{}, currentRouting.Load().(routingTable))
+}
+
+// Global function for resolving the handler for an entity key through the context's (or the current) routing table
+func routeEntity(ctx context.Context, entityKey string) (*entityHandler, bool) {
+	table, ok := ctx.Value(routingTableKey{}).(routingTable)
+	if !ok {
+		table, _ = currentRouting.Load().(routingTable)
+	}
+	handler, ok := entityHandlers[table[entityKey]]
+	return handler, ok
+}
+
+// Global function for describing the differences between two routing tables
+func describeRoutingChanges(previous routingTable, next routingTable) []string {
+	var changes []string
+	for entityKey, handlerName := range next {
+		if old, ok := previous[entityKey]; !ok {
+			changes = append(changes, fmt.Sprintf("added `%s` -> `%s`", entityKey, handlerName))
+		} else if old != handlerName {
+			changes = append(changes, fmt.Sprintf("changed `%s`: `%s` -> `%s`", entityKey, old, handlerName))
+		}
+	}
+	for entityKey, handlerName := range previous {
+		if _, ok := next[entityKey]; !ok {
+			changes = append(changes, fmt.Sprintf("removed `%s` -> `%s`", entityKey, handlerName))
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
+
+// Admin command re-reading the routing file and swapping the table in, or keeping the old one when validation fails; or
+// re-reading the message catalogs and personality packs
+func runAdminReload(event *slack.MessageEvent, args []string) string {
+	if len(args) == 1 && strings.ToLower(args[0]) == "messages" {
+		return reloadMessageCatalogs(event)
+	}
+	if len(args) != 1 || strings.ToLower(args[0]) != "routing" {
+		return "Usage: `!reload routing` or `!reload messages`"
+	}
+	if config.RoutingFile == "" {
+		return "No routing file is configured (set `WOLFY_ROUTING_FILE`); every handler answers the entity key matching its name."
+	}
+
+	table, problems, err := readRouting()
+	if err != nil {
+		log.Printf("ROUTING ERROR: Reload by %s failed.\nError Details: %v", event.User, err)
+		return fmt.Sprintf("Routing reload failed, keeping the current table: %v", err)
+	}
+	if len(problems) > 0 {
+		return "Routing reload rejected, keeping the current table. Bad entries:\n• " + strings.Join(problems, "\n• ")
+	}
+
+	previous := currentRouting.Load().(routingTable)
+	currentRouting.Store(table)
+	loadIntentSynonyms()
+	metrics.inc("wolfy_routing_reloads_total")
+	changes := describeRoutingChanges(previous, table)
+	log.Printf("ADMIN: %s reloaded routing (%d changes).", event.User, len(changes))
+	reply := "Routing reloaded - no changes."
+	if len(changes) > 0 {
+		reply = "Routing reloaded:\n• " + strings.Join(changes, "\n• ")
+	}
+	if check := checkWitEntities("routing reload"); check != "" {
+		reply += "\n" + check
+	}
+	return reply
+}
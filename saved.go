@@ -0,0 +1,185 @@
+//////////////////////////////////////////////////
+// Saved Answers Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for bookmarking answers into a private per-user saved list
+import (
+	"fmt"     // Permits formatting of the saved list
+	"log"     // Permits console logging
+	"regexp"  // Permits matching of save commands
+	"strconv" // Permits parsing of item numbers
+	"strings" // Permits joining of list lines
+	"sync"    // Permits concurrency-safe tracking and saving
+	"time"    // Permits save timestamps
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constants naming the saved answers bucket, the per-user cap, and how many recent answer messages are tracked
+const (
+	savedAnswersBucket = "saved_answers"
+	maxSavedPerUser    = 50
+	maxTrackedAnswers  = 1000
+)
+
+// Global struct holding an answer a user saved
+type savedAnswer struct {
+	Question  string
+	Answer    string
+	Permalink string
+	SavedAt   time.Time
+}
+
+// Global struct holding the question and answer behind one of our recent answer messages
+type answerMessage struct {
+	question string
+	answer   string
+}
+
+// Global bounded map of our recent answer messages by channel and timestamp, oldest evicted first
+var answerMessages = newBoundedCache("answer_messages", func() int { return maxTrackedAnswers }, nil)
+
+// Global mutex serializing read-modify-write of saved lists
+var savedMu sync.Mutex
+
+// Global patterns recognizing "wolfy save this", "wolfy saved", and "wolfy unsave <n>"
+var (
+	saveThisPattern   = regexp.MustCompile(`(?i)^\s*(?:wolfy\s+)?(?:save|bookmark)\s+(?:this|that)(?:\s+answer)?\s*[!.]*\s*$`)
+	savedListPattern  = regexp.MustCompile(`(?i)^\s*(?:wolfy\s+)?(?:saved|my\s+saved(?:\s+answers)?|bookmarks)\s*[?!.]*\s*$`)
+	unsaveItemPattern = regexp.MustCompile(`(?i)^\s*(?:wolfy\s+)?(?:unsave|unbookmark)\s+#?(\d+)\s*[!.]*\s*$`)
+)
+
+// Registering saving among the built-in capabilities
+func init() {
+	registerCapability("saved_answers", "Reply \"wolfy save this\" under one of my answers (or react with :bookmark:) to keep it; \"wolfy saved\" lists them and \"wolfy unsave 2\" removes one.", nil)
+}
+
+// Global function for remembering which question one of our answer messages replied to, so it can be saved later
+func noteAnswerMessage(channel string, timestamp string, question string, answer string) {
+	if channel == "" || timestamp == "" || isTransientReply(answer) {
+		return
+	}
+	answerMessages.put(channel+":"+timestamp, answerMessage{question: question, answer: answer})
+}
+
+// Global function for looking up one of our recent answer messages
+func lookupAnswerMessage(channel string, timestamp string) (answerMessage, bool) {
+	value, ok := answerMessages.get(channel + ":" + timestamp)
+	if !ok {
+		return answerMessage{}, false
+	}
+	return value.(answerMessage), true
+}
+
+// Global function for forgetting our recent answer messages in a channel, returning how many were dropped
+func forgetChannelAnswers(channel string) int {
+	return len(answerMessages.removeMatching(func(key string, value interface{}) bool { return strings.HasPrefix(key, channel+":") }))
+}
+
+// Global function for adding an answer message to a user's saved list, returning the reply to show them
+func saveAnswerMessage(user string, channel string, timestamp string) string {
+	message, ok := lookupAnswerMessage(channel, timestamp)
+	if !ok {
+		return "I can only save my own recent answers. :-/ Reply \"wolfy save this\" in the thread under one of my answers, or react to it with :bookmark:."
+	}
+	permalink, err := slackAPI().GetPermalink(&slack.PermalinkParameters{Channel: channel, Ts: timestamp})
+	if err != nil {
+		log.Printf("SAVED ERROR: Unable to get a permalink for %s in %s.\nError Details: %v", timestamp, channel, err)
+	}
+
+	savedMu.Lock()
+	defer savedMu.Unlock()
+	var saved []savedAnswer
+	store.get(savedAnswersBucket, user, &saved)
+	for _, item := range saved {
+		if item.Question == message.question && item.Answer == message.answer {
+			return "You've already saved that one. Say \"wolfy saved\" to see your list."
+		}
+	}
+	if len(saved) >= maxSavedPerUser {
+		return fmt.Sprintf("You already have %d saved answers - \"wolfy unsave <n>\" one first. :-)", maxSavedPerUser)
+	}
+	saved = append(saved, savedAnswer{Question: message.question, Answer: message.answer, Permalink: permalink, SavedAt: time.Now()})
+	if err := store.put(savedAnswersBucket, user, saved); err != nil {
+		log.Printf("SAVED ERROR: Unable to save an answer for %s.\nError Details: %v", user, err)
+		return "Sorry, I couldn't save that right now. :-("
+	}
+	metrics.inc("wolfy_saved_answers_total")
+	return fmt.Sprintf(":bookmark: Saved as #%d. Say \"wolfy saved\" to see your list.", len(saved))
+}
+
+// Global function for describing a user's saved list, newest last, with links back to each answer
+func describeSavedAnswers(user string) string {
+	var saved []savedAnswer
+	if !store.get(savedAnswersBucket, user, &saved) || len(saved) == 0 {
+		return "You haven't saved any answers yet. Reply \"wolfy save this\" under one of my answers to keep it!"
+	}
+	lines := []string{"*Your saved answers:*"}
+	for i, item := range saved {
+		line := fmt.Sprintf("%d. _%s_ - %s", i+1, isolateDirection(item.Question), isolateDirection(truncateGraphemes(item.Answer, 200)))
+		if item.Permalink != "" {
+			line += fmt.Sprintf(" (<%s|link>)", item.Permalink)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Global function for removing the nth (1-based) item from a user's saved list, returning the reply to show them
+func unsaveAnswer(user string, n int) string {
+	savedMu.Lock()
+	defer savedMu.Unlock()
+
+	var saved []savedAnswer
+	store.get(savedAnswersBucket, user, &saved)
+	if n < 1 || n > len(saved) {
+		return fmt.Sprintf("You don't have a saved answer #%d. Say \"wolfy saved\" to see your list.", n)
+	}
+	removed := saved[n-1]
+	saved = append(saved[:n-1], saved[n:]...)
+	if err := store.put(savedAnswersBucket, user, saved); err != nil {
+		log.Printf("SAVED ERROR: Unable to update saved answers for %s.\nError Details: %v", user, err)
+		return "Sorry, I couldn't update your saved answers right now. :-("
+	}
+	return fmt.Sprintf("Removed \"%s\" from your saved answers.", isolateDirection(removed.Question))
+}
+
+// Global function for handling save, list, and unsave commands privately, reporting whether the message was consumed
+func handleSavedAnswers(event *slack.MessageEvent) bool {
+	text := event.Msg.Text
+	switch {
+	case saveThisPattern.MatchString(text):
+		metrics.inc("wolfy_intents_total", "intent", "save_answer")
+		if event.ThreadTimestamp == "" {
+			postPrivately(event, "Reply \"wolfy save this\" in the thread under one of my answers (or react to it with :bookmark:) so I know which one to save.")
+			return true
+		}
+		postPrivately(event, saveAnswerMessage(event.User, event.Channel, event.ThreadTimestamp))
+	case savedListPattern.MatchString(text):
+		metrics.inc("wolfy_intents_total", "intent", "saved_answers")
+		postPrivately(event, describeSavedAnswers(event.User))
+	case unsaveItemPattern.MatchString(text):
+		metrics.inc("wolfy_intents_total", "intent", "unsave_answer")
+		n, _ := strconv.Atoi(unsaveItemPattern.FindStringSubmatch(text)[1])
+		postPrivately(event, unsaveAnswer(event.User, n))
+	default:
+		return false
+	}
+	return true
+}
+
+// Global function for saving an answer a user reacted to with :bookmark:, replying by DM
+func handleBookmarkReaction(event *slack.ReactionAddedEvent) {
+	if event.Reaction != "bookmark" || event.Item.Type != "message" || event.User == currentBotUserID() {
+		return
+	}
+	if currentBotUserID() != "" && event.ItemUser != currentBotUserID() {
+		postText(event.User, "I can only save my own answers - that :bookmark: was on someone else's message. :-/")
+		return
+	}
+	postText(event.User, saveAnswerMessage(event.User, event.Item.Channel, event.Item.Timestamp))
+}
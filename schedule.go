@@ -0,0 +1,607 @@
+//////////////////////////////////////////////////
+// Scheduled Queries Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for deferring a question until later and answering it fresh when due
+import (
+	"context" // Permits per-question API usage accounting
+	"fmt"     // Permits string formatting of replies
+	"log"     // Permits console logging
+	"regexp"  // Permits matching of scheduling phrasings
+	"sort"    // Permits ordering of a user's scheduled queries
+	"strconv" // Permits parsing of relative amounts and list positions
+	"strings" // Permits string normalization
+	"sync"    // Permits serialized claiming of due queries
+	"time"    // Permits due time arithmetic
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constants naming the store buckets for scheduled queries and for occurrences claimed but not yet delivered,
+// and capping how many a user may hold
+const (
+	scheduledQueriesBucket = "scheduled_queries"
+	claimedQueriesBucket   = "claimed_scheduled_queries"
+	maxScheduledPerUser    = 10
+)
+
+// Global struct holding a question to be answered fresh at a later time, and again on each recurrence when Every is set
+type scheduledQuery struct {
+	ID       string
+	User     string
+	Channel  string
+	ThreadTS string
+	Query    string
+	Due      time.Time
+	Created  time.Time
+
+	// Recurrence ("day", "weekday", or a weekday name), the local "15:04" time it fires at, and the timezone it was set in
+	Every    string
+	At       string
+	Timezone string
+
+	// Short ID recurring queries are listed and unsubscribed by, and when the last run was and whether it failed
+	Ref        string
+	LastRun    time.Time
+	LastFailed bool
+
+	// Whether this occurrence was held until the end of its channel's quiet hours
+	QuietHeld bool
+}
+
+// Global struct holding an occurrence claimed for running, kept until it is delivered so a restart mid-run resumes it
+// rather than losing it
+type claimedQuery struct {
+	Query     scheduledQuery
+	ClaimedAt time.Time
+}
+
+// Global patterns recognizing deferred questions (time phrase last or first) and the list/cancel commands
+var (
+	whenPhrase              = `(tomorrow(?:\s+(?:morning|afternoon|evening|night))?|tonight|this\s+(?:morning|afternoon|evening)|in\s+an?\s+(?:minute|hour|day)|in\s+\d+\s+(?:minutes?|mins?|hours?|hrs?|days?))`
+	scheduleTrailingPattern = regexp.MustCompile(`(?i)^\s*(?:please\s+)?(?:remind|ask|tell)\s+me\s+(.+?)\s+` + whenPhrase + `\s*[?.!]*\s*$`)
+	scheduleLeadingPattern  = regexp.MustCompile(`(?i)^\s*(?:please\s+)?(?:remind|ask|tell)\s+me\s+` + whenPhrase + `,?\s+(.+?)\s*[?.!]*\s*$`)
+	scheduleListPattern     = regexp.MustCompile(`(?i)^\s*(?:list\s+|show\s+)?my\s+(?:scheduled\s+(?:questions|queries)|reminders)\s*[?.!]*\s*$`)
+	scheduleCancelPattern   = regexp.MustCompile(`(?i)^\s*cancel\s+(?:scheduled\s+(?:question|query)|reminder)\s+#?(\d+)\s*[.!]*\s*$`)
+	relativeWhenPattern     = regexp.MustCompile(`^in\s+(an?|\d+)\s+([a-z]+)$`)
+
+	recurPhrase              = `every\s+(day|morning|afternoon|evening|night|weekday|monday|tuesday|wednesday|thursday|friday|saturday|sunday)(?:\s+at\s+(\d{1,2})(?::(\d{2}))?\s*(am|pm)?)?`
+	recurLeadingPattern      = regexp.MustCompile(`(?i)^\s*(?:please\s+)?` + recurPhrase + `,?\s+(?:remind|ask|tell)\s+me\s+(.+?)\s*[?.!]*\s*$`)
+	recurAfterPronounPattern = regexp.MustCompile(`(?i)^\s*(?:please\s+)?(?:remind|ask|tell)\s+me\s+` + recurPhrase + `,?\s+(.+?)\s*[?.!]*\s*$`)
+	recurTrailingPattern     = regexp.MustCompile(`(?i)^\s*(?:please\s+)?(?:remind|ask|tell)\s+me\s+(.+?)\s+` + recurPhrase + `\s*[?.!]*\s*$`)
+	schedulePreamblePattern  = regexp.MustCompile(`(?i)^(?:about\s+|to\s+check\s+)`)
+)
+
+// Global mutex serializing due query claims against list/cancel edits
+var scheduleMu sync.Mutex
+
+// Global hours that parts of the day (and bare recurrences) resolve to
+var partOfDayHours = map[string]int{"morning": 8, "afternoon": 13, "evening": 18, "night": 20, "day": 9}
+
+// Registering scheduled queries among the built-in capabilities
+func init() {
+	registerCapability("scheduled_queries", "Say \"remind me what the weather is tomorrow morning\" and I'll look it up fresh then, or \"subscribe me to 'weather in SF' every weekday at 9am\" to get it by DM on a schedule. \"my scheduled questions\" and \"wolfy subscriptions\" list them; \"cancel scheduled question 2\" and \"wolfy unsubscribe <id>\" remove one.", func() bool { return config.ScheduleCheckInterval > 0 })
+}
+
+// Global function for resolving a time phrase to a due time in the user's timezone
+func resolveWhen(phrase string, now time.Time) (time.Time, bool) {
+	phrase = strings.Join(strings.Fields(strings.ToLower(phrase)), " ")
+	at := func(dayOffset int, hour int) time.Time {
+		return time.Date(now.Year(), now.Month(), now.Day()+dayOffset, hour, 0, 0, 0, now.Location())
+	}
+	partHours := partOfDayHours
+
+	switch {
+	case phrase == "tomorrow":
+		return at(1, 9), true
+	case strings.HasPrefix(phrase, "tomorrow "):
+		return at(1, partHours[strings.TrimPrefix(phrase, "tomorrow ")]), true
+	case phrase == "tonight":
+		return at(0, partHours["night"]), at(0, partHours["night"]).After(now)
+	case strings.HasPrefix(phrase, "this "):
+		due := at(0, partHours[strings.TrimPrefix(phrase, "this ")])
+		return due, due.After(now)
+	}
+
+	match := relativeWhenPattern.FindStringSubmatch(phrase)
+	if match == nil {
+		return time.Time{}, false
+	}
+	amount, err := strconv.Atoi(match[1])
+	if err != nil {
+		amount = 1
+	}
+	switch {
+	case strings.HasPrefix(match[2], "min"):
+		return now.Add(time.Duration(amount) * time.Minute), amount > 0
+	case strings.HasPrefix(match[2], "h"):
+		return now.Add(time.Duration(amount) * time.Hour), amount > 0
+	case strings.HasPrefix(match[2], "day"):
+		return now.AddDate(0, 0, amount), amount > 0
+	}
+	return time.Time{}, false
+}
+
+// Global function for extracting the deferred question and its time phrase from a message
+func parseScheduledQuery(text string) (string, string, bool) {
+	if match := scheduleTrailingPattern.FindStringSubmatch(text); match != nil {
+		return match[1], match[2], true
+	}
+	if match := scheduleLeadingPattern.FindStringSubmatch(text); match != nil {
+		return match[2], match[1], true
+	}
+	return "", "", false
+}
+
+// Global function for extracting a recurring question and its recurrence ("every weekday at 9") from a message
+func parseRecurringQuery(text string) (scheduledQuery, bool) {
+	var fields []string
+	var question string
+	if match := recurLeadingPattern.FindStringSubmatch(text); match != nil {
+		fields, question = match[1:5], match[5]
+	} else if match := recurAfterPronounPattern.FindStringSubmatch(text); match != nil {
+		fields, question = match[1:5], match[5]
+	} else if match := recurTrailingPattern.FindStringSubmatch(text); match != nil {
+		fields, question = match[2:6], match[1]
+	} else {
+		return scheduledQuery{}, false
+	}
+	return recurringQuery(fields, question)
+}
+
+// Global function for building a recurring query from the matched recurrence fields (period, hour, minute, meridiem)
+func recurringQuery(fields []string, question string) (scheduledQuery, bool) {
+	period := strings.ToLower(fields[0])
+	hour, minute := partOfDayHours["day"], 0
+	if partHour, ok := partOfDayHours[period]; ok {
+		hour = partHour
+	}
+	if fields[1] != "" {
+		hour, _ = strconv.Atoi(fields[1])
+		minute, _ = strconv.Atoi(fields[2])
+		switch meridiem := strings.ToLower(fields[3]); {
+		case meridiem == "pm" && hour < 12, meridiem == "" && hour < 12 && partOfDayHours[period] >= 12:
+			// "every evening at 6" means 6 PM
+			hour += 12
+		case meridiem == "am" && hour == 12:
+			hour = 0
+		}
+		if hour > 23 || minute > 59 {
+			return scheduledQuery{}, false
+		}
+	}
+
+	every := period
+	if _, ok := partOfDayHours[period]; ok {
+		every = "day"
+	}
+	return scheduledQuery{Query: question, Every: every, At: fmt.Sprintf("%02d:%02d", hour, minute)}, true
+}
+
+// Global function for finding the first time a recurring query is due strictly after a moment
+func nextRecurrence(query scheduledQuery, after time.Time) time.Time {
+	location, err := time.LoadLocation(query.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	at, err := time.Parse("15:04", query.At)
+	if err != nil {
+		at = time.Date(0, 1, 1, partOfDayHours["day"], 0, 0, 0, time.UTC)
+	}
+
+	local := after.In(location)
+	for offset := 0; offset <= 8; offset++ {
+		candidate := time.Date(local.Year(), local.Month(), local.Day()+offset, at.Hour(), at.Minute(), 0, 0, location)
+		if !candidate.After(after) {
+			continue
+		}
+		weekday := candidate.Weekday()
+		switch {
+		case query.Every == "day",
+			query.Every == "weekday" && weekday != time.Saturday && weekday != time.Sunday,
+			strings.EqualFold(query.Every, weekday.String()):
+			return candidate
+		}
+	}
+	return after.Add(24 * time.Hour)
+}
+
+// Global function for describing a recurrence ("every weekday at 9:00 AM")
+func describeRecurrence(query scheduledQuery) string {
+	at, err := time.Parse("15:04", query.At)
+	if err != nil {
+		return "every " + query.Every
+	}
+	return fmt.Sprintf("every %s at %s", query.Every, at.Format("3:04 PM"))
+}
+
+// Global function for loading a user's scheduled queries, soonest first
+func userScheduledQueries(user string) []scheduledQuery {
+	var queries []scheduledQuery
+	for _, key := range store.keys(scheduledQueriesBucket) {
+		var query scheduledQuery
+		if store.get(scheduledQueriesBucket, key, &query) && query.User == user {
+			queries = append(queries, query)
+		}
+	}
+	sort.Slice(queries, func(i, j int) bool { return queries[i].Due.Before(queries[j].Due) })
+	return queries
+}
+
+// Global function for scheduling, listing, and cancelling deferred questions, reporting whether the message was consumed
+func handleScheduledQuery(event *slack.MessageEvent) bool {
+	if config.ScheduleCheckInterval <= 0 {
+		return false
+	}
+
+	if scheduleListPattern.MatchString(event.Msg.Text) {
+		metrics.inc("wolfy_intents_total", "intent", "scheduled_list")
+		queries := userScheduledQueries(event.User)
+		if len(queries) == 0 {
+			postText(event.User, "You don't have any scheduled questions.")
+			return true
+		}
+		location := userLocation(event.User)
+		lines := []string{"Your scheduled questions:"}
+		for i, query := range queries {
+			line := fmt.Sprintf("%d. \"%s\" at %s", i+1, isolateDirection(truncateGraphemes(query.Query, 80)), query.Due.In(location).Format("Mon Jan 2 3:04 PM MST"))
+			if query.Every != "" {
+				line += fmt.Sprintf(" (repeats %s)", describeRecurrence(query))
+			}
+			lines = append(lines, line)
+		}
+		postText(event.User, strings.Join(lines, "\n"))
+		return true
+	}
+
+	if match := scheduleCancelPattern.FindStringSubmatch(event.Msg.Text); match != nil {
+		metrics.inc("wolfy_intents_total", "intent", "scheduled_cancel")
+		position, _ := strconv.Atoi(match[1])
+
+		scheduleMu.Lock()
+		queries := userScheduledQueries(event.User)
+		if position < 1 || position > len(queries) {
+			scheduleMu.Unlock()
+			postText(event.User, fmt.Sprintf("I couldn't find scheduled question #%s. Say \"my scheduled questions\" to see the list.", match[1]))
+			return true
+		}
+		store.delete(scheduledQueriesBucket, queries[position-1].ID)
+		scheduleMu.Unlock()
+
+		postText(event.User, fmt.Sprintf("Cancelled: \"%s\"", queries[position-1].Query))
+		return true
+	}
+
+	if handleSubscriptionCommand(event) {
+		return true
+	}
+
+	location := userLocation(event.User)
+	query, recurring := parseRecurringQuery(event.Msg.Text)
+	subscribed := false
+	if !recurring {
+		query, subscribed = parseSubscription(event.Msg.Text)
+		recurring = subscribed
+	}
+	if recurring {
+		query.Timezone = location.String()
+		query.Due = nextRecurrence(query, time.Now())
+	} else {
+		question, when, ok := parseScheduledQuery(event.Msg.Text)
+		if !ok {
+			return false
+		}
+		due, ok := resolveWhen(when, time.Now().In(location))
+		if !ok {
+			return false
+		}
+		query = scheduledQuery{Query: question, Due: due}
+	}
+	metrics.inc("wolfy_intents_total", "intent", "scheduled_query")
+
+	if len(userScheduledQueries(event.User)) >= maxScheduledPerUser {
+		postText(event.User, fmt.Sprintf("You already have %d scheduled questions - cancel one first. :-)", maxScheduledPerUser))
+		return true
+	}
+	if recurring && len(userSubscriptions(event.User)) >= config.SubscriptionLimit {
+		postText(event.User, fmt.Sprintf("You already have %d subscriptions, the most I keep per person - say \"wolfy subscriptions\" to see them and \"wolfy unsubscribe <id>\" to drop one. :-)", config.SubscriptionLimit))
+		return true
+	}
+
+	// Answers are computed at send time, so we run our own scheduler rather than chat.scheduleMessage
+	now := time.Now()
+	query.ID = fmt.Sprintf("%s:%d", event.User, now.UnixNano())
+	query.User = event.User
+	query.Channel = event.Channel
+	query.ThreadTS = event.ThreadTimestamp
+	query.Query = schedulePreamblePattern.ReplaceAllString(query.Query, "")
+	query.Created = now
+	if recurring {
+		query.Ref = newSubscriptionRef(event.User)
+	}
+	if subscribed {
+		// Subscriptions are delivered by DM, wherever they were asked for
+		query.ThreadTS = ""
+	}
+	if err := store.put(scheduledQueriesBucket, query.ID, query); err != nil {
+		log.Printf("SCHEDULE ERROR: Unable to save scheduled query for %s.\nError Details: %v", event.User, err)
+		postText(event.User, "Sorry, I couldn't schedule that right now. :-(")
+		return true
+	}
+	if recurring {
+		postText(event.User, fmt.Sprintf("Got it! I'll look up \"%s\" for you %s, starting %s. :repeat: (ID `%s` - say \"wolfy unsubscribe %s\" to stop.)", query.Query, describeRecurrence(query), query.Due.In(location).Format("Mon Jan 2 3:04 PM MST"), query.Ref, query.Ref))
+		return true
+	}
+	postText(event.User, fmt.Sprintf("Got it! I'll look up \"%s\" for you at %s. :alarm_clock:", query.Query, query.Due.Format("Mon Jan 2 3:04 PM MST")))
+	return true
+}
+
+// Global function for building the key an occurrence is claimed under, distinct for each run of a recurring query
+func occurrenceKey(query scheduledQuery) string {
+	return fmt.Sprintf("%s@%d", query.ID, query.Due.Unix())
+}
+
+// Global function for claiming every scheduled query that has come due (claimed queries are removed, or moved to their next
+// recurrence, and recorded as claimed in the same step, then flushed to disk before running, so a restart neither runs an
+// occurrence again nor loses one; recurring occurrences missed while down collapse into one run, or are skipped under the
+// "skip" catch-up policy; those bound for a channel in quiet hours are held until the hours end)
+func claimDueQueries(now time.Time) []scheduledQuery {
+	scheduleMu.Lock()
+	defer scheduleMu.Unlock()
+
+	var due []scheduledQuery
+	held := 0
+	for _, key := range store.keys(scheduledQueriesBucket) {
+		var query scheduledQuery
+		if !store.get(scheduledQueriesBucket, key, &query) {
+			store.delete(scheduledQueriesBucket, key)
+			continue
+		}
+		if query.Due.After(now) {
+			continue
+		}
+		if holdForQuietHours(query, now) {
+			held++
+			continue
+		}
+		if query.Every == "" {
+			due = append(due, query)
+			claimOccurrence(query, now)
+			store.delete(scheduledQueriesBucket, key)
+			continue
+		}
+		next := query
+		next.Due, next.QuietHeld = nextRecurrence(query, now), false
+		if missedWhileDown(query, now) && strings.EqualFold(config.SubscriptionCatchUp, "skip") {
+			metrics.inc("wolfy_scheduled_queries_skipped_total")
+			log.Printf("SCHEDULE: Skipping recurring query %s missed at %s while down; next run %s.", key, query.Due.Format(time.RFC3339), next.Due.Format(time.RFC3339))
+		} else {
+			due = append(due, query)
+			claimOccurrence(query, now)
+		}
+		if err := store.put(scheduledQueriesBucket, key, next); err != nil {
+			log.Printf("SCHEDULE ERROR: Unable to reschedule recurring query %s.\nError Details: %v", key, err)
+		}
+	}
+	if len(due) > 0 || held > 0 {
+		if err := store.flush(); err != nil {
+			log.Printf("STORE ERROR: Unable to flush claimed scheduled queries.\nError Details: %v", err)
+		}
+	}
+	return due
+}
+
+// Global function for recording an occurrence as claimed, until its answer is delivered
+func claimOccurrence(query scheduledQuery, now time.Time) {
+	if err := store.put(claimedQueriesBucket, occurrenceKey(query), claimedQuery{Query: query, ClaimedAt: now}); err != nil {
+		log.Printf("SCHEDULE ERROR: Unable to record the claim on scheduled query %s.\nError Details: %v", query.ID, err)
+	}
+}
+
+// Global function for dropping an occurrence's claim once its answer is delivered, flushing so a restart right after
+// doesn't deliver it again
+func finishOccurrence(query scheduledQuery) {
+	store.delete(claimedQueriesBucket, occurrenceKey(query))
+	if err := store.flush(); err != nil {
+		log.Printf("STORE ERROR: Unable to flush finished scheduled query %s.\nError Details: %v", query.ID, err)
+	}
+}
+
+// Global function for returning the occurrences claimed before the last shutdown but never delivered, oldest first
+func unfinishedOccurrences() []scheduledQuery {
+	var claims []claimedQuery
+	for _, key := range store.keys(claimedQueriesBucket) {
+		var claim claimedQuery
+		if !store.get(claimedQueriesBucket, key, &claim) {
+			store.delete(claimedQueriesBucket, key)
+			continue
+		}
+		claims = append(claims, claim)
+	}
+	sort.Slice(claims, func(i, j int) bool { return claims[i].Query.Due.Before(claims[j].Query.Due) })
+	queries := make([]scheduledQuery, 0, len(claims))
+	for _, claim := range claims {
+		queries = append(queries, claim.Query)
+	}
+	return queries
+}
+
+// Global function for noting on an answer that it is late, because its time passed while the bot was offline
+func delayedNote(query scheduledQuery, now time.Time) string {
+	if !missedWhileDown(query, now) {
+		return ""
+	}
+	return fmt.Sprintf("\n_(Delayed: this was due %s, while I was offline.)_", query.Due.In(userLocation(query.User)).Format("Mon Jan 2 3:04 PM MST"))
+}
+
+// Global function for building the key due queries are batched under: the same question (normalized as for the
+// cache), due at the same instant, for askers whose resolved units, number locale, and language would fetch and word
+// it alike
+func scheduledBatchKey(query scheduledQuery) string {
+	units, _ := resolveUnits(query.User)
+	locale, _ := resolveLocale(query.User)
+	return fmt.Sprintf("%d|%s|%s|%s|%s", query.Due.Unix(), unitsName(units), locale, loadPreferences(query.User).Language, normalizeCacheQuery(query.Query))
+}
+
+// Global function for grouping due queries into batches answered with one lookup, in the order each batch first came due
+// (every query is a batch of its own when batching is off)
+func batchScheduledQueries(queries []scheduledQuery) [][]scheduledQuery {
+	var batches [][]scheduledQuery
+	positions := map[string]int{}
+	for _, query := range queries {
+		if !config.ScheduleBatching {
+			batches = append(batches, []scheduledQuery{query})
+			continue
+		}
+		key := scheduledBatchKey(query)
+		if position, ok := positions[key]; ok {
+			batches[position] = append(batches[position], query)
+			continue
+		}
+		positions[key] = len(batches)
+		batches = append(batches, []scheduledQuery{query})
+	}
+	return batches
+}
+
+// Global function for answering a batch of identical due queries with one fresh lookup, made as the first asker, then
+// delivering it to each asker in their own wording and timezone
+func runScheduledBatch(batch []scheduledQuery) {
+	event := scheduledEvent(batch[0])
+	ctx := withFlagScope(withAPIUsage(context.Background()), event)
+	answer := answerSubQuestion(ctx, event, batch[0].Query)
+	if len(batch) > 1 {
+		metrics.add(int64(len(batch)-1), "wolfy_scheduled_queries_coalesced_total")
+		log.Printf("SCHEDULE: Answered %q once for %d scheduled queries due %s.", batch[0].Query, len(batch), batch[0].Due.Format(time.RFC3339))
+	}
+	for i, query := range batch {
+		var calls map[string]int
+		if i == 0 {
+			calls = apiCalls(ctx)
+		}
+		deliverScheduledAnswer(query, answer, calls)
+	}
+}
+
+// Global function for building the message event a scheduled query is answered as
+func scheduledEvent(query scheduledQuery) *slack.MessageEvent {
+	event := &slack.MessageEvent{}
+	event.User = query.User
+	event.Channel = query.Channel
+	event.ThreadTimestamp = query.ThreadTS
+	event.Text = query.Query
+	return event
+}
+
+// Global function for delivering a due query's fresh answer to the original thread or the user's DM, counting the API
+// calls it took against the asker's history
+func deliverScheduledAnswer(query scheduledQuery, answer string, calls map[string]int) {
+	metrics.inc("wolfy_scheduled_queries_total")
+	event := scheduledEvent(query)
+	failed := isFailureReply(answer)
+	scope := flagScope{user: query.User, channel: query.Channel}
+	reply := fmt.Sprintf("%sYou asked me to look up \"%s\":\n%s", packEmoji(scope, "reminder"), query.Query, answer)
+	if query.Every != "" && failed {
+		// Saying so rather than skipping the day quietly, so a missing answer never looks like a missing subscription
+		metrics.inc("wolfy_scheduled_query_failures_total")
+		reply = fmt.Sprintf("%sI couldn't get a fresh answer for your %s lookup of \"%s\" this time:\n%s\nI'll try again at the next one, %s.", packEmoji(scope, "recurring"), describeRecurrence(query), query.Query, answer, nextRecurrence(query, time.Now()).In(userLocation(query.User)).Format("Mon Jan 2 3:04 PM MST"))
+	} else if query.Every != "" {
+		reply = fmt.Sprintf("%sYour %s lookup of \"%s\":\n%s", packEmoji(scope, "recurring"), describeRecurrence(query), query.Query, answer)
+	}
+	reply += delayedNote(query, time.Now())
+	if query.QuietHeld {
+		reply += "\n_(Held until quiet hours here ended.)_"
+	}
+	if query.Every != "" {
+		noteSubscriptionRun(query.ID, failed)
+	}
+	// Waiting for the post to land before dropping the claim, so an answer lost to a restart is resumed
+	if query.ThreadTS != "" {
+		postMessage(query.Channel, messageText(reply), slack.MsgOptionTS(query.ThreadTS))
+	} else {
+		postMessage(query.User, messageText(reply))
+	}
+	finishOccurrence(query)
+	rememberInteraction(event, interaction{Text: query.Query, EntityKey: "scheduled_query", Answer: reply, Calls: calls})
+}
+
+// Global function for cancelling the scheduled queries bound for a channel's threads (held and claimed occurrences
+// included), telling each asker by DM what was dropped, and moving those only asked there, which are answered by DM
+// anyway, off the channel; returns how many were cancelled and how many moved
+func cancelChannelQueries(channel string) (int, int) {
+	scheduleMu.Lock()
+	defer scheduleMu.Unlock()
+
+	moved := 0
+	cancelled := map[string]bool{}
+	dropped := map[string][]string{}
+	for _, key := range store.keys(scheduledQueriesBucket) {
+		var query scheduledQuery
+		if !store.get(scheduledQueriesBucket, key, &query) || query.Channel != channel {
+			continue
+		}
+		if query.ThreadTS == "" {
+			query.Channel = ""
+			if err := store.put(scheduledQueriesBucket, key, query); err != nil {
+				log.Printf("SCHEDULE ERROR: Unable to redirect scheduled query %s.\nError Details: %v", key, err)
+				continue
+			}
+			moved++
+			continue
+		}
+		store.delete(scheduledQueriesBucket, key)
+		dropped[query.User] = append(dropped[query.User], query.Query)
+		cancelled[query.ID] = true
+	}
+	for _, key := range store.keys(claimedQueriesBucket) {
+		var claim claimedQuery
+		if !store.get(claimedQueriesBucket, key, &claim) || claim.Query.Channel != channel || claim.Query.ThreadTS == "" {
+			continue
+		}
+		// An occurrence claimed for running counts once with its recurring query's next one
+		store.delete(claimedQueriesBucket, key)
+		cancelled[claim.Query.ID] = true
+	}
+	if len(cancelled)+moved > 0 {
+		if err := store.flush(); err != nil {
+			log.Printf("STORE ERROR: Unable to flush the scheduled queries of %s.\nError Details: %v", channel, err)
+		}
+	}
+
+	for user, queries := range dropped {
+		quoted := make([]string, 0, len(queries))
+		for _, query := range queries {
+			quoted = append(quoted, fmt.Sprintf("\"%s\"", truncateGraphemes(query, 80)))
+		}
+		postText(user, fmt.Sprintf("I'm no longer in <#%s>, so I've cancelled what you'd scheduled there: %s. Ask me again somewhere I am and I'll set it back up. :-)", channel, strings.Join(quoted, ", ")))
+	}
+	metrics.add(int64(len(cancelled)), "wolfy_scheduled_queries_cancelled_total", "reason", "channel_inactive")
+	return len(cancelled), moved
+}
+
+// Global function for starting the background loop running scheduled queries as they come due (persisted ones survive restarts),
+// in batches sharing one lookup, first resuming any claimed before the last shutdown but never delivered
+func startScheduler() {
+	if config.ScheduleCheckInterval <= 0 {
+		return
+	}
+	resumed := unfinishedOccurrences()
+	for _, query := range resumed {
+		metrics.inc("wolfy_scheduled_queries_resumed_total")
+		log.Printf("SCHEDULE: Resuming scheduled query %s, due %s, claimed but never delivered.", query.ID, query.Due.Format(time.RFC3339))
+	}
+	for _, batch := range batchScheduledQueries(resumed) {
+		go runScheduledBatch(batch)
+	}
+	go func() {
+		for range time.Tick(config.ScheduleCheckInterval) {
+			for _, batch := range batchScheduledQueries(claimDueQueries(time.Now())) {
+				go runScheduledBatch(batch)
+			}
+		}
+	}()
+}
@@ -0,0 +1,153 @@
+//////////////////////////////////////////////////
+// Scheduled Queries Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing due queries answered together with one lookup
+import (
+	"path/filepath" // Permits a data file of the test's own
+	"reflect"       // Permits comparison of batches
+	"testing"       // Permits Go testing
+	"time"          // Permits due times and the fake clock
+)
+
+// Global function for giving one test a data file of its own, returning a function that restarts on it: the current
+// store is dropped without a flush, as a crash would leave it, and the file is opened again
+func withRestartableStore(t *testing.T) func() {
+	path := filepath.Join(t.TempDir(), "wolfy.json")
+	previous := store
+	open := func() {
+		reopened, err := openStore(path)
+		if err != nil {
+			t.Fatalf("opening %s: %v", path, err)
+		}
+		store = reopened
+	}
+	open()
+	t.Cleanup(func() { store = previous })
+	return open
+}
+
+// Test for due queries claimed just before a crash being resumed once after the restart and never claimed again, a
+// recurring one's next run firing once when it comes due, and nothing resumed once delivered, all on a fake clock
+func TestScheduledQueriesAcrossRestarts(t *testing.T) {
+	const user = "USCHEDRESTART"
+	restart := withRestartableStore(t)
+	clock := time.Date(2026, time.June, 10, 9, 0, 0, 0, time.UTC)
+	queries := []scheduledQuery{
+		{ID: "once", User: user, Query: "what is 5 * 6", Due: clock.Add(-time.Minute), Created: clock.Add(-time.Hour)},
+		{ID: "daily", User: user, Query: "what is 7 * 8", Due: clock, Created: clock.Add(-time.Hour), Every: "day", At: "09:00", Timezone: "UTC"},
+	}
+	for _, query := range queries {
+		if err := store.put(scheduledQueriesBucket, query.ID, query); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.flush(); err != nil {
+		t.Fatal(err)
+	}
+	ids := func(queries []scheduledQuery) []string {
+		found := []string{}
+		for _, query := range queries {
+			found = append(found, query.ID)
+		}
+		return found
+	}
+	answered := func() (int, int) {
+		return len(postsContaining(user, "5 * 6 = 30")), len(postsContaining(user, "7 * 8 = 56"))
+	}
+	once, daily := answered()
+
+	// Claiming both, then crashing before either is delivered
+	if claimed := ids(claimDueQueries(clock)); !reflect.DeepEqual(claimed, []string{"daily", "once"}) {
+		t.Fatalf("first claim = %v; want [daily once]", claimed)
+	}
+	restart()
+	clock = clock.Add(time.Minute)
+	resumed := unfinishedOccurrences()
+	if got := ids(resumed); !reflect.DeepEqual(got, []string{"once", "daily"}) {
+		t.Fatalf("resumed after the restart = %v; want [once daily], oldest first", got)
+	}
+	if claimed := ids(claimDueQueries(clock)); len(claimed) != 0 {
+		t.Errorf("claimed again after the restart = %v; want none", claimed)
+	}
+	for _, batch := range batchScheduledQueries(resumed) {
+		runScheduledBatch(batch)
+	}
+
+	// Restarting once both are delivered, with nothing left to resume or claim until tomorrow's run
+	restart()
+	clock = clock.Add(time.Minute)
+	if got := ids(unfinishedOccurrences()); len(got) != 0 {
+		t.Errorf("resumed after delivering = %v; want none", got)
+	}
+	if claimed := ids(claimDueQueries(clock)); len(claimed) != 0 {
+		t.Errorf("claimed after delivering = %v; want none", claimed)
+	}
+	if gotOnce, gotDaily := answered(); gotOnce-once != 1 || gotDaily-daily != 1 {
+		t.Errorf("answers delivered = %d and %d; want each query answered once", gotOnce-once, gotDaily-daily)
+	}
+	var next scheduledQuery
+	if !store.get(scheduledQueriesBucket, "daily", &next) || !next.Due.Equal(time.Date(2026, time.June, 11, 9, 0, 0, 0, time.UTC)) {
+		t.Fatalf("the recurring query after its run = %+v; want it due tomorrow at 09:00", next)
+	}
+	if store.get(scheduledQueriesBucket, "once", &scheduledQuery{}) {
+		t.Error("the one-off query is still scheduled after being delivered")
+	}
+
+	tomorrow := next.Due
+	if claimed := ids(claimDueQueries(tomorrow)); !reflect.DeepEqual(claimed, []string{"daily"}) {
+		t.Errorf("claimed tomorrow = %v; want [daily]", claimed)
+	}
+	restart()
+	if claimed := ids(claimDueQueries(tomorrow.Add(time.Minute))); len(claimed) != 0 {
+		t.Errorf("claimed again after restarting tomorrow = %v; want none", claimed)
+	}
+	if got := ids(unfinishedOccurrences()); !reflect.DeepEqual(got, []string{"daily"}) {
+		t.Errorf("resumed after restarting tomorrow = %v; want [daily]", got)
+	}
+}
+
+// Test for batching due queries by the settings that change the answer as resolved for each asker (an explicit
+// preference matching their Slack locale batches with no preference at all), not by every setting they have saved
+func TestBatchScheduledQueries(t *testing.T) {
+	withConfig(t, func(c *Config) { c.ScheduleBatching = true })
+	preferences := map[string]userPreferences{
+		"UBATCHEXPLICIT": {Units: "imperial", Locale: "en-US", Delivery: "dm", Tone: "formal"},
+		"UBATCHMETRIC":   {Units: "metric"},
+		"UBATCHFRENCH":   {Language: "fr"},
+		"UBATCHGERMAN":   {Locale: "de-DE"},
+	}
+	for user, saved := range preferences {
+		if err := store.put(preferencesBucket, user, saved); err != nil {
+			t.Fatal(err)
+		}
+		defer store.delete(preferencesBucket, user)
+	}
+
+	due := time.Now().Add(time.Hour).Truncate(time.Minute)
+	queries := []scheduledQuery{
+		{ID: "plain", User: "UBATCHPLAIN", Query: "weather in Paris", Due: due},
+		{ID: "explicit", User: "UBATCHEXPLICIT", Query: "Weather in  Paris", Due: due},
+		{ID: "metric", User: "UBATCHMETRIC", Query: "weather in Paris", Due: due},
+		{ID: "french", User: "UBATCHFRENCH", Query: "weather in Paris", Due: due},
+		{ID: "german", User: "UBATCHGERMAN", Query: "weather in Paris", Due: due},
+		{ID: "later", User: "UBATCHPLAIN", Query: "weather in Paris", Due: due.Add(time.Minute)},
+		{ID: "other", User: "UBATCHEXPLICIT", Query: "weather in Rome", Due: due},
+	}
+	var got [][]string
+	for _, batch := range batchScheduledQueries(queries) {
+		var ids []string
+		for _, query := range batch {
+			ids = append(ids, query.ID)
+		}
+		got = append(got, ids)
+	}
+	want := [][]string{{"plain", "explicit"}, {"metric"}, {"french"}, {"german"}, {"later"}, {"other"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("batchScheduledQueries = %v; want %v", got, want)
+	}
+}
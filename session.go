@@ -0,0 +1,113 @@
+//////////////////////////////////////////////////
+// Session Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for per-user conversation memory
+import (
+	"time" // Permits session expiry
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global struct holding a single remembered question/answer exchange
+type interaction struct {
+	Text       string
+	EntityKey  string
+	Confidence float64
+	Query      string
+	Cached     bool
+	AnsweredAt time.Time
+	Answer     string
+	Outcome    string
+	At         time.Time
+
+	// Where it was asked, the external API calls it took, and their estimated cost (zero for local answers)
+	Channel   string
+	Calls     map[string]int
+	Cost      float64
+	CostKnown bool
+
+	// Whether the answer was posted in the channel rather than the asker's DM
+	AnsweredInChannel bool
+}
+
+// Global session memory shared by the message handlers: each user's most recent interaction, overall and per channel,
+// forgotten after the session TTL
+var sessions = struct {
+	users    *boundedCache
+	channels *boundedCache
+}{
+	users:    newBoundedCache("sessions", func() int { return config.SessionCacheSize }, func(interface{}) time.Duration { return config.SessionTTL }),
+	channels: newBoundedCache("channel_sessions", func() int { return config.SessionCacheSize }, func(interface{}) time.Duration { return config.SessionTTL }),
+}
+
+// Global function for keying a user's session within one channel
+func channelSessionKey(user string, channel string) string {
+	return user + ":" + channel
+}
+
+// Global function for remembering a user's latest interaction, pricing its API calls
+func rememberInteraction(event *slack.MessageEvent, entry interaction) {
+	user := event.User
+	entry.At = time.Now()
+	entry.Channel = event.Channel
+	entry.AnsweredInChannel = answerDestination(event) == event.Channel
+	entry.Cost, entry.CostKnown = priceCalls(entry.Calls)
+	if entry.Outcome == "" {
+		entry.Outcome = "answered"
+	}
+
+	storeSession(user, entry)
+
+	recordHistory(user, entry)
+	recordSpend(entry)
+	mirrorInteraction(event, entry)
+	sampleInteractionForReview(event, entry)
+	query := redactSecrets(entry.Text)
+	if optedOut(user) {
+		query = optedOutQuery
+	}
+	recentActivity.add(activityEntry{
+		Request: messageEventKey(event),
+		At:      entry.At,
+		User:    user,
+		Channel: entry.Channel,
+		Query:   query,
+		Intent:  intentLabel(entry.EntityKey),
+		Outcome: entry.Outcome,
+		Latency: messageLatency(event.Timestamp, entry.At),
+	})
+}
+
+// Global function for storing a user's latest interaction, overall and in its channel
+func storeSession(user string, entry interaction) {
+	sessions.users.put(user, entry)
+	sessions.channels.put(channelSessionKey(user, entry.Channel), entry)
+}
+
+// Global function for looking up a user's latest interaction, ignoring expired sessions
+func lastInteraction(user string) (interaction, bool) {
+	value, ok := sessions.users.get(user)
+	if !ok {
+		return interaction{}, false
+	}
+	return value.(interaction), true
+}
+
+// Global function for looking up a user's latest interaction in one channel, ignoring expired sessions
+func lastInteractionIn(user string, channel string) (interaction, bool) {
+	value, ok := sessions.channels.get(channelSessionKey(user, channel))
+	if !ok {
+		return interaction{}, false
+	}
+	return value.(interaction), true
+}
+
+// Global function for forgetting every user's per-channel session in a channel, returning how many were dropped
+func forgetChannelSessions(channel string) int {
+	return len(sessions.channels.removeMatching(func(key string, value interface{}) bool { return value.(interaction).Channel == channel }))
+}
@@ -0,0 +1,81 @@
+//////////////////////////////////////////////////
+// Shutdown Drain Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for letting questions being answered finish before shutting down, within a bounded drain time
+import (
+	"fmt"         // Permits formatting of straggler descriptions
+	"log"         // Permits console logging
+	"sort"        // Permits ordering of stragglers by request ID
+	"strings"     // Permits joining of straggler descriptions
+	"sync/atomic" // Permits reading of request states
+	"time"        // Permits the drain deadline and polling
+)
+
+// Global constant holding how often the drain checks whether everything has finished
+const shutdownDrainPollInterval = 100 * time.Millisecond
+
+// Global function for counting the work left before shutdown: questions being answered, questions waiting for a
+// worker, and posts not yet sent (queued, or still waiting on Slack)
+func pendingShutdownWork() (int, int, int64) {
+	messageQueue.Lock()
+	queued := len(messageQueue.pending)
+	messageQueue.Unlock()
+	outboundMu.Lock()
+	posts := outboundDepth + outboundSending
+	outboundMu.Unlock()
+	return countInFlight(), queued, posts
+}
+
+// Global function for waiting until every question has been answered and every post sent, reporting false when the
+// drain timeout passed first (0 doesn't wait at all, leaving what is pending behind without counting as a forced exit)
+func drainForShutdown(timeout time.Duration) bool {
+	if timeout <= 0 {
+		if running, queued, posts := pendingShutdownWork(); running > 0 || queued > 0 || posts > 0 {
+			log.Printf("SHUTDOWN: Draining is off; exiting with %d questions running, %d queued, and %d posts unsent.", running, queued, posts)
+		}
+		return true
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		running, queued, posts := pendingShutdownWork()
+		if running == 0 && queued == 0 && posts == 0 {
+			return true
+		}
+		if !time.Now().Before(deadline) {
+			log.Printf("SHUTDOWN ERROR: Drain timed out after %s with %d questions running, %d queued, and %d posts unsent; forcing exit.\nStill running: %s", timeout, running, queued, posts, describeInFlight())
+			return false
+		}
+		time.Sleep(shutdownDrainPollInterval)
+	}
+}
+
+// Global function for describing the questions still in flight by request ID, oldest first, for the forced exit log
+func describeInFlight() string {
+	inFlightMu.Lock()
+	var requests []*inFlightRequest
+	for _, byID := range inFlight {
+		for _, request := range byID {
+			requests = append(requests, request)
+		}
+	}
+	inFlightMu.Unlock()
+	if len(requests) == 0 {
+		return "none"
+	}
+
+	sort.Slice(requests, func(i, j int) bool { return requests[i].id < requests[j].id })
+	descriptions := make([]string, 0, len(requests))
+	for _, request := range requests {
+		state := "answering"
+		if atomic.LoadInt32(&request.state) == requestStateDelivering {
+			state = "delivering"
+		}
+		descriptions = append(descriptions, fmt.Sprintf("#%d (%s, %s) %q", request.id, request.user, state, truncateGraphemes(redactSecrets(request.question), 80)))
+	}
+	return strings.Join(descriptions, ", ")
+}
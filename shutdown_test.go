@@ -0,0 +1,110 @@
+//////////////////////////////////////////////////
+// Shutdown Drain Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing the shutdown drain against questions slower than its timeout
+import (
+	"net/http" // Permits the slow fake backend
+	"strconv"  // Permits message timestamps
+	"strings"  // Permits inspection of the straggler descriptions
+	"sync"     // Permits releasing a held post once
+	"testing"  // Permits Go testing
+	"time"     // Permits drain timeouts
+)
+
+// Test for the drain giving up on a question slower than its timeout and naming it, then completing once the question
+// finishes, while a timeout of 0 skips the drain without counting as a forced exit
+func TestShutdownDrainWithSlowHandler(t *testing.T) {
+	release := make(chan struct{})
+	withExternalAPIs(t, func(req *http.Request) (int, string) {
+		select {
+		case <-release:
+		case <-req.Context().Done():
+		}
+		return http.StatusOK, "about 47 million people"
+	})
+	// Sending the question to the slow backend on every run, never from the cache or as a repeat
+	withConfig(t, func(c *Config) {
+		c.IntentSynonyms = map[string]string{"population": "wolfram_search_query"}
+		c.MessageTimeout = 0
+		c.AnswerCacheTTL = 0
+		c.RepeatCooldown = 0
+	})
+	loadIntentSynonyms()
+	t.Cleanup(loadIntentSynonyms)
+	waitFor(t, "earlier tests' work to finish", func() bool { return drainForShutdown(shutdownDrainPollInterval) })
+
+	finished := make(chan struct{})
+	go func() {
+		// Stamping the question afresh each run, since answered messages are remembered
+		handleMSGEvent(testMessage("DDRAIN", "UDRAIN", strconv.FormatInt(time.Now().UnixNano(), 10), "population of spain"))
+		close(finished)
+	}()
+	waitFor(t, "the slow question to be in flight", func() bool { return countInFlight() == 1 })
+
+	started := time.Now()
+	if drainForShutdown(300 * time.Millisecond) {
+		t.Error("the drain completed with a question still being answered")
+	}
+	if elapsed := time.Since(started); elapsed < 300*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("the drain gave up after %s; want about 300ms", elapsed)
+	}
+	if described := describeInFlight(); !strings.Contains(described, "UDRAIN") || !strings.Contains(described, "population of spain") {
+		t.Errorf("describeInFlight = %q; want the slow question by its asker", described)
+	}
+	if !drainForShutdown(0) {
+		t.Error("drainForShutdown(0) = false with work pending; want true, since draining is off")
+	}
+
+	// Letting the question finish partway through a longer drain
+	time.AfterFunc(200*time.Millisecond, func() { close(release) })
+	if !drainForShutdown(5 * time.Second) {
+		t.Errorf("the drain timed out after the slow question finished; still running: %s", describeInFlight())
+	}
+	<-finished
+}
+
+// Test for the shutdown announcement being queued rather than holding up shutdown, and sent before the drain
+// completes even while Slack is slow to take it
+func TestShutdownAnnouncementDrained(t *testing.T) {
+	withConfig(t, func(c *Config) {
+		c.AdminChannel = "CSHUTDOWN"
+		c.AnnouncementsEnabled = true
+	})
+	waitFor(t, "earlier tests' work to finish", func() bool { return drainForShutdown(shutdownDrainPollInterval) })
+	announced := len(postsContaining("CSHUTDOWN", "going down for maintenance"))
+
+	// Holding the announcement inside Slack once its worker has taken it off the queue
+	var once sync.Once
+	release := fakeSlack.holdPosts("CSHUTDOWN")
+	t.Cleanup(func() { once.Do(release) })
+	announceShutdown()
+	waitFor(t, "the announcement to be taken off its queue", func() bool {
+		outboundMu.Lock()
+		defer outboundMu.Unlock()
+		_, queued := outboundQueues["CSHUTDOWN"]
+		return outboundSending == 1 && (!queued || len(outboundQueues["CSHUTDOWN"]) == 0)
+	})
+	if _, _, posts := pendingShutdownWork(); posts != 1 {
+		t.Fatalf("unsent posts while the announcement is being sent = %d; want 1", posts)
+	}
+	if !drainForShutdown(0) {
+		t.Error("drainForShutdown(0) = false with the announcement unsent; want true, so the shutdown is recorded as clean")
+	}
+	if drainForShutdown(300 * time.Millisecond) {
+		t.Error("the drain completed while the announcement was still being sent")
+	}
+
+	// Letting Slack take the announcement partway through a longer drain, posted by the time the drain returns
+	time.AfterFunc(200*time.Millisecond, func() { once.Do(release) })
+	if !drainForShutdown(5 * time.Second) {
+		t.Fatal("the drain timed out with only the shutdown announcement to send")
+	}
+	if got := len(postsContaining("CSHUTDOWN", "going down for maintenance")) - announced; got != 1 {
+		t.Errorf("shutdown announcements posted when the drain returned = %d; want 1", got)
+	}
+}
@@ -0,0 +1,118 @@
+//////////////////////////////////////////////////
+// Simple Explanations Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for beginner-friendly answers to "explain simply" and "eli5" questions
+import (
+	"context"   // Permits carrying simplified mode through the pipeline
+	"io/ioutil" // Permits reading of the spoken result
+	"net/http"  // Permits calling the spoken results API
+	"net/url"   // Permits building of query strings
+	"regexp"    // Permits matching of the modifier
+	"strings"   // Permits string normalization
+
+	wolfram "github.com/Krognol/go-wolfram" // External Wolfram API
+	slack "github.com/nlopes/slack"         // External Slack API
+)
+
+// Global constant holding the Wolfram spoken results endpoint
+const wolframSpokenResultsURL = "https://api.wolframalpha.com/v1/spoken"
+
+// Global patterns recognizing a leading ("explain simply what entropy is", "eli5: entropy") or trailing ("entropy, eli5") modifier
+var (
+	simplePrefixPattern = regexp.MustCompile(`(?i)^\s*(?:explain\s+(?:it\s+)?(?:simply|in\s+simple\s+terms|like\s+i'?m\s+(?:five|5))|eli5|in\s+simple\s+terms)\s*[:,-]?\s+(.+)$`)
+	simpleSuffixPattern = regexp.MustCompile(`(?i)^(.+?)[\s,]*\(?\b(?:eli5|explained\s+simply|in\s+simple\s+terms)\)?\s*[?.!]*\s*$`)
+)
+
+// Global list of full results pod titles holding plain-language explanations, in order of preference
+var simplePodTitles = []string{"basic definition", "description", "definition", "definitions", "basic information"}
+
+// Global context key type marking a question as asked in simplified mode
+type simpleModeKey struct{}
+
+// Global function for splitting a simplified-mode modifier off a question, reporting false when it has none
+func stripSimpleModifier(text string) (string, bool) {
+	for _, pattern := range []*regexp.Regexp{simplePrefixPattern, simpleSuffixPattern} {
+		if match := pattern.FindStringSubmatch(text); match != nil && strings.TrimSpace(match[1]) != "" {
+			return strings.TrimSpace(match[1]), true
+		}
+	}
+	return text, false
+}
+
+// Global function for stripping the modifier from a message and marking its context as simplified mode
+func detectSimpleModifier(ctx context.Context, event *slack.MessageEvent) context.Context {
+	question, ok := stripSimpleModifier(event.Msg.Text)
+	if !ok {
+		return ctx
+	}
+	traceStep(ctx, "simplified mode requested; asking %q", question)
+	event.Msg.Text = question
+	return context.WithValue(ctx, simpleModeKey{}, true)
+}
+
+// Global function for checking whether a question was asked in simplified mode
+func simpleModeRequested(ctx context.Context) bool {
+	simple, _ := ctx.Value(simpleModeKey{}).(bool)
+	return simple
+}
+
+// Global function for picking the most beginner-friendly pod's text from full results, empty when none is present
+func simplestPodText(result *fullResult) string {
+	for _, title := range simplePodTitles {
+		for _, pod := range result.Pods {
+			if strings.EqualFold(strings.TrimSpace(pod.Title), title) {
+				if text := strings.TrimSpace(pod.plaintext()); text != "" {
+					return text
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// Global function for requesting Wolfram's spoken result, a full-sentence answer, empty when it has none
+func fetchSpokenResult(ctx context.Context, client *wolfram.Client, query string) (string, error) {
+	if !externalAPIsAllowed() {
+		return "", errExternalAPIsDisabled
+	}
+	if err := awaitRecovery(ctx, "wolfram"); err != nil {
+		return "", err
+	}
+	recordAPICall(ctx, "wolfram")
+	req, err := http.NewRequest("GET", wolframSpokenResultsURL+"?"+url.Values{"appid": {client.AppID}, "i": {query}}.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	res, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	// Non-200 bodies are Wolfram's "did not understand" / "no spoken result" notices rather than answers
+	if res.StatusCode != http.StatusOK {
+		return "", nil
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	return strings.TrimSpace(string(body)), err
+}
+
+// Global function for finding a plain-language explanation via the definition pods, then the spoken result, reporting false when neither has one
+func simpleAnswer(ctx context.Context, client *wolfram.Client, query string) (string, bool) {
+	if result, err := fetchFullResult(ctx, client, query, nil); err == nil {
+		if text := simplestPodText(result); text != "" {
+			traceStep(ctx, "simplified answer from full results pods")
+			return text, true
+		}
+	}
+	if spoken, err := fetchSpokenResult(ctx, client, query); err == nil && spoken != "" {
+		traceStep(ctx, "simplified answer from spoken results")
+		return spoken, true
+	}
+	traceStep(ctx, "no simpler content found; keeping the normal answer")
+	return "", false
+}
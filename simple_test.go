@@ -0,0 +1,108 @@
+//////////////////////////////////////////////////
+// Simple Explanations Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing "explain simply" modifiers and the plain-language pods preferred for them
+import (
+	"context"       // Permits carrying of simplified mode
+	"encoding/json" // Permits decoding of full results fixtures
+	"net/http"      // Permits the fake Wolfram APIs
+	"strings"       // Permits routing of fake requests
+	"testing"       // Permits Go testing
+
+	wolfram "github.com/Krognol/go-wolfram" // External Wolfram API
+)
+
+// Test for the modifier being taken off either end of a question in any of its wordings, leaving questions that only
+// mention simplicity, or are nothing but the modifier, alone
+func TestDetectSimpleModifier(t *testing.T) {
+	cases := []struct {
+		text   string
+		asked  string
+		simple bool
+	}{
+		{"explain simply what entropy is", "what entropy is", true},
+		{"Explain it in simple terms: quantum tunneling", "quantum tunneling", true},
+		{"explain like I'm five - black holes", "black holes", true},
+		{"explain like im 5 black holes", "black holes", true},
+		{"ELI5: entropy", "entropy", true},
+		{"in simple terms, what is inflation", "what is inflation", true},
+		{"entropy, eli5", "entropy", true},
+		{"what is entropy (explained simply)?", "what is entropy", true},
+		{"quantum tunneling in simple terms.", "quantum tunneling", true},
+		{"eli5", "eli5", false},
+		{"explain simply", "explain simply", false},
+		{"simply the best song", "simply the best song", false},
+		{"what is a simple harmonic oscillator", "what is a simple harmonic oscillator", false},
+		{"deli5 menu", "deli5 menu", false},
+	}
+	for _, c := range cases {
+		event := testMessage("CSIMPLE", "USIMPLE", "7900.000001", c.text)
+		ctx := detectSimpleModifier(context.Background(), event)
+		if event.Msg.Text != c.asked || simpleModeRequested(ctx) != c.simple {
+			t.Errorf("detectSimpleModifier(%q) asked %q, simple %v; want %q, %v", c.text, event.Msg.Text, simpleModeRequested(ctx), c.asked, c.simple)
+		}
+	}
+}
+
+// Test for the most beginner-friendly pod winning by title preference, not by order in the results, titles matched
+// loosely and empty pods skipped
+func TestSimplestPodText(t *testing.T) {
+	cases := []struct {
+		name    string
+		fixture string
+		want    string
+	}{
+		{"preferred over earlier pods", `{"pods": [
+			{"title": "Definitions", "subpods": [{"plaintext": "1 | noun | (thermodynamics) a measure of disorder"}]},
+			{"title": "Basic definition", "subpods": [{"plaintext": "how spread out energy is"}]}
+		]}`, "how spread out energy is"},
+		{"title matched loosely", `{"pods": [{"title": "  DESCRIPTION ", "subpods": [{"plaintext": " a rare earth element "}]}]}`, "a rare earth element"},
+		{"empty pod skipped", `{"pods": [
+			{"title": "Basic definition", "subpods": [{"plaintext": ""}]},
+			{"title": "Basic information", "subpods": [{"plaintext": "symbol | Nd"}, {"plaintext": "atomic number | 60"}]}
+		]}`, "symbol | Nd\natomic number | 60"},
+		{"no explanation pod", `{"pods": [{"title": "Result", "subpods": [{"plaintext": "42"}]}]}`, ""},
+	}
+	for _, c := range cases {
+		var result fullResult
+		if err := json.Unmarshal([]byte(c.fixture), &result); err != nil {
+			t.Fatalf("%s: decoding the fixture: %v", c.name, err)
+		}
+		if got := simplestPodText(&result); got != c.want {
+			t.Errorf("%s: simplestPodText = %q; want %q", c.name, got, c.want)
+		}
+	}
+}
+
+// Test for a simple answer coming from the explanation pods when there is one, then from the spoken result, and not at
+// all when neither has one
+func TestSimpleAnswerFallbacks(t *testing.T) {
+	cases := []struct {
+		name   string
+		pods   string
+		status int
+		spoken string
+		want   string
+		ok     bool
+	}{
+		{"explanation pod", `[{"title": "Basic definition", "subpods": [{"plaintext": "how spread out energy is"}]}]`, http.StatusOK, "Entropy is a measure of disorder", "how spread out energy is", true},
+		{"spoken result", `[{"title": "Result", "subpods": [{"plaintext": "S = k log W"}]}]`, http.StatusOK, "Entropy is a measure of disorder", "Entropy is a measure of disorder", true},
+		{"neither", `[]`, http.StatusNotImplemented, "No spoken result available", "", false},
+	}
+	for _, c := range cases {
+		withExternalAPIs(t, func(req *http.Request) (int, string) {
+			if strings.HasPrefix(req.URL.String(), wolframSpokenResultsURL) {
+				return c.status, c.spoken
+			}
+			return http.StatusOK, `{"queryresult": {"success": true, "pods": ` + c.pods + `}}`
+		})
+		if got, ok := simpleAnswer(context.Background(), &wolfram.Client{AppID: "test"}, "entropy"); got != c.want || ok != c.ok {
+			t.Errorf("%s: simpleAnswer = %q, %v; want %q, %v", c.name, got, ok, c.want, c.ok)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+//////////////////////////////////////////////////
+// Slack Rate Limits Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for waiting out Slack's Retry-After on every Web API call the bot makes
+import (
+	"bytes"     // Permits replaying an upload's content on retry
+	"io/ioutil" // Permits buffering of an upload's reader
+	"log"       // Permits console logging
+	"time"      // Permits waiting out Retry-After
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global struct wrapping a Slack client so the Web API calls the bot makes retry when rate limited. Calls not overridden
+// here (auth tests, RTM) go straight to the embedded client.
+type retryingSlack struct {
+	*slack.Client
+}
+
+// Global function for running a Slack call, waiting out Retry-After and retrying while rate limited, up to the configured
+// retries and total wait. Give-ups are logged and counted per call.
+func withSlackRetry(call string, do func() error) error {
+	waited := time.Duration(0)
+	for attempt := 0; ; attempt++ {
+		err := do()
+		limited, isRateLimit := err.(*slack.RateLimitedError)
+		if !isRateLimit {
+			return err
+		}
+		metrics.inc("wolfy_slack_rate_limited_total")
+		wait := limited.RetryAfter
+		if wait <= 0 {
+			wait = time.Second
+		}
+		if attempt >= config.SlackPostRetries || (config.SlackRetryMaxWait > 0 && waited+wait > config.SlackRetryMaxWait) {
+			metrics.inc("wolfy_slack_rate_limit_giveups_total", "call", call)
+			log.Printf("SLACK ERROR: Giving up on %s after %d retries and %s waiting (Slack asked for %s more).", call, attempt, waited, wait)
+			return err
+		}
+		time.Sleep(wait)
+		waited += wait
+	}
+}
+
+// Method for posting a message, retrying when rate limited
+func (api retryingSlack) PostMessage(channelID string, options ...slack.MsgOption) (respChannel string, respTimestamp string, err error) {
+	err = withSlackRetry("chat.postMessage", func() error {
+		respChannel, respTimestamp, err = api.Client.PostMessage(channelID, options...)
+		return err
+	})
+	return respChannel, respTimestamp, err
+}
+
+// Method for posting an ephemeral message, retrying when rate limited
+func (api retryingSlack) PostEphemeral(channelID string, userID string, options ...slack.MsgOption) (timestamp string, err error) {
+	err = withSlackRetry("chat.postEphemeral", func() error {
+		timestamp, err = api.Client.PostEphemeral(channelID, userID, options...)
+		return err
+	})
+	return timestamp, err
+}
+
+// Method for editing a message, retrying when rate limited
+func (api retryingSlack) UpdateMessage(channelID string, timestamp string, options ...slack.MsgOption) (respChannel string, respTimestamp string, text string, err error) {
+	err = withSlackRetry("chat.update", func() error {
+		respChannel, respTimestamp, text, err = api.Client.UpdateMessage(channelID, timestamp, options...)
+		return err
+	})
+	return respChannel, respTimestamp, text, err
+}
+
+// Method for deleting a message, retrying when rate limited
+func (api retryingSlack) DeleteMessage(channelID string, timestamp string) (respChannel string, respTimestamp string, err error) {
+	err = withSlackRetry("chat.delete", func() error {
+		respChannel, respTimestamp, err = api.Client.DeleteMessage(channelID, timestamp)
+		return err
+	})
+	return respChannel, respTimestamp, err
+}
+
+// Method for adding a reaction, retrying when rate limited
+func (api retryingSlack) AddReaction(name string, item slack.ItemRef) error {
+	return withSlackRetry("reactions.add", func() error {
+		return api.Client.AddReaction(name, item)
+	})
+}
+
+// Method for uploading a file, retrying when rate limited; a streamed upload is buffered first so a retry can resend it
+func (api retryingSlack) UploadFile(params slack.FileUploadParameters) (file *slack.File, err error) {
+	var content []byte
+	if params.Reader != nil {
+		if content, err = ioutil.ReadAll(params.Reader); err != nil {
+			return nil, err
+		}
+	}
+	err = withSlackRetry("files.upload", func() error {
+		if content != nil {
+			params.Reader = bytes.NewReader(content)
+		}
+		file, err = api.Client.UploadFile(params)
+		return err
+	})
+	return file, err
+}
+
+// Method for looking up a user, retrying when rate limited
+func (api retryingSlack) GetUserInfo(user string) (info *slack.User, err error) {
+	err = withSlackRetry("users.info", func() error {
+		info, err = api.Client.GetUserInfo(user)
+		return err
+	})
+	return info, err
+}
+
+// Method for opening a DM channel, retrying when rate limited
+func (api retryingSlack) OpenIMChannel(user string) (noOp bool, alreadyOpen bool, channelID string, err error) {
+	err = withSlackRetry("im.open", func() error {
+		noOp, alreadyOpen, channelID, err = api.Client.OpenIMChannel(user)
+		return err
+	})
+	return noOp, alreadyOpen, channelID, err
+}
+
+// Method for looking up a message's permalink, retrying when rate limited
+func (api retryingSlack) GetPermalink(params *slack.PermalinkParameters) (permalink string, err error) {
+	err = withSlackRetry("chat.getPermalink", func() error {
+		permalink, err = api.Client.GetPermalink(params)
+		return err
+	})
+	return permalink, err
+}
+
+// Method for reading a conversation's history, retrying when rate limited
+func (api retryingSlack) GetConversationHistory(params *slack.GetConversationHistoryParameters) (history *slack.GetConversationHistoryResponse, err error) {
+	err = withSlackRetry("conversations.history", func() error {
+		history, err = api.Client.GetConversationHistory(params)
+		return err
+	})
+	return history, err
+}
+
+// Method for reading a thread's replies, retrying when rate limited
+func (api retryingSlack) GetConversationReplies(params *slack.GetConversationRepliesParameters) (messages []slack.Message, hasMore bool, nextCursor string, err error) {
+	err = withSlackRetry("conversations.replies", func() error {
+		messages, hasMore, nextCursor, err = api.Client.GetConversationReplies(params)
+		return err
+	})
+	return messages, hasMore, nextCursor, err
+}
+
+// Method for looking up a conversation, retrying when rate limited
+func (api retryingSlack) GetConversationInfo(channelID string, includeLocale bool) (channel *slack.Channel, err error) {
+	err = withSlackRetry("conversations.info", func() error {
+		channel, err = api.Client.GetConversationInfo(channelID, includeLocale)
+		return err
+	})
+	return channel, err
+}
+
+// Method for setting the bot's presence, retrying when rate limited
+func (api retryingSlack) SetUserPresence(presence string) error {
+	return withSlackRetry("users.setPresence", func() error {
+		return api.Client.SetUserPresence(presence)
+	})
+}
+
+// Method for setting the bot's custom status, retrying when rate limited
+func (api retryingSlack) SetUserCustomStatus(statusText string, statusEmoji string) error {
+	return withSlackRetry("users.profile.set", func() error {
+		return api.Client.SetUserCustomStatus(statusText, statusEmoji)
+	})
+}
+
+// Method for clearing the bot's custom status, retrying when rate limited
+func (api retryingSlack) UnsetUserCustomStatus() error {
+	return withSlackRetry("users.profile.set", func() error {
+		return api.Client.UnsetUserCustomStatus()
+	})
+}
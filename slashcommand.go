@@ -0,0 +1,102 @@
+//////////////////////////////////////////////////
+// Slash Command Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for answering the /wolfy slash command, over HTTP or Socket Mode, only to the asker or to the channel
+import (
+	"context"  // Permits bounding of the answer's lookup
+	"fmt"      // Permits formatting of public answers
+	"log"      // Permits console logging
+	"net/http" // Permits serving of the slash command route
+	"strings"  // Permits parsing of visibility flags
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constants holding the slash command route's path and the flags choosing who sees its answer
+const (
+	slashCommandPath  = "/slack/commands"
+	slashPublicFlag   = "--public"
+	slashPrivateFlag  = "--private"
+	slashInChannel    = "in_channel"
+	slashOnlyForAsker = "ephemeral"
+)
+
+// Global function for splitting a visibility flag off the start or end of a slash command's text ("--public distance
+// to mars"), so it never reaches the query; without one the configured default applies
+func parseSlashVisibility(text string) (string, bool) {
+	words := strings.Fields(text)
+	public := config.SlashCommandPublic
+	for len(words) > 0 {
+		first, last := strings.ToLower(words[0]), strings.ToLower(words[len(words)-1])
+		switch {
+		case first == slashPublicFlag || first == slashPrivateFlag:
+			public = first == slashPublicFlag
+			words = words[1:]
+		case last == slashPublicFlag || last == slashPrivateFlag:
+			public = last == slashPublicFlag
+			words = words[:len(words)-1]
+		default:
+			return strings.Join(words, " "), public
+		}
+	}
+	return "", public
+}
+
+// Global function for serving the slash command route: acknowledging at once, since Slack allows three seconds, and
+// answering through the command's response URL
+func serveSlashCommand(w http.ResponseWriter, r *http.Request) {
+	command, err := slack.SlashCommandParse(r)
+	if err != nil {
+		writeHTTPError(w, http.StatusBadRequest, "invalid_payload")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	if !acceptSlackDelivery(r, slashCommandPath, command.TriggerID) {
+		return
+	}
+	go handleSlashCommand(&command)
+}
+
+// Global function for answering a slash command. A public answer names the asker and repeats the question, since the
+// channel never sees the invocation; a private one is just the answer.
+func handleSlashCommand(command *slack.SlashCommand) {
+	query, public := parseSlashVisibility(command.Text)
+	visibility := map[bool]string{true: slashInChannel, false: slashOnlyForAsker}[public]
+	metrics.inc("wolfy_slash_commands_total", "visibility", visibility)
+
+	if query == "" {
+		respondToSlashCommand(command, slashOnlyForAsker, fmt.Sprintf("Ask me anything, e.g. `%s distance to mars`. Add `%s` to share the answer with the channel, or `%s` to keep it to yourself.", command.Command, slashPublicFlag, slashPrivateFlag))
+		return
+	}
+
+	event := &slack.MessageEvent{Msg: slack.Msg{User: command.UserID, Channel: command.ChannelID, Text: query}}
+	ctx := withFlagScope(withAPIUsage(context.Background()), event)
+	if config.MessageTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.MessageTimeout)
+		defer cancel()
+	}
+	reply := answerSubQuestion(ctx, event, query)
+	rememberInteraction(event, interaction{Text: query, EntityKey: "slash_command", Query: query, Answer: reply, Calls: apiCalls(ctx)})
+
+	if public {
+		reply = fmt.Sprintf("<@%s> asked: _%s_\n%s", command.UserID, query, reply)
+	}
+	respondToSlashCommand(command, visibility, reply)
+}
+
+// Global function for delivering a slash command's answer through its response URL as in_channel or ephemeral
+func respondToSlashCommand(command *slack.SlashCommand, responseType string, text string) {
+	err := postToResponseURL(command.ResponseURL, map[string]interface{}{
+		"response_type": responseType,
+		"text":          stagingTagged(escapeSlackText(text)),
+	})
+	if err != nil {
+		log.Printf("SLASH COMMAND ERROR: Unable to answer %s for %s.\nError Details: %v", command.Command, command.UserID, err)
+	}
+}
@@ -0,0 +1,147 @@
+//////////////////////////////////////////////////
+// Slash Command Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing answers to slash commands
+import (
+	"encoding/json"     // Permits decoding of posted responses
+	"io/ioutil"         // Permits reading of posted responses
+	"net/http"          // Permits fake response URLs
+	"net/http/httptest" // Permits the fake response URL server
+	"strings"           // Permits inspection of responses
+	"sync"              // Permits concurrency-safe response capture
+	"testing"           // Permits Go testing
+	"time"              // Permits message timeouts
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global function for pointing response URLs at a fake server for one test, returning a function listing the payloads
+// posted to it so far
+func withResponseServer(t *testing.T) func() []map[string]interface{} {
+	var mu sync.Mutex
+	var payloads []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("response URL body: %v", err)
+		}
+		mu.Lock()
+		payloads = append(payloads, payload)
+		mu.Unlock()
+	}))
+	previous := interactionResponseHost
+	interactionResponseHost = server.URL + "/"
+	t.Cleanup(func() {
+		interactionResponseHost = previous
+		server.Close()
+	})
+	return func() []map[string]interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]map[string]interface{}(nil), payloads...)
+	}
+}
+
+// Test for splitting visibility flags off either end of the command text, in any case, leaving ones mid-question be
+func TestParseSlashVisibility(t *testing.T) {
+	cases := []struct {
+		text          string
+		defaultPublic bool
+		query         string
+		public        bool
+	}{
+		{"distance to mars", false, "distance to mars", false},
+		{"distance to mars", true, "distance to mars", true},
+		{"--public distance to mars", false, "distance to mars", true},
+		{"distance to mars --public", false, "distance to mars", true},
+		{"--PRIVATE distance to mars", true, "distance to mars", false},
+		{"distance to mars   --private", true, "distance to mars", false},
+		{"--public distance to mars --private", false, "distance to mars", false},
+		{"what does --public mean", false, "what does --public mean", false},
+		{"--public", false, "", true},
+		{"", true, "", true},
+	}
+	for _, c := range cases {
+		withConfig(t, func(cfg *Config) { cfg.SlashCommandPublic = c.defaultPublic })
+		if query, public := parseSlashVisibility(c.text); query != c.query || public != c.public {
+			t.Errorf("parseSlashVisibility(%q) with default public %v = %q, %v; want %q, %v", c.text, c.defaultPublic, query, public, c.query, c.public)
+		}
+	}
+}
+
+// Test for a slash command's answer reaching its response URL as in_channel, naming the asker and the question the
+// channel never saw, or as ephemeral with just the answer, the flag never reaching the query
+func TestSlashCommandVisibility(t *testing.T) {
+	payloads := withResponseServer(t)
+	const publicReply = "<@USLASH> asked: _what is 6 * 7_\n6 * 7 = 42"
+	cases := []struct {
+		text          string
+		defaultPublic bool
+		responseType  string
+		reply         string
+	}{
+		{"what is 6 * 7", false, slashOnlyForAsker, "6 * 7 = 42"},
+		{"--public what is 6 * 7", false, slashInChannel, publicReply},
+		{"what is 6 * 7 --public", false, slashInChannel, publicReply},
+		{"what is 6 * 7", true, slashInChannel, publicReply},
+		{"--private what is 6 * 7", true, slashOnlyForAsker, "6 * 7 = 42"},
+		{"what is 6 * 7 --Private", true, slashOnlyForAsker, "6 * 7 = 42"},
+		{"--public", false, slashOnlyForAsker, "Ask me anything"},
+	}
+	for _, c := range cases {
+		withConfig(t, func(cfg *Config) { cfg.SlashCommandPublic = c.defaultPublic })
+		before := len(payloads())
+		handleSlashCommand(&slack.SlashCommand{Command: "/wolfy", Text: c.text, UserID: "USLASH", ChannelID: "CSLASH", ResponseURL: interactionResponseHost + "commands/visibility"})
+		posted := payloads()[before:]
+		if len(posted) != 1 {
+			t.Errorf("%q with default public %v posted %d responses; want 1", c.text, c.defaultPublic, len(posted))
+			continue
+		}
+		text, _ := posted[0]["text"].(string)
+		if posted[0]["response_type"] != c.responseType || !strings.HasPrefix(text, c.reply) {
+			t.Errorf("%q with default public %v posted %v %q; want %s %q", c.text, c.defaultPublic, posted[0]["response_type"], text, c.responseType, c.reply)
+		}
+		if c.reply != "Ask me anything" && strings.Contains(text, "--") {
+			t.Errorf("%q: the visibility flag reached the query: %q", c.text, text)
+		}
+	}
+}
+
+// Test for answering a slash command with and without an overall message timeout (0 disables it rather than
+// expiring every answer at once)
+func TestSlashCommandMessageTimeout(t *testing.T) {
+	var mu sync.Mutex
+	var responses []string
+	withExternalAPIs(t, func(req *http.Request) (int, string) {
+		if !strings.HasPrefix(req.URL.String(), interactionResponseHost) {
+			return http.StatusOK, "about 68 million people"
+		}
+		body, _ := ioutil.ReadAll(req.Body)
+		mu.Lock()
+		responses = append(responses, string(body))
+		mu.Unlock()
+		return http.StatusOK, "ok"
+	})
+
+	withConfig(t, func(c *Config) { c.IntentSynonyms = map[string]string{"population": "wolfram_search_query"} })
+	loadIntentSynonyms()
+	t.Cleanup(loadIntentSynonyms)
+
+	for _, timeout := range []time.Duration{0, 90 * time.Second} {
+		withConfig(t, func(c *Config) { c.MessageTimeout = timeout })
+		mu.Lock()
+		responses = nil
+		mu.Unlock()
+		handleSlashCommand(&slack.SlashCommand{Command: "/wolfy", Text: "population of france", UserID: "USLASH", ChannelID: "CSLASH", ResponseURL: interactionResponseHost + "commands/test"})
+		mu.Lock()
+		if len(responses) != 1 || !strings.Contains(responses[0], "68 million") {
+			t.Errorf("slash command responses with WOLFY_MESSAGE_TIMEOUT=%s = %q; want one answer from Wolfram", timeout, responses)
+		}
+		mu.Unlock()
+	}
+}
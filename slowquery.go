@@ -0,0 +1,131 @@
+//////////////////////////////////////////////////
+// Slow Query Log Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for logging questions that took too long to answer, with where the time went
+import (
+	"context" // Permits carrying the stage timings through the pipeline
+	"fmt"     // Permits formatting of stage timings
+	"log"     // Permits console logging
+	"strings" // Permits joining of stage timings
+	"sync"    // Permits concurrency-safe timing from handler goroutines
+	"time"    // Permits stage timing
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global struct collecting how long each stage of answering one message took, in the order the stages first ran
+type stageTimings struct {
+	mu      sync.Mutex
+	started time.Time
+	intent  string
+	order   []string
+	spent   map[string]time.Duration
+}
+
+// Global context key type for the stage timings
+type stageTimingsKey struct{}
+
+// Global function for attaching fresh stage timings to a context, starting the message's clock
+func withStageTimings(ctx context.Context) context.Context {
+	return context.WithValue(ctx, stageTimingsKey{}, &stageTimings{started: time.Now(), spent: map[string]time.Duration{}})
+}
+
+// Global function for timing a stage, returning the function that ends it; repeated stages (sub-questions) add up
+func timeStage(ctx context.Context, stage string) func() {
+	timings, ok := ctx.Value(stageTimingsKey{}).(*stageTimings)
+	if !ok {
+		return func() {}
+	}
+	started := time.Now()
+	return func() {
+		timings.mu.Lock()
+		if _, seen := timings.spent[stage]; !seen {
+			timings.order = append(timings.order, stage)
+		}
+		timings.spent[stage] += time.Since(started)
+		timings.mu.Unlock()
+	}
+}
+
+// Global function for noting the intent a message was routed to, for the slow query log
+func noteStageIntent(ctx context.Context, entityKey string) {
+	if timings, ok := ctx.Value(stageTimingsKey{}).(*stageTimings); ok {
+		timings.mu.Lock()
+		timings.intent = intentLabel(entityKey)
+		timings.mu.Unlock()
+	}
+}
+
+// Global function for logging a message whose handling took at least the slow query threshold, with its (redacted or
+// masked) text, intent, request ID, and per-stage timings
+func logSlowQuery(ctx context.Context, event *slack.MessageEvent) {
+	timings, ok := ctx.Value(stageTimingsKey{}).(*stageTimings)
+	if !ok || config.SlowQueryThreshold == 0 {
+		return
+	}
+	total := time.Since(timings.started)
+	if total < config.SlowQueryThreshold {
+		return
+	}
+
+	timings.mu.Lock()
+	intent := timings.intent
+	stages := make([]string, 0, len(timings.order))
+	for _, stage := range timings.order {
+		stages = append(stages, fmt.Sprintf("%s %s", stage, timings.spent[stage].Round(time.Millisecond)))
+	}
+	timings.mu.Unlock()
+	if intent == "" {
+		intent = "none"
+	}
+	if len(stages) == 0 {
+		stages = append(stages, "none recorded")
+	}
+
+	request := "-"
+	if inFlight := inFlightFrom(ctx); inFlight != nil {
+		request = fmt.Sprintf("#%d", inFlight.id)
+	}
+	text := redactSecrets(event.Msg.Text)
+	if config.SlowQueryMaskText {
+		text = fmt.Sprintf("(%d characters hidden)", len(graphemeClusters(event.Msg.Text)))
+	}
+	metrics.inc("wolfy_slow_queries_total", "intent", intent)
+	log.Printf("SLOW QUERY WARNING: Request %s took %s (threshold %s), intent %s: %q.\nStages: %s", request, total.Round(time.Millisecond), config.SlowQueryThreshold, intent, text, strings.Join(stages, ", "))
+}
+
+// Global function for building the compact "computed in" note appended to answers for traced users, and for admins in
+// debug mode: the time taken so far, the answer's source, and the per-stage timings. Empty for everyone else.
+func performanceNote(ctx context.Context, user string, response handlerResponse) string {
+	if !config.PerformanceNotes || !(isTraced(user) || (config.Debug && isAuthorized(user, roleViewer))) {
+		return ""
+	}
+	timings, ok := ctx.Value(stageTimingsKey{}).(*stageTimings)
+	if !ok {
+		return ""
+	}
+	total := time.Since(timings.started)
+
+	timings.mu.Lock()
+	stages := make([]string, 0, len(timings.order))
+	for _, stage := range timings.order {
+		stages = append(stages, fmt.Sprintf("%s %s", stage, timings.spent[stage].Round(time.Millisecond)))
+	}
+	timings.mu.Unlock()
+
+	note := fmt.Sprintf(":stopwatch: computed in %s", total.Round(time.Millisecond))
+	if source := responseSource(response); response.Cached {
+		note += " from the cache"
+	} else if source != "" {
+		note += " via " + source
+	}
+	if len(stages) > 0 {
+		note += " (" + strings.Join(stages, " · ") + ")"
+	}
+	return "_" + note + "_"
+}
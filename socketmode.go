@@ -0,0 +1,270 @@
+//////////////////////////////////////////////////
+// Socket Mode Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for receiving Slack events over Socket Mode, the successor to the deprecated RTM API
+import (
+	"encoding/json" // Permits decoding of Socket Mode envelopes and their events
+	"fmt"           // Permits formatting of connection errors
+	"log"           // Permits console logging
+	"net/http"      // Permits opening a Socket Mode connection
+	"reflect"       // Permits decoding events into the RTM library's event structs
+	"sync"          // Permits concurrency-safe access to the live socket
+	"time"          // Permits reconnect backoff and handshake deadlines
+
+	websocket "github.com/gorilla/websocket" // External WebSocket client
+	slack "github.com/nlopes/slack"          // External Slack API
+)
+
+// Global constants holding the endpoint handing out Socket Mode URLs and the bounds of the reconnect backoff
+const (
+	socketModeOpenURL       = "https://slack.com/api/apps.connections.open"
+	socketModeMinRetryDelay = time.Second
+	socketModeMaxRetryDelay = 2 * time.Minute
+)
+
+// Global struct holding one Socket Mode envelope: its ID (acknowledged once handled), its type ("hello", "events_api",
+// "disconnect", ...), and for Events API envelopes the event callback it carries
+type socketModeEnvelope struct {
+	EnvelopeID string          `json:"envelope_id"`
+	Type       string          `json:"type"`
+	Reason     string          `json:"reason"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// Global struct holding a Socket Mode event, shaped like an RTM event, and for Events API envelopes the acknowledgment
+// the main event loop sends once it has recorded the event as processed
+type socketModeEvent struct {
+	event slack.RTMEvent
+	ack   func()
+}
+
+// Global channel feeding Socket Mode events into the main event loop
+var socketModeEvents = make(chan socketModeEvent, 64)
+
+// Global live Socket Mode connection, closed to force a reconnect or on shutdown
+var (
+	socketModeConn    *websocket.Conn
+	socketModeMu      sync.Mutex
+	socketModeStopped bool
+)
+
+// Global function for checking whether the bot connects over Socket Mode rather than RTM
+func usesSocketMode() bool {
+	return config.ConnectionMode == "socket"
+}
+
+// Global function for asking Slack for a fresh Socket Mode WebSocket URL with the app-level token
+func openSocketModeURL() (string, error) {
+	req, err := http.NewRequest("POST", socketModeOpenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+config.SlackAppToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	var opened struct {
+		OK    bool   `json:"ok"`
+		URL   string `json:"url"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&opened); err != nil {
+		return "", err
+	}
+	if !opened.OK {
+		return "", fmt.Errorf("apps.connections.open failed: %s", opened.Error)
+	}
+	return opened.URL, nil
+}
+
+// Global function for running the Socket Mode connection until shutdown, reconnecting with backoff whenever it drops
+// and whenever Slack asks for a refresh. Connection changes are reported as the RTM library's events so the main loop
+// treats both transports alike.
+func runSocketMode() {
+	delay, connections := socketModeMinRetryDelay, 0
+	for {
+		socketModeEvents <- socketModeEvent{event: slack.RTMEvent{Type: "connecting", Data: &slack.ConnectingEvent{Attempt: 1, ConnectionCount: connections}}}
+		err := serveSocketModeConnection(&connections)
+
+		socketModeMu.Lock()
+		stopped := socketModeStopped
+		socketModeMu.Unlock()
+		if stopped {
+			return
+		}
+		socketModeEvents <- socketModeEvent{event: slack.RTMEvent{Type: "disconnected", Data: &slack.DisconnectedEvent{Intentional: err == nil}}}
+		if err == nil {
+			// Slack asked for a refresh (or the watchdog did): reconnecting straight away
+			delay = socketModeMinRetryDelay
+			continue
+		}
+
+		metrics.inc("wolfy_socket_mode_reconnects_total", "result", "failed")
+		log.Printf("SOCKET MODE ERROR: Connection lost; reconnecting in %s.\nError Details: %v", delay, err)
+		time.Sleep(delay)
+		if delay *= 2; delay > socketModeMaxRetryDelay {
+			delay = socketModeMaxRetryDelay
+		}
+	}
+}
+
+// Global function for serving one Socket Mode connection: acknowledging every envelope and forwarding its event. Events
+// API envelopes are acknowledged only after the main event loop has recorded them as processed, so an event lost to a
+// crash before then is redelivered rather than dropped. Returns nil when the connection ended on request (a
+// "disconnect" envelope, the watchdog, or shutdown).
+func serveSocketModeConnection(connections *int) error {
+	url, err := openSocketModeURL()
+	if err != nil {
+		return err
+	}
+	conn, _, err := rtmDialer.Dial(url, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetPingHandler(func(data string) error {
+		// Counting Slack's pings as traffic, as the RTM watchdog does with pongs
+		noteRTMActivity()
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+
+	socketModeMu.Lock()
+	if socketModeStopped {
+		socketModeMu.Unlock()
+		return nil
+	}
+	socketModeConn = conn
+	socketModeMu.Unlock()
+
+	// Serializing writes, since acknowledgments also come from the main event loop
+	var writeMu sync.Mutex
+	acknowledge := func(envelopeID string) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		return conn.WriteJSON(map[string]string{"envelope_id": envelopeID})
+	}
+
+	for {
+		var envelope socketModeEnvelope
+		if err := conn.ReadJSON(&envelope); err != nil {
+			socketModeMu.Lock()
+			closed := socketModeConn != conn
+			socketModeMu.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+		if envelope.EnvelopeID != "" && envelope.Type != "events_api" {
+			if err := acknowledge(envelope.EnvelopeID); err != nil {
+				return err
+			}
+		}
+
+		switch envelope.Type {
+		case "hello":
+			*connections++
+			if currentBotUserID() == "" {
+				resolveBotUserID()
+			}
+			info := &slack.Info{}
+			if id := currentBotUserID(); id != "" {
+				info.User = &slack.UserDetails{ID: id}
+			}
+			socketModeEvents <- socketModeEvent{event: slack.RTMEvent{Type: "connected", Data: &slack.ConnectedEvent{ConnectionCount: *connections, Info: info}}}
+		case "disconnect":
+			log.Printf("SOCKET MODE: Slack asked for a reconnect (%s).", envelope.Reason)
+			return nil
+		case "events_api":
+			envelopeID := envelope.EnvelopeID
+			ack := func() {
+				if envelopeID == "" {
+					return
+				}
+				if err := acknowledge(envelopeID); err != nil {
+					// Slack redelivers unacknowledged envelopes, and the processed record stops them being answered twice
+					log.Printf("SOCKET MODE ERROR: Unable to acknowledge envelope %s.\nError Details: %v", envelopeID, err)
+				}
+			}
+			event, ok := decodeSocketModeEvent(envelope.Payload)
+			if !ok {
+				ack()
+				continue
+			}
+			socketModeEvents <- socketModeEvent{event: event, ack: ack}
+		case "interactive":
+			var callback slack.InteractionCallback
+			if err := json.Unmarshal(envelope.Payload, &callback); err != nil {
+				log.Printf("SOCKET MODE ERROR: Unable to decode an interactive payload.\nError Details: %v", err)
+				continue
+			}
+			go handleInteraction(&callback)
+		case "slash_commands":
+			var command slack.SlashCommand
+			if err := json.Unmarshal(envelope.Payload, &command); err != nil {
+				log.Printf("SOCKET MODE ERROR: Unable to decode a slash command payload.\nError Details: %v", err)
+				continue
+			}
+			go handleSlashCommand(&command)
+		default:
+			// Other envelopes carry nothing the bot acts on; acknowledging them is enough
+			socketModeEvents <- socketModeEvent{event: slack.RTMEvent{Type: envelope.Type, Data: envelope.Type}}
+		}
+	}
+}
+
+// Global function for decoding an Events API callback into the RTM library's struct for its event type, reporting false
+// for payloads that don't carry an event
+func decodeSocketModeEvent(payload json.RawMessage) (slack.RTMEvent, bool) {
+	var callback struct {
+		Event json.RawMessage `json:"event"`
+	}
+	if err := json.Unmarshal(payload, &callback); err != nil || len(callback.Event) == 0 {
+		return slack.RTMEvent{}, false
+	}
+	var header struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(callback.Event, &header); err != nil || header.Type == "" {
+		return slack.RTMEvent{}, false
+	}
+
+	mapped, ok := slack.EventMapping[header.Type]
+	if !ok {
+		return slack.RTMEvent{Type: header.Type, Data: string(callback.Event)}, true
+	}
+	decoded := reflect.New(reflect.TypeOf(mapped)).Interface()
+	if err := json.Unmarshal(callback.Event, decoded); err != nil {
+		log.Printf("SOCKET MODE ERROR: Unable to decode a %q event.\nError Details: %v", header.Type, err)
+		return slack.RTMEvent{}, false
+	}
+	return slack.RTMEvent{Type: header.Type, Data: decoded}, true
+}
+
+// Global function for dropping the live Socket Mode connection so the run loop opens a fresh one
+func restartSocketMode() {
+	socketModeMu.Lock()
+	conn := socketModeConn
+	socketModeConn = nil
+	socketModeMu.Unlock()
+	if conn != nil {
+		conn.Close()
+	}
+}
+
+// Global function for closing the Socket Mode connection for good on shutdown
+func stopSocketMode() {
+	socketModeMu.Lock()
+	socketModeStopped = true
+	socketModeMu.Unlock()
+	restartSocketMode()
+}
@@ -0,0 +1,34 @@
+//////////////////////////////////////////////////
+// Staging Mode Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for marking a staging instance's replies and keeping it to its test channel
+import (
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global function for prefixing a post's text with the staging tag when running as a staging instance, so its replies are
+// never mistaken for production's
+func stagingTagged(text string) string {
+	if config.StagingMode && config.StagingTag != "" && text != "" {
+		return config.StagingTag + " " + text
+	}
+	return text
+}
+
+// Global function for deciding whether to handle a message by where it was sent: a staging instance with a test channel
+// answers only there and in DMs, leaving every other channel to production
+func acceptsChannel(event *slack.MessageEvent) bool {
+	if !config.StagingMode || config.StagingChannel == "" {
+		return true
+	}
+	if event.Channel == config.StagingChannel || isDirectMessage(event.Channel) {
+		return true
+	}
+	metrics.inc("wolfy_staging_ignored_messages_total")
+	return false
+}
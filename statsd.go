@@ -0,0 +1,160 @@
+//////////////////////////////////////////////////
+// StatsD Exporter Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for pushing the same counters, gauges, and histograms to a StatsD/Graphite endpoint
+import (
+	"log"     // Permits console logging
+	"net"     // Permits sending StatsD packets over UDP
+	"regexp"  // Permits sanitizing of metric path segments
+	"strconv" // Permits formatting of metric values
+	"strings" // Permits flattening of labelled metric keys
+	"sync"    // Permits concurrency-safe buffering of observations
+	"time"    // Permits the flush interval
+)
+
+// Global constants capping a StatsD packet to what fits an unfragmented UDP datagram, and how many histogram
+// observations are buffered between flushes
+const (
+	statsDMaxPacket       = 1432
+	statsDMaxObservations = 10000
+)
+
+// Global pattern matching characters StatsD and Graphite treat specially in a metric path
+var statsDUnsafePattern = regexp.MustCompile(`[^A-Za-z0-9_\-]+`)
+
+// Global histogram observations waiting for the next flush, already formatted as StatsD lines
+var (
+	statsDObservations   []string
+	statsDObservationsMu sync.Mutex
+)
+
+// Global function for checking whether metrics are pushed to StatsD
+func statsDEnabled() bool {
+	return config.MetricsExporter == "statsd" || config.MetricsExporter == "both"
+}
+
+// Global function for checking whether metrics are served on the Prometheus endpoint
+func prometheusEnabled() bool {
+	return config.MetricsExporter == "prometheus" || config.MetricsExporter == "both"
+}
+
+// Global function for flattening a labelled metric key into a dotted StatsD path after the configured prefix, e.g.
+// `wolfy_intents_total{intent="greetings"}` into "wolfy_intents_total.intent.greetings"
+func statsDName(key string) string {
+	name, labels := key, ""
+	if i := strings.Index(key, "{"); i >= 0 {
+		name, labels = key[:i], strings.TrimSuffix(key[i+1:], "}")
+	}
+	segments := []string{name}
+	for _, pair := range splitMetricLabels(labels) {
+		if i := strings.Index(pair, "="); i > 0 {
+			value := strings.Trim(pair[i+1:], `"`)
+			segments = append(segments, pair[:i], statsDUnsafePattern.ReplaceAllString(value, "_"))
+		}
+	}
+	return config.StatsDPrefix + strings.Join(segments, ".")
+}
+
+// Global function for splitting a metric key's label pairs, leaving commas inside quoted values alone
+func splitMetricLabels(labels string) []string {
+	var pairs []string
+	start, quoted := 0, false
+	for i := 0; i < len(labels); i++ {
+		switch labels[i] {
+		case '\\':
+			i++
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				pairs = append(pairs, labels[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if start < len(labels) {
+		pairs = append(pairs, labels[start:])
+	}
+	return pairs
+}
+
+// Global function for buffering a histogram observation for the next StatsD flush
+func queueStatsDObservation(key string, value float64) {
+	if !statsDEnabled() {
+		return
+	}
+	line := statsDName(key) + ":" + strconv.FormatFloat(value, 'g', -1, 64) + "|h"
+	statsDObservationsMu.Lock()
+	if len(statsDObservations) < statsDMaxObservations {
+		statsDObservations = append(statsDObservations, line)
+	}
+	statsDObservationsMu.Unlock()
+}
+
+// Global function for pushing metrics to StatsD every flush interval: counters as their increase since the last flush,
+// gauges as their value, and histograms as every buffered observation
+func startStatsDExporter() {
+	if !statsDEnabled() {
+		return
+	}
+	conn, err := net.Dial("udp", config.StatsDAddr)
+	if err != nil {
+		log.Printf("METRICS ERROR: Unable to reach StatsD at %s; not exporting.\nError Details: %v", config.StatsDAddr, err)
+		return
+	}
+
+	go func() {
+		previous := map[string]int64{}
+		for range time.Tick(config.StatsDFlushInterval) {
+			previous = flushStatsD(conn, previous)
+		}
+	}()
+}
+
+// Global function for sending one flush's worth of StatsD lines, returning the counter values it reported up to
+func flushStatsD(conn net.Conn, previous map[string]int64) map[string]int64 {
+	keys, values := metrics.sortedKeys()
+	var lines []string
+	for _, key := range keys {
+		name := key
+		if i := strings.Index(key, "{"); i >= 0 {
+			name = key[:i]
+		}
+		if metrics.isGauge(name) {
+			lines = append(lines, statsDName(key)+":"+strconv.FormatInt(values[key], 10)+"|g")
+		} else if delta := values[key] - previous[key]; delta > 0 {
+			lines = append(lines, statsDName(key)+":"+strconv.FormatInt(delta, 10)+"|c")
+		}
+	}
+	statsDObservationsMu.Lock()
+	lines, statsDObservations = append(lines, statsDObservations...), nil
+	statsDObservationsMu.Unlock()
+
+	// Packing lines into as few datagrams as fit
+	var packet strings.Builder
+	send := func() {
+		if packet.Len() == 0 {
+			return
+		}
+		if _, err := conn.Write([]byte(packet.String())); err != nil {
+			metrics.inc("wolfy_statsd_send_errors_total")
+		}
+		packet.Reset()
+	}
+	for _, line := range lines {
+		if packet.Len() > 0 && packet.Len()+1+len(line) > statsDMaxPacket {
+			send()
+		}
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+		packet.WriteString(line)
+	}
+	send()
+	return values
+}
@@ -0,0 +1,61 @@
+//////////////////////////////////////////////////
+// Storage Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for wiring the persistence layer into the bot
+import (
+	"time" // Permits periodic flushing
+
+	storage "github.com/AakashSudhakar/wolfybot/internal/store" // Internal JSON-file persistence layer
+)
+
+// Global struct adapting the internal file store to the calls every persistent feature makes
+type fileStore struct {
+	file *storage.File
+}
+
+// Global store shared by every persistent feature
+var store *fileStore
+
+// Global function for opening the data file, starting empty when it does not exist yet
+func openStore(path string) (*fileStore, error) {
+	file, err := storage.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &fileStore{file: file}, nil
+}
+
+// Method for decoding a stored record into target, reporting whether it exists
+func (s *fileStore) get(bucket string, key string, target interface{}) bool {
+	return s.file.Get(bucket, key, target)
+}
+
+// Method for saving a record in memory; it reaches disk on the next flush
+func (s *fileStore) put(bucket string, key string, value interface{}) error {
+	return s.file.Put(bucket, key, value)
+}
+
+// Method for removing a record
+func (s *fileStore) delete(bucket string, key string) {
+	s.file.Delete(bucket, key)
+}
+
+// Method for listing the keys of a bucket in sorted order
+func (s *fileStore) keys(bucket string) []string {
+	return s.file.Keys(bucket)
+}
+
+// Method for writing pending changes to disk atomically
+func (s *fileStore) flush() error {
+	return s.file.Flush()
+}
+
+// Method for flushing pending changes in the background on a fixed interval
+func (s *fileStore) startFlusher(interval time.Duration) {
+	s.file.StartFlusher(interval)
+}
@@ -0,0 +1,148 @@
+//////////////////////////////////////////////////
+// Subscriptions Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for subscribing to a question answered by DM on a schedule, and listing and dropping subscriptions
+import (
+	"crypto/rand" // Permits generation of subscription IDs
+	"fmt"         // Permits formatting of command replies
+	"log"         // Permits console logging
+	"regexp"      // Permits matching of subscription commands
+	"strings"     // Permits string normalization
+	"time"        // Permits run bookkeeping and catch-up decisions
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global patterns recognizing "subscribe me to 'X' every weekday at 8am", the listing, and unsubscribing by ID
+var (
+	subscribePattern         = regexp.MustCompile(`(?i)^\s*(?:wolfy\s+)?(?:please\s+)?subscribe\s+me\s+to\s+(.+?)\s+` + recurPhrase + `\s*[?.!]*\s*$`)
+	subscriptionsListPattern = regexp.MustCompile(`(?i)^\s*(?:wolfy\s+)?(?:list\s+|show\s+)?(?:my\s+)?subscriptions\s*[?.!]*\s*$`)
+	unsubscribePattern       = regexp.MustCompile(`(?i)^\s*(?:wolfy\s+)?unsubscribe\s+(?:me\s+from\s+)?(?:#|id\s+)?([a-z0-9]+)\s*[.!]*\s*$`)
+)
+
+// Global function for extracting a subscription's question and recurrence from a message
+func parseSubscription(text string) (scheduledQuery, bool) {
+	match := subscribePattern.FindStringSubmatch(text)
+	if match == nil {
+		return scheduledQuery{}, false
+	}
+	question := strings.TrimSpace(strings.Trim(match[1], `"'“”‘’`))
+	if question == "" {
+		return scheduledQuery{}, false
+	}
+	return recurringQuery(match[2:6], question)
+}
+
+// Global function for loading a user's recurring queries (their subscriptions), soonest first
+func userSubscriptions(user string) []scheduledQuery {
+	var subscriptions []scheduledQuery
+	for _, query := range userScheduledQueries(user) {
+		if query.Every != "" {
+			subscriptions = append(subscriptions, query)
+		}
+	}
+	return subscriptions
+}
+
+// Global function for generating a short subscription ID that is unique among the user's subscriptions
+func newSubscriptionRef(user string) string {
+	taken := map[string]bool{}
+	for _, query := range userSubscriptions(user) {
+		taken[query.Ref] = true
+	}
+	random := make([]byte, 2)
+	for {
+		if _, err := rand.Read(random); err != nil {
+			random[0], random[1] = byte(time.Now().UnixNano()), byte(time.Now().UnixNano()>>8)
+		}
+		if ref := fmt.Sprintf("%x", random); !taken[ref] {
+			return ref
+		}
+	}
+}
+
+// Global function for checking whether an occurrence fell due while the bot was down rather than since the last check
+func missedWhileDown(query scheduledQuery, now time.Time) bool {
+	return now.Sub(query.Due) > 2*config.ScheduleCheckInterval
+}
+
+// Global function for recording how a subscription's latest run went, unless it was unsubscribed meanwhile
+func noteSubscriptionRun(id string, failed bool) {
+	scheduleMu.Lock()
+	defer scheduleMu.Unlock()
+
+	var query scheduledQuery
+	if !store.get(scheduledQueriesBucket, id, &query) {
+		return
+	}
+	query.LastRun, query.LastFailed = time.Now(), failed
+	if err := store.put(scheduledQueriesBucket, id, query); err != nil {
+		log.Printf("SCHEDULE ERROR: Unable to record the run of subscription %s.\nError Details: %v", id, err)
+	}
+}
+
+// Global function for listing and dropping subscriptions, reporting whether the message was one of those commands
+func handleSubscriptionCommand(event *slack.MessageEvent) bool {
+	if subscriptionsListPattern.MatchString(event.Msg.Text) {
+		metrics.inc("wolfy_intents_total", "intent", "subscription_list")
+		scheduleMu.Lock()
+		subscriptions := userSubscriptions(event.User)
+		for i, query := range subscriptions {
+			// Giving recurring questions set up before subscriptions had IDs one now
+			if query.Ref == "" {
+				query.Ref = newSubscriptionRef(event.User)
+				if err := store.put(scheduledQueriesBucket, query.ID, query); err != nil {
+					log.Printf("SCHEDULE ERROR: Unable to assign an ID to subscription %s.\nError Details: %v", query.ID, err)
+				}
+				subscriptions[i] = query
+			}
+		}
+		scheduleMu.Unlock()
+
+		if len(subscriptions) == 0 {
+			postText(event.User, "You don't have any subscriptions. Say \"subscribe me to 'weather in Berlin' every weekday at 8am\" to start one.")
+			return true
+		}
+		location := userLocation(event.User)
+		lines := []string{fmt.Sprintf("Your subscriptions (%d of %d):", len(subscriptions), config.SubscriptionLimit)}
+		for _, query := range subscriptions {
+			line := fmt.Sprintf("• `%s` \"%s\" %s, next %s", query.Ref, isolateDirection(truncateGraphemes(query.Query, 80)), describeRecurrence(query), query.Due.In(location).Format("Mon Jan 2 3:04 PM MST"))
+			if query.LastFailed {
+				line += fmt.Sprintf(" _(the last run, %s, couldn't get an answer)_", query.LastRun.In(location).Format("Mon Jan 2"))
+			}
+			lines = append(lines, line)
+		}
+		lines = append(lines, "Say \"wolfy unsubscribe <id>\" to drop one.")
+		postText(event.User, strings.Join(lines, "\n"))
+		return true
+	}
+
+	if match := unsubscribePattern.FindStringSubmatch(event.Msg.Text); match != nil {
+		metrics.inc("wolfy_intents_total", "intent", "unsubscribe")
+		ref := strings.ToLower(match[1])
+
+		scheduleMu.Lock()
+		var dropped *scheduledQuery
+		for _, query := range userSubscriptions(event.User) {
+			if query.Ref == ref {
+				store.delete(scheduledQueriesBucket, query.ID)
+				dropped = &query
+				break
+			}
+		}
+		scheduleMu.Unlock()
+
+		if dropped == nil {
+			postText(event.User, fmt.Sprintf("I couldn't find subscription `%s`. Say \"wolfy subscriptions\" to see their IDs.", match[1]))
+			return true
+		}
+		postText(event.User, fmt.Sprintf("Unsubscribed from \"%s\" (%s).", dropped.Query, describeRecurrence(*dropped)))
+		return true
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+//////////////////////////////////////////////////
+// Follow-up Suggestions Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for suggesting related questions to ask after an answer
+import (
+	"context" // Permits tracing of the suggestions made
+	"fmt"     // Permits formatting of suggested questions
+	"regexp"  // Permits splitting of a question into its attribute and subject
+	"strings" // Permits string normalization
+)
+
+// Global constant capping how many follow-up questions are suggested after an answer
+const maxFollowUpSuggestions = 2
+
+// Global pattern splitting "what is the population of Japan?" into its attribute ("population") and subject ("Japan")
+var followUpQueryPattern = regexp.MustCompile(`(?i)^\s*(?:what(?:'s|\s+is|\s+are|\s+was)\s+)?(?:the\s+)?(.+?)\s+(?:of|in|to)\s+(?:the\s+)?(.+?)\s*[?.!]*\s*$`)
+
+// Global struct describing a kind of subject follow-ups can be suggested for: the subjects it covers (lowercase name to
+// display name) and the question templates offered for them
+type followUpType struct {
+	subjects  map[string]string
+	templates []string
+}
+
+// Global capitals of the countries below, so a question about a country can suggest the same question about its capital
+var countryCapitals = map[string]string{
+	"japan": "Tokyo", "france": "Paris", "germany": "Berlin", "italy": "Rome", "spain": "Madrid", "china": "Beijing",
+	"india": "New Delhi", "russia": "Moscow", "brazil": "Brasília", "canada": "Ottawa", "mexico": "Mexico City",
+	"australia": "Canberra", "egypt": "Cairo", "kenya": "Nairobi", "nigeria": "Abuja", "argentina": "Buenos Aires",
+	"south korea": "Seoul", "united kingdom": "London", "uk": "London", "united states": "Washington, D.C.",
+	"usa": "Washington, D.C.", "us": "Washington, D.C.", "sweden": "Stockholm", "norway": "Oslo", "turkey": "Ankara",
+	"portugal": "Lisbon", "greece": "Athens", "thailand": "Bangkok", "indonesia": "Jakarta", "vietnam": "Hanoi",
+}
+
+// Global kinds of subjects with the follow-ups offered for each
+var followUpTypes = []followUpType{
+	{
+		subjects: map[string]string{
+			"japan": "Japan", "france": "France", "germany": "Germany", "italy": "Italy", "spain": "Spain", "china": "China",
+			"india": "India", "russia": "Russia", "brazil": "Brazil", "canada": "Canada", "mexico": "Mexico",
+			"australia": "Australia", "egypt": "Egypt", "kenya": "Kenya", "nigeria": "Nigeria", "argentina": "Argentina",
+			"south korea": "South Korea", "united kingdom": "the United Kingdom", "uk": "the UK",
+			"united states": "the United States", "usa": "the USA", "us": "the US", "sweden": "Sweden", "norway": "Norway",
+			"turkey": "Turkey", "portugal": "Portugal", "greece": "Greece", "thailand": "Thailand", "indonesia": "Indonesia",
+			"vietnam": "Vietnam",
+		},
+		templates: []string{"population of %s", "GDP of %s", "capital of %s", "area of %s", "currency of %s"},
+	},
+	{
+		subjects: map[string]string{
+			"tokyo": "Tokyo", "paris": "Paris", "london": "London", "berlin": "Berlin", "new york": "New York",
+			"new york city": "New York City", "los angeles": "Los Angeles", "chicago": "Chicago", "san francisco": "San Francisco",
+			"seattle": "Seattle", "boston": "Boston", "rome": "Rome", "madrid": "Madrid", "beijing": "Beijing",
+			"shanghai": "Shanghai", "mumbai": "Mumbai", "moscow": "Moscow", "sydney": "Sydney", "toronto": "Toronto",
+			"cairo": "Cairo", "dubai": "Dubai", "singapore": "Singapore", "seoul": "Seoul", "bangkok": "Bangkok",
+		},
+		templates: []string{"population of %s", "weather in %s", "time in %s", "elevation of %s"},
+	},
+	{
+		subjects: map[string]string{
+			"hydrogen": "hydrogen", "helium": "helium", "carbon": "carbon", "nitrogen": "nitrogen", "oxygen": "oxygen",
+			"sodium": "sodium", "aluminum": "aluminum", "silicon": "silicon", "sulfur": "sulfur", "iron": "iron",
+			"copper": "copper", "zinc": "zinc", "silver": "silver", "gold": "gold", "mercury": "mercury", "lead": "lead",
+			"uranium": "uranium", "water": "water", "ethanol": "ethanol",
+		},
+		templates: []string{"melting point of %s", "boiling point of %s", "density of %s", "molar mass of %s"},
+	},
+	{
+		subjects: map[string]string{
+			"the sun": "the Sun", "sun": "the Sun", "the moon": "the Moon", "moon": "the Moon", "venus": "Venus",
+			"earth": "Earth", "mars": "Mars", "jupiter": "Jupiter", "saturn": "Saturn", "uranus": "Uranus",
+			"neptune": "Neptune", "pluto": "Pluto",
+		},
+		templates: []string{"mass of %s", "radius of %s", "distance to %s", "surface gravity of %s"},
+	},
+}
+
+// Registering the follow-up suggestions flag, off until rolled out
+func init() {
+	registerFeatureFlag(featureFlag{name: "follow_up_suggestions", description: "Suggest one or two related questions after an answer (\"GDP of Japan?\" after \"population of Japan\")."})
+}
+
+// Global function for suggesting up to two related questions about a question's subject, empty when its subject isn't a
+// known kind. Asks about a country first suggest the same question about its capital.
+func followUpSuggestions(query string) []string {
+	match := followUpQueryPattern.FindStringSubmatch(query)
+	if match == nil {
+		return nil
+	}
+	attribute, subject := strings.ToLower(strings.TrimSpace(match[1])), strings.ToLower(strings.TrimSpace(match[2]))
+
+	var suggestions []string
+	for _, kind := range followUpTypes {
+		display, ok := kind.subjects[subject]
+		if !ok {
+			continue
+		}
+		asked := ""
+		for _, template := range kind.templates {
+			if strings.HasPrefix(strings.ToLower(template), attribute+" ") {
+				asked = template
+			}
+		}
+		if capital, ok := countryCapitals[subject]; ok && asked == "population of %s" {
+			suggestions = append(suggestions, fmt.Sprintf(asked, capital)+"?")
+		}
+		for _, template := range kind.templates {
+			if template != asked && len(suggestions) < maxFollowUpSuggestions {
+				suggestions = append(suggestions, fmt.Sprintf(template, display)+"?")
+			}
+		}
+		break
+	}
+	return suggestions
+}
+
+// Global function for appending follow-up suggestions to an answer when the asker's flag is on
+func withFollowUpSuggestions(ctx context.Context, query string, text string) string {
+	if !flagEnabled(ctx, "follow_up_suggestions") {
+		return text
+	}
+	suggestions := followUpSuggestions(query)
+	if len(suggestions) == 0 {
+		return text
+	}
+	traceStep(ctx, "suggesting follow-ups %q", suggestions)
+	metrics.inc("wolfy_follow_up_suggestions_total")
+	return text + "\n_You could also ask: \"" + strings.Join(suggestions, "\" or \"") + "\"_"
+}
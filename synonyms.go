@@ -0,0 +1,70 @@
+//////////////////////////////////////////////////
+// Intent Synonyms Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for routing team jargon straight to an intent without retraining Wit.ai
+import (
+	"log"    // Permits console logging
+	"regexp" // Permits whole-word phrase matching
+	"sort"   // Permits longest-phrase-first matching
+	"sync"   // Permits concurrency-safe access to the compiled synonyms
+
+	wit "github.com/christianrondeau/go-wit" // External Wit.ai API
+)
+
+// Global struct holding one configured synonym: its phrase, the pattern matching it as whole words, and its entity key
+type intentSynonym struct {
+	phrase    string
+	pattern   *regexp.Regexp
+	entityKey string
+}
+
+// Global compiled synonyms, longest phrase first so "burn rate report" wins over "burn rate"
+var (
+	intentSynonyms   []intentSynonym
+	intentSynonymsMu sync.RWMutex
+)
+
+// Global function for compiling the configured synonyms, warning about any whose intent has no route
+func loadIntentSynonyms() {
+	synonyms := make([]intentSynonym, 0, len(config.IntentSynonyms))
+	for phrase, entityKey := range config.IntentSynonyms {
+		if phrase == "" || entityKey == "" {
+			continue
+		}
+		// Word boundaries that also hold at phrases starting or ending in punctuation ("c++", "p&l")
+		pattern := regexp.MustCompile(`(?i)(?:^|[^\pL\pN_])` + regexp.QuoteMeta(phrase) + `(?:$|[^\pL\pN_])`)
+		synonyms = append(synonyms, intentSynonym{phrase: phrase, pattern: pattern, entityKey: entityKey})
+		if _, ok := entityHandlers[currentRouting.Load().(routingTable)[entityKey]]; !ok {
+			log.Printf("CONFIG ERROR: Synonym %q maps to %q, which no handler is routed for; messages using it will get the unclear reply.", phrase, entityKey)
+		}
+	}
+	sort.Slice(synonyms, func(i, j int) bool {
+		if len(synonyms[i].phrase) != len(synonyms[j].phrase) {
+			return len(synonyms[i].phrase) > len(synonyms[j].phrase)
+		}
+		return synonyms[i].phrase < synonyms[j].phrase
+	})
+
+	intentSynonymsMu.Lock()
+	intentSynonyms = synonyms
+	intentSynonymsMu.Unlock()
+}
+
+// Global function for matching a message against the synonyms, returning the intent of the longest phrase it contains
+// as a full-confidence entity carrying the whole message
+func matchIntentSynonym(text string) (string, wit.MessageEntity, string, bool) {
+	intentSynonymsMu.RLock()
+	defer intentSynonymsMu.RUnlock()
+
+	for _, synonym := range intentSynonyms {
+		if synonym.pattern.MatchString(text) {
+			return synonym.entityKey, wit.MessageEntity{Value: text, Confidence: 1}, synonym.phrase, true
+		}
+	}
+	return "", wit.MessageEntity{}, "", false
+}
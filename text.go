@@ -0,0 +1,132 @@
+//////////////////////////////////////////////////
+// Unicode Text Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for Unicode-safe normalization and truncation of queries and answers
+import (
+	"regexp"       // Permits recognition of Slack code formatting
+	"strings"      // Permits string building
+	"unicode"      // Permits classification of combining marks
+	"unicode/utf8" // Permits rune-level decoding
+)
+
+// Global constant holding the zero-width joiner that glues emoji sequences together
+const zeroWidthJoiner = '\u200d'
+
+// Global patterns recognizing Slack code blocks ("```2^10```", across lines too) and inline code ("`2^10`")
+var (
+	codeBlockPattern  = regexp.MustCompile("(?s)```(.*?)```")
+	inlineCodePattern = regexp.MustCompile("`([^`\n]+)`")
+)
+
+// Global function for checking whether a rune is an invisible bidi control (LRM, RLM, embeddings, isolates)
+func isBidiControl(r rune) bool {
+	return r == '\u200e' || r == '\u200f' || r == '\u061c' || (r >= '\u202a' && r <= '\u202e') || (r >= '\u2066' && r <= '\u2069')
+}
+
+// Global function for checking whether a rune extends the preceding grapheme cluster rather than starting a new one
+func extendsGrapheme(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		(r >= '\ufe00' && r <= '\ufe0f') || // Variation selectors (e.g. emoji presentation)
+		(r >= 0x1f3fb && r <= 0x1f3ff) || // Emoji skin tone modifiers
+		(r >= 0xe0020 && r <= 0xe007f) // Emoji tag sequences (subdivision flags)
+}
+
+// Global function for checking whether a rune is a regional indicator (flag emoji come in pairs of these)
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// Global function for splitting text into user-perceived characters (an approximation of UAX #29 grapheme clusters)
+func graphemeClusters(text string) []string {
+	var clusters []string
+	var current strings.Builder
+	joinNext := false
+	regionalCount := 0
+
+	for _, r := range text {
+		startsNew := current.Len() > 0 && !joinNext && !extendsGrapheme(r) && r != zeroWidthJoiner
+		if isRegionalIndicator(r) {
+			// Pairing regional indicators so flags stay whole
+			if regionalCount%2 == 1 {
+				startsNew = false
+			}
+			regionalCount++
+		} else {
+			regionalCount = 0
+		}
+
+		if startsNew {
+			clusters = append(clusters, current.String())
+			current.Reset()
+		}
+		current.WriteRune(r)
+		joinNext = r == zeroWidthJoiner
+	}
+	if current.Len() > 0 {
+		clusters = append(clusters, current.String())
+	}
+	return clusters
+}
+
+// Global function for shortening user-facing text to at most max grapheme clusters, appending an ellipsis when cut
+func truncateGraphemes(text string, max int) string {
+	clusters := graphemeClusters(text)
+	if len(clusters) <= max {
+		return text
+	}
+	return strings.Join(clusters[:max], "") + "\u2026"
+}
+
+// Global function for shortening text to at most maxBytes without splitting a grapheme cluster (for logs and samples)
+func truncateBytes(text string, maxBytes int) (string, bool) {
+	if len(text) <= maxBytes {
+		return text, false
+	}
+
+	var kept strings.Builder
+	for _, cluster := range graphemeClusters(text) {
+		if kept.Len()+len(cluster) > maxBytes {
+			break
+		}
+		kept.WriteString(cluster)
+	}
+	return kept.String(), true
+}
+
+// Global function for unwrapping Slack code formatting from a question ("what is `2^10`" -> "what is 2^10"), so an
+// expression typed as code is classified and evaluated like any other; the message itself is left as the user sent it
+func stripCodeFormatting(text string) string {
+	text = codeBlockPattern.ReplaceAllStringFunc(text, func(block string) string {
+		return strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(block, "```"), "```"))
+	})
+	return inlineCodePattern.ReplaceAllString(text, "$1")
+}
+
+// Global function for normalizing a query: repairing invalid UTF-8, dropping invisible bidi controls, and trimming
+func normalizeQueryText(text string) string {
+	if !utf8.ValidString(text) {
+		text = strings.ToValidUTF8(text, "\ufffd")
+	}
+	text = strings.Map(func(r rune) rune {
+		if isBidiControl(r) {
+			return -1
+		}
+		return r
+	}, text)
+	return strings.TrimSpace(text)
+}
+
+// Global function for isolating right-to-left text inside LTR replies so surrounding punctuation renders correctly
+func isolateDirection(text string) string {
+	for _, r := range text {
+		if unicode.In(r, unicode.Arabic, unicode.Hebrew, unicode.Syriac, unicode.Thaana) {
+			return "\u2068" + text + "\u2069"
+		}
+	}
+	return text
+}
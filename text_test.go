@@ -0,0 +1,127 @@
+//////////////////////////////////////////////////
+// Unicode Text Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing normalization and truncation of right-to-left text and emoji sequences
+import (
+	"testing"      // Permits Go testing
+	"unicode/utf8" // Permits validation of truncated text
+)
+
+// Test for queries losing invisible bidi controls and invalid bytes but keeping their right-to-left letters, marks,
+// and emoji joiners
+func TestNormalizeQueryText(t *testing.T) {
+	cases := []struct {
+		text string
+		want string
+	}{
+		{"  \u200fما هو عدد سكان مصر؟\u200f ", "ما هو عدد سكان مصر؟"},
+		{"\u202bמה הבירה של צרפת?\u202c", "מה הבירה של צרפת?"},
+		{"\u2067שָׁלוֹם\u2069 in English", "שָׁלוֹם in English"},
+		{"\u061cكَتَبَ", "كَتَبَ"},
+		{"population of \u200eIsrael\u200e", "population of Israel"},
+		{"👨\u200d👩\u200d👧 emoji", "👨\u200d👩\u200d👧 emoji"},
+		{"caf\xe9 au lait", "caf\ufffd au lait"},
+		{"\u2066\u2069", ""},
+	}
+	for _, c := range cases {
+		if got := normalizeQueryText(c.text); got != c.want {
+			t.Errorf("normalizeQueryText(%q) = %q; want %q", c.text, got, c.want)
+		}
+	}
+}
+
+// Test for counting user-perceived characters: skin-toned and ZWJ-joined emoji, flags, keycaps, and letters carrying
+// Hebrew points or Arabic vowel marks each count once
+func TestGraphemeClusters(t *testing.T) {
+	cases := []struct {
+		text string
+		want int
+	}{
+		{"abc", 3},
+		{"👍🏽", 1},
+		{"👍🏽👍🏿", 2},
+		{"👩🏾\u200d💻", 1},
+		{"👨\u200d👩\u200d👧\u200d👦", 1},
+		{"🏳\ufe0f\u200d🌈", 1},
+		{"🇫🇷🇯🇵", 2},
+		{"🇫🇷🇯", 2},
+		{"🏴\U000e0067\U000e0062\U000e0073\U000e0063\U000e0074\U000e007f", 1},
+		{"1\ufe0f\u20e3", 1},
+		{"שָׁלוֹם", 4},
+		{"كَتَبَ", 3},
+		{"é", 1},
+		{"", 0},
+	}
+	for _, c := range cases {
+		if got := len(graphemeClusters(c.text)); got != c.want {
+			t.Errorf("len(graphemeClusters(%q)) = %d; want %d", c.text, got, c.want)
+		}
+	}
+}
+
+// Test for truncation never cutting inside a grapheme cluster, whether by clusters for replies or by bytes for logs
+func TestTruncateGraphemes(t *testing.T) {
+	cases := []struct {
+		text string
+		max  int
+		want string
+	}{
+		{"hello world", 5, "hello…"},
+		{"hello", 5, "hello"},
+		{"hi 👍🏽👍🏿", 4, "hi 👍🏽…"},
+		{"👨\u200d👩\u200d👧\u200d👦 family", 1, "👨\u200d👩\u200d👧\u200d👦…"},
+		{"🇫🇷🇯🇵🇮🇳", 2, "🇫🇷🇯🇵…"},
+		{"שָׁלוֹם עולם", 4, "שָׁלוֹם…"},
+		{"كَتَبَ الدرس", 3, "كَتَبَ…"},
+	}
+	for _, c := range cases {
+		if got := truncateGraphemes(c.text, c.max); got != c.want {
+			t.Errorf("truncateGraphemes(%q, %d) = %q; want %q", c.text, c.max, got, c.want)
+		}
+	}
+
+	bytes := []struct {
+		text     string
+		maxBytes int
+		want     string
+		cut      bool
+	}{
+		{"short", 10, "short", false},
+		{"ab👍🏽", 6, "ab", true},
+		{"ab👍🏽c", 10, "ab👍🏽", true},
+		{"a👨\u200d👩\u200d👧", 12, "a", true},
+		{"שָׁלוֹם", 5, "", true},
+		{"שָׁלוֹם", 7, "שָׁ", true},
+		{"كَتَبَ", 7, "كَ", true},
+		{"كَتَبَ", 8, "كَتَ", true},
+	}
+	for _, c := range bytes {
+		got, cut := truncateBytes(c.text, c.maxBytes)
+		if got != c.want || cut != c.cut || !utf8.ValidString(got) {
+			t.Errorf("truncateBytes(%q, %d) = %q, %v; want %q, %v", c.text, c.maxBytes, got, cut, c.want, c.cut)
+		}
+	}
+}
+
+// Test for right-to-left text being isolated inside replies, and left-to-right text left alone
+func TestIsolateDirection(t *testing.T) {
+	cases := []struct {
+		text string
+		want string
+	}{
+		{"Paris", "Paris"},
+		{"القاهرة", "\u2068القاهرة\u2069"},
+		{"ירושלים (Jerusalem)", "\u2068ירושלים (Jerusalem)\u2069"},
+		{"👍🏽 ok", "👍🏽 ok"},
+	}
+	for _, c := range cases {
+		if got := isolateDirection(c.text); got != c.want {
+			t.Errorf("isolateDirection(%q) = %q; want %q", c.text, got, c.want)
+		}
+	}
+}
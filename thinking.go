@@ -0,0 +1,74 @@
+//////////////////////////////////////////////////
+// Thinking Escalation Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for keeping askers informed while a slow answer is still being worked out
+import (
+	"context" // Permits wording and tracing of escalations
+	"time"    // Permits escalation timers
+)
+
+// Global struct holding the timers that escalate a slow answer's placeholder: to "still working", then to an offer to
+// cancel (a nil channel never fires, for stages that are off)
+type thinkingEscalation struct {
+	stillWorking <-chan time.Time
+	cancelOffer  <-chan time.Time
+	timers       []*time.Timer
+}
+
+// Global function for starting the escalation timers of an answer that has been worked on for elapsed already, each
+// stage firing once the answer has taken its configured time in all
+func startThinkingEscalation(elapsed time.Duration) *thinkingEscalation {
+	escalation := &thinkingEscalation{}
+	if !config.ThinkingEscalation {
+		return escalation
+	}
+	escalation.stillWorking = escalation.after(config.StillWorkingAfter, elapsed)
+	escalation.cancelOffer = escalation.after(config.CancelOfferAfter, elapsed)
+	return escalation
+}
+
+// Method for starting one stage's timer, leaving stages with no threshold off
+func (escalation *thinkingEscalation) after(threshold time.Duration, elapsed time.Duration) <-chan time.Time {
+	if threshold <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(threshold - elapsed)
+	escalation.timers = append(escalation.timers, timer)
+	return timer.C
+}
+
+// Method for stopping the timers once the answer is in
+func (escalation *thinkingEscalation) stop() {
+	for _, timer := range escalation.timers {
+		timer.Stop()
+	}
+}
+
+// Global function for moving a slow answer's placeholder on to an escalation stage's message, editing it in place (or
+// posting one when there is none), and returning where it now is so the answer replaces it
+func escalatePlaceholder(ctx context.Context, channel string, placeholderTS string, stage string) (string, string) {
+	metrics.inc("wolfy_thinking_escalations_total", "stage", stage)
+	traceStep(ctx, "still answering; escalating the placeholder to %s", stage)
+
+	text := messageText(scopedMessage(ctx, stage))
+	if placeholderTS != "" {
+		if posted, timestamp := replaceMessage(channel, placeholderTS, text); timestamp != "" {
+			channel, placeholderTS = posted, timestamp
+		}
+	} else {
+		posted, timestamp, err := postMessage(channel, text)
+		if err != nil {
+			return channel, ""
+		}
+		channel, placeholderTS = posted, timestamp
+	}
+	if request := inFlightFrom(ctx); request != nil && placeholderTS != "" {
+		request.notePlaceholder(channel, placeholderTS)
+	}
+	return channel, placeholderTS
+}
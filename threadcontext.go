@@ -0,0 +1,113 @@
+//////////////////////////////////////////////////
+// Thread Context Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for reading a thread's original question so follow-ups asked in it have context
+import (
+	"log"     // Permits console logging
+	"regexp"  // Permits recognition of elliptical follow-ups
+	"strconv" // Permits parsing of thread timestamps
+	"strings" // Permits joining of the resolved question
+	"time"    // Permits cache expiry and thread age
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constant holding how many replies are read after a thread's parent when looking for the bot's latest answer
+const threadContextReplyLimit = 20
+
+// Global pattern recognizing an elliptical follow-up ("and what about at sea level?", "how about in Celsius"), capturing
+// the fragment that qualifies the thread's original question
+var threadFollowUpPattern = regexp.MustCompile(`(?i)^\s*(?:and\s+)?(?:what|how)\s+about\s+(.+?)\s*\?*\s*$`)
+
+// Global struct holding what a thread was about: its parent question and the bot's latest answer in it, if any
+type threadParent struct {
+	key       string
+	question  string
+	answer    string
+	fetchedAt time.Time
+}
+
+// Global cache of fetched thread parents, including threads found to have none, evicting the least recently used
+var threadParents = newBoundedCache("thread_parents", func() int { return config.ThreadContextSize }, func(interface{}) time.Duration { return config.ThreadContextTTL }).evictingLeastRecentlyUsed()
+
+// Global function for checking whether a thread started within the configured age, judged by its root timestamp
+func threadRecentEnough(threadTS string, now time.Time) bool {
+	seconds, err := strconv.ParseFloat(threadTS, 64)
+	if err != nil {
+		return false
+	}
+	return now.Sub(time.Unix(int64(seconds), 0)) <= config.ThreadContextMaxAge
+}
+
+// Global function for forgetting the cached thread parents in a channel, returning how many were dropped
+func forgetChannelThreadParents(channel string) int {
+	return len(threadParents.removeMatching(func(key string, value interface{}) bool { return strings.HasPrefix(key, channel+":") }))
+}
+
+// Global function for reading a thread's parent question and the bot's latest reply in it from conversations.replies,
+// served from cache. Threads too old, or disabled by config, report false without an API call.
+func fetchThreadParent(channel string, threadTS string) (threadParent, bool) {
+	if config.ThreadContextMaxAge <= 0 || config.ThreadContextSize <= 0 || !threadRecentEnough(threadTS, time.Now()) {
+		return threadParent{}, false
+	}
+	key := threadKey(channel, threadTS)
+	if value, ok := threadParents.get(key); ok {
+		metrics.inc("wolfy_thread_context_total", "result", "cached")
+		parent := value.(threadParent)
+		return parent, parent.question != ""
+	}
+
+	messages, _, _, err := slackAPI().GetConversationReplies(&slack.GetConversationRepliesParameters{ChannelID: channel, Timestamp: threadTS, Limit: threadContextReplyLimit})
+	if err != nil {
+		metrics.inc("wolfy_thread_context_total", "result", "error")
+		log.Printf("SLACK ERROR: Unable to read the thread %s in %s.\nError Details: %v", threadTS, channel, err)
+		return threadParent{}, false
+	}
+
+	parent := threadParent{key: key, fetchedAt: time.Now()}
+	for _, message := range messages {
+		switch {
+		case message.Timestamp == threadTS && (currentBotUserID() == "" || message.User != currentBotUserID()):
+			parent.question = normalizeQueryText(stripCodeFormatting(stripTriggerPrefix(channel, stripBotMention(message.Text))))
+		case currentBotUserID() != "" && message.User == currentBotUserID():
+			parent.answer = message.Text
+		}
+	}
+	threadParents.put(key, parent)
+	metrics.inc("wolfy_thread_context_total", "result", map[bool]string{true: "fetched", false: "no_question"}[parent.question != ""])
+	return parent, parent.question != ""
+}
+
+// Global function for giving a reply in a thread the context of the thread's original question: seeding the asker's
+// session with it when they have none here, and folding an elliptical follow-up into it ("what about at sea level?"
+// under "boiling point of water" asks "boiling point of water at sea level")
+func attachThreadParent(event *slack.MessageEvent) {
+	if event.ThreadTimestamp == "" || event.ThreadTimestamp == event.Timestamp || event.SubType == "message_replied" {
+		return
+	}
+	_, known := lastInteractionIn(event.User, event.Channel)
+	followUp := threadFollowUpPattern.FindStringSubmatch(event.Msg.Text)
+	if known && followUp == nil {
+		return
+	}
+	parent, ok := fetchThreadParent(event.Channel, event.ThreadTimestamp)
+	if !ok {
+		return
+	}
+
+	if !known {
+		entry := interaction{Text: parent.question, EntityKey: "thread_context", Query: parent.question, Answer: parent.answer, Channel: event.Channel, At: time.Now()}
+		storeSession(event.User, entry)
+	}
+	if followUp != nil {
+		resolved := strings.TrimRight(parent.question, "?. ") + " " + followUp[1]
+		log.Printf("THREADS: Resolved the follow-up %q in %s to %q.", event.Msg.Text, threadKey(event.Channel, event.ThreadTimestamp), resolved)
+		metrics.inc("wolfy_thread_followups_resolved_total")
+		event.Msg.Text = resolved
+	}
+}
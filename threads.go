@@ -0,0 +1,235 @@
+//////////////////////////////////////////////////
+// Thread Participation Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for tracking threads the bot has answered in
+import (
+	"log"         // Permits console logging
+	"regexp"      // Permits recognition of question-like remainders after a trigger prefix
+	"sort"        // Permits trying longer trigger prefixes first
+	"strings"     // Permits mention detection and stripping
+	"sync/atomic" // Permits lock-free reads of the bot's own user ID
+	"time"        // Permits participation expiry
+	"unicode"     // Permits recognition of a mention with nothing but punctuation after it
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global holder of the bot's own user ID, learned at startup or when RTM connects and read by every handler goroutine
+var botUserID atomic.Value
+
+// Global function for reading the bot's own user ID ("" until it is learned)
+func currentBotUserID() string {
+	id, _ := botUserID.Load().(string)
+	return id
+}
+
+// Global function for recording the bot's own user ID
+func setBotUserID(id string) {
+	botUserID.Store(id)
+}
+
+// Global pattern recognizing a remainder that reads as a question or request ("what's...", "convert...", "...?"), so a
+// bare-word prefix in an ordinary sentence ("wolfy is great") isn't taken as a trigger
+var triggerQuestionPattern = regexp.MustCompile(`(?i)^(?:what|what's|whats|who|whom|whose|when|where|why|how|which|calculate|compute|convert|define|solve|integrate|differentiate|derive|simplify|factor|plot|graph|tell|show|give|find|list|remind|subscribe|unsubscribe|set|cancel|explain|translate|speak|help)\b|\?\s*$`)
+
+// Global struct holding what we know about a thread the bot has posted in
+type threadParticipation struct {
+	key        string
+	lastActive time.Time
+	lastReply  string
+}
+
+// Global tracker of the threads the bot has participated in, shared by the RTM loop and the message handlers; a
+// thread is forgotten once it has gone quiet for the tracking TTL, or is the least recently active beyond capacity
+var threads = newBoundedCache("threads", func() int { return config.ThreadTrackingSize }, func(interface{}) time.Duration { return config.ThreadTrackingTTL })
+
+// Global function for keying a thread by channel and root timestamp
+func threadKey(channel string, threadTS string) string {
+	return channel + ":" + threadTS
+}
+
+// Global function for recording that the bot posted in a thread
+func touchThread(key string, reply string) {
+	threads.put(key, threadParticipation{key: key, lastActive: time.Now(), lastReply: reply})
+}
+
+// Global function for looking up a tracked thread
+func lookupThread(key string) (threadParticipation, bool) {
+	value, ok := threads.get(key)
+	if !ok {
+		return threadParticipation{}, false
+	}
+	return value.(threadParticipation), true
+}
+
+// Global function for forgetting every tracked thread in a channel, returning how many were dropped
+func forgetChannelThreads(channel string) int {
+	return len(threads.removeMatching(func(key string, value interface{}) bool { return strings.HasPrefix(key, channel+":") }))
+}
+
+// Global function for resolving the bot's own user ID at startup, so self-join and own-message checks work before RTM connects
+func resolveBotUserID() {
+	identity, err := slackAPI().AuthTest()
+	if err != nil {
+		log.Printf("SLACK ERROR: Unable to resolve the bot's user ID (will retry on connect).\nError Details: %v", err)
+		return
+	}
+	setBotUserID(identity.UserID)
+}
+
+// Global function for checking whether a message was posted by this bot
+func isOwnMessage(event *slack.MessageEvent) bool {
+	return currentBotUserID() != "" && event.User == currentBotUserID()
+}
+
+// Global function for noting the threads our own messages land in (including "also send to channel" broadcasts)
+func noteOwnMessage(event *slack.MessageEvent) {
+	noteRecentOutput(event.Text)
+	if config.ThreadTrackingSize <= 0 || event.ThreadTimestamp == "" {
+		return
+	}
+	touchThread(threadKey(event.Channel, event.ThreadTimestamp), event.Text)
+}
+
+// Global function for finding the bot-participated thread a message replies in, if any
+func participatedThread(event *slack.MessageEvent) (threadParticipation, bool) {
+	// "message_replied" only notifies that the parent gained a reply and is not itself a question
+	if event.ThreadTimestamp == "" || event.SubType == "message_replied" {
+		return threadParticipation{}, false
+	}
+	return lookupThread(threadKey(event.Channel, event.ThreadTimestamp))
+}
+
+// Global function for checking whether a message is addressed to the bot: a DM, a mention, or a tracked thread follow-up
+func isAddressedToBot(event *slack.MessageEvent) bool {
+	if !config.RequireMention || isDirectMessage(event.Channel) {
+		return true
+	}
+	if currentBotUserID() != "" && strings.Contains(event.Text, "<@"+currentBotUserID()+">") {
+		return true
+	}
+	if _, ok := matchTriggerPrefix(event.Text); ok {
+		return true
+	}
+	// A bare prefix is a poke ("wolfy?") only with punctuation after it, so chat merely naming the bot is left alone
+	if text := strings.TrimSpace(event.Text); isBareTriggerPrefix(text) && strings.TrimRightFunc(text, unicode.IsPunct) != text {
+		return true
+	}
+	if _, ok := participatedThread(event); ok {
+		return true
+	}
+	// "save this" under one of our answers needs no mention either
+	if event.ThreadTimestamp != "" && saveThisPattern.MatchString(event.Text) {
+		_, ok := lookupAnswerMessage(event.Channel, event.ThreadTimestamp)
+		return ok
+	}
+	return false
+}
+
+// Global function for matching a channel message that starts with a trigger prefix ("wolfy: what's the boiling point of
+// lead"), case-insensitively, returning the remainder after it. A prefix ending in punctuation takes any remainder; a
+// bare word needs a comma or colon after it, or a question-like remainder.
+func matchTriggerPrefix(text string) (string, bool) {
+	prefixes := append([]string(nil), config.TriggerPrefixes...)
+	sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i]) > len(prefixes[j]) })
+
+	text = strings.TrimSpace(text)
+	for _, prefix := range prefixes {
+		if len(text) <= len(prefix) || !strings.EqualFold(text[:len(prefix)], prefix) {
+			continue
+		}
+		rest := text[len(prefix):]
+		if last := prefix[len(prefix)-1]; unicode.IsPunct(rune(last)) {
+			if rest = strings.TrimSpace(rest); rest != "" {
+				return rest, true
+			}
+			continue
+		}
+		if next := rest[0]; next == ',' || next == ':' {
+			if rest = strings.TrimSpace(rest[1:]); rest != "" {
+				return rest, true
+			}
+			continue
+		}
+		if !unicode.IsSpace(rune(rest[0])) {
+			// "wolfyish" merely starts with the prefix
+			continue
+		}
+		if rest = strings.TrimSpace(rest); triggerQuestionPattern.MatchString(rest) {
+			return rest, true
+		}
+	}
+	return "", false
+}
+
+// Global function for removing a trigger prefix from a channel message before classification, so it doesn't reach
+// the NLP input (DMs are left as written)
+func stripTriggerPrefix(channel string, text string) string {
+	if isDirectMessage(channel) {
+		return text
+	}
+	if rest, ok := matchTriggerPrefix(text); ok {
+		return rest
+	}
+	return text
+}
+
+// Global function for removing the bot's own mention from a message before classification
+func stripBotMention(text string) string {
+	if currentBotUserID() == "" {
+		return text
+	}
+	return strings.TrimSpace(strings.Replace(text, "<@"+currentBotUserID()+">", "", -1))
+}
+
+// Global function for trimming whitespace and punctuation from both ends of a message
+func trimSpaceAndPunct(text string) string {
+	return strings.TrimFunc(text, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsPunct(r) })
+}
+
+// Global function for checking whether a message is nothing but a trigger prefix ("wolfy:", "Wolfy?"), ignoring case
+// and punctuation
+func isBareTriggerPrefix(text string) bool {
+	core := trimSpaceAndPunct(text)
+	if core == "" {
+		return false
+	}
+	for _, prefix := range config.TriggerPrefixes {
+		if strings.EqualFold(core, trimSpaceAndPunct(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Global function for checking whether a message is nothing but the bot's mention ("@wolfy", "@wolfy ?") or a trigger
+// prefix ("wolfy:", "wolfy: ?"), leaving no question to classify
+func isEmptyMention(raw string, stripped string) bool {
+	if trimSpaceAndPunct(stripped) == "" {
+		if currentBotUserID() != "" && strings.Contains(raw, "<@"+currentBotUserID()+">") {
+			return true
+		}
+		if _, ok := matchTriggerPrefix(raw); ok {
+			return true
+		}
+	}
+	return isBareTriggerPrefix(stripped)
+}
+
+// Global function for attaching the thread's conversation context to the asker's session when they have none
+func attachThreadContext(event *slack.MessageEvent) {
+	thread, ok := participatedThread(event)
+	if !ok {
+		return
+	}
+	if _, ok := lastInteraction(event.User); ok {
+		return
+	}
+
+	sessions.users.put(event.User, interaction{EntityKey: "thread_context", Answer: thread.lastReply, At: time.Now()})
+}
@@ -0,0 +1,109 @@
+//////////////////////////////////////////////////
+// Thread Tracking Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing how messages are addressed to the bot by trigger prefix, and which address it with nothing
+// to ask
+import (
+	"testing" // Permits Go testing
+)
+
+// Test for a leading trigger prefix being removed from channel messages whatever its case, punctuation, or spacing,
+// while prefixes mid-sentence, words merely starting with one, and DMs are left as written
+func TestStripTriggerPrefix(t *testing.T) {
+	withConfig(t, func(config *Config) { config.TriggerPrefixes = []string{"wolfy", "wolfy:"} })
+	cases := []struct {
+		channel string
+		text    string
+		want    string
+	}{
+		{"CPREFIX", "wolfy: what is pi", "what is pi"},
+		{"CPREFIX", "wolfy, what is pi", "what is pi"},
+		{"CPREFIX", "wolfy:what is pi", "what is pi"},
+		{"CPREFIX", "WOLFY: what is pi", "what is pi"},
+		{"CPREFIX", "Wolfy, convert 5 km to miles", "convert 5 km to miles"},
+		{"CPREFIX", "  wolfy:   what is pi  ", "what is pi"},
+		{"CPREFIX", "wolfy what is pi", "what is pi"},
+		{"CPREFIX", "wolfy the height of Everest?", "the height of Everest?"},
+		{"CPREFIX", "wolfy is great", "wolfy is great"},
+		{"CPREFIX", "wolfyish: what is pi", "wolfyish: what is pi"},
+		{"CPREFIX", "ask wolfy: what is pi", "ask wolfy: what is pi"},
+		{"CPREFIX", "I asked wolfy, what is pi", "I asked wolfy, what is pi"},
+		{"CPREFIX", "wolfy:", "wolfy:"},
+		{"DPREFIX", "wolfy: what is pi", "wolfy: what is pi"},
+		{"DPREFIX", "Wolfy, what is pi", "Wolfy, what is pi"},
+	}
+	for _, c := range cases {
+		if got := stripTriggerPrefix(c.channel, c.text); got != c.want {
+			t.Errorf("stripTriggerPrefix(%s, %q) = %q; want %q", c.channel, c.text, got, c.want)
+		}
+	}
+}
+
+// Test for a trigger prefix addressing a channel message to the bot when mentions are required, a bare prefix only
+// with punctuation after it, and chat merely naming the bot not at all
+func TestTriggerPrefixAddressesBot(t *testing.T) {
+	withConfig(t, func(config *Config) {
+		config.RequireMention = true
+		config.TriggerPrefixes = []string{"wolfy", "wolfy:"}
+	})
+	cases := []struct {
+		text string
+		want bool
+	}{
+		{"wolfy: what is pi", true},
+		{"Wolfy, what is pi", true},
+		{"wolfy what is pi?", true},
+		{"wolfy?", true},
+		{"Wolfy!", true},
+		{"wolfy", false},
+		{"wolfy is great", false},
+		{"I love wolfy", false},
+		{"what is pi", false},
+	}
+	for _, c := range cases {
+		if got := isAddressedToBot(testMessage("CPREFIX", "UPREFIX", "8500.000001", c.text)); got != c.want {
+			t.Errorf("isAddressedToBot(%q) = %v; want %v", c.text, got, c.want)
+		}
+	}
+}
+
+// Test for a message counting as an empty mention when nothing is left of it once its mention, trigger prefix,
+// punctuation, whitespace, and invisible characters are cleaned away, and not when a question is left
+func TestIsEmptyMention(t *testing.T) {
+	withConfig(t, func(config *Config) { config.TriggerPrefixes = []string{"wolfy", "wolfy:"} })
+	cases := []struct {
+		channel string
+		raw     string
+		want    bool
+	}{
+		{"CEMPTY", "<@UBOT>", true},
+		{"CEMPTY", "  <@UBOT>  ", true},
+		{"CEMPTY", "<@UBOT> ?", true},
+		{"CEMPTY", "<@UBOT>!!! ", true},
+		{"CEMPTY", "<@UBOT> \u200f", true},
+		{"CEMPTY", "<@UBOT> <@UBOT>", true},
+		{"CEMPTY", "<@UBOT> wolfy:", true},
+		{"CEMPTY", "wolfy:", true},
+		{"CEMPTY", "Wolfy?", true},
+		{"CEMPTY", "wolfy: ?", true},
+		{"CEMPTY", "wolfy, !", true},
+		{"DEMPTY", "wolfy", true},
+		{"DEMPTY", "wolfy: ?", true},
+		{"CEMPTY", "<@UBOT> what is pi", false},
+		{"CEMPTY", "wolfy: what is pi", false},
+		{"CEMPTY", "<@UALICE>", false},
+		{"CEMPTY", "?", false},
+		{"DEMPTY", "what is pi", false},
+	}
+	for _, c := range cases {
+		stripped := normalizeQueryText(stripCodeFormatting(stripTriggerPrefix(c.channel, stripBotMention(c.raw))))
+		if got := isEmptyMention(c.raw, stripped); got != c.want {
+			t.Errorf("isEmptyMention(%q) in %s = %v; want %v", c.raw, c.channel, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+//////////////////////////////////////////////////
+// Time Series Plots Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for answering questions about data over time with Wolfram's plot and a short summary
+import (
+	"context"  // Permits bounding of the plot download
+	"fmt"      // Permits formatting of the summary
+	"log"      // Permits console logging
+	"net/http" // Permits downloading of the plot image
+	"net/url"  // Permits building of full results parameters
+	"regexp"   // Permits recognition of time-series questions, pods, and statistics
+	"strings"  // Permits string normalization
+
+	wolfram "github.com/Krognol/go-wolfram" // External Wolfram|Alpha API
+	slack "github.com/nlopes/slack"         // External Slack API
+)
+
+// Global patterns recognizing questions about a quantity over a span of time ("stock price of X over the last year",
+// "population of Japan since 1950"), the pods whose image plots it, and the statistic rows Wolfram lists beside them
+var (
+	timeSeriesQueryPattern = regexp.MustCompile(`(?i)\b(?:(?:over|during|for|in)\s+the\s+(?:last|past)\s+(?:\d+\s+|few\s+|several\s+)?(?:days?|weeks?|months?|quarters?|years?|decades?)|since\s+(?:\d{4}|last\s+\w+)|from\s+\d{4}\s+(?:to|until)\s+\d{4}|(?:history|trend|chart|graph|plot)\s+of|(?:price|temperature|population|rate)\s+(?:history|trend|chart|graph))\b`)
+	timeSeriesPodPattern   = regexp.MustCompile(`(?i)history|plot|chart|time\s*series|trend`)
+	timeSeriesStatPattern  = regexp.MustCompile(`(?i)^\s*(minimum|maximum|min|max|low|high|latest|last|current|average|mean)\s*\|\s*(.+?)\s*$`)
+)
+
+// Global labels the statistic rows are summarized under, in the order they are listed
+var timeSeriesStatLabels = []struct {
+	label string
+	names []string
+}{
+	{"low", []string{"minimum", "min", "low"}},
+	{"high", []string{"maximum", "max", "high"}},
+	{"average", []string{"average", "mean"}},
+	{"latest", []string{"latest", "last", "current"}},
+}
+
+// Global struct holding a time-series answer: the plot's image URL (empty when Wolfram drew none) and the summary
+type timeSeriesAnswer struct {
+	title   string
+	plotURL string
+	summary string
+}
+
+// Registering the time-series plots flag, off until rolled out
+func init() {
+	registerFeatureFlag(featureFlag{name: "time_series_plots", description: "Answer questions about data over time (\"stock price of X over the last year\") with Wolfram's plot and a low/high/latest summary."})
+}
+
+// Global function for checking whether a question asks about a quantity over a span of time
+func isTimeSeriesQuery(query string) bool {
+	return timeSeriesQueryPattern.MatchString(query)
+}
+
+// Global function for fetching a time-series question's plot and statistics from the full results API, reporting false
+// when Wolfram returned neither
+func fetchTimeSeriesAnswer(ctx context.Context, client *wolfram.Client, query string, units wolfram.Unit) (timeSeriesAnswer, bool) {
+	result, err := fetchFullResult(ctx, client, query, url.Values{"format": {"image,plaintext"}, "units": {unitsName(units)}})
+	if err != nil {
+		log.Printf("WOLFRAM ERROR: Unable to fetch the time series for %q.\nError Details: %v", query, err)
+		return timeSeriesAnswer{}, false
+	}
+
+	var answer timeSeriesAnswer
+	stats := map[string]string{}
+	for _, pod := range result.Pods {
+		plotted := timeSeriesPodPattern.MatchString(pod.ID) || timeSeriesPodPattern.MatchString(pod.Title)
+		for _, subPod := range pod.SubPods {
+			if plotted && answer.plotURL == "" && subPod.Img.Src != "" {
+				answer.title, answer.plotURL = pod.Title, subPod.Img.Src
+			}
+			for _, line := range strings.Split(subPod.Plaintext, "\n") {
+				if match := timeSeriesStatPattern.FindStringSubmatch(line); match != nil {
+					if name := strings.ToLower(match[1]); stats[name] == "" {
+						stats[name] = match[2]
+					}
+				}
+			}
+		}
+	}
+
+	var parts []string
+	for _, stat := range timeSeriesStatLabels {
+		for _, name := range stat.names {
+			if value := stats[name]; value != "" {
+				parts = append(parts, stat.label+" "+value)
+				break
+			}
+		}
+	}
+	if len(parts) > 0 {
+		answer.summary = strings.Join(parts, " · ")
+	}
+	if answer.plotURL == "" && answer.summary == "" {
+		return timeSeriesAnswer{}, false
+	}
+	return answer, true
+}
+
+// Global function for uploading a plot image to a channel, titled with the question, reporting whether it was posted
+func uploadTimeSeriesPlot(ctx context.Context, channelID string, query string, plotURL string) bool {
+	req, err := http.NewRequest("GET", plotURL, nil)
+	if err != nil {
+		return false
+	}
+	res, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		log.Printf("WOLFRAM ERROR: Unable to download the plot for %q.\nError Details: %v", query, err)
+		return false
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		log.Printf("WOLFRAM ERROR: Unable to download the plot for %q: status %s.", query, res.Status)
+		return false
+	}
+
+	_, err = slackAPI().UploadFile(slack.FileUploadParameters{
+		Reader:   res.Body,
+		Filename: "plot.gif",
+		Title:    query,
+		Channels: []string{channelID},
+	})
+	if err != nil {
+		log.Printf("SLACK ERROR: Unable to upload the plot for %q.\nError Details: %v", query, err)
+		return false
+	}
+	return true
+}
+
+// Global function for answering a time-series question with its plot uploaded to the asker's destination and a summary
+// as the reply, falling back to the summary alone when the plot can't be had. Reports false (answer normally) when the
+// flag is off, the question isn't about data over time, or Wolfram has no series for it.
+func answerTimeSeries(ctx context.Context, event *slack.MessageEvent, query string, units wolfram.Unit) (handlerResponse, bool) {
+	if !flagEnabled(ctx, "time_series_plots") || !isTimeSeriesQuery(query) {
+		return handlerResponse{}, false
+	}
+	answer, ok := fetchTimeSeriesAnswer(ctx, wolframClientFor(event), query, units)
+	if !ok {
+		traceStep(ctx, "no time series found for %q", query)
+		return handlerResponse{}, false
+	}
+
+	plotted := answer.plotURL != "" && uploadTimeSeriesPlot(ctx, answerDestination(event), query, answer.plotURL)
+	metrics.inc("wolfy_time_series_answers_total", "plot", map[bool]string{true: "uploaded", false: "missing"}[plotted])
+	traceStep(ctx, "time series for %q (plot uploaded: %t, summary %q)", query, plotted, answer.summary)
+
+	text := answer.summary
+	switch {
+	case plotted && text == "":
+		text = fmt.Sprintf("Here's the %s. :chart_with_upwards_trend:", strings.ToLower(answer.title))
+	case plotted:
+		text = fmt.Sprintf(":chart_with_upwards_trend: %s", text)
+	case text == "":
+		return handlerResponse{}, false
+	}
+	return handlerResponse{
+		Text:  text,
+		Query: query,
+		Details: []slack.AttachmentField{
+			{Title: "Interpreted as", Value: query, Short: true},
+			{Title: "Source", Value: "Wolfram|Alpha", Short: true},
+		},
+	}, true
+}
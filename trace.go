@@ -0,0 +1,101 @@
+//////////////////////////////////////////////////
+// Routing Trace Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for reporting the decision path behind an answer to opted-in maintainers
+import (
+	"context" // Permits carrying the trace through the pipeline
+	"fmt"     // Permits formatting of trace steps
+	"log"     // Permits console logging
+	"strings" // Permits joining of trace steps
+	"sync"    // Permits concurrency-safe trace and toggle access
+	"time"    // Permits step timing
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global struct collecting the routing decisions made while answering one message
+type routeTrace struct {
+	mu      sync.Mutex
+	started time.Time
+	steps   []string
+}
+
+// Global context key type for the routing trace
+type routeTraceKey struct{}
+
+// Global set of users who receive routing traces, toggled by admins with "!trace"
+var (
+	tracedUsersMu sync.Mutex
+	tracedUsers   = map[string]bool{}
+)
+
+// Global function for checking whether a user has tracing turned on
+func isTraced(user string) bool {
+	tracedUsersMu.Lock()
+	defer tracedUsersMu.Unlock()
+	return tracedUsers[user]
+}
+
+// Global function for attaching a routing trace to a context when the asker has tracing turned on
+func withRouteTrace(ctx context.Context, event *slack.MessageEvent) context.Context {
+	if !isTraced(event.User) {
+		return ctx
+	}
+	return context.WithValue(ctx, routeTraceKey{}, &routeTrace{started: time.Now()})
+}
+
+// Global function for recording a routing decision (a no-op unless the message is being traced)
+func traceStep(ctx context.Context, format string, args ...interface{}) {
+	trace, ok := ctx.Value(routeTraceKey{}).(*routeTrace)
+	if !ok {
+		return
+	}
+	trace.mu.Lock()
+	trace.steps = append(trace.steps, fmt.Sprintf("+%s ", time.Since(trace.started).Round(time.Millisecond))+fmt.Sprintf(format, args...))
+	trace.mu.Unlock()
+}
+
+// Global function for DMing the collected trace to the asker once the message has been handled
+func sendRouteTrace(ctx context.Context, event *slack.MessageEvent) {
+	trace, ok := ctx.Value(routeTraceKey{}).(*routeTrace)
+	if !ok {
+		return
+	}
+	trace.mu.Lock()
+	steps := strings.Join(trace.steps, "\n")
+	trace.mu.Unlock()
+	postText(event.User, fmt.Sprintf("*Routing trace for* \"%s\":\n```%s```", event.Msg.Text, steps))
+}
+
+// Admin command turning routing traces on or off for the admin or a mentioned user
+func runAdminTrace(event *slack.MessageEvent, args []string) string {
+	target := event.User
+	if len(args) > 1 {
+		match := userReferencePattern.FindStringSubmatch(args[1])
+		if match == nil {
+			return "Usage: `!trace on|off [@user]`"
+		}
+		target = match[1]
+	}
+
+	tracedUsersMu.Lock()
+	defer tracedUsersMu.Unlock()
+	if len(args) == 0 {
+		return fmt.Sprintf("Routing traces are %s for <@%s>.", onOff(tracedUsers[target]), target)
+	}
+	switch strings.ToLower(args[0]) {
+	case "on":
+		tracedUsers[target] = true
+	case "off":
+		delete(tracedUsers, target)
+	default:
+		return "Usage: `!trace on|off [@user]`"
+	}
+	log.Printf("ADMIN: %s turned routing traces %s for %s.", event.User, strings.ToLower(args[0]), target)
+	return fmt.Sprintf("Routing traces are now %s for <@%s>.", onOff(tracedUsers[target]), target)
+}
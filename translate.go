@@ -0,0 +1,211 @@
+//////////////////////////////////////////////////
+// Translation Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for translating non-English questions for Wolfram and the answers back
+import (
+	"bytes"         // Permits building of request bodies
+	"context"       // Permits deadlines on translation calls
+	"encoding/json" // Permits (de)serialization of translation API payloads
+	"fmt"           // Permits formatting of placeholders and errors
+	"log"           // Permits console logging
+	"net/http"      // Permits translation API requests
+	"regexp"        // Permits detection of protected spans
+	"strings"       // Permits string manipulation
+	"unicode"       // Permits recognition of capitalized words
+	"unicode/utf8"  // Permits reading of a span's first letter
+)
+
+// Global interface for a translation backend (LibreTranslate-compatible by default)
+type translator interface {
+	detect(ctx context.Context, text string) (string, error)
+	translate(ctx context.Context, text string, source string, target string) (string, error)
+}
+
+// Global struct holding a LibreTranslate-compatible HTTP backend
+type libreTranslator struct {
+	baseURL string
+	apiKey  string
+}
+
+// Global patterns recognizing the spans shielded from translation (Slack mentions, channel links, and links; numbers with
+// optional units; and runs of capitalized words, the proper nouns), and the text before a span that starts a sentence
+var (
+	protectedSpanPattern = regexp.MustCompile(`<[@#!][^<>]*>|<(?:https?|mailto):[^<>]*>|-?\d[\d.,]*(?:\s*(?:%|°\s?[CFK]?|[a-zA-Z]{1,4}\b(?:/[a-zA-Z]{1,4}\b)?))?|\p{Lu}[\p{L}\p{M}'’-]+(?:[ \t]+\p{Lu}[\p{L}\p{M}'’-]+)*`)
+	sentenceStartPattern = regexp.MustCompile(`(?:^|[.!?\n]\s|[¿¡])\s*$`)
+)
+
+// Global set of languages capitalizing every noun, where a capital letter doesn't mark a proper noun
+var capitalizedNounLanguages = map[string]bool{"de": true, "lb": true}
+
+// Global function for returning the configured translation backend, or nil when translation is off or switched off
+func activeTranslator() translator {
+	if config.TranslationURL == "" || !externalAPIsAllowed() {
+		return nil
+	}
+	return &libreTranslator{baseURL: strings.TrimRight(config.TranslationURL, "/"), apiKey: config.TranslationAPIKey}
+}
+
+// Method for POSTing a JSON payload to the backend and decoding its JSON reply
+func (t *libreTranslator) call(ctx context.Context, path string, payload map[string]string, target interface{}) error {
+	if t.apiKey != "" {
+		payload["api_key"] = t.apiKey
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	recordAPICall(ctx, "translation")
+	req, err := http.NewRequest("POST", t.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("translation request to %s failed with status %s", path, res.Status)
+	}
+	return json.NewDecoder(res.Body).Decode(target)
+}
+
+// Method for detecting the most likely language of a text
+func (t *libreTranslator) detect(ctx context.Context, text string) (string, error) {
+	var detections []struct {
+		Language   string  `json:"language"`
+		Confidence float64 `json:"confidence"`
+	}
+	if err := t.call(ctx, "/detect", map[string]string{"q": text}, &detections); err != nil {
+		return "", err
+	}
+	if len(detections) == 0 {
+		return "", fmt.Errorf("no language detected")
+	}
+	return detections[0].Language, nil
+}
+
+// Method for translating a text between two languages
+func (t *libreTranslator) translate(ctx context.Context, text string, source string, target string) (string, error) {
+	var translation struct {
+		TranslatedText string `json:"translatedText"`
+	}
+	payload := map[string]string{"q": text, "source": source, "target": target, "format": "text"}
+	if err := t.call(ctx, "/translate", payload, &translation); err != nil {
+		return "", err
+	}
+	return translation.TranslatedText, nil
+}
+
+// Global function for replacing the spans translation must leave alone with numbered placeholders, returning them in
+// order. A sentence's first word is capitalized whatever it is, so only the capitalized words after it are held back,
+// and none are in languages capitalizing every noun.
+func shieldProtectedSpans(text string, source string) (string, []string) {
+	var (
+		shielded strings.Builder
+		spans    []string
+	)
+	last := 0
+	for _, span := range protectedSpanPattern.FindAllStringIndex(text, -1) {
+		start, end := span[0], span[1]
+		if first, _ := utf8.DecodeRuneInString(text[start:]); unicode.IsUpper(first) {
+			if capitalizedNounLanguages[source] {
+				continue
+			}
+			if sentenceStartPattern.MatchString(text[:start]) {
+				next := strings.IndexFunc(text[start:end], unicode.IsSpace)
+				if next < 0 {
+					continue
+				}
+				start = end - len(strings.TrimLeftFunc(text[start+next:end], unicode.IsSpace))
+			}
+		}
+		shielded.WriteString(text[last:start])
+		fmt.Fprintf(&shielded, "[%d]", len(spans))
+		spans = append(spans, text[start:end])
+		last = end
+	}
+	shielded.WriteString(text[last:])
+	return shielded.String(), spans
+}
+
+// Global function for translating text while keeping Slack entities, numbers with their units, and proper nouns
+// verbatim via placeholders
+func translatePreserving(ctx context.Context, backend translator, text string, source string, target string) (string, error) {
+	shielded, spans := shieldProtectedSpans(text, source)
+
+	translated, err := backend.translate(ctx, shielded, source, target)
+	if err != nil {
+		return "", err
+	}
+	for i, span := range spans {
+		placeholder := fmt.Sprintf("[%d]", i)
+		if !strings.Contains(translated, placeholder) {
+			return "", fmt.Errorf("translation lost protected span %q", span)
+		}
+		translated = strings.Replace(translated, placeholder, span, 1)
+	}
+	return translated, nil
+}
+
+// Global function for translating a query to English, reporting the language to answer in: the asker's chosen one, else
+// the question's own ("" for English)
+func translateQueryToEnglish(ctx context.Context, query string) (string, string) {
+	backend := activeTranslator()
+	if backend == nil {
+		return query, ""
+	}
+	answerLanguage := func(detected string) string {
+		if preferred := preferredLanguage(ctx); preferred != "" {
+			detected = preferred
+		}
+		if detected == "en" {
+			return ""
+		}
+		return detected
+	}
+
+	language, err := backend.detect(ctx, query)
+	if err != nil {
+		log.Printf("TRANSLATION ERROR: Unable to detect query language.\nError Details: %v", err)
+		metrics.inc("wolfy_translation_failures_total", "stage", "detect")
+		return query, answerLanguage("")
+	}
+	if language == "" || language == "en" {
+		return query, answerLanguage("")
+	}
+
+	english, err := translatePreserving(ctx, backend, query, language, "en")
+	if err != nil {
+		// Falling back to querying in the original language
+		log.Printf("TRANSLATION ERROR: Unable to translate query from %s.\nError Details: %v", language, err)
+		metrics.inc("wolfy_translation_failures_total", "stage", "query")
+		return query, ""
+	}
+	metrics.inc("wolfy_translations_total", "language", language)
+	traceStep(ctx, "translated query from %s: %q", language, english)
+	return english, answerLanguage(language)
+}
+
+// Global function for translating an answer back into the asker's language, marking it as machine-translated
+func translateAnswerFromEnglish(ctx context.Context, answer string, language string) string {
+	backend := activeTranslator()
+	if backend == nil || language == "" {
+		return answer
+	}
+
+	translated, err := translatePreserving(ctx, backend, answer, "en", language)
+	if err != nil {
+		log.Printf("TRANSLATION ERROR: Unable to translate answer to %s.\nError Details: %v", language, err)
+		metrics.inc("wolfy_translation_failures_total", "stage", "answer")
+		return answer
+	}
+	return translated + " _(machine-translated)_"
+}
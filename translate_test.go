@@ -0,0 +1,103 @@
+//////////////////////////////////////////////////
+// Translation Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing which spans of a question or answer are kept verbatim through translation
+import (
+	"context" // Permits the translation's request context
+	"reflect" // Permits comparison of shielded spans
+	"regexp"  // Permits recognition of placeholders
+	"strings" // Permits mangling of translated words
+	"testing" // Permits Go testing
+)
+
+// Global struct holding a stand-in translation backend that spells every word backwards, leaving placeholders alone,
+// so any span it wasn't shielded from comes out mangled
+type reversingTranslator struct{}
+
+// Global pattern recognizing a placeholder or a word the reversing translator mangles
+var reversibleWordPattern = regexp.MustCompile(`\[\d+\]|[\p{L}\p{M}'’-]+`)
+
+// Method for detecting nothing, the tests naming their languages
+func (reversingTranslator) detect(ctx context.Context, text string) (string, error) {
+	return "", nil
+}
+
+// Method for "translating" by spelling each word backwards
+func (reversingTranslator) translate(ctx context.Context, text string, source string, target string) (string, error) {
+	return reversibleWordPattern.ReplaceAllStringFunc(text, func(word string) string {
+		if strings.HasPrefix(word, "[") {
+			return word
+		}
+		runes := []rune(word)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		return string(runes)
+	}), nil
+}
+
+// Test for Slack entities, numbers with units, and capitalized words past a sentence's start being shielded, while
+// a sentence's first word and, in languages capitalizing every noun, other capitalized words are translated
+func TestShieldProtectedSpans(t *testing.T) {
+	cases := []struct {
+		text     string
+		source   string
+		shielded string
+		spans    []string
+	}{
+		{"How tall is the Eiffel Tower in 2024?", "en", "How tall is the [0] in [1]?", []string{"Eiffel Tower", "2024"}},
+		{"Paris is the capital of France.", "en", "Paris is the capital of [0].", []string{"France"}},
+		{"The Eiffel Tower is 330 m tall.", "en", "The [0] is [1] tall.", []string{"Eiffel Tower", "330 m"}},
+		{"It is 5 km away. Then Madrid.", "en", "It is [0] away. Then [1].", []string{"5 km", "Madrid"}},
+		{"Thanks <@U123ABC>, see <#C0456|general> and <https://example.com|Example>", "en", "Thanks [0], see [1] and [2]", []string{"<@U123ABC>", "<#C0456|general>", "<https://example.com|Example>"}},
+		{"¿Cuál es la altura de la Torre Eiffel?", "es", "¿Cuál es la altura de la [0]?", []string{"Torre Eiffel"}},
+		{"Quelle est la population de Saint-Étienne ?", "fr", "Quelle est la population de [0] ?", []string{"Saint-Étienne"}},
+		{"Wie viele Einwohner hat Berlin?", "de", "Wie viele Einwohner hat Berlin?", nil},
+		{"Wie weit ist <@U1> von 12 km?", "de", "Wie weit ist [0] von [1]?", []string{"<@U1>", "12 km"}},
+		{"what is the boiling point of water", "en", "what is the boiling point of water", nil},
+	}
+	for _, c := range cases {
+		shielded, spans := shieldProtectedSpans(c.text, c.source)
+		if shielded != c.shielded || !reflect.DeepEqual(spans, c.spans) {
+			t.Errorf("shieldProtectedSpans(%q, %s) = %q, %q; want %q, %q", c.text, c.source, shielded, spans, c.shielded, c.spans)
+		}
+	}
+}
+
+// Test for proper nouns, Slack entities, and quantities surviving a translation out of English and back, verbatim on
+// the way out and the text whole on the way back
+func TestTranslatePreservingRoundTrip(t *testing.T) {
+	cases := []struct {
+		text  string
+		kept  []string
+		other string
+	}{
+		{"The Eiffel Tower in Paris is 330 m tall.", []string{"Eiffel Tower", "Paris", "330 m"}, "fr"},
+		{"Ask <@U123ABC> about New York City in <#C0456|trips>.", []string{"<@U123ABC>", "New York City", "<#C0456|trips>"}, "es"},
+		{"Mount Everest is 8848 m high, on the border of Nepal and China.", []string{"Everest", "8848 m", "Nepal", "China"}, "ja"},
+	}
+	backend := reversingTranslator{}
+	for _, c := range cases {
+		translated, err := translatePreserving(context.Background(), backend, c.text, "en", c.other)
+		if err != nil {
+			t.Fatalf("translatePreserving(%q) to %s: %v", c.text, c.other, err)
+		}
+		for _, kept := range c.kept {
+			if !strings.Contains(translated, kept) {
+				t.Errorf("translatePreserving(%q) to %s = %q; want %q kept verbatim", c.text, c.other, translated, kept)
+			}
+		}
+		back, err := translatePreserving(context.Background(), backend, translated, c.other, "en")
+		if err != nil {
+			t.Fatalf("translatePreserving(%q) back to en: %v", translated, err)
+		}
+		if back != c.text {
+			t.Errorf("round trip of %q through %s = %q; want it unchanged", c.text, c.other, back)
+		}
+	}
+}
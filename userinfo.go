@@ -0,0 +1,65 @@
+//////////////////////////////////////////////////
+// User Info Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for cached Slack user profile lookups
+import (
+	"log"  // Permits console logging
+	"time" // Permits cache expiry and timezone handling
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global users.info cache shared by timezone, locale, and name lookups
+var userInfo = newBoundedCache("users", func() int { return config.UserCacheSize }, func(interface{}) time.Duration { return config.UserCacheTTL }).evictingLeastRecentlyUsed()
+
+// Global function for fetching a user's Slack profile (including locale), served from cache when fresh
+func lookupUser(userID string) (*slack.User, error) {
+	if cached, ok := userInfo.get(userID); ok {
+		return cached.(*slack.User), nil
+	}
+
+	user, err := slackAPI().GetUserInfo(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	userInfo.put(userID, user)
+	return user, nil
+}
+
+// Global function for resolving the name to address a user by: their display name, else their real name, else nothing
+func userDisplayName(userID string) string {
+	if userID == "" {
+		return ""
+	}
+	user, err := lookupUser(userID)
+	if err != nil {
+		log.Printf("USER INFO ERROR: Unable to look up %s.\nError Details: %v", userID, err)
+		return ""
+	}
+	if user.Profile.DisplayName != "" {
+		return user.Profile.DisplayName
+	}
+	return user.RealName
+}
+
+// Global function for resolving a user's timezone, falling back to the configured default
+func userLocation(userID string) *time.Location {
+	if user, err := lookupUser(userID); err == nil && user.TZ != "" {
+		if location, err := time.LoadLocation(user.TZ); err == nil {
+			return location
+		}
+	} else if err != nil {
+		log.Printf("USER INFO ERROR: Unable to look up %s.\nError Details: %v", userID, err)
+	}
+
+	if location, err := time.LoadLocation(config.DefaultTimezone); err == nil {
+		return location
+	}
+	return time.UTC
+}
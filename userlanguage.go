@@ -0,0 +1,97 @@
+//////////////////////////////////////////////////
+// User Language Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for answering each user in their chosen language, through that language's own Wit.ai app
+import (
+	"context" // Permits reading of the asker from a request's context
+	"sort"    // Permits stable ordering of supported languages
+	"strings" // Permits string normalization
+	"sync"    // Permits concurrency-safe caching of per-language clients
+
+	wit "github.com/christianrondeau/go-wit" // External Wit.ai API
+)
+
+// Global map of the languages a user may choose, by code, with the name they are confirmed in
+var languageNames = map[string]string{
+	"en": "English", "fr": "French", "es": "Spanish", "de": "German", "it": "Italian", "pt": "Portuguese",
+	"nl": "Dutch", "ru": "Russian", "ja": "Japanese", "zh": "Chinese", "ko": "Korean", "ar": "Arabic",
+	"hi": "Hindi", "tr": "Turkish", "pl": "Polish", "sv": "Swedish",
+}
+
+// Global map of other names a language may be asked for by, including its own name for itself
+var languageAliases = map[string]string{
+	"français": "fr", "francais": "fr", "español": "es", "espanol": "es", "castellano": "es", "deutsch": "de",
+	"italiano": "it", "português": "pt", "portugues": "pt", "nederlands": "nl", "русский": "ru", "日本語": "ja",
+	"中文": "zh", "mandarin": "zh", "한국어": "ko", "العربية": "ar", "हिन्दी": "hi", "türkçe": "tr", "polski": "pl",
+	"svenska": "sv",
+}
+
+// Global Wit.ai clients for the languages with their own app, by token
+var languageWitClients sync.Map
+
+// Global function for resolving a language name or code ("French", "français", "fr") to its code
+func resolveLanguageName(name string) (string, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if _, ok := languageNames[name]; ok {
+		return name, true
+	}
+	if code, ok := languageAliases[name]; ok {
+		return code, true
+	}
+	for code, known := range languageNames {
+		if strings.EqualFold(known, name) {
+			return code, true
+		}
+	}
+	return "", false
+}
+
+// Global function for checking whether the bot can answer in a language: English always, others once questions can be
+// translated or the language has its own Wit.ai app
+func languageSupported(code string) bool {
+	if _, known := languageNames[code]; !known {
+		return false
+	}
+	_, ownApp := config.WitLanguageTokens[code]
+	return code == "en" || config.TranslationURL != "" || ownApp
+}
+
+// Global function for listing the names of the languages the bot can answer in
+func supportedLanguageNames() []string {
+	var names []string
+	for code, name := range languageNames {
+		if languageSupported(code) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Global function for returning the language the asker carried by a context chose ("" when they haven't)
+func preferredLanguage(ctx context.Context) string {
+	scope, _ := ctx.Value(flagScopeKey{}).(flagScope)
+	if scope.user == "" || store == nil {
+		return ""
+	}
+	return loadPreferences(scope.user).Language
+}
+
+// Global function for returning the Wit.ai client for the asker's chosen language: that language's own app when one
+// is configured, else the default app
+func witClientFor(ctx context.Context) *wit.Client {
+	token, ok := config.WitLanguageTokens[preferredLanguage(ctx)]
+	if !ok || token == "" {
+		return witAPI()
+	}
+	if client, ok := languageWitClients.Load(token); ok {
+		return client.(*wit.Client)
+	}
+	client, _ := languageWitClients.LoadOrStore(token, wit.NewClient(token))
+	return client.(*wit.Client)
+}
@@ -0,0 +1,167 @@
+//////////////////////////////////////////////////
+// Answer Verbosity Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for fitting how much an answer says to who is asking and where
+import (
+	"context" // Permits tracing of the chosen verbosity
+	"fmt"     // Permits formatting of the trimmed lines note
+	"log"     // Permits console logging
+	"net/url" // Permits the full results parameters of detailed answers
+	"regexp"  // Permits recognition of context notes
+	"strings" // Permits splitting of answers into lines
+
+	wolfram "github.com/Krognol/go-wolfram" // External Wolfram|Alpha API
+	slack "github.com/nlopes/slack"         // External Slack API
+)
+
+// Global constants holding how many lines a terse answer keeps, how many extra pods a detailed answer shows, and the
+// store bucket holding channel defaults set with "!channel style"
+const (
+	terseAnswerLines    = 3
+	detailedPodLimit    = 4
+	channelStylesBucket = "channel_styles"
+)
+
+// Global answer styles, from least said to most
+var answerStyles = []string{"terse", "normal", "detailed"}
+
+// Global pattern recognizing the italic "_(...)_" context notes appended to answers (as of, delays, rate notes, ...)
+var contextNotePattern = regexp.MustCompile(`[ \t]*_\([^\n]*?\)_`)
+
+// Global pods a detailed answer never repeats: the interpretation, shown as "Interpreted as" already
+var detailedSkippedPods = map[string]bool{"Input": true, "InputInterpretation": true}
+
+// Global function for checking whether a value names an answer style
+func isAnswerStyle(value string) bool {
+	for _, style := range answerStyles {
+		if value == style {
+			return true
+		}
+	}
+	return false
+}
+
+// Global function for looking up the answer style a channel's admins set for it with "!channel style"
+func channelAnswerStyle(channel string) string {
+	var style string
+	if store == nil || channel == "" || !store.get(channelStylesBucket, channel, &style) {
+		return ""
+	}
+	return style
+}
+
+// Global function for choosing how much detail an answer gets and why: the asker's "set style" choice, else the
+// style set for the channel it is posted in, else the default for where it is posted (a channel, or a DM)
+func answerVerbosity(user string, destination string) (string, string) {
+	if detail := loadPreferences(user).Detail; detail != "" {
+		return detail, "your preference"
+	}
+	if destination == user || isDirectMessage(destination) {
+		return config.DMVerbosity, "the DM default"
+	}
+	if style := channelAnswerStyle(destination); style != "" {
+		return style, "this channel's default"
+	}
+	return config.ChannelVerbosity, "the channel default"
+}
+
+// Global function for fitting an answer to its style: terse answers keep their first few lines without the
+// supporting detail or context notes, normal ones are left as they are, and detailed ones spell their detail out in
+// the text when attachments are off
+func applyVerbosity(ctx context.Context, user string, destination string, reply string, details []slack.AttachmentField) (string, []slack.AttachmentField) {
+	verbosity, source := answerVerbosity(user, destination)
+	switch verbosity {
+	case "terse":
+		traceStep(ctx, "terse answer (from %s)", source)
+		return terseAnswer(reply), nil
+	case "detailed":
+		traceStep(ctx, "detailed answer (from %s)", source)
+		if !config.AnswerAttachments {
+			for _, field := range details {
+				reply += fmt.Sprintf("\n_%s: %s_", field.Title, field.Value)
+			}
+		}
+	}
+	return reply, details
+}
+
+// Global function for trimming an answer to its first few lines with its context notes taken out, keeping the answer
+// as it was when nothing but notes would be left (its text is also the attachment fallback, which is never empty)
+func terseAnswer(reply string) string {
+	stripped := strings.TrimSpace(contextNotePattern.ReplaceAllString(reply, ""))
+	if stripped == "" {
+		stripped = reply
+	}
+	var lines []string
+	for _, line := range strings.Split(stripped, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) <= terseAnswerLines {
+		return strings.Join(lines, "\n")
+	}
+	metrics.inc("wolfy_terse_answers_trimmed_total")
+	return strings.Join(lines[:terseAnswerLines], "\n") + fmt.Sprintf("\n_(%d more lines - ask me in a DM, or say \"wolfy set style detailed\", for the full answer)_", len(lines)-terseAnswerLines)
+}
+
+// Global function for formatting the pods a detailed answer adds below the short one, as chosen for display: a bold
+// title and its text each, leaving out the interpretation and any pod that only repeats the answer
+func formatDetailedPods(result *fullResult, answer string) string {
+	var sections []string
+	for _, pod := range result.displayPods() {
+		text := strings.TrimSpace(pod.plaintext())
+		if detailedSkippedPods[pod.ID] || strings.Contains(answer, text) {
+			continue
+		}
+		sections = append(sections, fmt.Sprintf("*%s*\n%s", pod.Title, text))
+		if len(sections) == detailedPodLimit {
+			break
+		}
+	}
+	return strings.Join(sections, "\n")
+}
+
+// Global function for adding the full results pods to a detailed Wolfram|Alpha answer, leaving it as it was when they
+// can't be fetched or add nothing
+func withDetailedPods(ctx context.Context, client *wolfram.Client, query string, units wolfram.Unit, answer string) string {
+	result, err := fetchFullResult(ctx, client, query, url.Values{"format": {"plaintext"}, "units": {unitsName(units)}})
+	if err != nil {
+		log.Printf("WOLFRAM ERROR: Unable to fetch full results for the detailed answer to %q.\nError Details: %v", query, err)
+		return answer
+	}
+	pods := formatDetailedPods(result, answer)
+	if pods == "" {
+		return answer
+	}
+	metrics.inc("wolfy_detailed_answers_total")
+	traceStep(ctx, "detailed answer: added full results pods")
+	return answer + "\n" + pods
+}
+
+// Global function for setting or clearing a channel's default answer style at runtime, for "!channel style"
+func setChannelAnswerStyle(event *slack.MessageEvent, channel string, args []string, usage string) string {
+	if len(args) != 1 {
+		return usage
+	}
+	style := strings.ToLower(args[0])
+	if style == "clear" {
+		store.delete(channelStylesBucket, channel)
+		log.Printf("ADMIN: %s cleared the answer style for %s.", event.User, channel)
+		return fmt.Sprintf("Cleared the answer style for <#%s>; it's back to the default, `%s`.", channel, config.ChannelVerbosity)
+	}
+	if !isAnswerStyle(style) {
+		return usage
+	}
+	if err := store.put(channelStylesBucket, channel, style); err != nil {
+		log.Printf("ADMIN ERROR: Unable to persist the answer style for %s.\nError Details: %v", channel, err)
+		return "Sorry, I couldn't save that setting. :-("
+	}
+	log.Printf("ADMIN: %s set the answer style for %s to %s.", event.User, channel, style)
+	return fmt.Sprintf("Answers in <#%s> are now %s, unless the asker chose a style of their own.", channel, style)
+}
@@ -0,0 +1,127 @@
+//////////////////////////////////////////////////
+// Answer Verbosity Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing the formatter's terse, normal, and detailed levels
+import (
+	"context"       // Permits formatter contexts
+	"encoding/json" // Permits building of full results
+	"reflect"       // Permits comparison of attachment fields
+	"strings"       // Permits building of long answers
+	"testing"       // Permits Go testing
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global function for choosing one user's answer style for one test, clearing it afterwards
+func withAnswerStyle(t *testing.T, user string, style string) {
+	if _, err := updatePreferences(user, func(p *userPreferences) { p.Detail = style }); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { updatePreferences(user, func(p *userPreferences) { p.Detail = "" }) })
+}
+
+// Test for switching the formatter between levels: terse trims lines, notes, and detail fields, normal leaves the
+// answer alone, and detailed spells its fields out when attachments are off
+func TestApplyVerbosityLevels(t *testing.T) {
+	reply := "Distance to Mars: 1.6 au _(as of today)_\nabout 240 million km\nlight takes 13 minutes\nthird line of detail\nfourth line of detail"
+	details := []slack.AttachmentField{{Title: "Interpreted as", Value: "distance from Earth to Mars"}}
+	cases := []struct {
+		style       string
+		attachments bool
+		want        string
+		details     []slack.AttachmentField
+	}{
+		{"terse", true, "Distance to Mars: 1.6 au\nabout 240 million km\nlight takes 13 minutes\n_(2 more lines - ask me in a DM, or say \"wolfy set style detailed\", for the full answer)_", nil},
+		{"normal", true, reply, details},
+		{"normal", false, reply, details},
+		{"detailed", true, reply, details},
+		{"detailed", false, reply + "\n_Interpreted as: distance from Earth to Mars_", details},
+	}
+	for _, c := range cases {
+		withConfig(t, func(cfg *Config) { cfg.AnswerAttachments = c.attachments })
+		withAnswerStyle(t, "UVERBOSE", c.style)
+		got, gotDetails := applyVerbosity(context.Background(), "UVERBOSE", "CVERBOSE", reply, details)
+		if got != c.want || !reflect.DeepEqual(gotDetails, c.details) {
+			t.Errorf("applyVerbosity at %s (attachments %v) = %q, %v; want %q, %v", c.style, c.attachments, got, gotDetails, c.want, c.details)
+		}
+	}
+}
+
+// Test for picking the level from the asker's choice, then the channel's own default, then where the answer is posted
+func TestAnswerVerbositySources(t *testing.T) {
+	withConfig(t, func(c *Config) {
+		c.ChannelVerbosity = "terse"
+		c.DMVerbosity = "normal"
+	})
+	if err := store.put(channelStylesBucket, "CSTYLED", "detailed"); err != nil {
+		t.Fatal(err)
+	}
+	defer store.delete(channelStylesBucket, "CSTYLED")
+
+	cases := []struct {
+		user        string
+		destination string
+		want        string
+	}{
+		{"UPLAIN", "CPLAIN", "terse"},
+		{"UPLAIN", "DPLAIN", "normal"},
+		{"UPLAIN", "UPLAIN", "normal"},
+		{"UPLAIN", "CSTYLED", "detailed"},
+		{"UCHOSEN", "CSTYLED", "terse"},
+		{"UCHOSEN", "DCHOSEN", "terse"},
+	}
+	withAnswerStyle(t, "UCHOSEN", "terse")
+	for _, c := range cases {
+		if got, _ := answerVerbosity(c.user, c.destination); got != c.want {
+			t.Errorf("answerVerbosity(%q, %q) = %q; want %q", c.user, c.destination, got, c.want)
+		}
+	}
+}
+
+// Test for terse answers never going empty, since their text is also the Block Kit and attachment fallback
+func TestTerseAnswerFallback(t *testing.T) {
+	cases := []struct {
+		reply string
+		want  string
+	}{
+		{"42", "42"},
+		{"42 _(rounded)_", "42"},
+		{"_(as of 10:00 UTC)_", "_(as of 10:00 UTC)_"},
+		{"one\n\n\ntwo\nthree", "one\ntwo\nthree"},
+		{strings.Repeat("line\n", 3) + "_(note)_", "line\nline\nline"},
+	}
+	for _, c := range cases {
+		if got := terseAnswer(c.reply); got != c.want {
+			t.Errorf("terseAnswer(%q) = %q; want %q", c.reply, got, c.want)
+		}
+	}
+}
+
+// Test for the pods a detailed answer adds: titled, without the interpretation or pods repeating the answer, and
+// capped at the pod limit
+func TestFormatDetailedPods(t *testing.T) {
+	withConfig(t, func(c *Config) { c.DisplayPods = nil })
+	var result fullResult
+	err := json.Unmarshal([]byte(`{"success": true, "pods": [
+		{"title": "Input interpretation", "id": "Input", "subpods": [{"plaintext": "Mars distance"}]},
+		{"title": "Result", "id": "Result", "primary": true, "subpods": [{"plaintext": "1.6 au"}]},
+		{"title": "Unit conversions", "id": "UnitConversion", "subpods": [{"plaintext": "240 million km"}]},
+		{"title": "Light travel time", "id": "LightTime", "subpods": [{"plaintext": "13 minutes"}]},
+		{"title": "Comparison", "id": "Comparison", "subpods": [{"plaintext": "about 600 times the Moon"}]},
+		{"title": "Orbit", "id": "Orbit", "subpods": [{"plaintext": "687 days"}]},
+		{"title": "Extra", "id": "Extra", "subpods": [{"plaintext": "beyond the limit"}]},
+		{"title": "Empty", "id": "Empty", "subpods": [{"plaintext": ""}]}
+	]}`), &result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "*Unit conversions*\n240 million km\n*Light travel time*\n13 minutes\n*Comparison*\nabout 600 times the Moon\n*Orbit*\n687 days"
+	if got := formatDetailedPods(&result, "Distance to Mars: 1.6 au"); got != want {
+		t.Errorf("formatDetailedPods = %q; want %q", got, want)
+	}
+}
@@ -0,0 +1,73 @@
+//////////////////////////////////////////////////
+// RTM Watchdog Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for reconnecting an RTM connection that has silently stopped delivering events
+import (
+	"log"         // Permits console logging
+	"sync/atomic" // Permits lock-free recording of the last event time
+	"time"        // Permits idle time measurement
+)
+
+// Global constant holding the RTM library's ping interval; every pong arrives as a latency report event, so even a
+// workspace with nobody talking sees an event this often
+const rtmPingInterval = 30 * time.Second
+
+// Global time (Unix nanoseconds) of the last RTM event of any kind, latency reports included
+var lastRTMEvent int64
+
+// Global function for recording that the RTM connection delivered an event
+func noteRTMActivity() {
+	atomic.StoreInt64(&lastRTMEvent, time.Now().UnixNano())
+}
+
+// Global function for measuring how long the RTM connection has gone without an event
+func rtmIdleTime() time.Duration {
+	return time.Since(time.Unix(0, atomic.LoadInt64(&lastRTMEvent)))
+}
+
+// Global function for starting the watchdog that reconnects an RTM connection left idle past the configured threshold
+func startRTMWatchdog() {
+	if config.RTMIdleTimeout <= 0 {
+		return
+	}
+	noteRTMActivity()
+	go func() {
+		for range time.Tick(rtmPingInterval) {
+			checkRTMIdle()
+		}
+	}()
+}
+
+// Global function for reconnecting when a connection that claims to be up has been silent past the threshold. Quiet
+// workspaces still see a pong every ping interval, so silence past the threshold means the socket is half-open; while
+// disconnected or connecting, the RTM library's own reconnect loop is left to do its job.
+func checkRTMIdle() {
+	state, since := currentConnectionState()
+	idle := rtmIdleTime()
+	if state != "connected" || time.Since(since) < config.RTMIdleTimeout || idle < config.RTMIdleTimeout {
+		return
+	}
+
+	log.Printf("RTM WATCHDOG: No events for %s (threshold %s) on a connection that looks up; reconnecting.", idle.Round(time.Second), config.RTMIdleTimeout)
+	if usesSocketMode() {
+		metrics.inc("wolfy_rtm_watchdog_reconnects_total", "result", "ok")
+		noteRTMActivity()
+		restartSocketMode()
+		return
+	}
+	rtm, err := connectRotatedSlack(slackAPI().Client)
+	if err != nil {
+		metrics.inc("wolfy_rtm_watchdog_reconnects_total", "result", "failed")
+		log.Printf("RTM WATCHDOG ERROR: Unable to bring up a new RTM connection; trying again in %s.\nError Details: %v", rtmPingInterval, err)
+		return
+	}
+	metrics.inc("wolfy_rtm_watchdog_reconnects_total", "result", "ok")
+	log.Printf("RTM WATCHDOG: Reconnected after %s without events.", idle.Round(time.Second))
+	noteRTMActivity()
+	rtmSwitches <- rtm
+}
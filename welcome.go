@@ -0,0 +1,73 @@
+//////////////////////////////////////////////////
+// First Contact Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for greeting users the first time they DM the bot (or, optionally, ask in a channel)
+import (
+	"context" // Permits marking a first-contact message through the pipeline
+	"log"     // Permits console logging
+	"sync"    // Permits serialized claiming of first contact
+	"time"    // Permits first-contact timestamps
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constant naming the store bucket recording when each user first DMed the bot
+const firstContactBucket = "first_contact"
+
+// Global context key type marking a user's first message to the bot
+type firstContactKey struct{}
+
+// Global mutex serializing first-contact checks so two quick messages only get one welcome
+var firstContactMu sync.Mutex
+
+// Global function for claiming a user's first message (a DM, or a channel question with the onboarding note on), persisting it so restarts never re-trigger the welcome
+func claimFirstContact(event *slack.MessageEvent) bool {
+	if (isDirectMessage(event.Channel) && !config.WelcomeMessages) || (!isDirectMessage(event.Channel) && !config.OnboardingNote) {
+		return false
+	}
+	firstContactMu.Lock()
+	defer firstContactMu.Unlock()
+
+	var firstSeen time.Time
+	if store.get(firstContactBucket, event.User, &firstSeen) {
+		return false
+	}
+	if err := store.put(firstContactBucket, event.User, time.Now()); err != nil {
+		log.Printf("WELCOME ERROR: Unable to record first contact for %s.\nError Details: %v", event.User, err)
+	}
+	// Users who asked questions before first contact was tracked already know the bot
+	return len(userHistory(event.User)) == 0
+}
+
+// Global function for posting the one-time intro above a new user's first answer, marking the context when it does
+func welcomeFirstContact(ctx context.Context, event *slack.MessageEvent) context.Context {
+	if !claimFirstContact(event) {
+		return ctx
+	}
+	metrics.inc("wolfy_welcomes_total")
+	if isDirectMessage(event.Channel) {
+		postText(event.Channel, personalizedMessage(ctx, "welcome_intro", event.User))
+	}
+	return context.WithValue(ctx, firstContactKey{}, true)
+}
+
+// Global function for prefixing a new user's first channel reply with the brief onboarding note (DMs get the full intro instead)
+func withOnboardingNote(ctx context.Context, event *slack.MessageEvent, reply string) string {
+	if first, _ := ctx.Value(firstContactKey{}).(bool); !first || isDirectMessage(event.Channel) {
+		return reply
+	}
+	return scopedMessage(ctx, "onboarding_note") + "\n" + reply
+}
+
+// Global function for choosing the reply to a message we couldn't understand, swapping the warning for examples on first contact
+func unclearReply(ctx context.Context) string {
+	if first, _ := ctx.Value(firstContactKey{}).(bool); first {
+		return scopedMessage(ctx, "welcome_examples")
+	}
+	return failureReply(ctx, errNLPLowConfidence)
+}
@@ -0,0 +1,149 @@
+//////////////////////////////////////////////////
+// Wikipedia Fallback Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for answering encyclopedic questions Wolfram can't with a Wikipedia summary
+import (
+	"context"       // Permits honoring of the handler deadline
+	"encoding/json" // Permits decoding of the MediaWiki API response
+	"fmt"           // Permits formatting of HTTP errors
+	"log"           // Permits console logging
+	"net/http"      // Permits calling the MediaWiki API
+	"net/url"       // Permits building of query strings and article links
+	"strings"       // Permits article link building
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constants holding the MediaWiki API endpoint, the article base URL, and the requested thumbnail width
+const (
+	wikipediaAPIURL        = "https://en.wikipedia.org/w/api.php"
+	wikipediaArticleURL    = "https://en.wikipedia.org/wiki/"
+	wikipediaThumbnailSize = 400
+)
+
+// Global struct holding the best-matching article's title, lead extract, and lead image (empty when it has none)
+type wikipediaSummary struct {
+	title    string
+	extract  string
+	imageURL string
+}
+
+// Global function for finding the best-matching article for a query and fetching its intro and lead image in one call
+func fetchWikipediaSummary(ctx context.Context, query string) (wikipediaSummary, error) {
+	params := url.Values{
+		"action":      {"query"},
+		"format":      {"json"},
+		"generator":   {"search"},
+		"gsrsearch":   {query},
+		"gsrlimit":    {"1"},
+		"prop":        {"extracts|pageimages"},
+		"exintro":     {"1"},
+		"explaintext": {"1"},
+		"exsentences": {"2"},
+		"piprop":      {"thumbnail"},
+		"pithumbsize": {fmt.Sprint(wikipediaThumbnailSize)},
+	}
+	req, err := http.NewRequest("GET", wikipediaAPIURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return wikipediaSummary{}, err
+	}
+	res, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return wikipediaSummary{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return wikipediaSummary{}, fmt.Errorf("wikipedia returned %s", res.Status)
+	}
+
+	var envelope struct {
+		Query struct {
+			Pages map[string]struct {
+				Title     string `json:"title"`
+				Extract   string `json:"extract"`
+				Thumbnail struct {
+					Source string `json:"source"`
+				} `json:"thumbnail"`
+			} `json:"pages"`
+		} `json:"query"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&envelope); err != nil {
+		return wikipediaSummary{}, err
+	}
+	for _, page := range envelope.Query.Pages {
+		return wikipediaSummary{title: page.Title, extract: strings.TrimSpace(page.Extract), imageURL: page.Thumbnail.Source}, nil
+	}
+	return wikipediaSummary{}, nil
+}
+
+// Global function for looking a query up on Wikipedia, reporting false when no article has a usable intro
+func wikipediaFallback(ctx context.Context, query string) (wikipediaSummary, bool) {
+	if !externalAPIsAllowed() {
+		return wikipediaSummary{}, false
+	}
+	summary, err := fetchWikipediaSummary(ctx, query)
+	if err != nil {
+		metrics.inc("wolfy_wikipedia_fallbacks_total", "result", "error")
+		log.Printf("WIKIPEDIA ERROR: Unable to look up %q.\nError Details: %v", query, err)
+		return wikipediaSummary{}, false
+	}
+	if summary.extract == "" {
+		metrics.inc("wolfy_wikipedia_fallbacks_total", "result", "miss")
+		traceStep(ctx, "wikipedia fallback found nothing for %q", query)
+		return wikipediaSummary{}, false
+	}
+	metrics.inc("wolfy_wikipedia_fallbacks_total", "result", "answered")
+	traceStep(ctx, "final source: wikipedia article %q (image: %t)", summary.title, summary.imageURL != "")
+	return summary, true
+}
+
+// Method for turning a summary into a reply in the asker's language, attaching the lead image when enabled and present
+func (summary wikipediaSummary) response(ctx context.Context, query string, language string) handlerResponse {
+	link := wikipediaArticleURL + url.PathEscape(strings.Replace(summary.title, " ", "_", -1))
+	response := handlerResponse{
+		Text:  translateAnswerFromEnglish(ctx, summary.extract, language),
+		Query: query,
+		Details: []slack.AttachmentField{
+			{Title: "Interpreted as", Value: summary.title, Short: true},
+			{Title: "Source", Value: "<" + link + "|Wikipedia>", Short: true},
+		},
+	}
+	if config.WikipediaImages {
+		response.Image = summary.imageURL
+	}
+	return response
+}
+
+// Global function for naming the source a response is tagged with, dropping the link around a linked one ("<url|Wikipedia>")
+func responseSource(response handlerResponse) string {
+	for _, field := range response.Details {
+		if field.Title != "Source" {
+			continue
+		}
+		source := strings.TrimSuffix(strings.TrimPrefix(field.Value, "<"), ">")
+		if i := strings.LastIndex(source, "|"); i >= 0 && strings.HasPrefix(field.Value, "<") {
+			source = source[i+1:]
+		}
+		return source
+	}
+	return ""
+}
+
+// Global function for ending a fallback answer with the sources consulted on the way to it ("Checked: Wolfram|Alpha,
+// Wikipedia"), when the footer is enabled
+func withSourcesChecked(response handlerResponse, failed ...string) handlerResponse {
+	if !config.SourcesFooter {
+		return response
+	}
+	checked := failed
+	if source := responseSource(response); source != "" {
+		checked = append(checked, source)
+	}
+	response.Text += "\n_Checked: " + strings.Join(checked, ", ") + "_"
+	return response
+}
@@ -0,0 +1,183 @@
+//////////////////////////////////////////////////
+// Wit.ai Entity Check Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for checking the Wit.ai app defines every entity the routing table routes, and vice versa
+import (
+	"context"       // Permits bounding the management API call
+	"encoding/json" // Permits decoding of the entity listing
+	"fmt"           // Permits formatting of check reports
+	"log"           // Permits console logging
+	"net/http"      // Permits calling the Wit.ai management API
+	"sort"          // Permits stable ordering of reported names
+	"strings"       // Permits joining of reported names
+	"sync"          // Permits concurrency-safe access to the latest result
+	"time"          // Permits the management API deadline
+)
+
+// Global constants holding the Wit.ai entity listing endpoint and how long the check waits for it
+const (
+	witEntitiesURL     = "https://api.wit.ai/entities"
+	witEntityCheckWait = 15 * time.Second
+)
+
+// Global struct holding the outcome of the latest entity check: routed keys the app lacks and trained entities with no route
+type witEntityCheck struct {
+	Missing  []string
+	Unrouted []string
+	Skipped  string
+	At       time.Time
+}
+
+// Global latest entity check result, read by the readiness endpoint
+var (
+	lastWitEntityCheck   witEntityCheck
+	lastWitEntityCheckMu sync.Mutex
+)
+
+// Global function for listing the Wit.ai app's entity names, accepting both the legacy (names) and current (objects) formats
+func listWitEntities(ctx context.Context) ([]string, int, error) {
+	req, err := http.NewRequest("GET", witEntitiesURL, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Authorization", "Bearer "+currentCredentials.Load().(apiCredentials).witToken)
+	res, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, res.StatusCode, fmt.Errorf("entity listing failed with status %s", res.Status)
+	}
+
+	var listing []json.RawMessage
+	if err := json.NewDecoder(res.Body).Decode(&listing); err != nil {
+		return nil, res.StatusCode, err
+	}
+	names := make([]string, 0, len(listing))
+	for _, raw := range listing {
+		var name string
+		if json.Unmarshal(raw, &name) != nil {
+			var entity struct {
+				Name string `json:"name"`
+			}
+			if json.Unmarshal(raw, &entity) != nil || entity.Name == "" {
+				continue
+			}
+			name = entity.Name
+		}
+		names = append(names, name)
+	}
+	return names, res.StatusCode, nil
+}
+
+// Global function for comparing a routing table against the app's entities. Built-ins ("wit$datetime") are matched by their
+// bare name, as message responses key them, and never reported as unrouted; locally dispatched handlers need no entity.
+func compareWitEntities(table routingTable, names []string) (missing []string, unrouted []string) {
+	defined := map[string]bool{}
+	for _, name := range names {
+		bare := strings.TrimPrefix(strings.TrimPrefix(name, "wit$"), "wit/")
+		defined[bare] = true
+		if bare == name && table[name] == "" {
+			unrouted = append(unrouted, name)
+		}
+	}
+	for entityKey, handlerName := range table {
+		if handler := entityHandlers[handlerName]; handler != nil && handler.local && entityKey == handlerName {
+			continue
+		}
+		if !defined[entityKey] {
+			missing = append(missing, entityKey)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(unrouted)
+	return missing, unrouted
+}
+
+// Global function for checking the Wit.ai app against the current routing table, logging and alerting admins about any
+// mismatch and returning a report. Permission and network errors only downgrade the check to a warning.
+func checkWitEntities(trigger string) string {
+	if strings.EqualFold(config.WitEntityCheck, "off") || !externalAPIsAllowed() {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), witEntityCheckWait)
+	defer cancel()
+
+	result := witEntityCheck{At: time.Now()}
+	names, status, err := listWitEntities(ctx)
+	if err != nil {
+		if status == http.StatusUnauthorized || status == http.StatusForbidden {
+			result.Skipped = "the Wit.ai token can't read the app's entities"
+		} else {
+			result.Skipped = "the Wit.ai management API didn't answer"
+		}
+		log.Printf("WIT.AI: Skipping the entity check (%s): %s.\nDetails: %v", trigger, result.Skipped, err)
+		metrics.inc("wolfy_wit_entity_checks_total", "result", "skipped")
+		storeWitEntityCheck(result)
+		return "Wit.ai entity check skipped: " + result.Skipped + "."
+	}
+
+	result.Missing, result.Unrouted = compareWitEntities(currentRouting.Load().(routingTable), names)
+	storeWitEntityCheck(result)
+	if len(result.Missing) == 0 && len(result.Unrouted) == 0 {
+		metrics.inc("wolfy_wit_entity_checks_total", "result", "ok")
+		return fmt.Sprintf("Wit.ai entity check passed (%d entities).", len(names))
+	}
+
+	metrics.inc("wolfy_wit_entity_checks_total", "result", "mismatch")
+	var lines []string
+	if len(result.Missing) > 0 {
+		lines = append(lines, "routed but missing from the Wit.ai app (questions for these will never reach their handler): `"+strings.Join(result.Missing, "`, `")+"`")
+		log.Printf("WIT.AI ERROR: The Wit.ai app has no entity for routed keys %s (%s).", strings.Join(result.Missing, ", "), trigger)
+	}
+	if len(result.Unrouted) > 0 {
+		lines = append(lines, "trained in the Wit.ai app but not routed to any handler: `"+strings.Join(result.Unrouted, "`, `")+"`")
+		log.Printf("WIT.AI: Trained entities %s have no route (%s).", strings.Join(result.Unrouted, ", "), trigger)
+	}
+	report := fmt.Sprintf(":rotating_light: Wit.ai entity check (%s) found problems:\n• %s", trigger, strings.Join(lines, "\n• "))
+	if config.AdminChannel != "" {
+		postText(config.AdminChannel, report)
+	}
+	return report
+}
+
+// Global function for recording the latest entity check result
+func storeWitEntityCheck(result witEntityCheck) {
+	lastWitEntityCheckMu.Lock()
+	lastWitEntityCheck = result
+	lastWitEntityCheckMu.Unlock()
+}
+
+// Global function for explaining why the bot isn't ready, empty when it is: the RTM connection must be up and, under the
+// strict entity check, no routed key may be missing from the Wit.ai app
+func readinessProblem() string {
+	if state, _ := currentConnectionState(); state != "connected" {
+		return "RTM connection is " + state
+	}
+	if strings.EqualFold(config.WitEntityCheck, "strict") {
+		lastWitEntityCheckMu.Lock()
+		missing := lastWitEntityCheck.Missing
+		lastWitEntityCheckMu.Unlock()
+		if len(missing) > 0 {
+			return "Wit.ai app is missing routed entities: " + strings.Join(missing, ", ")
+		}
+	}
+	return ""
+}
+
+// Global function for serving the readiness endpoint: 200 when ready, 503 with the reason otherwise
+func serveReadiness(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if problem := readinessProblem(); problem != "" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, problem)
+		return
+	}
+	fmt.Fprintln(w, "ready")
+}
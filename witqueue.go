@@ -0,0 +1,97 @@
+//////////////////////////////////////////////////
+// Wit.ai Queue Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for pacing Wit.ai requests through a bounded queue while Wit.ai is throttling us
+import (
+	"context" // Permits giving up on a queued request with its question
+	"fmt"     // Permits formatting of the queue summary
+	"sync"    // Permits concurrency-safe queue state
+	"time"    // Permits pacing and the throttle cooldown
+)
+
+// Global constant holding how long after Wit.ai's last 429 classification stays paced
+const witThrottleCooldown = time.Minute
+
+// Global state of the Wit.ai queue: until when requests are paced, when the next one may go, and how many are waiting
+var witQueue struct {
+	mu             sync.Mutex
+	throttledUntil time.Time
+	nextSlot       time.Time
+	depth          int64
+}
+
+// Global function for noting that Wit.ai throttled a request, pacing classification until the cooldown passes
+func noteWitThrottled(now time.Time) {
+	witQueue.mu.Lock()
+	witQueue.throttledUntil = now.Add(witThrottleCooldown)
+	witQueue.mu.Unlock()
+}
+
+// Global function for waiting for a Wit.ai request's turn while throttled, at WOLFY_WIT_QUEUE_RATE per minute. Requests
+// beyond the queue's size, or whose turn is further off than WOLFY_WIT_QUEUE_MAX_WAIT, get errRateLimited straight away
+// so they take the no-NLP fallback instead of waiting.
+func waitForWitSlot(ctx context.Context) error {
+	if config.WitQueueSize == 0 {
+		return nil
+	}
+	witQueue.mu.Lock()
+	now := time.Now()
+	if !now.Before(witQueue.throttledUntil) {
+		witQueue.mu.Unlock()
+		return nil
+	}
+	if witQueue.depth >= int64(config.WitQueueSize) {
+		witQueue.mu.Unlock()
+		metrics.inc("wolfy_wit_queue_total", "result", "full")
+		traceStep(ctx, "wit.ai queue full")
+		return errRateLimited
+	}
+	slot := witQueue.nextSlot
+	if slot.Before(now) {
+		slot = now
+	}
+	if wait := slot.Sub(now); wait > config.WitQueueMaxWait {
+		witQueue.mu.Unlock()
+		metrics.inc("wolfy_wit_queue_total", "result", "too_slow")
+		traceStep(ctx, "wit.ai queue turn %s away; not waiting", wait.Round(time.Millisecond))
+		return errRateLimited
+	}
+	witQueue.nextSlot = slot.Add(time.Minute / time.Duration(config.WitQueueRate))
+	witQueue.depth++
+	metrics.set(witQueue.depth, "wolfy_wit_queue_depth")
+	witQueue.mu.Unlock()
+
+	defer func() {
+		witQueue.mu.Lock()
+		witQueue.depth--
+		metrics.set(witQueue.depth, "wolfy_wit_queue_depth")
+		witQueue.mu.Unlock()
+	}()
+	metrics.inc("wolfy_wit_queue_total", "result", "queued")
+	traceStep(ctx, "wit.ai throttled; queued for %s", slot.Sub(now).Round(time.Millisecond))
+	select {
+	case <-time.After(time.Until(slot)):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Global function for summarizing the Wit.ai queue for "!stats"
+func describeWitQueue(now time.Time) string {
+	witQueue.mu.Lock()
+	defer witQueue.mu.Unlock()
+
+	if config.WitQueueSize == 0 {
+		return "Wit.ai queue: disabled"
+	}
+	if !now.Before(witQueue.throttledUntil) {
+		return "Wit.ai queue: idle (not throttled)"
+	}
+	return fmt.Sprintf("Wit.ai queue: %d of %d waiting, paced to %d/min until %s", witQueue.depth, config.WitQueueSize, config.WitQueueRate, witQueue.throttledUntil.Format("15:04:05"))
+}
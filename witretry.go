@@ -0,0 +1,100 @@
+//////////////////////////////////////////////////
+// Wit.ai Rate Limits Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for retrying and falling back when Wit.ai throttles us
+import (
+	"context"  // Permits per-question API usage accounting
+	"log"      // Permits console logging
+	"net/http" // Permits matching of go-wit's status text errors
+	"strings"  // Permits string normalization
+	"time"     // Permits retry backoff
+
+	wit "github.com/christianrondeau/go-wit" // External Wit.ai API
+)
+
+// Global function for recognizing a Wit.ai 429 (go-wit only surfaces the status text)
+func isWitRateLimit(err error) bool {
+	return err != nil && err.Error() == http.StatusText(http.StatusTooManyRequests)
+}
+
+// Global function for querying Wit.ai, retrying rate limited requests with exponential backoff (or, when the queue is
+// on, at its pace)
+func witMessage(ctx context.Context, text string) (*wit.MessageResponse, error) {
+	if !externalAPIsAllowed() {
+		return nil, errExternalAPIsDisabled
+	}
+	if err := awaitRecovery(ctx, "wit"); err != nil {
+		return nil, err
+	}
+
+	backoff := config.WitRetryBackoff
+	for attempt := 0; ; attempt++ {
+		if err := waitForWitSlot(ctx); err != nil {
+			return nil, err
+		}
+		recordAPICall(ctx, "wit")
+		res, err := witMessageWithin(ctx, text)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if !isWitRateLimit(err) {
+			noteBackendResult("wit", err != nil)
+			return res, err
+		}
+		metrics.inc("wolfy_wit_rate_limited_total")
+		noteWitThrottled(time.Now())
+
+		if attempt >= config.WitRetries {
+			log.Printf("WIT.AI ERROR: Still rate limited after %d retries.", config.WitRetries)
+			noteBackendResult("wit", true)
+			return nil, errRateLimited
+		}
+		metrics.inc("wolfy_wit_retries_total")
+		if config.WitQueueSize > 0 {
+			continue
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// Global function for making one Wit.ai request that gives up when the context ends (go-wit takes no context,
+// so an abandoned request finishes in the background within the HTTP client timeout)
+func witMessageWithin(ctx context.Context, text string) (*wit.MessageResponse, error) {
+	type witReply struct {
+		res *wit.MessageResponse
+		err error
+	}
+	replies := make(chan witReply, 1)
+	go func() {
+		res, err := witClientFor(ctx).Message(text)
+		replies <- witReply{res, err}
+	}()
+	select {
+	case reply := <-replies:
+		return reply.res, reply.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Global function for classifying without Wit.ai once it stays rate limited, returning ok false when we should just say we're busy
+func witRateLimitFallback(text string) (string, wit.MessageEntity, bool) {
+	if strings.ToLower(config.WitRateLimitFallback) != "wolfram" {
+		metrics.inc("wolfy_wit_fallbacks_total", "mode", "busy")
+		return "", wit.MessageEntity{}, false
+	}
+
+	// Sending the raw message straight to Wolfram, which copes with most questions as typed
+	metrics.inc("wolfy_wit_fallbacks_total", "mode", "wolfram")
+	return "wolfram_search_query", wit.MessageEntity{Value: text}, true
+}
@@ -0,0 +1,438 @@
+//////////////////////////////////////////////////
+// Wolfram Answers Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for fetching answers from the Wolfram database
+import (
+	"context"       // Permits cancellation of slow backend calls
+	"encoding/json" // Permits decoding of full results
+	"fmt"           // Permits formatting of error messages
+	"io/ioutil"     // Permits reading of response bodies
+	"log"           // Permits console logging
+	"net/http"      // Permits direct Wolfram API requests
+	"net/url"       // Permits query string encoding
+	"strconv"       // Permits formatting of numeric parameters
+	"strings"       // Permits joining of pod text
+	"time"          // Permits reporting of cached answer ages
+
+	wolfram "github.com/Krognol/go-wolfram"  // External Wolfram API
+	wit "github.com/christianrondeau/go-wit" // External Wit.ai API
+	slack "github.com/nlopes/slack"          // External Slack API
+)
+
+// Global constants holding the Wolfram endpoints and the short answer server-side timeout parameter
+const (
+	wolframShortAnswerURL     = "https://api.wolframalpha.com/v1/result"
+	wolframFullResultsURL     = "https://api.wolframalpha.com/v2/query"
+	wolframShortAnswerTimeout = 1000
+)
+
+// Global constants holding the bodies the short answer endpoint sends in place of an answer
+const (
+	wolframNotUnderstoodReply = "Wolfram|Alpha did not understand your input"
+	wolframNoShortAnswerReply = "No short answer available"
+)
+
+// Global struct holding the parts of a full results response we use (go-wolfram's types don't match the JSON output)
+type fullResult struct {
+	Success     bool            `json:"success"`
+	TimedOut    string          `json:"timedout"`
+	Pods        []fullResultPod `json:"pods"`
+	Assumptions json.RawMessage `json:"assumptions"`
+	Error       json.RawMessage `json:"error"`
+
+	// Whether some pods are still missing because Wolfram ran out of time, even after any retry
+	Partial bool `json:"-"`
+}
+
+// Global constant holding the note added to answers built from a partial result
+const partialResultNote = "_(Wolfram|Alpha ran out of time on part of this, so the result may be incomplete.)_"
+
+// Global set of the full results timeout parameters a retry raises
+var fullResultTimeoutParams = []string{"scantimeout", "podtimeout", "formattimeout", "totaltimeout"}
+
+// Global struct holding a single full results pod
+type fullResultPod struct {
+	Title   string `json:"title"`
+	ID      string `json:"id"`
+	Primary bool   `json:"primary"`
+	SubPods []struct {
+		Title     string `json:"title"`
+		Plaintext string `json:"plaintext"`
+		Img       struct {
+			Src string `json:"src"`
+			Alt string `json:"alt"`
+		} `json:"img"`
+	} `json:"subpods"`
+	States []struct {
+		Name  string `json:"name"`
+		Input string `json:"input"`
+	} `json:"states"`
+
+	// Set when the pod failed to compute, and holding the URL to fetch it from when Wolfram computes it asynchronously
+	Error json.RawMessage `json:"error"`
+	Async string          `json:"async"`
+}
+
+// Method for choosing the pods worth showing from a full result: those named in the display allowlist (by title or ID)
+// in its order, else the primary pod when none of them came back, or every pod with text when there is no allowlist
+func (result *fullResult) displayPods() []fullResultPod {
+	var shown []fullResultPod
+	if len(config.DisplayPods) == 0 {
+		for _, pod := range result.Pods {
+			if strings.TrimSpace(pod.plaintext()) != "" {
+				shown = append(shown, pod)
+			}
+		}
+		return shown
+	}
+
+	for _, name := range config.DisplayPods {
+		for _, pod := range result.Pods {
+			if (strings.EqualFold(pod.Title, name) || strings.EqualFold(pod.ID, name)) && strings.TrimSpace(pod.plaintext()) != "" {
+				shown = append(shown, pod)
+			}
+		}
+	}
+	if len(shown) > 0 {
+		return shown
+	}
+	for _, pod := range result.Pods {
+		if (pod.Primary || pod.ID == "Result") && strings.TrimSpace(pod.plaintext()) != "" {
+			metrics.inc("wolfy_display_pods_fallbacks_total")
+			return []fullResultPod{pod}
+		}
+	}
+	return nil
+}
+
+// Method for joining the plaintext of every subpod in a pod
+func (pod fullResultPod) plaintext() string {
+	texts := make([]string, 0, len(pod.SubPods))
+	for _, subPod := range pod.SubPods {
+		if subPod.Plaintext != "" {
+			texts = append(texts, subPod.Plaintext)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// Global function for requesting a short answer, honoring context cancellation (go-wolfram cannot)
+func fetchShortAnswer(ctx context.Context, client *wolfram.Client, query string, units wolfram.Unit) (string, error) {
+	if !externalAPIsAllowed() {
+		return "", errExternalAPIsDisabled
+	}
+	if err := awaitRecovery(ctx, "wolfram"); err != nil {
+		return "", err
+	}
+	recordAPICall(ctx, "wolfram")
+	params := url.Values{
+		"appid":   {client.AppID},
+		"i":       {query},
+		"output":  {"json"},
+		"timeout": {strconv.Itoa(wolframShortAnswerTimeout)},
+	}
+	switch units {
+	case wolfram.Imperial:
+		params.Set("units", "imperial")
+	case wolfram.Metric:
+		params.Set("units", "metric")
+	}
+
+	req, err := http.NewRequest("GET", wolframShortAnswerURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	res, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		noteBackendResult("wolfram", ctx.Err() == nil)
+		return "", err
+	}
+	defer res.Body.Close()
+	// 501 only means "not understood / no short answer"; 403 covers invalid or exhausted App IDs
+	noteBackendResult("wolfram", res.StatusCode == http.StatusForbidden || (res.StatusCode >= 500 && res.StatusCode != http.StatusNotImplemented))
+
+	// Like go-wolfram, the body is the answer even on non-200 statuses, apart from the "no answer" replies
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+	switch answer := strings.TrimSpace(string(body)); {
+	case answer == wolframNotUnderstoodReply:
+		return "", errAnswerNotUnderstood
+	case answer == wolframNoShortAnswerReply:
+		return "", errAnswerTooLong
+	case res.StatusCode == http.StatusForbidden:
+		return "", wrapFailure(errQuotaExceeded, fmt.Errorf("short answer request failed with status %s: %s", res.Status, truncateGraphemes(answer, 80)))
+	}
+	return string(body), nil
+}
+
+// Global function for requesting full results (all pods) for a query with extra API parameters, retrying once with
+// longer timeouts when Wolfram reports timed out pods, and marking the result partial if they still time out
+func fetchFullResult(ctx context.Context, client *wolfram.Client, query string, params url.Values) (*fullResult, error) {
+	if !externalAPIsAllowed() {
+		return nil, errExternalAPIsDisabled
+	}
+	if err := awaitRecovery(ctx, "wolfram"); err != nil {
+		return nil, err
+	}
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("appid", client.AppID)
+	params.Set("input", query)
+	params.Set("output", "json")
+
+	result, err := requestFullResult(ctx, params)
+	if err != nil || result.TimedOut == "" {
+		return result, err
+	}
+
+	// Only retrying when there's time for it before the caller gives up, and never on top of a caller's own timeouts
+	timeout := config.WolframRetryTimeout
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < timeout {
+		timeout = time.Until(deadline) - time.Second
+	}
+	if timeout < time.Second || params.Get("podtimeout") != "" {
+		metrics.inc("wolfy_wolfram_partial_results_total", "outcome", "partial")
+		result.Partial = true
+		return result, nil
+	}
+	traceStep(ctx, "wolfram timed out on %s; retrying with a %s timeout", result.TimedOut, timeout)
+	retryParams := url.Values{}
+	for key, values := range params {
+		retryParams[key] = values
+	}
+	for _, key := range fullResultTimeoutParams {
+		retryParams.Set(key, strconv.FormatFloat(timeout.Seconds(), 'f', 0, 64))
+	}
+	retried, err := requestFullResult(ctx, retryParams)
+	if err != nil {
+		// Keeping what the first attempt found rather than failing outright
+		log.Printf("WOLFRAM ERROR: Unable to retry timed out full results for %q.\nError Details: %v", query, err)
+		metrics.inc("wolfy_wolfram_partial_results_total", "outcome", "partial")
+		result.Partial = true
+		return result, nil
+	}
+	retried.Partial = retried.TimedOut != ""
+	metrics.inc("wolfy_wolfram_partial_results_total", "outcome", map[bool]string{true: "partial", false: "completed_on_retry"}[retried.Partial])
+	return retried, nil
+}
+
+// Global function for making one full results request
+func requestFullResult(ctx context.Context, params url.Values) (*fullResult, error) {
+	recordAPICall(ctx, "wolfram")
+	req, err := http.NewRequest("GET", wolframFullResultsURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("full results request failed with status %s", res.Status)
+	}
+
+	envelope := struct {
+		QueryResult fullResult `json:"queryresult"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(&envelope); err != nil {
+		return nil, err
+	}
+	return &envelope.QueryResult, nil
+}
+
+// Handler answering questions via the Wolfram short answer API
+func handleWolframQuery(ctx context.Context, event *slack.MessageEvent, entity wit.MessageEntity) (handlerResponse, error) {
+	raw, _ := entityQuery(entity, event.Msg.Text)
+	// Rewriting pasted LaTeX, whose backslashes the short answer endpoint can't read
+	if converted, ok := convertLaTeX(raw); ok {
+		traceStep(ctx, "rewrote LaTeX %q as %q", raw, converted)
+		raw = converted
+	}
+	query, language := translateQueryToEnglish(ctx, raw)
+	// Taking the asker's up-front assumptions ("assume metric units", "treat mercury as an element") out of the question
+	query, assumed := parseAssumptionDirectives(query)
+	units, unitsSource := resolveUnits(event.User)
+	if assumed.hasUnits {
+		units, unitsSource = assumed.units, "the question (\"assume "+unitsName(assumed.units)+"\")"
+	}
+	if len(assumed.described) > 0 {
+		traceStep(ctx, "assuming %s; asking %q", strings.Join(assumed.described, ", "), query)
+	}
+	// Honoring a unit named inline for this question only, without touching the saved preference
+	inlineUnit, hasInlineUnit := inlineUnitOverride(query)
+	if hasInlineUnit {
+		units, unitsSource = inlineUnitSystem(inlineUnit, units), "the question (\"in "+inlineUnit.symbol+"\")"
+	}
+	// Preferring a plain-language explanation when asked to explain simply, else answering normally
+	if simpleModeRequested(ctx) {
+		if simple, ok := simpleAnswer(ctx, wolframClientFor(event), query); ok {
+			return handlerResponse{
+				Text:  translateAnswerFromEnglish(ctx, "In simple terms: "+simple, language),
+				Query: query,
+				Details: []slack.AttachmentField{
+					{Title: "Interpreted as", Value: query, Short: true},
+					{Title: "Mode", Value: "Explained simply", Short: true},
+				},
+			}, nil
+		}
+	}
+	// Converting currency at a past date's rate, or at today's with a note when that rate can't be had
+	rateNote := ""
+	now := time.Now().In(userLocation(event.User))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if conversion, ok := parseHistoricalConversion(query, today); ok {
+		answer, note, answered := answerHistoricalConversion(ctx, conversion, today)
+		if answered {
+			return handlerResponse{
+				Text:  localizeAnswerNumbers(translateAnswerFromEnglish(ctx, answer, language), event.User, config.NumberSignificantDigits),
+				Query: query,
+				Details: []slack.AttachmentField{
+					{Title: "Interpreted as", Value: query, Short: true},
+					{Title: "Source", Value: "Historical exchange rates", Short: true},
+				},
+			}, nil
+		}
+		query, rateNote = conversion.currentQuery(), note
+	}
+	// Plotting data over time ("stock price of X over the last year") rather than listing raw numbers
+	if response, ok := answerTimeSeries(ctx, event, query, units); ok {
+		response.Text = localizeAnswerNumbers(translateAnswerFromEnglish(ctx, response.Text, language), event.User, config.NumberSignificantDigits)
+		return response, nil
+	}
+	// Listing a compound's or element's properties rather than a single line
+	if response, ok := answerChemistry(ctx, event, query, units); ok {
+		response.Text = localizeAnswerNumbers(translateAnswerFromEnglish(ctx, response.Text, language), event.User, config.NumberSignificantDigits)
+		return response, nil
+	}
+	// Laying out where a place is and what borders it, with a map
+	if response, ok := answerGeography(ctx, event, query, units); ok {
+		response.Text = localizeAnswerNumbers(translateAnswerFromEnglish(ctx, response.Text, language), event.User, config.NumberSignificantDigits)
+		return response, nil
+	}
+	traceStep(ctx, "wolfram short answer for %q in %s units (from %s)", query, unitsName(units), unitsSource)
+
+	var (
+		res        string
+		imageURL   string
+		entry      cachedAnswer
+		cached     bool
+		answeredAt time.Time
+	)
+	// Answers under explicit assumptions bypass the cache, whose keys don't carry them, as do questions whose earlier
+	// answers were rated down
+	poorlyRatedAnswer := poorlyRated(event.Msg.Text, time.Now())
+	if poorlyRatedAnswer {
+		metrics.inc("wolfy_answer_cache_total", "result", "poorly_rated")
+		traceStep(ctx, "skipping the answer cache: earlier answers to this were rated down")
+	}
+	if len(assumed.assumptions) == 0 && !poorlyRatedAnswer {
+		entry, cached = cachedAnswerFor(ctx, query, units)
+	}
+	if cached {
+		metrics.inc("wolfy_answer_cache_total", "result", "hit")
+		traceStep(ctx, "answer cache hit (cached %s ago)", time.Since(entry.StoredAt).Round(time.Second))
+		res, answeredAt = entry.Answer, entry.StoredAt
+	} else {
+		if answers != nil {
+			metrics.inc("wolfy_answer_cache_total", "result", "miss")
+			traceStep(ctx, "answer cache miss")
+		}
+		var (
+			err     error
+			content podAnswer
+			shape   = "text"
+		)
+		endFetch := timeStage(ctx, "wolfram")
+		if len(assumed.assumptions) > 0 {
+			content, err = fetchAssumedAnswer(ctx, wolframClientFor(event), query, units, assumed.assumptions)
+			res, imageURL = content.text, content.imageURL
+		} else {
+			res, err = fetchShortAnswer(ctx, wolframClientFor(event), query, units)
+			// Looking at every pod when there's no short answer, so image-only and oversized results get an honest reply
+			// rather than none
+			if err == errAnswerTooLong {
+				if content, ok := fullResultFallback(ctx, wolframClientFor(event), query, units); ok {
+					res, imageURL, shape, err = content.text, content.imageURL, content.shape, nil
+				}
+			}
+		}
+		endFetch()
+		answeredAt = time.Now()
+		if err != nil {
+			traceStep(ctx, "wolfram|alpha gave no answer: %v", err)
+			// Falling back to an encyclopedia summary when Wolfram has no short answer
+			if (err == errAnswerNotUnderstood || err == errAnswerTooLong) && config.WikipediaFallback {
+				if summary, ok := wikipediaFallback(ctx, query); ok {
+					return withSourcesChecked(summary.response(ctx, query, language), "Wolfram|Alpha"), nil
+				}
+			}
+			return handlerResponse{Query: query}, err
+		}
+		// Image links expire and oversize notes aren't answers, so only text is cached
+		if answers != nil && len(assumed.assumptions) == 0 && shape == "text" {
+			cacheAnswer(ctx, query, units, res)
+		}
+	}
+	if hasInlineUnit {
+		if converted := convertAnswerToUnit(res, inlineUnit); converted != res {
+			traceStep(ctx, "converted %q to %s as asked", res, inlineUnit.symbol)
+			res = converted
+		}
+	}
+	traceStep(ctx, "final source: wolfram|alpha (raw reply %q)", res)
+
+	response := handlerResponse{Query: query, Cached: cached, Image: imageURL, AnsweredAt: answeredAt}
+	response.Details = []slack.AttachmentField{
+		{Title: "Interpreted as", Value: query, Short: true},
+		{Title: "Source", Value: "Wolfram|Alpha", Short: true},
+	}
+	if len(assumed.described) > 0 {
+		response.Details = append(response.Details, slack.AttachmentField{Title: "Assuming", Value: strings.Join(assumed.described, ", "), Short: true})
+	}
+	answer, listedCandidates := res, false
+	if flagEnabled(ctx, "answer_candidates") {
+		if candidates, ok := answerCandidates(ctx, wolframClientFor(event), query, units, res); ok {
+			traceStep(ctx, "ambiguous query; listing alternative readings")
+			answer, listedCandidates = candidates, true
+		}
+	}
+	if percentage, ok := formatPercentageAnswer(query, answer); ok && !listedCandidates {
+		traceStep(ctx, "formatted %q as a percentage", answer)
+		answer = percentage
+	}
+	if isChemistryQuery(query) {
+		answer = formatChemicalNotation(answer)
+	}
+	response.Text = localizeAnswerNumbers(translateAnswerFromEnglish(ctx, answer, language), event.User, config.NumberSignificantDigits)
+	if !listedCandidates {
+		response.Text = formatAnswer(ctx, response.Text)
+		if asOf := answerAsOf(ctx, wolframClientFor(event), query, units, res); asOf != "" {
+			response.Text += " _(" + asOf + ")_"
+			response.Details = append(response.Details, slack.AttachmentField{Title: "As of", Value: asOf, Short: true})
+		}
+		// Suggesting follow-ups only in English, the language their templates are written in
+		if language == "" {
+			response.Text = withFollowUpSuggestions(ctx, query, response.Text)
+		}
+		// Adding the rest of the full result for askers who want it all, in English like the pods
+		if verbosity, _ := answerVerbosity(event.User, answerDestination(event)); verbosity == "detailed" && language == "" {
+			response.Text = withDetailedPods(ctx, wolframClientFor(event), query, units, response.Text)
+		}
+	}
+	if rateNote != "" {
+		response.Text += " _(" + translateAnswerFromEnglish(ctx, rateNote, language) + ")_"
+	}
+	if poorlyRatedAnswer {
+		response.Text += "\n" + translateAnswerFromEnglish(ctx, poorlyRatedNote, language)
+	}
+	return response, nil
+}
@@ -0,0 +1,209 @@
+//////////////////////////////////////////////////
+// Worker Pool Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for bounding concurrent message handling and telling queued askers where they stand
+import (
+	"fmt"  // Permits formatting of queue notices
+	"log"  // Permits console logging
+	"sync" // Permits concurrency-safe queue access
+	"time" // Permits queue notice delays and message freshness
+
+	slack "github.com/nlopes/slack" // External Slack API
+)
+
+// Global constant holding the apology posted (once per freshness window) in a channel whose stale questions were skipped
+const staleQueueApology = ":turtle: I fell behind and skipped some older questions. Please ask again if you still need an answer!"
+
+// Global struct holding a message waiting for a worker, when it joined the queue, and the queue notice posted for it
+// (if any)
+type queuedMessage struct {
+	event      *slack.MessageEvent
+	enqueuedAt time.Time
+
+	mu            sync.Mutex
+	started       bool
+	noticeChannel string
+	noticeTS      string
+}
+
+// Global FIFO of messages waiting for a worker, signalled whenever one is added
+var messageQueue = struct {
+	sync.Mutex
+	cond    *sync.Cond
+	pending []*queuedMessage
+}{}
+
+// Global record of stale messages dropped: when each channel was last apologized to, and when admins were last alerted
+// along with how many were dropped since
+var staleDrops = struct {
+	sync.Mutex
+	apologized map[string]time.Time
+	alertedAt  time.Time
+	dropped    int
+}{apologized: map[string]time.Time{}}
+
+// Global function for starting the bounded worker pool (a size of 0 keeps one goroutine per message)
+func startWorkerPool() {
+	if config.Workers <= 0 {
+		return
+	}
+	messageQueue.cond = sync.NewCond(&messageQueue.Mutex)
+	for i := 0; i < config.Workers; i++ {
+		go runMessageWorker()
+	}
+}
+
+// Global function for handing a message to the worker pool, or straight to its own goroutine when the pool is off
+func dispatchMessage(event *slack.MessageEvent) {
+	if config.Workers <= 0 {
+		go handleMSGEvent(event)
+		return
+	}
+
+	queued := &queuedMessage{event: event, enqueuedAt: time.Now()}
+	messageQueue.Lock()
+	messageQueue.pending = append(messageQueue.pending, queued)
+	metrics.set(int64(len(messageQueue.pending)), "wolfy_worker_queue_depth")
+	messageQueue.cond.Signal()
+	messageQueue.Unlock()
+
+	if config.QueueNoticeDelay > 0 {
+		time.AfterFunc(config.QueueNoticeDelay, func() { postQueueNotice(queued) })
+	}
+}
+
+// Global function for taking the oldest waiting message, blocking until there is one
+func nextQueuedMessage() *queuedMessage {
+	messageQueue.Lock()
+	defer messageQueue.Unlock()
+
+	for len(messageQueue.pending) == 0 {
+		messageQueue.cond.Wait()
+	}
+	queued := messageQueue.pending[0]
+	messageQueue.pending = messageQueue.pending[1:]
+	metrics.set(int64(len(messageQueue.pending)), "wolfy_worker_queue_depth")
+	return queued
+}
+
+// Global function for running one worker, handling queued messages in arrival order
+func runMessageWorker() {
+	for {
+		queued := nextQueuedMessage()
+		queued.mu.Lock()
+		queued.started = true
+		channel, timestamp := queued.noticeChannel, queued.noticeTS
+		queued.mu.Unlock()
+		removeQueueNotice(channel, timestamp)
+
+		if now := time.Now(); queuedTooLong(queued, now) {
+			dropStaleMessage(queued, now)
+			finishGroupedMessage(queued.event)
+			continue
+		}
+		handleMSGEvent(queued.event)
+	}
+}
+
+// Global function for checking whether a message waited in the queue longer than the freshness window, so answering
+// it now would come long after anyone cares
+func queuedTooLong(queued *queuedMessage, now time.Time) bool {
+	return config.QueueFreshness > 0 && now.Sub(queued.enqueuedAt) > config.QueueFreshness
+}
+
+// Global function for skipping a stale message, apologizing in its channel and alerting admins at most once per
+// freshness window each, so a backlog of thousands yields one note rather than thousands
+func dropStaleMessage(queued *queuedMessage, now time.Time) {
+	event := queued.event
+	metrics.inc("wolfy_worker_stale_dropped_total")
+	log.Printf("QUEUE: Skipped a message from %s in %s that waited %s.", event.User, event.Channel, now.Sub(queued.enqueuedAt).Round(time.Second))
+
+	staleDrops.Lock()
+	staleDrops.dropped++
+	apologize := now.Sub(staleDrops.apologized[event.Channel]) > config.QueueFreshness
+	if apologize {
+		staleDrops.apologized[event.Channel] = now
+	}
+	alert, dropped := now.Sub(staleDrops.alertedAt) > config.QueueFreshness, staleDrops.dropped
+	if alert {
+		staleDrops.alertedAt, staleDrops.dropped = now, 0
+	}
+	staleDrops.Unlock()
+
+	if apologize {
+		postText(event.Channel, staleQueueApology)
+	}
+	if alert && config.AdminChannel != "" {
+		postText(config.AdminChannel, fmt.Sprintf(":turtle: *Falling behind*: skipped %d queued question(s) older than %s. %d more are waiting.", dropped, config.QueueFreshness, queueDepth()))
+	}
+}
+
+// Global function for counting the messages still waiting for a worker
+func queueDepth() int {
+	messageQueue.Lock()
+	defer messageQueue.Unlock()
+	return len(messageQueue.pending)
+}
+
+// Global function for finding a waiting message's place in line (0 once a worker has it); everything before it is
+// still waiting, so it is never reported ahead of a message that arrived first
+func queuePosition(queued *queuedMessage) int {
+	messageQueue.Lock()
+	defer messageQueue.Unlock()
+
+	for i, pending := range messageQueue.pending {
+		if pending == queued {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// Global function for telling a still-waiting asker their place in line, in a thread under their message
+func postQueueNotice(queued *queuedMessage) {
+	position := queuePosition(queued)
+	if position == 0 {
+		return
+	}
+
+	event := queued.event
+	options := []slack.MsgOption{messageText(fmt.Sprintf(":hourglass_flowing_sand: I'm a little busy - you're #%d in line, and I'll answer as soon as I can.", position))}
+	if !isDirectMessage(event.Channel) {
+		threadTS := event.ThreadTimestamp
+		if threadTS == "" {
+			threadTS = event.Timestamp
+		}
+		options = append(options, slack.MsgOptionTS(threadTS))
+	}
+	metrics.inc("wolfy_queue_notices_total")
+	channel, timestamp, err := postMessage(event.Channel, options...)
+	if err != nil {
+		return
+	}
+
+	// Removing the notice right away when a worker picked the message up while it was being posted
+	queued.mu.Lock()
+	started := queued.started
+	if !started {
+		queued.noticeChannel, queued.noticeTS = channel, timestamp
+	}
+	queued.mu.Unlock()
+	if started {
+		removeQueueNotice(channel, timestamp)
+	}
+}
+
+// Global function for deleting a queue notice once its message is being handled
+func removeQueueNotice(channel string, timestamp string) {
+	if timestamp == "" {
+		return
+	}
+	if _, _, err := slackAPI().DeleteMessage(channel, timestamp); err != nil {
+		log.Printf("QUEUE ERROR: Unable to remove the queue notice in %s.\nError Details: %v", channel, err)
+	}
+}
@@ -0,0 +1,83 @@
+//////////////////////////////////////////////////
+// Worker Pool Test Go File for the WolfyBot Project.
+// Created and Maintained by Aakash Sudhakar.
+//////////////////////////////////////////////////
+
+// Main package for general Golang functionality
+package main
+
+// Global imports for testing the freshness window that skips questions left too long in the queue
+import (
+	"fmt"     // Permits formatting of expected alerts
+	"strings" // Permits matching of alert text
+	"testing" // Permits Go testing
+	"time"    // Permits the fake clock
+)
+
+// Test for a message going stale only once it has waited longer than the freshness window, and never without one
+func TestQueuedTooLong(t *testing.T) {
+	enqueued := time.Date(2026, 6, 10, 9, 0, 0, 0, time.UTC)
+	cases := []struct {
+		freshness time.Duration
+		waited    time.Duration
+		want      bool
+	}{
+		{2 * time.Minute, 0, false},
+		{2 * time.Minute, time.Minute + 59*time.Second, false},
+		{2 * time.Minute, 2 * time.Minute, false},
+		{2 * time.Minute, 2*time.Minute + time.Second, true},
+		{2 * time.Minute, time.Hour, true},
+		{0, 24 * time.Hour, false},
+	}
+	for _, c := range cases {
+		withConfig(t, func(config *Config) { config.QueueFreshness = c.freshness })
+		if got := queuedTooLong(&queuedMessage{enqueuedAt: enqueued}, enqueued.Add(c.waited)); got != c.want {
+			t.Errorf("queuedTooLong after %s with a %s window = %v; want %v", c.waited, c.freshness, got, c.want)
+		}
+	}
+}
+
+// Test for stale drops apologizing in each channel and alerting admins at most once per freshness window on a fake
+// clock, the alert counting every drop since the last one
+func TestDropStaleMessageRateLimited(t *testing.T) {
+	withConfig(t, func(config *Config) {
+		config.QueueFreshness = 2 * time.Minute
+		config.AdminChannel = "CSTALEADMIN"
+	})
+	staleDrops.Lock()
+	previous := staleDrops.apologized
+	staleDrops.apologized, staleDrops.alertedAt, staleDrops.dropped = map[string]time.Time{}, time.Time{}, 0
+	staleDrops.Unlock()
+	t.Cleanup(func() {
+		staleDrops.Lock()
+		staleDrops.apologized, staleDrops.alertedAt, staleDrops.dropped = previous, time.Time{}, 0
+		staleDrops.Unlock()
+	})
+
+	first, second := len(postsContaining("CSTALE1", staleQueueApology)), len(postsContaining("CSTALE2", staleQueueApology))
+	alerts := len(postsContaining("CSTALEADMIN", "Falling behind"))
+	dropped := metricValue("wolfy_worker_stale_dropped_total")
+
+	now := time.Date(2026, 6, 10, 9, 0, 0, 0, time.UTC)
+	for _, drop := range []struct {
+		channel string
+		at      time.Duration
+	}{{"CSTALE1", 0}, {"CSTALE2", 10 * time.Second}, {"CSTALE1", 30 * time.Second}, {"CSTALE1", 3 * time.Minute}} {
+		queued := &queuedMessage{event: testMessage(drop.channel, "USTALE", "8100.000001", "what is pi"), enqueuedAt: now.Add(drop.at - 5*time.Minute)}
+		dropStaleMessage(queued, now.Add(drop.at))
+	}
+
+	waitFor(t, "the apologies and alerts to be posted", func() bool {
+		return len(postsContaining("CSTALE1", staleQueueApology)) == first+2 && len(postsContaining("CSTALE2", staleQueueApology)) == second+1 &&
+			len(postsContaining("CSTALEADMIN", "Falling behind")) == alerts+2
+	})
+	for i, want := range []int{1, 3} {
+		text := postsContaining("CSTALEADMIN", "Falling behind")[alerts+i].values.Get("text")
+		if expected := fmt.Sprintf("skipped %d queued question(s) older than 2m0s", want); !strings.Contains(text, expected) {
+			t.Errorf("alert %d = %q; want it to say %q", i+1, text, expected)
+		}
+	}
+	if got := metricValue("wolfy_worker_stale_dropped_total") - dropped; got != 4 {
+		t.Errorf("counted %d stale drops; want 4", got)
+	}
+}